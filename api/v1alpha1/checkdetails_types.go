@@ -0,0 +1,172 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Typed schemas of the details of the most used checks. These checks build their details from
+// these structs, so clients can rely on the keys instead of guessing them; the other checks keep
+// free-form details, read with DetailsMap. The JSON keys are the ones the checks always stored,
+// so the results of NodeChecks written by previous versions decode into them as well.
+
+// LoadDetails are the details of the uptime (load average) check
+// +kubebuilder:object:generate=false
+type LoadDetails struct {
+	// CheckSource is where the values come from: proc_loadavg, host_fallback, container_fallback or failed
+	CheckSource string `json:"check_source,omitempty"`
+	// Uptime is the output of uptime, set when /proc/loadavg could not be read
+	Uptime            string  `json:"uptime,omitempty"`
+	CPUCores          int     `json:"cpu_cores,omitempty"`
+	Load1Min          float64 `json:"load_1min"`
+	Load5Min          float64 `json:"load_5min"`
+	Load15Min         float64 `json:"load_15min"`
+	WarningThreshold  float64 `json:"warning_threshold,omitempty"`
+	CriticalThreshold float64 `json:"critical_threshold,omitempty"`
+	// IOWaitPercent is the share of CPU time waiting for I/O, measured over one second
+	IOWaitPercent   float64 `json:"iowait_percent,omitempty"`
+	IOWaitJiffies   int64   `json:"iowait_jiffies,omitempty"`
+	TotalCPUJiffies int64   `json:"total_cpu_jiffies,omitempty"`
+}
+
+// MemoryDetails are the details of the memory check. The byte counts come from /proc/meminfo;
+// when it cannot be read they are parsed from free -h, whose human readable columns are set as well.
+// +kubebuilder:object:generate=false
+type MemoryDetails struct {
+	// CheckSource is where the values come from: proc_meminfo, host_fallback, container_fallback or failed
+	CheckSource          string  `json:"check_source,omitempty"`
+	MemoryUsagePercent   float64 `json:"memory_usage_percent"`
+	TotalMemoryBytes     int64   `json:"total_memory_bytes,omitempty"`
+	AvailableMemoryBytes int64   `json:"available_memory_bytes,omitempty"`
+	FreeMemoryBytes      int64   `json:"free_memory_bytes,omitempty"`
+	UsedMemoryBytes      int64   `json:"used_memory_bytes,omitempty"`
+	BuffersBytes         int64   `json:"buffers_bytes,omitempty"`
+	CachedBytes          int64   `json:"cached_bytes,omitempty"`
+	// SharedBytes and BuffCacheBytes are only set from free -h
+	SharedBytes    int64 `json:"shared_bytes,omitempty"`
+	BuffCacheBytes int64 `json:"buff_cache_bytes,omitempty"`
+
+	FreeOutput      string `json:"free_output,omitempty"`
+	TotalMemory     string `json:"total_memory,omitempty"`
+	UsedMemory      string `json:"used_memory,omitempty"`
+	FreeMemory      string `json:"free_memory,omitempty"`
+	SharedMemory    string `json:"shared_memory,omitempty"`
+	BuffCache       string `json:"buff_cache,omitempty"`
+	AvailableMemory string `json:"available_memory,omitempty"`
+}
+
+// DiskSpaceDetails are the details of the disk space check
+// +kubebuilder:object:generate=false
+type DiskSpaceDetails struct {
+	// CheckSource is where df ran: host or container
+	CheckSource string `json:"check_source,omitempty"`
+	DFOutput    string `json:"df_output,omitempty"`
+	// ToolVersion is the package and version of df (e.g. "GNU coreutils 8.32")
+	ToolVersion string `json:"tool_version,omitempty"`
+	// DiskUsage lists the checked filesystems
+	DiskUsage []FilesystemUsage `json:"disk_usage,omitempty"`
+	// DiskUsageMap is DiskUsage keyed by filesystem, kept for older clients
+	DiskUsageMap map[string]map[string]string `json:"disk_usage_map,omitempty"`
+	// CriticalDisks and WarningDisks list the mount points above the thresholds, as "<mount>: <usage>%"
+	CriticalDisks []string `json:"critical_disks"`
+	WarningDisks  []string `json:"warning_disks"`
+}
+
+// FilesystemUsage is the usage of a filesystem, as reported by df -hPT. Size, Used, Available and
+// UsePercent are the human readable columns of df, the other fields their values in base units.
+// +kubebuilder:object:generate=false
+type FilesystemUsage struct {
+	Filesystem string `json:"filesystem"`
+	FSType     string `json:"fs_type"`
+	Size       string `json:"size"`
+	Used       string `json:"used"`
+	Available  string `json:"available"`
+	UsePercent string `json:"use_percent"`
+	MountedOn  string `json:"mounted_on"`
+
+	SizeBytes      int64   `json:"size_bytes,omitempty"`
+	UsedBytes      int64   `json:"used_bytes,omitempty"`
+	AvailableBytes int64   `json:"available_bytes,omitempty"`
+	UsedPercent    float64 `json:"used_percent,omitempty"`
+}
+
+// NodeResourceUsageDetails are the details of the node resource usage check (metrics-server)
+// +kubebuilder:object:generate=false
+type NodeResourceUsageDetails struct {
+	NodeName    string       `json:"node_name,omitempty"`
+	IsOpenShift bool         `json:"is_openshift"`
+	CPUUsage    *CPUUsage    `json:"cpu_usage,omitempty"`
+	MemoryUsage *MemoryUsage `json:"memory_usage,omitempty"`
+	CheckMethod string       `json:"check_method,omitempty"`
+	Note        string       `json:"note,omitempty"`
+	// Error is set when the metrics could not be read
+	Error string `json:"error,omitempty"`
+}
+
+// CPUUsage is the actual CPU consumption of a node
+// +kubebuilder:object:generate=false
+type CPUUsage struct {
+	// Cores is the usage in millicores (e.g. "1250m")
+	Cores    string  `json:"cores"`
+	Percent  float64 `json:"percent"`
+	Capacity string  `json:"capacity"`
+	// Millicores and CapacityMillicores are Cores and Capacity as numbers
+	Millicores         int64 `json:"millicores"`
+	CapacityMillicores int64 `json:"capacity_millicores"`
+}
+
+// MemoryUsage is the actual memory consumption of a node
+// +kubebuilder:object:generate=false
+type MemoryUsage struct {
+	Bytes    int64   `json:"bytes"`
+	Human    string  `json:"human"`
+	Percent  float64 `json:"percent"`
+	Capacity string  `json:"capacity"`
+	// CapacityBytes is Capacity as a number
+	CapacityBytes int64 `json:"capacity_bytes"`
+}
+
+// ExpandDetails decodes in place the detail values stored as JSON strings. The checks store nested
+// objects and lists as JSON strings so that they survive the status round trip.
+func ExpandDetails(details map[string]interface{}) {
+	for key, value := range details {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		if (strings.HasPrefix(str, "{") && strings.HasSuffix(str, "}")) ||
+			(strings.HasPrefix(str, "[") && strings.HasSuffix(str, "]")) {
+			var parsed interface{}
+			if err := json.Unmarshal([]byte(str), &parsed); err == nil {
+				details[key] = parsed
+			}
+		}
+	}
+}
+
+// DetailsMap returns the details of a result as a map, with the nested values decoded.
+// It returns nil when the result has no details.
+func (in *CheckResult) DetailsMap() (map[string]interface{}, error) {
+	if len(in.Details.Raw) == 0 {
+		return nil, nil
+	}
+	var details map[string]interface{}
+	if err := json.Unmarshal(in.Details.Raw, &details); err != nil {
+		return nil, err
+	}
+	ExpandDetails(details)
+	return details, nil
+}
+
+// DecodeDetails decodes the details of a result into one of the typed schemas (e.g. *MemoryDetails)
+func (in *CheckResult) DecodeDetails(into interface{}) error {
+	details, err := in.DetailsMap()
+	if err != nil || details == nil {
+		return err
+	}
+	data, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
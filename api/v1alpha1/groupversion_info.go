@@ -22,5 +22,5 @@ var (
 func init() {
 	// Register the types with the SchemeBuilder
 	// This must be done before AddToScheme is called
-	SchemeBuilder.Register(&NodeCheck{}, &NodeCheckList{})
+	SchemeBuilder.Register(&NodeCheck{}, &NodeCheckList{}, &NodeCheckTemplate{}, &NodeCheckTemplateList{}, &NodeCheckOperatorConfig{}, &NodeCheckOperatorConfigList{})
 }
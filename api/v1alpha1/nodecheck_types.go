@@ -22,6 +22,10 @@ type CheckResult struct {
 
 	// Details provides additional information about the check
 	Details runtime.RawExtension `json:"details,omitempty"`
+
+	// RunID identifies the run of the executor that produced the result; the results of a run share it
+	// (see status.runs)
+	RunID string `json:"runID,omitempty"`
 }
 
 // NodeCheckSpec defines the desired state of NodeCheck
@@ -33,9 +37,115 @@ type NodeCheckSpec struct {
 	// - Use a specific node name to check only that node.
 	NodeName string `json:"nodeName,omitempty"`
 
+	// Paused stops check executions without deleting the NodeCheck, so the last results and the
+	// history are kept (e.g. while a node is being debugged). On a "*" NodeCheck it pauses all nodes.
+	// A single NodeCheck can also be paused with the node-check.openshift.io/skip: "true" annotation.
+	Paused bool `json:"paused,omitempty"`
+
 	// CheckInterval is the interval between checks in minutes
 	CheckInterval int `json:"checkInterval,omitempty"`
 
+	// Scheduling selects what runs the checks:
+	// - "Interval" (default): every checkInterval (and categoryIntervals) minutes
+	// - "External": only when triggered through the nodecheck.openshift.io/trigger annotation, e.g. by
+	//   the dashboard trigger webhook called from a CronJob or a KEDA ScaledJob; the intervals are ignored
+	// A trigger also runs the checks of an Interval NodeCheck right away.
+	// +kubebuilder:validation:Enum=Interval;External
+	Scheduling string `json:"scheduling,omitempty"`
+
+	// CategoryIntervals optionally overrides CheckInterval per check category, so expensive
+	// checks (e.g. SMART) can run less often than cheap ones. Each category is scheduled independently.
+	CategoryIntervals *CategoryIntervals `json:"categoryIntervals,omitempty"`
+
+	// Timeouts configures how long checks may run before they are cancelled
+	Timeouts *CheckTimeouts `json:"timeouts,omitempty"`
+
+	// ResultLogging makes the executor log every completed check as a single structured
+	// entry (node, check, status, duration, key numbers) at info level.
+	// - "None" (default): no per-check log entries
+	// - "NonHealthy": only checks that are not Healthy
+	// - "All": every check
+	// +kubebuilder:validation:Enum=None;NonHealthy;All
+	ResultLogging string `json:"resultLogging,omitempty"`
+
+	// ReportDetail defines which check results keep their details in the status, to keep the
+	// NodeCheck objects (and etcd) small on large fleets:
+	// - "Full" (default): every result keeps its details
+	// - "FailuresOnly": only results that are not Healthy keep their details
+	// - "Summary": no result keeps its details, only status and message are stored
+	// +kubebuilder:validation:Enum=Full;FailuresOnly;Summary
+	ReportDetail string `json:"reportDetail,omitempty"`
+
+	// AggregationPolicy defines how check results are combined into the overall status:
+	// - "Worst" (default): the worst check status wins, a single Warning makes the node Warning
+	// - "Weighted": Warning/Critical when non-healthy checks reach 10%/25% of the total check weight
+	// - "Quorum": Warning/Critical when at least half of the checks are non-healthy/Critical
+	// - "CriticalOnly": only Critical checks degrade the node, Warnings are reported in the message
+	// +kubebuilder:validation:Enum=Worst;Weighted;Quorum;CriticalOnly
+	AggregationPolicy string `json:"aggregationPolicy,omitempty"`
+
+	// CheckWeights sets the weight of individual checks for the Weighted policy, keyed by check name
+	// (e.g. "disk_smart": 5). Checks default to weight 1; 0 excludes a check from the overall status.
+	CheckWeights map[string]int `json:"checkWeights,omitempty"`
+
+	// Thresholds overrides the usage percentages at which checks report Warning and Critical, keyed by
+	// check name. Supported by memory, file_descriptors, disk_space, disk_inode_usage, pressure_stall,
+	// conntrack, inotify, process_limits, disk_runtime_storage and disk_wear_level.
+	Thresholds map[string]CheckThresholds `json:"thresholds,omitempty"`
+
+	// TimeDrift overrides the clock offsets at which the time_drift check reports Warning and Critical
+	TimeDrift *TimeDriftThresholds `json:"timeDrift,omitempty"`
+
+	// CertificateExpiry adds certificate files and directories to the paths the certificate_expiry check
+	// scans and overrides the days before expiry at which it reports Warning and Critical
+	CertificateExpiry *CertificateExpiryConfig `json:"certificateExpiry,omitempty"`
+
+	// Sampling makes the checks of spiky metrics (cpu_steal_time, context_switches, disk_io_wait) sample
+	// them several times within a run and report their min, average, max and a percentile, instead of a
+	// single snapshot. Unset, each check keeps its single measurement.
+	Sampling *SamplingConfig `json:"sampling,omitempty"`
+
+	// Smoothing makes checks compare an exponentially weighted moving average (EWMA) of their value over
+	// the last runs with their thresholds, instead of the value of the run, keyed by check name. Supported
+	// by cpu_steal_time, uninterruptible_tasks, context_switches and disk_io_wait; a smoothed check no
+	// longer counts its recent high values in an event window.
+	Smoothing map[string]SmoothingConfig `json:"smoothing,omitempty"`
+
+	// CheckDependencies lists the prerequisites of individual checks, keyed by check name. A check only runs
+	// when all its prerequisites are met; otherwise it is skipped and reported in the ChecksSkipped condition.
+	// A prerequisite is either another check, whose latest result must be Healthy, or a node capability
+	// ("capability:lvm", "capability:ipmi") probed once per hour. By default disk_lvm and disk_pvs require
+	// capability:lvm and hardware_ipmi and hardware_bmc require capability:ipmi; an empty list disables the default.
+	CheckDependencies map[string][]string `json:"checkDependencies,omitempty"`
+
+	// MaxConcurrentChecks is the number of checks run at the same time on a node (default: 1, one check
+	// at a time). Higher values shorten the runs of nodes with many checks enabled; a check still waits
+	// for the checks it depends on (spec.checkDependencies).
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=16
+	// +optional
+	MaxConcurrentChecks int `json:"maxConcurrentChecks,omitempty"`
+
+	// CheckOrder runs the listed checks first, one at a time and in this order, before any other check.
+	// List the I/O-heavy checks (e.g. disk_performance, disk_smart, system_logs) here so they never
+	// overlap and skew each other's measurements when maxConcurrentChecks is greater than 1.
+	// +optional
+	CheckOrder []string `json:"checkOrder,omitempty"`
+
+	// ResultLabels are stamped by the executor onto the NodeCheck labels after each run (e.g. team,
+	// environment) and exposed by the dashboard API and metrics, so alerts can be routed by ownership
+	ResultLabels map[string]string `json:"resultLabels,omitempty"`
+
+	// ResultAnnotations are stamped by the executor onto the NodeCheck annotations after each run
+	// (e.g. a runbook URL or an on-call contact) and exposed by the dashboard API
+	ResultAnnotations map[string]string `json:"resultAnnotations,omitempty"`
+
+	// HistorySize is the number of past results kept per check in status.history,
+	// so flapping checks can be spotted without external storage. 0 (default) disables the history.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	HistorySize int `json:"historySize,omitempty"`
+
 	// NodeSelector is a label query over nodes that determines which nodes the executor DaemonSet
 	// should be scheduled on. If specified, the DaemonSet will only run on nodes matching the selector.
 	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
@@ -44,11 +154,322 @@ type NodeCheckSpec struct {
 	// If specified, the DaemonSet pods will tolerate the listed taints.
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// ExecutorConfig customizes the executor DaemonSet pods (scheduling, resources, image).
+	// Unlike NodeSelector, it also applies to NodeChecks with nodeName "*".
+	ExecutorConfig *ExecutorConfig `json:"executorConfig,omitempty"`
+
 	// SystemChecks defines which system-level checks to perform
 	SystemChecks SystemChecks `json:"systemChecks,omitempty"`
 
 	// KubernetesChecks defines which Kubernetes-level checks to perform
 	KubernetesChecks KubernetesChecks `json:"kubernetesChecks,omitempty"`
+
+	// Suppressions defines maintenance windows during which checks still run but their
+	// results are marked "Suppressed" and excluded from OverallStatus (e.g. planned patching).
+	Suppressions []SuppressionWindow `json:"suppressions,omitempty"`
+
+	// Expectations declares the expected state of the node. Checks compare the actual state against
+	// these expectations and report the differences instead of applying their built-in opinions.
+	Expectations *ExpectedState `json:"expectations,omitempty"`
+
+	// Baseline records the node configuration on the first run and reports the changes found by later
+	// runs in the baseline_drift check, so unexpected upgrades or manual tuning are noticed.
+	Baseline *BaselineSpec `json:"baseline,omitempty"`
+
+	// Filters customizes which mount points, block devices, network interfaces and namespaces
+	// the disk, network and Kubernetes checks look at, on top of the built-in skip lists
+	Filters *CheckFilters `json:"filters,omitempty"`
+
+	// EmitEvents records Kubernetes Events when checks change status, so transitions show up in
+	// kubectl describe and in the event stream of the cluster
+	EmitEvents *EventsSpec `json:"emitEvents,omitempty"`
+
+	// CloudEvents sends the status transitions of the checks as CloudEvents, for Knative eventing and
+	// event-driven automation
+	CloudEvents *CloudEventsSpec `json:"cloudEvents,omitempty"`
+}
+
+// EventsSpec configures the Events recorded by the executor when a check changes status:
+// a Warning CheckDegraded event when it goes from Healthy to Warning or Critical (or from Warning
+// to Critical), and a Normal CheckRecovered event when it is Healthy again
+type EventsSpec struct {
+	// Enabled records the Events on the NodeCheck
+	Enabled bool `json:"enabled,omitempty"`
+
+	// OnNode also records them on the Node, so they are listed by kubectl describe node
+	OnNode bool `json:"onNode,omitempty"`
+}
+
+// CloudEventsSpec configures the CloudEvents sent by the executor when a check changes status, with the
+// same transitions as the Events: io.nodecheck.check.critical and io.nodecheck.check.warning when it
+// degrades, io.nodecheck.check.recovered when it is Healthy again
+type CloudEventsSpec struct {
+	// Binding is the protocol binding: HTTP (default) posts the events to Sink in binary content mode,
+	// Kafka produces them to Topic through the Kafka bridge at Sink (Strimzi Kafka Bridge HTTP API)
+	// +kubebuilder:validation:Enum=HTTP;Kafka
+	Binding string `json:"binding,omitempty"`
+
+	// Sink is the URL the events are sent to, e.g. a Knative broker
+	// (http://broker-ingress.knative-eventing.svc.cluster.local/<namespace>/default) or the Kafka bridge
+	// +kubebuilder:validation:Pattern=`^https?://.+$`
+	Sink string `json:"sink"`
+
+	// Topic is the Kafka topic of the Kafka binding
+	Topic string `json:"topic,omitempty"`
+}
+
+// CategoryIntervals defines per-category check intervals in minutes.
+// A category left unset (0) uses CheckInterval.
+type CategoryIntervals struct {
+	// System applies to the top-level system checks (uptime, memory, processes, ...)
+	System int `json:"system,omitempty"`
+
+	// Disks applies to the disk checks
+	Disks int `json:"disks,omitempty"`
+
+	// Network applies to the network checks
+	Network int `json:"network,omitempty"`
+
+	// Hardware applies to the hardware checks
+	Hardware int `json:"hardware,omitempty"`
+
+	// Kubernetes applies to the Kubernetes checks
+	Kubernetes int `json:"kubernetes,omitempty"`
+}
+
+// CheckThresholds defines the usage percentages at which a check reports Warning and Critical.
+// A threshold left unset (0) keeps the check's built-in value.
+type CheckThresholds struct {
+	// Warning is the usage percentage from which the check reports Warning
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Warning int `json:"warning,omitempty"`
+
+	// Critical is the usage percentage from which the check reports Critical
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Critical int `json:"critical,omitempty"`
+}
+
+// TimeDriftThresholds defines the clock offsets, in milliseconds, at which the time_drift check reports
+// Warning and Critical. A threshold left unset (0) keeps the built-in value.
+type TimeDriftThresholds struct {
+	// WarningMilliseconds is the offset from which the check reports Warning (default 100)
+	// +kubebuilder:validation:Minimum=0
+	WarningMilliseconds int `json:"warningMilliseconds,omitempty"`
+
+	// CriticalMilliseconds is the offset from which the check reports Critical (default 1000)
+	// +kubebuilder:validation:Minimum=0
+	CriticalMilliseconds int `json:"criticalMilliseconds,omitempty"`
+}
+
+// CertificateExpiryConfig defines the certificates the certificate_expiry check reads and the days
+// before their expiry at which it reports Warning and Critical. A threshold left unset (0) keeps the
+// built-in value.
+type CertificateExpiryConfig struct {
+	// Paths are certificate files, or directories whose *.crt and *.pem files (two levels deep) are read,
+	// scanned in addition to the kubelet, etcd and Kubernetes PKI defaults (e.g. a custom PKI directory).
+	// Paths must be absolute; missing paths are skipped.
+	// +kubebuilder:validation:MaxItems=32
+	Paths []string `json:"paths,omitempty"`
+
+	// WarningDays is the number of days before expiry from which a certificate is a Warning (default 30)
+	// +kubebuilder:validation:Minimum=0
+	WarningDays int `json:"warningDays,omitempty"`
+
+	// CriticalDays is the number of days before expiry from which a certificate is Critical (default 7)
+	// +kubebuilder:validation:Minimum=0
+	CriticalDays int `json:"criticalDays,omitempty"`
+}
+
+// SamplingConfig defines how the checks of spiky metrics sample them within a run
+type SamplingConfig struct {
+	// Samples is the number of samples taken per run (default 5)
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=30
+	Samples int `json:"samples,omitempty"`
+
+	// Interval is the time between two samples, in whole seconds from 1s to 10s (default 1s)
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Percentile is the percentile of the samples reported along min, average and max (default 95)
+	// +kubebuilder:validation:Minimum=50
+	// +kubebuilder:validation:Maximum=99
+	Percentile int `json:"percentile,omitempty"`
+
+	// Statistic is the value of the samples the thresholds apply to:
+	// - "Average" (default): the mean, short spikes do not trigger a Warning on their own
+	// - "Percentile": the percentile above, for metrics whose spikes matter
+	// - "Max": the highest sample, as sensitive as the single snapshot
+	// +kubebuilder:validation:Enum=Average;Percentile;Max
+	Statistic string `json:"statistic,omitempty"`
+}
+
+// SmoothingConfig defines the exponentially weighted moving average a check compares with its thresholds
+type SmoothingConfig struct {
+	// Samples is the number of runs the average covers, the current one included (default 5)
+	// +kubebuilder:validation:Minimum=2
+	// +kubebuilder:validation:Maximum=50
+	Samples int `json:"samples,omitempty"`
+
+	// WeightPercent is the weight of the newest value, in percent; the older values weigh exponentially
+	// less (default 2/(samples+1), 33% for 5 samples)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	WeightPercent int `json:"weightPercent,omitempty"`
+}
+
+// CheckTimeouts defines global and per-check timeouts
+type CheckTimeouts struct {
+	// Default is applied to every check without a specific override (e.g. "30s").
+	// If unset, each check uses its built-in timeout.
+	Default *metav1.Duration `json:"default,omitempty"`
+
+	// Checks overrides the timeout of individual checks, keyed by check name
+	// (e.g. "system_logs": "60s", "disk_smart": "2m")
+	Checks map[string]metav1.Duration `json:"checks,omitempty"`
+}
+
+// SuppressionWindow defines a maintenance window, either as a fixed start/end range
+// or as a recurring cron schedule with a duration
+type SuppressionWindow struct {
+	// Name identifies the window in status and check messages
+	Name string `json:"name"`
+
+	// Start is the beginning of a one-off window (used together with End)
+	Start *metav1.Time `json:"start,omitempty"`
+
+	// End is the end of a one-off window (used together with Start)
+	End *metav1.Time `json:"end,omitempty"`
+
+	// Schedule is a standard 5-field cron expression (minute hour day-of-month month day-of-week)
+	// evaluated in UTC that marks the start of a recurring window
+	Schedule string `json:"schedule,omitempty"`
+
+	// Duration is how long a recurring window stays open after each Schedule match (e.g. "2h")
+	Duration metav1.Duration `json:"duration,omitempty"`
+
+	// Checks restricts the suppression to the listed check names (e.g. "disk_smart", "ntp_sync").
+	// If empty, all checks are suppressed while the window is active.
+	Checks []string `json:"checks,omitempty"`
+}
+
+// ExpectedState declares the expected node state checked by the selinux_status, ntp_sync,
+// kernel_modules, services, transparent_hugepages, sysctl_drift, kernel_cmdline, sriov and swap_policy
+// checks. Unset fields keep the built-in behavior.
+type ExpectedState struct {
+	// SELinux is the expected SELinux mode
+	// +kubebuilder:validation:Enum=Enforcing;Permissive;Disabled
+	SELinux string `json:"selinux,omitempty"`
+
+	// NTPDaemon is the expected time synchronization daemon
+	// +kubebuilder:validation:Enum=chronyd;ntpd;systemd-timesyncd
+	NTPDaemon string `json:"ntpDaemon,omitempty"`
+
+	// RequiredKernelModules lists kernel modules that must be loaded (e.g. "br_netfilter", "overlay")
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_-]+$`
+	RequiredKernelModules []string `json:"requiredKernelModules,omitempty"`
+
+	// RequiredServices lists systemd units that must be active (e.g. "crio", "kubelet")
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9@._:-]+$`
+	RequiredServices []string `json:"requiredServices,omitempty"`
+
+	// TransparentHugePages is the expected Transparent Huge Pages mode (databases typically require "never")
+	// +kubebuilder:validation:Enum=always;madvise;never
+	TransparentHugePages string `json:"transparentHugePages,omitempty"`
+
+	// TransparentHugePagesDefrag is the expected Transparent Huge Pages defrag mode
+	// +kubebuilder:validation:Enum=always;defer;defer+madvise;madvise;never
+	TransparentHugePagesDefrag string `json:"transparentHugePagesDefrag,omitempty"`
+
+	// Sysctls are the expected values of kernel parameters, keyed by name as in sysctl
+	// (e.g. "net.ipv4.ip_forward": "1"). Values made of several fields (e.g. net.ipv4.ip_local_port_range)
+	// are compared field by field, whatever the whitespace.
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+
+	// RequiredKernelParameters lists the parameters the kernel must have been booted with, as "name"
+	// (any value) or "name=value" (e.g. "intel_iommu=on", "hugepagesz=1G")
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_.-]+(=\S+)?$`
+	RequiredKernelParameters []string `json:"requiredKernelParameters,omitempty"`
+
+	// ForbiddenKernelParameters lists the parameters the kernel must not have been booted with, as
+	// "name" (any value) or "name=value" (e.g. "mitigations=off", "selinux=0")
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_.-]+(=\S+)?$`
+	ForbiddenKernelParameters []string `json:"forbiddenKernelParameters,omitempty"`
+
+	// SRIOVVirtualFunctions is the number of SR-IOV virtual functions expected on each physical
+	// function, keyed by interface name (e.g. "ens1f0": 8)
+	SRIOVVirtualFunctions map[string]int `json:"sriovVirtualFunctions,omitempty"`
+
+	// Swap is the swap policy of the node: Disabled (the kubelet default) wants swap off, NoSwap and
+	// LimitedSwap a kubelet with failSwapOn false and the same memorySwap.swapBehavior
+	// +kubebuilder:validation:Enum=Disabled;NoSwap;LimitedSwap
+	Swap string `json:"swap,omitempty"`
+}
+
+// BaselineSpec configures the baseline drift detection
+type BaselineSpec struct {
+	// Enabled records the kernel version, sysctls, loaded kernel modules, mounted filesystems and
+	// network interfaces of the node in status.baseline on the first run. Later runs compare the node
+	// against it and the baseline_drift check reports Warning with the differences.
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Sysctls lists the kernel parameters recorded in the baseline (default: a set of common
+	// kernel, memory and network parameters)
+	// +kubebuilder:validation:items:Pattern=`^[a-zA-Z0-9_.-]+$`
+	Sysctls []string `json:"sysctls,omitempty"`
+
+	// Recapture captures the baseline again when its value changes (e.g. set it to the date of an
+	// accepted upgrade). The value used for the current baseline is kept in status.baseline.recapture.
+	Recapture string `json:"recapture,omitempty"`
+}
+
+// ExecutorConfig customizes the executor DaemonSet. All NodeChecks share a single DaemonSet:
+// tolerations and node selectors are merged, while for the other fields the first NodeCheck
+// (by namespace/name) that sets them wins.
+type ExecutorConfig struct {
+	// Tolerations are added to the executor pods, e.g. to run on tainted masters or infra nodes
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeSelector restricts the nodes the executor pods run on
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Resources overrides the resource requests and limits of the executor container
+	// (default: requests 10m CPU / 64Mi memory, limits 500m CPU / 128Mi memory)
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// PriorityClassName is the priority class of the executor pods
+	// (e.g. "system-node-critical" so they are not evicted first under pressure)
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Image overrides the executor image (default: the operator image)
+	Image string `json:"image,omitempty"`
+}
+
+// CheckFilters defines include/exclude patterns per kind of checked object
+type CheckFilters struct {
+	// MountPoints filters mount points in the disk space and inode checks (e.g. "/var/lib/etcd", "/mnt/*")
+	MountPoints *FilterPatterns `json:"mountPoints,omitempty"`
+
+	// Devices filters block devices in the disk checks (e.g. "sdb", "/dev/nvme*", "dm-*")
+	Devices *FilterPatterns `json:"devices,omitempty"`
+
+	// Interfaces filters network interfaces in the network checks (e.g. "br-ex", "veth*")
+	Interfaces *FilterPatterns `json:"interfaces,omitempty"`
+
+	// Namespaces filters namespaces in the pod and service checks (e.g. "openshift-*")
+	Namespaces *FilterPatterns `json:"namespaces,omitempty"`
+}
+
+// FilterPatterns holds shell-style glob patterns (as in path.Match).
+// Exclude skips matching objects in addition to the built-in skip lists; Include brings back
+// objects that the built-in skip lists would ignore. Exclude takes precedence over Include.
+type FilterPatterns struct {
+	// Include lists patterns of objects to check even if they are skipped by default
+	Include []string `json:"include,omitempty"`
+
+	// Exclude lists patterns of objects to skip
+	Exclude []string `json:"exclude,omitempty"`
 }
 
 // SystemChecks defines system-level checks
@@ -74,6 +495,33 @@ type SystemChecks struct {
 	SELinuxStatus       bool           `json:"selinuxStatus,omitempty"`
 	SSHAccess           bool           `json:"sshAccess,omitempty"`
 	KernelModules       bool           `json:"kernelModules,omitempty"`
+	KernelTaint         bool           `json:"kernelTaint,omitempty"`
+	PressureStall       bool           `json:"pressureStall,omitempty"`
+	Entropy             bool           `json:"entropy,omitempty"`
+	TransparentHugePages bool          `json:"transparentHugePages,omitempty"`
+	HugePages           bool           `json:"hugePages,omitempty"`
+	NUMA                bool           `json:"numa,omitempty"`
+	Conntrack           bool           `json:"conntrack,omitempty"`
+	KnownIssues         bool           `json:"knownIssues,omitempty"`
+	Kdump               bool           `json:"kdump,omitempty"`
+	SysctlDrift         bool           `json:"sysctlDrift,omitempty"`
+	Inotify             bool           `json:"inotify,omitempty"`
+	ServiceRestarts     bool           `json:"serviceRestarts,omitempty"`
+	CoreDumps           bool           `json:"coreDumps,omitempty"`
+	TimeDrift           bool           `json:"timeDrift,omitempty"`
+	Audit               bool           `json:"audit,omitempty"`
+	CPUVulnerabilities  bool           `json:"cpuVulnerabilities,omitempty"`
+	KernelCmdline       bool           `json:"kernelCmdline,omitempty"`
+	OSUpdates           bool           `json:"osUpdates,omitempty"`
+	CgroupDriver        bool           `json:"cgroupDriver,omitempty"`
+	ProcessLimits       bool           `json:"processLimits,omitempty"`
+	OrphanedMounts      bool           `json:"orphanedMounts,omitempty"`
+	UserspaceOOM        bool           `json:"userspaceOOM,omitempty"`
+	GPU                 bool           `json:"gpu,omitempty"` // NVIDIA/AMD GPU health with nvidia-smi or rocm-smi
+	SRIOV               bool           `json:"sriov,omitempty"` // IOMMU and SR-IOV virtual functions per physical function
+	KernelLockups       bool           `json:"kernelLockups,omitempty"` // Soft/hard lockups, hung tasks and RCU stalls in the kernel log
+	SwapPolicy          bool           `json:"swapPolicy,omitempty"` // Swap configuration against the swap policy and the kubelet failSwapOn
+	CertificateExpiry   bool           `json:"certificateExpiry,omitempty"` // Days until the expiry of the kubelet, etcd and custom certificate files
 	Hardware            HardwareChecks `json:"hardware,omitempty"`
 	Disks               DiskChecks     `json:"disks,omitempty"`
 	Network             NetworkChecks  `json:"network,omitempty"`
@@ -92,6 +540,9 @@ type DiskChecks struct {
 	FilesystemErrors bool `json:"filesystemErrors,omitempty"`
 	InodeUsage      bool `json:"inodeUsage,omitempty"`
 	MountPoints     bool `json:"mountPoints,omitempty"`
+	WriteProbe      bool `json:"writeProbe,omitempty"` // Write and fsync a probe file under /var, /var/log and /etc
+	RuntimeStorage  bool `json:"runtimeStorage,omitempty"` // Image storage of CRI-O/containerd against the kubelet image GC thresholds
+	WearLevel       bool `json:"wearLevel,omitempty"` // Consumed and projected endurance of the SSDs and NVMe drives
 }
 
 // HardwareChecks defines hardware-related checks
@@ -117,6 +568,40 @@ type NetworkChecks struct {
 	DNSResolution   bool `json:"dnsResolution,omitempty"`
 	BondingStatus   bool `json:"bondingStatus,omitempty"`
 	FirewallRules   bool `json:"firewallRules,omitempty"`
+
+	// Egress validates north-south traffic: a request to EgressEchoURL must succeed and the source IP
+	// reported by the echo endpoint must be one of EgressExpectedSourceIPs (e.g. the egress IP or gateway)
+	Egress bool `json:"egress,omitempty"`
+
+	// EgressEchoURL is an external HTTP(S) endpoint that returns the caller's IP address as plain text
+	// (e.g. "https://ifconfig.me/ip" or an internal echo service)
+	// +kubebuilder:validation:Pattern=`^https?://[A-Za-z0-9._~:/?#@!&()*+,;=%\[\]-]+$`
+	EgressEchoURL string `json:"egressEchoURL,omitempty"`
+
+	// EgressExpectedSourceIPs lists the IP addresses or CIDRs the traffic is expected to exit with.
+	// If empty, only reachability is checked and the observed source IP is reported.
+	EgressExpectedSourceIPs []string `json:"egressExpectedSourceIPs,omitempty"`
+
+	// EgressNamespace runs the probe from the network namespace of a pod of this namespace on the node
+	// instead of the host, for egress IPs assigned to namespaces. Nodes without such a pod are skipped.
+	EgressNamespace string `json:"egressNamespace,omitempty"`
+
+	// Ingress completes a TLS handshake for a canary route through the ingress VIP and with every
+	// default router pod, catching asymmetric routing or per-node firewall rules affecting ingress traffic
+	Ingress bool `json:"ingress,omitempty"`
+
+	// IngressCanaryHost is the route host used for the handshake
+	// (default: the host of the OpenShift canary route openshift-ingress-canary/canary)
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?$`
+	IngressCanaryHost string `json:"ingressCanaryHost,omitempty"`
+
+	// IngressVIP is the ingress virtual IP or load balancer address to connect to.
+	// If empty, the canary host is resolved through the node's DNS.
+	IngressVIP string `json:"ingressVIP,omitempty"`
+
+	// ResolvConf validates /etc/resolv.conf on the host: nameservers present and reachable, ndots and
+	// the length of the search list
+	ResolvConf bool `json:"resolvConf,omitempty"`
 }
 
 // KubernetesChecks defines Kubernetes-level checks
@@ -130,6 +615,32 @@ type KubernetesChecks struct {
 	KubeletHealth     bool `json:"kubeletHealth,omitempty"`
 	CNIPlugin         bool `json:"cniPlugin,omitempty"`
 	NodeConditions    bool `json:"nodeConditions,omitempty"`
+
+	// PodNetwork probes the cluster DNS and the kubernetes service from inside the network namespace
+	// of a pod running on the node, since CNI problems often only affect the pod path
+	PodNetwork bool `json:"podNetwork,omitempty"`
+
+	// LBHealthCheck validates the ports cloud load balancers probe on the node (kube-proxy healthz,
+	// healthCheckNodePort and node ports of LoadBalancer services), since a failing probe silently
+	// pulls the node out of load balancer rotation
+	LBHealthCheck bool `json:"lbHealthCheck,omitempty"`
+
+	// PodScheduling schedules a tiny pause pod pinned to the node and measures the time until
+	// it is running and until it is torn down again (opt-in, creates and deletes a pod on every run)
+	PodScheduling bool `json:"podScheduling,omitempty"`
+
+	// PodSchedulingImage overrides the image used by the PodScheduling and PVCProvisioning checks
+	// (default: registry.k8s.io/pause:3.9)
+	PodSchedulingImage string `json:"podSchedulingImage,omitempty"`
+
+	// PVCProvisioning provisions a 1Gi PVC per storage class, mounts it in a pause pod pinned to
+	// the node and measures the provisioning and attach/mount latency (opt-in, creates and deletes
+	// a PVC and a pod on every run)
+	PVCProvisioning bool `json:"pvcProvisioning,omitempty"`
+
+	// PVCProvisioningStorageClasses lists the storage classes tested by the PVCProvisioning check
+	// (default: the cluster's default storage class)
+	PVCProvisioningStorageClasses []string `json:"pvcProvisioningStorageClasses,omitempty"`
 }
 
 // SystemCheckResults contains the results of system-level checks
@@ -155,6 +666,34 @@ type SystemCheckResults struct {
 	SELinuxStatus       *CheckResult           `json:"selinuxStatus,omitempty"`
 	SSHAccess           *CheckResult           `json:"sshAccess,omitempty"`
 	KernelModules       *CheckResult           `json:"kernelModules,omitempty"`
+	KernelTaint         *CheckResult           `json:"kernelTaint,omitempty"`
+	PressureStall       *CheckResult           `json:"pressureStall,omitempty"`
+	Entropy             *CheckResult           `json:"entropy,omitempty"`
+	TransparentHugePages *CheckResult          `json:"transparentHugePages,omitempty"`
+	HugePages           *CheckResult           `json:"hugePages,omitempty"`
+	NUMA                *CheckResult           `json:"numa,omitempty"`
+	Conntrack           *CheckResult           `json:"conntrack,omitempty"`
+	KnownIssues         *CheckResult           `json:"knownIssues,omitempty"`
+	Kdump               *CheckResult           `json:"kdump,omitempty"`
+	SysctlDrift         *CheckResult           `json:"sysctlDrift,omitempty"`
+	Inotify             *CheckResult           `json:"inotify,omitempty"`
+	ServiceRestarts     *CheckResult           `json:"serviceRestarts,omitempty"`
+	CoreDumps           *CheckResult           `json:"coreDumps,omitempty"`
+	TimeDrift           *CheckResult           `json:"timeDrift,omitempty"`
+	Audit               *CheckResult           `json:"audit,omitempty"`
+	CPUVulnerabilities  *CheckResult           `json:"cpuVulnerabilities,omitempty"`
+	KernelCmdline       *CheckResult           `json:"kernelCmdline,omitempty"`
+	OSUpdates           *CheckResult           `json:"osUpdates,omitempty"`
+	CgroupDriver        *CheckResult           `json:"cgroupDriver,omitempty"`
+	ProcessLimits       *CheckResult           `json:"processLimits,omitempty"`
+	OrphanedMounts      *CheckResult           `json:"orphanedMounts,omitempty"`
+	UserspaceOOM        *CheckResult           `json:"userspaceOOM,omitempty"`
+	GPU                 *CheckResult           `json:"gpu,omitempty"`
+	SRIOV               *CheckResult           `json:"sriov,omitempty"`
+	KernelLockups       *CheckResult           `json:"kernelLockups,omitempty"`
+	SwapPolicy          *CheckResult           `json:"swapPolicy,omitempty"`
+	CertificateExpiry   *CheckResult           `json:"certificateExpiry,omitempty"`
+	BaselineDrift       *CheckResult           `json:"baselineDrift,omitempty"`
 	Hardware            *HardwareCheckResults  `json:"hardware,omitempty"`
 	Disks               *DiskCheckResults      `json:"disks,omitempty"`
 	Network             *NetworkCheckResults   `json:"network,omitempty"`
@@ -185,6 +724,9 @@ type DiskCheckResults struct {
 	FilesystemErrors *CheckResult `json:"filesystemErrors,omitempty"`
 	InodeUsage       *CheckResult `json:"inodeUsage,omitempty"`
 	MountPoints      *CheckResult `json:"mountPoints,omitempty"`
+	WriteProbe       *CheckResult `json:"writeProbe,omitempty"`
+	RuntimeStorage   *CheckResult `json:"runtimeStorage,omitempty"`
+	WearLevel        *CheckResult `json:"wearLevel,omitempty"`
 }
 
 // NetworkCheckResults contains network check results
@@ -198,6 +740,9 @@ type NetworkCheckResults struct {
 	DNSResolution *CheckResult `json:"dnsResolution,omitempty"`
 	BondingStatus *CheckResult `json:"bondingStatus,omitempty"`
 	FirewallRules *CheckResult `json:"firewallRules,omitempty"`
+	Egress        *CheckResult `json:"egress,omitempty"`
+	Ingress       *CheckResult `json:"ingress,omitempty"`
+	ResolvConf    *CheckResult `json:"resolvConf,omitempty"`
 }
 
 // KubernetesCheckResults contains the results of Kubernetes-level checks
@@ -211,6 +756,10 @@ type KubernetesCheckResults struct {
 	KubeletHealth      *CheckResult `json:"kubeletHealth,omitempty"`
 	CNIPlugin          *CheckResult `json:"cniPlugin,omitempty"`
 	NodeConditions     *CheckResult `json:"nodeConditions,omitempty"`
+	PodScheduling      *CheckResult `json:"podScheduling,omitempty"`
+	PVCProvisioning    *CheckResult `json:"pvcProvisioning,omitempty"`
+	PodNetwork         *CheckResult `json:"podNetwork,omitempty"`
+	LBHealthCheck      *CheckResult `json:"lbHealthCheck,omitempty"`
 }
 
 // CheckResults contains all check results
@@ -235,15 +784,192 @@ type NodeCheckStatus struct {
 
 	// CheckResults contains all check results
 	CheckResults CheckResults `json:"checkResults,omitempty"`
+
+	// SuppressedBy is the comma-separated names of the maintenance windows that were active during the last check
+	SuppressedBy string `json:"suppressedBy,omitempty"`
+
+	// Conditions report the state of the executor for this NodeCheck.
+	// "Degraded" is True while checks failing with environmental errors (tool missing,
+	// permission denied) are backed off. "Paused" is True while check executions are paused.
+	// "ChecksSkipped" is True while checks are skipped because their prerequisites are not met.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// History keeps the last spec.historySize results of each check (oldest first)
+	// +listType=map
+	// +listMapKey=name
+	History []CheckHistory `json:"history,omitempty"`
+
+	// Lifecycle records the lifecycle events of the node (provisioning, reboots, kubelet and MachineConfig
+	// updates) as markers for the history, so status changes can be correlated with maintenance. Kept
+	// while spec.historySize is set.
+	Lifecycle *NodeLifecycle `json:"lifecycle,omitempty"`
+
+	// Baseline is the node configuration recorded when spec.baseline is enabled
+	Baseline *NodeBaseline `json:"baseline,omitempty"`
+
+	// Verification is the last post-maintenance verification of the node, requested with the
+	// nodecheck.openshift.io/verify annotation
+	Verification *VerificationStatus `json:"verification,omitempty"`
+
+	// Trigger is the last run requested with the nodecheck.openshift.io/trigger annotation
+	Trigger *TriggerStatus `json:"trigger,omitempty"`
+
+	// Runs are the last runs of the executor, oldest first. The results of a run carry its ID (runID),
+	// so the results of a single run can be told apart from the results kept from earlier runs.
+	// +listType=map
+	// +listMapKey=id
+	Runs []RunRecord `json:"runs,omitempty"`
+}
+
+// RunRecord is a run of the executor: the checks of the due categories, run together
+type RunRecord struct {
+	// ID identifies the run; the results of the run carry it as runID
+	ID string `json:"id"`
+
+	// Categories are the check categories that ran
+	Categories []string `json:"categories,omitempty"`
+
+	// Checks are the checks that produced a result in the run
+	Checks []string `json:"checks,omitempty"`
+
+	// StartTime is when the run started
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is when the results of the run were stored; unset while the run is in progress
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// TriggerStatus records the check categories run for the value of the trigger annotation, so each
+// category runs once per trigger
+type TriggerStatus struct {
+	// Value is the value of the trigger annotation
+	Value string `json:"value"`
+
+	// Categories are the check categories that ran for the trigger
+	Categories []string `json:"categories,omitempty"`
+
+	// LastRunTime is when the last category ran for the trigger
+	LastRunTime metav1.Time `json:"lastRunTime"`
+}
+
+// VerificationStatus is the outcome of a post-maintenance verification: a one-shot run of all the checks
+// with tighter thresholds, gating the return of the node to service
+type VerificationStatus struct {
+	// Phase of the verification
+	// +kubebuilder:validation:Enum=Running;Passed;Failed
+	Phase string `json:"phase"`
+
+	// RequestedBy is the user who requested the verification, when known
+	RequestedBy string `json:"requestedBy,omitempty"`
+
+	// StartTime is when the verification NodeCheck was created
+	StartTime metav1.Time `json:"startTime"`
+
+	// CompletionTime is when the gate was evaluated
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// NodeCheck is the one-shot NodeCheck running the verification profile, deleted once evaluated
+	NodeCheck string `json:"nodeCheck,omitempty"`
+
+	// AutoUncordon uncordons the node when the verification passes
+	AutoUncordon bool `json:"autoUncordon,omitempty"`
+
+	// Uncordoned is true when the node was uncordoned by the verification
+	Uncordoned bool `json:"uncordoned,omitempty"`
+
+	// FailedChecks lists the checks in Warning or Critical status under the verification profile
+	FailedChecks []string `json:"failedChecks,omitempty"`
+
+	// Message summarizes the outcome
+	Message string `json:"message,omitempty"`
+}
+
+// NodeBaseline is the node configuration compared by the baseline_drift check
+type NodeBaseline struct {
+	// CapturedAt is when the baseline was recorded
+	CapturedAt metav1.Time `json:"capturedAt"`
+
+	// Recapture is the value of spec.baseline.recapture when the baseline was recorded
+	Recapture string `json:"recapture,omitempty"`
+
+	// KernelVersion is the running kernel release (uname -r)
+	KernelVersion string `json:"kernelVersion,omitempty"`
+
+	// Sysctls are the values of the kernel parameters of spec.baseline.sysctls
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+
+	// KernelModules are the loaded kernel modules, sorted
+	KernelModules []string `json:"kernelModules,omitempty"`
+
+	// Mounts maps the mount points of the node filesystems to their source and type
+	Mounts map[string]string `json:"mounts,omitempty"`
+
+	// NetworkInterfaces maps the physical and bond interfaces to their MTU and MAC address
+	NetworkInterfaces map[string]string `json:"networkInterfaces,omitempty"`
+}
+
+// NodeLifecycle holds the lifecycle events of a node and the values they are detected from
+type NodeLifecycle struct {
+	// BootID is the boot ID of the node at the last run; a new one means the node rebooted
+	BootID string `json:"bootID,omitempty"`
+
+	// KubeletVersion is the kubelet version of the node at the last run
+	KubeletVersion string `json:"kubeletVersion,omitempty"`
+
+	// MachineConfig is the current MachineConfig of the node at the last run (OpenShift)
+	MachineConfig string `json:"machineConfig,omitempty"`
+
+	// Events are the last spec.historySize lifecycle events, oldest first
+	Events []LifecycleEvent `json:"events,omitempty"`
+}
+
+// LifecycleEvent is a lifecycle event of a node
+type LifecycleEvent struct {
+	// Type of the event
+	// +kubebuilder:validation:Enum=Provisioned;Rebooted;KubeletUpgraded;MachineConfigUpdated;VerificationPassed;VerificationFailed
+	Type string `json:"type"`
+
+	// Timestamp is when the event happened (Provisioned) or was detected
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Message describes the event (e.g. "kubelet v1.27.6 -> v1.28.3")
+	Message string `json:"message,omitempty"`
+}
+
+// CheckHistory is the result history of a single check
+type CheckHistory struct {
+	// Name is the check name (e.g. "disk_smart", "node_conditions")
+	Name string `json:"name"`
+
+	// Transitions is the number of status changes within Entries
+	Transitions int `json:"transitions"`
+
+	// LastTransitionTime is when the check last changed status
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Entries are the most recent results, oldest first
+	Entries []CheckHistoryEntry `json:"entries,omitempty"`
+}
+
+// CheckHistoryEntry is a single past result of a check
+type CheckHistoryEntry struct {
+	// Timestamp when the check was performed
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Status of the check at that time
+	Status string `json:"status"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:resource:scope=Namespaced
+// +kubebuilder:resource:scope=Namespaced,shortName=nc
 // +kubebuilder:printcolumn:name="Node",type="string",JSONPath=".spec.nodeName"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.overallStatus"
 // +kubebuilder:printcolumn:name="Last Check",type="date",JSONPath=".status.lastCheckTime"
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.message"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 
 // NodeCheck is the Schema for the nodechecks API
 type NodeCheck struct {
@@ -325,8 +1051,98 @@ func (in *NodeCheckList) DeepCopyInto(out *NodeCheckList) {
 // DeepCopyInto copies all properties of this object into another object of the same type
 func (in *NodeCheckSpec) DeepCopyInto(out *NodeCheckSpec) {
 	*out = *in
+	if in.CategoryIntervals != nil {
+		out.CategoryIntervals = new(CategoryIntervals)
+		*out.CategoryIntervals = *in.CategoryIntervals
+	}
+	if in.Timeouts != nil {
+		out.Timeouts = in.Timeouts.DeepCopy()
+	}
 	in.SystemChecks.DeepCopyInto(&out.SystemChecks)
 	in.KubernetesChecks.DeepCopyInto(&out.KubernetesChecks)
+	if in.Suppressions != nil {
+		in, out := &in.Suppressions, &out.Suppressions
+		*out = make([]SuppressionWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExecutorConfig != nil {
+		out.ExecutorConfig = in.ExecutorConfig.DeepCopy()
+	}
+	if in.Baseline != nil {
+		out.Baseline = in.Baseline.DeepCopy()
+	}
+	if in.Expectations != nil {
+		out.Expectations = in.Expectations.DeepCopy()
+	}
+	if in.Filters != nil {
+		out.Filters = in.Filters.DeepCopy()
+	}
+	if in.EmitEvents != nil {
+		out.EmitEvents = new(EventsSpec)
+		*out.EmitEvents = *in.EmitEvents
+	}
+	if in.CloudEvents != nil {
+		out.CloudEvents = new(CloudEventsSpec)
+		*out.CloudEvents = *in.CloudEvents
+	}
+	if in.CheckWeights != nil {
+		out.CheckWeights = make(map[string]int, len(in.CheckWeights))
+		for key, val := range in.CheckWeights {
+			out.CheckWeights[key] = val
+		}
+	}
+	if in.Thresholds != nil {
+		out.Thresholds = make(map[string]CheckThresholds, len(in.Thresholds))
+		for key, val := range in.Thresholds {
+			out.Thresholds[key] = val
+		}
+	}
+	if in.TimeDrift != nil {
+		out.TimeDrift = new(TimeDriftThresholds)
+		*out.TimeDrift = *in.TimeDrift
+	}
+	if in.CertificateExpiry != nil {
+		out.CertificateExpiry = in.CertificateExpiry.DeepCopy()
+	}
+	if in.Sampling != nil {
+		out.Sampling = new(SamplingConfig)
+		in.Sampling.DeepCopyInto(out.Sampling)
+	}
+	if in.Smoothing != nil {
+		out.Smoothing = make(map[string]SmoothingConfig, len(in.Smoothing))
+		for key, val := range in.Smoothing {
+			out.Smoothing[key] = val
+		}
+	}
+	if in.ResultLabels != nil {
+		out.ResultLabels = make(map[string]string, len(in.ResultLabels))
+		for key, val := range in.ResultLabels {
+			out.ResultLabels[key] = val
+		}
+	}
+	if in.ResultAnnotations != nil {
+		out.ResultAnnotations = make(map[string]string, len(in.ResultAnnotations))
+		for key, val := range in.ResultAnnotations {
+			out.ResultAnnotations[key] = val
+		}
+	}
+	if in.CheckDependencies != nil {
+		out.CheckDependencies = make(map[string][]string, len(in.CheckDependencies))
+		for key, val := range in.CheckDependencies {
+			if val == nil {
+				out.CheckDependencies[key] = nil
+				continue
+			}
+			out.CheckDependencies[key] = make([]string, len(val))
+			copy(out.CheckDependencies[key], val)
+		}
+	}
+	if in.CheckOrder != nil {
+		out.CheckOrder = make([]string, len(in.CheckOrder))
+		copy(out.CheckOrder, in.CheckOrder)
+	}
 }
 
 // DeepCopy returns a deep copy of the NodeCheckSpec
@@ -339,7 +1155,365 @@ func (in *NodeCheckSpec) DeepCopy() *NodeCheckSpec {
 	return out
 }
 
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *CheckTimeouts) DeepCopyInto(out *CheckTimeouts) {
+	*out = *in
+	if in.Default != nil {
+		out.Default = new(metav1.Duration)
+		*out.Default = *in.Default
+	}
+	if in.Checks != nil {
+		out.Checks = make(map[string]metav1.Duration, len(in.Checks))
+		for key, val := range in.Checks {
+			out.Checks[key] = val
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the CheckTimeouts
+func (in *CheckTimeouts) DeepCopy() *CheckTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *CertificateExpiryConfig) DeepCopyInto(out *CertificateExpiryConfig) {
+	*out = *in
+	if in.Paths != nil {
+		out.Paths = make([]string, len(in.Paths))
+		copy(out.Paths, in.Paths)
+	}
+}
+
+// DeepCopy returns a deep copy of the CertificateExpiryConfig
+func (in *CertificateExpiryConfig) DeepCopy() *CertificateExpiryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateExpiryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *SamplingConfig) DeepCopyInto(out *SamplingConfig) {
+	*out = *in
+	if in.Interval != nil {
+		out.Interval = new(metav1.Duration)
+		*out.Interval = *in.Interval
+	}
+}
+
+// DeepCopy returns a deep copy of the SamplingConfig
+func (in *SamplingConfig) DeepCopy() *SamplingConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SamplingConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *SuppressionWindow) DeepCopyInto(out *SuppressionWindow) {
+	*out = *in
+	if in.Start != nil {
+		out.Start = in.Start.DeepCopy()
+	}
+	if in.End != nil {
+		out.End = in.End.DeepCopy()
+	}
+	if in.Checks != nil {
+		out.Checks = make([]string, len(in.Checks))
+		copy(out.Checks, in.Checks)
+	}
+}
+
+// DeepCopy returns a deep copy of the SuppressionWindow
+func (in *SuppressionWindow) DeepCopy() *SuppressionWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(SuppressionWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *ExpectedState) DeepCopyInto(out *ExpectedState) {
+	*out = *in
+	if in.RequiredKernelModules != nil {
+		out.RequiredKernelModules = make([]string, len(in.RequiredKernelModules))
+		copy(out.RequiredKernelModules, in.RequiredKernelModules)
+	}
+	if in.RequiredServices != nil {
+		out.RequiredServices = make([]string, len(in.RequiredServices))
+		copy(out.RequiredServices, in.RequiredServices)
+	}
+	if in.Sysctls != nil {
+		out.Sysctls = make(map[string]string, len(in.Sysctls))
+		for key, val := range in.Sysctls {
+			out.Sysctls[key] = val
+		}
+	}
+	if in.RequiredKernelParameters != nil {
+		out.RequiredKernelParameters = make([]string, len(in.RequiredKernelParameters))
+		copy(out.RequiredKernelParameters, in.RequiredKernelParameters)
+	}
+	if in.ForbiddenKernelParameters != nil {
+		out.ForbiddenKernelParameters = make([]string, len(in.ForbiddenKernelParameters))
+		copy(out.ForbiddenKernelParameters, in.ForbiddenKernelParameters)
+	}
+	if in.SRIOVVirtualFunctions != nil {
+		out.SRIOVVirtualFunctions = make(map[string]int, len(in.SRIOVVirtualFunctions))
+		for key, val := range in.SRIOVVirtualFunctions {
+			out.SRIOVVirtualFunctions[key] = val
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the ExpectedState
+func (in *ExpectedState) DeepCopy() *ExpectedState {
+	if in == nil {
+		return nil
+	}
+	out := new(ExpectedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *BaselineSpec) DeepCopyInto(out *BaselineSpec) {
+	*out = *in
+	if in.Sysctls != nil {
+		out.Sysctls = make([]string, len(in.Sysctls))
+		copy(out.Sysctls, in.Sysctls)
+	}
+}
 
+// DeepCopy returns a deep copy of the BaselineSpec
+func (in *BaselineSpec) DeepCopy() *BaselineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BaselineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeBaseline) DeepCopyInto(out *NodeBaseline) {
+	*out = *in
+	in.CapturedAt.DeepCopyInto(&out.CapturedAt)
+	if in.Sysctls != nil {
+		out.Sysctls = make(map[string]string, len(in.Sysctls))
+		for key, val := range in.Sysctls {
+			out.Sysctls[key] = val
+		}
+	}
+	if in.Mounts != nil {
+		out.Mounts = make(map[string]string, len(in.Mounts))
+		for key, val := range in.Mounts {
+			out.Mounts[key] = val
+		}
+	}
+	if in.NetworkInterfaces != nil {
+		out.NetworkInterfaces = make(map[string]string, len(in.NetworkInterfaces))
+		for key, val := range in.NetworkInterfaces {
+			out.NetworkInterfaces[key] = val
+		}
+	}
+	if in.KernelModules != nil {
+		out.KernelModules = make([]string, len(in.KernelModules))
+		copy(out.KernelModules, in.KernelModules)
+	}
+}
+
+// DeepCopy returns a deep copy of the NodeBaseline
+func (in *NodeBaseline) DeepCopy() *NodeBaseline {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeBaseline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *VerificationStatus) DeepCopyInto(out *VerificationStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		out.CompletionTime = in.CompletionTime.DeepCopy()
+	}
+	if in.FailedChecks != nil {
+		out.FailedChecks = make([]string, len(in.FailedChecks))
+		copy(out.FailedChecks, in.FailedChecks)
+	}
+}
+
+// DeepCopy returns a deep copy of the VerificationStatus
+func (in *VerificationStatus) DeepCopy() *VerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(VerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *TriggerStatus) DeepCopyInto(out *TriggerStatus) {
+	*out = *in
+	if in.Categories != nil {
+		out.Categories = make([]string, len(in.Categories))
+		copy(out.Categories, in.Categories)
+	}
+	in.LastRunTime.DeepCopyInto(&out.LastRunTime)
+}
+
+// DeepCopy returns a deep copy of the TriggerStatus
+func (in *TriggerStatus) DeepCopy() *TriggerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TriggerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *RunRecord) DeepCopyInto(out *RunRecord) {
+	*out = *in
+	if in.Categories != nil {
+		out.Categories = make([]string, len(in.Categories))
+		copy(out.Categories, in.Categories)
+	}
+	if in.Checks != nil {
+		out.Checks = make([]string, len(in.Checks))
+		copy(out.Checks, in.Checks)
+	}
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.CompletionTime != nil {
+		out.CompletionTime = in.CompletionTime.DeepCopy()
+	}
+}
+
+// DeepCopy returns a deep copy of the RunRecord
+func (in *RunRecord) DeepCopy() *RunRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(RunRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeLifecycle) DeepCopyInto(out *NodeLifecycle) {
+	*out = *in
+	if in.Events != nil {
+		out.Events = make([]LifecycleEvent, len(in.Events))
+		for i := range in.Events {
+			out.Events[i] = in.Events[i]
+			in.Events[i].Timestamp.DeepCopyInto(&out.Events[i].Timestamp)
+		}
+	}
+}
+
+// DeepCopy returns a deep copy of the NodeLifecycle
+func (in *NodeLifecycle) DeepCopy() *NodeLifecycle {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLifecycle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *CheckFilters) DeepCopyInto(out *CheckFilters) {
+	*out = *in
+	if in.MountPoints != nil {
+		out.MountPoints = in.MountPoints.DeepCopy()
+	}
+	if in.Devices != nil {
+		out.Devices = in.Devices.DeepCopy()
+	}
+	if in.Interfaces != nil {
+		out.Interfaces = in.Interfaces.DeepCopy()
+	}
+	if in.Namespaces != nil {
+		out.Namespaces = in.Namespaces.DeepCopy()
+	}
+}
+
+// DeepCopy returns a deep copy of the CheckFilters
+func (in *CheckFilters) DeepCopy() *CheckFilters {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckFilters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *ExecutorConfig) DeepCopyInto(out *ExecutorConfig) {
+	*out = *in
+	if in.Tolerations != nil {
+		out.Tolerations = make([]corev1.Toleration, len(in.Tolerations))
+		for i := range in.Tolerations {
+			in.Tolerations[i].DeepCopyInto(&out.Tolerations[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		out.NodeSelector = make(map[string]string, len(in.NodeSelector))
+		for key, val := range in.NodeSelector {
+			out.NodeSelector[key] = val
+		}
+	}
+	if in.Resources != nil {
+		out.Resources = in.Resources.DeepCopy()
+	}
+}
+
+// DeepCopy returns a deep copy of the ExecutorConfig
+func (in *ExecutorConfig) DeepCopy() *ExecutorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ExecutorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *FilterPatterns) DeepCopyInto(out *FilterPatterns) {
+	*out = *in
+	if in.Include != nil {
+		out.Include = make([]string, len(in.Include))
+		copy(out.Include, in.Include)
+	}
+	if in.Exclude != nil {
+		out.Exclude = make([]string, len(in.Exclude))
+		copy(out.Exclude, in.Exclude)
+	}
+}
+
+// DeepCopy returns a deep copy of the FilterPatterns
+func (in *FilterPatterns) DeepCopy() *FilterPatterns {
+	if in == nil {
+		return nil
+	}
+	out := new(FilterPatterns)
+	in.DeepCopyInto(out)
+	return out
+}
 
 // DeepCopyInto copies all properties of this object into another object of the same type
 func (in *SystemChecks) DeepCopyInto(out *SystemChecks) {
@@ -376,6 +1550,10 @@ func (in *DiskChecks) DeepCopy() *DiskChecks {
 // DeepCopyInto copies all properties of this object into another object of the same type
 func (in *NetworkChecks) DeepCopyInto(out *NetworkChecks) {
 	*out = *in
+	if in.EgressExpectedSourceIPs != nil {
+		out.EgressExpectedSourceIPs = make([]string, len(in.EgressExpectedSourceIPs))
+		copy(out.EgressExpectedSourceIPs, in.EgressExpectedSourceIPs)
+	}
 }
 
 // DeepCopy returns a deep copy of the NetworkChecks
@@ -391,6 +1569,10 @@ func (in *NetworkChecks) DeepCopy() *NetworkChecks {
 // DeepCopyInto copies all properties of this object into another object of the same type
 func (in *KubernetesChecks) DeepCopyInto(out *KubernetesChecks) {
 	*out = *in
+	if in.PVCProvisioningStorageClasses != nil {
+		out.PVCProvisioningStorageClasses = make([]string, len(in.PVCProvisioningStorageClasses))
+		copy(out.PVCProvisioningStorageClasses, in.PVCProvisioningStorageClasses)
+	}
 }
 
 // DeepCopy returns a deep copy of the KubernetesChecks
@@ -0,0 +1,538 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NodeCheckOperatorConfigName is the name of the NodeCheckOperatorConfig singleton read by the operator
+const NodeCheckOperatorConfigName = "cluster"
+
+// NodeCheckOperatorConfigSpec defines the desired configuration of the operator.
+// Fields left empty fall back to the environment variables of the operator Deployment
+// (WATCH_NAMESPACE, OPERATOR_IMAGE, CONSOLE_PLUGIN_IMAGE, ENABLE_OPENSHIFT_FEATURES,
+// ENABLE_NODEHEALTH_API, ENABLE_FAULT_INJECTION) and then to the built-in defaults.
+type NodeCheckOperatorConfigSpec struct {
+	// WatchNamespace is the namespace of the executor DaemonSet and of the console plugin resources
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	WatchNamespace string `json:"watchNamespace,omitempty"`
+
+	// OperatorImage is the image of the executor DaemonSet
+	OperatorImage string `json:"operatorImage,omitempty"`
+
+	// ConsolePluginImage is the image of the console plugin Deployment
+	ConsolePluginImage string `json:"consolePluginImage,omitempty"`
+
+	// DashboardPort is the port the dashboard API listens on (default 31682).
+	// The dashboard Service keeps exposing port 31682 and targets this port.
+	// +kubebuilder:validation:Minimum=1024
+	// +kubebuilder:validation:Maximum=65535
+	DashboardPort int `json:"dashboardPort,omitempty"`
+
+	// FeatureGates enable or disable optional components of the operator
+	FeatureGates *OperatorFeatureGates `json:"featureGates,omitempty"`
+
+	// Ticketing opens an issue in GitHub or Jira for every node and check that stays Critical, and
+	// closes it once the check is Healthy again. Disabled when unset.
+	Ticketing *TicketingConfig `json:"ticketing,omitempty"`
+
+	// RulePacks pulls rule packs (log patterns, threshold presets and known-issue signatures) published
+	// as OCI artifacts. Rule packs can also be provided as ConfigMaps labelled
+	// nodecheck.openshift.io/rule-pack=true in the operator namespace.
+	RulePacks *RulePacksConfig `json:"rulePacks,omitempty"`
+
+	// History selects where the operator keeps the check history served by the trend endpoints of the
+	// dashboard. When unset, the trend endpoints read status.history of the NodeChecks.
+	History *HistoryConfig `json:"history,omitempty"`
+
+	// ScaleDownProtection annotates the nodes under investigation with
+	// cluster-autoscaler.kubernetes.io/scale-down-disabled, so the cluster autoscaler does not remove
+	// them, and the evidence on them, mid-incident. Disabled when unset.
+	ScaleDownProtection *ScaleDownProtectionConfig `json:"scaleDownProtection,omitempty"`
+
+	// ResultWebhooks post the raw check results, as JSON, to HTTP endpoints (SIEM, CMDB, ...) as soon as
+	// the executors store them, so those systems do not have to poll the dashboard API
+	// +kubebuilder:validation:MaxItems=10
+	ResultWebhooks []ResultWebhookConfig `json:"resultWebhooks,omitempty"`
+
+	// KafkaExport streams every check result to a Kafka topic, for the data lakes collecting the
+	// infrastructure telemetry. Disabled when unset.
+	KafkaExport *KafkaExportConfig `json:"kafkaExport,omitempty"`
+
+	// Telemetry opts in to reporting anonymized fleet statistics (pass rates by check type, operator
+	// version, cluster size bucket) to the maintainers. Disabled when unset.
+	Telemetry *TelemetryConfig `json:"telemetry,omitempty"`
+
+	// UI brands and customizes the console plugin, served to it by /api/v1/uiconfig. Changes apply on
+	// the next page load, without rebuilding the plugin image.
+	UI *UIConfig `json:"ui,omitempty"`
+}
+
+// UIConfig configures the console plugin
+type UIConfig struct {
+	// Title is the title of the plugin page (default "Node Check")
+	// +kubebuilder:validation:MaxLength=100
+	Title string `json:"title,omitempty"`
+
+	// LogoURL is the logo shown next to the title, as an http(s) URL or a data:image/ URI
+	// +kubebuilder:validation:Pattern=`^(https?://|data:image/).+$`
+	LogoURL string `json:"logoURL,omitempty"`
+
+	// DefaultView is the view opened first (default overview)
+	// +kubebuilder:validation:Enum=overview;checks;nodes
+	DefaultView string `json:"defaultView,omitempty"`
+
+	// FeatureFlags enable or disable features of the plugin by name (checksView, nodesView, resourceLink,
+	// falsePositiveFeedback). Features are enabled unless disabled here; other names are passed through
+	// for custom builds.
+	FeatureFlags map[string]bool `json:"featureFlags,omitempty"`
+}
+
+// TelemetryConfig configures the anonymized statistics report. The report only holds counts: no names of
+// nodes, NodeChecks, namespaces or clusters, and no check messages or details.
+type TelemetryConfig struct {
+	// Endpoint is the URL the report is posted to, as JSON
+	// +kubebuilder:validation:Pattern=`^https://.+$`
+	Endpoint string `json:"endpoint"`
+
+	// Interval is how often the report is sent (default 24h)
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// KafkaExportConfig configures the topic the check results are streamed to. The records are produced
+// through the HTTP API of a Kafka bridge (Strimzi Kafka Bridge), keyed by node.
+type KafkaExportConfig struct {
+	// BridgeURL is the URL of the Kafka bridge, e.g. http://my-bridge-bridge-service.kafka.svc:8080
+	// +kubebuilder:validation:Pattern=`^https?://.+$`
+	BridgeURL string `json:"bridgeURL"`
+
+	// Topic is the Kafka topic the results are produced to
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9._-]+$`
+	Topic string `json:"topic"`
+
+	// Serialization of the record values: JSON (default) or Avro (binary encoding, schema in the README)
+	// +kubebuilder:validation:Enum=JSON;Avro
+	Serialization string `json:"serialization,omitempty"`
+
+	// SchemaID is the ID of the Avro schema in a Confluent Schema Registry. When set, the Avro values are
+	// framed in the wire format of the registry (magic byte and schema ID) for its deserializers.
+	// +kubebuilder:validation:Minimum=1
+	SchemaID int32 `json:"schemaID,omitempty"`
+
+	// CredentialsSecret is a Secret of the operator namespace whose "authorization" key is sent as the
+	// Authorization header to the bridge. No header when unset.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// ResultWebhookConfig is an endpoint the new check results of some check categories are posted to
+type ResultWebhookConfig struct {
+	// Name identifies the webhook in the logs and in the X-NodeCheck-Webhook header of the requests
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// URL is the endpoint the results are posted to
+	// +kubebuilder:validation:Pattern=`^https?://.+$`
+	URL string `json:"url"`
+
+	// Categories are the check categories whose results are posted (default: all of them)
+	// +kubebuilder:validation:items:Enum=system;disks;network;hardware;kubernetes
+	Categories []string `json:"categories,omitempty"`
+
+	// CredentialsSecret is a Secret of the operator namespace whose "authorization" key is sent as the
+	// Authorization header, e.g. "Bearer <token>" or "Splunk <HEC token>". No header when unset.
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+}
+
+// ScaleDownProtectionConfig configures the protection of the nodes under investigation from the
+// scale-down of the cluster autoscaler. A node is under investigation while one of its NodeChecks has a
+// Critical check, or while it carries the nodecheck.openshift.io/investigation annotation (an
+// acknowledgement set by whoever is looking into it).
+type ScaleDownProtectionConfig struct {
+	// HoldFor keeps a node protected for this long after its last Critical check recovered, to collect
+	// the evidence of the incident (default 1h). The investigation annotation protects the node until
+	// it is removed.
+	HoldFor *metav1.Duration `json:"holdFor,omitempty"`
+}
+
+// HistoryConfig configures the backend storing the check history beyond status.history
+type HistoryConfig struct {
+	// Backend is the history store: Status (status.history of the NodeChecks only), Memory (in the
+	// operator process, lost on restart), File (files under Path, e.g. on a PersistentVolumeClaim mounted
+	// in the operator Deployment), SQLite (a database under Path, likewise) or Prometheus (exported as the
+	// nodecheck_check_history_status metric and read back from PrometheusURL)
+	// +kubebuilder:validation:Enum=Status;Memory;File;SQLite;Prometheus
+	Backend string `json:"backend"`
+
+	// Retention is how long the Memory, File and SQLite backends keep the history (default 168h). The
+	// retention of the Prometheus backend is the one of Prometheus.
+	Retention *metav1.Duration `json:"retention,omitempty"`
+
+	// Path is the directory of the File and SQLite backends (default /var/lib/node-check-operator/history)
+	Path string `json:"path,omitempty"`
+
+	// PrometheusURL is the Prometheus API the Prometheus backend queries, e.g.
+	// https://thanos-querier.openshift-monitoring.svc:9091. Requests carry the token of the operator
+	// service account.
+	PrometheusURL string `json:"prometheusURL,omitempty"`
+}
+
+// RulePacksConfig configures the OCI artifacts the rule packs are pulled from
+type RulePacksConfig struct {
+	// Artifacts are the references of the OCI artifacts holding rule packs
+	// (e.g. quay.io/example/node-rules:v1). The operator pulls each of them into a ConfigMap of the
+	// operator namespace, read by the executors.
+	// +kubebuilder:validation:MaxItems=20
+	Artifacts []string `json:"artifacts,omitempty"`
+
+	// PullSecret is a kubernetes.io/dockerconfigjson Secret of the operator namespace with the
+	// credentials of the registries. Artifacts are pulled anonymously when unset.
+	PullSecret string `json:"pullSecret,omitempty"`
+
+	// RefreshInterval is how often the artifacts are checked for updates (default 1h)
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// TicketingConfig configures the issue tracker the persistent Critical findings are reported to
+type TicketingConfig struct {
+	// Provider is the issue tracker
+	// +kubebuilder:validation:Enum=GitHub;Jira
+	Provider string `json:"provider"`
+
+	// URL is the API URL of the tracker: the GitHub API (default https://api.github.com) or the
+	// base URL of the Jira instance (e.g. https://example.atlassian.net)
+	URL string `json:"url,omitempty"`
+
+	// Repository is the GitHub repository the issues are opened in, as "owner/name"
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`
+	Repository string `json:"repository,omitempty"`
+
+	// Project is the key of the Jira project the issues are opened in
+	Project string `json:"project,omitempty"`
+
+	// IssueType is the type of the Jira issues (default "Bug")
+	IssueType string `json:"issueType,omitempty"`
+
+	// CredentialsSecret is the Secret of the operator namespace holding the "token" key (GitHub token or
+	// Jira API token) and, for Jira Cloud, the "username" key (the e-mail of the token owner)
+	CredentialsSecret string `json:"credentialsSecret"`
+
+	// CriticalFor is how long a check must stay Critical before an issue is opened (default 30m)
+	CriticalFor *metav1.Duration `json:"criticalFor,omitempty"`
+
+	// ConsoleURL is the URL of the OpenShift console. Issues link to the NodeCheck in the console.
+	ConsoleURL string `json:"consoleURL,omitempty"`
+
+	// Labels are added to the issues, besides the node-check-operator label used to find them again
+	Labels []string `json:"labels,omitempty"`
+}
+
+// OperatorFeatureGates are the optional components of the operator. A gate left unset keeps the
+// value of its environment variable.
+type OperatorFeatureGates struct {
+	// OpenShiftFeatures enables the console plugin, the dashboard server and the
+	// ServiceMonitor/PrometheusRule resources
+	OpenShiftFeatures *bool `json:"openShiftFeatures,omitempty"`
+
+	// NodeHealthAPI enables the read-only nodehealth aggregated API server. The APIService
+	// registering it is installed separately (Helm nodeHealthAPI.enabled or config/nodehealth).
+	NodeHealthAPI *bool `json:"nodeHealthAPI,omitempty"`
+
+	// FaultInjection enables the dashboard endpoints injecting synthetic Warning/Critical results, and
+	// their application by the executors, to test the alerting pipelines. Never enable it in production.
+	FaultInjection *bool `json:"faultInjection,omitempty"`
+}
+
+// NodeCheckOperatorConfigStatus defines the observed state of NodeCheckOperatorConfig
+type NodeCheckOperatorConfigStatus struct {
+	// ObservedGeneration is the generation of the spec applied by the operator
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// WatchNamespace is the namespace in use, after the fallbacks are applied
+	WatchNamespace string `json:"watchNamespace,omitempty"`
+
+	// OperatorImage is the executor image in use
+	OperatorImage string `json:"operatorImage,omitempty"`
+
+	// ConsolePluginImage is the console plugin image in use
+	ConsolePluginImage string `json:"consolePluginImage,omitempty"`
+
+	// DashboardPort is the port the dashboard API listens on
+	DashboardPort int `json:"dashboardPort,omitempty"`
+
+	// OpenShiftFeatures reports whether the OpenShift integrations are enabled
+	OpenShiftFeatures bool `json:"openShiftFeatures,omitempty"`
+
+	// NodeHealthAPI reports whether the nodehealth aggregated API server is enabled
+	NodeHealthAPI bool `json:"nodeHealthAPI,omitempty"`
+
+	// FaultInjection reports whether synthetic results can be injected
+	FaultInjection bool `json:"faultInjection,omitempty"`
+
+	// Conditions report the state of the configuration. "Applied" is True once the operator
+	// runs with the current spec.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=ncoc
+// +kubebuilder:validation:XValidation:rule="self.metadata.name == 'cluster'",message="the NodeCheckOperatorConfig must be named cluster"
+// +kubebuilder:printcolumn:name="Namespace",type="string",JSONPath=".status.watchNamespace"
+// +kubebuilder:printcolumn:name="Applied",type="string",JSONPath=".status.conditions[?(@.type==\"Applied\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NodeCheckOperatorConfig is the Schema for the nodecheckoperatorconfigs API. The singleton named
+// "cluster" configures the operator; the controllers watch it and apply changes without a restart.
+type NodeCheckOperatorConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeCheckOperatorConfigSpec   `json:"spec,omitempty"`
+	Status NodeCheckOperatorConfigStatus `json:"status,omitempty"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *NodeCheckOperatorConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy returns a deep copy of the NodeCheckOperatorConfig
+func (in *NodeCheckOperatorConfig) DeepCopy() *NodeCheckOperatorConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCheckOperatorConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeCheckOperatorConfig) DeepCopyInto(out *NodeCheckOperatorConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// +kubebuilder:object:root=true
+
+// NodeCheckOperatorConfigList contains a list of NodeCheckOperatorConfig
+type NodeCheckOperatorConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeCheckOperatorConfig `json:"items"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *NodeCheckOperatorConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy returns a deep copy of the NodeCheckOperatorConfigList
+func (in *NodeCheckOperatorConfigList) DeepCopy() *NodeCheckOperatorConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCheckOperatorConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeCheckOperatorConfigList) DeepCopyInto(out *NodeCheckOperatorConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeCheckOperatorConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeCheckOperatorConfigSpec) DeepCopyInto(out *NodeCheckOperatorConfigSpec) {
+	*out = *in
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = new(OperatorFeatureGates)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ticketing != nil {
+		in, out := &in.Ticketing, &out.Ticketing
+		*out = new(TicketingConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RulePacks != nil {
+		in, out := &in.RulePacks, &out.RulePacks
+		*out = new(RulePacksConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = new(HistoryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScaleDownProtection != nil {
+		in, out := &in.ScaleDownProtection, &out.ScaleDownProtection
+		*out = new(ScaleDownProtectionConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KafkaExport != nil {
+		in, out := &in.KafkaExport, &out.KafkaExport
+		*out = new(KafkaExportConfig)
+		**out = **in
+	}
+	if in.Telemetry != nil {
+		in, out := &in.Telemetry, &out.Telemetry
+		*out = new(TelemetryConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UI != nil {
+		in, out := &in.UI, &out.UI
+		*out = new(UIConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResultWebhooks != nil {
+		in, out := &in.ResultWebhooks, &out.ResultWebhooks
+		*out = make([]ResultWebhookConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *ResultWebhookConfig) DeepCopyInto(out *ResultWebhookConfig) {
+	*out = *in
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy returns a deep copy of the UIConfig
+func (in *UIConfig) DeepCopy() *UIConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(UIConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *UIConfig) DeepCopyInto(out *UIConfig) {
+	*out = *in
+	if in.FeatureFlags != nil {
+		in, out := &in.FeatureFlags, &out.FeatureFlags
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *TelemetryConfig) DeepCopyInto(out *TelemetryConfig) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *ScaleDownProtectionConfig) DeepCopyInto(out *ScaleDownProtectionConfig) {
+	*out = *in
+	if in.HoldFor != nil {
+		in, out := &in.HoldFor, &out.HoldFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *TicketingConfig) DeepCopyInto(out *TicketingConfig) {
+	*out = *in
+	if in.CriticalFor != nil {
+		in, out := &in.CriticalFor, &out.CriticalFor
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *RulePacksConfig) DeepCopyInto(out *RulePacksConfig) {
+	*out = *in
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *HistoryConfig) DeepCopyInto(out *HistoryConfig) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *OperatorFeatureGates) DeepCopyInto(out *OperatorFeatureGates) {
+	*out = *in
+	if in.OpenShiftFeatures != nil {
+		in, out := &in.OpenShiftFeatures, &out.OpenShiftFeatures
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NodeHealthAPI != nil {
+		in, out := &in.NodeHealthAPI, &out.NodeHealthAPI
+		*out = new(bool)
+		**out = **in
+	}
+	if in.FaultInjection != nil {
+		in, out := &in.FaultInjection, &out.FaultInjection
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeCheckOperatorConfigStatus) DeepCopyInto(out *NodeCheckOperatorConfigStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NodeCheckTemplateSpec defines the desired state of NodeCheckTemplate
+type NodeCheckTemplateSpec struct {
+	// Template is the NodeCheck spec shared by all the pools. Its nodeName is ignored: every pool
+	// gets a NodeCheck with nodeName "*" restricted to the nodes of the pool.
+	Template NodeCheckSpec `json:"template,omitempty"`
+
+	// Pools selects groups of nodes and the overrides applied to the template for them,
+	// e.g. etcd and ClusterOperator checks for masters, disk and GPU checks for workers.
+	// A node selected by several pools is checked by each of them.
+	// +listType=map
+	// +listMapKey=name
+	Pools []NodePool `json:"pools"`
+}
+
+// NodePool is a group of nodes of a NodeCheckTemplate with its overrides.
+// The selectors of a pool are combined: a node must match all of them.
+type NodePool struct {
+	// Name identifies the pool; the NodeCheck of the pool is named <template>-<name>
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	Name string `json:"name"`
+
+	// NodeRole selects the nodes with the node-role.kubernetes.io/<role> label (e.g. "master", "worker")
+	NodeRole string `json:"nodeRole,omitempty"`
+
+	// MachineConfigPool selects the nodes of an OpenShift MachineConfigPool through its spec.nodeSelector.
+	// Only the matchLabels of the pool's selector are supported.
+	MachineConfigPool string `json:"machineConfigPool,omitempty"`
+
+	// NodeSelector selects the nodes with all the given labels
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Overrides are merged into the template for the nodes of the pool: the fields set here replace
+	// the template's ones, maps are merged key by key and checks enabled here are added to the
+	// checks enabled by the template.
+	Overrides NodeCheckSpec `json:"overrides,omitempty"`
+}
+
+// NodeCheckTemplateStatus defines the observed state of NodeCheckTemplate
+type NodeCheckTemplateStatus struct {
+	// Pools reports the NodeCheck generated for each pool and the nodes it currently selects
+	Pools []NodePoolStatus `json:"pools,omitempty"`
+
+	// Conditions report the state of the template. "Ready" is False while a pool cannot be
+	// resolved (e.g. its MachineConfigPool does not exist) or its NodeCheck cannot be updated.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// NodePoolStatus is the observed state of a pool of a NodeCheckTemplate
+type NodePoolStatus struct {
+	// Name is the name of the pool
+	Name string `json:"name"`
+
+	// NodeCheck is the name of the NodeCheck generated for the pool
+	NodeCheck string `json:"nodeCheck,omitempty"`
+
+	// NodeSelector is the resolved label selector of the pool
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Nodes is the number of nodes currently selected by the pool
+	Nodes int `json:"nodes"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Namespaced,shortName=nct
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// NodeCheckTemplate is the Schema for the nodechecktemplates API. It generates one NodeCheck per
+// node pool, so each pool gets its own checks from a single declarative object.
+type NodeCheckTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NodeCheckTemplateSpec   `json:"spec,omitempty"`
+	Status NodeCheckTemplateStatus `json:"status,omitempty"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *NodeCheckTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy returns a deep copy of the NodeCheckTemplate
+func (in *NodeCheckTemplate) DeepCopy() *NodeCheckTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCheckTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeCheckTemplate) DeepCopyInto(out *NodeCheckTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// +kubebuilder:object:root=true
+
+// NodeCheckTemplateList contains a list of NodeCheckTemplate
+type NodeCheckTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NodeCheckTemplate `json:"items"`
+}
+
+// DeepCopyObject returns a generically typed copy of an object
+func (in *NodeCheckTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopy returns a deep copy of the NodeCheckTemplateList
+func (in *NodeCheckTemplateList) DeepCopy() *NodeCheckTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCheckTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeCheckTemplateList) DeepCopyInto(out *NodeCheckTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NodeCheckTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeCheckTemplateSpec) DeepCopyInto(out *NodeCheckTemplateSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]NodePool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodePool) DeepCopyInto(out *NodePool) {
+	*out = *in
+	if in.NodeSelector != nil {
+		out.NodeSelector = make(map[string]string, len(in.NodeSelector))
+		for key, value := range in.NodeSelector {
+			out.NodeSelector[key] = value
+		}
+	}
+	in.Overrides.DeepCopyInto(&out.Overrides)
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodeCheckTemplateStatus) DeepCopyInto(out *NodeCheckTemplateStatus) {
+	*out = *in
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]NodePoolStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopyInto copies all properties of this object into another object of the same type
+func (in *NodePoolStatus) DeepCopyInto(out *NodePoolStatus) {
+	*out = *in
+	if in.NodeSelector != nil {
+		out.NodeSelector = make(map[string]string, len(in.NodeSelector))
+		for key, value := range in.NodeSelector {
+			out.NodeSelector[key] = value
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Numeric details are stored in base units with the unit as suffix of the key: sizes in bytes
+// ("<name>_bytes"), durations in seconds or milliseconds ("<name>_seconds", "<name>_ms"), CPU in
+// millicores ("<name>_millicores") and ratios in percent ("<name>_percent"). Keys without a suffix
+// that hold human readable strings (e.g. "size": "8.2G") are kept for older clients.
+
+// sizeMultipliers are the multipliers of the size suffixes, upper-cased. The tools printing sizes
+// (free -h, df -h, lvs --units g) use binary units even without the "i".
+var sizeMultipliers = map[string]float64{
+	"": 1, "B": 1,
+	"K": 1 << 10, "KI": 1 << 10, "KB": 1 << 10, "KIB": 1 << 10,
+	"M": 1 << 20, "MI": 1 << 20, "MB": 1 << 20, "MIB": 1 << 20,
+	"G": 1 << 30, "GI": 1 << 30, "GB": 1 << 30, "GIB": 1 << 30,
+	"T": 1 << 40, "TI": 1 << 40, "TB": 1 << 40, "TIB": 1 << 40,
+	"P": 1 << 50, "PI": 1 << 50, "PB": 1 << 50, "PIB": 1 << 50,
+}
+
+// ParseSize parses a size printed by free -h, df -h or lvs (e.g. "8.2Gi", "1.5G", "<10.00g",
+// "512K", "4096") to bytes. A decimal comma, as printed under some locales, is accepted.
+func ParseSize(size string) (int64, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(size), "<")
+	end := 0
+	for end < len(value) && (value[end] >= '0' && value[end] <= '9' || value[end] == '.' || value[end] == ',') {
+		end++
+	}
+	number, err := strconv.ParseFloat(strings.Replace(value[:end], ",", ".", 1), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", size)
+	}
+	multiplier, ok := sizeMultipliers[strings.ToUpper(strings.TrimSpace(value[end:]))]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit", size)
+	}
+	return int64(number * multiplier), nil
+}
+
+// byteUnits are the binary units of FormatBytes
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+
+// FormatBytes formats a size in bytes with a binary unit and one decimal (e.g. "8.2 GiB")
+func FormatBytes(bytes float64) string {
+	unit := 0
+	for (bytes >= 1024 || bytes <= -1024) && unit < len(byteUnits)-1 {
+		bytes /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%.0f B", bytes)
+	}
+	return fmt.Sprintf("%.1f %s", bytes, byteUnits[unit])
+}
+
+// FormatSeconds formats a duration in seconds with its two largest units (e.g. "3d 4h", "2m 5s",
+// "1.5s", "250ms")
+func FormatSeconds(seconds float64) string {
+	switch {
+	case seconds < 1:
+		return fmt.Sprintf("%.0fms", seconds*1000)
+	case seconds < 60:
+		return fmt.Sprintf("%.1fs", seconds)
+	}
+	total := int64(seconds)
+	days, hours, minutes := total/86400, total%86400/3600, total%3600/60
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	default:
+		return fmt.Sprintf("%dm %ds", minutes, total%60)
+	}
+}
@@ -0,0 +1,201 @@
+// Command checkreplay runs the checks against canned host command outputs, so their parsers can be
+// verified against the output of different tool versions (iostat, df, smartctl, ...) without a node.
+// Each fixture (see pkg/checks/hostfake.LoadFixture) names a check, the outputs of its host commands
+// and the status it must report:
+//
+//	go run ./cmd/checkreplay pkg/checks/hostfake/testdata
+//	go run ./cmd/checkreplay -v pkg/checks/hostfake/testdata/iostat-sysstat-11.fixture
+//
+// Only the host commands are canned: a command without output fails, and the check takes its
+// fallback path (which may run the command in the local container). The native /proc collection of
+// the checks is disabled, so the checks reading /proc run their command fallback against the fixture. The Kubernetes checks need a
+// cluster and are not supported.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/checks"
+	"github.com/albertofilice/node-check-operator/pkg/checks/hostfake"
+)
+
+// checkFunc is the signature of the check methods of the checkers
+type checkFunc = func(context.Context) *nodecheckv1alpha1.CheckResult
+
+func main() {
+	verbose := flag.Bool("v", false, "print the details of the results and the commands run")
+	nodeName := flag.String("node", "replay-node", "node name given to the checkers")
+	timeout := flag.Duration("timeout", time.Minute, "timeout of each check")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <fixture or directory>...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	paths, err := fixturePaths(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	failed := 0
+	for _, path := range paths {
+		if !replay(path, *nodeName, *timeout, *verbose) {
+			failed++
+		}
+	}
+	fmt.Printf("%d fixtures, %d failed\n", len(paths), failed)
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// fixturePaths expands the directories of the arguments to their *.fixture files
+func fixturePaths(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(arg, "*.fixture"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// replay runs the check of a fixture and reports whether it returned the expected status
+func replay(path, nodeName string, timeout time.Duration, verbose bool) bool {
+	fixture, err := hostfake.LoadFixture(path)
+	if err != nil {
+		fmt.Printf("FAIL %s: %v\n", path, err)
+		return false
+	}
+	result, runner, err := runFixture(fixture, nodeName, timeout)
+	if err != nil {
+		fmt.Printf("FAIL %s: %v\n", path, err)
+		return false
+	}
+
+	passed := fixture.Expect == "" || result.Status == fixture.Expect
+	verdict := "PASS"
+	if !passed {
+		verdict = "FAIL"
+	}
+	description := ""
+	if fixture.Description != "" {
+		description = " (" + fixture.Description + ")"
+	}
+	fmt.Printf("%s %s %s%s: %s: %s\n", verdict, filepath.Base(path), fixture.Check, description, result.Status, result.Message)
+	if !passed {
+		fmt.Printf("     expected %s\n", fixture.Expect)
+	}
+	if unmatched := runner.Unmatched(); len(unmatched) > 0 {
+		fmt.Printf("     commands without output: %s\n", strings.Join(unmatched, "; "))
+	}
+	if verbose {
+		fmt.Printf("     commands: %s\n", strings.Join(runner.Calls(), "; "))
+		if details, err := result.DetailsMap(); err == nil && details != nil {
+			data, _ := json.MarshalIndent(details, "     ", "  ")
+			fmt.Printf("     details: %s\n", data)
+		}
+	}
+	return passed
+}
+
+// runFixture runs the check of a fixture against its canned outputs, with the expectations and the
+// sampling of its headers. It returns the result and the runner, which recorded the commands run.
+func runFixture(fixture *hostfake.Fixture, nodeName string, timeout time.Duration) (*nodecheckv1alpha1.CheckResult, *hostfake.Runner, error) {
+	var expectations *nodecheckv1alpha1.ExpectedState
+	if fixture.Expectations != "" {
+		expectations = &nodecheckv1alpha1.ExpectedState{}
+		if err := json.Unmarshal([]byte(fixture.Expectations), expectations); err != nil {
+			return nil, nil, fmt.Errorf("invalid expectations header: %w", err)
+		}
+	}
+	var sampling *nodecheckv1alpha1.SamplingConfig
+	if fixture.Sampling != "" {
+		sampling = &nodecheckv1alpha1.SamplingConfig{}
+		if err := json.Unmarshal([]byte(fixture.Sampling), sampling); err != nil {
+			return nil, nil, fmt.Errorf("invalid sampling header: %w", err)
+		}
+	}
+	check, err := findCheck(fixture.Check, nodeName, expectations, sampling)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	runner := fixture.Runner()
+	previous := checks.SetHostRunner(runner)
+	defer checks.SetHostRunner(previous)
+	previousProcRoot := checks.SetProcRoot("")
+	defer checks.SetProcRoot(previousProcRoot)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return check(ctx), runner, nil
+}
+
+// findCheck returns the check method of a check name (e.g. "disk_performance" is CheckDiskPerformance
+// of the DiskChecker, "ntp_sync" CheckNTPSync of the SystemChecker, "gpu" CheckGPU of the GPUChecker).
+// The SystemChecker gets the expectations of the fixture, the SystemChecker and DiskChecker its sampling.
+func findCheck(name, nodeName string, expectations *nodecheckv1alpha1.ExpectedState, sampling *nodecheckv1alpha1.SamplingConfig) (checkFunc, error) {
+	systemChecker := checks.NewSystemChecker(nodeName)
+	systemChecker.SetExpectations(expectations)
+	systemChecker.SetSampling(sampling)
+	diskChecker := checks.NewDiskChecker(nodeName)
+	diskChecker.SetSampling(sampling)
+	var checker interface{} = systemChecker
+	candidates := []string{name}
+	for prefix, prefixed := range map[string]interface{}{
+		"disk_":     diskChecker,
+		"hardware_": checks.NewHardwareChecker(nodeName),
+		"network_":  checks.NewNetworkChecker(nodeName),
+		"gpu":       checks.NewGPUChecker(nodeName),
+	} {
+		if strings.HasPrefix(name, prefix) {
+			checker = prefixed
+			candidates = append(candidates, strings.TrimPrefix(name, prefix))
+		}
+	}
+
+	value := reflect.ValueOf(checker)
+	for i := 0; i < value.NumMethod(); i++ {
+		method := value.Type().Method(i)
+		normalized := strings.ToLower(strings.TrimPrefix(method.Name, "Check"))
+		if !strings.HasPrefix(method.Name, "Check") {
+			continue
+		}
+		for _, candidate := range candidates {
+			if strings.ReplaceAll(candidate, "_", "") != normalized {
+				continue
+			}
+			if check, ok := value.Method(i).Interface().(checkFunc); ok {
+				return check, nil
+			}
+			return nil, fmt.Errorf("check %s needs more than a context and cannot be replayed", name)
+		}
+	}
+	return nil, fmt.Errorf("unknown check %s", name)
+}
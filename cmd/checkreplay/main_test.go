@@ -0,0 +1,47 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/pkg/checks/hostfake"
+)
+
+// fixtureDir holds the fixtures replayed by make replay
+var fixtureDir = filepath.Join("..", "..", "pkg", "checks", "hostfake", "testdata")
+
+// TestFixtures runs the check of every fixture against its canned outputs and compares the status with
+// its expect header, as make replay does. The checks share the host runner, so they run one at a time.
+func TestFixtures(t *testing.T) {
+	paths, err := fixturePaths([]string{fixtureDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no fixture in %s", fixtureDir)
+	}
+	for _, path := range paths {
+		path := path
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".fixture"), func(t *testing.T) {
+			fixture, err := hostfake.LoadFixture(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if fixture.Expect == "" {
+				t.Fatalf("%s has no expect header", path)
+			}
+			result, runner, err := runFixture(fixture, "replay-node", time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Status != fixture.Expect {
+				t.Errorf("%s returned %s (%s), want %s", fixture.Check, result.Status, result.Message, fixture.Expect)
+			}
+			if unmatched := runner.Unmatched(); len(unmatched) > 0 {
+				t.Logf("commands without output: %s", strings.Join(unmatched, "; "))
+			}
+		})
+	}
+}
@@ -0,0 +1,593 @@
+// Command loadgen is a load-test harness for large fleets: it creates synthetic NodeChecks with realistic
+// status payloads, keeps updating their status at a given rate as the executors would, and measures the
+// reconcile throughput and memory of the operator and the latency of the dashboard API meanwhile.
+//
+// The NodeChecks target nodes that do not exist (loadgen-node-NNNN) and enable no check, so no executor
+// runs them. Run it against a test cluster with port-forwards to the operator metrics and dashboard:
+//
+//	kubectl port-forward -n node-check-operator-system deployment/node-check-operator-controller-manager 31680 &
+//	kubectl port-forward -n node-check-operator-system svc/node-check-operator-dashboard 31682 &
+//	go run ./cmd/loadgen -nodechecks 500 -duration 5m -token "$(oc whoami -t)"
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// loadgenLabel marks the NodeChecks created by loadgen, so they can be cleaned up
+const loadgenLabel = "nodecheck.openshift.io/loadgen"
+
+// options are the command line flags
+type options struct {
+	nodeChecks       int
+	namespace        string
+	prefix           string
+	duration         time.Duration
+	updatesPerSecond float64
+	historySize      int
+	dashboardURL     string
+	token            string
+	insecure         bool
+	concurrency      int
+	metricsURL       string
+	cleanup          bool
+	cleanupOnly      bool
+	jsonReport       bool
+}
+
+// Report is the result of a run
+type Report struct {
+	NodeChecks      int                       `json:"nodeChecks"`
+	Duration        string                    `json:"duration"`
+	StatusUpdates   int64                     `json:"statusUpdates"`
+	UpdateErrors    int64                     `json:"updateErrors"`
+	Reconciles      map[string]float64        `json:"reconcilesPerSecond,omitempty"`
+	ReconcileErrors map[string]float64        `json:"reconcileErrors,omitempty"`
+	Memory          *MemoryReport             `json:"memory,omitempty"`
+	Dashboard       map[string]*LatencyReport `json:"dashboard,omitempty"`
+}
+
+// MemoryReport is the memory of the operator sampled during the run
+type MemoryReport struct {
+	MaxRSSBytes       float64 `json:"maxRSSBytes"`
+	FinalRSSBytes     float64 `json:"finalRSSBytes"`
+	MaxHeapAllocBytes float64 `json:"maxHeapAllocBytes"`
+}
+
+// LatencyReport is the latency of a dashboard endpoint, in milliseconds
+type LatencyReport struct {
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"`
+	P50      float64 `json:"p50Ms"`
+	P95      float64 `json:"p95Ms"`
+	P99      float64 `json:"p99Ms"`
+	Max      float64 `json:"maxMs"`
+
+	samples []time.Duration
+}
+
+func main() {
+	var opts options
+	flag.IntVar(&opts.nodeChecks, "nodechecks", 500, "Number of synthetic NodeChecks to create")
+	flag.StringVar(&opts.namespace, "namespace", "node-check-operator-system", "Namespace of the synthetic NodeChecks")
+	flag.StringVar(&opts.prefix, "prefix", "loadgen", "Name prefix of the synthetic NodeChecks")
+	flag.DurationVar(&opts.duration, "duration", 5*time.Minute, "How long the status updates and the measures run")
+	flag.Float64Var(&opts.updatesPerSecond, "updates-per-second", 20, "Status updates per second across all NodeChecks")
+	flag.IntVar(&opts.historySize, "history-size", 10, "spec.historySize of the synthetic NodeChecks")
+	flag.StringVar(&opts.dashboardURL, "dashboard-url", "https://localhost:31682", "URL of the dashboard API, empty to skip the latency measures")
+	flag.StringVar(&opts.token, "token", os.Getenv("TOKEN"), "Bearer token for the /api/v2 endpoints (default $TOKEN); v2 is skipped when empty")
+	flag.BoolVar(&opts.insecure, "insecure", true, "Skip the TLS verification of the dashboard (self-signed service certificate behind a port-forward)")
+	flag.IntVar(&opts.concurrency, "concurrency", 4, "Concurrent dashboard clients")
+	flag.StringVar(&opts.metricsURL, "metrics-url", "http://localhost:31680/metrics", "URL of the operator metrics, empty to skip the reconcile and memory measures")
+	flag.BoolVar(&opts.cleanup, "cleanup", true, "Delete the synthetic NodeChecks at the end of the run")
+	flag.BoolVar(&opts.cleanupOnly, "cleanup-only", false, "Only delete the synthetic NodeChecks left by a previous run")
+	flag.BoolVar(&opts.jsonReport, "json", false, "Print the report as JSON")
+	flag.Parse()
+	if opts.nodeChecks <= 0 && !opts.cleanupOnly {
+		fmt.Fprintln(os.Stderr, "-nodechecks must be greater than 0")
+		os.Exit(2)
+	}
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(nodecheckv1alpha1.AddToScheme(scheme))
+	k8sClient, err := client.New(ctrl.GetConfigOrDie(), client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to create the Kubernetes client: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if opts.cleanupOnly {
+		if err := cleanup(context.Background(), k8sClient, opts.namespace); err != nil {
+			fmt.Fprintf(os.Stderr, "Cleanup failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	report, err := run(ctx, k8sClient, opts)
+	if opts.cleanup {
+		// The cleanup also runs after an interrupt
+		if err := cleanup(context.Background(), k8sClient, opts.namespace); err != nil {
+			fmt.Fprintf(os.Stderr, "Cleanup failed: %v\n", err)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Load test failed: %v\n", err)
+		os.Exit(1)
+	}
+	if opts.jsonReport {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+		return
+	}
+	printReport(report)
+}
+
+// run creates the NodeChecks, then updates their status while measuring the operator and the dashboard
+func run(ctx context.Context, k8sClient client.Client, opts options) (*Report, error) {
+	fmt.Fprintf(os.Stderr, "Creating %d NodeChecks in %s...\n", opts.nodeChecks, opts.namespace)
+	nodeChecks, err := createNodeChecks(ctx, k8sClient, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{NodeChecks: len(nodeChecks), Duration: opts.duration.String()}
+	before, err := scrapeMetrics(ctx, opts.metricsURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to read the operator metrics, skipping the reconcile and memory measures: %v\n", err)
+		opts.metricsURL = ""
+	}
+
+	runCtx, stop := context.WithTimeout(ctx, opts.duration)
+	defer stop()
+	started := time.Now()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		report.StatusUpdates, report.UpdateErrors = updateStatuses(runCtx, k8sClient, nodeChecks, opts)
+	}()
+	if opts.dashboardURL != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Dashboard = measureDashboard(runCtx, opts, nodeChecks)
+		}()
+	}
+	if opts.metricsURL != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Memory = sampleMemory(runCtx, opts.metricsURL)
+		}()
+	}
+	fmt.Fprintf(os.Stderr, "Running for %s...\n", opts.duration)
+	wg.Wait()
+	elapsed := time.Since(started).Seconds()
+
+	if opts.metricsURL != "" {
+		// The run may have been interrupted
+		after, err := scrapeMetrics(context.Background(), opts.metricsURL)
+		if err != nil {
+			return report, fmt.Errorf("reading the operator metrics after the run: %w", err)
+		}
+		report.Reconciles = counterRates(before, after, "controller_runtime_reconcile_total", elapsed)
+		report.ReconcileErrors = counterRates(before, after, "controller_runtime_reconcile_errors_total", elapsed)
+	}
+	return report, nil
+}
+
+// createNodeChecks creates the synthetic NodeChecks with an initial status
+func createNodeChecks(ctx context.Context, k8sClient client.Client, opts options) ([]*nodecheckv1alpha1.NodeCheck, error) {
+	nodeChecks := make([]*nodecheckv1alpha1.NodeCheck, 0, opts.nodeChecks)
+	for i := 0; i < opts.nodeChecks; i++ {
+		nodeName := fmt.Sprintf("%s-node-%04d", opts.prefix, i)
+		nodeCheck := &nodecheckv1alpha1.NodeCheck{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      nodeName,
+				Namespace: opts.namespace,
+				Labels:    map[string]string{loadgenLabel: "true"},
+			},
+			Spec: nodecheckv1alpha1.NodeCheckSpec{
+				NodeName:    nodeName,
+				HistorySize: opts.historySize,
+			},
+		}
+		if err := k8sClient.Create(ctx, nodeCheck); err != nil {
+			if !errors.IsAlreadyExists(err) {
+				return nodeChecks, fmt.Errorf("creating NodeCheck %s: %w", nodeName, err)
+			}
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(nodeCheck), nodeCheck); err != nil {
+				return nodeChecks, err
+			}
+		}
+		nodeCheck.Status = syntheticStatus(nodeName, nil, opts.historySize)
+		if err := k8sClient.Status().Update(ctx, nodeCheck); err != nil {
+			return nodeChecks, fmt.Errorf("setting the status of NodeCheck %s: %w", nodeName, err)
+		}
+		nodeChecks = append(nodeChecks, nodeCheck)
+	}
+	return nodeChecks, nil
+}
+
+// updateStatuses rewrites the status of random NodeChecks at opts.updatesPerSecond until ctx is done
+func updateStatuses(ctx context.Context, k8sClient client.Client, nodeChecks []*nodecheckv1alpha1.NodeCheck, opts options) (updates, failures int64) {
+	if opts.updatesPerSecond <= 0 || len(nodeChecks) == 0 {
+		<-ctx.Done()
+		return 0, 0
+	}
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.updatesPerSecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return updates, failures
+		case <-ticker.C:
+		}
+		nodeCheck := nodeChecks[rand.Intn(len(nodeChecks))]
+		nodeCheck.Status = syntheticStatus(nodeCheck.Spec.NodeName, nodeCheck.Status.History, opts.historySize)
+		if err := k8sClient.Status().Update(ctx, nodeCheck); err != nil {
+			if ctx.Err() != nil {
+				return updates, failures
+			}
+			failures++
+			// A conflict leaves the local copy stale
+			k8sClient.Get(ctx, client.ObjectKeyFromObject(nodeCheck), nodeCheck)
+			continue
+		}
+		updates++
+	}
+}
+
+// syntheticChecks are the checks of the synthetic status
+var syntheticChecks = []string{"uptime", "memory", "processes", "file_descriptors", "disk_space", "conntrack", "inotify", "node_status", "kubelet_health", "node_conditions"}
+
+// randomStatus returns Healthy, Warning or Critical with the proportions of a mostly healthy fleet
+func randomStatus() string {
+	switch n := rand.Intn(100); {
+	case n < 5:
+		return "Critical"
+	case n < 15:
+		return "Warning"
+	default:
+		return "Healthy"
+	}
+}
+
+// syntheticStatus returns a status with the size and shape of the one of a real node: check results with
+// details, the overall status and the history of the previous status appended with the new results
+func syntheticStatus(nodeName string, previous []nodecheckv1alpha1.CheckHistory, historySize int) nodecheckv1alpha1.NodeCheckStatus {
+	now := metav1.Now()
+	statuses := make(map[string]string, len(syntheticChecks))
+	overall := "Healthy"
+	for _, name := range syntheticChecks {
+		status := randomStatus()
+		statuses[name] = status
+		if status == "Critical" || (status == "Warning" && overall == "Healthy") {
+			overall = status
+		}
+	}
+	result := func(name, message string, details map[string]interface{}) *nodecheckv1alpha1.CheckResult {
+		data, _ := json.Marshal(details)
+		return &nodecheckv1alpha1.CheckResult{
+			Status:    statuses[name],
+			Message:   message,
+			Timestamp: now,
+			Details:   runtime.RawExtension{Raw: data},
+		}
+	}
+
+	memoryPercent := 40 + rand.Float64()*55
+	load := rand.Float64() * 32
+	var filesystems []map[string]interface{}
+	for _, mount := range []string{"/", "/boot", "/var", "/var/lib/containers", "/var/log"} {
+		filesystems = append(filesystems, map[string]interface{}{
+			"filesystem": "/dev/mapper/vg0-" + strings.Trim(strings.ReplaceAll(mount, "/", "_"), "_"),
+			"mount":      mount,
+			"size":       "120G",
+			"used":       fmt.Sprintf("%dG", rand.Intn(120)),
+			"use":        fmt.Sprintf("%d%%", rand.Intn(100)),
+		})
+	}
+
+	status := nodecheckv1alpha1.NodeCheckStatus{
+		NodeName:      nodeName,
+		OverallStatus: overall,
+		Message:       fmt.Sprintf("Synthetic status of %s", nodeName),
+		LastCheckTime: now,
+	}
+	system := &status.CheckResults.SystemResults
+	system.Uptime = result("uptime", fmt.Sprintf("Load average %.2f", load), map[string]interface{}{
+		"check_source": "proc_loadavg", "cpu_cores": 32, "load_1min": load, "load_5min": load * 0.9, "load_15min": load * 0.8,
+	})
+	system.Memory = result("memory", fmt.Sprintf("Memory usage %.1f%%", memoryPercent), map[string]interface{}{
+		"check_source": "proc_meminfo", "memory_usage_percent": memoryPercent, "total_memory_bytes": int64(256) << 30,
+		"available_memory_bytes": int64((100 - memoryPercent) / 100 * float64(int64(256)<<30)),
+	})
+	system.Processes = result("processes", fmt.Sprintf("%d processes", 400+rand.Intn(800)), map[string]interface{}{
+		"total_processes": 400 + rand.Intn(800), "zombie_processes": rand.Intn(3),
+	})
+	system.FileDescriptors = result("file_descriptors", "File descriptors within limits", map[string]interface{}{
+		"allocated": 20000 + rand.Intn(50000), "max": 9223372036854775807,
+	})
+	system.Conntrack = result("conntrack", "Conntrack table usage", map[string]interface{}{
+		"count": rand.Intn(262144), "max": 262144,
+	})
+	system.Inotify = result("inotify", "inotify usage", map[string]interface{}{
+		"max_user_watches": 65536, "max_user_instances": 8192,
+		"users": []map[string]interface{}{{"uid": "0", "instances": rand.Intn(500), "watches": rand.Intn(65536)}},
+	})
+	system.Disks = &nodecheckv1alpha1.DiskCheckResults{
+		Space: result("disk_space", "Disk usage within limits", map[string]interface{}{"check_source": "host", "disk_usage": filesystems}),
+	}
+	kubernetes := &status.CheckResults.KubernetesResults
+	kubernetes.NodeStatus = result("node_status", "Node is Ready", map[string]interface{}{"ready": true, "kubelet_version": "v1.28.6"})
+	kubernetes.KubeletHealth = result("kubelet_health", "kubelet healthz ok", map[string]interface{}{"healthz": "ok"})
+	kubernetes.NodeConditions = result("node_conditions", "No pressure condition", map[string]interface{}{
+		"MemoryPressure": "False", "DiskPressure": "False", "PIDPressure": "False",
+	})
+
+	byName := make(map[string]nodecheckv1alpha1.CheckHistory, len(previous))
+	for _, history := range previous {
+		byName[history.Name] = history
+	}
+	for _, name := range syntheticChecks {
+		history := byName[name]
+		history.Name = name
+		entries := append(history.Entries, nodecheckv1alpha1.CheckHistoryEntry{Timestamp: now, Status: statuses[name]})
+		if len(entries) > historySize {
+			entries = entries[len(entries)-historySize:]
+		}
+		history.Entries = entries
+		history.Transitions = 0
+		for i := 1; i < len(entries); i++ {
+			if entries[i].Status != entries[i-1].Status {
+				history.Transitions++
+				history.LastTransitionTime = &entries[i].Timestamp
+			}
+		}
+		if historySize > 0 {
+			status.History = append(status.History, history)
+		}
+	}
+	return status
+}
+
+// measureDashboard requests the dashboard endpoints with opts.concurrency clients until ctx is done
+func measureDashboard(ctx context.Context, opts options, nodeChecks []*nodecheckv1alpha1.NodeCheck) map[string]*LatencyReport {
+	endpoints := []string{"/api/v1/stats", "/api/v1/nodechecks", "/api/v1/heatmap"}
+	if opts.token != "" {
+		endpoints = append(endpoints, "/api/v2/nodechecks?limit=100", "/api/v2/nodechecks/{namespace}/{name}")
+	}
+	httpClient := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: opts.insecure}},
+	}
+
+	var mu sync.Mutex
+	reports := make(map[string]*LatencyReport, len(endpoints))
+	for _, endpoint := range endpoints {
+		reports[endpoint] = &LatencyReport{}
+	}
+	var wg sync.WaitGroup
+	for worker := 0; worker < opts.concurrency; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := worker; ctx.Err() == nil; i++ {
+				endpoint := endpoints[i%len(endpoints)]
+				path := endpoint
+				if strings.Contains(path, "{name}") {
+					nodeCheck := nodeChecks[rand.Intn(len(nodeChecks))]
+					path = strings.NewReplacer("{namespace}", nodeCheck.Namespace, "{name}", nodeCheck.Name).Replace(path)
+				}
+				latency, err := timeRequest(ctx, httpClient, opts, path)
+				if ctx.Err() != nil {
+					return
+				}
+				mu.Lock()
+				report := reports[endpoint]
+				report.Requests++
+				if err != nil {
+					report.Errors++
+				} else {
+					report.samples = append(report.samples, latency)
+				}
+				mu.Unlock()
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	for _, report := range reports {
+		sort.Slice(report.samples, func(i, j int) bool { return report.samples[i] < report.samples[j] })
+		milliseconds := func(percent int) float64 {
+			return float64(report.samples[len(report.samples)*percent/100]) / float64(time.Millisecond)
+		}
+		if n := len(report.samples); n > 0 {
+			report.P50, report.P95, report.P99 = milliseconds(50), milliseconds(95), milliseconds(99)
+			report.Max = float64(report.samples[n-1]) / float64(time.Millisecond)
+		}
+	}
+	return reports
+}
+
+// timeRequest returns the latency of a GET of the dashboard, including reading the whole body
+func timeRequest(ctx context.Context, httpClient *http.Client, opts options, path string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(opts.dashboardURL, "/")+path, nil)
+	if err != nil {
+		return 0, err
+	}
+	if opts.token != "" {
+		req.Header.Set("Authorization", "Bearer "+opts.token)
+	}
+	started := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		return 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("%s: %s", path, resp.Status)
+	}
+	return time.Since(started), nil
+}
+
+// sampleMemory samples the memory of the operator every 10 seconds until ctx is done
+func sampleMemory(ctx context.Context, metricsURL string) *MemoryReport {
+	report := &MemoryReport{}
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for {
+		if samples, err := scrapeMetrics(ctx, metricsURL); err == nil {
+			rss := samples["process_resident_memory_bytes"]
+			heap := samples["go_memstats_heap_alloc_bytes"]
+			if rss > report.MaxRSSBytes {
+				report.MaxRSSBytes = rss
+			}
+			if heap > report.MaxHeapAllocBytes {
+				report.MaxHeapAllocBytes = heap
+			}
+			report.FinalRSSBytes = rss
+		}
+		select {
+		case <-ctx.Done():
+			return report
+		case <-ticker.C:
+		}
+	}
+}
+
+// scrapeMetrics reads the samples of a Prometheus text exposition, keyed by series (name{labels})
+func scrapeMetrics(ctx context.Context, metricsURL string) (map[string]float64, error) {
+	if metricsURL == "" {
+		return nil, fmt.Errorf("no metrics URL")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metricsURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", metricsURL, resp.Status)
+	}
+	samples := make(map[string]float64)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		separator := strings.LastIndex(line, " ")
+		if separator < 0 {
+			continue
+		}
+		value, err := strconv.ParseFloat(line[separator+1:], 64)
+		if err != nil {
+			continue
+		}
+		samples[line[:separator]] = value
+	}
+	return samples, scanner.Err()
+}
+
+// counterRates returns the per-second increase of the series of a counter between two scrapes, keyed by
+// the controller label
+func counterRates(before, after map[string]float64, name string, seconds float64) map[string]float64 {
+	rates := make(map[string]float64)
+	for series, value := range after {
+		if !strings.HasPrefix(series, name+"{") {
+			continue
+		}
+		controller := series
+		if _, label, ok := strings.Cut(series, `controller="`); ok {
+			controller, _, _ = strings.Cut(label, `"`)
+		}
+		rates[controller] += (value - before[series]) / seconds
+	}
+	return rates
+}
+
+// cleanup deletes the NodeChecks created by loadgen
+func cleanup(ctx context.Context, k8sClient client.Client, namespace string) error {
+	fmt.Fprintf(os.Stderr, "Deleting the synthetic NodeChecks of %s...\n", namespace)
+	return k8sClient.DeleteAllOf(ctx, &nodecheckv1alpha1.NodeCheck{},
+		client.InNamespace(namespace), client.MatchingLabels{loadgenLabel: "true"})
+}
+
+// printReport prints the report as text
+func printReport(report *Report) {
+	fmt.Printf("NodeChecks:      %d\n", report.NodeChecks)
+	fmt.Printf("Duration:        %s\n", report.Duration)
+	fmt.Printf("Status updates:  %d (%d failed)\n", report.StatusUpdates, report.UpdateErrors)
+
+	if len(report.Reconciles) > 0 {
+		fmt.Println("\nReconciles per second:")
+		controllers := make([]string, 0, len(report.Reconciles))
+		for controller := range report.Reconciles {
+			controllers = append(controllers, controller)
+		}
+		sort.Strings(controllers)
+		for _, controller := range controllers {
+			fmt.Printf("  %-28s %8.2f/s  (errors %.2f/s)\n", controller, report.Reconciles[controller], report.ReconcileErrors[controller])
+		}
+	}
+	if report.Memory != nil {
+		fmt.Println("\nOperator memory:")
+		fmt.Printf("  max RSS        %8.1f MiB\n", report.Memory.MaxRSSBytes/(1<<20))
+		fmt.Printf("  final RSS      %8.1f MiB\n", report.Memory.FinalRSSBytes/(1<<20))
+		fmt.Printf("  max heap       %8.1f MiB\n", report.Memory.MaxHeapAllocBytes/(1<<20))
+	}
+	if len(report.Dashboard) > 0 {
+		fmt.Println("\nDashboard latency:")
+		fmt.Printf("  %-40s %8s %6s %10s %10s %10s %10s\n", "endpoint", "requests", "errors", "p50", "p95", "p99", "max")
+		endpoints := make([]string, 0, len(report.Dashboard))
+		for endpoint := range report.Dashboard {
+			endpoints = append(endpoints, endpoint)
+		}
+		sort.Strings(endpoints)
+		for _, endpoint := range endpoints {
+			r := report.Dashboard[endpoint]
+			fmt.Printf("  %-40s %8d %6d %8.1fms %8.1fms %8.1fms %8.1fms\n", endpoint, r.Requests, r.Errors, r.P50, r.P95, r.P99, r.Max)
+		}
+	}
+}
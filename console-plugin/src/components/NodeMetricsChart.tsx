@@ -1,7 +1,7 @@
 import React, { useState, useEffect } from 'react';
 import { Card, CardBody, Spinner } from '@patternfly/react-core';
 import { ChartDonutUtilization } from '@patternfly/react-charts/victory';
-import { apiGet } from '../utils/api';
+import { apiGet, MemoryDetails, NodeResourceUsageDetails } from '../utils/api';
 
 interface NodeCheckDetail {
   name: string;
@@ -12,7 +12,7 @@ interface NodeCheckDetail {
       status?: string;
     };
     memory?: {
-      details?: Record<string, any>;
+      details?: MemoryDetails;
       status?: string;
     };
     hardware?: {
@@ -27,7 +27,7 @@ interface NodeCheckDetail {
       status?: string;
     };
     nodeResourceUsage?: {
-      details?: Record<string, any>;
+      details?: NodeResourceUsageDetails;
       status?: string;
     };
   };
@@ -56,13 +56,13 @@ export const NodeMetricsChart: React.FC<NodeMetricsChartProps> = ({ nodeChecks }
             const detail = await apiGet<NodeCheckDetail>(`nodechecks/${nodeCheck.name}`, { namespace });
             details[nodeCheck.nodeName] = detail;
           } catch (err) {
-            console.error(`Errore caricamento dettagli per ${nodeCheck.name}:`, err);
+            console.error(`Error loading details for ${nodeCheck.name}:`, err);
           }
         }
         
         setNodeDetails(details);
       } catch (err) {
-        console.error('Errore caricamento dettagli:', err);
+        console.error('Error loading details:', err);
       } finally {
         setLoading(false);
       }
@@ -81,7 +81,7 @@ export const NodeMetricsChart: React.FC<NodeMetricsChartProps> = ({ nodeChecks }
         <CardBody>
           <div style={{ textAlign: 'center', padding: '2rem' }}>
             <Spinner size="lg" />
-            <p style={{ marginTop: '1rem' }}>Caricamento metriche...</p>
+            <p style={{ marginTop: '1rem' }}>Loading metrics...</p>
           </div>
         </CardBody>
       </Card>
@@ -130,8 +130,8 @@ export const NodeMetricsChart: React.FC<NodeMetricsChartProps> = ({ nodeChecks }
     // CONSUMI REAL-TIME: da kubernetesResults.nodeResourceUsage (metrics-server)
     const nodeResourceUsage = detail.kubernetesResults?.nodeResourceUsage?.details;
     if (nodeResourceUsage) {
-      const cpuUsage = nodeResourceUsage.cpu_usage as Record<string, any> | undefined;
-      const memoryUsage = nodeResourceUsage.memory_usage as Record<string, any> | undefined;
+      const cpuUsage = nodeResourceUsage.cpu_usage;
+      const memoryUsage = nodeResourceUsage.memory_usage;
       
       if (cpuUsage && typeof cpuUsage.percent === 'number') {
         cpuUsageValues.push(cpuUsage.percent);
@@ -10,6 +10,7 @@ import { apiGet } from '../utils/api';
 import { NodeMetricsChart } from './NodeMetricsChart';
 
 interface CheckSummary {
+  key?: string;
   name: string;
   category: 'system' | 'kubernetes';
   enabled: boolean;
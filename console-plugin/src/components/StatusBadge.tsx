@@ -2,7 +2,7 @@ import React from 'react';
 import { Badge } from '@patternfly/react-core';
 
 interface StatusBadgeProps {
-  status: 'Healthy' | 'Warning' | 'Critical' | 'Unknown';
+  status: 'Healthy' | 'Warning' | 'Critical' | 'Unknown' | 'Suppressed' | 'NotSupported';
 }
 
 export const StatusBadge: React.FC<StatusBadgeProps> = ({ status }) => {
@@ -18,6 +18,10 @@ export const StatusBadge: React.FC<StatusBadgeProps> = ({ status }) => {
         return 'warning';
       case 'Critical':
         return 'danger';
+      case 'NotSupported':
+        return 'warning';
+      case 'Suppressed':
+        return 'info';
       default:
         return 'secondary';
     }
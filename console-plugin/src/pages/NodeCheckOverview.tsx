@@ -26,7 +26,7 @@ import { Chip } from '@patternfly/react-core/deprecated';
 import { Table, Thead, Tbody, Tr, Th, Td } from '@patternfly/react-table';
 import { StatusBadge } from '../components/StatusBadge';
 import { StatsOverview } from '../components/StatsCard';
-import { apiGet } from '../utils/api';
+import { apiGet, getUIConfig, isFeatureEnabled, markFalsePositive, DEFAULT_UI_CONFIG, UIConfig } from '../utils/api';
 import { navigateTo } from '../utils/navigation';
 import '../styles.css';
 
@@ -40,7 +40,7 @@ interface NodeCheck {
 }
 
 interface CheckResult {
-  status?: 'Healthy' | 'Warning' | 'Critical' | 'Unknown';
+  status?: 'Healthy' | 'Warning' | 'Critical' | 'Unknown' | 'Suppressed' | 'NotSupported';
   message?: string;
   timestamp?: string;
   command?: string;
@@ -76,6 +76,34 @@ interface NodeCheckDetail {
     selinuxStatus?: CheckResult;
     sshAccess?: CheckResult;
     kernelModules?: CheckResult;
+    kernelTaint?: CheckResult;
+    pressureStall?: CheckResult;
+    entropy?: CheckResult;
+    transparentHugePages?: CheckResult;
+    hugePages?: CheckResult;
+    numa?: CheckResult;
+    conntrack?: CheckResult;
+    knownIssues?: CheckResult;
+    kdump?: CheckResult;
+    sysctlDrift?: CheckResult;
+    inotify?: CheckResult;
+    serviceRestarts?: CheckResult;
+    coreDumps?: CheckResult;
+    timeDrift?: CheckResult;
+    audit?: CheckResult;
+    cpuVulnerabilities?: CheckResult;
+    kernelCmdline?: CheckResult;
+    osUpdates?: CheckResult;
+    cgroupDriver?: CheckResult;
+    processLimits?: CheckResult;
+    orphanedMounts?: CheckResult;
+    userspaceOOM?: CheckResult;
+    gpu?: CheckResult;
+    sriov?: CheckResult;
+    kernelLockups?: CheckResult;
+    swapPolicy?: CheckResult;
+    certificateExpiry?: CheckResult;
+    baselineDrift?: CheckResult;
     hardware?: {
       temperature?: CheckResult;
       ipmi?: CheckResult;
@@ -98,6 +126,9 @@ interface NodeCheckDetail {
       filesystemErrors?: CheckResult;
       inodeUsage?: CheckResult;
       mountPoints?: CheckResult;
+      writeProbe?: CheckResult;
+      runtimeStorage?: CheckResult;
+      wearLevel?: CheckResult;
     };
     network?: {
       interfaces?: CheckResult;
@@ -109,6 +140,9 @@ interface NodeCheckDetail {
       dnsResolution?: CheckResult;
       bondingStatus?: CheckResult;
       firewallRules?: CheckResult;
+      egress?: CheckResult;
+      ingress?: CheckResult;
+      resolvConf?: CheckResult;
     };
   };
   kubernetesResults?: {
@@ -121,10 +155,15 @@ interface NodeCheckDetail {
     kubeletHealth?: CheckResult;
     cniPlugin?: CheckResult;
     nodeConditions?: CheckResult;
+    podScheduling?: CheckResult;
+    pvcProvisioning?: CheckResult;
+    podNetwork?: CheckResult;
+    lbHealthCheck?: CheckResult;
   };
 }
 
 interface CheckSummary {
+  key?: string;
   name: string;
   category: 'system' | 'kubernetes';
   enabled: boolean;
@@ -132,7 +171,8 @@ interface CheckSummary {
   warningCount: number;
   criticalCount: number;
   unknownCount: number;
-  overallStatus: 'Healthy' | 'Warning' | 'Critical' | 'Unknown';
+  suppressedCount?: number;
+  overallStatus: 'Healthy' | 'Warning' | 'Critical' | 'Unknown' | 'Suppressed';
 }
 
 interface Stats {
@@ -145,6 +185,9 @@ interface Stats {
   checks?: CheckSummary[];
 }
 
+// Tab of each view that uiconfig's defaultView can open
+const VIEW_TABS: Record<UIConfig['defaultView'], number> = { overview: 0, checks: 1, nodes: 2 };
+
 const NodeCheckOverview: React.FC = () => {
   const [stats, setStats] = useState<Stats | null>(null);
   const [nodeChecks, setNodeChecks] = useState<NodeCheck[]>([]);
@@ -162,6 +205,11 @@ const NodeCheckOverview: React.FC = () => {
   const [nodeCheckTypeaheadOpen, setNodeCheckTypeaheadOpen] = useState<Record<string, boolean>>({});
   const [nodeCheckTypeaheadFocused, setNodeCheckTypeaheadFocused] = useState<Record<string, number | null>>({});
   const [nodeCheckTabs, setNodeCheckTabs] = useState<Record<string, string | number>>({});
+  const [uiConfig, setUIConfig] = useState<UIConfig>(DEFAULT_UI_CONFIG);
+  // False-positive feedback per check card: "sending", "sent" or the error of the request
+  const [falsePositives, setFalsePositives] = useState<Record<string, string>>({});
+  const showChecksView = isFeatureEnabled(uiConfig, 'checksView');
+  const showNodesView = isFeatureEnabled(uiConfig, 'nodesView');
 
   useEffect(() => {
     const loadData = async () => {
@@ -169,11 +217,19 @@ const NodeCheckOverview: React.FC = () => {
         setLoading(true);
         setError(null);
 
-        const [statsData, nodeChecksData] = await Promise.all([
+        const [statsData, nodeChecksData, uiConfigData] = await Promise.all([
           apiGet<Stats>('stats'),
           apiGet<NodeCheck[]>('nodechecks'),
+          getUIConfig(),
         ]);
 
+        setUIConfig(uiConfigData);
+        // Open the configured default view, unless it is disabled
+        const defaultTab = VIEW_TABS[uiConfigData.defaultView] ?? 0;
+        const defaultTabHidden =
+          (defaultTab === 1 && !isFeatureEnabled(uiConfigData, 'checksView')) ||
+          (defaultTab === 2 && !isFeatureEnabled(uiConfigData, 'nodesView'));
+        setActiveTab(defaultTabHidden ? 0 : defaultTab);
         setStats(statsData);
         // Filter out generic NodeChecks (nodeName === "*")
         const filteredNodeChecks = (nodeChecksData || []).filter(
@@ -181,8 +237,8 @@ const NodeCheckOverview: React.FC = () => {
         );
         setNodeChecks(filteredNodeChecks);
       } catch (err) {
-        console.error('Errore caricamento dati:', err);
-        setError(err instanceof Error ? err.message : 'Errore sconosciuto');
+        console.error('Error loading data:', err);
+        setError(err instanceof Error ? err.message : 'Unknown error');
       } finally {
         setLoading(false);
       }
@@ -201,7 +257,7 @@ const NodeCheckOverview: React.FC = () => {
                 <Bullseye>
                   <div style={{ textAlign: 'center' }}>
                     <Spinner size="xl" />
-                    <p style={{ marginTop: '1rem' }}>Caricamento dati...</p>
+                    <p style={{ marginTop: '1rem' }}>Loading data...</p>
                   </div>
                 </Bullseye>
               </div>
@@ -219,7 +275,7 @@ const NodeCheckOverview: React.FC = () => {
           <main className="pf-v6-c-page__main">
             <section className="pf-v6-c-page__main-section">
               <div className="pf-v6-c-page__main-body">
-                <Alert variant="danger" title="Errore nel caricamento dei dati">
+                <Alert variant="danger" title="Error loading data">
                   {error}
                 </Alert>
               </div>
@@ -273,8 +329,8 @@ const NodeCheckOverview: React.FC = () => {
         [nodeName]: 0
       }));
     } catch (err) {
-      console.error(`Errore caricamento dettagli per nodo ${nodeName}:`, err);
-      const errorMessage = err instanceof Error ? err.message : 'Errore sconosciuto nel caricamento dei dettagli';
+      console.error(`Error loading details for node ${nodeName}:`, err);
+      const errorMessage = err instanceof Error ? err.message : 'Unknown error while loading the details';
       setNodeErrors(prev => ({ ...prev, [nodeName]: errorMessage }));
     } finally {
       setNodeLoading(prev => ({ ...prev, [nodeName]: false }));
@@ -337,6 +393,34 @@ const NodeCheckOverview: React.FC = () => {
       'SELinux Status': 'SELinux Status',
       'SSH Access': 'SSH Access',
       'Kernel Modules': 'Kernel Modules',
+      'Kernel Taint': 'Kernel Taint',
+      'Pressure Stall (PSI)': 'Pressure Stall (PSI)',
+      'Entropy Pool': 'Entropy Pool',
+      'Transparent Huge Pages': 'Transparent Huge Pages',
+      'Huge Pages': 'Huge Pages',
+      'NUMA Balance': 'NUMA Balance',
+      'Conntrack Table': 'Conntrack Table',
+      'Known Issues': 'Known Issues',
+      'Kdump Readiness': 'Kdump Readiness',
+      'Sysctl Drift': 'Sysctl Drift',
+      'Inotify Limits': 'Inotify Limits',
+      'Service Restarts': 'Service Restarts',
+      'Core Dumps': 'Core Dumps',
+      'Time Drift': 'Time Drift',
+      'Audit Subsystem': 'Audit Subsystem',
+      'CPU Vulnerabilities': 'CPU Vulnerabilities',
+      'Kernel Command Line': 'Kernel Command Line',
+      'OS Updates': 'OS Updates',
+      'Cgroup Driver': 'Cgroup Driver',
+      'Process Limits': 'Process Limits',
+      'Orphaned Mounts': 'Orphaned Mounts',
+      'Userspace OOM': 'Userspace OOM',
+      'GPU': 'GPU',
+      'SR-IOV': 'SR-IOV',
+      'Kernel Lockups': 'Kernel Lockups',
+      'Swap Policy': 'Swap Policy',
+      'Certificate Expiry': 'Certificate Expiry',
+      'Baseline Drift': 'Baseline Drift',
       'Fan Status': 'Fan Status',
       'Power Supply': 'Power Supply',
       'Memory Errors': 'Memory Errors',
@@ -347,15 +431,25 @@ const NodeCheckOverview: React.FC = () => {
       'Filesystem Errors': 'Filesystem Errors',
       'Inode Usage': 'Inode Usage',
       'Mount Points': 'Mount Points',
+      'Write Probe': 'Write Probe',
+      'Runtime Storage': 'Runtime Storage',
+      'Wear Level': 'Wear Level',
       'Errors': 'Network Errors',
       'Latency': 'Network Latency',
       'DNS Resolution': 'DNS Resolution',
       'Bonding Status': 'Bonding Status',
       'Firewall Rules': 'Firewall Rules',
+      'Egress': 'Egress',
+      'Ingress': 'Ingress',
+      'Resolv Conf': 'Resolv Conf',
       'Container Runtime': 'Container Runtime',
       'Kubelet Health': 'Kubelet Health',
       'CNI Plugin': 'CNI Plugin',
       'Node Conditions': 'Node Conditions',
+      'Pod Scheduling': 'Pod Scheduling',
+      'PVC Provisioning': 'PVC Provisioning',
+      'Pod Network': 'Pod Network',
+      'LB Health Check': 'LB Health Check',
     };
     return titleToCheckName[title] || title;
   };
@@ -404,6 +498,34 @@ const NodeCheckOverview: React.FC = () => {
     }));
   };
 
+  // Path of a result in the results of a node ("systemResults.disks.space"), found by identity
+  const findResultPath = (tree: Record<string, any> | undefined, result: CheckResult, path: string): string | undefined => {
+    if (!tree) return undefined;
+    for (const [key, value] of Object.entries(tree)) {
+      if (value === result) return `${path}.${key}`;
+      if (value && typeof value === 'object' && !('status' in value)) {
+        const found = findResultPath(value, result, `${path}.${key}`);
+        if (found) return found;
+      }
+    }
+    return undefined;
+  };
+
+  const handleMarkFalsePositive = async (nodeName: string, result: CheckResult, checkKey: string) => {
+    const detail = nodeDetails[nodeName];
+    const check = detail && (findResultPath(detail.systemResults, result, 'systemResults') ||
+      findResultPath(detail.kubernetesResults, result, 'kubernetesResults'));
+    if (!detail || !check) return;
+    setFalsePositives(prev => ({ ...prev, [checkKey]: 'sending' }));
+    try {
+      await markFalsePositive(detail.namespace || 'node-check-operator-system', detail.name, check);
+      setFalsePositives(prev => ({ ...prev, [checkKey]: 'sent' }));
+    } catch (err) {
+      const errorMessage = err instanceof Error ? err.message : 'Unable to record the feedback';
+      setFalsePositives(prev => ({ ...prev, [checkKey]: errorMessage }));
+    }
+  };
+
   const renderCheckResult = (
     nodeName: string,
     title: string,
@@ -489,6 +611,22 @@ const NodeCheckOverview: React.FC = () => {
               <p>
                 <strong>Message:</strong> {message}
               </p>
+              {result && (status === 'Warning' || status === 'Critical') &&
+                isFeatureEnabled(uiConfig, 'falsePositiveFeedback') && (
+                <p>
+                  <Button
+                    variant="secondary"
+                    size="sm"
+                    isDisabled={falsePositives[checkKey] === 'sending' || falsePositives[checkKey] === 'sent'}
+                    onClick={() => handleMarkFalsePositive(nodeName, result, checkKey)}
+                  >
+                    {falsePositives[checkKey] === 'sent' ? 'Marked as false positive' : 'Mark as false positive'}
+                  </Button>
+                  {falsePositives[checkKey] && falsePositives[checkKey] !== 'sending' && falsePositives[checkKey] !== 'sent' && (
+                    <span style={{ marginLeft: '0.5rem', color: '#c9190b' }}>{falsePositives[checkKey]}</span>
+                  )}
+                </p>
+              )}
               {displayResult.command && (
                 <p>
                   <strong>Command:</strong>{' '}
@@ -556,6 +694,7 @@ const NodeCheckOverview: React.FC = () => {
               }}
               onMouseEnter={(e) => e.currentTarget.style.textDecoration = 'underline'}
               onMouseLeave={(e) => e.currentTarget.style.textDecoration = 'none'}
+              disabled={!showNodesView}
               onClick={(e) => {
                 e.preventDefault();
                 e.stopPropagation();
@@ -605,15 +744,21 @@ const NodeCheckOverview: React.FC = () => {
                   data-ouia-component-id="PageHeader-title" 
                   data-pf-content="true" 
                   className="pf-v6-c-content--h1"
+                  style={{ display: 'flex', alignItems: 'center', gap: '0.5rem' }}
                 >
-                  Node Check
+                  {uiConfig.logoURL && (
+                    <img src={uiConfig.logoURL} alt="" style={{ height: '2rem' }} />
+                  )}
+                  {uiConfig.title}
                 </h1>
-                <Button
-                  variant="secondary"
-                  onClick={() => navigateTo('/k8s/all-namespaces/nodecheck.openshift.io~v1alpha1~NodeCheck')}
-                >
-                  Vedi NodeChecks (Kubernetes)
-                </Button>
+                {isFeatureEnabled(uiConfig, 'resourceLink') && (
+                  <Button
+                    variant="secondary"
+                    onClick={() => navigateTo('/k8s/all-namespaces/nodecheck.openshift.io~v1alpha1~NodeCheck')}
+                  >
+                    View NodeChecks (Kubernetes)
+                  </Button>
+                )}
               </div>
             </div>
           </section>
@@ -630,17 +775,17 @@ const NodeCheckOverview: React.FC = () => {
             </div>
                 </Tab>
 
-                <Tab eventKey={1} title="Checks">
+                <Tab eventKey={1} title="Checks" isHidden={!showChecksView}>
                   <div style={{ marginTop: '1rem' }}>
               <Card>
                 <CardBody>
                         {!stats?.checks || stats.checks.length === 0 ? (
                     <EmptyState>
                             <h4 style={{ fontSize: '1.25rem', fontWeight: 'bold', marginBottom: '0.5rem' }}>
-                              Nessun check disponibile
+                              No checks available
                             </h4>
                       <EmptyStateBody>
-                              Non ci sono check disponibili. Verifica che l'operator sia configurato correttamente.
+                              There are no checks available. Verify that the operator is configured correctly.
                       </EmptyStateBody>
                     </EmptyState>
                   ) : (
@@ -684,17 +829,17 @@ const NodeCheckOverview: React.FC = () => {
                   </div>
                 </Tab>
 
-                <Tab eventKey={2} title="Node Details">
+                <Tab eventKey={2} title="Node Details" isHidden={!showNodesView}>
                   <div style={{ marginTop: '1rem' }}>
                     {Object.keys(nodeGroups).length === 0 ? (
                       <Card>
                         <CardBody>
                           <EmptyState>
                             <h4 style={{ fontSize: '1.25rem', fontWeight: 'bold', marginBottom: '0.5rem' }}>
-                              Nessun nodo trovato
+                              No nodes found
                             </h4>
                             <EmptyStateBody>
-                              Non ci sono nodi disponibili. Verifica che l'operator sia configurato correttamente.
+                              There are no nodes available. Verify that the operator is configured correctly.
                             </EmptyStateBody>
                           </EmptyState>
                         </CardBody>
@@ -783,7 +928,7 @@ const NodeCheckOverview: React.FC = () => {
                                   return (
                                     <div style={{ marginTop: '1rem', paddingLeft: '2rem' }}>
                                       <Spinner size="sm" />
-                                      <span style={{ marginLeft: '0.5rem' }}>Caricamento dettagli...</span>
+                                      <span style={{ marginLeft: '0.5rem' }}>Loading details...</span>
                                     </div>
                                   );
                                 }
@@ -791,7 +936,7 @@ const NodeCheckOverview: React.FC = () => {
                                 if (nodeError) {
                                   return (
                                     <div style={{ marginTop: '1rem', paddingLeft: '2rem' }}>
-                                      <Alert variant="danger" title="Errore nel caricamento dei dettagli">
+                                      <Alert variant="danger" title="Error loading details">
                                         {nodeError}
                                         <div style={{ marginTop: '0.5rem' }}>
                                           <Button
@@ -812,7 +957,7 @@ const NodeCheckOverview: React.FC = () => {
                                               loadNodeDetails(nodeName);
                                             }}
                                           >
-                                            Riprova
+                                            Retry
                                           </Button>
                                         </div>
                                       </Alert>
@@ -823,8 +968,8 @@ const NodeCheckOverview: React.FC = () => {
                                 if (!nodeDetail) {
                                   return (
                                     <div style={{ marginTop: '1rem', paddingLeft: '2rem' }}>
-                                      <Alert variant="warning" title="Dettagli non disponibili">
-                                        I dettagli per questo nodo non sono ancora stati caricati.
+                                      <Alert variant="warning" title="Details not available">
+                                        The details of this node have not been loaded yet.
                                       </Alert>
                                     </div>
                                   );
@@ -840,25 +985,29 @@ const NodeCheckOverview: React.FC = () => {
                                   systemResults.oomKiller || systemResults.cpuFrequency ||
                                   systemResults.interruptsBalance || systemResults.cpuStealTime || systemResults.memoryFragmentation ||
                                   systemResults.swapActivity || systemResults.contextSwitches || systemResults.selinuxStatus ||
-                                  systemResults.sshAccess || systemResults.kernelModules ||
+                                  systemResults.sshAccess || systemResults.kernelModules || systemResults.kernelTaint || systemResults.pressureStall || systemResults.entropy || systemResults.transparentHugePages || systemResults.hugePages || systemResults.numa || systemResults.conntrack || systemResults.knownIssues || systemResults.kdump || systemResults.sysctlDrift || systemResults.inotify || systemResults.serviceRestarts || systemResults.coreDumps || systemResults.timeDrift || systemResults.audit || systemResults.cpuVulnerabilities || systemResults.kernelCmdline || systemResults.osUpdates || systemResults.cgroupDriver || systemResults.processLimits || systemResults.orphanedMounts || systemResults.userspaceOOM || systemResults.gpu || systemResults.sriov || systemResults.kernelLockups || systemResults.swapPolicy || systemResults.certificateExpiry || systemResults.baselineDrift ||
                                   (systemResults.hardware && (systemResults.hardware.temperature || systemResults.hardware.ipmi || systemResults.hardware.bmc ||
                                     systemResults.hardware.fanStatus || systemResults.hardware.powerSupply || systemResults.hardware.memoryErrors ||
                                     systemResults.hardware.pcieErrors || systemResults.hardware.cpuMicrocode)) ||
                                   (systemResults.disks && (systemResults.disks.space || systemResults.disks.smart || systemResults.disks.performance ||
                                     systemResults.disks.raid || systemResults.disks.pvs || systemResults.disks.lvm || systemResults.disks.ioWait ||
                                     systemResults.disks.queueDepth || systemResults.disks.filesystemErrors || systemResults.disks.inodeUsage ||
-                                    systemResults.disks.mountPoints)) ||
+                                    systemResults.disks.mountPoints || systemResults.disks.writeProbe || systemResults.disks.runtimeStorage || systemResults.disks.wearLevel)) ||
                                   (systemResults.network && (systemResults.network.interfaces || systemResults.network.routing ||
                                     systemResults.network.connectivity || systemResults.network.statistics || systemResults.network.errors ||
                                     systemResults.network.latency || systemResults.network.dnsResolution || systemResults.network.bondingStatus ||
-                                    systemResults.network.firewallRules))
+                                    systemResults.network.firewallRules || systemResults.network.egress || systemResults.network.ingress ||
+                                    systemResults.network.resolvConf))
                                 );
                                 const hasKubernetesResults = kubernetesResults && (
                                   kubernetesResults.nodeStatus || kubernetesResults.pods ||
                                   kubernetesResults.clusterOperators || kubernetesResults.nodeResources ||
                                   kubernetesResults.nodeResourceUsage || kubernetesResults.containerRuntime ||
                                   kubernetesResults.kubeletHealth || kubernetesResults.cniPlugin ||
-                                  kubernetesResults.nodeConditions
+                                  kubernetesResults.nodeConditions || kubernetesResults.podScheduling ||
+                                  kubernetesResults.pvcProvisioning ||
+                                  kubernetesResults.podNetwork ||
+                                  kubernetesResults.lbHealthCheck
                                 );
 
                                 const isFilterDropdownOpen = nodeFilterDropdowns[nodeName] || false;
@@ -1132,6 +1281,34 @@ const NodeCheckOverview: React.FC = () => {
                                                   {renderCheckResult(nodeName, 'SELinux Status', systemResults.selinuxStatus, `${nodeName}-system-selinux-status`, true)}
                                                   {renderCheckResult(nodeName, 'SSH Access', systemResults.sshAccess, `${nodeName}-system-ssh-access`, true)}
                                                   {renderCheckResult(nodeName, 'Kernel Modules', systemResults.kernelModules, `${nodeName}-system-kernel-modules`, true)}
+                                                  {renderCheckResult(nodeName, 'Kernel Taint', systemResults.kernelTaint, `${nodeName}-system-kernel-taint`, true)}
+                                                  {renderCheckResult(nodeName, 'Pressure Stall (PSI)', systemResults.pressureStall, `${nodeName}-system-pressure-stall`, true)}
+                                                  {renderCheckResult(nodeName, 'Entropy Pool', systemResults.entropy, `${nodeName}-system-entropy`, true)}
+                                                  {renderCheckResult(nodeName, 'Transparent Huge Pages', systemResults.transparentHugePages, `${nodeName}-system-transparent-hugepages`, true)}
+                                                  {renderCheckResult(nodeName, 'Huge Pages', systemResults.hugePages, `${nodeName}-system-huge-pages`, true)}
+                                                  {renderCheckResult(nodeName, 'NUMA Balance', systemResults.numa, `${nodeName}-system-numa`, true)}
+                                                  {renderCheckResult(nodeName, 'Conntrack Table', systemResults.conntrack, `${nodeName}-system-conntrack`, true)}
+                                                  {renderCheckResult(nodeName, 'Known Issues', systemResults.knownIssues, `${nodeName}-system-known-issues`, true)}
+                                                  {renderCheckResult(nodeName, 'Kdump Readiness', systemResults.kdump, `${nodeName}-system-kdump`, true)}
+                                                  {renderCheckResult(nodeName, 'Sysctl Drift', systemResults.sysctlDrift, `${nodeName}-system-sysctl-drift`, true)}
+                                                  {renderCheckResult(nodeName, 'Inotify Limits', systemResults.inotify, `${nodeName}-system-inotify`, true)}
+                                                  {renderCheckResult(nodeName, 'Service Restarts', systemResults.serviceRestarts, `${nodeName}-system-service-restarts`, true)}
+                                                  {renderCheckResult(nodeName, 'Core Dumps', systemResults.coreDumps, `${nodeName}-system-core-dumps`, true)}
+                                                  {renderCheckResult(nodeName, 'Time Drift', systemResults.timeDrift, `${nodeName}-system-time-drift`, true)}
+                                                  {renderCheckResult(nodeName, 'Audit Subsystem', systemResults.audit, `${nodeName}-system-audit`, true)}
+                                                  {renderCheckResult(nodeName, 'CPU Vulnerabilities', systemResults.cpuVulnerabilities, `${nodeName}-system-cpu-vulnerabilities`, true)}
+                                                  {renderCheckResult(nodeName, 'Kernel Command Line', systemResults.kernelCmdline, `${nodeName}-system-kernel-cmdline`, true)}
+                                                  {renderCheckResult(nodeName, 'OS Updates', systemResults.osUpdates, `${nodeName}-system-os-updates`, true)}
+                                                  {renderCheckResult(nodeName, 'Cgroup Driver', systemResults.cgroupDriver, `${nodeName}-system-cgroup-driver`, true)}
+                                                  {renderCheckResult(nodeName, 'Process Limits', systemResults.processLimits, `${nodeName}-system-process-limits`, true)}
+                                                  {renderCheckResult(nodeName, 'Orphaned Mounts', systemResults.orphanedMounts, `${nodeName}-system-orphaned-mounts`, true)}
+                                                  {renderCheckResult(nodeName, 'Userspace OOM', systemResults.userspaceOOM, `${nodeName}-system-userspace-oom`, true)}
+                                                  {renderCheckResult(nodeName, 'GPU', systemResults.gpu, `${nodeName}-system-gpu`, true)}
+                                                  {renderCheckResult(nodeName, 'SR-IOV', systemResults.sriov, `${nodeName}-system-sriov`, true)}
+                                                  {renderCheckResult(nodeName, 'Kernel Lockups', systemResults.kernelLockups, `${nodeName}-system-kernel-lockups`, true)}
+                                                  {renderCheckResult(nodeName, 'Swap Policy', systemResults.swapPolicy, `${nodeName}-system-swap-policy`, true)}
+                                                  {renderCheckResult(nodeName, 'Certificate Expiry', systemResults.certificateExpiry, `${nodeName}-system-certificate-expiry`, true)}
+                                                  {renderCheckResult(nodeName, 'Baseline Drift', systemResults.baselineDrift, `${nodeName}-system-baseline-drift`, true)}
                                                   
                                                   <h3 style={{ fontSize: '1.2rem', marginTop: '1.5rem', marginBottom: '0.5rem' }}>Hardware</h3>
                                                   {renderCheckResult(nodeName, 'Temperature', systemResults.hardware?.temperature, `${nodeName}-hardware-temperature`, true)}
@@ -1155,6 +1332,9 @@ const NodeCheckOverview: React.FC = () => {
                                                   {renderCheckResult(nodeName, 'Filesystem Errors', systemResults.disks?.filesystemErrors, `${nodeName}-disk-filesystem-errors`, true)}
                                                   {renderCheckResult(nodeName, 'Inode Usage', systemResults.disks?.inodeUsage, `${nodeName}-disk-inode-usage`, true)}
                                                   {renderCheckResult(nodeName, 'Mount Points', systemResults.disks?.mountPoints, `${nodeName}-disk-mount-points`, true)}
+                                                  {renderCheckResult(nodeName, 'Write Probe', systemResults.disks?.writeProbe, `${nodeName}-disk-write-probe`, true)}
+                                                  {renderCheckResult(nodeName, 'Runtime Storage', systemResults.disks?.runtimeStorage, `${nodeName}-disk-runtime-storage`, true)}
+                                                  {renderCheckResult(nodeName, 'Wear Level', systemResults.disks?.wearLevel, `${nodeName}-disk-wear-level`, true)}
 
                                                   <h3 style={{ fontSize: '1.2rem', marginTop: '1.5rem', marginBottom: '0.5rem' }}>Network</h3>
                                                   {renderCheckResult(nodeName, 'Interfaces', systemResults.network?.interfaces, `${nodeName}-network-interfaces`, true)}
@@ -1166,12 +1346,15 @@ const NodeCheckOverview: React.FC = () => {
                                                   {renderCheckResult(nodeName, 'DNS Resolution', systemResults.network?.dnsResolution, `${nodeName}-network-dns-resolution`, true)}
                                                   {renderCheckResult(nodeName, 'Bonding Status', systemResults.network?.bondingStatus, `${nodeName}-network-bonding-status`, true)}
                                                   {renderCheckResult(nodeName, 'Firewall Rules', systemResults.network?.firewallRules, `${nodeName}-network-firewall-rules`, true)}
+                                                  {renderCheckResult(nodeName, 'Egress', systemResults.network?.egress, `${nodeName}-network-egress`, true)}
+                                                  {renderCheckResult(nodeName, 'Ingress', systemResults.network?.ingress, `${nodeName}-network-ingress`, true)}
+                                                  {renderCheckResult(nodeName, 'Resolv Conf', systemResults.network?.resolvConf, `${nodeName}-network-resolv-conf`, true)}
                                                   
                                                   {!hasSystemResults && (
                                                     <Card style={{ marginTop: '1rem' }}>
                                                       <CardBody>
                                                         <p style={{ color: '#666', fontStyle: 'italic' }}>
-                                                          Nessun check di sistema disponibile al momento.
+                                                          No system checks available at the moment.
                                                         </p>
                                                       </CardBody>
                                                     </Card>
@@ -1181,7 +1364,7 @@ const NodeCheckOverview: React.FC = () => {
                                                 <Card style={{ marginTop: '1rem' }}>
                                                   <CardBody>
                                                     <p style={{ color: '#666', fontStyle: 'italic' }}>
-                                                      Dati dei check di sistema non disponibili.
+                                                      System check data not available.
                                                     </p>
                                                   </CardBody>
                                                 </Card>
@@ -1202,12 +1385,16 @@ const NodeCheckOverview: React.FC = () => {
                                                   {renderCheckResult(nodeName, 'Kubelet Health', kubernetesResults.kubeletHealth, `${nodeName}-k8s-kubelet-health`, true)}
                                                   {renderCheckResult(nodeName, 'CNI Plugin', kubernetesResults.cniPlugin, `${nodeName}-k8s-cni-plugin`, true)}
                                                   {renderCheckResult(nodeName, 'Node Conditions', kubernetesResults.nodeConditions, `${nodeName}-k8s-node-conditions`, true)}
+                                                  {renderCheckResult(nodeName, 'Pod Scheduling', kubernetesResults.podScheduling, `${nodeName}-k8s-pod-scheduling`, true)}
+                                                  {renderCheckResult(nodeName, 'PVC Provisioning', kubernetesResults.pvcProvisioning, `${nodeName}-k8s-pvc-provisioning`, true)}
+                                                  {renderCheckResult(nodeName, 'Pod Network', kubernetesResults.podNetwork, `${nodeName}-k8s-pod-network`, true)}
+                                                  {renderCheckResult(nodeName, 'LB Health Check', kubernetesResults.lbHealthCheck, `${nodeName}-k8s-lb-health-check`, true)}
                                                   
                                                   {!hasKubernetesResults && (
                                                     <Card style={{ marginTop: '1rem' }}>
                                                       <CardBody>
                                                         <p style={{ color: '#666', fontStyle: 'italic' }}>
-                                                          Nessun check Kubernetes disponibile al momento.
+                                                          No Kubernetes checks available at the moment.
                                                         </p>
                                                       </CardBody>
                                                     </Card>
@@ -1217,7 +1404,7 @@ const NodeCheckOverview: React.FC = () => {
                                                 <Card style={{ marginTop: '1rem' }}>
                                                   <CardBody>
                                                     <p style={{ color: '#666', fontStyle: 'italic' }}>
-                                                      Dati dei check Kubernetes non disponibili.
+                                                      Kubernetes check data not available.
                                                     </p>
                                                   </CardBody>
                                                 </Card>
@@ -22,7 +22,8 @@ export function getProxyURL(path: string): string {
   
   // Se il path non inizia già con "api/v1/", aggiungilo
   // Questo è necessario perché il proxy inoltra tutto il path dopo l'alias
-  const fullPath = cleanPath.startsWith('api/v1/') ? cleanPath : `api/v1/${cleanPath}`;
+  // The /api/v2 endpoints are passed with their prefix
+  const fullPath = cleanPath.startsWith('api/v1/') || cleanPath.startsWith('api/v2/') ? cleanPath : `api/v1/${cleanPath}`;
   
   // Costruisci l'URL completo per il proxy
   // Esempio: /api/proxy/plugin/node-check-console-plugin/api-v1/api/v1/stats
@@ -52,7 +53,15 @@ export async function apiGet<T = any>(path: string, params?: Record<string, stri
       try {
         const errorBody = await response.text();
         if (errorBody) {
-          errorMessage += ` - ${errorBody}`;
+          // API errors are JSON with a message localized from Accept-Language ("error"),
+          // a message key ("code") and its parameters ("params")
+          let apiError: string | undefined;
+          try {
+            apiError = JSON.parse(errorBody).error;
+          } catch (e) {
+            apiError = undefined;
+          }
+          errorMessage += ` - ${apiError || errorBody}`;
         }
       } catch (e) {
         // Ignora errori nel parsing del body
@@ -71,3 +80,201 @@ export async function apiGet<T = any>(path: string, params?: Record<string, stri
   }
 }
 
+/**
+ * Headers of the write requests: the console rejects them through its proxy without the CSRF token
+ * of its cookie
+ */
+function writeHeaders(): Record<string, string> {
+  const csrfToken = document.cookie
+    .split(';')
+    .map((cookie) => cookie.trim())
+    .find((cookie) => cookie.startsWith('csrf-token='))
+    ?.substring('csrf-token='.length);
+  return {
+    'Content-Type': 'application/json',
+    ...(csrfToken ? { 'X-CSRFToken': csrfToken } : {}),
+  };
+}
+
+/**
+ * Updates the configuration of a check of a NodeCheck (PATCH /api/v2/nodechecks/:namespace/:name/checks/:check).
+ * The console proxy forwards the user's token, so the NodeCheck is changed with the user's permissions.
+ */
+export interface CheckConfigUpdate {
+  enabled?: boolean;
+  thresholds?: { warning?: number; critical?: number };
+}
+
+export async function updateCheckConfig(
+  namespace: string,
+  nodeCheckName: string,
+  check: string,
+  update: CheckConfigUpdate,
+): Promise<any> {
+  const url = getProxyURL(
+    `api/v2/nodechecks/${encodeURIComponent(namespace)}/${encodeURIComponent(nodeCheckName)}/checks/${encodeURIComponent(check)}`,
+  );
+
+  const response = await fetch(url, {
+    method: 'PATCH',
+    headers: writeHeaders(),
+    body: JSON.stringify(update),
+  });
+  const body = await response.json().catch(() => ({}));
+  if (!response.ok) {
+    throw new Error(body?.error || `API request failed: ${response.status} ${response.statusText}`);
+  }
+  return body;
+}
+
+/**
+ * Marks the current result of a check of a NodeCheck as a false positive
+ * (POST /api/v2/nodechecks/:namespace/:name/false-positives). check is the check name ("disk_space")
+ * or the path of its result ("systemResults.disks.space"); the values of the result are recorded
+ * with the feedback.
+ */
+export async function markFalsePositive(
+  namespace: string,
+  nodeCheckName: string,
+  check: string,
+  comment?: string,
+): Promise<any> {
+  const url = getProxyURL(
+    `api/v2/nodechecks/${encodeURIComponent(namespace)}/${encodeURIComponent(nodeCheckName)}/false-positives`,
+  );
+  const response = await fetch(url, {
+    method: 'POST',
+    headers: writeHeaders(),
+    body: JSON.stringify({ check, comment }),
+  });
+  const body = await response.json().catch(() => ({}));
+  if (!response.ok) {
+    throw new Error(body?.error || `API request failed: ${response.status} ${response.statusText}`);
+  }
+  return body;
+}
+
+/**
+ * Fleet heatmap served by /api/v1/heatmap: matrix[i][j] is the status code of checks[j] on
+ * nodes[i] and legend[code] its status (0 = the check did not run on the node)
+ */
+export interface HeatmapData {
+  nodes: string[];
+  checks: string[];
+  legend: string[];
+  matrix: number[][];
+  lastUpdate: string;
+}
+
+export async function getHeatmap(namespace?: string): Promise<HeatmapData> {
+  return apiGet<HeatmapData>('heatmap', namespace ? { namespace } : undefined);
+}
+
+/**
+ * Typed check details, mirroring api/v1alpha1/checkdetails_types.go. The other checks have
+ * free-form details.
+ */
+export interface LoadDetails {
+  check_source?: string;
+  uptime?: string;
+  cpu_cores?: number;
+  load_1min: number;
+  load_5min: number;
+  load_15min: number;
+  warning_threshold?: number;
+  critical_threshold?: number;
+  iowait_percent?: number;
+  iowait_jiffies?: number;
+  total_cpu_jiffies?: number;
+}
+
+export interface MemoryDetails {
+  check_source?: string;
+  memory_usage_percent: number;
+  total_memory_bytes?: number;
+  available_memory_bytes?: number;
+  free_memory_bytes?: number;
+  used_memory_bytes?: number;
+  buffers_bytes?: number;
+  cached_bytes?: number;
+  shared_bytes?: number;
+  buff_cache_bytes?: number;
+  free_output?: string;
+  total_memory?: string;
+  used_memory?: string;
+  free_memory?: string;
+  shared_memory?: string;
+  buff_cache?: string;
+  available_memory?: string;
+}
+
+export interface FilesystemUsage {
+  filesystem: string;
+  fs_type: string;
+  size: string;
+  used: string;
+  available: string;
+  use_percent: string;
+  mounted_on: string;
+  size_bytes?: number;
+  used_bytes?: number;
+  available_bytes?: number;
+  used_percent?: number;
+}
+
+export interface DiskSpaceDetails {
+  check_source?: string;
+  df_output?: string;
+  disk_usage?: FilesystemUsage[];
+  disk_usage_map?: Record<string, Record<string, string>>;
+  critical_disks: string[];
+  warning_disks: string[];
+}
+
+export interface NodeResourceUsageDetails {
+  node_name?: string;
+  is_openshift: boolean;
+  cpu_usage?: { cores: string; percent: number; capacity: string; millicores: number; capacity_millicores: number };
+  memory_usage?: { bytes: number; human: string; percent: number; capacity: string; capacity_bytes: number };
+  check_method?: string;
+  note?: string;
+  error?: string;
+}
+
+/**
+ * Console plugin configuration served by /api/v1/uiconfig, from spec.ui of the
+ * NodeCheckOperatorConfig singleton
+ */
+export interface UIConfig {
+  title: string;
+  logoURL?: string;
+  defaultView: 'overview' | 'checks' | 'nodes';
+  features: Record<string, boolean>;
+}
+
+export const DEFAULT_UI_CONFIG: UIConfig = {
+  title: 'Node Check',
+  defaultView: 'overview',
+  features: {},
+};
+
+/**
+ * Loads the plugin configuration. The defaults are returned when it cannot be loaded
+ * (e.g. an older operator without the endpoint), so branding never blocks the page.
+ */
+export async function getUIConfig(): Promise<UIConfig> {
+  try {
+    const config = await apiGet<UIConfig>('uiconfig');
+    return { ...DEFAULT_UI_CONFIG, ...config, features: config?.features || {} };
+  } catch (error) {
+    console.warn('Unable to load the UI configuration, using the defaults:', error);
+    return DEFAULT_UI_CONFIG;
+  }
+}
+
+/**
+ * Features are enabled unless the configuration disables them
+ */
+export function isFeatureEnabled(config: UIConfig, feature: string): boolean {
+  return config.features[feature] !== false;
+}
@@ -20,9 +20,12 @@ import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"k8s.io/client-go/kubernetes"
 
 	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
 )
 
 // ConsolePluginReconciler reconciles ConsolePlugin resources
@@ -31,8 +34,9 @@ type ConsolePluginReconciler struct {
 	client.Client
 	Scheme    *runtime.Scheme
 	Clientset kubernetes.Interface
-	Namespace string
-	Image     string
+	// Config provides the namespace, image, dashboard port and OpenShift feature gate
+	// (NodeCheckOperatorConfig or environment)
+	Config *operatorconfig.Config
 }
 
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
@@ -50,14 +54,21 @@ func (r *ConsolePluginReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	// We reconcile on any NodeCheck change, Deployment/Service changes, or periodically
 	// to ensure resources are up to date
 
+	settings, err := r.Config.Load(ctx, r.Client)
+	if err != nil {
+		log.Error(err, "unable to read the NodeCheckOperatorConfig")
+		return ctrl.Result{}, err
+	}
+	if !settings.OpenShiftFeatures {
+		log.Info("OpenShift-specific features disabled; skipping ConsolePlugin resources")
+		return ctrl.Result{}, nil
+	}
+
 	deploymentName := "node-check-console-plugin"
 	serviceName := "node-check-console-plugin"
 	consolePluginName := "node-check-console-plugin"
-	namespace := r.Namespace
-	image := r.Image
-	if image == "" {
-		image = "quay.io/rh_ee_afilice/node-check-operator-console-plugin:v1.0.8"
-	}
+	namespace := settings.WatchNamespace
+	image := settings.ConsolePluginImage
 
 	// Reconcile Deployment
 	var deployment appsv1.Deployment
@@ -123,7 +134,7 @@ func (r *ConsolePluginReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if err := r.Get(ctx, types.NamespacedName{Name: dashboardServiceName, Namespace: namespace}, &dashboardService); err != nil {
 		if errors.IsNotFound(err) {
 			log.Info("Creating Dashboard Service", "name", dashboardServiceName)
-			dashboardService = r.buildDashboardService(dashboardServiceName, namespace)
+			dashboardService = r.buildDashboardService(dashboardServiceName, namespace, settings.DashboardPort)
 			if err := r.Create(ctx, &dashboardService); err != nil {
 				log.Error(err, "unable to create Dashboard Service")
 				return ctrl.Result{}, err
@@ -135,7 +146,7 @@ func (r *ConsolePluginReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	} else {
 		// Update if needed
-		desiredDashboardService := r.buildDashboardService(dashboardServiceName, namespace)
+		desiredDashboardService := r.buildDashboardService(dashboardServiceName, namespace, settings.DashboardPort)
 		if r.serviceNeedsUpdate(&dashboardService, &desiredDashboardService) {
 			log.Info("Updating Dashboard Service", "name", dashboardServiceName)
 			dashboardService.Spec = desiredDashboardService.Spec
@@ -223,7 +234,8 @@ func (r *ConsolePluginReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		needsUpdate := existingDisplayName != desiredDisplayName ||
 			existingServiceName != desiredServiceName ||
 			existingServiceNamespace != desiredServiceNamespace ||
-			existingServicePort != desiredServicePort
+			existingServicePort != desiredServicePort ||
+			proxyAuthorization(existingCR) != proxyAuthorization(consolePluginCR)
 		
 		if needsUpdate {
 			log.Info("Updating ConsolePlugin CR", "name", consolePluginName)
@@ -398,8 +410,9 @@ func (r *ConsolePluginReconciler) deploymentNeedsUpdate(current, desired *appsv1
 	return false
 }
 
-// buildDashboardService creates a Service spec for the Dashboard API
-func (r *ConsolePluginReconciler) buildDashboardService(name, namespace string) corev1.Service {
+// buildDashboardService creates a Service spec for the Dashboard API. The Service port stays 31682,
+// the port used by the ConsolePlugin proxy, and targets the port the dashboard listens on.
+func (r *ConsolePluginReconciler) buildDashboardService(name, namespace string, port int) corev1.Service {
 	return corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -418,7 +431,7 @@ func (r *ConsolePluginReconciler) buildDashboardService(name, namespace string)
 			Ports: []corev1.ServicePort{
 				{
 					Port:       31682,
-					TargetPort: intstr.FromInt(31682),
+					TargetPort: intstr.FromInt(port),
 					Protocol:   corev1.ProtocolTCP,
 					Name:       "dashboard",
 				},
@@ -438,6 +451,12 @@ func (r *ConsolePluginReconciler) serviceNeedsUpdate(current, desired *corev1.Se
 	if !reflect.DeepEqual(current.Spec.Selector, desired.Spec.Selector) {
 		return true
 	}
+	// Check target ports (the dashboard port can be changed by the NodeCheckOperatorConfig)
+	for i := range desired.Spec.Ports {
+		if current.Spec.Ports[i].TargetPort != desired.Spec.Ports[i].TargetPort {
+			return true
+		}
+	}
 	// Add more comparisons as needed
 	return false
 }
@@ -467,6 +486,8 @@ func (r *ConsolePluginReconciler) buildConsolePluginCR(name, namespace string) *
 		"proxy": []interface{}{
 			map[string]interface{}{
 				"alias": "api-v1",
+				// The console forwards the user's token, so the dashboard API can act as the user
+				"authorization": "UserToken",
 				"endpoint": map[string]interface{}{
 					"type": "Service",
 					"service": map[string]interface{}{
@@ -483,6 +504,21 @@ func (r *ConsolePluginReconciler) buildConsolePluginCR(name, namespace string) *
 	return cr
 }
 
+// proxyAuthorization returns the authorization of the first proxy of a ConsolePlugin CR
+func proxyAuthorization(cr *unstructured.Unstructured) string {
+	proxies, found, err := unstructured.NestedFieldNoCopy(cr.Object, "spec", "proxy")
+	if err != nil || !found {
+		return ""
+	}
+	if list, ok := proxies.([]interface{}); ok && len(list) > 0 {
+		if proxy, ok := list[0].(map[string]interface{}); ok {
+			authorization, _ := proxy["authorization"].(string)
+			return authorization
+		}
+	}
+	return ""
+}
+
 func (r *ConsolePluginReconciler) ensureNamespaceMonitoringLabel(ctx context.Context, namespace string, log logr.Logger) error {
 	var ns corev1.Namespace
 	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
@@ -705,6 +741,8 @@ func (r *ConsolePluginReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&nodecheckv1alpha1.NodeCheck{}).
 		Owns(&appsv1.Deployment{}). // Watch for changes to the console plugin deployment
 		Owns(&corev1.Service{}).     // Watch for changes to the console plugin service
-		Complete(r)
+		// Follow namespace, image, port and feature gate changes of the NodeCheckOperatorConfig
+		Watches(&nodecheckv1alpha1.NodeCheckOperatorConfig{}, &handler.EnqueueRequestForObject{}).
+		Complete(selfstatus.Track("ConsolePlugin", r))
 }
 
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -14,9 +17,13 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"k8s.io/client-go/kubernetes"
 
 	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/faults"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
 )
 
 // ExecutorDaemonSetReconciler reconciles DaemonSet for NodeCheck executors
@@ -25,13 +32,14 @@ type ExecutorDaemonSetReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Clientset kubernetes.Interface
-	Namespace string
-	Image     string
+	// Config provides the namespace and image of the DaemonSet (NodeCheckOperatorConfig or environment)
+	Config *operatorconfig.Config
 }
 
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 //+kubebuilder:rbac:groups=nodecheck.openshift.io,resources=nodechecks,verbs=get;list;watch
+//+kubebuilder:rbac:groups=nodecheck.openshift.io,resources=nodecheckoperatorconfigs,verbs=get;list;watch
 
 // Reconcile ensures the executor DaemonSet exists when NodeChecks are present
 func (r *ExecutorDaemonSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -54,18 +62,30 @@ func (r *ExecutorDaemonSetReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		}
 	}
 
+	settings, err := r.Config.Load(ctx, r.Client)
+	if err != nil {
+		log.Error(err, "unable to read the NodeCheckOperatorConfig")
+		return ctrl.Result{}, err
+	}
+
 	daemonSetName := "node-check-executor"
-	daemonSetNamespace := r.Namespace
+	daemonSetNamespace := settings.WatchNamespace
+
+	// The watch namespace can change at runtime: remove the executors left in the previous one
+	if err := r.deleteStaleDaemonSets(ctx, daemonSetName, daemonSetNamespace); err != nil {
+		log.Error(err, "unable to delete the executor DaemonSet of the previous namespace")
+		return ctrl.Result{}, err
+	}
 
 	var daemonSet appsv1.DaemonSet
-	err := r.Get(ctx, types.NamespacedName{Name: daemonSetName, Namespace: daemonSetNamespace}, &daemonSet)
+	err = r.Get(ctx, types.NamespacedName{Name: daemonSetName, Namespace: daemonSetNamespace}, &daemonSet)
 
 	if hasActiveNodeChecks {
 		// DaemonSet should exist
 		if errors.IsNotFound(err) {
 			// Create DaemonSet
 			log.Info("Creating executor DaemonSet", "name", daemonSetName)
-			daemonSet = r.buildDaemonSet(daemonSetName, daemonSetNamespace, &nodeChecks)
+			daemonSet = r.buildDaemonSet(daemonSetName, daemonSetNamespace, settings.OperatorImage, settings.FaultInjection, &nodeChecks)
 			if err := r.Create(ctx, &daemonSet); err != nil {
 				log.Error(err, "unable to create DaemonSet")
 				return ctrl.Result{}, err
@@ -77,7 +97,7 @@ func (r *ExecutorDaemonSetReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			return ctrl.Result{}, err
 		}
 		// DaemonSet exists, ensure it's up to date
-		desiredDaemonSet := r.buildDaemonSet(daemonSetName, daemonSetNamespace, &nodeChecks)
+		desiredDaemonSet := r.buildDaemonSet(daemonSetName, daemonSetNamespace, settings.OperatorImage, settings.FaultInjection, &nodeChecks)
 		if r.daemonSetNeedsUpdate(&daemonSet, &desiredDaemonSet) {
 			log.Info("Updating executor DaemonSet", "name", daemonSetName)
 			daemonSet.Spec = desiredDaemonSet.Spec
@@ -107,19 +127,79 @@ func (r *ExecutorDaemonSetReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	return ctrl.Result{}, nil
 }
 
-// buildDaemonSet creates a DaemonSet spec for the executor
-func (r *ExecutorDaemonSetReconciler) buildDaemonSet(name, namespace string, nodeChecks *nodecheckv1alpha1.NodeCheckList) appsv1.DaemonSet {
-	image := r.Image
-	if image == "" {
-		image = "quay.io/rh_ee_afilice/node-check-operator:v1.0.8"
+// deleteStaleDaemonSets deletes the executor DaemonSets outside of the current namespace
+func (r *ExecutorDaemonSetReconciler) deleteStaleDaemonSets(ctx context.Context, name, namespace string) error {
+	var daemonSets appsv1.DaemonSetList
+	if err := r.List(ctx, &daemonSets, client.MatchingLabels{"app": "node-check-executor"}); err != nil {
+		return err
 	}
+	for i := range daemonSets.Items {
+		daemonSet := &daemonSets.Items[i]
+		if daemonSet.Name != name || daemonSet.Namespace == namespace {
+			continue
+		}
+		ctrl.Log.WithName("ExecutorDaemonSetReconciler").Info("Deleting executor DaemonSet of the previous namespace", "namespace", daemonSet.Namespace)
+		if err := r.Delete(ctx, daemonSet); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildDaemonSet creates a DaemonSet spec for the executor
+func (r *ExecutorDaemonSetReconciler) buildDaemonSet(name, namespace, image string, faultInjection bool, nodeChecks *nodecheckv1alpha1.NodeCheckList) appsv1.DaemonSet {
 
 	// Collect NodeSelector and Tolerations from all NodeChecks
 	// Merge node selectors (all must match)
 	mergedNodeSelector := make(map[string]string)
 	mergedTolerations := make(map[string]corev1.Toleration)
-	
-	for _, nc := range nodeChecks.Items {
+	resources := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		},
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+	}
+	priorityClassName := ""
+
+	// Process NodeChecks by namespace/name so that the first one setting an executorConfig
+	// override wins deterministically
+	items := make([]nodecheckv1alpha1.NodeCheck, len(nodeChecks.Items))
+	copy(items, nodeChecks.Items)
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Namespace != items[j].Namespace {
+			return items[i].Namespace < items[j].Namespace
+		}
+		return items[i].Name < items[j].Name
+	})
+
+	imageOverridden, resourcesOverridden := false, false
+	for _, nc := range items {
+		// executorConfig applies to every NodeCheck, templates included
+		if cfg := nc.Spec.ExecutorConfig; cfg != nil {
+			for _, tol := range cfg.Tolerations {
+				key := fmt.Sprintf("%s:%s:%s", tol.Key, tol.Operator, tol.Effect)
+				mergedTolerations[key] = tol
+			}
+			for k, v := range cfg.NodeSelector {
+				mergedNodeSelector[k] = v
+			}
+			if cfg.Resources != nil && !resourcesOverridden {
+				resources = *cfg.Resources.DeepCopy()
+				resourcesOverridden = true
+			}
+			if cfg.PriorityClassName != "" && priorityClassName == "" {
+				priorityClassName = cfg.PriorityClassName
+			}
+			if cfg.Image != "" && !imageOverridden {
+				image = cfg.Image
+				imageOverridden = true
+			}
+		}
+
 		// For template NodeChecks (nodeName="*" or "all"), only use Tolerations (not NodeSelector)
 		// NodeSelector is used to filter which nodes get child NodeChecks, not for DaemonSet scheduling
 		if nc.Spec.NodeName == "*" || nc.Spec.NodeName == "all" {
@@ -149,6 +229,11 @@ func (r *ExecutorDaemonSetReconciler) buildDaemonSet(name, namespace string, nod
 	for _, tol := range mergedTolerations {
 		tolerationsList = append(tolerationsList, tol)
 	}
+	// Keep a stable order so daemonSetNeedsUpdate does not see a change on every reconcile
+	sort.Slice(tolerationsList, func(i, j int) bool {
+		return fmt.Sprintf("%s:%s:%s", tolerationsList[i].Key, tolerationsList[i].Operator, tolerationsList[i].Effect) <
+			fmt.Sprintf("%s:%s:%s", tolerationsList[j].Key, tolerationsList[j].Operator, tolerationsList[j].Effect)
+	})
 
 	daemonSet := appsv1.DaemonSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -175,6 +260,7 @@ func (r *ExecutorDaemonSetReconciler) buildDaemonSet(name, namespace string, nod
 					HostNetwork:        true,
 					NodeSelector:       mergedNodeSelector,
 					Tolerations:        tolerationsList,
+					PriorityClassName:  priorityClassName,
 					Volumes: []corev1.Volume{
 						{
 							Name: "host-proc",
@@ -247,6 +333,10 @@ func (r *ExecutorDaemonSetReconciler) buildDaemonSet(name, namespace string, nod
 										},
 									},
 								},
+								{
+									Name:  faults.EnvVar,
+									Value: strconv.FormatBool(faultInjection),
+								},
 							},
 							VolumeMounts: []corev1.VolumeMount{
 								{
@@ -279,16 +369,7 @@ func (r *ExecutorDaemonSetReconciler) buildDaemonSet(name, namespace string, nod
 							// require those host ports to be free on the node, causing "didn't have free
 							// ports for the requested pod ports". The executor can still listen on 8080/8081
 							// (or configurable ports) without declaring them here.
-							Resources: corev1.ResourceRequirements{
-								Limits: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("500m"),
-									corev1.ResourceMemory: resource.MustParse("128Mi"),
-								},
-								Requests: corev1.ResourceList{
-									corev1.ResourceCPU:    resource.MustParse("10m"),
-									corev1.ResourceMemory: resource.MustParse("64Mi"),
-								},
-							},
+							Resources: resources,
 							SecurityContext: &corev1.SecurityContext{
 								AllowPrivilegeEscalation: func() *bool { b := true; return &b }(),
 								Privileged:               func() *bool { b := true; return &b }(),
@@ -342,13 +423,45 @@ func (r *ExecutorDaemonSetReconciler) daemonSetNeedsUpdate(current, desired *app
 		return true
 	}
 	
+	// Check PriorityClassName
+	if current.Spec.Template.Spec.PriorityClassName != desired.Spec.Template.Spec.PriorityClassName {
+		return true
+	}
+	
+	// Check the fault injection gate
+	if containerEnv(current, faults.EnvVar) != containerEnv(desired, faults.EnvVar) {
+		return true
+	}
+	
+	// Check Resources (semantic comparison: quantities read back from the API server are normalized)
+	if len(current.Spec.Template.Spec.Containers) > 0 && len(desired.Spec.Template.Spec.Containers) > 0 {
+		if !equality.Semantic.DeepEqual(current.Spec.Template.Spec.Containers[0].Resources, desired.Spec.Template.Spec.Containers[0].Resources) {
+			return true
+		}
+	}
+	
 	return false
 }
 
+// containerEnv returns the value of an environment variable of the executor container
+func containerEnv(daemonSet *appsv1.DaemonSet, name string) string {
+	if len(daemonSet.Spec.Template.Spec.Containers) == 0 {
+		return ""
+	}
+	for _, env := range daemonSet.Spec.Template.Spec.Containers[0].Env {
+		if env.Name == name {
+			return env.Value
+		}
+	}
+	return ""
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ExecutorDaemonSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nodecheckv1alpha1.NodeCheck{}).
-		Complete(r)
+		// Follow namespace and image changes of the NodeCheckOperatorConfig
+		Watches(&nodecheckv1alpha1.NodeCheckOperatorConfig{}, &handler.EnqueueRequestForObject{}).
+		Complete(selfstatus.Track("ExecutorDaemonSet", r))
 }
 
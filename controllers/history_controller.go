@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/history"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+)
+
+// HistoryReconciler copies status.history of the NodeChecks into the history store of spec.history of the
+// NodeCheckOperatorConfig, which keeps it beyond spec.historySize for the trend endpoints of the dashboard.
+// The store is recreated when spec.history changes; the NodeChecks without history are not recorded.
+type HistoryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// Reconcile records the history of a NodeCheck
+func (r *HistoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("HistoryReconciler")
+
+	var spec *nodecheckv1alpha1.HistoryConfig
+	var config nodecheckv1alpha1.NodeCheckOperatorConfig
+	if err := r.Get(ctx, client.ObjectKey{Name: nodecheckv1alpha1.NodeCheckOperatorConfigName}, &config); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	} else {
+		spec = config.Spec.History
+	}
+	changed, err := history.Configure(spec)
+	if err != nil {
+		selfstatus.SetComponent("history-store", selfstatus.StatusFailed, err.Error())
+		return ctrl.Result{}, err
+	}
+	store := history.Current()
+	if store == nil {
+		selfstatus.SetComponent("history-store", selfstatus.StatusNotConfigured, "history kept in status.history only")
+		return ctrl.Result{}, nil
+	}
+	if changed {
+		log.Info("History store configured", "backend", store.Backend())
+	}
+
+	key := history.Key{Namespace: req.Namespace, Name: req.Name}
+	var nodeCheck nodecheckv1alpha1.NodeCheck
+	if err := r.Get(ctx, req.NamespacedName, &nodeCheck); err != nil {
+		if errors.IsNotFound(err) {
+			store.Forget(key)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	// Parent NodeChecks ("*", "all") have no results of their own
+	nodeName := nodeCheck.Status.NodeName
+	if nodeName == "" || nodeName == "*" || nodeName == "all" || len(nodeCheck.Status.History) == 0 {
+		return ctrl.Result{}, nil
+	}
+	if err := store.Record(ctx, key, nodeName, history.StatusEntries(nodeCheck.Status.History)); err != nil {
+		selfstatus.SetComponent("history-store", selfstatus.StatusDegraded, err.Error())
+		log.Error(err, "unable to record the history", "nodecheck", req.NamespacedName)
+		return ctrl.Result{}, err
+	}
+	selfstatus.SetComponent("history-store", selfstatus.StatusOK, fmt.Sprintf("%s backend", store.Backend()))
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HistoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("history").
+		For(&nodecheckv1alpha1.NodeCheck{}).
+		Complete(selfstatus.Track("History", r))
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/kafkaexport"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+)
+
+// kafkaExportDestination is the destination name of the Kafka export in the result deliveries
+const kafkaExportDestination = "kafka"
+
+// KafkaExportReconciler streams the check results of the NodeChecks to the Kafka topic of
+// spec.kafkaExport of the NodeCheckOperatorConfig: whenever a NodeCheck is updated, every result that
+// ran since the previous delivery is produced as a record. A failed delivery is retried with the
+// results that ran since; after a restart of the operator the latest results are produced again.
+type KafkaExportReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Clientset kubernetes.Interface
+	Config    *operatorconfig.Config
+
+	deliveries resultDeliveries
+}
+
+// Reconcile produces the new results of a NodeCheck
+func (r *KafkaExportReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("KafkaExportReconciler")
+
+	var config nodecheckv1alpha1.NodeCheckOperatorConfig
+	if err := r.Get(ctx, client.ObjectKey{Name: nodecheckv1alpha1.NodeCheckOperatorConfigName}, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	spec := config.Spec.KafkaExport
+	if spec == nil {
+		return ctrl.Result{}, nil
+	}
+
+	var nodeCheck nodecheckv1alpha1.NodeCheck
+	if err := r.Get(ctx, req.NamespacedName, &nodeCheck); err != nil {
+		if errors.IsNotFound(err) {
+			r.deliveries.forget(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// Parent NodeChecks ("*", "all") have no results of their own
+	nodeName := nodeCheck.Status.NodeName
+	if nodeName == "" || nodeName == "*" || nodeName == "all" {
+		return ctrl.Result{}, nil
+	}
+
+	pending := make(map[string]nodecheckv1alpha1.CheckResult)
+	var records []kafkaexport.Record
+	for category, results := range resultsByCategory(nodeCheck.Status.CheckResults) {
+		for name, result := range r.deliveries.pending(kafkaExportDestination, req.NamespacedName, results) {
+			pending[name] = result
+			records = append(records, kafkaexport.NewRecord(&nodeCheck, category, name, result))
+		}
+	}
+	if len(records) == 0 {
+		return ctrl.Result{}, nil
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Check < records[j].Check })
+
+	var authorization string
+	if spec.CredentialsSecret != "" {
+		settings, err := r.Config.Load(ctx, r.Client)
+		if err != nil {
+			log.Error(err, "unable to read the NodeCheckOperatorConfig, using the namespace of the environment")
+		}
+		secret, err := r.Clientset.CoreV1().Secrets(settings.WatchNamespace).Get(ctx, spec.CredentialsSecret, metav1.GetOptions{})
+		if err != nil {
+			log.Error(err, "unable to read the Kafka bridge credentials", "secret", spec.CredentialsSecret)
+			return ctrl.Result{}, err
+		}
+		authorization = strings.TrimSpace(string(secret.Data["authorization"]))
+	}
+
+	if err := kafkaexport.Produce(ctx, spec.BridgeURL, spec.Topic, authorization, spec.Serialization, spec.SchemaID, records); err != nil {
+		log.Error(err, "unable to produce the check results", "topic", spec.Topic, "nodeCheck", req.NamespacedName, "records", len(records))
+		return ctrl.Result{}, err
+	}
+	r.deliveries.record(kafkaExportDestination, req.NamespacedName, pending)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *KafkaExportReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("kafkaexport").
+		For(&nodecheckv1alpha1.NodeCheck{}).
+		Complete(selfstatus.Track("KafkaExport", r))
+}
@@ -11,10 +11,14 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/api/core/v1"
 
 	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+	"github.com/albertofilice/node-check-operator/pkg/trigger"
+	"github.com/albertofilice/node-check-operator/pkg/verification"
 )
 
 // NodeCheckReconciler reconciles a NodeCheck object
@@ -48,9 +52,32 @@ func (r *NodeCheckReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	}
 
 	nodeName := nodeCheck.Spec.NodeName
+	allNodes := nodeName == "*" || nodeName == "all"
+
+	// Deleting a "*" NodeCheck (or switching it to a single node) removes its children and their metrics
+	if controllerutil.ContainsFinalizer(&nodeCheck, CleanupFinalizer) && (!nodeCheck.DeletionTimestamp.IsZero() || !allNodes) {
+		return r.finalizeNodeCheck(ctx, &nodeCheck)
+	}
+	if !nodeCheck.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	// Post-maintenance verifications run in a one-shot NodeCheck evaluated here once it has run
+	if verifiedName := nodeCheck.Labels[verification.Label]; verifiedName != "" {
+		return r.evaluateVerification(ctx, &nodeCheck, verifiedName)
+	}
+	if verification.Requested(nodeCheck.Annotations) && !allNodes {
+		return r.startVerification(ctx, &nodeCheck)
+	}
 	
 	// If nodeName is "*" or "all", create/update child NodeChecks for each matching node
-	if nodeName == "*" || nodeName == "all" {
+	if allNodes {
+		if controllerutil.AddFinalizer(&nodeCheck, CleanupFinalizer) {
+			if err := r.Update(ctx, &nodeCheck); err != nil {
+				log.Error(err, "unable to add cleanup finalizer", "nodeCheck", req.Name)
+				return ctrl.Result{}, err
+			}
+		}
 		log.Info("Processing NodeCheck with nodeName='*' - creating/updating child resources", "nodeCheck", req.Name)
 		return r.reconcileAllNodesMode(ctx, req, nodeCheck)
 	}
@@ -110,21 +137,17 @@ func (r *NodeCheckReconciler) reconcileAllNodesMode(ctx context.Context, req ctr
 	}
 	
 	// Find and delete child NodeChecks for nodes that no longer match the selector
-	var existingChildNodeChecks nodecheckv1alpha1.NodeCheckList
 	// We identify child NodeChecks by name prefix (format: templateName-nodeName)
-	if err := r.Client.List(ctx, &existingChildNodeChecks, client.InNamespace(req.Namespace)); err == nil {
-		for _, child := range existingChildNodeChecks.Items {
-			// Check if this is a child of the current template (name starts with template name + "-")
-			if len(child.Name) > len(req.Name)+1 && child.Name[:len(req.Name)+1] == req.Name+"-" {
-				// Extract node name from child name (format: templateName-nodeName)
-				childNodeName := child.Name[len(req.Name)+1:]
-				// If this node is not in the matching set, delete the child NodeCheck
-				if !matchingNodeNames[childNodeName] {
-					log.Info("Deleting child NodeCheck for node that no longer matches selector", 
-						"childNodeCheckName", child.Name, "nodeName", childNodeName)
-					if err := r.Delete(ctx, &child); err != nil {
-						log.Error(err, "unable to delete orphaned child NodeCheck", "childNodeCheckName", child.Name)
-					}
+	if existingChildNodeChecks, err := r.listChildNodeChecks(ctx, &templateNodeCheck); err == nil {
+		for i := range existingChildNodeChecks {
+			child := &existingChildNodeChecks[i]
+			nodeName := childNodeName(req.Name, child.Name)
+			// If this node is not in the matching set, delete the child NodeCheck
+			if !matchingNodeNames[nodeName] {
+				log.Info("Deleting child NodeCheck for node that no longer matches selector", 
+					"childNodeCheckName", child.Name, "nodeName", nodeName)
+				if err := r.deleteChildNodeCheck(ctx, child, nodeName); err != nil {
+					log.Error(err, "unable to delete orphaned child NodeCheck", "childNodeCheckName", child.Name)
 				}
 			}
 		}
@@ -146,11 +169,22 @@ func (r *NodeCheckReconciler) reconcileAllNodesMode(ctx context.Context, req ctr
 			childNodeCheck.Name = childNodeCheckName
 			childNodeCheck.ResourceVersion = ""
 			childNodeCheck.UID = ""
+			// The owner of the parent (e.g. a NodeCheckTemplate) manages the parent only
+			childNodeCheck.OwnerReferences = nil
 			childNodeCheck.Spec.NodeName = nodeName
 			// Remove NodeSelector from child (it's already for a specific node)
 			childNodeCheck.Spec.NodeSelector = nil
 			// Keep Tolerations (they may be needed for the DaemonSet)
 			childNodeCheck.Status = nodecheckv1alpha1.NodeCheckStatus{}
+			// The overrides belong to each child, they are not inherited
+			if _, ok := childNodeCheck.Annotations[OverridesAnnotation]; ok {
+				childNodeCheck.Annotations = make(map[string]string, len(templateNodeCheck.Annotations))
+				for key, value := range templateNodeCheck.Annotations {
+					if key != OverridesAnnotation {
+						childNodeCheck.Annotations[key] = value
+					}
+				}
+			}
 			
 			if err := r.Create(ctx, &childNodeCheck); err != nil {
 				log.Error(err, "unable to create child NodeCheck", "childNodeCheckName", childNodeCheckName)
@@ -160,36 +194,17 @@ func (r *NodeCheckReconciler) reconcileAllNodesMode(ctx context.Context, req ctr
 			continue
 		}
 		
-		// Child exists, check if we need to sync the spec from the template
-		needsUpdate := false
-		if childNodeCheck.Spec.CheckInterval != templateNodeCheck.Spec.CheckInterval {
-			childNodeCheck.Spec.CheckInterval = templateNodeCheck.Spec.CheckInterval
-			needsUpdate = true
-		}
-		if !r.specsEqual(childNodeCheck.Spec.SystemChecks, templateNodeCheck.Spec.SystemChecks) {
-			childNodeCheck.Spec.SystemChecks = templateNodeCheck.Spec.SystemChecks
-			needsUpdate = true
-		}
-		if !r.kubernetesChecksEqual(childNodeCheck.Spec.KubernetesChecks, templateNodeCheck.Spec.KubernetesChecks) {
-			childNodeCheck.Spec.KubernetesChecks = templateNodeCheck.Spec.KubernetesChecks
-			needsUpdate = true
-		}
-		if childNodeCheck.Spec.NodeName != nodeName {
-			childNodeCheck.Spec.NodeName = nodeName
-			needsUpdate = true
-		}
-		// Sync Tolerations from template (NodeSelector is not synced as child is for specific node)
-		if !reflect.DeepEqual(childNodeCheck.Spec.Tolerations, templateNodeCheck.Spec.Tolerations) {
-			childNodeCheck.Spec.Tolerations = templateNodeCheck.Spec.Tolerations
-			needsUpdate = true
-		}
-		// Ensure NodeSelector is nil for child (it's for a specific node)
-		if len(childNodeCheck.Spec.NodeSelector) > 0 {
-			childNodeCheck.Spec.NodeSelector = nil
-			needsUpdate = true
+		// Child exists: its spec is the parent spec with the child's own overrides applied, so changes to
+		// the parent propagate without clobbering the per-node overrides
+		desiredSpec, err := childNodeCheckSpec(&templateNodeCheck, &childNodeCheck, nodeName)
+		if err != nil {
+			log.Error(err, "unable to sync child NodeCheck, fix or remove its overrides annotation", 
+				"childNodeCheckName", childNodeCheckName, "annotation", OverridesAnnotation)
+			continue
 		}
 		
-		if needsUpdate {
+		if !reflect.DeepEqual(childNodeCheck.Spec, desiredSpec) {
+			childNodeCheck.Spec = desiredSpec
 			// Update with retry logic for conflict errors
 			maxRetries := 3
 			updateSuccess := false
@@ -204,25 +219,14 @@ func (r *NodeCheckReconciler) reconcileAllNodesMode(ctx context.Context, req ctr
 								log.Error(err, "unable to fetch child NodeCheck for retry")
 								break
 							}
-							// Re-apply spec changes
-							if childNodeCheck.Spec.CheckInterval != templateNodeCheck.Spec.CheckInterval {
-								childNodeCheck.Spec.CheckInterval = templateNodeCheck.Spec.CheckInterval
-							}
-							if !r.specsEqual(childNodeCheck.Spec.SystemChecks, templateNodeCheck.Spec.SystemChecks) {
-								childNodeCheck.Spec.SystemChecks = templateNodeCheck.Spec.SystemChecks
-							}
-							if !r.kubernetesChecksEqual(childNodeCheck.Spec.KubernetesChecks, templateNodeCheck.Spec.KubernetesChecks) {
-								childNodeCheck.Spec.KubernetesChecks = templateNodeCheck.Spec.KubernetesChecks
-							}
-							if childNodeCheck.Spec.NodeName != nodeName {
-								childNodeCheck.Spec.NodeName = nodeName
-							}
-							if !reflect.DeepEqual(childNodeCheck.Spec.Tolerations, templateNodeCheck.Spec.Tolerations) {
-								childNodeCheck.Spec.Tolerations = templateNodeCheck.Spec.Tolerations
-							}
-							if len(childNodeCheck.Spec.NodeSelector) > 0 {
-								childNodeCheck.Spec.NodeSelector = nil
+							// Re-apply the spec, the overrides may have changed in the meantime
+							desiredSpec, err := childNodeCheckSpec(&templateNodeCheck, &childNodeCheck, nodeName)
+							if err != nil {
+								log.Error(err, "unable to sync child NodeCheck, fix or remove its overrides annotation", 
+									"childNodeCheckName", childNodeCheckName, "annotation", OverridesAnnotation)
+								break
 							}
+							childNodeCheck.Spec = desiredSpec
 							time.Sleep(time.Millisecond * 100 * time.Duration(i+1))
 							continue
 						}
@@ -238,24 +242,33 @@ func (r *NodeCheckReconciler) reconcileAllNodesMode(ctx context.Context, req ctr
 				log.Info("Updated child NodeCheck spec from template", "childNodeCheckName", childNodeCheckName, "node", nodeName)
 			}
 		}
+
+		// A trigger of the parent runs the checks of every node
+		if err := r.propagateTrigger(ctx, &templateNodeCheck, &childNodeCheck); err != nil {
+			log.Error(err, "unable to trigger child NodeCheck", "childNodeCheckName", childNodeCheckName)
+		}
 	}
 	
 	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
 }
 
-// specsEqual compares two SystemChecks structs for equality
-func (r *NodeCheckReconciler) specsEqual(a, b nodecheckv1alpha1.SystemChecks) bool {
-	return reflect.DeepEqual(a, b)
-}
-
-// kubernetesChecksEqual compares two KubernetesChecks structs for equality
-func (r *NodeCheckReconciler) kubernetesChecksEqual(a, b nodecheckv1alpha1.KubernetesChecks) bool {
-	return reflect.DeepEqual(a, b)
+// propagateTrigger copies the trigger annotation of a "*" NodeCheck to one of its children
+func (r *NodeCheckReconciler) propagateTrigger(ctx context.Context, parent, child *nodecheckv1alpha1.NodeCheck) error {
+	value, ok := parent.Annotations[trigger.Annotation]
+	if !ok || child.Annotations[trigger.Annotation] == value {
+		return nil
+	}
+	patch := client.MergeFrom(child.DeepCopy())
+	if child.Annotations == nil {
+		child.Annotations = make(map[string]string)
+	}
+	child.Annotations[trigger.Annotation] = value
+	return r.Patch(ctx, child, patch)
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *NodeCheckReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&nodecheckv1alpha1.NodeCheck{}).
-		Complete(r)
+		Complete(selfstatus.Track("NodeCheck", r))
 }
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/checks"
+)
+
+// Aggregation policies for spec.aggregationPolicy
+const (
+	AggregationWorst        = "Worst"
+	AggregationWeighted     = "Weighted"
+	AggregationQuorum       = "Quorum"
+	AggregationCriticalOnly = "CriticalOnly"
+)
+
+// Thresholds (percent of the total weight, or of the number of checks for Quorum) used by the
+// Weighted and Quorum policies. Suppressed and Unknown results are not counted.
+const (
+	weightedWarningPercent  = 10
+	weightedCriticalPercent = 25
+	quorumWarningPercent    = 50
+	quorumCriticalPercent   = 50
+)
+
+// aggregateOverallStatus computes the overall status of a node from its check results according to
+// spec.aggregationPolicy. healthyMessage is used as the message when the node is Healthy.
+func aggregateOverallStatus(spec *nodecheckv1alpha1.NodeCheckSpec, systemResults, kubernetesResults map[string]nodecheckv1alpha1.CheckResult, healthyMessage string) (string, string) {
+	switch spec.AggregationPolicy {
+	case AggregationWeighted:
+		return aggregateByShare(systemResults, kubernetesResults, spec.CheckWeights, weightedWarningPercent, weightedCriticalPercent, "of the check weight", healthyMessage)
+	case AggregationQuorum:
+		return aggregateByShare(systemResults, kubernetesResults, nil, quorumWarningPercent, quorumCriticalPercent, "of the checks", healthyMessage)
+	case AggregationCriticalOnly:
+		return aggregateCriticalOnly(systemResults, kubernetesResults, healthyMessage)
+	default:
+		return aggregateWorst(systemResults, kubernetesResults, healthyMessage)
+	}
+}
+
+// aggregateWorst reports the worst status of any check: a single Warning makes the node Warning.
+// A check that cannot parse the output of its tool (NotSupported) counts as a Warning, so a tool
+// version unknown to the parsers is not hidden behind a Healthy node.
+func aggregateWorst(systemResults, kubernetesResults map[string]nodecheckv1alpha1.CheckResult, healthyMessage string) (string, string) {
+	overallStatus := "Healthy"
+	overallMessage := healthyMessage
+
+	// Check for any critical or warning statuses from system checks
+	for _, result := range systemResults {
+		if result.Status == "Critical" {
+			overallStatus = "Critical"
+			overallMessage = result.Message
+			break
+		} else if (result.Status == "Warning" || result.Status == checks.StatusNotSupported) && overallStatus == "Healthy" {
+			overallStatus = "Warning"
+			overallMessage = result.Message
+		}
+	}
+
+	// Check for any critical or warning statuses from Kubernetes checks
+	for _, result := range kubernetesResults {
+		if result.Status == "Critical" {
+			overallStatus = "Critical"
+			overallMessage = result.Message
+			break
+		} else if (result.Status == "Warning" || result.Status == checks.StatusNotSupported) && overallStatus == "Healthy" {
+			overallStatus = "Warning"
+			overallMessage = result.Message
+		}
+	}
+	return overallStatus, overallMessage
+}
+
+// aggregateCriticalOnly reports Critical if any check is Critical and Healthy otherwise;
+// Warnings are mentioned in the message but do not degrade the node
+func aggregateCriticalOnly(systemResults, kubernetesResults map[string]nodecheckv1alpha1.CheckResult, healthyMessage string) (string, string) {
+	critical, warning := []string{}, []string{}
+	for _, results := range []map[string]nodecheckv1alpha1.CheckResult{systemResults, kubernetesResults} {
+		for name, result := range results {
+			switch result.Status {
+			case "Critical":
+				critical = append(critical, name)
+			case "Warning":
+				warning = append(warning, name)
+			}
+		}
+	}
+	sort.Strings(critical)
+	sort.Strings(warning)
+
+	if len(critical) > 0 {
+		return "Critical", fmt.Sprintf("Critical checks: %s", strings.Join(critical, ", "))
+	}
+	if len(warning) > 0 {
+		return "Healthy", fmt.Sprintf("%s (%d checks in Warning ignored by CriticalOnly policy: %s)", healthyMessage, len(warning), strings.Join(warning, ", "))
+	}
+	return "Healthy", healthyMessage
+}
+
+// aggregateByShare reports Warning or Critical when the non-healthy (or Critical) checks reach the given
+// percentage of the total weight. Checks default to weight 1; weight 0 excludes a check. A nil weights
+// map counts every check once.
+func aggregateByShare(systemResults, kubernetesResults map[string]nodecheckv1alpha1.CheckResult, weights map[string]int, warningPercent, criticalPercent int, unit, healthyMessage string) (string, string) {
+	total, warningWeight, criticalWeight := 0, 0, 0
+	nonHealthy := []string{}
+	for _, results := range []map[string]nodecheckv1alpha1.CheckResult{systemResults, kubernetesResults} {
+		for name, result := range results {
+			weight := 1
+			if w, ok := weights[name]; ok {
+				weight = w
+			}
+			if weight <= 0 {
+				continue
+			}
+			switch result.Status {
+			case "Healthy":
+				total += weight
+			case "Warning":
+				total += weight
+				warningWeight += weight
+				nonHealthy = append(nonHealthy, fmt.Sprintf("%s=%s", name, result.Status))
+			case "Critical":
+				total += weight
+				criticalWeight += weight
+				nonHealthy = append(nonHealthy, fmt.Sprintf("%s=%s", name, result.Status))
+			}
+		}
+	}
+	if total == 0 || len(nonHealthy) == 0 {
+		return "Healthy", healthyMessage
+	}
+	sort.Strings(nonHealthy)
+
+	criticalShare := criticalWeight * 100 / total
+	nonHealthyShare := (warningWeight + criticalWeight) * 100 / total
+	summary := fmt.Sprintf("%d%% %s non-healthy, %d%% critical (%s)", nonHealthyShare, unit, criticalShare, strings.Join(nonHealthy, ", "))
+	switch {
+	case criticalWeight > 0 && criticalShare >= criticalPercent:
+		return "Critical", summary
+	case nonHealthyShare >= warningPercent:
+		return "Warning", summary
+	default:
+		return "Healthy", fmt.Sprintf("%s (below thresholds: %s)", healthyMessage, summary)
+	}
+}
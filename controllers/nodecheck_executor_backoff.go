@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionDegraded is set on a NodeCheck while one or more checks are backed off
+// because they keep failing with the same environmental error
+const ConditionDegraded = "Degraded"
+
+const (
+	// backoffFailureThreshold is the number of consecutive runs failing with the same
+	// environmental error after which a check is backed off
+	backoffFailureThreshold = 3
+
+	// backoffMaxMultiplier caps the backoff at this many times the check's normal interval
+	backoffMaxMultiplier = 8
+)
+
+// environmentalErrors maps message fragments to the environmental error they indicate.
+// These errors cannot be fixed by re-running the check, only by changing the node or the executor.
+var environmentalErrors = []struct {
+	fragment string
+	reason   string
+}{
+	{"executable file not found", "tool missing"},
+	{"command not found", "tool missing"},
+	{"not installed", "tool missing"},
+	{"not available", "tool missing"},
+	{"no such file or directory", "tool missing"},
+	{"permission denied", "permission denied"},
+	{"operation not permitted", "permission denied"},
+	{"forbidden", "permission denied"},
+	{"cannot access", "permission denied"},
+}
+
+// environmentalError returns the environmental error behind a non-healthy result, or "" if there is none
+func environmentalError(result *nodecheckv1alpha1.CheckResult) string {
+	if result.Status == "Healthy" {
+		return ""
+	}
+	message := strings.ToLower(result.Message)
+	for _, e := range environmentalErrors {
+		if strings.Contains(message, e.fragment) {
+			return e.reason
+		}
+	}
+	return ""
+}
+
+// checkBackoffState tracks the consecutive environmental failures of a single check
+type checkBackoffState struct {
+	reason   string
+	failures int
+	nextRun  time.Time
+}
+
+// checkBackoff is a circuit breaker for checks that keep failing with the same environmental
+// error (tool missing, permission denied). Once a check reaches backoffFailureThreshold
+// consecutive failures it is run less and less often, up to backoffMaxMultiplier times its interval.
+// The state lives in the executor's memory and is reset when the executor restarts.
+type checkBackoff struct {
+	mu     sync.Mutex
+	checks map[string]map[string]*checkBackoffState
+}
+
+// newCheckBackoff creates an empty backoff tracker
+func newCheckBackoff() *checkBackoff {
+	return &checkBackoff{checks: make(map[string]map[string]*checkBackoffState)}
+}
+
+// skip reports whether a backed-off check must not run yet
+func (b *checkBackoff) skip(nodeCheck, name string, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state, ok := b.checks[nodeCheck][name]
+	return ok && now.Before(state.nextRun)
+}
+
+// record updates the state of a check after it ran, given its normal interval
+func (b *checkBackoff) record(nodeCheck, name string, result *nodecheckv1alpha1.CheckResult, interval time.Duration, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	reason := environmentalError(result)
+	if reason == "" {
+		delete(b.checks[nodeCheck], name)
+		return
+	}
+	if b.checks[nodeCheck] == nil {
+		b.checks[nodeCheck] = make(map[string]*checkBackoffState)
+	}
+	state, ok := b.checks[nodeCheck][name]
+	if !ok || state.reason != reason {
+		state = &checkBackoffState{reason: reason}
+		b.checks[nodeCheck][name] = state
+	}
+	state.failures++
+	if state.failures < backoffFailureThreshold {
+		return
+	}
+
+	// Double the interval for every failure past the threshold
+	multiplier := 1 << uint(state.failures-backoffFailureThreshold+1)
+	if multiplier > backoffMaxMultiplier || multiplier <= 0 {
+		multiplier = backoffMaxMultiplier
+	}
+	state.nextRun = now.Add(time.Duration(multiplier) * interval)
+}
+
+// forget drops the state of a NodeCheck (e.g. when it is deleted)
+func (b *checkBackoff) forget(nodeCheck string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.checks, nodeCheck)
+}
+
+// degradedCondition builds the Degraded condition for a NodeCheck from the backed-off checks
+func (b *checkBackoff) degradedCondition(nodeCheck string, generation int64, now time.Time) metav1.Condition {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	backedOff := []string{}
+	for name, state := range b.checks[nodeCheck] {
+		if state.failures < backoffFailureThreshold {
+			continue
+		}
+		backedOff = append(backedOff, fmt.Sprintf("%s: %s (%d consecutive failures, next run in %s)",
+			name, state.reason, state.failures, state.nextRun.Sub(now).Round(time.Second)))
+	}
+	sort.Strings(backedOff)
+
+	if len(backedOff) == 0 {
+		return metav1.Condition{
+			Type:               ConditionDegraded,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ChecksRunning",
+			Message:            "All checks run at their configured interval",
+			ObservedGeneration: generation,
+		}
+	}
+	return metav1.Condition{
+		Type:               ConditionDegraded,
+		Status:             metav1.ConditionTrue,
+		Reason:             "ChecksBackedOff",
+		Message:            "Checks failing with environmental errors are run less often: " + strings.Join(backedOff, "; "),
+		ObservedGeneration: generation,
+	}
+}
+
+// setDegradedCondition sets the Degraded condition on the NodeCheck status
+func setDegradedCondition(status *nodecheckv1alpha1.NodeCheckStatus, condition metav1.Condition) {
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// scheduledCheck is a check queued for execution in the current run
+type scheduledCheck struct {
+	name    string
+	results map[string]nodecheckv1alpha1.CheckResult
+	check   func(context.Context) *nodecheckv1alpha1.CheckResult
+}
+
+// orderChecks returns the checks in execution order and how many of them are pinned by spec.checkOrder:
+// the listed checks come first, in the listed order, followed by the others in their default order
+func orderChecks(spec *nodecheckv1alpha1.NodeCheckSpec, scheduled []scheduledCheck) ([]scheduledCheck, int) {
+	byName := make(map[string]int, len(scheduled))
+	for i, check := range scheduled {
+		byName[check.name] = i
+	}
+
+	ordered := make([]scheduledCheck, 0, len(scheduled))
+	pinned := make(map[string]bool)
+	for _, name := range spec.CheckOrder {
+		if i, ok := byName[name]; ok && !pinned[name] {
+			ordered = append(ordered, scheduled[i])
+			pinned[name] = true
+		}
+	}
+	for _, check := range scheduled {
+		if !pinned[check.name] {
+			ordered = append(ordered, check)
+		}
+	}
+	return ordered, len(pinned)
+}
+
+// executeChecks runs the scheduled checks and stores their results. The checks pinned by spec.checkOrder
+// run first, one at a time; the others run up to spec.maxConcurrentChecks at a time. A check waits for
+// the checks it depends on that come earlier in the order, so it sees their result from this run as it
+// would when the checks run one at a time. mu guards the result maps, which run may read meanwhile.
+// done, if set, is called with the name of each check once its result is stored.
+func executeChecks(ctx context.Context, spec *nodecheckv1alpha1.NodeCheckSpec, scheduled []scheduledCheck, mu *sync.Mutex, run func(string, func(context.Context) *nodecheckv1alpha1.CheckResult) nodecheckv1alpha1.CheckResult, done func(string)) {
+	ordered, pinned := orderChecks(spec, scheduled)
+	store := func(check scheduledCheck) {
+		result := run(check.name, check.check)
+		mu.Lock()
+		check.results[check.name] = result
+		mu.Unlock()
+		if done != nil {
+			done(check.name)
+		}
+	}
+
+	limit := spec.MaxConcurrentChecks
+	if limit <= 1 {
+		for _, check := range ordered {
+			store(check)
+		}
+		return
+	}
+
+	for _, check := range ordered[:pinned] {
+		store(check)
+	}
+
+	finishedChecks := make(map[string]chan struct{}, len(ordered)-pinned)
+	slots := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for _, check := range ordered[pinned:] {
+		waitFor := []chan struct{}{}
+		for _, prerequisite := range checkPrerequisites(spec, check.name) {
+			if strings.HasPrefix(prerequisite, capabilityPrefix) {
+				continue
+			}
+			if finished, ok := finishedChecks[prerequisite]; ok {
+				waitFor = append(waitFor, finished)
+			}
+		}
+		finished := make(chan struct{})
+		finishedChecks[check.name] = finished
+
+		wg.Add(1)
+		go func(check scheduledCheck, waitFor []chan struct{}, finished chan struct{}) {
+			defer wg.Done()
+			defer close(finished)
+			for _, prerequisite := range waitFor {
+				<-prerequisite
+			}
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slots }()
+			store(check)
+		}(check, waitFor, finished)
+	}
+	wg.Wait()
+}
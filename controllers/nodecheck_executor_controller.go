@@ -2,18 +2,29 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
+	"github.com/go-logr/logr"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
 
 	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
 	"github.com/albertofilice/node-check-operator/pkg/checks"
+	"github.com/albertofilice/node-check-operator/pkg/faults"
+	"github.com/albertofilice/node-check-operator/pkg/maintenance"
+	"github.com/albertofilice/node-check-operator/pkg/trigger"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -24,24 +35,49 @@ type NodeCheckExecutorReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Clientset kubernetes.Interface
+
+	// backoff throttles checks that keep failing with environmental errors
+	backoff *checkBackoff
+
+	// dependencies skips checks whose prerequisites (other checks, node capabilities) are not met
+	dependencies *checkDependencies
+
+	// recorder records the Events of spec.emitEvents
+	recorder record.EventRecorder
+
+	// ruleCache holds the rules of the rule pack ConfigMaps
+	ruleCache rulePackCache
 }
 
 //+kubebuilder:rbac:groups=nodecheck.openshift.io,resources=nodechecks,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=nodecheck.openshift.io,resources=nodechecks/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;patch
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=resourcequotas,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=endpoints,verbs=get;list;watch
 //+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=route.openshift.io,resources=routes,verbs=get;list;watch
 
 // Reconcile executes checks for NodeCheck resources that match the current node
 func (r *NodeCheckExecutorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := ctrl.Log.WithName("NodeCheckExecutor")
 
+	// Each check category of a NodeCheck is a work item of its own (see categoryRequests)
+	key, category := splitCategoryRequest(req)
+	if category != "" {
+		log = log.WithValues("category", category)
+	}
+
 	// Fetch the NodeCheck instance
 	var nodeCheck nodecheckv1alpha1.NodeCheck
-	if err := r.Get(ctx, req.NamespacedName, &nodeCheck); err != nil {
+	if err := r.Get(ctx, key, &nodeCheck); err != nil {
+		if errors.IsNotFound(err) {
+			r.backoff.forget(key.String())
+			r.dependencies.forget(key.String())
+		}
 		log.Error(err, "unable to fetch NodeCheck")
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
@@ -82,348 +118,780 @@ func (r *NodeCheckExecutorReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return ctrl.Result{}, nil
 	}
 
+	// Paused NodeChecks keep their last results; resuming (spec or annotation change) triggers a new reconcile
+	if reason := pausedReason(&nodeCheck); reason != "" {
+		log.Info("Skipping NodeCheck - check executions are paused", "node", currentNodeName, "reason", reason)
+		if setPausedCondition(&nodeCheck.Status, pausedCondition(reason, nodeCheck.Generation)) {
+			if err := r.Status().Update(ctx, &nodeCheck); err != nil {
+				if errors.IsConflict(err) {
+					return ctrl.Result{Requeue: true}, nil
+				}
+				log.Error(err, "unable to update NodeCheck paused condition")
+				return ctrl.Result{}, err
+			}
+		}
+		return ctrl.Result{}, nil
+	}
+
+	// Node annotations disable checks or override thresholds on this node only
+	overrides := r.nodeOverrides(ctx, log, currentNodeName)
+	// Rule packs add log patterns, known-issue signatures and threshold presets under spec.thresholds
+	rules := r.rulePacks(ctx, log)
+	thresholds := overrides.applyThresholds(withThresholdPresets(rules.ThresholdPresets, nodeCheck.Spec.Thresholds))
+	// The smoothed checks average their values per NodeCheck
+	smoothingScope := nodeCheck.Namespace + "/" + nodeCheck.Name
+
 	// Calculate check interval
 	interval := time.Duration(nodeCheck.Spec.CheckInterval) * time.Minute
 	if interval == 0 {
 		interval = 5 * time.Minute // Default interval
 	}
 
-	// Check if enough time has passed since last check
-	if !nodeCheck.Status.LastCheckTime.IsZero() {
-		timeSinceLastCheck := time.Since(nodeCheck.Status.LastCheckTime.Time)
-		if timeSinceLastCheck < interval {
-			// Not enough time has passed, requeue for the remaining time
-			remainingTime := interval - timeSinceLastCheck
-			log.Info("Skipping check, not enough time has passed", 
-				"timeSinceLastCheck", timeSinceLastCheck, 
+	// Work out which check categories are due. Each category has its own schedule
+	// (spec.categoryIntervals), based on the timestamps of its previous results.
+	categories := checkCategories
+	if category != "" {
+		if !categoryEnabled(&nodeCheck.Spec, category) {
+			// The category was disabled; enabling it again queues its work item
+			return ctrl.Result{}, nil
+		}
+		categories = []string{category}
+	}
+	previousSystemResults, previousKubernetesResults := flattenCheckResults(nodeCheck.Status.CheckResults)
+	lastRuns := lastCategoryRuns(previousSystemResults, previousKubernetesResults)
+	var due map[string]bool
+	var nextRun time.Duration
+	if trigger.External(&nodeCheck.Spec) {
+		// Externally scheduled NodeChecks only run when triggered
+		due = make(map[string]bool)
+	} else {
+		due, nextRun = dueCategories(&nodeCheck.Spec, categories, lastRuns, interval, time.Now())
+	}
+	// A new value of the trigger annotation runs the enabled categories that did not run for it yet
+	triggerValue, triggered := "", []string{}
+	for _, category := range categories {
+		if !categoryEnabled(&nodeCheck.Spec, category) {
+			continue
+		}
+		if value, pending := trigger.Pending(&nodeCheck, category); pending {
+			triggerValue = value
+			triggered = append(triggered, category)
+			due[category] = true
+		}
+	}
+	if len(due) == 0 && trigger.External(&nodeCheck.Spec) {
+		log.V(1).Info("Skipping check, waiting for a trigger", "annotation", trigger.Annotation)
+		return ctrl.Result{}, nil
+	}
+	if len(due) == 0 {
+		if nextRun == 0 {
+			// No checks enabled: refresh the (empty) status every CheckInterval
+			nextRun = interval
+			if !nodeCheck.Status.LastCheckTime.IsZero() {
+				nextRun = interval - time.Since(nodeCheck.Status.LastCheckTime.Time)
+			}
+		}
+		if nextRun > 0 {
+			// No category is due yet, requeue for the remaining time
+			log.Info("Skipping check, no check category is due", 
 				"interval", interval, 
-				"remainingTime", remainingTime)
-			return ctrl.Result{RequeueAfter: remainingTime}, nil
+				"remainingTime", nextRun)
+			return ctrl.Result{RequeueAfter: nextRun}, nil
 		}
 	}
+	if nextRun <= 0 {
+		nextRun = interval
+	}
 
-	log.Info("Executing checks for NodeCheck", "nodeCheck", req.Name, "node", currentNodeName)
+	dueList := []string{}
+	for _, category := range checkCategories {
+		if due[category] {
+			dueList = append(dueList, category)
+		}
+	}
+	// The results of this run carry its ID, so they can be told apart from the results kept from earlier
+	// runs (status.runs)
+	runStart := metav1.Now()
+	runID := newRunID(string(nodeCheck.UID), dueList, runStart.Time)
+	log.Info("Executing checks for NodeCheck", "nodeCheck", key.Name, "node", currentNodeName, "categories", dueList, "triggered", triggered, "runID", runID)
 
 	// Initialize check results for the current node
 	systemResults := make(map[string]nodecheckv1alpha1.CheckResult)
 	kubernetesResults := make(map[string]nodecheckv1alpha1.CheckResult)
 
-	// Perform system checks for the current node
-	if nodeCheck.Spec.SystemChecks.Uptime {
-		systemChecker := checks.NewSystemChecker(currentNodeName)
-		result := systemChecker.CheckUptime(ctx)
-		systemResults["uptime"] = *result
-	}
+	// resultsMu guards the results of this run and the skipped checks, as checks may run concurrently
+	var resultsMu sync.Mutex
 
-	if nodeCheck.Spec.SystemChecks.Processes {
-		systemChecker := checks.NewSystemChecker(currentNodeName)
-		result := systemChecker.CheckProcesses(ctx)
-		systemResults["processes"] = *result
+	// latestResult returns the result of a check from this run, or from the previous one if it did not run yet
+	latestResult := func(name string) (nodecheckv1alpha1.CheckResult, bool) {
+		resultsMu.Lock()
+		defer resultsMu.Unlock()
+		for _, results := range []map[string]nodecheckv1alpha1.CheckResult{systemResults, kubernetesResults, previousSystemResults, previousKubernetesResults} {
+			if result, ok := results[name]; ok {
+				return result, true
+			}
+		}
+		return nodecheckv1alpha1.CheckResult{}, false
 	}
 
-	if nodeCheck.Spec.SystemChecks.Resources {
-		systemChecker := checks.NewSystemChecker(currentNodeName)
-		result := systemChecker.CheckResources(ctx)
-		systemResults["resources"] = *result
+	// run executes a single check with its configured timeout and optional result logging.
+	// Checks backed off after repeated environmental errors keep their previous result instead,
+	// and checks with unmet prerequisites (spec.checkDependencies) are skipped and dropped from the results.
+	backoffKey := key.String()
+	r.dependencies.startRun(backoffKey, due)
+	skipped := make(map[string]bool)
+	run := func(name string, check func(context.Context) *nodecheckv1alpha1.CheckResult) nodecheckv1alpha1.CheckResult {
+		if reason := r.dependencies.unmetPrerequisite(ctx, &nodeCheck.Spec, name, latestResult, time.Now()); reason != "" {
+			log.V(1).Info("Skipping check with unmet prerequisites", "check", name, "reason", reason)
+			r.dependencies.markSkipped(backoffKey, name, reason)
+			resultsMu.Lock()
+			skipped[name] = true
+			resultsMu.Unlock()
+			return nodecheckv1alpha1.CheckResult{}
+		}
+		previous, ok := previousSystemResults[name]
+		if kubernetesCheckNames[name] {
+			previous, ok = previousKubernetesResults[name]
+		}
+		if ok && r.backoff.skip(backoffKey, name, time.Now()) {
+			log.V(1).Info("Skipping backed-off check", "check", name)
+			return previous
+		}
+		result := runCheck(ctx, log, &nodeCheck.Spec, currentNodeName, runID, name, check)
+		category := checkCategory(name, kubernetesCheckNames[name])
+		r.backoff.record(backoffKey, name, &result, categoryInterval(&nodeCheck.Spec, category, interval), time.Now())
+		return result
 	}
 
-	if nodeCheck.Spec.SystemChecks.Memory {
-		systemChecker := checks.NewSystemChecker(currentNodeName)
-		result := systemChecker.CheckMemory(ctx)
-		systemResults["memory"] = *result
+	// schedule queues a check; the queued checks are run by executeChecks according to
+	// spec.checkOrder and spec.maxConcurrentChecks
+	scheduled := []scheduledCheck{}
+	// capturedBaseline is the baseline recorded by the baseline_drift check in this run, if any
+	var capturedBaseline *nodecheckv1alpha1.NodeBaseline
+	schedule := func(results map[string]nodecheckv1alpha1.CheckResult, name string, check func(context.Context) *nodecheckv1alpha1.CheckResult) {
+		if overrides.disables(name) {
+			log.V(1).Info("Skipping check disabled by node annotation", "check", name, "annotation", DisableChecksAnnotation)
+			return
+		}
+		scheduled = append(scheduled, scheduledCheck{name: name, results: results, check: check})
 	}
 
-	if nodeCheck.Spec.SystemChecks.UninterruptibleTasks {
-		systemChecker := checks.NewSystemChecker(currentNodeName)
-		result := systemChecker.CheckUninterruptibleTasks(ctx)
-		systemResults["uninterruptible_tasks"] = *result
-	}
+	// Perform system checks for the current node
+	if due[categorySystem] {
+		if nodeCheck.Spec.SystemChecks.Uptime {
+			systemChecker := checks.NewSystemChecker(currentNodeName)
+			schedule(systemResults, "uptime", systemChecker.CheckUptime)
+		}
 
-	if nodeCheck.Spec.SystemChecks.Services {
-		systemChecker := checks.NewSystemChecker(currentNodeName)
-		result := systemChecker.CheckServices(ctx)
-		systemResults["services"] = *result
-	}
+		if nodeCheck.Spec.SystemChecks.Processes {
+			systemChecker := checks.NewSystemChecker(currentNodeName)
+			schedule(systemResults, "processes", systemChecker.CheckProcesses)
+		}
 
-	if nodeCheck.Spec.SystemChecks.SystemLogs {
-		systemChecker := checks.NewSystemChecker(currentNodeName)
-		result := systemChecker.CheckSystemLogs(ctx)
-		systemResults["system_logs"] = *result
-	}
+		if nodeCheck.Spec.SystemChecks.Resources {
+			systemChecker := checks.NewSystemChecker(currentNodeName)
+			schedule(systemResults, "resources", systemChecker.CheckResources)
+		}
 
-	// New system checks
-	systemChecker := checks.NewSystemChecker(currentNodeName)
-	if nodeCheck.Spec.SystemChecks.FileDescriptors {
-		result := systemChecker.CheckFileDescriptors(ctx)
-		systemResults["file_descriptors"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.ZombieProcesses {
-		result := systemChecker.CheckZombieProcesses(ctx)
-		systemResults["zombie_processes"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.NTPSync {
-		result := systemChecker.CheckNTPSync(ctx)
-		systemResults["ntp_sync"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.KernelPanics {
-		result := systemChecker.CheckKernelPanics(ctx)
-		systemResults["kernel_panics"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.OOMKiller {
-		result := systemChecker.CheckOOMKiller(ctx)
-		systemResults["oom_killer"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.CPUFrequency {
-		result := systemChecker.CheckCPUFrequency(ctx)
-		systemResults["cpu_frequency"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.InterruptsBalance {
-		result := systemChecker.CheckInterruptsBalance(ctx)
-		systemResults["interrupts_balance"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.CPUStealTime {
-		result := systemChecker.CheckCPUStealTime(ctx)
-		systemResults["cpu_steal_time"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.MemoryFragmentation {
-		result := systemChecker.CheckMemoryFragmentation(ctx)
-		systemResults["memory_fragmentation"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.SwapActivity {
-		result := systemChecker.CheckSwapActivity(ctx)
-		systemResults["swap_activity"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.ContextSwitches {
-		result := systemChecker.CheckContextSwitches(ctx)
-		systemResults["context_switches"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.SELinuxStatus {
-		result := systemChecker.CheckSELinuxStatus(ctx)
-		systemResults["selinux_status"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.SSHAccess {
-		result := systemChecker.CheckSSHAccess(ctx)
-		systemResults["ssh_access"] = *result
-	}
-	if nodeCheck.Spec.SystemChecks.KernelModules {
-		result := systemChecker.CheckKernelModules(ctx)
-		systemResults["kernel_modules"] = *result
+		if nodeCheck.Spec.SystemChecks.Memory {
+			systemChecker := checks.NewSystemChecker(currentNodeName)
+			systemChecker.SetThresholds(thresholds)
+			schedule(systemResults, "memory", systemChecker.CheckMemory)
+		}
+
+		if nodeCheck.Spec.SystemChecks.UninterruptibleTasks {
+			systemChecker := checks.NewSystemChecker(currentNodeName)
+			systemChecker.SetSmoothing(smoothingScope, nodeCheck.Spec.Smoothing)
+			schedule(systemResults, "uninterruptible_tasks", systemChecker.CheckUninterruptibleTasks)
+		}
+
+		if nodeCheck.Spec.SystemChecks.Services {
+			systemChecker := checks.NewSystemChecker(currentNodeName)
+			systemChecker.SetExpectations(nodeCheck.Spec.Expectations)
+			schedule(systemResults, "services", systemChecker.CheckServices)
+		}
+
+		if nodeCheck.Spec.SystemChecks.SystemLogs {
+			systemChecker := checks.NewSystemChecker(currentNodeName)
+			systemChecker.SetRules(rules)
+			schedule(systemResults, "system_logs", systemChecker.CheckSystemLogs)
+		}
+
+		// New system checks
+		systemChecker := checks.NewSystemChecker(currentNodeName)
+		systemChecker.SetExpectations(nodeCheck.Spec.Expectations)
+		systemChecker.SetThresholds(thresholds)
+		systemChecker.SetTimeDrift(nodeCheck.Spec.TimeDrift)
+		systemChecker.SetCertificateExpiry(nodeCheck.Spec.CertificateExpiry)
+		systemChecker.SetSampling(nodeCheck.Spec.Sampling)
+		systemChecker.SetSmoothing(smoothingScope, nodeCheck.Spec.Smoothing)
+		systemChecker.SetRules(rules)
+		if nodeCheck.Spec.SystemChecks.FileDescriptors {
+			schedule(systemResults, "file_descriptors", systemChecker.CheckFileDescriptors)
+		}
+		if nodeCheck.Spec.SystemChecks.ZombieProcesses {
+			schedule(systemResults, "zombie_processes", systemChecker.CheckZombieProcesses)
+		}
+		if nodeCheck.Spec.SystemChecks.NTPSync {
+			schedule(systemResults, "ntp_sync", systemChecker.CheckNTPSync)
+		}
+		if nodeCheck.Spec.SystemChecks.KernelPanics {
+			schedule(systemResults, "kernel_panics", systemChecker.CheckKernelPanics)
+		}
+		if nodeCheck.Spec.SystemChecks.OOMKiller {
+			schedule(systemResults, "oom_killer", systemChecker.CheckOOMKiller)
+		}
+		if nodeCheck.Spec.SystemChecks.CPUFrequency {
+			schedule(systemResults, "cpu_frequency", systemChecker.CheckCPUFrequency)
+		}
+		if nodeCheck.Spec.SystemChecks.InterruptsBalance {
+			schedule(systemResults, "interrupts_balance", systemChecker.CheckInterruptsBalance)
+		}
+		if nodeCheck.Spec.SystemChecks.CPUStealTime {
+			schedule(systemResults, "cpu_steal_time", systemChecker.CheckCPUStealTime)
+		}
+		if nodeCheck.Spec.SystemChecks.MemoryFragmentation {
+			schedule(systemResults, "memory_fragmentation", systemChecker.CheckMemoryFragmentation)
+		}
+		if nodeCheck.Spec.SystemChecks.SwapActivity {
+			schedule(systemResults, "swap_activity", systemChecker.CheckSwapActivity)
+		}
+		if nodeCheck.Spec.SystemChecks.ContextSwitches {
+			schedule(systemResults, "context_switches", systemChecker.CheckContextSwitches)
+		}
+		if nodeCheck.Spec.SystemChecks.SELinuxStatus {
+			schedule(systemResults, "selinux_status", systemChecker.CheckSELinuxStatus)
+		}
+		if nodeCheck.Spec.SystemChecks.SSHAccess {
+			schedule(systemResults, "ssh_access", systemChecker.CheckSSHAccess)
+		}
+		if nodeCheck.Spec.SystemChecks.KernelModules {
+			schedule(systemResults, "kernel_modules", systemChecker.CheckKernelModules)
+		}
+		if nodeCheck.Spec.SystemChecks.KernelTaint {
+			schedule(systemResults, "kernel_taint", systemChecker.CheckKernelTaint)
+		}
+		if nodeCheck.Spec.SystemChecks.PressureStall {
+			schedule(systemResults, "pressure_stall", systemChecker.CheckPressureStall)
+		}
+		if nodeCheck.Spec.SystemChecks.Entropy {
+			schedule(systemResults, "entropy", systemChecker.CheckEntropy)
+		}
+		if nodeCheck.Spec.SystemChecks.TransparentHugePages {
+			schedule(systemResults, "transparent_hugepages", systemChecker.CheckTHP)
+		}
+		if nodeCheck.Spec.SystemChecks.HugePages {
+			schedule(systemResults, "huge_pages", systemChecker.CheckHugePages)
+		}
+		if nodeCheck.Spec.SystemChecks.NUMA {
+			schedule(systemResults, "numa", systemChecker.CheckNUMA)
+		}
+		if nodeCheck.Spec.SystemChecks.Conntrack {
+			schedule(systemResults, "conntrack", systemChecker.CheckConntrack)
+		}
+		if nodeCheck.Spec.SystemChecks.KnownIssues {
+			schedule(systemResults, "known_issues", systemChecker.CheckKnownIssues)
+		}
+		if nodeCheck.Spec.SystemChecks.Kdump {
+			schedule(systemResults, "kdump", systemChecker.CheckKdump)
+		}
+		if nodeCheck.Spec.SystemChecks.SysctlDrift {
+			schedule(systemResults, "sysctl_drift", systemChecker.CheckSysctlDrift)
+		}
+		if nodeCheck.Spec.SystemChecks.Inotify {
+			schedule(systemResults, "inotify", systemChecker.CheckInotify)
+		}
+		if nodeCheck.Spec.SystemChecks.ServiceRestarts {
+			schedule(systemResults, "service_restarts", systemChecker.CheckServiceRestarts)
+		}
+		if nodeCheck.Spec.SystemChecks.CoreDumps {
+			schedule(systemResults, "core_dumps", systemChecker.CheckCoreDumps)
+		}
+		if nodeCheck.Spec.SystemChecks.TimeDrift {
+			schedule(systemResults, "time_drift", systemChecker.CheckTimeDrift)
+		}
+		if nodeCheck.Spec.SystemChecks.Audit {
+			schedule(systemResults, "audit", systemChecker.CheckAudit)
+		}
+		if nodeCheck.Spec.SystemChecks.CPUVulnerabilities {
+			schedule(systemResults, "cpu_vulnerabilities", systemChecker.CheckCPUVulnerabilities)
+		}
+		if nodeCheck.Spec.SystemChecks.KernelCmdline {
+			schedule(systemResults, "kernel_cmdline", systemChecker.CheckKernelCmdline)
+		}
+		if nodeCheck.Spec.SystemChecks.OSUpdates {
+			schedule(systemResults, "os_updates", systemChecker.CheckOSUpdates)
+		}
+		if nodeCheck.Spec.SystemChecks.CgroupDriver {
+			schedule(systemResults, "cgroup_driver", systemChecker.CheckCgroupDriver)
+		}
+		if nodeCheck.Spec.SystemChecks.ProcessLimits {
+			schedule(systemResults, "process_limits", systemChecker.CheckProcessLimits)
+		}
+		if nodeCheck.Spec.SystemChecks.OrphanedMounts {
+			schedule(systemResults, "orphaned_mounts", systemChecker.CheckOrphanedMounts)
+		}
+		if nodeCheck.Spec.SystemChecks.UserspaceOOM {
+			schedule(systemResults, "userspace_oom", systemChecker.CheckUserspaceOOM)
+		}
+		if nodeCheck.Spec.SystemChecks.GPU {
+			gpuChecker := checks.NewGPUChecker(currentNodeName)
+			schedule(systemResults, "gpu", gpuChecker.CheckGPU)
+		}
+		if nodeCheck.Spec.SystemChecks.SRIOV {
+			schedule(systemResults, "sriov", systemChecker.CheckSRIOV)
+		}
+		if nodeCheck.Spec.SystemChecks.KernelLockups {
+			schedule(systemResults, "kernel_lockups", systemChecker.CheckKernelLockups)
+		}
+		if nodeCheck.Spec.SystemChecks.SwapPolicy {
+			schedule(systemResults, "swap_policy", systemChecker.CheckSwapPolicy)
+		}
+		if nodeCheck.Spec.SystemChecks.CertificateExpiry {
+			schedule(systemResults, "certificate_expiry", systemChecker.CheckCertificateExpiry)
+		}
+		if baseline := nodeCheck.Spec.Baseline; baseline != nil && baseline.Enabled {
+			previousBaseline := nodeCheck.Status.Baseline
+			schedule(systemResults, "baseline_drift", func(ctx context.Context) *nodecheckv1alpha1.CheckResult {
+				result, captured := systemChecker.CheckBaselineDrift(ctx, baseline, previousBaseline)
+				if captured != nil {
+					capturedBaseline = captured
+				}
+				return result
+			})
+		}
 	}
 
 	// Perform disk checks for the current node
-	if nodeCheck.Spec.SystemChecks.Disks.Space || nodeCheck.Spec.SystemChecks.Disks.SMART || 
-	   nodeCheck.Spec.SystemChecks.Disks.Performance || nodeCheck.Spec.SystemChecks.Disks.RAID ||
-	   nodeCheck.Spec.SystemChecks.Disks.PVs || nodeCheck.Spec.SystemChecks.Disks.LVM ||
-	   nodeCheck.Spec.SystemChecks.Disks.IOWait || nodeCheck.Spec.SystemChecks.Disks.QueueDepth ||
-	   nodeCheck.Spec.SystemChecks.Disks.FilesystemErrors || nodeCheck.Spec.SystemChecks.Disks.InodeUsage ||
-	   nodeCheck.Spec.SystemChecks.Disks.MountPoints {
+	if due[categoryDisks] {
 		diskChecker := checks.NewDiskChecker(currentNodeName)
+		diskChecker.SetFilters(nodeCheck.Spec.Filters)
+		diskChecker.SetThresholds(thresholds)
+		diskChecker.SetSampling(nodeCheck.Spec.Sampling)
+		diskChecker.SetSmoothing(smoothingScope, nodeCheck.Spec.Smoothing)
 		if nodeCheck.Spec.SystemChecks.Disks.Space {
-			result := diskChecker.CheckDiskSpace(ctx)
-			systemResults["disk_space"] = *result
+			schedule(systemResults, "disk_space", diskChecker.CheckDiskSpace)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.SMART {
-			result := diskChecker.CheckSMART(ctx)
-			systemResults["disk_smart"] = *result
+			schedule(systemResults, "disk_smart", diskChecker.CheckSMART)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.Performance {
-			result := diskChecker.CheckDiskPerformance(ctx)
-			systemResults["disk_performance"] = *result
+			schedule(systemResults, "disk_performance", diskChecker.CheckDiskPerformance)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.RAID {
-			result := diskChecker.CheckRAID(ctx)
-			systemResults["disk_raid"] = *result
+			schedule(systemResults, "disk_raid", diskChecker.CheckRAID)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.PVs {
-			result := diskChecker.CheckPVs(ctx)
-			systemResults["disk_pvs"] = *result
+			schedule(systemResults, "disk_pvs", diskChecker.CheckPVs)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.LVM {
-			result := diskChecker.CheckLVM(ctx)
-			systemResults["disk_lvm"] = *result
+			schedule(systemResults, "disk_lvm", diskChecker.CheckLVM)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.IOWait {
-			result := diskChecker.CheckIOWait(ctx)
-			systemResults["disk_io_wait"] = *result
+			schedule(systemResults, "disk_io_wait", diskChecker.CheckIOWait)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.QueueDepth {
-			result := diskChecker.CheckQueueDepth(ctx)
-			systemResults["disk_queue_depth"] = *result
+			schedule(systemResults, "disk_queue_depth", diskChecker.CheckQueueDepth)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.FilesystemErrors {
-			result := diskChecker.CheckFilesystemErrors(ctx)
-			systemResults["disk_filesystem_errors"] = *result
+			schedule(systemResults, "disk_filesystem_errors", diskChecker.CheckFilesystemErrors)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.InodeUsage {
-			result := diskChecker.CheckInodeUsage(ctx)
-			systemResults["disk_inode_usage"] = *result
+			schedule(systemResults, "disk_inode_usage", diskChecker.CheckInodeUsage)
 		}
 		if nodeCheck.Spec.SystemChecks.Disks.MountPoints {
-			result := diskChecker.CheckMountPoints(ctx)
-			systemResults["disk_mount_points"] = *result
+			schedule(systemResults, "disk_mount_points", diskChecker.CheckMountPoints)
+		}
+		if nodeCheck.Spec.SystemChecks.Disks.WriteProbe {
+			schedule(systemResults, "disk_write_probe", diskChecker.CheckWriteProbe)
+		}
+		if nodeCheck.Spec.SystemChecks.Disks.RuntimeStorage {
+			schedule(systemResults, "disk_runtime_storage", diskChecker.CheckRuntimeStorage)
+		}
+		if nodeCheck.Spec.SystemChecks.Disks.WearLevel {
+			schedule(systemResults, "disk_wear_level", diskChecker.CheckWearLevel)
 		}
 	}
 
 	// Perform hardware checks for the current node
-	if nodeCheck.Spec.SystemChecks.Hardware.Temperature || nodeCheck.Spec.SystemChecks.Hardware.IPMI || 
-	   nodeCheck.Spec.SystemChecks.Hardware.BMC || nodeCheck.Spec.SystemChecks.Hardware.FanStatus ||
-	   nodeCheck.Spec.SystemChecks.Hardware.PowerSupply || nodeCheck.Spec.SystemChecks.Hardware.MemoryErrors ||
-	   nodeCheck.Spec.SystemChecks.Hardware.PCIeErrors || nodeCheck.Spec.SystemChecks.Hardware.CPUMicrocode {
+	if due[categoryHardware] {
 		hardwareChecker := checks.NewHardwareChecker(currentNodeName)
 		if nodeCheck.Spec.SystemChecks.Hardware.Temperature {
-			result := hardwareChecker.CheckTemperature(ctx)
-			systemResults["hardware_temperature"] = *result
+			schedule(systemResults, "hardware_temperature", hardwareChecker.CheckTemperature)
 		}
 		if nodeCheck.Spec.SystemChecks.Hardware.IPMI {
-			result := hardwareChecker.CheckIPMI(ctx)
-			systemResults["hardware_ipmi"] = *result
+			schedule(systemResults, "hardware_ipmi", hardwareChecker.CheckIPMI)
 		}
 		if nodeCheck.Spec.SystemChecks.Hardware.BMC {
-			result := hardwareChecker.CheckBMC(ctx)
-			systemResults["hardware_bmc"] = *result
+			schedule(systemResults, "hardware_bmc", hardwareChecker.CheckBMC)
 		}
 		if nodeCheck.Spec.SystemChecks.Hardware.FanStatus {
-			result := hardwareChecker.CheckFanStatus(ctx)
-			systemResults["hardware_fan_status"] = *result
+			schedule(systemResults, "hardware_fan_status", hardwareChecker.CheckFanStatus)
 		}
 		if nodeCheck.Spec.SystemChecks.Hardware.PowerSupply {
-			result := hardwareChecker.CheckPowerSupply(ctx)
-			systemResults["hardware_power_supply"] = *result
+			schedule(systemResults, "hardware_power_supply", hardwareChecker.CheckPowerSupply)
 		}
 		if nodeCheck.Spec.SystemChecks.Hardware.MemoryErrors {
-			result := hardwareChecker.CheckMemoryErrors(ctx)
-			systemResults["hardware_memory_errors"] = *result
+			schedule(systemResults, "hardware_memory_errors", hardwareChecker.CheckMemoryErrors)
 		}
 		if nodeCheck.Spec.SystemChecks.Hardware.PCIeErrors {
-			result := hardwareChecker.CheckPCIeErrors(ctx)
-			systemResults["hardware_pcie_errors"] = *result
+			schedule(systemResults, "hardware_pcie_errors", hardwareChecker.CheckPCIeErrors)
 		}
 		if nodeCheck.Spec.SystemChecks.Hardware.CPUMicrocode {
-			result := hardwareChecker.CheckCPUMicrocode(ctx)
-			systemResults["hardware_cpu_microcode"] = *result
+			schedule(systemResults, "hardware_cpu_microcode", hardwareChecker.CheckCPUMicrocode)
 		}
 	}
 
 	// Perform network checks for the current node
-	if nodeCheck.Spec.SystemChecks.Network.Interfaces || nodeCheck.Spec.SystemChecks.Network.Routing || 
-	   nodeCheck.Spec.SystemChecks.Network.Connectivity || nodeCheck.Spec.SystemChecks.Network.Statistics ||
-	   nodeCheck.Spec.SystemChecks.Network.Errors || nodeCheck.Spec.SystemChecks.Network.Latency ||
-	   nodeCheck.Spec.SystemChecks.Network.DNSResolution || nodeCheck.Spec.SystemChecks.Network.BondingStatus ||
-	   nodeCheck.Spec.SystemChecks.Network.FirewallRules {
+	if due[categoryNetwork] {
 		networkChecker := checks.NewNetworkChecker(currentNodeName)
+		networkChecker.SetFilters(nodeCheck.Spec.Filters)
 		if nodeCheck.Spec.SystemChecks.Network.Interfaces {
-			result := networkChecker.CheckInterfaces(ctx)
-			systemResults["network_interfaces"] = *result
+			schedule(systemResults, "network_interfaces", networkChecker.CheckInterfaces)
 		}
 		if nodeCheck.Spec.SystemChecks.Network.Routing {
-			result := networkChecker.CheckRouting(ctx)
-			systemResults["network_routing"] = *result
+			schedule(systemResults, "network_routing", networkChecker.CheckRouting)
 		}
 		if nodeCheck.Spec.SystemChecks.Network.Connectivity {
-			result := networkChecker.CheckConnectivity(ctx)
-			systemResults["network_connectivity"] = *result
+			schedule(systemResults, "network_connectivity", networkChecker.CheckConnectivity)
 		}
 		if nodeCheck.Spec.SystemChecks.Network.Statistics {
-			result := networkChecker.CheckStatistics(ctx)
-			systemResults["network_statistics"] = *result
+			schedule(systemResults, "network_statistics", networkChecker.CheckStatistics)
 		}
 		if nodeCheck.Spec.SystemChecks.Network.Errors {
-			result := networkChecker.CheckErrors(ctx)
-			systemResults["network_errors"] = *result
+			schedule(systemResults, "network_errors", networkChecker.CheckErrors)
 		}
 		if nodeCheck.Spec.SystemChecks.Network.Latency {
-			result := networkChecker.CheckLatency(ctx)
-			systemResults["network_latency"] = *result
+			schedule(systemResults, "network_latency", networkChecker.CheckLatency)
 		}
 		if nodeCheck.Spec.SystemChecks.Network.DNSResolution {
-			result := networkChecker.CheckDNSResolution(ctx)
-			systemResults["network_dns_resolution"] = *result
+			schedule(systemResults, "network_dns_resolution", networkChecker.CheckDNSResolution)
 		}
 		if nodeCheck.Spec.SystemChecks.Network.BondingStatus {
-			result := networkChecker.CheckBondingStatus(ctx)
-			systemResults["network_bonding_status"] = *result
+			schedule(systemResults, "network_bonding_status", networkChecker.CheckBondingStatus)
 		}
 		if nodeCheck.Spec.SystemChecks.Network.FirewallRules {
-			result := networkChecker.CheckFirewallRules(ctx)
-			systemResults["network_firewall_rules"] = *result
+			schedule(systemResults, "network_firewall_rules", networkChecker.CheckFirewallRules)
+		}
+		if nodeCheck.Spec.SystemChecks.Network.Egress {
+			networkSpec := nodeCheck.Spec.SystemChecks.Network
+			schedule(systemResults, "network_egress", func(ctx context.Context) *nodecheckv1alpha1.CheckResult {
+				return networkChecker.CheckEgress(ctx, networkSpec)
+			})
+		}
+		if nodeCheck.Spec.SystemChecks.Network.Ingress {
+			networkSpec := nodeCheck.Spec.SystemChecks.Network
+			schedule(systemResults, "network_ingress", func(ctx context.Context) *nodecheckv1alpha1.CheckResult {
+				return networkChecker.CheckIngress(ctx, networkSpec)
+			})
+		}
+		if nodeCheck.Spec.SystemChecks.Network.ResolvConf {
+			schedule(systemResults, "network_resolv_conf", networkChecker.CheckResolvConf)
 		}
 	}
 
 	// Perform Kubernetes checks
-	kubernetesChecker, err := checks.NewKubernetesChecker(nodeCheck.Spec.NodeName)
-	if err != nil {
-		log.Error(err, "failed to create Kubernetes checker")
-	} else {
-		if nodeCheck.Spec.KubernetesChecks.NodeStatus {
-			result := kubernetesChecker.CheckNodeStatus(ctx)
-			kubernetesResults["node_status"] = *result
-		}
+	if due[categoryKubernetes] {
+		kubernetesChecker, err := checks.NewKubernetesChecker(nodeCheck.Spec.NodeName)
+		if err != nil {
+			log.Error(err, "failed to create Kubernetes checker")
+		} else {
+			kubernetesChecker.SetFilters(nodeCheck.Spec.Filters)
+			if nodeCheck.Spec.KubernetesChecks.NodeStatus {
+				schedule(kubernetesResults, "node_status", kubernetesChecker.CheckNodeStatus)
+			}
 
-		if nodeCheck.Spec.KubernetesChecks.Pods {
-			result := kubernetesChecker.CheckPods(ctx)
-			kubernetesResults["pods"] = *result
-		}
+			if nodeCheck.Spec.KubernetesChecks.Pods {
+				schedule(kubernetesResults, "pods", kubernetesChecker.CheckPods)
+			}
 
-		if nodeCheck.Spec.KubernetesChecks.ClusterOperators {
-			result := kubernetesChecker.CheckClusterOperators(ctx)
-			kubernetesResults["cluster_operators"] = *result
-		}
+			if nodeCheck.Spec.KubernetesChecks.ClusterOperators {
+				schedule(kubernetesResults, "cluster_operators", kubernetesChecker.CheckClusterOperators)
+			}
 
-		if nodeCheck.Spec.KubernetesChecks.NodeResources {
-			result := kubernetesChecker.CheckNodeResources(ctx)
-			kubernetesResults["node_resources"] = *result
-		}
+			if nodeCheck.Spec.KubernetesChecks.NodeResources {
+				schedule(kubernetesResults, "node_resources", kubernetesChecker.CheckNodeResources)
+			}
 
-		if nodeCheck.Spec.KubernetesChecks.NodeResourceUsage {
-			result := kubernetesChecker.CheckNodeResourceUsage(ctx)
-			kubernetesResults["node_resource_usage"] = *result
+			if nodeCheck.Spec.KubernetesChecks.NodeResourceUsage {
+				schedule(kubernetesResults, "node_resource_usage", kubernetesChecker.CheckNodeResourceUsage)
+			}
+			if nodeCheck.Spec.KubernetesChecks.ContainerRuntime {
+				schedule(kubernetesResults, "container_runtime", kubernetesChecker.CheckContainerRuntime)
+			}
+			if nodeCheck.Spec.KubernetesChecks.KubeletHealth {
+				schedule(kubernetesResults, "kubelet_health", kubernetesChecker.CheckKubeletHealth)
+			}
+			if nodeCheck.Spec.KubernetesChecks.CNIPlugin {
+				schedule(kubernetesResults, "cni_plugin", kubernetesChecker.CheckCNIPlugin)
+			}
+			if nodeCheck.Spec.KubernetesChecks.NodeConditions {
+				schedule(kubernetesResults, "node_conditions", kubernetesChecker.CheckNodeConditions)
+			}
+			if nodeCheck.Spec.KubernetesChecks.PodNetwork {
+				schedule(kubernetesResults, "pod_network", kubernetesChecker.CheckPodNetwork)
+			}
+			if nodeCheck.Spec.KubernetesChecks.LBHealthCheck {
+				schedule(kubernetesResults, "lb_health_check", kubernetesChecker.CheckLBHealthCheck)
+			}
+
+			if nodeCheck.Spec.KubernetesChecks.PodScheduling {
+				image := nodeCheck.Spec.KubernetesChecks.PodSchedulingImage
+				schedule(kubernetesResults, "pod_scheduling", func(ctx context.Context) *nodecheckv1alpha1.CheckResult {
+					return kubernetesChecker.CheckPodScheduling(ctx, image)
+				})
+			}
+			if nodeCheck.Spec.KubernetesChecks.PVCProvisioning {
+				image := nodeCheck.Spec.KubernetesChecks.PodSchedulingImage
+				storageClasses := nodeCheck.Spec.KubernetesChecks.PVCProvisioningStorageClasses
+				schedule(kubernetesResults, "pvc_provisioning", func(ctx context.Context) *nodecheckv1alpha1.CheckResult {
+					return kubernetesChecker.CheckPVCProvisioning(ctx, image, storageClasses)
+				})
+			}
 		}
-		if nodeCheck.Spec.KubernetesChecks.ContainerRuntime {
-			result := kubernetesChecker.CheckContainerRuntime(ctx)
-			kubernetesResults["container_runtime"] = *result
+	}
+
+	// The fast checks run first and their results are stored right away; the slow checks follow and their
+	// results are added to the status as they complete (at most every progressInterval)
+	fastChecks, slowPhase := splitPhases(&nodeCheck.Spec, scheduled)
+	executeChecks(ctx, &nodeCheck.Spec, fastChecks, &resultsMu, run, nil)
+	if len(slowPhase) > 0 {
+		pending := make(map[string]bool, len(slowPhase))
+		for _, check := range slowPhase {
+			pending[check.name] = true
 		}
-		if nodeCheck.Spec.KubernetesChecks.KubeletHealth {
-			result := kubernetesChecker.CheckKubeletHealth(ctx)
-			kubernetesResults["kubelet_health"] = *result
+		var progressMu sync.Mutex
+		var lastProgress time.Time
+		publish := func() {
+			resultsMu.Lock()
+			progressSystem := progressResults(systemResults, previousSystemResults, pending, skipped)
+			progressKubernetes := progressResults(kubernetesResults, previousKubernetesResults, pending, skipped)
+			resultsMu.Unlock()
+			status, message, suppressed := finalizeResults(logr.Discard(), &nodeCheck.Spec, currentNodeName, progressSystem, progressKubernetes,
+				previousSystemResults, previousKubernetesResults, due, overrides)
+			thisRun := runRecord(runID, dueList, runStart, false, progressSystem, progressKubernetes)
+			if err := r.publishProgress(ctx, &nodeCheck, buildCheckResults(progressSystem, progressKubernetes), thisRun, status, message, suppressed); err != nil {
+				log.Error(err, "unable to store the results of the fast checks, they are stored at the end of the run")
+			}
+			lastProgress = time.Now()
 		}
-		if nodeCheck.Spec.KubernetesChecks.CNIPlugin {
-			result := kubernetesChecker.CheckCNIPlugin(ctx)
-			kubernetesResults["cni_plugin"] = *result
+		if len(fastChecks) > 0 {
+			publish()
 		}
-		if nodeCheck.Spec.KubernetesChecks.NodeConditions {
-			result := kubernetesChecker.CheckNodeConditions(ctx)
-			kubernetesResults["node_conditions"] = *result
+		executeChecks(ctx, &nodeCheck.Spec, slowPhase, &resultsMu, run, func(name string) {
+			progressMu.Lock()
+			defer progressMu.Unlock()
+			resultsMu.Lock()
+			delete(pending, name)
+			remaining := len(pending)
+			resultsMu.Unlock()
+			// The last slow check is stored by the final update of the run
+			if remaining > 0 && time.Since(lastProgress) >= progressInterval {
+				publish()
+			}
+		})
+	}
+
+	// Skipped checks have no result; they are listed in the ChecksSkipped condition instead
+	for name := range skipped {
+		delete(systemResults, name)
+		delete(kubernetesResults, name)
+	}
+
+	// Faults injected to test the alerting pipelines replace the results of their checks, when the
+	// operator enables the FaultInjection feature gate
+	if faults.Enabled() {
+		injectFaults(log, &nodeCheck, systemResults, kubernetesResults, time.Now())
+	}
+
+	// Failing results keep the last Healthy result of their check in their details
+	attachLastKnownGood(systemResults, previousSystemResults)
+	attachLastKnownGood(kubernetesResults, previousKubernetesResults)
+
+	// The other categories of the NodeCheck run in their own work items and may store their results while
+	// this run is in progress: on a conflict the results of this run are merged with the stored ones again
+	runSystemResults, runKubernetesResults := copyCheckResults(systemResults), copyCheckResults(kubernetesResults)
+	overallStatus, overallMessage, suppressedBy := finalizeResults(log, &nodeCheck.Spec, currentNodeName, systemResults, kubernetesResults,
+		previousSystemResults, previousKubernetesResults, due, overrides)
+	checkResults := buildCheckResults(systemResults, kubernetesResults)
+
+	// Update status
+	nodeCheck.Status.NodeName = currentNodeName
+	nodeCheck.Status.OverallStatus = overallStatus
+	nodeCheck.Status.Message = overallMessage
+	nodeCheck.Status.LastCheckTime = metav1.Now()
+	nodeCheck.Status.CheckResults = checkResults
+	nodeCheck.Status.SuppressedBy = suppressedBy
+	degraded := r.backoff.degradedCondition(backoffKey, nodeCheck.Generation, time.Now())
+	setDegradedCondition(&nodeCheck.Status, degraded)
+	checksSkipped := r.dependencies.skippedCondition(backoffKey, nodeCheck.Generation)
+	setChecksSkippedCondition(&nodeCheck.Status, checksSkipped)
+	setPausedCondition(&nodeCheck.Status, pausedCondition("", nodeCheck.Generation))
+	meta.SetStatusCondition(&nodeCheck.Status.Conditions, runInProgressCondition(false, nodeCheck.Generation))
+
+	// Record the results of this run in the per-check history (spec.historySize)
+	allResults := combineCheckResults(systemResults, kubernetesResults)
+	nodeCheck.Status.History = updateCheckHistory(nodeCheck.Status.History, allResults, nodeCheck.Spec.HistorySize)
+	setBaseline(&nodeCheck.Status, &nodeCheck.Spec, capturedBaseline)
+	// Mark the node lifecycle events (reboots, upgrades) alongside the history
+	var lifecycle *nodeLifecycleObservation
+	if nodeCheck.Spec.HistorySize > 0 {
+		lifecycle = r.observeNodeLifecycle(ctx, log, currentNodeName)
+	}
+	setLifecycle(&nodeCheck.Status, &nodeCheck.Spec, lifecycle, metav1.Now())
+	trigger.Record(&nodeCheck.Status, triggerValue, triggered, metav1.Now())
+	thisRun := runRecord(runID, dueList, runStart, true, systemResults, kubernetesResults)
+	recordRun(&nodeCheck.Status, thisRun)
+
+	// Compare with the results of the previous run for spec.emitEvents and spec.cloudEvents
+	transitions := append(statusTransitions(previousSystemResults, systemResults), statusTransitions(previousKubernetesResults, kubernetesResults)...)
+
+	// Update the status with retry logic for conflict errors
+	maxRetries := 3
+	for i := 0; i < maxRetries; i++ {
+		if err := r.Status().Update(ctx, &nodeCheck); err != nil {
+			if errors.IsConflict(err) {
+				// Conflict error - fetch latest version and retry
+				if i < maxRetries-1 {
+					log.Info("Conflict updating status, retrying...", "attempt", i+1, "maxRetries", maxRetries)
+					// Fetch latest version
+					if err := r.Get(ctx, key, &nodeCheck); err != nil {
+						log.Error(err, "unable to fetch NodeCheck for retry")
+						return ctrl.Result{}, err
+					}
+					storedSystemResults, storedKubernetesResults := flattenCheckResults(nodeCheck.Status.CheckResults)
+					systemResults, kubernetesResults = copyCheckResults(runSystemResults), copyCheckResults(runKubernetesResults)
+					overallStatus, overallMessage, suppressedBy = finalizeResults(logr.Discard(), &nodeCheck.Spec, currentNodeName, systemResults, kubernetesResults,
+						storedSystemResults, storedKubernetesResults, due, overrides)
+					checkResults = buildCheckResults(systemResults, kubernetesResults)
+					allResults = combineCheckResults(systemResults, kubernetesResults)
+					// Re-apply status changes
+					nodeCheck.Status.NodeName = currentNodeName
+					nodeCheck.Status.OverallStatus = overallStatus
+					nodeCheck.Status.Message = overallMessage
+					nodeCheck.Status.LastCheckTime = metav1.Now()
+					nodeCheck.Status.CheckResults = checkResults
+					nodeCheck.Status.SuppressedBy = suppressedBy
+					setDegradedCondition(&nodeCheck.Status, degraded)
+					setChecksSkippedCondition(&nodeCheck.Status, checksSkipped)
+					setPausedCondition(&nodeCheck.Status, pausedCondition("", nodeCheck.Generation))
+					meta.SetStatusCondition(&nodeCheck.Status.Conditions, runInProgressCondition(false, nodeCheck.Generation))
+					nodeCheck.Status.History = updateCheckHistory(nodeCheck.Status.History, allResults, nodeCheck.Spec.HistorySize)
+					setBaseline(&nodeCheck.Status, &nodeCheck.Spec, capturedBaseline)
+					setLifecycle(&nodeCheck.Status, &nodeCheck.Spec, lifecycle, metav1.Now())
+					trigger.Record(&nodeCheck.Status, triggerValue, triggered, metav1.Now())
+					recordRun(&nodeCheck.Status, thisRun)
+					time.Sleep(time.Millisecond * 100 * time.Duration(i+1)) // Exponential backoff
+					continue
+				}
+			}
+			// Non-conflict error or max retries reached
+			log.Error(err, "unable to update NodeCheck status")
+			return ctrl.Result{}, err
 		}
+		// Success
+		break
 	}
 
-	// Determine overall status
-	overallStatus := "Healthy"
-	overallMessage := fmt.Sprintf("Node %s is healthy", currentNodeName)
+	// Record the status transitions as Events (spec.emitEvents) and CloudEvents (spec.cloudEvents), once
+	// the status is stored
+	r.recordTransitionEvents(&nodeCheck, currentNodeName, transitions)
+	r.sendTransitionCloudEvents(ctx, &nodeCheck, currentNodeName, transitions)
+
+	// Stamp spec.resultLabels and spec.resultAnnotations so downstream systems can route by ownership
+	if err := r.applyResultMetadata(ctx, &nodeCheck); err != nil {
+		log.Error(err, "unable to stamp result labels and annotations, retrying on the next run")
+	}
+
+	log.Info("NodeCheck checks executed successfully", "node", currentNodeName, "status", overallStatus)
+
+	// Reconcile again when the next check category is due; externally scheduled NodeChecks wait for
+	// the next trigger
+	if trigger.External(&nodeCheck.Spec) {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: nextRun}, nil
+}
 
-	// Check for any critical or warning statuses from system checks
-	for _, result := range systemResults {
-		if result.Status == "Critical" {
-			overallStatus = "Critical"
-			overallMessage = result.Message
-			break
-		} else if result.Status == "Warning" && overallStatus == "Healthy" {
-			overallStatus = "Warning"
-			overallMessage = result.Message
+// finalizeResults prepares the results of a run for the status: results covered by an active maintenance
+// window are Suppressed, the previous results of the enabled categories that were not due are kept and
+// spec.reportDetail is applied. It returns the overall status and message, and the active windows.
+func finalizeResults(log logr.Logger, spec *nodecheckv1alpha1.NodeCheckSpec, nodeName string, systemResults, kubernetesResults,
+	previousSystemResults, previousKubernetesResults map[string]nodecheckv1alpha1.CheckResult, due map[string]bool, overrides nodeOverrides) (string, string, string) {
+	// Apply maintenance windows: checks still run, but results covered by an active
+	// window are marked Suppressed so they don't contribute to the overall status
+	activeWindows, err := maintenance.ActiveWindows(spec.Suppressions, time.Now())
+	if err != nil {
+		log.Error(err, "ignoring invalid suppression windows")
+	}
+	suppressedCount := 0
+	if len(activeWindows) > 0 {
+		for name, result := range systemResults {
+			if maintenance.SuppressResult(name, &result, activeWindows) {
+				systemResults[name] = result
+				suppressedCount++
+			}
+		}
+		for name, result := range kubernetesResults {
+			if maintenance.SuppressResult(name, &result, activeWindows) {
+				kubernetesResults[name] = result
+				suppressedCount++
+			}
 		}
+		log.Info("Maintenance window active, suppressing check results",
+			"windows", maintenance.WindowNames(activeWindows), "suppressedChecks", suppressedCount)
 	}
+	suppressedBy := maintenance.WindowNames(activeWindows)
 
-	// Check for any critical or warning statuses from Kubernetes checks
-	for _, result := range kubernetesResults {
-		if result.Status == "Critical" {
-			overallStatus = "Critical"
-			overallMessage = result.Message
-			break
-		} else if result.Status == "Warning" && overallStatus == "Healthy" {
-			overallStatus = "Warning"
-			overallMessage = result.Message
+	// Keep the previous results of enabled categories that were not due in this run
+	for key, result := range previousSystemResults {
+		category := checkCategory(key, false)
+		if _, ok := systemResults[key]; !ok && !due[category] && categoryEnabled(spec, category) && !overrides.disables(key) {
+			systemResults[key] = result
+		}
+	}
+	for key, result := range previousKubernetesResults {
+		if _, ok := kubernetesResults[key]; !ok && !due[categoryKubernetes] && categoryEnabled(spec, categoryKubernetes) && !overrides.disables(key) {
+			kubernetesResults[key] = result
 		}
 	}
 
+	// Drop the details spec.reportDetail does not keep
+	trimResultDetails(spec.ReportDetail, systemResults)
+	trimResultDetails(spec.ReportDetail, kubernetesResults)
+
+	// Determine overall status according to spec.aggregationPolicy
+	healthyMessage := fmt.Sprintf("Node %s is healthy", nodeName)
+	if suppressedCount > 0 {
+		healthyMessage = fmt.Sprintf("Node %s is healthy (%d checks suppressed by maintenance window: %s)", nodeName, suppressedCount, suppressedBy)
+	}
+	overallStatus, overallMessage := aggregateOverallStatus(spec, systemResults, kubernetesResults, healthyMessage)
+	return overallStatus, overallMessage, suppressedBy
+}
+
+// copyCheckResults returns a copy of keyed check results
+func copyCheckResults(results map[string]nodecheckv1alpha1.CheckResult) map[string]nodecheckv1alpha1.CheckResult {
+	copied := make(map[string]nodecheckv1alpha1.CheckResult, len(results))
+	for name, result := range results {
+		copied[name] = result
+	}
+	return copied
+}
+
+// combineCheckResults returns the system and Kubernetes results in a single map keyed by check name
+func combineCheckResults(systemResults, kubernetesResults map[string]nodecheckv1alpha1.CheckResult) map[string]nodecheckv1alpha1.CheckResult {
+	combined := make(map[string]nodecheckv1alpha1.CheckResult, len(systemResults)+len(kubernetesResults))
+	for name, result := range systemResults {
+		combined[name] = result
+	}
+	for name, result := range kubernetesResults {
+		combined[name] = result
+	}
+	return combined
+}
+
+// buildCheckResults arranges the results of a run, keyed by check name, in the CheckResults of the status
+func buildCheckResults(systemResults, kubernetesResults map[string]nodecheckv1alpha1.CheckResult) nodecheckv1alpha1.CheckResults {
 	// Build SystemCheckResults struct
 	systemCheckResults := nodecheckv1alpha1.SystemCheckResults{}
 	if result, ok := systemResults["uptime"]; ok {
@@ -489,6 +957,90 @@ func (r *NodeCheckExecutorReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	if result, ok := systemResults["kernel_modules"]; ok {
 		systemCheckResults.KernelModules = &result
 	}
+	if result, ok := systemResults["kernel_taint"]; ok {
+		systemCheckResults.KernelTaint = &result
+	}
+	if result, ok := systemResults["pressure_stall"]; ok {
+		systemCheckResults.PressureStall = &result
+	}
+	if result, ok := systemResults["entropy"]; ok {
+		systemCheckResults.Entropy = &result
+	}
+	if result, ok := systemResults["transparent_hugepages"]; ok {
+		systemCheckResults.TransparentHugePages = &result
+	}
+	if result, ok := systemResults["huge_pages"]; ok {
+		systemCheckResults.HugePages = &result
+	}
+	if result, ok := systemResults["numa"]; ok {
+		systemCheckResults.NUMA = &result
+	}
+	if result, ok := systemResults["conntrack"]; ok {
+		systemCheckResults.Conntrack = &result
+	}
+	if result, ok := systemResults["known_issues"]; ok {
+		systemCheckResults.KnownIssues = &result
+	}
+	if result, ok := systemResults["kdump"]; ok {
+		systemCheckResults.Kdump = &result
+	}
+	if result, ok := systemResults["sysctl_drift"]; ok {
+		systemCheckResults.SysctlDrift = &result
+	}
+	if result, ok := systemResults["inotify"]; ok {
+		systemCheckResults.Inotify = &result
+	}
+	if result, ok := systemResults["service_restarts"]; ok {
+		systemCheckResults.ServiceRestarts = &result
+	}
+	if result, ok := systemResults["core_dumps"]; ok {
+		systemCheckResults.CoreDumps = &result
+	}
+	if result, ok := systemResults["time_drift"]; ok {
+		systemCheckResults.TimeDrift = &result
+	}
+	if result, ok := systemResults["audit"]; ok {
+		systemCheckResults.Audit = &result
+	}
+	if result, ok := systemResults["cpu_vulnerabilities"]; ok {
+		systemCheckResults.CPUVulnerabilities = &result
+	}
+	if result, ok := systemResults["kernel_cmdline"]; ok {
+		systemCheckResults.KernelCmdline = &result
+	}
+	if result, ok := systemResults["os_updates"]; ok {
+		systemCheckResults.OSUpdates = &result
+	}
+	if result, ok := systemResults["cgroup_driver"]; ok {
+		systemCheckResults.CgroupDriver = &result
+	}
+	if result, ok := systemResults["process_limits"]; ok {
+		systemCheckResults.ProcessLimits = &result
+	}
+	if result, ok := systemResults["orphaned_mounts"]; ok {
+		systemCheckResults.OrphanedMounts = &result
+	}
+	if result, ok := systemResults["userspace_oom"]; ok {
+		systemCheckResults.UserspaceOOM = &result
+	}
+	if result, ok := systemResults["gpu"]; ok {
+		systemCheckResults.GPU = &result
+	}
+	if result, ok := systemResults["sriov"]; ok {
+		systemCheckResults.SRIOV = &result
+	}
+	if result, ok := systemResults["kernel_lockups"]; ok {
+		systemCheckResults.KernelLockups = &result
+	}
+	if result, ok := systemResults["swap_policy"]; ok {
+		systemCheckResults.SwapPolicy = &result
+	}
+	if result, ok := systemResults["certificate_expiry"]; ok {
+		systemCheckResults.CertificateExpiry = &result
+	}
+	if result, ok := systemResults["baseline_drift"]; ok {
+		systemCheckResults.BaselineDrift = &result
+	}
 	
 	// Build HardwareCheckResults
 	hardwareResults := &nodecheckv1alpha1.HardwareCheckResults{}
@@ -557,10 +1109,20 @@ func (r *NodeCheckExecutorReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	if result, ok := systemResults["disk_mount_points"]; ok {
 		diskResults.MountPoints = &result
 	}
+	if result, ok := systemResults["disk_write_probe"]; ok {
+		diskResults.WriteProbe = &result
+	}
+	if result, ok := systemResults["disk_runtime_storage"]; ok {
+		diskResults.RuntimeStorage = &result
+	}
+	if result, ok := systemResults["disk_wear_level"]; ok {
+		diskResults.WearLevel = &result
+	}
 	if diskResults.Space != nil || diskResults.SMART != nil || diskResults.Performance != nil || 
 	   diskResults.RAID != nil || diskResults.PVs != nil || diskResults.LVM != nil ||
 	   diskResults.IOWait != nil || diskResults.QueueDepth != nil || diskResults.FilesystemErrors != nil ||
-	   diskResults.InodeUsage != nil || diskResults.MountPoints != nil {
+	   diskResults.InodeUsage != nil || diskResults.MountPoints != nil || diskResults.WriteProbe != nil ||
+	   diskResults.RuntimeStorage != nil || diskResults.WearLevel != nil {
 		systemCheckResults.Disks = diskResults
 	}
 	
@@ -593,9 +1155,19 @@ func (r *NodeCheckExecutorReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	if result, ok := systemResults["network_firewall_rules"]; ok {
 		networkResults.FirewallRules = &result
 	}
+	if result, ok := systemResults["network_egress"]; ok {
+		networkResults.Egress = &result
+	}
+	if result, ok := systemResults["network_ingress"]; ok {
+		networkResults.Ingress = &result
+	}
+	if result, ok := systemResults["network_resolv_conf"]; ok {
+		networkResults.ResolvConf = &result
+	}
 	if networkResults.Interfaces != nil || networkResults.Routing != nil || networkResults.Connectivity != nil || networkResults.Statistics != nil ||
 	   networkResults.Errors != nil || networkResults.Latency != nil || networkResults.DNSResolution != nil ||
-	   networkResults.BondingStatus != nil || networkResults.FirewallRules != nil {
+	   networkResults.BondingStatus != nil || networkResults.FirewallRules != nil || networkResults.Egress != nil ||
+	   networkResults.Ingress != nil || networkResults.ResolvConf != nil {
 		systemCheckResults.Network = networkResults
 	}
 
@@ -628,61 +1200,108 @@ func (r *NodeCheckExecutorReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	if result, ok := kubernetesResults["node_conditions"]; ok {
 		kubernetesCheckResults.NodeConditions = &result
 	}
+	if result, ok := kubernetesResults["pod_scheduling"]; ok {
+		kubernetesCheckResults.PodScheduling = &result
+	}
+	if result, ok := kubernetesResults["pvc_provisioning"]; ok {
+		kubernetesCheckResults.PVCProvisioning = &result
+	}
+	if result, ok := kubernetesResults["pod_network"]; ok {
+		kubernetesCheckResults.PodNetwork = &result
+	}
+	if result, ok := kubernetesResults["lb_health_check"]; ok {
+		kubernetesCheckResults.LBHealthCheck = &result
+	}
 
-	// Update status
-	nodeCheck.Status.NodeName = currentNodeName
-	nodeCheck.Status.OverallStatus = overallStatus
-	nodeCheck.Status.Message = overallMessage
-	nodeCheck.Status.LastCheckTime = metav1.Now()
-	nodeCheck.Status.CheckResults = nodecheckv1alpha1.CheckResults{
+	return nodecheckv1alpha1.CheckResults{
 		SystemResults:     systemCheckResults,
 		KubernetesResults: kubernetesCheckResults,
 	}
+}
 
-	// Update the status with retry logic for conflict errors
-	maxRetries := 3
-	for i := 0; i < maxRetries; i++ {
-		if err := r.Status().Update(ctx, &nodeCheck); err != nil {
-			if errors.IsConflict(err) {
-				// Conflict error - fetch latest version and retry
-				if i < maxRetries-1 {
-					log.Info("Conflict updating status, retrying...", "attempt", i+1, "maxRetries", maxRetries)
-					// Fetch latest version
-					if err := r.Get(ctx, req.NamespacedName, &nodeCheck); err != nil {
-						log.Error(err, "unable to fetch NodeCheck for retry")
-						return ctrl.Result{}, err
-					}
-					// Re-apply status changes
-					nodeCheck.Status.NodeName = currentNodeName
-					nodeCheck.Status.OverallStatus = overallStatus
-					nodeCheck.Status.Message = overallMessage
-					nodeCheck.Status.LastCheckTime = metav1.Now()
-					nodeCheck.Status.CheckResults = nodecheckv1alpha1.CheckResults{
-						SystemResults:     systemCheckResults,
-						KubernetesResults: kubernetesCheckResults,
-					}
-					time.Sleep(time.Millisecond * 100 * time.Duration(i+1)) // Exponential backoff
-					continue
-				}
-			}
-			// Non-conflict error or max retries reached
-			log.Error(err, "unable to update NodeCheck status")
-			return ctrl.Result{}, err
+// runCheck executes a single check, bounded by the timeout configured for it in spec.timeouts.
+// Checks fall back to their built-in timeouts when none is configured.
+func runCheck(ctx context.Context, log logr.Logger, spec *nodecheckv1alpha1.NodeCheckSpec, nodeName, runID, name string, check func(context.Context) *nodecheckv1alpha1.CheckResult) nodecheckv1alpha1.CheckResult {
+	if timeout := checkTimeout(spec, name); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	result := *check(ctx)
+	result.RunID = runID
+	logCheckResult(log, spec.ResultLogging, nodeName, name, &result, time.Since(start))
+	return result
+}
+
+// logCheckResult logs a completed check as a single structured entry, according to spec.resultLogging
+func logCheckResult(log logr.Logger, mode, nodeName, name string, result *nodecheckv1alpha1.CheckResult, duration time.Duration) {
+	switch mode {
+	case "All":
+	case "NonHealthy":
+		if result.Status == "Healthy" {
+			return
 		}
-		// Success
-		break
+	default:
+		return
 	}
 
-	log.Info("NodeCheck checks executed successfully", "node", currentNodeName, "status", overallStatus)
+	log.Info("Check completed",
+		"node", nodeName,
+		"check", name,
+		"runID", result.RunID,
+		"status", result.Status,
+		"durationMs", duration.Milliseconds(),
+		"message", result.Message,
+		"values", checkResultValues(result))
+}
 
-	// Reconcile again after the specified interval
-	return ctrl.Result{RequeueAfter: interval}, nil
+// checkResultValues extracts the top-level numeric and boolean details of a check result
+// (e.g. usage percentages, counters), which are the values worth indexing in log pipelines
+func checkResultValues(result *nodecheckv1alpha1.CheckResult) map[string]interface{} {
+	values := make(map[string]interface{})
+	if len(result.Details.Raw) == 0 {
+		return values
+	}
+	var details map[string]interface{}
+	if err := json.Unmarshal(result.Details.Raw, &details); err != nil {
+		return values
+	}
+	for key, value := range details {
+		switch value.(type) {
+		case float64, bool:
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// checkTimeout returns the configured timeout for a check, or 0 if none is set
+func checkTimeout(spec *nodecheckv1alpha1.NodeCheckSpec, name string) time.Duration {
+	if spec.Timeouts == nil {
+		return 0
+	}
+	if timeout, ok := spec.Timeouts.Checks[name]; ok && timeout.Duration > 0 {
+		return timeout.Duration
+	}
+	if spec.Timeouts.Default != nil && spec.Timeouts.Default.Duration > 0 {
+		return spec.Timeouts.Default.Duration
+	}
+	return 0
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *NodeCheckExecutorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.backoff = newCheckBackoff()
+	r.dependencies = newCheckDependencies()
+	r.recorder = mgr.GetEventRecorderFor("node-check-executor")
+	// Every check category of a NodeCheck is queued as its own work item, so up to one reconcile
+	// per category runs at a time
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&nodecheckv1alpha1.NodeCheck{}).
-		Complete(r)
+		Named("nodecheck").
+		Watches(&nodecheckv1alpha1.NodeCheck{}, handler.EnqueueRequestsFromMapFunc(categoryRequests)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: len(checkCategories)}).
+		Complete(selfstatus.Track("NodeCheckExecutor", r))
 }
 
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// createExecutorNode creates the node of the executor of the suite, once
+func createExecutorNode(t *testing.T) {
+	t.Helper()
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   envtestNodeName,
+		Labels: map[string]string{nodeRoleLabelPrefix + "worker": ""},
+	}}
+	if err := k8sClient.Create(context.Background(), node); err != nil && !errors.IsAlreadyExists(err) {
+		t.Fatalf("unable to create node %s: %v", envtestNodeName, err)
+	}
+}
+
+// createNodeCheck creates a NodeCheck of the suite namespace running the uptime check, deleted at the
+// end of the test
+func createNodeCheck(t *testing.T, name, nodeName string) *nodecheckv1alpha1.NodeCheck {
+	t.Helper()
+	nodeCheck := &nodecheckv1alpha1.NodeCheck{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: envtestNamespace},
+		Spec: nodecheckv1alpha1.NodeCheckSpec{
+			NodeName:      nodeName,
+			CheckInterval: 5,
+			SystemChecks:  nodecheckv1alpha1.SystemChecks{Uptime: true},
+		},
+	}
+	if err := k8sClient.Create(context.Background(), nodeCheck); err != nil {
+		t.Fatalf("unable to create NodeCheck %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		k8sClient.Delete(context.Background(), nodeCheck)
+	})
+	return nodeCheck
+}
+
+func TestExecutorRunsTheChecksOfItsNode(t *testing.T) {
+	createExecutorNode(t)
+	createNodeCheck(t, "executor-own-node", envtestNodeName)
+
+	eventually(t, func() error {
+		var nodeCheck nodecheckv1alpha1.NodeCheck
+		if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: envtestNamespace, Name: "executor-own-node"}, &nodeCheck); err != nil {
+			return err
+		}
+		uptime := nodeCheck.Status.CheckResults.SystemResults.Uptime
+		if uptime == nil {
+			return fmt.Errorf("no uptime result yet")
+		}
+		if uptime.RunID == "" {
+			return fmt.Errorf("uptime result without a run ID")
+		}
+		if nodeCheck.Status.LastCheckTime.IsZero() {
+			return fmt.Errorf("lastCheckTime not set")
+		}
+		return nil
+	})
+}
+
+func TestExecutorDetectsTheNodeName(t *testing.T) {
+	createExecutorNode(t)
+	createNodeCheck(t, "executor-detected-node", "")
+
+	eventually(t, func() error {
+		var nodeCheck nodecheckv1alpha1.NodeCheck
+		if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: envtestNamespace, Name: "executor-detected-node"}, &nodeCheck); err != nil {
+			return err
+		}
+		if nodeCheck.Spec.NodeName != envtestNodeName {
+			return fmt.Errorf("nodeName is %q, want %q", nodeCheck.Spec.NodeName, envtestNodeName)
+		}
+		return nil
+	})
+}
+
+func TestExecutorSkipsOtherNodes(t *testing.T) {
+	createNodeCheck(t, "executor-other-node", "other-node")
+
+	// The executor of another node never writes the status
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		var nodeCheck nodecheckv1alpha1.NodeCheck
+		if err := k8sClient.Get(context.Background(), client.ObjectKey{Namespace: envtestNamespace, Name: "executor-other-node"}, &nodeCheck); err != nil {
+			t.Fatal(err)
+		}
+		if nodeCheck.Status.CheckResults.SystemResults.Uptime != nil || !nodeCheck.Status.LastCheckTime.IsZero() {
+			t.Fatalf("the executor of %s ran the checks of a NodeCheck of other-node", envtestNodeName)
+		}
+		time.Sleep(eventuallyInterval)
+	}
+}
@@ -0,0 +1,168 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/checks"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionChecksSkipped is set on a NodeCheck while some checks are skipped because their prerequisites are not met
+const ConditionChecksSkipped = "ChecksSkipped"
+
+// capabilityPrefix marks a prerequisite in spec.checkDependencies as a node capability instead of a check
+const capabilityPrefix = "capability:"
+
+// capabilityProbeTTL is how long a capability probe result is reused before the node is probed again
+const capabilityProbeTTL = time.Hour
+
+// defaultCheckDependencies are the prerequisites of checks without an entry in spec.checkDependencies
+var defaultCheckDependencies = map[string][]string{
+	"disk_lvm":      {capabilityPrefix + checks.CapabilityLVM},
+	"disk_pvs":      {capabilityPrefix + checks.CapabilityLVM},
+	"hardware_ipmi": {capabilityPrefix + checks.CapabilityIPMI},
+	"hardware_bmc":  {capabilityPrefix + checks.CapabilityIPMI},
+}
+
+// checkPrerequisites returns the prerequisites of a check: spec.checkDependencies overrides the defaults
+func checkPrerequisites(spec *nodecheckv1alpha1.NodeCheckSpec, name string) []string {
+	if prerequisites, ok := spec.CheckDependencies[name]; ok {
+		return prerequisites
+	}
+	return defaultCheckDependencies[name]
+}
+
+// capabilityProbe is the cached result of probing a node capability
+type capabilityProbe struct {
+	present  bool
+	reason   string
+	probedAt time.Time
+}
+
+// checkDependencies evaluates the prerequisites of checks. Capability probes are cached, so a node
+// without LVM or a BMC is not probed on every run; since the executor runs on a single node, the
+// cache is per node. It also remembers the checks skipped per NodeCheck for the ChecksSkipped condition.
+// The state lives in the executor's memory and is reset when the executor restarts.
+type checkDependencies struct {
+	mu           sync.Mutex
+	capabilities map[string]capabilityProbe
+	skipped      map[string]map[string]string
+}
+
+// newCheckDependencies creates an empty dependency tracker
+func newCheckDependencies() *checkDependencies {
+	return &checkDependencies{
+		capabilities: make(map[string]capabilityProbe),
+		skipped:      make(map[string]map[string]string),
+	}
+}
+
+// hasCapability reports whether the node has a capability, probing it if the cached result expired.
+// Capabilities that cannot be probed are assumed present so the check runs and reports the problem.
+func (d *checkDependencies) hasCapability(ctx context.Context, name string, now time.Time) (bool, string) {
+	if !checks.IsKnownCapability(name) {
+		return false, fmt.Sprintf("unknown capability %q", name)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if probe, ok := d.capabilities[name]; ok && now.Sub(probe.probedAt) < capabilityProbeTTL {
+		return probe.present, probe.reason
+	}
+	present, reason, err := checks.ProbeCapability(ctx, name)
+	if err != nil {
+		return true, ""
+	}
+	d.capabilities[name] = capabilityProbe{present: present, reason: reason, probedAt: now}
+	return present, reason
+}
+
+// unmetPrerequisite returns why a check must be skipped, or "" if all its prerequisites are met.
+// Check prerequisites use the latest result: from this run if the check already ran, else the previous one.
+func (d *checkDependencies) unmetPrerequisite(ctx context.Context, spec *nodecheckv1alpha1.NodeCheckSpec, name string, latest func(string) (nodecheckv1alpha1.CheckResult, bool), now time.Time) string {
+	for _, prerequisite := range checkPrerequisites(spec, name) {
+		if capability := strings.TrimPrefix(prerequisite, capabilityPrefix); capability != prerequisite {
+			if present, reason := d.hasCapability(ctx, capability, now); !present {
+				return reason
+			}
+			continue
+		}
+		result, ok := latest(prerequisite)
+		if !ok {
+			return fmt.Sprintf("prerequisite %s has no result", prerequisite)
+		}
+		if result.Status != "Healthy" {
+			return fmt.Sprintf("prerequisite %s is %s", prerequisite, result.Status)
+		}
+	}
+	return ""
+}
+
+// startRun forgets the skipped checks of the categories about to run, as they are evaluated again
+func (d *checkDependencies) startRun(nodeCheck string, due map[string]bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for name := range d.skipped[nodeCheck] {
+		if due[checkCategory(name, kubernetesCheckNames[name])] {
+			delete(d.skipped[nodeCheck], name)
+		}
+	}
+}
+
+// markSkipped records that a check was skipped in this run
+func (d *checkDependencies) markSkipped(nodeCheck, name, reason string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.skipped[nodeCheck] == nil {
+		d.skipped[nodeCheck] = make(map[string]string)
+	}
+	d.skipped[nodeCheck][name] = reason
+}
+
+// forget drops the skipped checks of a NodeCheck (e.g. when it is deleted)
+func (d *checkDependencies) forget(nodeCheck string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.skipped, nodeCheck)
+}
+
+// skippedCondition builds the ChecksSkipped condition for a NodeCheck from the skipped checks
+func (d *checkDependencies) skippedCondition(nodeCheck string, generation int64) metav1.Condition {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	skipped := []string{}
+	for name, reason := range d.skipped[nodeCheck] {
+		skipped = append(skipped, fmt.Sprintf("%s: %s", name, reason))
+	}
+	sort.Strings(skipped)
+
+	if len(skipped) == 0 {
+		return metav1.Condition{
+			Type:               ConditionChecksSkipped,
+			Status:             metav1.ConditionFalse,
+			Reason:             "PrerequisitesMet",
+			Message:            "All enabled checks have their prerequisites met",
+			ObservedGeneration: generation,
+		}
+	}
+	return metav1.Condition{
+		Type:               ConditionChecksSkipped,
+		Status:             metav1.ConditionTrue,
+		Reason:             "PrerequisitesNotMet",
+		Message:            "Checks skipped because their prerequisites are not met: " + strings.Join(skipped, "; "),
+		ObservedGeneration: generation,
+	}
+}
+
+// setChecksSkippedCondition sets the ChecksSkipped condition on the NodeCheck status
+func setChecksSkippedCondition(status *nodecheckv1alpha1.NodeCheckStatus, condition metav1.Condition) {
+	meta.SetStatusCondition(&status.Conditions, condition)
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// trimResultDetails drops the details of the results that spec.reportDetail does not keep.
+// Status and message are always kept, so the overall status, the history and the dashboard
+// summaries are not affected.
+func trimResultDetails(mode string, results map[string]nodecheckv1alpha1.CheckResult) {
+	switch mode {
+	case "Summary", "FailuresOnly":
+	default:
+		return
+	}
+	for name, result := range results {
+		if len(result.Details.Raw) == 0 && result.Details.Object == nil {
+			continue
+		}
+		if mode == "FailuresOnly" && result.Status != "Healthy" {
+			continue
+		}
+		result.Details = runtime.RawExtension{}
+		results[name] = result
+	}
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/cloudevents"
+)
+
+const (
+	// EventReasonCheckDegraded is the reason of the Warning Event recorded when a check degrades
+	EventReasonCheckDegraded = "CheckDegraded"
+	// EventReasonCheckRecovered is the reason of the Normal Event recorded when a check is Healthy again
+	EventReasonCheckRecovered = "CheckRecovered"
+)
+
+// checkTransition is a change of status of a check between two runs
+type checkTransition struct {
+	check    string
+	previous string
+	current  string
+	message  string
+	// timestamp is when the check ran with the current status
+	timestamp metav1.Time
+}
+
+// transitionSeverity ranks the statuses that produce Events. Suppressed and Unknown results are
+// not ranked: a maintenance window or a check that could not run is not a transition of the node.
+func transitionSeverity(status string) (int, bool) {
+	switch status {
+	case "Healthy":
+		return 0, true
+	case "Warning":
+		return 1, true
+	case "Critical":
+		return 2, true
+	default:
+		return 0, false
+	}
+}
+
+// statusTransitions compares the results of the previous run with the current ones and returns the
+// checks that degraded or recovered, sorted by check name. Checks without a previous result (first
+// run, newly enabled) are not transitions.
+func statusTransitions(previous, current map[string]nodecheckv1alpha1.CheckResult) []checkTransition {
+	var transitions []checkTransition
+	for name, result := range current {
+		before, ok := previous[name]
+		if !ok || before.Status == result.Status {
+			continue
+		}
+		previousSeverity, ok := transitionSeverity(before.Status)
+		if !ok {
+			continue
+		}
+		currentSeverity, ok := transitionSeverity(result.Status)
+		if !ok {
+			continue
+		}
+		// Warning -> Healthy is a recovery, Critical -> Warning is neither
+		if currentSeverity > previousSeverity || currentSeverity == 0 {
+			transitions = append(transitions, checkTransition{
+				check:     name,
+				previous:  before.Status,
+				current:   result.Status,
+				message:   result.Message,
+				timestamp: result.Timestamp,
+			})
+		}
+	}
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].check < transitions[j].check })
+	return transitions
+}
+
+// recordTransitionEvents records an Event for each transition on the NodeCheck and, with
+// spec.emitEvents.onNode, on the Node so they are listed by kubectl describe node
+func (r *NodeCheckExecutorReconciler) recordTransitionEvents(nodeCheck *nodecheckv1alpha1.NodeCheck, nodeName string, transitions []checkTransition) {
+	events := nodeCheck.Spec.EmitEvents
+	if r.recorder == nil || events == nil || !events.Enabled {
+		return
+	}
+
+	// Node Events use the node name as UID, like the kubelet, which is what kubectl describe node selects on
+	nodeRef := &corev1.ObjectReference{Kind: "Node", Name: nodeName, UID: types.UID(nodeName)}
+	for _, transition := range transitions {
+		eventType, reason := corev1.EventTypeWarning, EventReasonCheckDegraded
+		if transition.current == "Healthy" {
+			eventType, reason = corev1.EventTypeNormal, EventReasonCheckRecovered
+		}
+		message := fmt.Sprintf("Check %s changed from %s to %s: %s", transition.check, transition.previous, transition.current, transition.message)
+
+		r.recorder.Event(nodeCheck, eventType, reason, message)
+		if events.OnNode {
+			r.recorder.Eventf(nodeRef, eventType, reason, "NodeCheck %s/%s: %s", nodeCheck.Namespace, nodeCheck.Name, message)
+		}
+	}
+}
+
+// sendTransitionCloudEvents sends the transitions as CloudEvents (spec.cloudEvents). Like the Events they
+// are best effort: a sink that cannot be reached is logged and the events of the run are not sent again.
+func (r *NodeCheckExecutorReconciler) sendTransitionCloudEvents(ctx context.Context, nodeCheck *nodecheckv1alpha1.NodeCheck, nodeName string, transitions []checkTransition) {
+	spec := nodeCheck.Spec.CloudEvents
+	if spec == nil || spec.Sink == "" || len(transitions) == 0 {
+		return
+	}
+	events := make([]cloudevents.Event, 0, len(transitions))
+	for _, transition := range transitions {
+		events = append(events, cloudevents.New(string(nodeCheck.UID), cloudevents.Transition{
+			Node:      nodeName,
+			Namespace: nodeCheck.Namespace,
+			NodeCheck: nodeCheck.Name,
+			Check:     transition.check,
+			Previous:  transition.previous,
+			Current:   transition.current,
+			Message:   transition.message,
+		}, transition.timestamp.Time))
+	}
+	if err := cloudevents.Send(ctx, spec.Binding, spec.Sink, spec.Topic, events); err != nil {
+		ctrl.Log.WithName("NodeCheckExecutorReconciler").Error(err, "unable to send the check transitions as CloudEvents",
+			"nodeCheck", nodeCheck.Name, "sink", spec.Sink, "events", len(events))
+	}
+}
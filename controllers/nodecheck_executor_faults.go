@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/go-logr/logr"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/faults"
+)
+
+// injectFaults replaces the results of this run with the active faults of the faults.Annotation of
+// the NodeCheck. Only checks that ran get a fault: an injected result goes through maintenance
+// windows, the overall status, events, history and notifications like a real one, and the check
+// reports its real result again on its first run after the fault expires.
+func injectFaults(log logr.Logger, nodeCheck *nodecheckv1alpha1.NodeCheck, systemResults, kubernetesResults map[string]nodecheckv1alpha1.CheckResult, now time.Time) {
+	parsed, err := faults.Parse(nodeCheck.Annotations)
+	if err != nil {
+		log.Error(err, "ignoring injected faults")
+		return
+	}
+	active := faults.Active(parsed, now)
+	if len(active) == 0 {
+		return
+	}
+	injected := []string{}
+	for _, results := range []map[string]nodecheckv1alpha1.CheckResult{systemResults, kubernetesResults} {
+		for name, result := range results {
+			if faults.InjectResult(name, &result, active, now) {
+				results[name] = result
+				injected = append(injected, name)
+			}
+		}
+	}
+	if len(injected) > 0 {
+		log.Info("Injected synthetic check results (fault injection)", "checks", injected)
+	}
+}
@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"sort"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// updateCheckHistory appends the results of the current run to the per-check history, keeping at most
+// size entries per check. Results kept from a previous run (same timestamp as the last entry) are not
+// appended again. Checks without a current result are dropped. A size of 0 disables the history.
+func updateCheckHistory(previous []nodecheckv1alpha1.CheckHistory, results map[string]nodecheckv1alpha1.CheckResult, size int) []nodecheckv1alpha1.CheckHistory {
+	if size <= 0 {
+		return nil
+	}
+
+	previousByName := make(map[string]nodecheckv1alpha1.CheckHistory, len(previous))
+	for _, history := range previous {
+		previousByName[history.Name] = history
+	}
+
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	updated := make([]nodecheckv1alpha1.CheckHistory, 0, len(names))
+	for _, name := range names {
+		result := results[name]
+		history := previousByName[name]
+		history.Name = name
+		entries := append([]nodecheckv1alpha1.CheckHistoryEntry{}, history.Entries...)
+
+		last := len(entries) - 1
+		if last < 0 || result.Timestamp.Time.After(entries[last].Timestamp.Time) {
+			if last >= 0 && entries[last].Status != result.Status {
+				transitionTime := result.Timestamp
+				history.LastTransitionTime = &transitionTime
+			}
+			entries = append(entries, nodecheckv1alpha1.CheckHistoryEntry{
+				Timestamp: result.Timestamp,
+				Status:    result.Status,
+			})
+		}
+		if len(entries) > size {
+			entries = entries[len(entries)-size:]
+		}
+
+		history.Entries = entries
+		history.Transitions = 0
+		for i := 1; i < len(entries); i++ {
+			if entries[i].Status != entries[i-1].Status {
+				history.Transitions++
+			}
+		}
+		updated = append(updated, history)
+	}
+	return updated
+}
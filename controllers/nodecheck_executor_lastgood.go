@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// lastKnownGoodDetail is the detail of a Warning or Critical result holding the last Healthy result of the check
+const lastKnownGoodDetail = "last_known_good"
+
+// lastKnownGood is the last Healthy result of a failing check: when it was taken, its message and its
+// top-level values, so responders see what changed and when without querying the history
+type lastKnownGood struct {
+	Timestamp metav1.Time            `json:"timestamp"`
+	Message   string                 `json:"message,omitempty"`
+	Values    map[string]interface{} `json:"values,omitempty"`
+}
+
+// attachLastKnownGood adds the last Healthy result of each check to its Warning and Critical results of this
+// run. A check that was already failing carries over the last Healthy result stored in its previous result.
+func attachLastKnownGood(results, previousResults map[string]nodecheckv1alpha1.CheckResult) {
+	for name, result := range results {
+		if result.Status != "Warning" && result.Status != "Critical" {
+			continue
+		}
+		previous, ok := previousResults[name]
+		// A backed-off check returns its previous result, which already carries the detail
+		if !ok || previous.Timestamp.Equal(&result.Timestamp) {
+			continue
+		}
+
+		var good string
+		if previous.Status == "Healthy" {
+			data, err := json.Marshal(lastKnownGood{
+				Timestamp: previous.Timestamp,
+				Message:   previous.Message,
+				Values:    checkResultValues(&previous),
+			})
+			if err != nil {
+				continue
+			}
+			good = string(data)
+		} else {
+			good = detailString(&previous, lastKnownGoodDetail)
+		}
+		if good == "" {
+			continue
+		}
+
+		details := make(map[string]interface{})
+		if len(result.Details.Raw) > 0 {
+			if err := json.Unmarshal(result.Details.Raw, &details); err != nil {
+				continue
+			}
+		}
+		// Stored as a JSON string like the other nested details (see CheckResult.DetailsMap)
+		details[lastKnownGoodDetail] = good
+		data, err := json.Marshal(details)
+		if err != nil {
+			continue
+		}
+		result.Details = runtime.RawExtension{Raw: data}
+		results[name] = result
+	}
+}
+
+// detailString returns a string detail of a result, or "" if it is not set
+func detailString(result *nodecheckv1alpha1.CheckResult, key string) string {
+	if len(result.Details.Raw) == 0 {
+		return ""
+	}
+	var details map[string]interface{}
+	if err := json.Unmarshal(result.Details.Raw, &details); err != nil {
+		return ""
+	}
+	value, _ := details[key].(string)
+	return value
+}
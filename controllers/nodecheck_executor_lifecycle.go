@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// machineConfigAnnotation is the MachineConfig the Machine Config Daemon last applied to a node
+const machineConfigAnnotation = "machineconfiguration.openshift.io/currentConfig"
+
+// Lifecycle event types
+const (
+	lifecycleProvisioned          = "Provisioned"
+	lifecycleRebooted             = "Rebooted"
+	lifecycleKubeletUpgraded      = "KubeletUpgraded"
+	lifecycleMachineConfigUpdated = "MachineConfigUpdated"
+	lifecycleVerificationPassed   = "VerificationPassed"
+	lifecycleVerificationFailed   = "VerificationFailed"
+)
+
+// nodeLifecycleObservation is the state of a node the lifecycle events are detected from
+type nodeLifecycleObservation struct {
+	nodeName       string
+	created        metav1.Time
+	bootID         string
+	kubeletVersion string
+	machineConfig  string
+}
+
+// observeNodeLifecycle reads the lifecycle state of a node. It returns nil when the node cannot be read,
+// so the lifecycle recorded in the status is left unchanged.
+func (r *NodeCheckExecutorReconciler) observeNodeLifecycle(ctx context.Context, log logr.Logger, nodeName string) *nodeLifecycleObservation {
+	var node corev1.Node
+	if err := r.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		log.Error(err, "unable to read node, skipping lifecycle events", "node", nodeName)
+		return nil
+	}
+	return &nodeLifecycleObservation{
+		nodeName:       nodeName,
+		created:        node.CreationTimestamp,
+		bootID:         node.Status.NodeInfo.BootID,
+		kubeletVersion: node.Status.NodeInfo.KubeletVersion,
+		machineConfig:  node.Annotations[machineConfigAnnotation],
+	}
+}
+
+// setLifecycle records the lifecycle events detected since the previous run: the first run records when
+// the node was provisioned, later runs a reboot (new boot ID), a kubelet upgrade or a MachineConfig
+// update. At most spec.historySize events are kept; the lifecycle is dropped when the history is disabled.
+func setLifecycle(status *nodecheckv1alpha1.NodeCheckStatus, spec *nodecheckv1alpha1.NodeCheckSpec, observed *nodeLifecycleObservation, now metav1.Time) {
+	if spec.HistorySize <= 0 {
+		status.Lifecycle = nil
+		return
+	}
+	if observed == nil {
+		return
+	}
+
+	lifecycle := status.Lifecycle.DeepCopy()
+	if lifecycle == nil {
+		lifecycle = &nodecheckv1alpha1.NodeLifecycle{}
+		lifecycle.Events = append(lifecycle.Events, nodecheckv1alpha1.LifecycleEvent{
+			Type:      lifecycleProvisioned,
+			Timestamp: observed.created,
+			Message:   fmt.Sprintf("Node %s added to the cluster with kubelet %s", observed.nodeName, observed.kubeletVersion),
+		})
+	} else {
+		if lifecycle.BootID != "" && observed.bootID != "" && lifecycle.BootID != observed.bootID {
+			lifecycle.Events = append(lifecycle.Events, nodecheckv1alpha1.LifecycleEvent{
+				Type:      lifecycleRebooted,
+				Timestamp: now,
+				Message:   fmt.Sprintf("Node rebooted (boot ID %s)", observed.bootID),
+			})
+		}
+		if lifecycle.KubeletVersion != "" && observed.kubeletVersion != "" && lifecycle.KubeletVersion != observed.kubeletVersion {
+			lifecycle.Events = append(lifecycle.Events, nodecheckv1alpha1.LifecycleEvent{
+				Type:      lifecycleKubeletUpgraded,
+				Timestamp: now,
+				Message:   fmt.Sprintf("kubelet %s -> %s", lifecycle.KubeletVersion, observed.kubeletVersion),
+			})
+		}
+		if lifecycle.MachineConfig != "" && observed.machineConfig != "" && lifecycle.MachineConfig != observed.machineConfig {
+			lifecycle.Events = append(lifecycle.Events, nodecheckv1alpha1.LifecycleEvent{
+				Type:      lifecycleMachineConfigUpdated,
+				Timestamp: now,
+				Message:   fmt.Sprintf("MachineConfig %s -> %s", lifecycle.MachineConfig, observed.machineConfig),
+			})
+		}
+	}
+	if observed.bootID != "" {
+		lifecycle.BootID = observed.bootID
+	}
+	if observed.kubeletVersion != "" {
+		lifecycle.KubeletVersion = observed.kubeletVersion
+	}
+	if observed.machineConfig != "" {
+		lifecycle.MachineConfig = observed.machineConfig
+	}
+	if len(lifecycle.Events) > spec.HistorySize {
+		lifecycle.Events = lifecycle.Events[len(lifecycle.Events)-spec.HistorySize:]
+	}
+	status.Lifecycle = lifecycle
+}
@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+const (
+	// StampedLabelsAnnotation lists the label keys stamped from spec.resultLabels, so keys removed
+	// from the spec are also removed from the NodeCheck
+	StampedLabelsAnnotation = "node-check.openshift.io/stamped-labels"
+	// StampedAnnotationsAnnotation lists the annotation keys stamped from spec.resultAnnotations
+	StampedAnnotationsAnnotation = "node-check.openshift.io/stamped-annotations"
+)
+
+// stampMap sets desired on current, removes the previously stamped keys no longer desired and
+// returns the updated map, the new list of stamped keys and whether anything changed
+func stampMap(current, desired map[string]string, previouslyStamped string) (map[string]string, string, bool) {
+	changed := false
+	if current == nil {
+		current = make(map[string]string)
+	}
+	for _, key := range strings.Split(previouslyStamped, ",") {
+		if _, ok := desired[key]; key != "" && !ok {
+			if _, exists := current[key]; exists {
+				delete(current, key)
+				changed = true
+			}
+		}
+	}
+	keys := make([]string, 0, len(desired))
+	for key, value := range desired {
+		keys = append(keys, key)
+		if existing, ok := current[key]; !ok || existing != value {
+			current[key] = value
+			changed = true
+		}
+	}
+	sort.Strings(keys)
+	return current, strings.Join(keys, ","), changed
+}
+
+// stampResultMetadata applies spec.resultLabels and spec.resultAnnotations to the NodeCheck metadata,
+// so downstream systems (alert routing, inventories) can find the owner of a node's results.
+// It reports whether the NodeCheck must be updated.
+func stampResultMetadata(nodeCheck *nodecheckv1alpha1.NodeCheck) bool {
+	annotations := nodeCheck.Annotations
+	labels, stampedLabels, labelsChanged := stampMap(nodeCheck.Labels, nodeCheck.Spec.ResultLabels, annotations[StampedLabelsAnnotation])
+	annotations, stampedAnnotations, annotationsChanged := stampMap(annotations, nodeCheck.Spec.ResultAnnotations, annotations[StampedAnnotationsAnnotation])
+
+	bookkeepingChanged := false
+	for key, value := range map[string]string{StampedLabelsAnnotation: stampedLabels, StampedAnnotationsAnnotation: stampedAnnotations} {
+		existing, ok := annotations[key]
+		switch {
+		case value == "" && ok:
+			delete(annotations, key)
+			bookkeepingChanged = true
+		case value != "" && existing != value:
+			annotations[key] = value
+			bookkeepingChanged = true
+		}
+	}
+
+	if !labelsChanged && !annotationsChanged && !bookkeepingChanged {
+		return false
+	}
+	nodeCheck.Labels = labels
+	nodeCheck.Annotations = annotations
+	return true
+}
+
+// applyResultMetadata stamps spec.resultLabels and spec.resultAnnotations onto the NodeCheck.
+// Failures are not fatal: the next run tries again.
+func (r *NodeCheckExecutorReconciler) applyResultMetadata(ctx context.Context, nodeCheck *nodecheckv1alpha1.NodeCheck) error {
+	if !stampResultMetadata(nodeCheck) {
+		return nil
+	}
+	return r.Update(ctx, nodeCheck)
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/checks"
+)
+
+const (
+	// DisableChecksAnnotation lists the checks that do not run on a node, comma-separated. Checks are named
+	// as in the results (e.g. "disk_smart,hardware_ipmi"); the disk_, hardware_ and network_ prefixes can be
+	// omitted ("smart,ipmi").
+	DisableChecksAnnotation = "nodecheck.openshift.io/disable"
+	// ThresholdsAnnotation overrides spec.thresholds on a node, as JSON keyed by check name
+	// (e.g. {"disk_space":{"warning":90,"critical":97}})
+	ThresholdsAnnotation = "nodecheck.openshift.io/thresholds"
+)
+
+// nodeOverrides are the check overrides read from the annotations of a node, so a single machine can be
+// adjusted without editing the NodeCheck specs
+type nodeOverrides struct {
+	disabled   map[string]bool
+	thresholds map[string]nodecheckv1alpha1.CheckThresholds
+}
+
+// parseNodeOverrides reads the overrides from the annotations of a node. An invalid thresholds
+// annotation is ignored as a whole and reported in the returned error.
+func parseNodeOverrides(annotations map[string]string) (nodeOverrides, error) {
+	overrides := nodeOverrides{disabled: map[string]bool{}}
+	for _, name := range strings.Split(annotations[DisableChecksAnnotation], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			overrides.disabled[name] = true
+		}
+	}
+
+	value := strings.TrimSpace(annotations[ThresholdsAnnotation])
+	if value == "" {
+		return overrides, nil
+	}
+	var thresholds map[string]nodecheckv1alpha1.CheckThresholds
+	if err := json.Unmarshal([]byte(value), &thresholds); err != nil {
+		return overrides, fmt.Errorf("invalid %s annotation: %w", ThresholdsAnnotation, err)
+	}
+	for name, threshold := range thresholds {
+		if !checks.ThresholdChecks[name] {
+			return overrides, fmt.Errorf("invalid %s annotation: check %q has no thresholds", ThresholdsAnnotation, name)
+		}
+		if threshold.Warning < 0 || threshold.Warning > 100 || threshold.Critical < 0 || threshold.Critical > 100 {
+			return overrides, fmt.Errorf("invalid %s annotation: thresholds of %s must be between 0 and 100", ThresholdsAnnotation, name)
+		}
+		if threshold.Warning > 0 && threshold.Critical > 0 && threshold.Warning >= threshold.Critical {
+			return overrides, fmt.Errorf("invalid %s annotation: warning threshold of %s must be lower than the critical one", ThresholdsAnnotation, name)
+		}
+	}
+	overrides.thresholds = thresholds
+	return overrides, nil
+}
+
+// disables reports whether the node disables a check
+func (o nodeOverrides) disables(name string) bool {
+	if o.disabled[name] {
+		return true
+	}
+	for _, prefix := range []string{"disk_", "hardware_", "network_"} {
+		if short, ok := strings.CutPrefix(name, prefix); ok && o.disabled[short] {
+			return true
+		}
+	}
+	return false
+}
+
+// applyThresholds returns spec.thresholds with the node overrides applied. A value left unset (0)
+// in the annotation keeps the one of the spec.
+func (o nodeOverrides) applyThresholds(spec map[string]nodecheckv1alpha1.CheckThresholds) map[string]nodecheckv1alpha1.CheckThresholds {
+	if len(o.thresholds) == 0 {
+		return spec
+	}
+	merged := make(map[string]nodecheckv1alpha1.CheckThresholds, len(spec)+len(o.thresholds))
+	for name, threshold := range spec {
+		merged[name] = threshold
+	}
+	for name, override := range o.thresholds {
+		threshold := merged[name]
+		if override.Warning > 0 {
+			threshold.Warning = override.Warning
+		}
+		if override.Critical > 0 {
+			threshold.Critical = override.Critical
+		}
+		merged[name] = threshold
+	}
+	return merged
+}
+
+// nodeOverrides reads the check overrides from the annotations of the node. They are read at every
+// run, so changes to the annotations apply from the next run of the checks.
+func (r *NodeCheckExecutorReconciler) nodeOverrides(ctx context.Context, log logr.Logger, nodeName string) nodeOverrides {
+	var node corev1.Node
+	if err := r.Get(ctx, types.NamespacedName{Name: nodeName}, &node); err != nil {
+		log.Error(err, "unable to read node annotations, running checks without node overrides", "node", nodeName)
+		return nodeOverrides{}
+	}
+	overrides, err := parseNodeOverrides(node.Annotations)
+	if err != nil {
+		log.Error(err, "ignoring node threshold overrides", "node", nodeName)
+	}
+	return overrides
+}
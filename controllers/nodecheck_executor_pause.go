@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConditionPaused is set on a NodeCheck while its check executions are paused
+const ConditionPaused = "Paused"
+
+// SkipAnnotation pauses the check executions of a single NodeCheck when set to "true"
+const SkipAnnotation = "node-check.openshift.io/skip"
+
+// pausedReason returns why the checks of a NodeCheck must not run, or "" if they can run
+func pausedReason(nodeCheck *nodecheckv1alpha1.NodeCheck) string {
+	if nodeCheck.Spec.Paused {
+		return "SpecPaused"
+	}
+	if nodeCheck.Annotations[SkipAnnotation] == "true" {
+		return "SkipAnnotation"
+	}
+	return ""
+}
+
+// pausedCondition builds the Paused condition for a NodeCheck from the reason returned by pausedReason
+func pausedCondition(reason string, generation int64) metav1.Condition {
+	switch reason {
+	case "SpecPaused":
+		return metav1.Condition{
+			Type:               ConditionPaused,
+			Status:             metav1.ConditionTrue,
+			Reason:             reason,
+			Message:            "Check executions are paused by spec.paused; the last results are kept",
+			ObservedGeneration: generation,
+		}
+	case "SkipAnnotation":
+		return metav1.Condition{
+			Type:               ConditionPaused,
+			Status:             metav1.ConditionTrue,
+			Reason:             reason,
+			Message:            "Check executions are paused by the " + SkipAnnotation + " annotation; the last results are kept",
+			ObservedGeneration: generation,
+		}
+	}
+	return metav1.Condition{
+		Type:               ConditionPaused,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Running",
+		Message:            "Checks run at their configured interval",
+		ObservedGeneration: generation,
+	}
+}
+
+// setPausedCondition sets the Paused condition on the NodeCheck status and reports whether it changed
+func setPausedCondition(status *nodecheckv1alpha1.NodeCheckStatus, condition metav1.Condition) bool {
+	existing := meta.FindStatusCondition(status.Conditions, condition.Type)
+	changed := existing == nil || existing.Status != condition.Status || existing.Reason != condition.Reason ||
+		existing.ObservedGeneration != condition.ObservedGeneration
+	meta.SetStatusCondition(&status.Conditions, condition)
+	return changed
+}
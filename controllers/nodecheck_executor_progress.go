@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// ConditionRunInProgress is True while the slow checks of a run are still running. The status then holds
+// the results of the checks completed so far, and the previous results of the others.
+const ConditionRunInProgress = "RunInProgress"
+
+// progressInterval is the minimum time between two updates of the status while the slow checks run
+const progressInterval = 5 * time.Second
+
+// slowChecks are the checks that take seconds to complete: they sample the node (iostat, vmstat, ping),
+// query slow devices (SMART, IPMI) or create workloads. They run after the other checks of a run, whose
+// results are stored in the status first, so the basic health of the node is fresh within seconds.
+var slowChecks = map[string]bool{
+	"resources":            true,
+	"swap_activity":        true,
+	"context_switches":     true,
+	"disk_smart":           true,
+	"disk_performance":     true,
+	"disk_io_wait":         true,
+	"disk_queue_depth":     true,
+	"hardware_ipmi":        true,
+	"hardware_bmc":         true,
+	"network_connectivity": true,
+	"network_latency":      true,
+	"network_egress":       true,
+	"network_ingress":      true,
+	"network_resolv_conf":  true,
+	"pod_network":          true,
+	"lb_health_check":      true,
+	"pod_scheduling":       true,
+	"pvc_provisioning":     true,
+}
+
+// splitPhases splits the scheduled checks in the fast ones, run first, and the slow ones. A check depending
+// on a slow check (spec.checkDependencies) runs with the slow checks, so it still sees the result of this run.
+func splitPhases(spec *nodecheckv1alpha1.NodeCheckSpec, scheduled []scheduledCheck) ([]scheduledCheck, []scheduledCheck) {
+	slowPhase := make(map[string]bool)
+	for _, check := range scheduled {
+		if slowChecks[check.name] {
+			slowPhase[check.name] = true
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, check := range scheduled {
+			if slowPhase[check.name] {
+				continue
+			}
+			for _, prerequisite := range checkPrerequisites(spec, check.name) {
+				if slowPhase[prerequisite] {
+					slowPhase[check.name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	var fast, slow []scheduledCheck
+	for _, check := range scheduled {
+		if slowPhase[check.name] {
+			slow = append(slow, check)
+		} else {
+			fast = append(fast, check)
+		}
+	}
+	return fast, slow
+}
+
+// progressResults returns a copy of the results of a run so far. The scheduled checks that did not complete
+// yet keep their previous result; skipped checks are dropped as in the final results.
+func progressResults(results, previous map[string]nodecheckv1alpha1.CheckResult, pending, skipped map[string]bool) map[string]nodecheckv1alpha1.CheckResult {
+	progress := make(map[string]nodecheckv1alpha1.CheckResult, len(results))
+	for name, result := range results {
+		if !skipped[name] {
+			progress[name] = result
+		}
+	}
+	for name, result := range previous {
+		if _, ok := progress[name]; !ok && pending[name] {
+			progress[name] = result
+		}
+	}
+	return progress
+}
+
+// runInProgressCondition builds the RunInProgress condition
+func runInProgressCondition(inProgress bool, generation int64) metav1.Condition {
+	if inProgress {
+		return metav1.Condition{
+			Type:               ConditionRunInProgress,
+			Status:             metav1.ConditionTrue,
+			Reason:             "SlowChecksRunning",
+			Message:            "Slow checks are still running; the other results of this run are already stored",
+			ObservedGeneration: generation,
+		}
+	}
+	return metav1.Condition{
+		Type:               ConditionRunInProgress,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RunCompleted",
+		Message:            "All the checks of the last run completed",
+		ObservedGeneration: generation,
+	}
+}
+
+// publishProgress stores the results of a run that is still in progress, with its record in status.runs.
+// It patches the status, so the final update of the run is not blocked by a conflict: the resource version
+// of nodeCheck is advanced to the patched one, its status is left untouched.
+func (r *NodeCheckExecutorReconciler) publishProgress(ctx context.Context, nodeCheck *nodecheckv1alpha1.NodeCheck, checkResults nodecheckv1alpha1.CheckResults, run nodecheckv1alpha1.RunRecord, overallStatus, overallMessage, suppressedBy string) error {
+	progress := nodeCheck.DeepCopy()
+	progress.Status.OverallStatus = overallStatus
+	progress.Status.Message = overallMessage
+	progress.Status.CheckResults = checkResults
+	progress.Status.SuppressedBy = suppressedBy
+	recordRun(&progress.Status, run)
+	meta.SetStatusCondition(&progress.Status.Conditions, runInProgressCondition(true, nodeCheck.Generation))
+	if err := r.Status().Patch(ctx, progress, client.MergeFrom(nodeCheck)); err != nil {
+		return err
+	}
+	nodeCheck.ResourceVersion = progress.ResourceVersion
+	return nil
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/checks"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/rulepacks"
+)
+
+// rulePacksReload is how often the executor lists the rule pack ConfigMaps again
+const rulePacksReload = time.Minute
+
+// rulePackCache holds the rules of the rule pack ConfigMaps, shared by the runs of all NodeChecks
+type rulePackCache struct {
+	mu     sync.Mutex
+	rules  *rulepacks.Rules
+	loaded time.Time
+}
+
+// rulePacks returns the rules of the ConfigMaps labelled rulepacks.Label in the executor namespace,
+// listed again every rulePacksReload. When they cannot be listed the previous rules are kept.
+func (r *NodeCheckExecutorReconciler) rulePacks(ctx context.Context, log logr.Logger) *rulepacks.Rules {
+	r.ruleCache.mu.Lock()
+	defer r.ruleCache.mu.Unlock()
+	if r.ruleCache.rules != nil && time.Since(r.ruleCache.loaded) < rulePacksReload {
+		return r.ruleCache.rules
+	}
+
+	namespace := os.Getenv("WATCH_NAMESPACE")
+	if namespace == "" {
+		namespace = operatorconfig.DefaultNamespace
+	}
+	configMaps, err := r.Clientset.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{LabelSelector: rulepacks.Label + "=true"})
+	if err != nil {
+		log.Error(err, "unable to list the rule pack ConfigMaps, keeping the previous rules", "namespace", namespace)
+		if r.ruleCache.rules == nil {
+			return &rulepacks.Rules{}
+		}
+		return r.ruleCache.rules
+	}
+	data := make(map[string]map[string]string, len(configMaps.Items))
+	for _, configMap := range configMaps.Items {
+		data[configMap.Name] = configMap.Data
+	}
+	rules := rulepacks.FromConfigMapData(data)
+	for name := range rules.ThresholdPresets {
+		if !checks.ThresholdChecks[name] {
+			rules.Errors = append(rules.Errors, "threshold preset of "+name+": the check has no thresholds")
+			delete(rules.ThresholdPresets, name)
+		}
+	}
+	for _, reason := range rules.Errors {
+		log.Info("Ignoring invalid rule pack", "reason", reason)
+	}
+	r.ruleCache.rules = rules
+	r.ruleCache.loaded = time.Now()
+	return rules
+}
+
+// withThresholdPresets returns spec.thresholds over the threshold presets of the rule packs. A value
+// left unset (0) in the spec keeps the one of the presets.
+func withThresholdPresets(presets, spec map[string]nodecheckv1alpha1.CheckThresholds) map[string]nodecheckv1alpha1.CheckThresholds {
+	if len(presets) == 0 {
+		return spec
+	}
+	merged := make(map[string]nodecheckv1alpha1.CheckThresholds, len(presets)+len(spec))
+	for name, preset := range presets {
+		merged[name] = preset
+	}
+	for name, threshold := range spec {
+		preset := merged[name]
+		if threshold.Warning > 0 {
+			preset.Warning = threshold.Warning
+		}
+		if threshold.Critical > 0 {
+			preset.Critical = threshold.Critical
+		}
+		merged[name] = preset
+	}
+	return merged
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// maxRunRecords is the number of runs kept in status.runs. The categories of a NodeCheck run in their own
+// work items, so a few runs of each category stay resolvable.
+const maxRunRecords = 20
+
+// newRunID returns the ID of a run, derived from the NodeCheck UID, the categories and the start time like
+// the IDs of the CloudEvents, so it is unique across the NodeChecks and short enough to quote
+func newRunID(uid string, categories []string, start time.Time) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s", uid, strings.Join(categories, ","), start.UTC().Format(time.RFC3339Nano))))
+	return hex.EncodeToString(hash[:8])
+}
+
+// runChecks returns the names of the results of a run, sorted. Results kept from earlier runs (backed-off
+// checks, categories that were not due) carry the ID of their own run and are left out.
+func runChecks(runID string, results ...map[string]nodecheckv1alpha1.CheckResult) []string {
+	checks := []string{}
+	for _, keyed := range results {
+		for name, result := range keyed {
+			if result.RunID == runID {
+				checks = append(checks, name)
+			}
+		}
+	}
+	sort.Strings(checks)
+	return checks
+}
+
+// recordRun stores a run in status.runs, replacing the record of the same run while it is in progress,
+// and keeps the last maxRunRecords runs
+func recordRun(status *nodecheckv1alpha1.NodeCheckStatus, run nodecheckv1alpha1.RunRecord) {
+	runs := make([]nodecheckv1alpha1.RunRecord, 0, len(status.Runs)+1)
+	for _, recorded := range status.Runs {
+		if recorded.ID != run.ID {
+			runs = append(runs, recorded)
+		}
+	}
+	runs = append(runs, run)
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].StartTime.Before(&runs[j].StartTime) })
+	if len(runs) > maxRunRecords {
+		runs = runs[len(runs)-maxRunRecords:]
+	}
+	status.Runs = runs
+}
+
+// runRecord builds the record of a run from its results; completed is false while the slow checks run
+func runRecord(runID string, categories []string, start metav1.Time, completed bool, systemResults, kubernetesResults map[string]nodecheckv1alpha1.CheckResult) nodecheckv1alpha1.RunRecord {
+	run := nodecheckv1alpha1.RunRecord{
+		ID:         runID,
+		Categories: append([]string(nil), categories...),
+		Checks:     runChecks(runID, systemResults, kubernetesResults),
+		StartTime:  start,
+	}
+	if completed {
+		now := metav1.Now()
+		run.CompletionTime = &now
+	}
+	return run
+}
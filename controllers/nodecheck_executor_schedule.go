@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"strings"
+	"time"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// Check categories that can be scheduled independently via spec.categoryIntervals
+const (
+	categorySystem     = "system"
+	categoryDisks      = "disks"
+	categoryNetwork    = "network"
+	categoryHardware   = "hardware"
+	categoryKubernetes = "kubernetes"
+)
+
+var checkCategories = []string{categorySystem, categoryDisks, categoryNetwork, categoryHardware, categoryKubernetes}
+
+// categoryScheduleSlack lets a category run slightly early so that categories sharing an
+// interval are executed in the same reconcile even if their result timestamps differ by a few seconds
+const categoryScheduleSlack = 30 * time.Second
+
+// kubernetesCheckNames are the result keys of the Kubernetes checks
+var kubernetesCheckNames = map[string]bool{
+	"node_status":         true,
+	"pods":                true,
+	"cluster_operators":   true,
+	"node_resources":      true,
+	"node_resource_usage": true,
+	"container_runtime":   true,
+	"kubelet_health":      true,
+	"cni_plugin":          true,
+	"node_conditions":     true,
+	"pod_scheduling":      true,
+	"pod_network":         true,
+	"pvc_provisioning":    true,
+	"lb_health_check":     true,
+}
+
+// checkCategory returns the category of a system or Kubernetes result key
+func checkCategory(key string, kubernetes bool) string {
+	switch {
+	case kubernetes:
+		return categoryKubernetes
+	case strings.HasPrefix(key, "disk_"):
+		return categoryDisks
+	case strings.HasPrefix(key, "network_"):
+		return categoryNetwork
+	case strings.HasPrefix(key, "hardware_"):
+		return categoryHardware
+	default:
+		return categorySystem
+	}
+}
+
+// categoryInterval returns the interval for a category, falling back to the default interval
+func categoryInterval(spec *nodecheckv1alpha1.NodeCheckSpec, category string, defaultInterval time.Duration) time.Duration {
+	if spec.CategoryIntervals == nil {
+		return defaultInterval
+	}
+
+	minutes := 0
+	switch category {
+	case categorySystem:
+		minutes = spec.CategoryIntervals.System
+	case categoryDisks:
+		minutes = spec.CategoryIntervals.Disks
+	case categoryNetwork:
+		minutes = spec.CategoryIntervals.Network
+	case categoryHardware:
+		minutes = spec.CategoryIntervals.Hardware
+	case categoryKubernetes:
+		minutes = spec.CategoryIntervals.Kubernetes
+	}
+	if minutes <= 0 {
+		return defaultInterval
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// categoryEnabled reports whether at least one check of the category is enabled
+func categoryEnabled(spec *nodecheckv1alpha1.NodeCheckSpec, category string) bool {
+	sc := spec.SystemChecks
+	switch category {
+	case categorySystem:
+		return sc.Uptime || sc.Processes || sc.Resources || sc.Memory || sc.UninterruptibleTasks ||
+			sc.Services || sc.SystemLogs || sc.FileDescriptors || sc.ZombieProcesses || sc.NTPSync ||
+			sc.KernelPanics || sc.OOMKiller || sc.CPUFrequency || sc.InterruptsBalance || sc.CPUStealTime ||
+			sc.MemoryFragmentation || sc.SwapActivity || sc.ContextSwitches || sc.SELinuxStatus ||
+			sc.SSHAccess || sc.KernelModules || sc.KernelTaint || sc.PressureStall || sc.Entropy ||
+			sc.TransparentHugePages || sc.HugePages || sc.NUMA || sc.Conntrack || sc.KnownIssues ||
+			sc.Kdump || sc.SysctlDrift || sc.Inotify || sc.ServiceRestarts || sc.CoreDumps || sc.TimeDrift ||
+			sc.Audit || sc.CPUVulnerabilities || sc.KernelCmdline || sc.OSUpdates || sc.CgroupDriver ||
+			sc.ProcessLimits || sc.OrphanedMounts || sc.UserspaceOOM || sc.GPU || sc.SRIOV ||
+			sc.KernelLockups || sc.SwapPolicy || sc.CertificateExpiry ||
+			(spec.Baseline != nil && spec.Baseline.Enabled)
+	case categoryDisks:
+		return sc.Disks.Space || sc.Disks.SMART || sc.Disks.Performance || sc.Disks.RAID ||
+			sc.Disks.PVs || sc.Disks.LVM || sc.Disks.IOWait || sc.Disks.QueueDepth ||
+			sc.Disks.FilesystemErrors || sc.Disks.InodeUsage || sc.Disks.MountPoints || sc.Disks.WriteProbe ||
+			sc.Disks.RuntimeStorage || sc.Disks.WearLevel
+	case categoryNetwork:
+		return sc.Network.Interfaces || sc.Network.Routing || sc.Network.Connectivity || sc.Network.Statistics ||
+			sc.Network.Errors || sc.Network.Latency || sc.Network.DNSResolution || sc.Network.BondingStatus ||
+			sc.Network.FirewallRules || sc.Network.Egress || sc.Network.Ingress || sc.Network.ResolvConf
+	case categoryHardware:
+		return sc.Hardware.Temperature || sc.Hardware.IPMI || sc.Hardware.BMC || sc.Hardware.FanStatus ||
+			sc.Hardware.PowerSupply || sc.Hardware.MemoryErrors || sc.Hardware.PCIeErrors || sc.Hardware.CPUMicrocode
+	case categoryKubernetes:
+		kc := spec.KubernetesChecks
+		return kc.NodeStatus || kc.Pods || kc.ClusterOperators || kc.NodeResources || kc.NodeResourceUsage ||
+			kc.ContainerRuntime || kc.KubeletHealth || kc.CNIPlugin || kc.NodeConditions || kc.PodScheduling ||
+			kc.PVCProvisioning || kc.PodNetwork || kc.LBHealthCheck
+	}
+	return false
+}
+
+// lastCategoryRuns returns, per category, the most recent result timestamp in the given results
+func lastCategoryRuns(systemResults, kubernetesResults map[string]nodecheckv1alpha1.CheckResult) map[string]time.Time {
+	lastRuns := make(map[string]time.Time)
+	record := func(category string, result nodecheckv1alpha1.CheckResult) {
+		if result.Timestamp.Time.After(lastRuns[category]) {
+			lastRuns[category] = result.Timestamp.Time
+		}
+	}
+	for key, result := range systemResults {
+		record(checkCategory(key, false), result)
+	}
+	for key, result := range kubernetesResults {
+		record(checkCategory(key, true), result)
+	}
+	return lastRuns
+}
+
+// dueCategories returns the enabled categories among the given ones that must run now, and how long
+// until the next of them becomes due (0 if none is enabled)
+func dueCategories(spec *nodecheckv1alpha1.NodeCheckSpec, categories []string, lastRuns map[string]time.Time, defaultInterval time.Duration, now time.Time) (map[string]bool, time.Duration) {
+	due := make(map[string]bool)
+	var nextRun time.Duration
+
+	for _, category := range categories {
+		if !categoryEnabled(spec, category) {
+			continue
+		}
+		interval := categoryInterval(spec, category, defaultInterval)
+		remaining := interval
+		if lastRun, ok := lastRuns[category]; ok {
+			remaining = interval - now.Sub(lastRun)
+		}
+		if _, ok := lastRuns[category]; !ok || remaining <= categoryScheduleSlack {
+			due[category] = true
+			// The category runs now, so its next run is a full interval away
+			remaining = interval
+		}
+		if nextRun == 0 || remaining < nextRun {
+			nextRun = remaining
+		}
+	}
+	return due, nextRun
+}
+
+// flattenCheckResults converts stored check results back into the executor's keyed maps
+func flattenCheckResults(results nodecheckv1alpha1.CheckResults) (map[string]nodecheckv1alpha1.CheckResult, map[string]nodecheckv1alpha1.CheckResult) {
+	systemResults := make(map[string]nodecheckv1alpha1.CheckResult)
+	kubernetesResults := make(map[string]nodecheckv1alpha1.CheckResult)
+	add := func(m map[string]nodecheckv1alpha1.CheckResult, key string, result *nodecheckv1alpha1.CheckResult) {
+		if result != nil {
+			m[key] = *result
+		}
+	}
+
+	sr := results.SystemResults
+	add(systemResults, "uptime", sr.Uptime)
+	add(systemResults, "processes", sr.Processes)
+	add(systemResults, "resources", sr.Resources)
+	add(systemResults, "memory", sr.Memory)
+	add(systemResults, "uninterruptible_tasks", sr.UninterruptibleTasks)
+	add(systemResults, "services", sr.Services)
+	add(systemResults, "system_logs", sr.SystemLogs)
+	add(systemResults, "file_descriptors", sr.FileDescriptors)
+	add(systemResults, "zombie_processes", sr.ZombieProcesses)
+	add(systemResults, "ntp_sync", sr.NTPSync)
+	add(systemResults, "kernel_panics", sr.KernelPanics)
+	add(systemResults, "oom_killer", sr.OOMKiller)
+	add(systemResults, "cpu_frequency", sr.CPUFrequency)
+	add(systemResults, "interrupts_balance", sr.InterruptsBalance)
+	add(systemResults, "cpu_steal_time", sr.CPUStealTime)
+	add(systemResults, "memory_fragmentation", sr.MemoryFragmentation)
+	add(systemResults, "swap_activity", sr.SwapActivity)
+	add(systemResults, "context_switches", sr.ContextSwitches)
+	add(systemResults, "selinux_status", sr.SELinuxStatus)
+	add(systemResults, "ssh_access", sr.SSHAccess)
+	add(systemResults, "kernel_modules", sr.KernelModules)
+	add(systemResults, "kernel_taint", sr.KernelTaint)
+	add(systemResults, "pressure_stall", sr.PressureStall)
+	add(systemResults, "entropy", sr.Entropy)
+	add(systemResults, "transparent_hugepages", sr.TransparentHugePages)
+	add(systemResults, "huge_pages", sr.HugePages)
+	add(systemResults, "numa", sr.NUMA)
+	add(systemResults, "conntrack", sr.Conntrack)
+	add(systemResults, "known_issues", sr.KnownIssues)
+	add(systemResults, "kdump", sr.Kdump)
+	add(systemResults, "sysctl_drift", sr.SysctlDrift)
+	add(systemResults, "inotify", sr.Inotify)
+	add(systemResults, "service_restarts", sr.ServiceRestarts)
+	add(systemResults, "core_dumps", sr.CoreDumps)
+	add(systemResults, "time_drift", sr.TimeDrift)
+	add(systemResults, "audit", sr.Audit)
+	add(systemResults, "cpu_vulnerabilities", sr.CPUVulnerabilities)
+	add(systemResults, "kernel_cmdline", sr.KernelCmdline)
+	add(systemResults, "os_updates", sr.OSUpdates)
+	add(systemResults, "cgroup_driver", sr.CgroupDriver)
+	add(systemResults, "process_limits", sr.ProcessLimits)
+	add(systemResults, "orphaned_mounts", sr.OrphanedMounts)
+	add(systemResults, "userspace_oom", sr.UserspaceOOM)
+	add(systemResults, "gpu", sr.GPU)
+	add(systemResults, "sriov", sr.SRIOV)
+	add(systemResults, "kernel_lockups", sr.KernelLockups)
+	add(systemResults, "swap_policy", sr.SwapPolicy)
+	add(systemResults, "certificate_expiry", sr.CertificateExpiry)
+	add(systemResults, "baseline_drift", sr.BaselineDrift)
+
+	if hw := sr.Hardware; hw != nil {
+		add(systemResults, "hardware_temperature", hw.Temperature)
+		add(systemResults, "hardware_ipmi", hw.IPMI)
+		add(systemResults, "hardware_bmc", hw.BMC)
+		add(systemResults, "hardware_fan_status", hw.FanStatus)
+		add(systemResults, "hardware_power_supply", hw.PowerSupply)
+		add(systemResults, "hardware_memory_errors", hw.MemoryErrors)
+		add(systemResults, "hardware_pcie_errors", hw.PCIeErrors)
+		add(systemResults, "hardware_cpu_microcode", hw.CPUMicrocode)
+	}
+
+	if disks := sr.Disks; disks != nil {
+		add(systemResults, "disk_space", disks.Space)
+		add(systemResults, "disk_smart", disks.SMART)
+		add(systemResults, "disk_performance", disks.Performance)
+		add(systemResults, "disk_raid", disks.RAID)
+		add(systemResults, "disk_pvs", disks.PVs)
+		add(systemResults, "disk_lvm", disks.LVM)
+		add(systemResults, "disk_io_wait", disks.IOWait)
+		add(systemResults, "disk_queue_depth", disks.QueueDepth)
+		add(systemResults, "disk_filesystem_errors", disks.FilesystemErrors)
+		add(systemResults, "disk_inode_usage", disks.InodeUsage)
+		add(systemResults, "disk_mount_points", disks.MountPoints)
+		add(systemResults, "disk_write_probe", disks.WriteProbe)
+		add(systemResults, "disk_runtime_storage", disks.RuntimeStorage)
+		add(systemResults, "disk_wear_level", disks.WearLevel)
+	}
+
+	if network := sr.Network; network != nil {
+		add(systemResults, "network_interfaces", network.Interfaces)
+		add(systemResults, "network_routing", network.Routing)
+		add(systemResults, "network_connectivity", network.Connectivity)
+		add(systemResults, "network_statistics", network.Statistics)
+		add(systemResults, "network_errors", network.Errors)
+		add(systemResults, "network_latency", network.Latency)
+		add(systemResults, "network_dns_resolution", network.DNSResolution)
+		add(systemResults, "network_bonding_status", network.BondingStatus)
+		add(systemResults, "network_firewall_rules", network.FirewallRules)
+		add(systemResults, "network_egress", network.Egress)
+		add(systemResults, "network_ingress", network.Ingress)
+		add(systemResults, "network_resolv_conf", network.ResolvConf)
+	}
+
+	kr := results.KubernetesResults
+	add(kubernetesResults, "node_status", kr.NodeStatus)
+	add(kubernetesResults, "pods", kr.Pods)
+	add(kubernetesResults, "cluster_operators", kr.ClusterOperators)
+	add(kubernetesResults, "node_resources", kr.NodeResources)
+	add(kubernetesResults, "node_resource_usage", kr.NodeResourceUsage)
+	add(kubernetesResults, "container_runtime", kr.ContainerRuntime)
+	add(kubernetesResults, "kubelet_health", kr.KubeletHealth)
+	add(kubernetesResults, "cni_plugin", kr.CNIPlugin)
+	add(kubernetesResults, "node_conditions", kr.NodeConditions)
+	add(kubernetesResults, "pod_scheduling", kr.PodScheduling)
+	add(kubernetesResults, "pvc_provisioning", kr.PVCProvisioning)
+	add(kubernetesResults, "pod_network", kr.PodNetwork)
+	add(kubernetesResults, "lb_health_check", kr.LBHealthCheck)
+
+	return systemResults, kubernetesResults
+}
+
+// setBaseline records the baseline captured in this run in the status. The baseline is dropped
+// when spec.baseline is disabled, so enabling it again captures a fresh one.
+func setBaseline(status *nodecheckv1alpha1.NodeCheckStatus, spec *nodecheckv1alpha1.NodeCheckSpec, captured *nodecheckv1alpha1.NodeBaseline) {
+	switch {
+	case captured != nil:
+		status.Baseline = captured
+	case spec.Baseline == nil || !spec.Baseline.Enabled:
+		status.Baseline = nil
+	}
+}
@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// categoryRequestSeparator separates the NodeCheck name from the check category in the name of a work item.
+// NodeCheck names cannot contain it, so the work items of different NodeChecks never collide.
+const categoryRequestSeparator = "/"
+
+// categoryRequest returns the work item that runs one check category of a NodeCheck
+func categoryRequest(key types.NamespacedName, category string) reconcile.Request {
+	return reconcile.Request{NamespacedName: types.NamespacedName{
+		Namespace: key.Namespace,
+		Name:      key.Name + categoryRequestSeparator + category,
+	}}
+}
+
+// splitCategoryRequest returns the NodeCheck and the check category of a work item. The category is empty
+// for a request naming the NodeCheck only, which runs all the due categories at once.
+func splitCategoryRequest(req reconcile.Request) (types.NamespacedName, string) {
+	name, category, ok := strings.Cut(req.Name, categoryRequestSeparator)
+	if !ok {
+		return req.NamespacedName, ""
+	}
+	for _, known := range checkCategories {
+		if category == known {
+			return types.NamespacedName{Namespace: req.Namespace, Name: name}, category
+		}
+	}
+	return req.NamespacedName, ""
+}
+
+// categoryRequests maps a NodeCheck event to one work item per enabled check category. The work items are
+// queued and reconciled independently, so a slow category (e.g. hardware probes) does not delay the others
+// and each category is requeued at its own interval. A NodeCheck without enabled categories gets a single
+// work item, which keeps its (empty) status refreshed.
+func categoryRequests(_ context.Context, obj client.Object) []reconcile.Request {
+	nodeCheck, ok := obj.(*nodecheckv1alpha1.NodeCheck)
+	if !ok {
+		return nil
+	}
+	key := types.NamespacedName{Namespace: nodeCheck.Namespace, Name: nodeCheck.Name}
+	var requests []reconcile.Request
+	for _, category := range checkCategories {
+		if categoryEnabled(&nodeCheck.Spec, category) {
+			requests = append(requests, categoryRequest(key, category))
+		}
+	}
+	if len(requests) == 0 {
+		requests = append(requests, reconcile.Request{NamespacedName: key})
+	}
+	return requests
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/metrics"
+)
+
+// CleanupFinalizer is added to NodeChecks with nodeName "*" so that deleting them also deletes
+// their child NodeChecks and the Prometheus series of their nodes
+const CleanupFinalizer = "nodecheck.openshift.io/cleanup"
+
+// childNodeName returns the node of a child NodeCheck (named <parent>-<node>), or "" if it is not a child of parent
+func childNodeName(parentName, childName string) string {
+	if !strings.HasPrefix(childName, parentName+"-") {
+		return ""
+	}
+	return strings.TrimPrefix(childName, parentName+"-")
+}
+
+// listChildNodeChecks returns the child NodeChecks of a "*" NodeCheck. The node in the name must match
+// spec.nodeName, so another "*" NodeCheck whose name starts with the parent's name is never picked up.
+func (r *NodeCheckReconciler) listChildNodeChecks(ctx context.Context, parent *nodecheckv1alpha1.NodeCheck) ([]nodecheckv1alpha1.NodeCheck, error) {
+	var nodeChecks nodecheckv1alpha1.NodeCheckList
+	if err := r.Client.List(ctx, &nodeChecks, client.InNamespace(parent.Namespace)); err != nil {
+		return nil, err
+	}
+	children := []nodecheckv1alpha1.NodeCheck{}
+	for _, nodeCheck := range nodeChecks.Items {
+		if nodeName := childNodeName(parent.Name, nodeCheck.Name); nodeName != "" && nodeName == nodeCheck.Spec.NodeName {
+			children = append(children, nodeCheck)
+		}
+	}
+	return children, nil
+}
+
+// deleteChildNodeCheck deletes a child NodeCheck and the metric series of its node
+func (r *NodeCheckReconciler) deleteChildNodeCheck(ctx context.Context, child *nodecheckv1alpha1.NodeCheck, nodeName string) error {
+	if err := r.Delete(ctx, child); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	metrics.DeleteNodeMetrics(nodeName)
+	return nil
+}
+
+// finalizeNodeCheck deletes the children of a NodeCheck being deleted (or no longer in "*" mode),
+// clears the metric series of their nodes and removes the cleanup finalizer
+func (r *NodeCheckReconciler) finalizeNodeCheck(ctx context.Context, nodeCheck *nodecheckv1alpha1.NodeCheck) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("NodeCheckReconciler")
+
+	children, err := r.listChildNodeChecks(ctx, nodeCheck)
+	if err != nil {
+		log.Error(err, "unable to list child NodeChecks for cleanup", "nodeCheck", nodeCheck.Name)
+		return ctrl.Result{}, err
+	}
+	for i := range children {
+		nodeName := childNodeName(nodeCheck.Name, children[i].Name)
+		log.Info("Deleting child NodeCheck", "childNodeCheckName", children[i].Name, "nodeName", nodeName)
+		if err := r.deleteChildNodeCheck(ctx, &children[i], nodeName); err != nil {
+			log.Error(err, "unable to delete child NodeCheck", "childNodeCheckName", children[i].Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	if controllerutil.RemoveFinalizer(nodeCheck, CleanupFinalizer) {
+		if err := r.Update(ctx, nodeCheck); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+	}
+	log.Info("Cleaned up child NodeChecks", "nodeCheck", nodeCheck.Name, "children", len(children))
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// OverridesAnnotation holds the overrides of a child NodeCheck of a "*" NodeCheck, as a JSON NodeCheckSpec
+// (e.g. {"checkInterval":10,"thresholds":{"disk_space":{"warning":90}}}). The child spec is the parent spec
+// with the overrides merged in like the pool overrides of a NodeCheckTemplate: the fields set replace the
+// parent's ones, maps are merged key by key and checks enabled here are added to the checks enabled by
+// the parent. Any other change to the spec of a child is reverted by the next sync.
+const OverridesAnnotation = "nodecheck.openshift.io/overrides"
+
+// childNodeCheckSpec returns the spec of a child NodeCheck: the parent spec, the child's overrides and the
+// node of the child. The spec fields the child cannot override (nodeName, nodeSelector) are always reset.
+func childNodeCheckSpec(parent, child *nodecheckv1alpha1.NodeCheck, nodeName string) (nodecheckv1alpha1.NodeCheckSpec, error) {
+	var overrides nodecheckv1alpha1.NodeCheckSpec
+	if value := child.Annotations[OverridesAnnotation]; value != "" {
+		if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+			return nodecheckv1alpha1.NodeCheckSpec{}, fmt.Errorf("invalid %s annotation: %w", OverridesAnnotation, err)
+		}
+	}
+
+	spec, err := mergeNodeCheckSpec(parent.Spec, overrides)
+	if err != nil {
+		return spec, err
+	}
+	spec.NodeName = nodeName
+	spec.NodeSelector = nil
+	return spec, nil
+}
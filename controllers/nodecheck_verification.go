@@ -0,0 +1,216 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/verification"
+)
+
+// verificationPollInterval is how often a running verification is looked at, besides the status
+// updates of its NodeCheck
+const verificationPollInterval = 30 * time.Second
+
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch;patch
+
+// startVerification starts the verification requested by the annotation of a NodeCheck: it creates the
+// one-shot NodeCheck running the verification profile on the node, replacing a verification still
+// running, marks status.verification Running and removes the annotation.
+func (r *NodeCheckReconciler) startVerification(ctx context.Context, nodeCheck *nodecheckv1alpha1.NodeCheck) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("NodeCheckReconciler").WithValues("nodeCheck", nodeCheck.Name)
+
+	request, err := verification.ParseRequest(nodeCheck.Annotations)
+	if err != nil {
+		log.Error(err, "ignoring the invalid verification request")
+		return ctrl.Result{}, r.removeVerificationRequest(ctx, nodeCheck)
+	}
+	if nodeCheck.Spec.NodeName == "" {
+		// The executor has not detected the node yet
+		return ctrl.Result{RequeueAfter: verificationPollInterval}, nil
+	}
+
+	name := verification.Name(nodeCheck.Name)
+	var previous nodecheckv1alpha1.NodeCheck
+	if err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: nodeCheck.Namespace}, &previous); err == nil {
+		if err := r.Delete(ctx, &previous); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		log.Info("Replacing the running verification", "verificationNodeCheck", name)
+		return ctrl.Result{RequeueAfter: time.Second}, nil
+	} else if !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+
+	oneShot := &nodecheckv1alpha1.NodeCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: nodeCheck.Namespace,
+			Labels:    map[string]string{verification.Label: nodeCheck.Name},
+		},
+		Spec: verification.Spec(&nodeCheck.Spec),
+	}
+	if err := controllerutil.SetControllerReference(nodeCheck, oneShot, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, oneShot); err != nil {
+		if errors.IsAlreadyExists(err) {
+			// The previous verification NodeCheck is still being deleted
+			return ctrl.Result{RequeueAfter: time.Second}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	nodeCheck.Status.Verification = &nodecheckv1alpha1.VerificationStatus{
+		Phase:        verification.PhaseRunning,
+		RequestedBy:  request.RequestedBy,
+		StartTime:    oneShot.CreationTimestamp,
+		NodeCheck:    name,
+		AutoUncordon: request.AutoUncordon,
+		Message:      fmt.Sprintf("Running all the checks on node %s with the verification thresholds", nodeCheck.Spec.NodeName),
+	}
+	if nodeCheck.Status.Verification.StartTime.IsZero() {
+		nodeCheck.Status.Verification.StartTime = metav1.Now()
+	}
+	if err := r.Status().Update(ctx, nodeCheck); err != nil {
+		return ctrl.Result{}, err
+	}
+	log.Info("Started the post-maintenance verification", "node", nodeCheck.Spec.NodeName, "verificationNodeCheck", name,
+		"requestedBy", request.RequestedBy, "autoUncordon", request.AutoUncordon)
+	return ctrl.Result{}, r.removeVerificationRequest(ctx, nodeCheck)
+}
+
+// removeVerificationRequest removes the verification request annotation of a NodeCheck
+func (r *NodeCheckReconciler) removeVerificationRequest(ctx context.Context, nodeCheck *nodecheckv1alpha1.NodeCheck) error {
+	patch := client.MergeFrom(nodeCheck.DeepCopy())
+	delete(nodeCheck.Annotations, verification.Annotation)
+	return r.Patch(ctx, nodeCheck, patch)
+}
+
+// evaluateVerification evaluates the gate of a verification once the first run of its one-shot NodeCheck
+// has completed (or timed out), records it in the status of the verified NodeCheck, uncordons the node if
+// requested and deletes the one-shot NodeCheck
+func (r *NodeCheckReconciler) evaluateVerification(ctx context.Context, oneShot *nodecheckv1alpha1.NodeCheck, verifiedName string) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("NodeCheckReconciler").WithValues("nodeCheck", verifiedName)
+
+	var verified nodecheckv1alpha1.NodeCheck
+	if err := r.Get(ctx, client.ObjectKey{Name: verifiedName, Namespace: oneShot.Namespace}, &verified); err != nil {
+		// A deleted NodeCheck takes its verification with it (owner reference)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	state := verified.Status.Verification
+	if state == nil || state.Phase != verification.PhaseRunning || state.NodeCheck != oneShot.Name {
+		// Left over by a verification that was already evaluated
+		return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, oneShot))
+	}
+
+	complete := verificationComplete(oneShot)
+	if !complete && time.Since(state.StartTime.Time) < verification.Timeout {
+		return ctrl.Result{RequeueAfter: verificationPollInterval}, nil
+	}
+
+	state = state.DeepCopy()
+	checkCount, failed := verification.Evaluate(oneShot.Status.CheckResults)
+	now := metav1.Now()
+	state.CompletionTime = &now
+	state.FailedChecks = failed
+	switch {
+	case !complete:
+		state.Phase = verification.PhaseFailed
+		state.Message = fmt.Sprintf("The verification did not complete within %s (%d checks reported)", verification.Timeout, checkCount)
+	case len(failed) > 0:
+		state.Phase = verification.PhaseFailed
+		state.Message = fmt.Sprintf("%d of %d checks failed the verification: %s", len(failed), checkCount, strings.Join(failed, ", "))
+	default:
+		state.Phase = verification.PhasePassed
+		state.Message = fmt.Sprintf("All %d checks passed the verification", checkCount)
+	}
+	if state.Phase == verification.PhasePassed && state.AutoUncordon {
+		uncordoned, err := r.uncordonNode(ctx, verified.Spec.NodeName)
+		switch {
+		case err != nil:
+			log.Error(err, "unable to uncordon the verified node", "node", verified.Spec.NodeName)
+			state.Message += fmt.Sprintf("; unable to uncordon the node: %v", err)
+		case uncordoned:
+			state.Uncordoned = true
+			state.Message += "; node uncordoned"
+		default:
+			state.Message += "; the node was not cordoned"
+		}
+	}
+
+	verified.Status.Verification = state
+	recordVerificationEvent(&verified.Status, &verified.Spec, state)
+	if err := r.Status().Update(ctx, &verified); err != nil {
+		if errors.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	log.Info("Post-maintenance verification completed", "node", verified.Spec.NodeName, "phase", state.Phase,
+		"failedChecks", len(failed), "uncordoned", state.Uncordoned)
+	return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, oneShot))
+}
+
+// verificationComplete reports whether every enabled check category of the one-shot NodeCheck has
+// stored its results and no slow check is still running
+func verificationComplete(oneShot *nodecheckv1alpha1.NodeCheck) bool {
+	if meta.IsStatusConditionTrue(oneShot.Status.Conditions, ConditionRunInProgress) {
+		return false
+	}
+	systemResults, kubernetesResults := flattenCheckResults(oneShot.Status.CheckResults)
+	lastRuns := lastCategoryRuns(systemResults, kubernetesResults)
+	for _, category := range checkCategories {
+		if categoryEnabled(&oneShot.Spec, category) && lastRuns[category].IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+// uncordonNode marks a node schedulable again. It returns false when the node was not cordoned.
+func (r *NodeCheckReconciler) uncordonNode(ctx context.Context, nodeName string) (bool, error) {
+	var node corev1.Node
+	if err := r.Get(ctx, client.ObjectKey{Name: nodeName}, &node); err != nil {
+		return false, err
+	}
+	if !node.Spec.Unschedulable {
+		return false, nil
+	}
+	patch := client.MergeFrom(node.DeepCopy())
+	node.Spec.Unschedulable = false
+	if err := r.Patch(ctx, &node, patch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// recordVerificationEvent adds the outcome of a verification to the lifecycle events, the markers of
+// the history. Like the other events, it is kept while spec.historySize is set.
+func recordVerificationEvent(status *nodecheckv1alpha1.NodeCheckStatus, spec *nodecheckv1alpha1.NodeCheckSpec, state *nodecheckv1alpha1.VerificationStatus) {
+	if spec.HistorySize <= 0 || status.Lifecycle == nil {
+		return
+	}
+	eventType := lifecycleVerificationPassed
+	if state.Phase != verification.PhasePassed {
+		eventType = lifecycleVerificationFailed
+	}
+	status.Lifecycle.Events = append(status.Lifecycle.Events, nodecheckv1alpha1.LifecycleEvent{
+		Type:      eventType,
+		Timestamp: *state.CompletionTime,
+		Message:   state.Message,
+	})
+	if len(status.Lifecycle.Events) > spec.HistorySize {
+		status.Lifecycle.Events = status.Lifecycle.Events[len(status.Lifecycle.Events)-spec.HistorySize:]
+	}
+}
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+)
+
+const (
+	// TemplateLabel is set on the NodeChecks generated by a NodeCheckTemplate to the name of the template
+	TemplateLabel = "nodecheck.openshift.io/template"
+	// PoolLabel is set on the NodeChecks generated by a NodeCheckTemplate to the name of the pool
+	PoolLabel = "nodecheck.openshift.io/pool"
+	// ConditionTemplateReady is False while some pools of a NodeCheckTemplate cannot be reconciled
+	ConditionTemplateReady = "Ready"
+)
+
+// nodeRoleLabelPrefix is the prefix of the node role labels (node-role.kubernetes.io/master, ...)
+const nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+
+// machineConfigPoolGVK is the OpenShift MachineConfigPool kind, read as unstructured so the operator
+// keeps working on clusters without the machine-config operator
+var machineConfigPoolGVK = schema.GroupVersionKind{
+	Group:   "machineconfiguration.openshift.io",
+	Version: "v1",
+	Kind:    "MachineConfigPool",
+}
+
+// NodeCheckTemplateReconciler reconciles a NodeCheckTemplate object: it keeps one NodeCheck with
+// nodeName "*" per pool, which the NodeCheckReconciler then expands to one child per node
+type NodeCheckTemplateReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+//+kubebuilder:rbac:groups=nodecheck.openshift.io,resources=nodechecktemplates,verbs=get;list;watch
+//+kubebuilder:rbac:groups=nodecheck.openshift.io,resources=nodechecktemplates/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=nodecheck.openshift.io,resources=nodechecktemplates/finalizers,verbs=update
+//+kubebuilder:rbac:groups=machineconfiguration.openshift.io,resources=machineconfigpools,verbs=get;list;watch
+
+// Reconcile creates, updates and deletes the NodeChecks of the pools of a NodeCheckTemplate.
+// The NodeChecks are owned by the template, so deleting the template deletes them (and their children).
+func (r *NodeCheckTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("NodeCheckTemplateReconciler")
+
+	var template nodecheckv1alpha1.NodeCheckTemplate
+	if err := r.Get(ctx, req.NamespacedName, &template); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !template.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		log.Error(err, "unable to list nodes")
+		return ctrl.Result{}, err
+	}
+
+	problems := []string{}
+	desired := make(map[string]bool)
+	poolStatuses := []nodecheckv1alpha1.NodePoolStatus{}
+	for _, pool := range template.Spec.Pools {
+		nodeCheckName := poolNodeCheckName(template.Name, pool.Name)
+		desired[nodeCheckName] = true
+
+		spec, err := r.poolNodeCheckSpec(ctx, &template, pool)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("pool %s: %v", pool.Name, err))
+			continue
+		}
+		if err := r.applyPoolNodeCheck(ctx, &template, pool.Name, spec); err != nil {
+			log.Error(err, "unable to apply pool NodeCheck", "template", template.Name, "pool", pool.Name)
+			problems = append(problems, fmt.Sprintf("pool %s: %v", pool.Name, err))
+			continue
+		}
+
+		matching := 0
+		for i := range nodes.Items {
+			if nodeMatchesSelector(&nodes.Items[i], spec.NodeSelector) {
+				matching++
+			}
+		}
+		poolStatuses = append(poolStatuses, nodecheckv1alpha1.NodePoolStatus{
+			Name:         pool.Name,
+			NodeCheck:    nodeCheckName,
+			NodeSelector: spec.NodeSelector,
+			Nodes:        matching,
+		})
+	}
+
+	// Delete the NodeChecks of the pools removed from the template. Their children carry the same
+	// labels, so only the NodeChecks named after a pool are considered.
+	var generated nodecheckv1alpha1.NodeCheckList
+	if err := r.List(ctx, &generated, client.InNamespace(template.Namespace), client.MatchingLabels{TemplateLabel: template.Name}); err != nil {
+		log.Error(err, "unable to list generated NodeChecks", "template", template.Name)
+		return ctrl.Result{}, err
+	}
+	for i := range generated.Items {
+		nodeCheck := &generated.Items[i]
+		if desired[nodeCheck.Name] || nodeCheck.Name != poolNodeCheckName(template.Name, nodeCheck.Labels[PoolLabel]) || !metav1.IsControlledBy(nodeCheck, &template) {
+			continue
+		}
+		log.Info("Deleting NodeCheck of removed pool", "template", template.Name, "nodeCheck", nodeCheck.Name)
+		if err := r.Delete(ctx, nodeCheck); err != nil && !errors.IsNotFound(err) {
+			problems = append(problems, fmt.Sprintf("unable to delete NodeCheck %s: %v", nodeCheck.Name, err))
+		}
+	}
+
+	var status nodecheckv1alpha1.NodeCheckTemplateStatus
+	template.Status.DeepCopyInto(&status)
+	status.Pools = poolStatuses
+	condition := metav1.Condition{
+		Type:               ConditionTemplateReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "PoolsReconciled",
+		Message:            fmt.Sprintf("%d pool NodeCheck(s) reconciled", len(poolStatuses)),
+		ObservedGeneration: template.Generation,
+	}
+	if len(problems) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "PoolsFailed"
+		condition.Message = strings.Join(problems, "; ")
+	}
+	meta.SetStatusCondition(&status.Conditions, condition)
+	if !equality.Semantic.DeepEqual(status, template.Status) {
+		template.Status = status
+		if err := r.Status().Update(ctx, &template); err != nil {
+			log.Error(err, "unable to update NodeCheckTemplate status", "template", template.Name)
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Pools are re-evaluated periodically, so MachineConfigPool and node label changes are picked up
+	return ctrl.Result{RequeueAfter: 5 * time.Minute}, nil
+}
+
+// poolNodeCheckName returns the name of the NodeCheck generated for a pool of a template
+func poolNodeCheckName(templateName, poolName string) string {
+	return fmt.Sprintf("%s-%s", templateName, poolName)
+}
+
+// nodeMatchesSelector reports whether the node has all the labels of the selector
+func nodeMatchesSelector(node *corev1.Node, selector map[string]string) bool {
+	for key, value := range selector {
+		if nodeValue, exists := node.Labels[key]; !exists || nodeValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeNodeCheckSpec overlays the overrides of a pool on the template spec. All the spec fields are
+// omitempty, so encoding the overrides keeps only the fields they set, which are then decoded over
+// a copy of the template: nested structs and maps are merged, scalars and lists are replaced.
+func mergeNodeCheckSpec(template, overrides nodecheckv1alpha1.NodeCheckSpec) (nodecheckv1alpha1.NodeCheckSpec, error) {
+	merged := *template.DeepCopy()
+	data, err := json.Marshal(overrides)
+	if err != nil {
+		return merged, err
+	}
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return merged, err
+	}
+	return merged, nil
+}
+
+// poolNodeCheckSpec builds the spec of the NodeCheck of a pool: the template merged with the pool's
+// overrides, for all the nodes matching the pool's selectors
+func (r *NodeCheckTemplateReconciler) poolNodeCheckSpec(ctx context.Context, template *nodecheckv1alpha1.NodeCheckTemplate, pool nodecheckv1alpha1.NodePool) (nodecheckv1alpha1.NodeCheckSpec, error) {
+	spec, err := mergeNodeCheckSpec(template.Spec.Template, pool.Overrides)
+	if err != nil {
+		return spec, fmt.Errorf("unable to merge overrides: %w", err)
+	}
+
+	selector := make(map[string]string)
+	for key, value := range spec.NodeSelector {
+		selector[key] = value
+	}
+	if pool.MachineConfigPool != "" {
+		poolSelector, err := r.machineConfigPoolSelector(ctx, pool.MachineConfigPool)
+		if err != nil {
+			return spec, err
+		}
+		for key, value := range poolSelector {
+			selector[key] = value
+		}
+	}
+	if pool.NodeRole != "" {
+		selector[nodeRoleLabelPrefix+pool.NodeRole] = ""
+	}
+	for key, value := range pool.NodeSelector {
+		selector[key] = value
+	}
+
+	spec.NodeName = "*"
+	spec.NodeSelector = nil
+	if len(selector) > 0 {
+		spec.NodeSelector = selector
+	}
+	return spec, nil
+}
+
+// machineConfigPoolSelector returns the node labels selected by a MachineConfigPool
+func (r *NodeCheckTemplateReconciler) machineConfigPoolSelector(ctx context.Context, name string) (map[string]string, error) {
+	machineConfigPool := &unstructured.Unstructured{}
+	machineConfigPool.SetGroupVersionKind(machineConfigPoolGVK)
+	if err := r.Get(ctx, types.NamespacedName{Name: name}, machineConfigPool); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, fmt.Errorf("MachineConfigPools are not available on this cluster")
+		}
+		return nil, fmt.Errorf("unable to get MachineConfigPool %s: %w", name, err)
+	}
+	if expressions, found, _ := unstructured.NestedSlice(machineConfigPool.Object, "spec", "nodeSelector", "matchExpressions"); found && len(expressions) > 0 {
+		return nil, fmt.Errorf("MachineConfigPool %s uses matchExpressions, which are not supported", name)
+	}
+	matchLabels, _, err := unstructured.NestedStringMap(machineConfigPool.Object, "spec", "nodeSelector", "matchLabels")
+	if err != nil {
+		return nil, fmt.Errorf("invalid nodeSelector in MachineConfigPool %s: %w", name, err)
+	}
+	return matchLabels, nil
+}
+
+// applyPoolNodeCheck creates or updates the NodeCheck of a pool. Only the labels, the owner and the spec
+// are managed, so the finalizer added by the NodeCheckReconciler is preserved.
+func (r *NodeCheckTemplateReconciler) applyPoolNodeCheck(ctx context.Context, template *nodecheckv1alpha1.NodeCheckTemplate, poolName string, spec nodecheckv1alpha1.NodeCheckSpec) error {
+	nodeCheck := &nodecheckv1alpha1.NodeCheck{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      poolNodeCheckName(template.Name, poolName),
+			Namespace: template.Namespace,
+		},
+	}
+	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, nodeCheck, func() error {
+		if nodeCheck.Labels == nil {
+			nodeCheck.Labels = make(map[string]string)
+		}
+		nodeCheck.Labels[TemplateLabel] = template.Name
+		nodeCheck.Labels[PoolLabel] = poolName
+		nodeCheck.Spec = spec
+		return controllerutil.SetControllerReference(template, nodeCheck, r.Scheme)
+	})
+	if err != nil {
+		return err
+	}
+	if result != controllerutil.OperationResultNone {
+		ctrl.Log.WithName("NodeCheckTemplateReconciler").Info("Applied pool NodeCheck", "template", template.Name, "nodeCheck", nodeCheck.Name, "operation", result)
+	}
+	return nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NodeCheckTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&nodecheckv1alpha1.NodeCheckTemplate{}).
+		Owns(&nodecheckv1alpha1.NodeCheck{}).
+		Complete(selfstatus.Track("NodeCheckTemplate", r))
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// getPoolNodeCheck returns the NodeCheck generated for a pool of a template
+func getPoolNodeCheck(templateName, poolName string) (*nodecheckv1alpha1.NodeCheck, error) {
+	var nodeCheck nodecheckv1alpha1.NodeCheck
+	key := client.ObjectKey{Namespace: envtestNamespace, Name: poolNodeCheckName(templateName, poolName)}
+	if err := k8sClient.Get(context.Background(), key, &nodeCheck); err != nil {
+		return nil, err
+	}
+	return &nodeCheck, nil
+}
+
+func TestTemplateGeneratesAPoolNodeCheckPerPool(t *testing.T) {
+	createExecutorNode(t)
+	template := &nodecheckv1alpha1.NodeCheckTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet", Namespace: envtestNamespace},
+		Spec: nodecheckv1alpha1.NodeCheckTemplateSpec{
+			Template: nodecheckv1alpha1.NodeCheckSpec{
+				CheckInterval: 10,
+				SystemChecks:  nodecheckv1alpha1.SystemChecks{Uptime: true},
+			},
+			Pools: []nodecheckv1alpha1.NodePool{
+				{
+					Name:      "masters",
+					NodeRole:  "master",
+					Overrides: nodecheckv1alpha1.NodeCheckSpec{CheckInterval: 5},
+				},
+				{
+					Name:     "workers",
+					NodeRole: "worker",
+					Overrides: nodecheckv1alpha1.NodeCheckSpec{
+						SystemChecks: nodecheckv1alpha1.SystemChecks{Memory: true},
+					},
+				},
+			},
+		},
+	}
+	if err := k8sClient.Create(context.Background(), template); err != nil {
+		t.Fatalf("unable to create NodeCheckTemplate: %v", err)
+	}
+	t.Cleanup(func() {
+		k8sClient.Delete(context.Background(), template)
+	})
+
+	eventually(t, func() error {
+		masters, err := getPoolNodeCheck("fleet", "masters")
+		if err != nil {
+			return err
+		}
+		if masters.Spec.NodeName != "*" || masters.Spec.CheckInterval != 5 || !masters.Spec.SystemChecks.Uptime {
+			return fmt.Errorf("unexpected spec of the masters NodeCheck: %+v", masters.Spec)
+		}
+		if _, ok := masters.Spec.NodeSelector[nodeRoleLabelPrefix+"master"]; !ok {
+			return fmt.Errorf("masters NodeCheck selects %v", masters.Spec.NodeSelector)
+		}
+		workers, err := getPoolNodeCheck("fleet", "workers")
+		if err != nil {
+			return err
+		}
+		if workers.Spec.CheckInterval != 10 || !workers.Spec.SystemChecks.Uptime || !workers.Spec.SystemChecks.Memory {
+			return fmt.Errorf("unexpected spec of the workers NodeCheck: %+v", workers.Spec)
+		}
+		if workers.Labels[TemplateLabel] != "fleet" || workers.Labels[PoolLabel] != "workers" {
+			return fmt.Errorf("workers NodeCheck labelled %v", workers.Labels)
+		}
+		return nil
+	})
+
+	eventually(t, func() error {
+		var current nodecheckv1alpha1.NodeCheckTemplate
+		if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(template), &current); err != nil {
+			return err
+		}
+		if !meta.IsStatusConditionTrue(current.Status.Conditions, ConditionTemplateReady) {
+			return fmt.Errorf("template not Ready: %v", current.Status.Conditions)
+		}
+		for _, pool := range current.Status.Pools {
+			if pool.Name == "workers" && pool.Nodes != 1 {
+				return fmt.Errorf("workers pool selects %d nodes, want 1", pool.Nodes)
+			}
+		}
+		return nil
+	})
+
+	// Removing a pool deletes its NodeCheck
+	var current nodecheckv1alpha1.NodeCheckTemplate
+	if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(template), &current); err != nil {
+		t.Fatal(err)
+	}
+	current.Spec.Pools = current.Spec.Pools[1:]
+	if err := k8sClient.Update(context.Background(), &current); err != nil {
+		t.Fatalf("unable to remove the masters pool: %v", err)
+	}
+	eventually(t, func() error {
+		if _, err := getPoolNodeCheck("fleet", "masters"); !errors.IsNotFound(err) {
+			return fmt.Errorf("masters NodeCheck still present (%v)", err)
+		}
+		return nil
+	})
+}
+
+func TestTemplateReportsUnavailableMachineConfigPools(t *testing.T) {
+	template := &nodecheckv1alpha1.NodeCheckTemplate{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-pools", Namespace: envtestNamespace},
+		Spec: nodecheckv1alpha1.NodeCheckTemplateSpec{
+			Pools: []nodecheckv1alpha1.NodePool{{Name: "infra", MachineConfigPool: "infra"}},
+		},
+	}
+	if err := k8sClient.Create(context.Background(), template); err != nil {
+		t.Fatalf("unable to create NodeCheckTemplate: %v", err)
+	}
+	t.Cleanup(func() {
+		k8sClient.Delete(context.Background(), template)
+	})
+
+	// envtest has no machine-config operator, so the pool cannot be resolved
+	eventually(t, func() error {
+		var current nodecheckv1alpha1.NodeCheckTemplate
+		if err := k8sClient.Get(context.Background(), client.ObjectKeyFromObject(template), &current); err != nil {
+			return err
+		}
+		condition := meta.FindStatusCondition(current.Status.Conditions, ConditionTemplateReady)
+		if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "PoolsFailed" {
+			return fmt.Errorf("unexpected Ready condition %v", condition)
+		}
+		return nil
+	})
+	if _, err := getPoolNodeCheck("openshift-pools", "infra"); !errors.IsNotFound(err) {
+		t.Fatalf("NodeCheck generated for an unresolved pool (%v)", err)
+	}
+}
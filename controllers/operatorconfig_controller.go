@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+)
+
+// ConditionOperatorConfigApplied is True once the operator runs with the current NodeCheckOperatorConfig spec
+const ConditionOperatorConfigApplied = "Applied"
+
+// OperatorConfigReconciler reconciles the NodeCheckOperatorConfig singleton. The ExecutorDaemonSet and
+// ConsolePlugin controllers read the configuration themselves on every reconcile; this controller
+// reports the settings in use in the status and calls OnChange when they change, so the components
+// started outside of the controllers (dashboard server, nodehealth API) follow the configuration too.
+type OperatorConfigReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Config *operatorconfig.Config
+	// OnChange is called with the previous and the new settings when they change
+	OnChange func(previous, current operatorconfig.Settings)
+}
+
+//+kubebuilder:rbac:groups=nodecheck.openshift.io,resources=nodecheckoperatorconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=nodecheck.openshift.io,resources=nodecheckoperatorconfigs/status,verbs=get;update;patch
+
+// Reconcile applies the NodeCheckOperatorConfig singleton. When it is deleted the operator goes back
+// to the settings of its environment variables.
+func (r *OperatorConfigReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("OperatorConfigReconciler")
+
+	if req.Name != nodecheckv1alpha1.NodeCheckOperatorConfigName {
+		log.Info("Ignoring NodeCheckOperatorConfig, only the one named cluster is used", "name", req.Name)
+		return ctrl.Result{}, nil
+	}
+
+	settings, err := r.Config.Load(ctx, r.Client)
+	if err != nil {
+		log.Error(err, "unable to read the NodeCheckOperatorConfig")
+		return ctrl.Result{}, err
+	}
+	if previous := r.Config.SetApplied(settings); previous != settings {
+		log.Info("Operator configuration changed", "namespace", settings.WatchNamespace,
+			"operatorImage", settings.OperatorImage, "consolePluginImage", settings.ConsolePluginImage,
+			"dashboardPort", settings.DashboardPort, "openShiftFeatures", settings.OpenShiftFeatures,
+			"nodeHealthAPI", settings.NodeHealthAPI, "faultInjection", settings.FaultInjection)
+		if r.OnChange != nil {
+			r.OnChange(previous, settings)
+		}
+	}
+
+	var config nodecheckv1alpha1.NodeCheckOperatorConfig
+	if err := r.Get(ctx, req.NamespacedName, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	status := &nodecheckv1alpha1.NodeCheckOperatorConfigStatus{}
+	config.Status.DeepCopyInto(status)
+	status.ObservedGeneration = config.Generation
+	status.WatchNamespace = settings.WatchNamespace
+	status.OperatorImage = settings.OperatorImage
+	status.ConsolePluginImage = settings.ConsolePluginImage
+	status.DashboardPort = settings.DashboardPort
+	status.OpenShiftFeatures = settings.OpenShiftFeatures
+	status.NodeHealthAPI = settings.NodeHealthAPI
+	status.FaultInjection = settings.FaultInjection
+	meta.SetStatusCondition(&status.Conditions, metav1.Condition{
+		Type:               ConditionOperatorConfigApplied,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Applied",
+		Message:            fmt.Sprintf("Operator running with generation %d", config.Generation),
+		ObservedGeneration: config.Generation,
+	})
+	if equality.Semantic.DeepEqual(status, &config.Status) {
+		return ctrl.Result{}, nil
+	}
+	config.Status = *status
+	if err := r.Status().Update(ctx, &config); err != nil {
+		log.Error(err, "unable to update NodeCheckOperatorConfig status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *OperatorConfigReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&nodecheckv1alpha1.NodeCheckOperatorConfig{}).
+		Complete(selfstatus.Track("OperatorConfig", r))
+}
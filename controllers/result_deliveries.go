@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// resultDeliveries tracks the check results delivered to the destinations of the operator (the result
+// webhooks, the Kafka export): the timestamp of the last result delivered, per destination, NodeCheck and
+// check. They are tracked in memory, so after a restart of the operator the latest results are
+// delivered again.
+type resultDeliveries struct {
+	mu        sync.Mutex
+	delivered map[string]time.Time
+}
+
+// deliveryKey returns the key of the deliveries of a check of a NodeCheck to a destination
+func deliveryKey(destination string, nodeCheck client.ObjectKey, check string) string {
+	return fmt.Sprintf("%s/%s/%s", destination, nodeCheck, check)
+}
+
+// pending returns the results not delivered to a destination yet
+func (d *resultDeliveries) pending(destination string, nodeCheck client.ObjectKey, results map[string]nodecheckv1alpha1.CheckResult) map[string]nodecheckv1alpha1.CheckResult {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	pending := make(map[string]nodecheckv1alpha1.CheckResult)
+	for name, result := range results {
+		if last, ok := d.delivered[deliveryKey(destination, nodeCheck, name)]; !ok || result.Timestamp.Time.After(last) {
+			pending[name] = result
+		}
+	}
+	return pending
+}
+
+// record marks results as delivered to a destination
+func (d *resultDeliveries) record(destination string, nodeCheck client.ObjectKey, results map[string]nodecheckv1alpha1.CheckResult) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.delivered == nil {
+		d.delivered = make(map[string]time.Time)
+	}
+	for name, result := range results {
+		d.delivered[deliveryKey(destination, nodeCheck, name)] = result.Timestamp.Time
+	}
+}
+
+// forget drops the deliveries of a deleted NodeCheck
+func (d *resultDeliveries) forget(nodeCheck client.ObjectKey) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	infix := "/" + nodeCheck.String() + "/"
+	for delivery := range d.delivered {
+		if strings.Contains(delivery, infix) {
+			delete(d.delivered, delivery)
+		}
+	}
+}
+
+// resultsByCategory groups the results of a NodeCheck by check category
+func resultsByCategory(checkResults nodecheckv1alpha1.CheckResults) map[string]map[string]nodecheckv1alpha1.CheckResult {
+	systemResults, kubernetesResults := flattenCheckResults(checkResults)
+	categories := make(map[string]map[string]nodecheckv1alpha1.CheckResult)
+	group := func(results map[string]nodecheckv1alpha1.CheckResult, kubernetes bool) {
+		for name, result := range results {
+			category := checkCategory(name, kubernetes)
+			if categories[category] == nil {
+				categories[category] = make(map[string]nodecheckv1alpha1.CheckResult)
+			}
+			categories[category][name] = result
+		}
+	}
+	group(systemResults, false)
+	group(kubernetesResults, true)
+	return categories
+}
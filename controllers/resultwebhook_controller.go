@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/resultwebhook"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+)
+
+// ResultWebhookReconciler posts the new results of the NodeChecks to the webhooks of spec.resultWebhooks of
+// the NodeCheckOperatorConfig: whenever a NodeCheck is updated, the results of each check category that ran
+// since the previous delivery are posted in one request per webhook. A failed delivery is retried with the
+// results that ran since. After a restart of the operator the latest results are posted again, with the
+// same payload ID.
+type ResultWebhookReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Clientset kubernetes.Interface
+	Config    *operatorconfig.Config
+
+	deliveries resultDeliveries
+}
+
+// Reconcile posts the new results of a NodeCheck
+func (r *ResultWebhookReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("ResultWebhookReconciler")
+
+	var config nodecheckv1alpha1.NodeCheckOperatorConfig
+	if err := r.Get(ctx, client.ObjectKey{Name: nodecheckv1alpha1.NodeCheckOperatorConfigName}, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if len(config.Spec.ResultWebhooks) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	var nodeCheck nodecheckv1alpha1.NodeCheck
+	if err := r.Get(ctx, req.NamespacedName, &nodeCheck); err != nil {
+		if errors.IsNotFound(err) {
+			r.deliveries.forget(req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// Parent NodeChecks ("*", "all") have no results of their own
+	nodeName := nodeCheck.Status.NodeName
+	if nodeName == "" || nodeName == "*" || nodeName == "all" {
+		return ctrl.Result{}, nil
+	}
+
+	categories := resultsByCategory(nodeCheck.Status.CheckResults)
+
+	var namespace string
+	var failed error
+	for i := range config.Spec.ResultWebhooks {
+		webhook := &config.Spec.ResultWebhooks[i]
+		payloads := r.pending(webhook, req.NamespacedName, &nodeCheck, categories)
+		if len(payloads) == 0 {
+			continue
+		}
+
+		var authorization string
+		if webhook.CredentialsSecret != "" {
+			if namespace == "" {
+				settings, err := r.Config.Load(ctx, r.Client)
+				if err != nil {
+					log.Error(err, "unable to read the NodeCheckOperatorConfig, using the namespace of the environment")
+				}
+				namespace = settings.WatchNamespace
+			}
+			secret, err := r.Clientset.CoreV1().Secrets(namespace).Get(ctx, webhook.CredentialsSecret, metav1.GetOptions{})
+			if err != nil {
+				log.Error(err, "unable to read the webhook credentials", "webhook", webhook.Name, "secret", webhook.CredentialsSecret)
+				failed = err
+				continue
+			}
+			authorization = strings.TrimSpace(string(secret.Data["authorization"]))
+		}
+
+		for _, payload := range payloads {
+			if err := resultwebhook.Post(ctx, webhook, authorization, payload); err != nil {
+				log.Error(err, "unable to post the check results", "webhook", webhook.Name, "nodeCheck", req.NamespacedName, "category", payload.Category)
+				failed = err
+				continue
+			}
+			r.deliveries.record(webhook.Name, req.NamespacedName, payload.Results)
+		}
+	}
+	return ctrl.Result{}, failed
+}
+
+// pending returns the payloads of the check categories of a webhook with results not delivered yet
+func (r *ResultWebhookReconciler) pending(webhook *nodecheckv1alpha1.ResultWebhookConfig, key client.ObjectKey,
+	nodeCheck *nodecheckv1alpha1.NodeCheck, categories map[string]map[string]nodecheckv1alpha1.CheckResult) []resultwebhook.Payload {
+	var payloads []resultwebhook.Payload
+	for _, category := range checkCategories {
+		if !resultwebhook.Covers(webhook, category) {
+			continue
+		}
+		if results := r.deliveries.pending(webhook.Name, key, categories[category]); len(results) > 0 {
+			payloads = append(payloads, resultwebhook.NewPayload(nodeCheck, category, results))
+		}
+	}
+	return payloads
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ResultWebhookReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("resultwebhook").
+		For(&nodecheckv1alpha1.NodeCheck{}).
+		Complete(selfstatus.Track("ResultWebhooks", r))
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/rulepacks"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+)
+
+// defaultRulePacksRefresh is how often the rule pack artifacts are checked for updates, unless
+// spec.rulePacks.refreshInterval is set
+const defaultRulePacksRefresh = time.Hour
+
+// rulePackConfigMapPrefix prefixes the ConfigMaps the artifacts are pulled into
+const rulePackConfigMapPrefix = "node-check-operator-rulepack-"
+
+// RulePackReconciler pulls the OCI artifacts of spec.rulePacks of the NodeCheckOperatorConfig into
+// ConfigMaps of the operator namespace, where the executors read the rule packs from. A ConfigMap is only
+// rewritten when the digest of its artifact changes, and removed when the artifact is no longer listed.
+// An artifact that cannot be pulled keeps its previous ConfigMap.
+type RulePackReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Clientset kubernetes.Interface
+	Config    *operatorconfig.Config
+}
+
+//+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;create;update;delete
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Reconcile pulls the rule pack artifacts of the NodeCheckOperatorConfig singleton
+func (r *RulePackReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("RulePackReconciler")
+
+	if req.Name != nodecheckv1alpha1.NodeCheckOperatorConfigName {
+		return ctrl.Result{}, nil
+	}
+	var spec nodecheckv1alpha1.RulePacksConfig
+	var config nodecheckv1alpha1.NodeCheckOperatorConfig
+	if err := r.Get(ctx, req.NamespacedName, &config); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	} else if config.Spec.RulePacks != nil {
+		spec = *config.Spec.RulePacks
+	}
+
+	settings, err := r.Config.Load(ctx, r.Client)
+	if err != nil {
+		log.Error(err, "unable to read the NodeCheckOperatorConfig, using the namespace of the environment")
+	}
+	namespace := settings.WatchNamespace
+	configMaps := r.Clientset.CoreV1().ConfigMaps(namespace)
+
+	existing, err := configMaps.List(ctx, metav1.ListOptions{LabelSelector: rulepacks.Label + "=true"})
+	if err != nil {
+		log.Error(err, "unable to list the rule pack ConfigMaps", "namespace", namespace)
+		return ctrl.Result{}, err
+	}
+	pulled := make(map[string]*corev1.ConfigMap)
+	for i := range existing.Items {
+		if source := existing.Items[i].Annotations[rulepacks.SourceAnnotation]; source != "" {
+			pulled[existing.Items[i].Name] = &existing.Items[i]
+		}
+	}
+
+	var failed error
+	wanted := make(map[string]bool)
+	for _, ref := range spec.Artifacts {
+		name := rulePackConfigMapName(ref)
+		wanted[name] = true
+		if err := r.pullArtifact(ctx, log, namespace, &spec, ref, name, pulled[name]); err != nil {
+			log.Error(err, "unable to pull rule pack artifact, keeping the previous rules", "artifact", ref)
+			failed = err
+		}
+	}
+	for name := range pulled {
+		if wanted[name] {
+			continue
+		}
+		if err := configMaps.Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+			log.Error(err, "unable to delete rule pack ConfigMap", "configMap", name)
+			failed = err
+			continue
+		}
+		log.Info("Removed rule pack of an artifact no longer configured", "configMap", name,
+			"artifact", pulled[name].Annotations[rulepacks.SourceAnnotation])
+	}
+	if failed != nil {
+		return ctrl.Result{}, failed
+	}
+	if len(spec.Artifacts) == 0 {
+		return ctrl.Result{}, nil
+	}
+	refresh := defaultRulePacksRefresh
+	if spec.RefreshInterval != nil && spec.RefreshInterval.Duration > 0 {
+		refresh = spec.RefreshInterval.Duration
+	}
+	return ctrl.Result{RequeueAfter: refresh}, nil
+}
+
+// pullArtifact pulls an artifact into its ConfigMap, unless the ConfigMap already holds the current digest
+func (r *RulePackReconciler) pullArtifact(ctx context.Context, log logr.Logger, namespace string, spec *nodecheckv1alpha1.RulePacksConfig,
+	ref, name string, current *corev1.ConfigMap) error {
+	credentials, err := r.pullCredentials(ctx, namespace, spec.PullSecret, ref)
+	if err != nil {
+		return err
+	}
+	artifact, err := rulepacks.Pull(ctx, ref, credentials)
+	if err != nil {
+		return err
+	}
+	if current != nil && current.Annotations[rulepacks.DigestAnnotation] == artifact.Digest &&
+		current.Annotations[rulepacks.SourceAnnotation] == ref {
+		return nil
+	}
+	// Invalid packs are still stored: the executors report them in the known_issues check
+	for file, data := range artifact.Files {
+		if _, err := rulepacks.Parse([]byte(data)); err != nil {
+			log.Error(err, "invalid rule pack in artifact", "artifact", ref, "file", file)
+		}
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{rulepacks.Label: "true"},
+			Annotations: map[string]string{
+				rulepacks.SourceAnnotation: ref,
+				rulepacks.DigestAnnotation: artifact.Digest,
+			},
+		},
+		Data: artifact.Files,
+	}
+	configMaps := r.Clientset.CoreV1().ConfigMaps(namespace)
+	if current == nil {
+		_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+	} else {
+		configMap.ResourceVersion = current.ResourceVersion
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	log.Info("Pulled rule pack artifact", "artifact", ref, "digest", artifact.Digest,
+		"configMap", name, "files", len(artifact.Files))
+	return nil
+}
+
+// pullCredentials returns the credentials of the registry of an artifact from spec.rulePacks.pullSecret
+func (r *RulePackReconciler) pullCredentials(ctx context.Context, namespace, pullSecret, ref string) (rulepacks.Credentials, error) {
+	if pullSecret == "" {
+		return rulepacks.Credentials{}, nil
+	}
+	secret, err := r.Clientset.CoreV1().Secrets(namespace).Get(ctx, pullSecret, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return rulepacks.Credentials{}, fmt.Errorf("secret %s/%s not found", namespace, pullSecret)
+		}
+		return rulepacks.Credentials{}, err
+	}
+	return rulepacks.DockerConfigCredentials(secret.Data[corev1.DockerConfigJsonKey], ref)
+}
+
+// rulePackConfigMapName returns the name of the ConfigMap an artifact is pulled into
+func rulePackConfigMapName(ref string) string {
+	sum := sha256.Sum256([]byte(ref))
+	return rulePackConfigMapPrefix + hex.EncodeToString(sum[:])[:10]
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RulePackReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("rulepacks").
+		For(&nodecheckv1alpha1.NodeCheckOperatorConfig{}).
+		Complete(selfstatus.Track("RulePacks", r))
+}
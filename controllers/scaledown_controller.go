@@ -0,0 +1,206 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+	"github.com/albertofilice/node-check-operator/pkg/verification"
+)
+
+const (
+	// scaleDownDisabledAnnotation keeps the cluster autoscaler from removing a node
+	scaleDownDisabledAnnotation = "cluster-autoscaler.kubernetes.io/scale-down-disabled"
+	// investigationAnnotation acknowledges that a node is being investigated: it stays protected until
+	// the annotation is removed. The value is free text (who and why).
+	investigationAnnotation = "nodecheck.openshift.io/investigation"
+	// scaleDownProtectedAnnotation marks the scale-down-disabled annotations set by the operator, with the
+	// reason, so the ones set by hand are never removed
+	scaleDownProtectedAnnotation = "nodecheck.openshift.io/scale-down-protected"
+	// scaleDownProtectedUntilAnnotation is when the protection of a node no longer under investigation
+	// ends, once spec.scaleDownProtection.holdFor has elapsed
+	scaleDownProtectedUntilAnnotation = "nodecheck.openshift.io/scale-down-protected-until"
+)
+
+// defaultScaleDownHoldFor is how long a node stays protected after its last Critical check recovered,
+// unless spec.scaleDownProtection.holdFor is set
+const defaultScaleDownHoldFor = time.Hour
+
+// ScaleDownProtectionReconciler protects the nodes under investigation from the cluster autoscaler
+// (NodeCheckOperatorConfig spec.scaleDownProtection): while one of the NodeChecks of a node has a
+// Critical check, or the node carries the investigation annotation, the node is annotated with
+// cluster-autoscaler.kubernetes.io/scale-down-disabled. The annotation is removed holdFor after the
+// investigation ends, or at once when the protection is disabled.
+type ScaleDownProtectionReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// Reconcile protects or releases a node
+func (r *ScaleDownProtectionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("ScaleDownProtectionReconciler").WithValues("node", req.Name)
+
+	var node corev1.Node
+	if err := r.Get(ctx, client.ObjectKey{Name: req.Name}, &node); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	var config nodecheckv1alpha1.NodeCheckOperatorConfig
+	if err := r.Get(ctx, client.ObjectKey{Name: nodecheckv1alpha1.NodeCheckOperatorConfigName}, &config); client.IgnoreNotFound(err) != nil {
+		return ctrl.Result{}, err
+	}
+	spec := config.Spec.ScaleDownProtection
+	_, owned := node.Annotations[scaleDownProtectedAnnotation]
+	if spec == nil {
+		if !owned {
+			return ctrl.Result{}, nil
+		}
+		log.Info("Scale-down protection disabled, releasing the node")
+		return ctrl.Result{}, r.release(ctx, &node)
+	}
+
+	reason, err := r.investigation(ctx, &node)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if reason != "" {
+		if !owned && node.Annotations[scaleDownDisabledAnnotation] != "" {
+			// Set by hand: the node is protected already and the annotation is not the operator's
+			return ctrl.Result{}, nil
+		}
+		_, releasing := node.Annotations[scaleDownProtectedUntilAnnotation]
+		if owned && !releasing && node.Annotations[scaleDownProtectedAnnotation] == reason && node.Annotations[scaleDownDisabledAnnotation] == "true" {
+			return ctrl.Result{}, nil
+		}
+		patch := client.MergeFrom(node.DeepCopy())
+		if node.Annotations == nil {
+			node.Annotations = make(map[string]string)
+		}
+		node.Annotations[scaleDownDisabledAnnotation] = "true"
+		node.Annotations[scaleDownProtectedAnnotation] = reason
+		delete(node.Annotations, scaleDownProtectedUntilAnnotation)
+		if err := r.Patch(ctx, &node, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		if !owned {
+			log.Info("Node protected from the cluster autoscaler scale-down", "reason", reason)
+		}
+		return ctrl.Result{}, nil
+	}
+	if !owned {
+		return ctrl.Result{}, nil
+	}
+
+	// No longer under investigation: keep the node for holdFor, then release it
+	holdFor := defaultScaleDownHoldFor
+	if spec.HoldFor != nil && spec.HoldFor.Duration >= 0 {
+		holdFor = spec.HoldFor.Duration
+	}
+	until, err := time.Parse(time.RFC3339, node.Annotations[scaleDownProtectedUntilAnnotation])
+	if err != nil {
+		until = time.Now().Add(holdFor).Truncate(time.Second)
+		patch := client.MergeFrom(node.DeepCopy())
+		node.Annotations[scaleDownProtectedUntilAnnotation] = until.UTC().Format(time.RFC3339)
+		if err := r.Patch(ctx, &node, patch); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("Investigation over, the node stays protected from the scale-down", "until", until.UTC().Format(time.RFC3339))
+	}
+	if remaining := time.Until(until); remaining > 0 {
+		return ctrl.Result{RequeueAfter: remaining}, nil
+	}
+	log.Info("Releasing the node to the cluster autoscaler")
+	return ctrl.Result{}, r.release(ctx, &node)
+}
+
+// investigation returns why a node is under investigation, or "" when it is not: the investigation
+// annotation, then the Critical checks of its NodeChecks. The one-shot NodeChecks of the
+// post-maintenance verification run with tighter thresholds and are left out.
+func (r *ScaleDownProtectionReconciler) investigation(ctx context.Context, node *corev1.Node) (string, error) {
+	if value := strings.TrimSpace(node.Annotations[investigationAnnotation]); value != "" {
+		return fmt.Sprintf("investigation: %s", value), nil
+	}
+	var nodeChecks nodecheckv1alpha1.NodeCheckList
+	if err := r.List(ctx, &nodeChecks); err != nil {
+		return "", err
+	}
+	critical := make(map[string]bool)
+	for _, nodeCheck := range nodeChecks.Items {
+		if nodeCheck.Status.NodeName != node.Name || nodeCheck.Labels[verification.Label] != "" {
+			continue
+		}
+		for name, result := range combineCheckResults(flattenCheckResults(nodeCheck.Status.CheckResults)) {
+			if result.Status == "Critical" {
+				critical[name] = true
+			}
+		}
+	}
+	if len(critical) == 0 {
+		return "", nil
+	}
+	names := make([]string, 0, len(critical))
+	for name := range critical {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return fmt.Sprintf("critical: %s", strings.Join(names, ", ")), nil
+}
+
+// release removes the annotations of the operator from a node
+func (r *ScaleDownProtectionReconciler) release(ctx context.Context, node *corev1.Node) error {
+	patch := client.MergeFrom(node.DeepCopy())
+	delete(node.Annotations, scaleDownDisabledAnnotation)
+	delete(node.Annotations, scaleDownProtectedAnnotation)
+	delete(node.Annotations, scaleDownProtectedUntilAnnotation)
+	return client.IgnoreNotFound(r.Patch(ctx, node, patch))
+}
+
+// nodeRequest queues the node of a NodeCheck
+func nodeRequest(_ context.Context, obj client.Object) []reconcile.Request {
+	nodeCheck, ok := obj.(*nodecheckv1alpha1.NodeCheck)
+	if !ok {
+		return nil
+	}
+	// Parent NodeChecks ("*", "all") have no results of their own
+	nodeName := nodeCheck.Status.NodeName
+	if nodeName == "" || nodeName == "*" || nodeName == "all" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: nodeName}}}
+}
+
+// allNodeRequests queues every node when the NodeCheckOperatorConfig changes, so the nodes are
+// protected as soon as the protection is enabled and released when it is disabled
+func (r *ScaleDownProtectionReconciler) allNodeRequests(ctx context.Context, _ client.Object) []reconcile.Request {
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		ctrl.Log.WithName("ScaleDownProtectionReconciler").Error(err, "unable to list the nodes")
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: node.Name}})
+	}
+	return requests
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ScaleDownProtectionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("scaledownprotection").
+		For(&corev1.Node{}).
+		Watches(&nodecheckv1alpha1.NodeCheck{}, handler.EnqueueRequestsFromMapFunc(nodeRequest)).
+		Watches(&nodecheckv1alpha1.NodeCheckOperatorConfig{}, handler.EnqueueRequestsFromMapFunc(r.allNodeRequests)).
+		Complete(selfstatus.Track("ScaleDownProtection", r))
+}
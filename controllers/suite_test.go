@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/checks"
+	"github.com/albertofilice/node-check-operator/pkg/checks/hostfake"
+)
+
+// The suite runs the NodeCheckExecutorReconciler and the NodeCheckTemplateReconciler against the API
+// server and etcd of envtest, with the CRDs of config/crd/bases. The binaries are found through
+// KUBEBUILDER_ASSETS, which `make test` sets; without them the suite is skipped.
+
+// envtestNodeName is the node the executor of the suite runs on (NODE_NAME)
+const envtestNodeName = "envtest-node"
+
+// envtestNamespace is the namespace of the objects of the suite
+const envtestNamespace = "default"
+
+// Polling of the assertions on the objects written by the reconcilers
+const (
+	eventuallyTimeout  = 30 * time.Second
+	eventuallyInterval = 250 * time.Millisecond
+)
+
+// k8sClient reads and writes the objects of the suite, bypassing the cache of the manager
+var k8sClient client.Client
+
+func TestMain(m *testing.M) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		fmt.Println("Skipping the controller suite: KUBEBUILDER_ASSETS is not set (run make test)")
+		os.Exit(0)
+	}
+	os.Exit(runSuite(m))
+}
+
+// runSuite starts envtest and the manager running the reconcilers, runs the tests and stops them
+func runSuite(m *testing.M) int {
+	ctrl.SetLogger(zap.New(zap.WriteTo(os.Stderr), zap.UseDevMode(true)))
+
+	testEnv := &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+	config, err := testEnv.Start()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to start envtest: %v\n", err)
+		return 1
+	}
+	defer testEnv.Stop()
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if err := nodecheckv1alpha1.AddToScheme(scheme); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	k8sClient, err = client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create the client: %v\n", err)
+		return 1
+	}
+
+	// The executor runs on envtestNodeName. Its host commands get no canned output, so the checks take
+	// their fallback path instead of entering the namespaces of the host.
+	os.Setenv("NODE_NAME", envtestNodeName)
+	checks.SetHostRunner(hostfake.New())
+
+	mgr, err := ctrl.NewManager(config, ctrl.Options{
+		Scheme:  scheme,
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create the manager: %v\n", err)
+		return 1
+	}
+	if err := (&NodeCheckExecutorReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Clientset: kubernetes.NewForConfigOrDie(config),
+	}).SetupWithManager(mgr); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to set up the executor: %v\n", err)
+		return 1
+	}
+	if err := (&NodeCheckTemplateReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		fmt.Fprintf(os.Stderr, "unable to set up the template controller: %v\n", err)
+		return 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() {
+		stopped <- mgr.Start(ctx)
+	}()
+	code := m.Run()
+	cancel()
+	if err := <-stopped; err != nil {
+		fmt.Fprintf(os.Stderr, "manager stopped with an error: %v\n", err)
+		return 1
+	}
+	return code
+}
+
+// eventually polls condition until it returns nil, failing the test with its last error after
+// eventuallyTimeout
+func eventually(t *testing.T, condition func() error) {
+	t.Helper()
+	deadline := time.Now().Add(eventuallyTimeout)
+	for {
+		err := condition()
+		if err == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met after %s: %v", eventuallyTimeout, err)
+		}
+		time.Sleep(eventuallyInterval)
+	}
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/history"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+	"github.com/albertofilice/node-check-operator/pkg/telemetry"
+)
+
+// defaultTelemetryInterval is how often the telemetry report is sent, unless spec.telemetry.interval is set
+const defaultTelemetryInterval = 24 * time.Hour
+
+// TelemetryReconciler sends the anonymized fleet statistics to the endpoint of spec.telemetry of the
+// NodeCheckOperatorConfig, once per interval. Nothing is collected or sent while spec.telemetry is unset.
+// The time of the last report is kept in memory, so a restart of the operator sends a report right away.
+type TelemetryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Config *operatorconfig.Config
+
+	mu       sync.Mutex
+	lastSent time.Time
+}
+
+// Reconcile sends the telemetry report when the interval elapsed since the previous one
+func (r *TelemetryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("TelemetryReconciler")
+
+	if req.Name != nodecheckv1alpha1.NodeCheckOperatorConfigName {
+		return ctrl.Result{}, nil
+	}
+	var config nodecheckv1alpha1.NodeCheckOperatorConfig
+	if err := r.Get(ctx, req.NamespacedName, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	spec := config.Spec.Telemetry
+	if spec == nil {
+		return ctrl.Result{}, nil
+	}
+	interval := defaultTelemetryInterval
+	if spec.Interval != nil && spec.Interval.Duration > 0 {
+		interval = spec.Interval.Duration
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if wait := time.Until(r.lastSent.Add(interval)); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	settings, err := r.Config.Load(ctx, r.Client)
+	if err != nil {
+		log.Error(err, "unable to read the NodeCheckOperatorConfig, using the image of the environment")
+	}
+	var nodes corev1.NodeList
+	if err := r.List(ctx, &nodes); err != nil {
+		log.Error(err, "unable to list the nodes")
+		return ctrl.Result{}, err
+	}
+	var nodeChecks nodecheckv1alpha1.NodeCheckList
+	if err := r.List(ctx, &nodeChecks); err != nil {
+		log.Error(err, "unable to list the NodeChecks")
+		return ctrl.Result{}, err
+	}
+
+	report := telemetry.NewReport(telemetry.InstallationID(string(config.UID)),
+		telemetry.OperatorVersion(settings.OperatorImage), len(nodes.Items))
+	for i := range nodeChecks.Items {
+		status := &nodeChecks.Items[i].Status
+		// Parent NodeChecks ("*", "all") have no results of their own
+		if status.NodeName == "" || status.NodeName == "*" || status.NodeName == "all" {
+			continue
+		}
+		results, kubernetesResults := flattenCheckResults(status.CheckResults)
+		for name, result := range kubernetesResults {
+			results[name] = result
+		}
+		report.Add(status.History, results)
+	}
+	// The false positives marked from the dashboard since the previous report
+	if feedback, err := history.CurrentFeedback().QueryFeedback(ctx, time.Now().Add(-interval)); err != nil {
+		log.Error(err, "unable to read the false-positive feedback, reporting without it")
+	} else {
+		for _, summary := range history.SummarizeFeedback(feedback) {
+			report.AddFalsePositives(summary.Check, summary.FalsePositives)
+		}
+	}
+
+	if err := telemetry.Send(ctx, spec.Endpoint, report); err != nil {
+		log.Error(err, "unable to send the telemetry report", "endpoint", spec.Endpoint)
+		return ctrl.Result{}, err
+	}
+	r.lastSent = time.Now()
+	log.Info("Sent the telemetry report", "endpoint", spec.Endpoint, "checks", len(report.Checks),
+		"clusterSize", report.ClusterSize)
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TelemetryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("telemetry").
+		For(&nodecheckv1alpha1.NodeCheckOperatorConfig{}).
+		Complete(selfstatus.Track("Telemetry", r))
+}
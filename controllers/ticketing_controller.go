@@ -0,0 +1,312 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
+	"github.com/albertofilice/node-check-operator/pkg/ticketing"
+)
+
+// defaultCriticalFor is how long a check must stay Critical before an issue is opened, unless
+// spec.ticketing.criticalFor is set
+const defaultCriticalFor = 30 * time.Minute
+
+// openIssuesRefresh is how often the open issues are listed again from the tracker, so issues closed or
+// opened by hand are taken into account
+const openIssuesRefresh = 10 * time.Minute
+
+// TicketingReconciler reports the checks that stay Critical to the issue tracker of spec.ticketing of the
+// NodeCheckOperatorConfig. It opens an issue per node and check once the check has been Critical for
+// spec.ticketing.criticalFor, and closes it when the check is Healthy again. The issues carry the node and
+// check they are about, so an issue already open (e.g. before a restart of the operator) is never duplicated.
+type TicketingReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Clientset kubernetes.Interface
+	Config    *operatorconfig.Config
+
+	mu sync.Mutex
+	// criticalSince is when each check (keyed by ticketing.Key) was first seen Critical, for the
+	// NodeChecks without history
+	criticalSince map[string]time.Time
+	// issues caches the open issues of the tracker configured by issuesConfig, listed at issuesListed
+	issues       map[string]ticketing.Issue
+	issuesConfig ticketing.Config
+	issuesListed time.Time
+}
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// Reconcile opens and closes the issues of the checks of a NodeCheck
+func (r *TicketingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.Log.WithName("TicketingReconciler")
+
+	var config nodecheckv1alpha1.NodeCheckOperatorConfig
+	if err := r.Get(ctx, client.ObjectKey{Name: nodecheckv1alpha1.NodeCheckOperatorConfigName}, &config); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if config.Spec.Ticketing == nil {
+		return ctrl.Result{}, nil
+	}
+	spec := config.Spec.Ticketing
+
+	var nodeCheck nodecheckv1alpha1.NodeCheck
+	if err := r.Get(ctx, req.NamespacedName, &nodeCheck); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	// Parent NodeChecks ("*", "all") have no results of their own
+	nodeName := nodeCheck.Status.NodeName
+	if nodeName == "" || nodeName == "*" || nodeName == "all" {
+		return ctrl.Result{}, nil
+	}
+
+	criticalFor := defaultCriticalFor
+	if spec.CriticalFor != nil && spec.CriticalFor.Duration > 0 {
+		criticalFor = spec.CriticalFor.Duration
+	}
+
+	// Work out the issues to open and to close
+	systemResults, kubernetesResults := flattenCheckResults(nodeCheck.Status.CheckResults)
+	results := combineCheckResults(systemResults, kubernetesResults)
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	now := time.Now()
+	var toOpen, toClose []string
+	var requeueAfter time.Duration
+	r.mu.Lock()
+	if r.criticalSince == nil {
+		r.criticalSince = make(map[string]time.Time)
+	}
+	for _, name := range names {
+		key := ticketing.Key(nodeName, name)
+		switch results[name].Status {
+		case "Critical":
+			since, ok := criticalSinceHistory(nodeCheck.Status.History, name)
+			if !ok {
+				if _, seen := r.criticalSince[key]; !seen {
+					r.criticalSince[key] = results[name].Timestamp.Time
+				}
+				since = r.criticalSince[key]
+			}
+			if remaining := criticalFor - now.Sub(since); remaining > 0 {
+				if requeueAfter == 0 || remaining < requeueAfter {
+					requeueAfter = remaining
+				}
+			} else {
+				toOpen = append(toOpen, name)
+			}
+		case "Healthy":
+			delete(r.criticalSince, key)
+			toClose = append(toClose, name)
+		default:
+			// Warning, Unknown and Suppressed results neither open nor close an issue
+			delete(r.criticalSince, key)
+		}
+	}
+	// While the cached issues are fresh, only the Healthy checks with an open issue need the tracker
+	if len(toOpen) == 0 && r.issues != nil && now.Sub(r.issuesListed) < openIssuesRefresh {
+		open := toClose[:0]
+		for _, name := range toClose {
+			if _, ok := r.issues[ticketing.Key(nodeName, name)]; ok {
+				open = append(open, name)
+			}
+		}
+		toClose = open
+	}
+	r.mu.Unlock()
+	if len(toOpen) == 0 && len(toClose) == 0 {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+
+	settings, err := r.Config.Load(ctx, r.Client)
+	if err != nil {
+		log.Error(err, "unable to read the NodeCheckOperatorConfig, using the namespace of the environment")
+	}
+	trackerConfig, err := r.trackerConfig(ctx, spec, settings.WatchNamespace)
+	if err != nil {
+		log.Error(err, "unable to read the ticketing credentials", "secret", spec.CredentialsSecret)
+		return ctrl.Result{}, err
+	}
+	tracker, err := ticketing.New(trackerConfig)
+	if err != nil {
+		log.Error(err, "invalid ticketing configuration")
+		return ctrl.Result{}, nil
+	}
+	issues, err := r.openIssues(ctx, tracker, trackerConfig)
+	if err != nil {
+		log.Error(err, "unable to list the open issues", "provider", trackerConfig.Provider)
+		return ctrl.Result{}, err
+	}
+
+	var failed error
+	for _, name := range toOpen {
+		key := ticketing.Key(nodeName, name)
+		if _, ok := issues[key]; ok {
+			continue
+		}
+		issue, err := tracker.Create(ctx, criticalIssue(&nodeCheck, spec, nodeName, name, results[name], criticalFor, key))
+		if err != nil {
+			log.Error(err, "unable to open issue", "node", nodeName, "check", name)
+			failed = err
+			continue
+		}
+		log.Info("Opened issue for Critical check", "node", nodeName, "check", name, "issue", issue.URL)
+		r.recordIssue(trackerConfig, key, &issue)
+	}
+	for _, name := range toClose {
+		key := ticketing.Key(nodeName, name)
+		issue, ok := issues[key]
+		if !ok {
+			continue
+		}
+		comment := fmt.Sprintf("Check %s on node %s is Healthy again: %s", name, nodeName, results[name].Message)
+		if err := tracker.Close(ctx, issue, comment); err != nil {
+			log.Error(err, "unable to close issue", "node", nodeName, "check", name, "issue", issue.URL)
+			failed = err
+			continue
+		}
+		log.Info("Closed issue of recovered check", "node", nodeName, "check", name, "issue", issue.URL)
+		r.recordIssue(trackerConfig, key, nil)
+	}
+	if failed != nil {
+		return ctrl.Result{}, failed
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// criticalSinceHistory returns when a check turned Critical according to its history (spec.historySize)
+func criticalSinceHistory(history []nodecheckv1alpha1.CheckHistory, name string) (time.Time, bool) {
+	for _, check := range history {
+		if check.Name != name || len(check.Entries) == 0 || check.Entries[len(check.Entries)-1].Status != "Critical" {
+			continue
+		}
+		// Walk back to the first entry of the current Critical streak
+		since := check.Entries[len(check.Entries)-1].Timestamp.Time
+		for i := len(check.Entries) - 2; i >= 0 && check.Entries[i].Status == "Critical"; i-- {
+			since = check.Entries[i].Timestamp.Time
+		}
+		if check.LastTransitionTime != nil && check.LastTransitionTime.Time.Before(since) {
+			since = check.LastTransitionTime.Time
+		}
+		return since, true
+	}
+	return time.Time{}, false
+}
+
+// criticalIssue builds the issue of a check that stayed Critical
+func criticalIssue(nodeCheck *nodecheckv1alpha1.NodeCheck, spec *nodecheckv1alpha1.TicketingConfig, nodeName, name string,
+	result nodecheckv1alpha1.CheckResult, criticalFor time.Duration, key string) ticketing.Issue {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Check `%s` has been Critical on node `%s` for more than %s.\n\n", name, nodeName, criticalFor)
+	fmt.Fprintf(&body, "- Message: %s\n", result.Message)
+	fmt.Fprintf(&body, "- Last run: %s\n", result.Timestamp.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&body, "- NodeCheck: %s/%s\n", nodeCheck.Namespace, nodeCheck.Name)
+	if good := detailString(&result, lastKnownGoodDetail); good != "" {
+		fmt.Fprintf(&body, "- Last Healthy result: %s\n", good)
+	}
+	if spec.ConsoleURL != "" {
+		fmt.Fprintf(&body, "- Details: %s/k8s/ns/%s/nodecheck.openshift.io~v1alpha1~NodeCheck/%s\n",
+			strings.TrimSuffix(spec.ConsoleURL, "/"), nodeCheck.Namespace, nodeCheck.Name)
+	}
+	body.WriteString("\nThe issue is closed automatically once the check is Healthy again.")
+	return ticketing.Issue{
+		Key:   key,
+		Title: fmt.Sprintf("[node-check] %s: %s is Critical", nodeName, name),
+		Body:  body.String(),
+	}
+}
+
+// trackerConfig returns the tracker configuration with the credentials of spec.ticketing.credentialsSecret
+func (r *TicketingReconciler) trackerConfig(ctx context.Context, spec *nodecheckv1alpha1.TicketingConfig, namespace string) (ticketing.Config, error) {
+	secret, err := r.Clientset.CoreV1().Secrets(namespace).Get(ctx, spec.CredentialsSecret, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ticketing.Config{}, fmt.Errorf("secret %s/%s not found", namespace, spec.CredentialsSecret)
+		}
+		return ticketing.Config{}, err
+	}
+	return ticketing.Config{
+		Provider:   spec.Provider,
+		URL:        spec.URL,
+		Repository: spec.Repository,
+		Project:    spec.Project,
+		IssueType:  spec.IssueType,
+		Username:   string(secret.Data["username"]),
+		Token:      strings.TrimSpace(string(secret.Data["token"])),
+		Labels:     spec.Labels,
+	}, nil
+}
+
+// openIssues returns the open issues of the tracker, listed again every openIssuesRefresh or when the
+// configuration changes
+func (r *TicketingReconciler) openIssues(ctx context.Context, tracker ticketing.Tracker, config ticketing.Config) (map[string]ticketing.Issue, error) {
+	r.mu.Lock()
+	if r.issues != nil && sameTracker(r.issuesConfig, config) && time.Since(r.issuesListed) < openIssuesRefresh {
+		issues := make(map[string]ticketing.Issue, len(r.issues))
+		for key, issue := range r.issues {
+			issues[key] = issue
+		}
+		r.mu.Unlock()
+		return issues, nil
+	}
+	r.mu.Unlock()
+
+	issues, err := tracker.OpenIssues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.issues = make(map[string]ticketing.Issue, len(issues))
+	for key, issue := range issues {
+		r.issues[key] = issue
+	}
+	r.issuesConfig = config
+	r.issuesListed = time.Now()
+	return issues, nil
+}
+
+// recordIssue updates the cached open issues after an issue is opened, or closed (nil issue)
+func (r *TicketingReconciler) recordIssue(config ticketing.Config, key string, issue *ticketing.Issue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.issues == nil || !sameTracker(r.issuesConfig, config) {
+		return
+	}
+	if issue == nil {
+		delete(r.issues, key)
+	} else {
+		r.issues[key] = *issue
+	}
+}
+
+// sameTracker reports whether two configurations point to the same issues
+func sameTracker(a, b ticketing.Config) bool {
+	return a.Provider == b.Provider && a.URL == b.URL && a.Repository == b.Repository && a.Project == b.Project
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TicketingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("ticketing").
+		For(&nodecheckv1alpha1.NodeCheck{}).
+		Complete(selfstatus.Track("Ticketing", r))
+}
@@ -11,5 +11,8 @@ require (
 	k8s.io/apimachinery v0.28.0
 	k8s.io/client-go v0.28.0
 	k8s.io/metrics v0.28.0
+	modernc.org/sqlite v1.27.0
 	sigs.k8s.io/controller-runtime v0.16.0
 )
+
+require k8s.io/apiextensions-apiserver v0.28.0 // indirect
@@ -3,8 +3,9 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
-	"strings"
+	"sync"
 	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -19,7 +20,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -27,6 +30,10 @@ import (
 	nodecheckv1alpha1 "github.com/albertofilice/node-check-operator/api/v1alpha1"
 	"github.com/albertofilice/node-check-operator/controllers"
 	"github.com/albertofilice/node-check-operator/pkg/dashboard"
+	dashboardapi "github.com/albertofilice/node-check-operator/pkg/dashboard/api"
+	"github.com/albertofilice/node-check-operator/pkg/nodehealth"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
 	_ "github.com/albertofilice/node-check-operator/pkg/metrics" // Import to initialize metrics
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"k8s.io/client-go/kubernetes"
@@ -67,6 +74,7 @@ func main() {
 	var probeAddr string
 	var mode string
 	var enableOpenShiftFeatures bool = true
+	var enableNodeHealthAPI bool = false
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":31680", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":31681", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -79,21 +87,14 @@ func main() {
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	if val := strings.ToLower(os.Getenv("ENABLE_OPENSHIFT_FEATURES")); val != "" {
-		switch val {
-		case "false", "0", "no", "disabled":
-			enableOpenShiftFeatures = false
-		default:
-			enableOpenShiftFeatures = true
-		}
-	}
-	
+	// Environment variables are the defaults of the NodeCheckOperatorConfig singleton
+	operatorConfig := operatorconfig.New(operatorconfig.FromEnvironment(enableOpenShiftFeatures, enableNodeHealthAPI))
+
 	if mode != "operator" && mode != "executor" {
 		setupLog.Error(nil, "Invalid mode", "mode", mode, "validModes", []string{"operator", "executor"})
 		os.Exit(1)
 	}
 	setupLog.Info("Starting in mode", "mode", mode)
-	setupLog.Info("OpenShift integrations enabled", "enabled", enableOpenShiftFeatures)
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
@@ -126,6 +127,12 @@ func main() {
 	}
 	setupLog.Info("NodeCheck type registered in manager scheme", "gvk", managerGVK[0])
 
+	// Track controller internals for the health probes and /api/v1/selfstatus
+	selfstatus.SetMode(mode)
+	selfstatus.SetCacheSyncFunc(mgr.GetCache().WaitForCacheSync)
+	// The History controller reports the backend of spec.history once it runs
+	selfstatus.SetComponent("history-store", selfstatus.StatusNotConfigured, "no history backend configured")
+
 	// Create a test instance to verify it can get its GVK
 	testNodeCheck := &nodecheckv1alpha1.NodeCheck{}
 	testGVK, _, testErr := managerScheme.ObjectKinds(testNodeCheck)
@@ -151,97 +158,28 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create Dashboard Service immediately (only in operator mode with OpenShift features)
-	// This ensures the Service exists before the dashboard server starts, allowing
-	// the Service Serving Certificate Signer to create the TLS secret
-	if mode == "operator" && enableOpenShiftFeatures {
-		namespace := "node-check-operator-system"
-		serviceName := "node-check-operator-dashboard"
-		
-		setupLog.Info("Ensuring Dashboard Service exists", "service", serviceName, "namespace", namespace)
-		
-		// Create Service directly using the clientset (works before manager starts)
-		ctx := context.Background()
-		service := &corev1.Service{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      serviceName,
-				Namespace: namespace,
-				Labels: map[string]string{
-					"control-plane": "controller-manager",
-				},
-				Annotations: map[string]string{
-					"service.beta.openshift.io/serving-cert-secret-name": "node-check-operator-dashboard-tls",
-				},
-			},
-			Spec: corev1.ServiceSpec{
-				Selector: map[string]string{
-					"control-plane": "controller-manager",
-				},
-				Ports: []corev1.ServicePort{
-					{
-						Port:       31682,
-						TargetPort: intstr.FromInt(31682),
-						Protocol:   corev1.ProtocolTCP,
-						Name:       "dashboard",
-					},
-				},
-				Type: corev1.ServiceTypeClusterIP,
-			},
-		}
-		
-		// Try to get the Service first
-		_, err := clientset.CoreV1().Services(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	// Apply the NodeCheckOperatorConfig singleton, if any, before starting the dashboard server and the
+	// aggregated API; the OperatorConfig controller applies its later changes
+	var components *runtimeComponents
+	if mode == "operator" {
+		settings, err := operatorConfig.Load(context.Background(), mgr.GetAPIReader())
 		if err != nil {
-			if errors.IsNotFound(err) {
-				// Service doesn't exist, create it
-				_, err = clientset.CoreV1().Services(namespace).Create(ctx, service, metav1.CreateOptions{})
-				if err != nil {
-					setupLog.Error(err, "unable to create Dashboard Service")
-					// Don't exit - the ConsolePlugin controller will create it later
-				} else {
-					setupLog.Info("Created Dashboard Service", "service", serviceName)
-				}
-			} else {
-				setupLog.Error(err, "unable to check Dashboard Service")
-			}
-		} else {
-			setupLog.Info("Dashboard Service already exists", "service", serviceName)
+			setupLog.Error(err, "unable to read the NodeCheckOperatorConfig, using the environment settings")
 		}
-		
-		// Start dashboard server asynchronously after a short delay
-		// This gives the Service Serving Certificate Signer time to create the secret
-		dashboardServer := dashboard.NewDashboardServer(mgr.GetClient(), clientset, namespace, 31682)
-		go func() {
-			setupLog.Info("Waiting for TLS certificates to be created by Service Serving Certificate Signer", "waitSeconds", 15)
-			time.Sleep(15 * time.Second) // Wait for Service Serving Certificate Signer
-			
-			setupLog.Info("Starting dashboard server", "port", 31682)
-			if err := dashboardServer.Start(); err != nil {
-				setupLog.Error(err, "unable to start dashboard server")
-				// Don't exit - the operator can still function without the dashboard
-			} else {
-				setupLog.Info("Dashboard server started successfully", "port", 31682)
-			}
-		}()
-	} else if mode == "operator" {
-		setupLog.Info("OpenShift-specific features disabled; skipping dashboard server")
-	}
+		operatorConfig.SetApplied(settings)
+		// The dashboard serves the ui section of the singleton to the console plugin
+		dashboardapi.SetOperatorConfig(operatorConfig)
+		setupLog.Info("OpenShift integrations enabled", "enabled", settings.OpenShiftFeatures)
+		setupLog.Info("NodeHealth aggregated API enabled", "enabled", settings.NodeHealthAPI)
 
-	// Get namespace from environment or use default
-	namespace := os.Getenv("WATCH_NAMESPACE")
-	if namespace == "" {
-		namespace = "node-check-operator-system"
-	}
-
-	// Get image from environment or use default
-	operatorImage := os.Getenv("OPERATOR_IMAGE")
-	if operatorImage == "" {
-		operatorImage = "quay.io/rh_ee_afilice/node-check-operator:v1.0.8"
-	}
-	consolePluginImage := os.Getenv("CONSOLE_PLUGIN_IMAGE")
-	if consolePluginImage == "" {
-		consolePluginImage = "quay.io/rh_ee_afilice/node-check-operator-console-plugin:v1.0.8"
+		components = &runtimeComponents{
+			k8sClient:  mgr.GetClient(),
+			clientset:  clientset,
+			restConfig: config,
+		}
+		components.apply(settings, true)
 	}
+	namespace := operatorConfig.Applied().WatchNamespace
 
 	// Setup controllers based on mode
 	if mode == "operator" {
@@ -254,33 +192,128 @@ func main() {
 			setupLog.Error(err, "unable to create controller", "controller", "NodeCheck")
 			os.Exit(1)
 		}
+
+		// Controller for NodeCheckTemplates: one "*" NodeCheck per node pool
+		if err = (&controllers.NodeCheckTemplateReconciler{
+			Client: mgr.GetClient(),
+			Scheme: managerScheme,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "NodeCheckTemplate")
+			os.Exit(1)
+		}
 		
+		// Controller for the NodeCheckOperatorConfig singleton: restarts the dashboard server and
+		// the aggregated API when their settings change
+		if err = (&controllers.OperatorConfigReconciler{
+			Client: mgr.GetClient(),
+			Scheme: managerScheme,
+			Config: operatorConfig,
+			OnChange: func(previous, current operatorconfig.Settings) {
+				components.apply(current, false)
+			},
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OperatorConfig")
+			os.Exit(1)
+		}
+
+		// Controller opening issues for persistent Critical findings (NodeCheckOperatorConfig spec.ticketing)
+		if err = (&controllers.TicketingReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    managerScheme,
+			Clientset: clientset,
+			Config:    operatorConfig,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Ticketing")
+			os.Exit(1)
+		}
+
+		// Controller protecting the nodes under investigation from the cluster autoscaler scale-down
+		// (NodeCheckOperatorConfig spec.scaleDownProtection)
+		if err = (&controllers.ScaleDownProtectionReconciler{
+			Client: mgr.GetClient(),
+			Scheme: managerScheme,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ScaleDownProtection")
+			os.Exit(1)
+		}
+
+		// Controller posting the check results to the webhooks of NodeCheckOperatorConfig spec.resultWebhooks
+		if err = (&controllers.ResultWebhookReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    managerScheme,
+			Clientset: clientset,
+			Config:    operatorConfig,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ResultWebhooks")
+			os.Exit(1)
+		}
+
+		// Controller streaming the check results to Kafka (NodeCheckOperatorConfig spec.kafkaExport)
+		if err = (&controllers.KafkaExportReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    managerScheme,
+			Clientset: clientset,
+			Config:    operatorConfig,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "KafkaExport")
+			os.Exit(1)
+		}
+
+		// Controller sending the anonymized fleet statistics (NodeCheckOperatorConfig spec.telemetry, opt-in)
+		if err = (&controllers.TelemetryReconciler{
+			Client: mgr.GetClient(),
+			Scheme: managerScheme,
+			Config: operatorConfig,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Telemetry")
+			os.Exit(1)
+		}
+
+		// Controller pulling the rule packs of OCI artifacts (NodeCheckOperatorConfig spec.rulePacks)
+		if err = (&controllers.RulePackReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    managerScheme,
+			Clientset: clientset,
+			Config:    operatorConfig,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "RulePacks")
+			os.Exit(1)
+		}
+
+		// Controller recording the check history in the store of NodeCheckOperatorConfig spec.history
+		if err = (&controllers.HistoryReconciler{
+			Client: mgr.GetClient(),
+			Scheme: managerScheme,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "History")
+			os.Exit(1)
+		}
+
 		// Controller for executor DaemonSet
 		if err = (&controllers.ExecutorDaemonSetReconciler{
 			Client:    mgr.GetClient(),
 			Scheme:    managerScheme,
 			Clientset: clientset,
-			Namespace: namespace,
-			Image:     operatorImage,
+			Config:    operatorConfig,
 		}).SetupWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "ExecutorDaemonSet")
 			os.Exit(1)
 		}
 		
-		// Controller for ConsolePlugin resources (only when OpenShift features are enabled)
-		if enableOpenShiftFeatures {
-			consolePluginReconciler := &controllers.ConsolePluginReconciler{
-				Client:    mgr.GetClient(),
-				Scheme:    managerScheme,
-				Clientset: clientset,
-				Namespace: namespace,
-				Image:     consolePluginImage,
-			}
-			if err = consolePluginReconciler.SetupWithManager(mgr); err != nil {
-				setupLog.Error(err, "unable to create controller", "controller", "ConsolePlugin")
-				os.Exit(1)
-			}
-			
+		// Controller for ConsolePlugin resources. It is always registered so the OpenShift features can be
+		// enabled at runtime through the NodeCheckOperatorConfig; it does nothing while they are disabled.
+		consolePluginReconciler := &controllers.ConsolePluginReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    managerScheme,
+			Clientset: clientset,
+			Config:    operatorConfig,
+		}
+		if err = consolePluginReconciler.SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ConsolePlugin")
+			os.Exit(1)
+		}
+		
+		if operatorConfig.Applied().OpenShiftFeatures {
 			// Trigger initial reconcile for ConsolePlugin resources on startup
 			// This ensures resources are created even if no NodeCheck exists yet
 			go func() {
@@ -299,7 +332,7 @@ func main() {
 				_, _ = consolePluginReconciler.Reconcile(ctx, req)
 			}()
 		} else {
-			setupLog.Info("OpenShift-specific features disabled; skipping ConsolePlugin controller registration")
+			setupLog.Info("OpenShift-specific features disabled; skipping ConsolePlugin resources")
 		}
 		
 	} else if mode == "executor" {
@@ -323,6 +356,15 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// Detailed sub-checks, visible with /healthz?verbose and /readyz?verbose
+	if err := mgr.AddReadyzCheck("informer-cache", selfstatus.CacheSyncCheck); err != nil {
+		setupLog.Error(err, "unable to set up informer cache ready check")
+		os.Exit(1)
+	}
+	if err := mgr.AddHealthzCheck("reconcilers", selfstatus.ReconcilersCheck); err != nil {
+		setupLog.Error(err, "unable to set up reconcilers health check")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -330,3 +372,203 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// dashboardNamespace is the namespace of the operator Deployment, which serves the dashboard
+const dashboardNamespace = "node-check-operator-system"
+
+// runtimeComponents runs the servers started outside of the manager, the dashboard and the nodehealth
+// aggregated API, and starts, stops or restarts them when the operator settings change
+type runtimeComponents struct {
+	k8sClient  client.Client
+	clientset  *kubernetes.Clientset
+	restConfig *rest.Config
+
+	mu               sync.Mutex
+	dashboard        *dashboard.DashboardServer
+	dashboardPort    int
+	dashboardStarted bool
+	nodeHealth       *nodehealth.Server
+	nodeHealthStop   context.CancelFunc
+}
+
+// apply brings the components in line with the settings. initial is true on startup, when the
+// dashboard gives the Service Serving Certificate Signer time to create its certificate.
+func (rc *runtimeComponents) apply(settings operatorconfig.Settings, initial bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.dashboard != nil && (!settings.OpenShiftFeatures || settings.DashboardPort != rc.dashboardPort) {
+		rc.stopDashboard()
+	}
+	if settings.OpenShiftFeatures && rc.dashboard == nil {
+		rc.startDashboard(settings.DashboardPort, initial)
+	} else if !settings.OpenShiftFeatures {
+		selfstatus.SetComponent("dashboard", selfstatus.StatusNotConfigured, "OpenShift features disabled")
+	}
+
+	if rc.nodeHealth != nil && !settings.NodeHealthAPI {
+		rc.stopNodeHealthAPI()
+	}
+	if settings.NodeHealthAPI && rc.nodeHealth == nil {
+		rc.startNodeHealthAPI()
+	}
+
+	dashboardapi.SetFaultInjectionEnabled(settings.FaultInjection)
+}
+
+// startDashboard ensures the dashboard Service exists and starts the dashboard server in the background
+func (rc *runtimeComponents) startDashboard(port int, initial bool) {
+	// Create the Service before the dashboard server starts, allowing
+	// the Service Serving Certificate Signer to create the TLS secret
+	rc.ensureDashboardService(port)
+
+	dashboardServer := dashboard.NewDashboardServer(rc.k8sClient, rc.clientset, rc.restConfig, dashboardNamespace, port)
+	rc.dashboard, rc.dashboardPort, rc.dashboardStarted = dashboardServer, port, false
+	selfstatus.SetComponent("dashboard", selfstatus.StatusStarting, "waiting for TLS certificates")
+	go func() {
+		if initial {
+			setupLog.Info("Waiting for TLS certificates to be created by Service Serving Certificate Signer", "waitSeconds", 15)
+			time.Sleep(15 * time.Second) // Wait for Service Serving Certificate Signer
+		}
+
+		setupLog.Info("Starting dashboard server", "port", port)
+		err := dashboardServer.Start()
+
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		if rc.dashboard != dashboardServer {
+			// The configuration changed while the server was waiting for its certificates
+			if err == nil {
+				_ = dashboardServer.Stop(context.Background())
+			}
+			return
+		}
+		if err != nil {
+			setupLog.Error(err, "unable to start dashboard server")
+			selfstatus.SetComponent("dashboard", selfstatus.StatusFailed, err.Error())
+			// Don't exit - the operator can still function without the dashboard
+			rc.dashboard = nil
+			return
+		}
+		rc.dashboardStarted = true
+		setupLog.Info("Dashboard server started successfully", "port", port)
+		selfstatus.SetComponent("dashboard", selfstatus.StatusOK, fmt.Sprintf("serving HTTPS on port %d", port))
+	}()
+}
+
+// stopDashboard stops the dashboard server. A server still waiting for its certificates is stopped
+// by its start goroutine once it is up.
+func (rc *runtimeComponents) stopDashboard() {
+	dashboardServer, started := rc.dashboard, rc.dashboardStarted
+	rc.dashboard, rc.dashboardStarted = nil, false
+	setupLog.Info("Stopping dashboard server", "port", rc.dashboardPort)
+	if started {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := dashboardServer.Stop(ctx); err != nil {
+			setupLog.Error(err, "unable to stop dashboard server")
+		}
+	}
+	selfstatus.SetComponent("dashboard", selfstatus.StatusNotConfigured, "stopped by the operator configuration")
+}
+
+// ensureDashboardService creates the dashboard Service if it does not exist. The ConsolePlugin
+// controller keeps it up to date afterwards.
+func (rc *runtimeComponents) ensureDashboardService(port int) {
+	serviceName := "node-check-operator-dashboard"
+	setupLog.Info("Ensuring Dashboard Service exists", "service", serviceName, "namespace", dashboardNamespace)
+
+	// Create Service directly using the clientset (works before manager starts)
+	ctx := context.Background()
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceName,
+			Namespace: dashboardNamespace,
+			Labels: map[string]string{
+				"control-plane": "controller-manager",
+			},
+			Annotations: map[string]string{
+				"service.beta.openshift.io/serving-cert-secret-name": "node-check-operator-dashboard-tls",
+			},
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{
+				"control-plane": "controller-manager",
+			},
+			Ports: []corev1.ServicePort{
+				{
+					Port:       31682,
+					TargetPort: intstr.FromInt(port),
+					Protocol:   corev1.ProtocolTCP,
+					Name:       "dashboard",
+				},
+			},
+			Type: corev1.ServiceTypeClusterIP,
+		},
+	}
+
+	// Try to get the Service first
+	_, err := rc.clientset.CoreV1().Services(dashboardNamespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Service doesn't exist, create it
+			_, err = rc.clientset.CoreV1().Services(dashboardNamespace).Create(ctx, service, metav1.CreateOptions{})
+			if err != nil {
+				setupLog.Error(err, "unable to create Dashboard Service")
+				// Don't exit - the ConsolePlugin controller will create it later
+			} else {
+				setupLog.Info("Created Dashboard Service", "service", serviceName)
+			}
+		} else {
+			setupLog.Error(err, "unable to check Dashboard Service")
+		}
+	} else {
+		setupLog.Info("Dashboard Service already exists", "service", serviceName)
+	}
+}
+
+// startNodeHealthAPI starts the optional aggregated API (health.nodecheck.openshift.io) serving
+// read-only nodehealth resources. It is registered with kube-apiserver through an APIService.
+func (rc *runtimeComponents) startNodeHealthAPI() {
+	ctx, cancel := context.WithCancel(context.Background())
+	nodeHealthServer := nodehealth.NewServer(rc.k8sClient, rc.clientset, nodehealth.DefaultPort)
+	rc.nodeHealth, rc.nodeHealthStop = nodeHealthServer, cancel
+	selfstatus.SetComponent("nodehealth-api", selfstatus.StatusStarting, "")
+	go func() {
+		setupLog.Info("Starting nodehealth aggregated API server", "port", nodehealth.DefaultPort)
+		err := nodeHealthServer.Start(ctx)
+
+		rc.mu.Lock()
+		defer rc.mu.Unlock()
+		if rc.nodeHealth != nodeHealthServer {
+			// Disabled while starting
+			if err == nil {
+				_ = nodeHealthServer.Stop(context.Background())
+			}
+			return
+		}
+		if err != nil {
+			setupLog.Error(err, "unable to start nodehealth aggregated API server")
+			selfstatus.SetComponent("nodehealth-api", selfstatus.StatusFailed, err.Error())
+			// Don't exit - the operator can still function without the aggregated API
+			rc.nodeHealth = nil
+			cancel()
+			return
+		}
+		selfstatus.SetComponent("nodehealth-api", selfstatus.StatusOK, fmt.Sprintf("serving HTTPS on port %d", nodehealth.DefaultPort))
+	}()
+}
+
+// stopNodeHealthAPI stops the nodehealth aggregated API server
+func (rc *runtimeComponents) stopNodeHealthAPI() {
+	nodeHealthServer := rc.nodeHealth
+	rc.nodeHealth = nil
+	rc.nodeHealthStop()
+	setupLog.Info("Stopping nodehealth aggregated API server")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := nodeHealthServer.Stop(ctx); err != nil {
+		setupLog.Error(err, "unable to stop nodehealth aggregated API server")
+	}
+	selfstatus.SetComponent("nodehealth-api", selfstatus.StatusNotConfigured, "stopped by the operator configuration")
+}
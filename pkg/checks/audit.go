@@ -0,0 +1,141 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// auditBacklogWarningPercent and auditBacklogCriticalPercent are the fill levels of the audit backlog
+	// queue: once backlog_limit is reached the kernel drops (or, with failure 2, panics on) new events
+	auditBacklogWarningPercent  = 80.0
+	auditBacklogCriticalPercent = 95.0
+)
+
+// auditLost remembers the lost counter of the previous audit check: the kernel counter only grows
+// since boot, so the events lost recently are the difference between two checks
+var auditLost = struct {
+	sync.Mutex
+	value int64
+	seen  bool
+}{}
+
+// auditFailureModes describes the failure flag of auditctl -s
+var auditFailureModes = map[int64]string{0: "silent", 1: "printk", 2: "panic"}
+
+// parseAuditStatus parses the "key value" lines of auditctl -s
+func parseAuditStatus(output string) map[string]int64 {
+	status := make(map[string]int64)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if value, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			status[fields[0]] = value
+		}
+	}
+	return status
+}
+
+// CheckAudit checks the kernel audit subsystem: auditd must be receiving the events while auditing is
+// enabled, the backlog queue must not be close to backlog_limit and no event must have been lost
+// since the previous check. Lost events are gaps in the audit trail required by compliance profiles.
+func (sc *SystemChecker) CheckAudit(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	ctx, cancel := withTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	output, err := runHostCommandWithCommand(ctx, "auditctl -s", result)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to read the audit status (is auditctl installed on the host?): %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	status := parseAuditStatus(string(output))
+	enabled, ok := status["enabled"]
+	if !ok {
+		result.Message = "Unable to parse the output of auditctl -s"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	pid, limit, backlog, lost := status["pid"], status["backlog_limit"], status["backlog"], status["lost"]
+	details["enabled"] = enabled
+	details["locked"] = enabled == 2
+	details["failure_mode"] = auditFailureModes[status["failure"]]
+	details["auditd_pid"] = pid
+	details["backlog"] = backlog
+	details["backlog_limit"] = limit
+	details["lost"] = lost
+	if waitTime, ok := status["backlog_wait_time"]; ok {
+		details["backlog_wait_time"] = waitTime
+	}
+
+	if enabled == 0 {
+		result.Status = "Healthy"
+		result.Message = "Kernel auditing is disabled"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	// New lost events since the previous check; on the first check the whole counter since boot
+	auditLost.Lock()
+	newLost, baseline := lost, !auditLost.seen
+	if auditLost.seen && lost >= auditLost.value {
+		newLost = lost - auditLost.value
+	}
+	auditLost.value, auditLost.seen = lost, true
+	auditLost.Unlock()
+	details["lost_since_last_check"] = newLost
+
+	var critical, warning []string
+	if pid == 0 {
+		critical = append(critical, "auditing is enabled but no audit daemon is registered (auditd not running)")
+	}
+	if limit > 0 {
+		backlogPercent := float64(backlog) / float64(limit) * 100
+		details["backlog_percent"] = backlogPercent
+		label := fmt.Sprintf("audit backlog at %.0f%% (%d/%d)", backlogPercent, backlog, limit)
+		switch {
+		case backlogPercent >= auditBacklogCriticalPercent:
+			critical = append(critical, label)
+		case backlogPercent >= auditBacklogWarningPercent:
+			warning = append(warning, label)
+		}
+	}
+	switch {
+	case newLost > 0 && baseline:
+		warning = append(warning, fmt.Sprintf("%d audit events lost since boot", newLost))
+	case newLost > 0:
+		critical = append(critical, fmt.Sprintf("%d audit events lost since the previous check (%d since boot)", newLost, lost))
+	}
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = "Audit subsystem: " + strings.Join(append(critical, warning...), ", ")
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = "Audit subsystem: " + strings.Join(warning, ", ")
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("auditd (pid %d) is receiving the events, backlog %d/%d, no event lost", pid, backlog, limit)
+		if lost > 0 {
+			result.Message = fmt.Sprintf("auditd (pid %d) is receiving the events, backlog %d/%d, no event lost since the previous check (%d since boot)", pid, backlog, limit, lost)
+		}
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,273 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultBaselineSysctls are the kernel parameters recorded when spec.baseline.sysctls is empty
+var DefaultBaselineSysctls = []string{
+	"kernel.pid_max",
+	"kernel.panic",
+	"kernel.panic_on_oops",
+	"vm.swappiness",
+	"vm.overcommit_memory",
+	"vm.max_map_count",
+	"vm.dirty_ratio",
+	"vm.dirty_background_ratio",
+	"fs.file-max",
+	"fs.inotify.max_user_watches",
+	"fs.inotify.max_user_instances",
+	"net.core.somaxconn",
+	"net.core.netdev_max_backlog",
+	"net.ipv4.ip_forward",
+	"net.ipv4.conf.all.rp_filter",
+	"net.ipv4.tcp_keepalive_time",
+	"net.bridge.bridge-nf-call-iptables",
+}
+
+// Sysctl names are interpolated into a host shell command, so they are validated again here
+// even though the CRD already restricts them
+var validSysctlName = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// baselineFilesystems are the filesystem types recorded in the baseline; pseudo and container
+// filesystems change with the workloads and are left out
+var baselineFilesystems = map[string]bool{
+	"xfs": true, "ext2": true, "ext3": true, "ext4": true, "btrfs": true,
+	"vfat": true, "zfs": true, "nfs": true, "nfs4": true, "cifs": true,
+}
+
+// baselineSkippedMountPrefixes are the mount points of pod volumes and container storage
+var baselineSkippedMountPrefixes = []string{"/var/lib/kubelet/", "/var/lib/containers/", "/run/"}
+
+// maxReportedDrifts bounds the differences listed in the result message
+const maxReportedDrifts = 5
+
+// CaptureBaseline records the current configuration of the node
+func CaptureBaseline(ctx context.Context, sysctls []string) (*v1alpha1.NodeBaseline, error) {
+	ctx, cancel := withTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	baseline := &v1alpha1.NodeBaseline{
+		CapturedAt:        metav1.Now(),
+		Sysctls:           map[string]string{},
+		Mounts:            map[string]string{},
+		NetworkInterfaces: map[string]string{},
+	}
+
+	output, err := runHostCommand(ctx, "uname -r")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the kernel version: %v", err)
+	}
+	baseline.KernelVersion = strings.TrimSpace(string(output))
+
+	if len(sysctls) == 0 {
+		sysctls = DefaultBaselineSysctls
+	}
+	names := []string{}
+	for _, name := range sysctls {
+		if validSysctlName.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		// -e ignores the parameters the kernel does not know (e.g. br_netfilter not loaded)
+		output, err = runHostCommand(ctx, "sysctl -e "+strings.Join(names, " "))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read sysctls: %v", err)
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			if name, value, ok := strings.Cut(line, " = "); ok {
+				baseline.Sysctls[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	output, err = runHostCommand(ctx, "cut -d' ' -f1 /proc/modules")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list kernel modules: %v", err)
+	}
+	baseline.KernelModules = strings.Fields(string(output))
+	sort.Strings(baseline.KernelModules)
+
+	output, err = runHostCommand(ctx, "cat /proc/mounts")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list mounts: %v", err)
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !baselineFilesystems[fields[2]] || skippedBaselineMount(fields[1]) {
+			continue
+		}
+		baseline.Mounts[fields[1]] = fmt.Sprintf("%s %s", fields[0], fields[2])
+	}
+
+	// Physical interfaces have a device link and bonds a bonding directory; virtual interfaces
+	// (veth, bridges, tunnels) come and go with the pods and are left out
+	output, err = runHostCommand(ctx, "grep -H . /sys/class/net/*/mtu /sys/class/net/*/address; ls -d /sys/class/net/*/device /sys/class/net/*/bonding 2>/dev/null; true")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list network interfaces: %v", err)
+	}
+	baseline.NetworkInterfaces = parseBaselineInterfaces(string(output))
+
+	return baseline, nil
+}
+
+// skippedBaselineMount reports whether a mount point belongs to pod volumes or container storage
+func skippedBaselineMount(mountPoint string) bool {
+	for _, prefix := range baselineSkippedMountPrefixes {
+		if strings.HasPrefix(mountPoint, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBaselineInterfaces builds the "mtu=<mtu> address=<mac>" configuration of the physical and
+// bond interfaces from the output of the grep/ls command of CaptureBaseline
+func parseBaselineInterfaces(output string) map[string]string {
+	physical := map[string]bool{}
+	attributes := map[string]map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if file, value, ok := strings.Cut(line, ":"); ok {
+			name := path.Base(path.Dir(file))
+			if attributes[name] == nil {
+				attributes[name] = map[string]string{}
+			}
+			attributes[name][path.Base(file)] = value
+		} else if base := path.Base(line); base == "device" || base == "bonding" {
+			physical[path.Base(path.Dir(line))] = true
+		}
+	}
+
+	interfaces := map[string]string{}
+	for name := range physical {
+		interfaces[name] = fmt.Sprintf("mtu=%s address=%s", attributes[name]["mtu"], attributes[name]["address"])
+	}
+	return interfaces
+}
+
+// compareBaseline returns the differences between the baseline and the current configuration.
+// Sysctls are compared only when recorded in both, so changing spec.baseline.sysctls does not
+// report drift before the baseline is captured again.
+func compareBaseline(baseline, current *v1alpha1.NodeBaseline) []string {
+	drifts := []string{}
+	if baseline.KernelVersion != current.KernelVersion {
+		drifts = append(drifts, fmt.Sprintf("kernel %s -> %s", baseline.KernelVersion, current.KernelVersion))
+	}
+	for _, name := range sortedKeys(baseline.Sysctls) {
+		if value, ok := current.Sysctls[name]; ok && value != baseline.Sysctls[name] {
+			drifts = append(drifts, fmt.Sprintf("sysctl %s %s -> %s", name, baseline.Sysctls[name], value))
+		}
+	}
+
+	loaded := make(map[string]bool, len(current.KernelModules))
+	for _, module := range current.KernelModules {
+		loaded[module] = true
+	}
+	recorded := make(map[string]bool, len(baseline.KernelModules))
+	for _, module := range baseline.KernelModules {
+		recorded[module] = true
+		if !loaded[module] {
+			drifts = append(drifts, fmt.Sprintf("module %s unloaded", module))
+		}
+	}
+	for _, module := range current.KernelModules {
+		if !recorded[module] {
+			drifts = append(drifts, fmt.Sprintf("module %s loaded", module))
+		}
+	}
+
+	drifts = append(drifts, compareBaselineMap("mount", baseline.Mounts, current.Mounts)...)
+	drifts = append(drifts, compareBaselineMap("interface", baseline.NetworkInterfaces, current.NetworkInterfaces)...)
+	return drifts
+}
+
+// compareBaselineMap reports the added, removed and changed entries of a baseline map
+func compareBaselineMap(kind string, baseline, current map[string]string) []string {
+	drifts := []string{}
+	for _, key := range sortedKeys(baseline) {
+		value, ok := current[key]
+		switch {
+		case !ok:
+			drifts = append(drifts, fmt.Sprintf("%s %s removed", kind, key))
+		case value != baseline[key]:
+			drifts = append(drifts, fmt.Sprintf("%s %s %s -> %s", kind, key, baseline[key], value))
+		}
+	}
+	for _, key := range sortedKeys(current) {
+		if _, ok := baseline[key]; !ok {
+			drifts = append(drifts, fmt.Sprintf("%s %s added (%s)", kind, key, current[key]))
+		}
+	}
+	return drifts
+}
+
+// sortedKeys returns the keys of a map in order, so drifts are reported deterministically
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CheckBaselineDrift compares the node configuration against its baseline. Without a baseline, or
+// when spec.baseline.recapture changed, the current configuration becomes the baseline: it is
+// returned so the executor records it in status.baseline.
+func (sc *SystemChecker) CheckBaselineDrift(ctx context.Context, spec *v1alpha1.BaselineSpec, baseline *v1alpha1.NodeBaseline) (*v1alpha1.CheckResult, *v1alpha1.NodeBaseline) {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+		Command:   "uname -r; sysctl -e <sysctls>; cat /proc/modules; cat /proc/mounts; /sys/class/net",
+	}
+
+	current, err := CaptureBaseline(ctx, spec.Sysctls)
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Unable to read the node configuration: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result, nil
+	}
+
+	if baseline == nil || baseline.Recapture != spec.Recapture {
+		current.Recapture = spec.Recapture
+		details["captured_at"] = current.CapturedAt.Format(time.RFC3339)
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Baseline captured: kernel %s, %d sysctls, %d modules, %d mounts, %d interfaces",
+			current.KernelVersion, len(current.Sysctls), len(current.KernelModules), len(current.Mounts), len(current.NetworkInterfaces))
+		result.Details = mapToRawExtension(details)
+		return result, current
+	}
+
+	details["baseline_captured_at"] = baseline.CapturedAt.Format(time.RFC3339)
+	drifts := compareBaseline(baseline, current)
+	if len(drifts) == 0 {
+		result.Status = "Healthy"
+		result.Message = "Node configuration matches the baseline"
+		result.Details = mapToRawExtension(details)
+		return result, nil
+	}
+
+	details["drifts"] = drifts
+	details["drift_count"] = len(drifts)
+	reported := drifts
+	if len(reported) > maxReportedDrifts {
+		reported = append(reported[:maxReportedDrifts:maxReportedDrifts], fmt.Sprintf("and %d more", len(drifts)-maxReportedDrifts))
+	}
+	result.Status = "Warning"
+	result.Message = fmt.Sprintf("Node configuration drifted from the baseline: %s", strings.Join(reported, ", "))
+	result.Details = mapToRawExtension(details)
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Node capabilities that checks can require through spec.checkDependencies
+const (
+	CapabilityLVM  = "lvm"
+	CapabilityIPMI = "ipmi"
+)
+
+// capabilityProbes are the host commands detecting each capability: the capability is present when the
+// command prints something. The commands never fail on nodes without the capability.
+var capabilityProbes = map[string]struct {
+	command string
+	missing string
+}{
+	CapabilityLVM: {
+		command: "command -v vgs >/dev/null 2>&1 && vgs --noheadings -o vg_name 2>/dev/null; true",
+		missing: "no LVM volume group detected",
+	},
+	CapabilityIPMI: {
+		command: "ls /dev/ipmi0 /dev/ipmi/0 /dev/ipmidev/0 2>/dev/null; true",
+		missing: "no BMC detected (no /dev/ipmi* device)",
+	},
+}
+
+// IsKnownCapability reports whether name is a capability ProbeCapability can detect
+func IsKnownCapability(name string) bool {
+	_, ok := capabilityProbes[name]
+	return ok
+}
+
+// ProbeCapability detects whether the node has a capability. When it is missing, the returned reason
+// explains why; an error means the probe itself could not run and the result is unknown.
+func ProbeCapability(ctx context.Context, name string) (bool, string, error) {
+	probe, ok := capabilityProbes[name]
+	if !ok {
+		return false, "", fmt.Errorf("unknown capability %q", name)
+	}
+	output, err := runHostCommand(ctx, probe.command)
+	if err != nil {
+		return false, "", err
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return false, probe.missing, nil
+	}
+	return true, "", nil
+}
@@ -0,0 +1,238 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Built-in windows of the certificate_expiry check, in days before expiry
+const (
+	certificateWarningDays  = 30
+	certificateCriticalDays = 7
+)
+
+// defaultCertificatePaths are the certificates of the kubelet (client and serving, rotated or
+// self-signed), the kubeadm PKI with its etcd certificates and the etcd certificates of the OpenShift
+// control plane. The kubelet directory itself is not scanned: it keeps the rotated-out certificates,
+// which expire without harm.
+var defaultCertificatePaths = []string{
+	"/var/lib/kubelet/pki/kubelet-client-current.pem",
+	"/var/lib/kubelet/pki/kubelet-server-current.pem",
+	"/var/lib/kubelet/pki/kubelet.crt",
+	"/etc/kubernetes/pki",
+	"/etc/kubernetes/static-pod-resources/etcd-certs/secrets/etcd-all-certs",
+}
+
+// Paths are interpolated into the host shell command, so they are validated again here
+// even though the CRD already requires them absolute
+var validCertificatePath = regexp.MustCompile(`^/[a-zA-Z0-9@._+/-]*$`)
+
+// certificateExpiryCommand prints the current time of the node (seconds since the epoch), then a line per
+// *.crt and *.pem file of the paths: "<file>|notBefore=...|notAfter=...|subject=...|", with nothing after
+// the file when it holds no certificate (e.g. a key). Only the first certificate of a bundle is read.
+func certificateExpiryCommand(paths []string) string {
+	return "date -u +%s; for p in " + strings.Join(paths, " ") + `; do if [ -d "$p" ]; then ` +
+		`find -L "$p" -maxdepth 2 -type f \( -name '*.crt' -o -name '*.pem' \); elif [ -f "$p" ]; then echo "$p"; fi; ` +
+		`done 2>/dev/null | sort -u | while read -r f; do ` +
+		`echo "$f|$(openssl x509 -noout -startdate -enddate -subject -in "$f" 2>/dev/null | tr '\n' '|')"; done; true`
+}
+
+// hostCertificate is a certificate file of certificateExpiryCommand
+type hostCertificate struct {
+	path      string
+	subject   string
+	notBefore time.Time
+	notAfter  time.Time
+}
+
+// parseCertificateDates parses the output of certificateExpiryCommand into the time of the node and the
+// certificates, with the files holding no certificate apart
+func parseCertificateDates(output string) (time.Time, []hostCertificate, []string, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	seconds, err := strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64)
+	if err != nil {
+		return time.Time{}, nil, nil, fmt.Errorf("unexpected time of the node %q", lines[0])
+	}
+	var certificates []hostCertificate
+	skipped := []string{}
+	for _, line := range lines[1:] {
+		fields := strings.Split(strings.TrimSpace(line), "|")
+		if fields[0] == "" {
+			continue
+		}
+		certificate := hostCertificate{path: fields[0]}
+		for _, field := range fields[1:] {
+			key, value, _ := strings.Cut(field, "=")
+			switch strings.TrimSpace(key) {
+			case "notBefore":
+				certificate.notBefore, _ = time.Parse("Jan _2 15:04:05 2006 MST", strings.TrimSpace(value))
+			case "notAfter":
+				certificate.notAfter, _ = time.Parse("Jan _2 15:04:05 2006 MST", strings.TrimSpace(value))
+			case "subject":
+				certificate.subject = strings.TrimSpace(value)
+			}
+		}
+		if certificate.notAfter.IsZero() {
+			skipped = append(skipped, certificate.path)
+			continue
+		}
+		certificates = append(certificates, certificate)
+	}
+	return time.Unix(seconds, 0).UTC(), certificates, skipped, nil
+}
+
+// certificateWindow returns the window before expiry of a certificate: the configured days, shortened
+// to a fraction of the lifetime of short-lived certificates, which are rotated close to their expiry
+func certificateWindow(days int, lifetime time.Duration, fraction float64) time.Duration {
+	window := time.Duration(days) * 24 * time.Hour
+	if lifetime > 0 {
+		if short := time.Duration(float64(lifetime) * fraction); short < window {
+			return short
+		}
+	}
+	return window
+}
+
+// SetCertificateExpiry applies the paths and windows of spec.certificateExpiry
+func (sc *SystemChecker) SetCertificateExpiry(config *v1alpha1.CertificateExpiryConfig) {
+	sc.certificates = config
+}
+
+// CheckCertificateExpiry reports the days until the expiry of the certificate files of the node: the
+// kubelet client and serving certificates, the etcd and control plane certificates of the masters and
+// the paths of spec.certificateExpiry. A certificate is a Warning within warningDays (30) of its expiry
+// and Critical within criticalDays (7) or once expired. The kubelet rotates its certificates after 70 to
+// 90% of their lifetime, so for short-lived certificates the windows shrink to the last 10% and 5% of
+// the lifetime, i.e. the rotation is overdue. The time of the node is the reference, its drift is
+// time_drift's concern.
+func (sc *SystemChecker) CheckCertificateExpiry(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	warningDays, criticalDays := certificateWarningDays, certificateCriticalDays
+	paths := append([]string(nil), defaultCertificatePaths...)
+	invalid := []string{}
+	if sc.certificates != nil {
+		if sc.certificates.WarningDays > 0 {
+			warningDays = sc.certificates.WarningDays
+		}
+		if sc.certificates.CriticalDays > 0 {
+			criticalDays = sc.certificates.CriticalDays
+		}
+		for _, path := range sc.certificates.Paths {
+			if validCertificatePath.MatchString(path) {
+				paths = append(paths, path)
+			} else {
+				invalid = append(invalid, path)
+			}
+		}
+	}
+	details["warning_days"] = warningDays
+	details["critical_days"] = criticalDays
+	details["paths"] = paths
+	if len(invalid) > 0 {
+		details["invalid_paths"] = invalid
+	}
+
+	ctx, cancel := withTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	command := certificateExpiryCommand(paths)
+	result.Command = command
+	output, err := runHostCommand(ctx, command)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to read the certificates: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	now, certificates, skipped, err := parseCertificateDates(string(output))
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to read the certificates: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	if len(skipped) > 0 {
+		details["not_certificates"] = skipped
+	}
+	if len(certificates) == 0 {
+		result.Message = fmt.Sprintf("No certificate found in %s", strings.Join(paths, ", "))
+		if len(skipped) > 0 {
+			result.Message = fmt.Sprintf("No certificate could be read from %d files, is openssl installed on the node?", len(skipped))
+		}
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	// Soonest expiry first
+	sort.Slice(certificates, func(i, j int) bool {
+		if !certificates[i].notAfter.Equal(certificates[j].notAfter) {
+			return certificates[i].notAfter.Before(certificates[j].notAfter)
+		}
+		return certificates[i].path < certificates[j].path
+	})
+	var critical, warning []string
+	certificateDetails := []map[string]interface{}{}
+	for _, certificate := range certificates {
+		remaining := certificate.notAfter.Sub(now)
+		days := int(remaining.Hours() / 24)
+		lifetime := time.Duration(0)
+		if !certificate.notBefore.IsZero() {
+			lifetime = certificate.notAfter.Sub(certificate.notBefore)
+		}
+		status := "Healthy"
+		switch {
+		case remaining < certificateWindow(criticalDays, lifetime, 0.05):
+			status = "Critical"
+		case remaining < certificateWindow(warningDays, lifetime, 0.10):
+			status = "Warning"
+		}
+		expiry := fmt.Sprintf("%s (%d days)", certificate.path, days)
+		if remaining < 0 {
+			expiry = fmt.Sprintf("%s (expired on %s)", certificate.path, certificate.notAfter.Format("2006-01-02"))
+		}
+		switch status {
+		case "Critical":
+			critical = append(critical, expiry)
+		case "Warning":
+			warning = append(warning, expiry)
+		}
+		entry := map[string]interface{}{
+			"path":           certificate.path,
+			"subject":        certificate.subject,
+			"not_after":      certificate.notAfter.Format(time.RFC3339),
+			"days_to_expiry": days,
+			"status":         status,
+		}
+		if lifetime > 0 {
+			entry["lifetime_days"] = int(lifetime.Hours() / 24)
+		}
+		certificateDetails = append(certificateDetails, entry)
+	}
+	details["certificates"] = certificateDetails
+	details["certificate_count"] = len(certificates)
+	details["min_days_to_expiry"] = certificateDetails[0]["days_to_expiry"]
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("%d certificates expired or expire within %d days: %s", len(critical), criticalDays, strings.Join(critical, ", "))
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("%d certificates expire within %d days: %s", len(warning), warningDays, strings.Join(warning, ", "))
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("%d certificates valid, the next to expire is %s in %d days", len(certificates), certificates[0].path, certificateDetails[0]["days_to_expiry"])
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
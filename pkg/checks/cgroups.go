@@ -0,0 +1,119 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the cgroup_driver check. The kubelet configuration is /etc/kubernetes/kubelet.conf
+// on OpenShift and /var/lib/kubelet/config.yaml with kubeadm. CRI-O reads its drop-ins after crio.conf,
+// in lexical order, so the last cgroup_manager wins.
+const (
+	cgroupFSTypeCommand     = "stat -fc %T /sys/fs/cgroup/"
+	kubeletConfigCommand    = "cat /etc/kubernetes/kubelet.conf 2>/dev/null || cat /var/lib/kubelet/config.yaml"
+	crioConfigCommand       = "cat /etc/crio/crio.conf /etc/crio/crio.conf.d/* 2>/dev/null; true"
+	containerdConfigCommand = "cat /etc/containerd/config.toml"
+)
+
+var (
+	// kubeletCgroupDriverPattern matches cgroupDriver in the YAML or JSON kubelet configuration
+	kubeletCgroupDriverPattern = regexp.MustCompile(`(?m)^\s*"?cgroupDriver"?\s*:\s*"?([a-z]+)"?`)
+	// crioCgroupManagerPattern matches cgroup_manager in the TOML CRI-O configuration, not commented out
+	crioCgroupManagerPattern = regexp.MustCompile(`(?m)^\s*cgroup_manager\s*=\s*"([a-z]+)"`)
+	// containerdSystemdCgroupPattern matches SystemdCgroup of the runc options of containerd
+	containerdSystemdCgroupPattern = regexp.MustCompile(`(?m)^\s*SystemdCgroup\s*=\s*(true|false)`)
+)
+
+// lastSubmatch returns the first group of the last match of a pattern, or ""
+func lastSubmatch(pattern *regexp.Regexp, text string) string {
+	matches := pattern.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	return matches[len(matches)-1][1]
+}
+
+// CheckCgroupDriver detects the cgroup version of the host (v2 when /sys/fs/cgroup is a cgroup2fs mount)
+// and compares the cgroup driver of the kubelet with the one of the container runtime (CRI-O, or
+// containerd). Different drivers make two managers account the same pods in different hierarchies: pods
+// fail to start with cgroup errors and the kubelet misreports their resources, so a mismatch is
+// Critical. The cgroupfs driver on a cgroup v2 host is Warning, systemd being the supported driver there.
+func (sc *SystemChecker) CheckCgroupDriver(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = strings.Join([]string{cgroupFSTypeCommand, kubeletConfigCommand, crioConfigCommand, containerdConfigCommand}, "; ")
+
+	output, err := runHostCommand(ctx, cgroupFSTypeCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to detect the cgroup version: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	fsType := strings.TrimSpace(string(output))
+	cgroupVersion := "v1"
+	if fsType == "cgroup2fs" {
+		cgroupVersion = "v2"
+	}
+	details["cgroup_version"] = cgroupVersion
+	details["cgroup_fs_type"] = fsType
+
+	output, err = runHostCommand(ctx, kubeletConfigCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("cgroup %s; unable to read the kubelet configuration: %v", cgroupVersion, err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	kubeletDriver := lastSubmatch(kubeletCgroupDriverPattern, string(output))
+	if kubeletDriver == "" {
+		// Default of the KubeletConfiguration
+		kubeletDriver = "cgroupfs"
+		details["kubelet_driver_default"] = true
+	}
+	details["kubelet_cgroup_driver"] = kubeletDriver
+
+	runtime, runtimeDriver := "", ""
+	if output, err := runHostCommand(ctx, crioConfigCommand); err == nil && strings.TrimSpace(string(output)) != "" {
+		runtime = "cri-o"
+		runtimeDriver = lastSubmatch(crioCgroupManagerPattern, string(output))
+		if runtimeDriver == "" {
+			// Default of CRI-O
+			runtimeDriver = "systemd"
+		}
+	} else if output, err := runHostCommand(ctx, containerdConfigCommand); err == nil {
+		runtime = "containerd"
+		runtimeDriver = "cgroupfs"
+		if lastSubmatch(containerdSystemdCgroupPattern, string(output)) == "true" {
+			runtimeDriver = "systemd"
+		}
+	}
+	if runtime != "" {
+		details["container_runtime"] = runtime
+		details["runtime_cgroup_driver"] = runtimeDriver
+	}
+
+	switch {
+	case runtime != "" && kubeletDriver != runtimeDriver:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("cgroup driver mismatch: the kubelet uses %s and %s uses %s (cgroup %s), pods may fail to start",
+			kubeletDriver, runtime, runtimeDriver, cgroupVersion)
+	case cgroupVersion == "v2" && kubeletDriver == "cgroupfs":
+		result.Status = "Warning"
+		result.Message = "The kubelet uses the cgroupfs driver on a cgroup v2 host, use the systemd driver"
+	case runtime == "":
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("cgroup %s, kubelet cgroup driver %s; no CRI-O or containerd configuration found to compare", cgroupVersion, kubeletDriver)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("cgroup %s, the kubelet and %s both use the %s driver", cgroupVersion, runtime, kubeletDriver)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
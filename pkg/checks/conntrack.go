@@ -0,0 +1,71 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckConntrack compares the entries of the netfilter connection tracking table with its size. When the
+// table is full the kernel drops the packets of new connections ("nf_conntrack: table full, dropping
+// packet"), with nothing but a line in the kernel log. The usage is compared with the thresholds of the
+// conntrack check (75/90% by default).
+func (sc *SystemChecker) CheckConntrack(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = "cat /proc/sys/net/netfilter/nf_conntrack_count /proc/sys/net/netfilter/nf_conntrack_max"
+
+	countData, err := readProcFile(ctx, "/proc/sys/net/netfilter/nf_conntrack_count")
+	if err != nil {
+		result.Message = fmt.Sprintf("Connection tracking not available (nf_conntrack module not loaded): %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	maxData, err := readProcFile(ctx, "/proc/sys/net/netfilter/nf_conntrack_max")
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to read nf_conntrack_max: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	count, err := strconv.ParseInt(strings.TrimSpace(string(countData)), 10, 64)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to parse nf_conntrack_count: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	max, err := strconv.ParseInt(strings.TrimSpace(string(maxData)), 10, 64)
+	if err != nil || max <= 0 {
+		result.Message = fmt.Sprintf("Invalid nf_conntrack_max %q", strings.TrimSpace(string(maxData)))
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	warningPercent, criticalPercent := usageThresholds(sc.thresholds, "conntrack", 75, 90)
+	usage := float64(count) * 100 / float64(max)
+	details["count"] = count
+	details["max"] = max
+	details["usage_percent"] = usage
+	details["warning_threshold"] = warningPercent
+	details["critical_threshold"] = criticalPercent
+
+	switch {
+	case usage >= float64(criticalPercent):
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Conntrack table %.1f%% full (%d/%d entries), new connections are about to be dropped", usage, count, max)
+	case usage >= float64(warningPercent):
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Conntrack table %.1f%% full (%d/%d entries)", usage, count, max)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Conntrack table %.1f%% full (%d/%d entries)", usage, count, max)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
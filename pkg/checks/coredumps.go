@@ -0,0 +1,189 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// coreDumpWindow is the window in which the core dumps of a process are counted
+	coreDumpWindow = 24 * time.Hour
+	// coreDumpRepeatWarning and coreDumpRepeatCritical are the core dumps of a process within coreDumpWindow
+	coreDumpRepeatWarning  = 3
+	coreDumpRepeatCritical = 10
+	// coreDumpSizeWarning and coreDumpSizeCritical are the bytes taken by the core dumps on disk
+	coreDumpSizeWarning  = 5 * 1024 * 1024 * 1024
+	coreDumpSizeCritical = 20 * 1024 * 1024 * 1024
+	// systemdCoredumpDir is where systemd-coredump stores the core dumps
+	systemdCoredumpDir = "/var/lib/systemd/coredump"
+)
+
+// coreDumpSuffix matches the pid, timestamp and compression suffixes of a core dump file name
+var coreDumpSuffix = regexp.MustCompile(`([._-][0-9]+|\.(zst|xz|lz4|gz))+$`)
+
+// coreDumpProcess returns the process of a core dump file: systemd-coredump names them
+// core.<comm>.<uid>.<boot id>.<pid>.<timestamp>[.zst], other patterns usually end with the pid.
+func coreDumpProcess(name string) string {
+	if parts := strings.Split(name, "."); len(parts) >= 6 && parts[0] == "core" {
+		return parts[1]
+	}
+	if process := coreDumpSuffix.ReplaceAllString(name, ""); process != "" {
+		return process
+	}
+	return name
+}
+
+// CheckCoreDumps flags the processes dumping core repeatedly and the core dumps filling the disk. The
+// directory is read from kernel.core_pattern: /var/lib/systemd/coredump when the dumps are piped to
+// systemd-coredump, the directory of the pattern when it is an absolute path.
+func (sc *SystemChecker) CheckCoreDumps(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	output, err := runHostCommandWithCommand(ctx, "cat /proc/sys/kernel/core_pattern", result)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to read kernel.core_pattern: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	pattern := strings.TrimSpace(string(output))
+	details["core_pattern"] = pattern
+
+	var dir string
+	switch {
+	case strings.HasPrefix(pattern, "|") && strings.Contains(pattern, "systemd-coredump"):
+		dir = systemdCoredumpDir
+	case strings.HasPrefix(pattern, "|"):
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Core dumps are piped to %s, not stored in a known directory", strings.Fields(strings.TrimPrefix(pattern, "|"))[0])
+		result.Details = mapToRawExtension(details)
+		return result
+	case strings.HasPrefix(pattern, "/"):
+		dir = path.Dir(pattern)
+	default:
+		// A relative pattern (e.g. the kernel default "core") dumps in the working directory of each process
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Core dumps are written to the working directory of the processes (core_pattern %q)", pattern)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["directory"] = dir
+
+	listCommand := fmt.Sprintf("find '%s' -maxdepth 1 -type f -printf '%%T@ %%s %%f\\n' 2>/dev/null || true", dir)
+	result.Command += "; " + listCommand
+	output, err = runHostCommand(ctx, listCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to list the core dumps in %s: %v", dir, err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	type processCores struct {
+		recent   int
+		size     int64
+		lastDump time.Time
+	}
+	processes := make(map[string]*processCores)
+	var totalSize int64
+	totalCores, recentCores := 0, 0
+	cutoff := time.Now().Add(-coreDumpWindow)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		modified, err1 := strconv.ParseFloat(fields[0], 64)
+		size, err2 := strconv.ParseInt(fields[1], 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		dumped := time.Unix(int64(modified), 0)
+		process := coreDumpProcess(fields[2])
+		if processes[process] == nil {
+			processes[process] = &processCores{}
+		}
+		cores := processes[process]
+		cores.size += size
+		if dumped.After(cores.lastDump) {
+			cores.lastDump = dumped
+		}
+		if dumped.After(cutoff) {
+			cores.recent++
+			recentCores++
+		}
+		totalSize += size
+		totalCores++
+	}
+
+	names := make([]string, 0, len(processes))
+	for name := range processes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if processes[names[i]].recent != processes[names[j]].recent {
+			return processes[names[i]].recent > processes[names[j]].recent
+		}
+		return processes[names[i]].size > processes[names[j]].size
+	})
+
+	var top []map[string]interface{}
+	var critical, warning []string
+	for i, name := range names {
+		cores := processes[name]
+		if i < 10 {
+			top = append(top, map[string]interface{}{
+				"process":    name,
+				"cores_24h":  cores.recent,
+				"size_bytes": cores.size,
+				"last_dump":  cores.lastDump.UTC().Format(time.RFC3339),
+			})
+		}
+		label := fmt.Sprintf("%s (%d core dumps in 24h)", name, cores.recent)
+		switch {
+		case cores.recent >= coreDumpRepeatCritical:
+			critical = append(critical, label)
+		case cores.recent >= coreDumpRepeatWarning:
+			warning = append(warning, label)
+		}
+	}
+	details["total_cores"] = totalCores
+	details["cores_24h"] = recentCores
+	details["total_size_bytes"] = totalSize
+	details["processes"] = top
+
+	sizeGiB := float64(totalSize) / (1024 * 1024 * 1024)
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Processes dumping core repeatedly: %s", strings.Join(critical, ", "))
+	case totalSize >= coreDumpSizeCritical:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Core dumps take %.1f GiB in %s", sizeGiB, dir)
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Processes dumping core repeatedly: %s", strings.Join(warning, ", "))
+	case totalSize >= coreDumpSizeWarning:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Core dumps take %.1f GiB in %s", sizeGiB, dir)
+	case recentCores > 0:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("%d core dumps in the last 24h, no process dumping core repeatedly (%.1f GiB in %s)", recentCores, sizeGiB, dir)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("No core dump in the last 24h (%d stored, %.1f GiB in %s)", totalCores, sizeGiB, dir)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,7 +15,13 @@ import (
 
 // DiskChecker handles disk monitoring
 type DiskChecker struct {
-	nodeName string
+	nodeName       string
+	mountPoints    *v1alpha1.FilterPatterns
+	devices        *v1alpha1.FilterPatterns
+	thresholds     map[string]v1alpha1.CheckThresholds
+	sampling       *v1alpha1.SamplingConfig
+	smoothing      map[string]v1alpha1.SmoothingConfig
+	smoothingScope string
 }
 
 // NewDiskChecker creates a new disk checker
@@ -24,39 +31,68 @@ func NewDiskChecker(nodeName string) *DiskChecker {
 	}
 }
 
+// SetFilters applies the mount point and device filters from spec.filters
+func (dc *DiskChecker) SetFilters(filters *v1alpha1.CheckFilters) {
+	if filters == nil {
+		return
+	}
+	dc.mountPoints = filters.MountPoints
+	dc.devices = filters.Devices
+}
+
+// checkDevice reports whether a block device (e.g. "sda" or "/dev/sda") must be checked
+func (dc *DiskChecker) checkDevice(device string, skippedByDefault bool) bool {
+	return filterAllows(dc.devices, skippedByDefault, device, strings.TrimPrefix(device, "/dev/"))
+}
+
+// checkMount reports whether a filesystem mounted at mountPoint must be checked
+func (dc *DiskChecker) checkMount(filesystem, mountPoint string, skippedByDefault bool) bool {
+	if dc.devices != nil && matchesAnyPattern(dc.devices.Exclude, []string{filesystem, strings.TrimPrefix(filesystem, "/dev/")}) {
+		return false
+	}
+	return filterAllows(dc.mountPoints, skippedByDefault, mountPoint)
+}
+
 // CheckDiskSpace performs disk space monitoring
 func (dc *DiskChecker) CheckDiskSpace(ctx context.Context) *v1alpha1.CheckResult {
-	details := make(map[string]interface{})
+	details := v1alpha1.DiskSpaceDetails{}
 	result := &v1alpha1.CheckResult{
 		Timestamp: metav1.Now(),
 		Status:    "Unknown",
 	}
+	warningPercent, criticalPercent := usageThresholds(dc.thresholds, "disk_space", 85, 95)
 
 	command := "df -hPT"
 	result.Command = command
 
 	output, err := runHostCommand(ctx, command)
 	if err != nil {
-		cmd := exec.CommandContext(ctx, "df", "-h")
+		cmd := exec.CommandContext(ctx, "df", "-hP")
 		output, err = cmd.Output()
 		if err != nil {
 			result.Status = "Critical"
 			result.Message = fmt.Sprintf("Failed to execute df: %v", err)
-			result.Details = mapToRawExtension(details)
+			result.Details = typedToRawExtension(details)
 			return result
 		}
-		details["check_source"] = "container"
+		details.CheckSource = "container"
 		result.Command = command
 	} else {
-		details["check_source"] = "host"
+		details.CheckSource = "host"
 		result.Command = command
 	}
 
 	dfOutput := strings.TrimSpace(string(output))
-	details["df_output"] = dfOutput
+	details.DFOutput = dfOutput
+	details.ToolVersion = toolVersion(ctx, "df")
 
-	// Parse disk usage
-	lines := strings.Split(dfOutput, "\n")
+	// Parse disk usage, by the column names: the container fallback has no Type column
+	filesystems, err := parseDF(dfOutput)
+	if err != nil {
+		notSupported(ctx, result, err)
+		result.Details = typedToRawExtension(details)
+		return result
+	}
 	diskUsage := make(map[string]map[string]string)
 	criticalDisks := []string{}
 	warningDisks := []string{}
@@ -81,41 +117,21 @@ func (dc *DiskChecker) CheckDiskSpace(ctx context.Context) *v1alpha1.CheckResult
 		"composefs":  true,
 	}
 
-	for i, line := range lines {
-		if i == 0 {
-			continue // Skip header
-		}
-		
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// Parse df output: filesystem type size used available use% mounted_on
-		// Use a more robust parsing that handles variable whitespace
-		fields := strings.Fields(line)
-		if len(fields) < 7 {
-			continue
-		}
-
-		filesystem := fields[0]
-		fsType := fields[1]
-		size := fields[2]
-		used := fields[3]
-		available := fields[4]
-		usePercent := fields[5]
-		mountedOn := strings.Join(fields[6:], " ")
-
-		// Skip known pseudo or read-only filesystems
-		if skipFSTypes[fsType] {
-			continue
-		}
-		if strings.Contains(mountedOn, "/tmp") || strings.HasPrefix(mountedOn, "/run/") {
-			continue
-		}
-
-		// Skip tiny read-only filesystems that always show 100%
-		if fsType == "composefs" || (available == "0" && usePercent == "100%") {
+	for _, fs := range filesystems {
+		filesystem := fs["filesystem"]
+		fsType := fs["fs_type"]
+		size := fs["size"]
+		used := fs["used"]
+		available := fs["available"]
+		usePercent := fs["use_percent"]
+		mountedOn := fs["mounted_on"]
+
+		// Skip known pseudo or read-only filesystems, tmp/run mounts and tiny read-only
+		// filesystems that always show 100%, unless spec.filters says otherwise
+		skipped := skipFSTypes[fsType] ||
+			strings.Contains(mountedOn, "/tmp") || strings.HasPrefix(mountedOn, "/run/") ||
+			fsType == "composefs" || (available == "0" && usePercent == "100%")
+		if !dc.checkMount(filesystem, mountedOn, skipped) {
 			continue
 		}
 
@@ -132,9 +148,9 @@ func (dc *DiskChecker) CheckDiskSpace(ctx context.Context) *v1alpha1.CheckResult
 		// Parse usage percentage
 		usePercentStr := strings.Trim(usePercent, "%")
 		if usePercentInt, err := strconv.Atoi(usePercentStr); err == nil {
-			if usePercentInt >= 95 {
+			if usePercentInt >= criticalPercent {
 				criticalDisks = append(criticalDisks, fmt.Sprintf("%s: %d%%", mountedOn, usePercentInt))
-			} else if usePercentInt >= 85 {
+			} else if usePercentInt >= warningPercent {
 				warningDisks = append(warningDisks, fmt.Sprintf("%s: %d%%", mountedOn, usePercentInt))
 			}
 		}
@@ -144,27 +160,31 @@ func (dc *DiskChecker) CheckDiskSpace(ctx context.Context) *v1alpha1.CheckResult
 	log := ctrl.Log.WithName("DiskChecker")
 	log.V(1).Info("Parsed disk usage", "diskUsageCount", len(diskUsage), "criticalDisks", len(criticalDisks), "warningDisks", len(warningDisks))
 	
-	// Convert map[string]map[string]string to []interface{} for better serialization
-	// This avoids potential issues with nested maps in JSON serialization
-	diskUsageList := make([]map[string]interface{}, 0, len(diskUsage))
+	// List the filesystems in a stable order, the map is kept for older clients
+	diskUsageList := make([]v1alpha1.FilesystemUsage, 0, len(diskUsage))
 	for filesystem, diskInfo := range diskUsage {
-		entry := map[string]interface{}{
-			"filesystem": filesystem,
-			"fs_type":     diskInfo["fs_type"],
-			"size":        diskInfo["size"],
-			"used":        diskInfo["used"],
-			"available":   diskInfo["available"],
-			"use_percent": diskInfo["use_percent"],
-			"mounted_on":  diskInfo["mounted_on"],
-		}
-		diskUsageList = append(diskUsageList, entry)
-	}
-	
-	// Store both formats for compatibility
-	details["disk_usage"] = diskUsageList  // List format (more reliable for JSON)
-	details["disk_usage_map"] = diskUsage  // Original map format
-	details["critical_disks"] = criticalDisks
-	details["warning_disks"] = warningDisks
+		usage := v1alpha1.FilesystemUsage{
+			Filesystem: filesystem,
+			FSType:     diskInfo["fs_type"],
+			Size:       diskInfo["size"],
+			Used:       diskInfo["used"],
+			Available:  diskInfo["available"],
+			UsePercent: diskInfo["use_percent"],
+			MountedOn:  diskInfo["mounted_on"],
+		}
+		// df -h rounds the sizes, the bytes are as precise as its output
+		usage.SizeBytes, _ = v1alpha1.ParseSize(usage.Size)
+		usage.UsedBytes, _ = v1alpha1.ParseSize(usage.Used)
+		usage.AvailableBytes, _ = v1alpha1.ParseSize(usage.Available)
+		usage.UsedPercent, _ = strconv.ParseFloat(strings.TrimSuffix(usage.UsePercent, "%"), 64)
+		diskUsageList = append(diskUsageList, usage)
+	}
+	sort.Slice(diskUsageList, func(i, j int) bool { return diskUsageList[i].MountedOn < diskUsageList[j].MountedOn })
+
+	details.DiskUsage = diskUsageList
+	details.DiskUsageMap = diskUsage
+	details.CriticalDisks = criticalDisks
+	details.WarningDisks = warningDisks
 
 	if len(criticalDisks) > 0 {
 		result.Status = "Critical"
@@ -177,7 +197,7 @@ func (dc *DiskChecker) CheckDiskSpace(ctx context.Context) *v1alpha1.CheckResult
 		result.Message = "Disk usage is normal"
 	}
 
-	result.Details = mapToRawExtension(details)
+	result.Details = typedToRawExtension(details)
 	return result
 }
 
@@ -213,7 +233,7 @@ func (dc *DiskChecker) CheckSMART(ctx context.Context) *v1alpha1.CheckResult {
 	warningDisks := []string{}
 
 	for _, device := range devices {
-		if strings.HasPrefix(device, "sd") || strings.HasPrefix(device, "nvme") {
+		if device != "" && dc.checkDevice(device, !strings.HasPrefix(device, "sd") && !strings.HasPrefix(device, "nvme")) {
 			devicePath := "/dev/" + device
 			
 			// Execute smartctl for each device
@@ -327,8 +347,20 @@ func (dc *DiskChecker) CheckDiskPerformance(ctx context.Context) *v1alpha1.Check
 	iostatOutput := strings.TrimSpace(string(output))
 	details["iostat_output"] = iostatOutput
 
-	// Parse iostat output for performance metrics
-	lines := strings.Split(iostatOutput, "\n")
+	if version := toolVersion(ctx, "iostat"); version != "" {
+		details["tool_version"] = version
+	}
+
+	// Parse iostat output for performance metrics. The columns differ between sysstat versions
+	// (e.g. 11.x prints r/s w/s rkB/s wkB/s ... svctm %util, 12.x adds the discard and flush
+	// columns), so they are looked up by the name in the header.
+	devices, iostatStats, err := parseIostat(iostatOutput, "utilization_percent")
+	if err != nil {
+		notSupported(ctx, result, err)
+		details["error"] = err.Error()
+		result.Details = mapToRawExtension(details)
+		return result
+	}
 	deviceStats := make(map[string]map[string]float64)
 	
 	// Track issues for status determination
@@ -336,112 +368,12 @@ func (dc *DiskChecker) CheckDiskPerformance(ctx context.Context) *v1alpha1.Check
 	highLatency := []string{}
 	highServiceTime := []string{}
 
-	// Find the LAST set of statistics (iostat outputs multiple samples)
-	// We want the most recent/average statistics
-	lastHeaderIndex := -1
-	for i := len(lines) - 1; i >= 0; i-- {
-		if strings.Contains(lines[i], "Device") && strings.Contains(lines[i], "r/s") {
-			lastHeaderIndex = i
-			break
-		}
-	}
-
-	if lastHeaderIndex == -1 {
-		result.Status = "Warning"
-		result.Message = "Unable to parse iostat output (header not found)"
-		details["error"] = "iostat output format not recognized"
-		result.Details = mapToRawExtension(details)
-		return result
-	}
-
-	// Parse device statistics starting from the last header
-	startIndex := lastHeaderIndex + 1
-	for i := startIndex; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-		
-		fields := strings.Fields(line)
-		// iostat -x output format (modern): 
-		// Device r/s rkB/s rrqm/s %rrqm r_await rareq-sz w/s wkB/s wrqm/s %wrqm w_await wareq-sz d/s dkB/s drqm/s %drqm d_await dareq-sz f/s f_await aqu-sz %util
-		// Minimum 23 fields required for full extended stats
-		if len(fields) < 14 {
-			continue
-		}
-
-		device := fields[0]
-		if device == "Device" || strings.HasPrefix(device, "avg-cpu") || strings.HasPrefix(device, "Linux") {
-			continue // Skip header lines and CPU stats
-		}
-
+	for _, device := range devices {
 		// Skip loop devices and other virtual devices for performance checks
-		if strings.HasPrefix(device, "loop") || strings.HasPrefix(device, "dm-") {
+		if !dc.checkDevice(device, strings.HasPrefix(device, "loop") || strings.HasPrefix(device, "dm-")) {
 			continue
 		}
-
-		stats := make(map[string]float64)
-		
-		// Parse metrics based on modern iostat -x format
-		// Field positions: 0=Device, 1=r/s, 2=rkB/s, 3=rrqm/s, 4=%rrqm, 5=r_await, 6=rareq-sz,
-		//                 7=w/s, 8=wkB/s, 9=wrqm/s, 10=%wrqm, 11=w_await, 12=wareq-sz,
-		//                 13=d/s, 14=dkB/s, 15=drqm/s, 16=%drqm, 17=d_await, 18=dareq-sz,
-		//                 19=f/s, 20=f_await, 21=aqu-sz, 22=%util
-		
-		if rps, err := strconv.ParseFloat(fields[1], 64); err == nil {
-			stats["reads_per_sec"] = rps
-		}
-		if rkbs, err := strconv.ParseFloat(fields[2], 64); err == nil {
-			stats["read_kb_per_sec"] = rkbs
-		}
-		if rrqm, err := strconv.ParseFloat(fields[3], 64); err == nil {
-			stats["read_requests_merged"] = rrqm
-		}
-		// fields[4] is %rrqm - skip for now
-		if r_await, err := strconv.ParseFloat(fields[5], 64); err == nil {
-			stats["read_await_ms"] = r_await
-		}
-		if ravgq, err := strconv.ParseFloat(fields[6], 64); err == nil {
-			stats["read_avg_queue_size"] = ravgq
-		}
-		if len(fields) > 7 {
-			if wps, err := strconv.ParseFloat(fields[7], 64); err == nil {
-				stats["writes_per_sec"] = wps
-			}
-		}
-		if len(fields) > 8 {
-			if wkbs, err := strconv.ParseFloat(fields[8], 64); err == nil {
-				stats["write_kb_per_sec"] = wkbs
-			}
-		}
-		if len(fields) > 9 {
-			if wrqm, err := strconv.ParseFloat(fields[9], 64); err == nil {
-				stats["write_requests_merged"] = wrqm
-			}
-		}
-		// fields[10] is %wrqm - skip for now
-		if len(fields) > 11 {
-			if w_await, err := strconv.ParseFloat(fields[11], 64); err == nil {
-				stats["write_await_ms"] = w_await
-			}
-		}
-		if len(fields) > 12 {
-			if wavgq, err := strconv.ParseFloat(fields[12], 64); err == nil {
-				stats["write_avg_queue_size"] = wavgq
-			}
-		}
-		// fields[13-18] are discard stats - skip for now
-		// fields[19-20] are flush stats - skip for now
-		if len(fields) > 21 {
-			if aqu_sz, err := strconv.ParseFloat(fields[21], 64); err == nil {
-				stats["avg_queue_size"] = aqu_sz
-			}
-		}
-		if len(fields) > 22 {
-			if util, err := strconv.ParseFloat(fields[22], 64); err == nil {
-				stats["utilization_percent"] = util
-			}
-		}
+		stats := iostatStats[device]
 		
 		// Note: svctm (service time) was deprecated in newer iostat versions
 		// We can approximate it from r_await and w_await, but it's not as accurate
@@ -728,6 +660,11 @@ func parseSizeToGB(sizeStr string) float64 {
 	}
 }
 
+// gbToBytes converts the GiB of parseSizeToGB to bytes, the unit of the "_bytes" details
+func gbToBytes(gb float64) int64 {
+	return int64(gb * 1024 * 1024 * 1024)
+}
+
 // CheckPVs performs Physical Volume (LVM) monitoring using the pvs command
 func (dc *DiskChecker) CheckPVs(ctx context.Context) *v1alpha1.CheckResult {
 	details := make(map[string]interface{})
@@ -789,6 +726,9 @@ func (dc *DiskChecker) CheckPVs(ctx context.Context) *v1alpha1.CheckResult {
 				pvInfo["size_gb"] = pvSizeGB
 				pvInfo["free_gb"] = pvFreeGB
 				pvInfo["used_gb"] = pvUsed
+				pvInfo["size_bytes"] = gbToBytes(pvSizeGB)
+				pvInfo["free_bytes"] = gbToBytes(pvFreeGB)
+				pvInfo["used_bytes"] = gbToBytes(pvUsed)
 				pvInfo["used_percent"] = fmt.Sprintf("%.1f", pvUsedPercent)
 				pvInfo["free_percent"] = fmt.Sprintf("%.1f", pvFreePercent)
 
@@ -953,6 +893,9 @@ func (dc *DiskChecker) CheckLVM(ctx context.Context) *v1alpha1.CheckResult {
 					vgInfo["size_gb"] = vgSize
 					vgInfo["free_gb"] = vgFree
 					vgInfo["used_gb"] = vgUsed
+					vgInfo["size_bytes"] = gbToBytes(vgSize)
+					vgInfo["free_bytes"] = gbToBytes(vgFree)
+					vgInfo["used_bytes"] = gbToBytes(vgUsed)
 					vgInfo["used_percent"] = fmt.Sprintf("%.1f", vgUsedPercent)
 					vgInfo["free_percent"] = fmt.Sprintf("%.1f", vgFreePercent)
 
@@ -1027,6 +970,10 @@ func (dc *DiskChecker) CheckLVM(ctx context.Context) *v1alpha1.CheckResult {
 							"used_size_gb": usedSizeGB,
 							"free_size_gb": freeSizeGB,
 							"vg_free_gb":   vgFreeGB,
+							"total_size_bytes": gbToBytes(totalSizeGB),
+							"used_size_bytes":  gbToBytes(usedSizeGB),
+							"free_size_bytes":  gbToBytes(freeSizeGB),
+							"vg_free_bytes":    gbToBytes(vgFreeGB),
 						}
 						
 						// Check for low space in thin pool
@@ -1078,6 +1025,9 @@ func (dc *DiskChecker) CheckLVM(ctx context.Context) *v1alpha1.CheckResult {
 }
 
 // CheckIOWait checks disk I/O wait time
+// With spec.sampling, iostat takes one report per interval and the thresholds apply to a statistic of
+// the utilization of each device across the reports
+// With spec.smoothing, the thresholds apply to the EWMA of the utilization of each device over the last runs
 func (dc *DiskChecker) CheckIOWait(ctx context.Context) *v1alpha1.CheckResult {
 	details := make(map[string]interface{})
 	result := &v1alpha1.CheckResult{
@@ -1085,12 +1035,18 @@ func (dc *DiskChecker) CheckIOWait(ctx context.Context) *v1alpha1.CheckResult {
 		Status:    "Unknown",
 	}
 
-	command := "iostat -x 1 3"
+	settings, sampled := samplingSettings(dc.sampling)
+	args := []string{"-x", "1", "3"}
+	if sampled {
+		// The first report of iostat is the average since boot
+		args = []string{"-x", strconv.Itoa(settings.seconds()), strconv.Itoa(settings.samples + 1)}
+	}
+	command := "iostat " + strings.Join(args, " ")
 	result.Command = command
 
 	output, err := runHostCommand(ctx, command)
 	if err != nil {
-		cmd := exec.CommandContext(ctx, "iostat", "-x", "1", "3")
+		cmd := exec.CommandContext(ctx, "iostat", args...)
 		output, err = cmd.Output()
 		if err != nil {
 			result.Status = "Warning"
@@ -1107,21 +1063,10 @@ func (dc *DiskChecker) CheckIOWait(ctx context.Context) *v1alpha1.CheckResult {
 		result.Command = command
 	}
 
-	iostatOutput := strings.TrimSpace(string(output))
-	lines := strings.Split(iostatOutput, "\n")
-	
-	// Find the last set of statistics
-	lastHeaderIndex := -1
-	for i := len(lines) - 1; i >= 0; i-- {
-		if strings.Contains(lines[i], "Device") && strings.Contains(lines[i], "r/s") {
-			lastHeaderIndex = i
-			break
-		}
-	}
-
-	if lastHeaderIndex == -1 {
-		result.Status = "Warning"
-		result.Message = "Unable to parse iostat output"
+	devices, iostatStats, err := parseIostat(strings.TrimSpace(string(output)), "utilization_percent")
+	if err != nil {
+		notSupported(ctx, result, err)
+		details["error"] = err.Error()
 		result.Details = mapToRawExtension(details)
 		return result
 	}
@@ -1129,43 +1074,59 @@ func (dc *DiskChecker) CheckIOWait(ctx context.Context) *v1alpha1.CheckResult {
 	// Parse device statistics
 	highIOWait := []string{}
 	maxIOWait := 0.0
-	
-	for i := lastHeaderIndex + 1; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-		
-		fields := strings.Fields(line)
-		if len(fields) < 14 {
-			continue
-		}
-
-		device := fields[0]
-		if device == "Device" || strings.HasPrefix(device, "avg-cpu") || strings.HasPrefix(device, "Linux") {
-			continue
+	var reports []map[string]map[string]float64
+	deviceSampling := make(map[string]interface{})
+	deviceSmoothing := make(map[string]interface{})
+	smooth, smoothed := smoothing(dc.smoothing, dc.smoothingScope, "disk_io_wait")
+	if sampled {
+		if reports, err = parseIostatReports(strings.TrimSpace(string(output)), "utilization_percent"); err != nil {
+			notSupported(ctx, result, err)
+			details["error"] = err.Error()
+			result.Details = mapToRawExtension(details)
+			return result
 		}
-
+	}
+	
+	for _, device := range devices {
 		// Skip loop and dm devices
-		if strings.HasPrefix(device, "loop") || strings.HasPrefix(device, "dm-") {
+		if !dc.checkDevice(device, strings.HasPrefix(device, "loop") || strings.HasPrefix(device, "dm-")) {
 			continue
 		}
 
-		// Parse utilization (field 22 in modern iostat)
-		if len(fields) > 22 {
-			if util, err := strconv.ParseFloat(fields[22], 64); err == nil {
-				if util > 90 {
-					highIOWait = append(highIOWait, fmt.Sprintf("%s: %.1f%%", device, util))
-					if util > maxIOWait {
-						maxIOWait = util
-					}
+		util := iostatStats[device]["utilization_percent"]
+		if sampled {
+			var samples []float64
+			for _, report := range reports {
+				if stats, ok := report[device]; ok {
+					samples = append(samples, stats["utilization_percent"])
 				}
 			}
+			stats := summarizeSamples(samples, settings.percentile)
+			deviceSampling[device] = stats.details(settings)
+			util = stats.value(settings.statistic)
+		}
+		if smoothed {
+			// Each device has its own average
+			var smoothingDetails map[string]interface{}
+			util, smoothingDetails = smooth.smooth(device, util)
+			deviceSmoothing[device] = smoothingDetails
+		}
+		if util > 90 {
+			highIOWait = append(highIOWait, fmt.Sprintf("%s: %.1f%%", device, util))
+			if util > maxIOWait {
+				maxIOWait = util
+			}
 		}
 	}
 
 	details["high_io_wait_devices"] = highIOWait
 	details["max_io_wait"] = maxIOWait
+	if sampled {
+		details["sampling"] = deviceSampling
+	}
+	if smoothed {
+		details["smoothing"] = deviceSmoothing
+	}
 
 	if len(highIOWait) > 0 {
 		result.Status = "Warning"
@@ -1174,6 +1135,12 @@ func (dc *DiskChecker) CheckIOWait(ctx context.Context) *v1alpha1.CheckResult {
 		result.Status = "Healthy"
 		result.Message = "I/O wait is normal"
 	}
+	if sampled {
+		result.Message += fmt.Sprintf(" (%s)", settings.label())
+	}
+	if smoothed {
+		result.Message += fmt.Sprintf(" (EWMA of the last %d runs)", smooth.config.Samples)
+	}
 
 	result.Details = mapToRawExtension(details)
 	return result
@@ -1203,49 +1170,23 @@ func (dc *DiskChecker) CheckQueueDepth(ctx context.Context) *v1alpha1.CheckResul
 	}
 	result.Command = command
 
-	iostatOutput := strings.TrimSpace(string(output))
-	lines := strings.Split(iostatOutput, "\n")
-	
-	lastHeaderIndex := -1
-	for i := len(lines) - 1; i >= 0; i-- {
-		if strings.Contains(lines[i], "Device") && strings.Contains(lines[i], "aqu-sz") {
-			lastHeaderIndex = i
-			break
-		}
-	}
-
-	if lastHeaderIndex == -1 {
-		result.Status = "Warning"
-		result.Message = "Unable to parse iostat output"
+	devices, iostatStats, err := parseIostat(strings.TrimSpace(string(output)), "avg_queue_size")
+	if err != nil {
+		notSupported(ctx, result, err)
+		details["error"] = err.Error()
 		result.Details = mapToRawExtension(details)
 		return result
 	}
 
 	highQueueDepth := []string{}
 	
-	for i := lastHeaderIndex + 1; i < len(lines); i++ {
-		line := strings.TrimSpace(lines[i])
-		if line == "" {
-			continue
-		}
-		
-		fields := strings.Fields(line)
-		if len(fields) < 22 {
-			continue
-		}
-
-		device := fields[0]
-		if strings.HasPrefix(device, "loop") || strings.HasPrefix(device, "dm-") {
+	for _, device := range devices {
+		if !dc.checkDevice(device, strings.HasPrefix(device, "loop") || strings.HasPrefix(device, "dm-")) {
 			continue
 		}
 
-		// Parse average queue size (field 21 in modern iostat)
-		if len(fields) > 21 {
-			if aquSz, err := strconv.ParseFloat(fields[21], 64); err == nil {
-				if aquSz > 10.0 {
-					highQueueDepth = append(highQueueDepth, fmt.Sprintf("%s: %.2f", device, aquSz))
-				}
-			}
+		if aquSz := iostatStats[device]["avg_queue_size"]; aquSz > 10.0 {
+			highQueueDepth = append(highQueueDepth, fmt.Sprintf("%s: %.2f", device, aquSz))
 		}
 	}
 
@@ -1326,6 +1267,7 @@ func (dc *DiskChecker) CheckInodeUsage(ctx context.Context) *v1alpha1.CheckResul
 		Timestamp: metav1.Now(),
 		Status:    "Unknown",
 	}
+	warningPercent, criticalPercent := usageThresholds(dc.thresholds, "disk_inode_usage", 85, 95)
 
 	highInodeUsage := []string{}
 	criticalInodeUsage := []string{}
@@ -1342,15 +1284,15 @@ func (dc *DiskChecker) CheckInodeUsage(ctx context.Context) *v1alpha1.CheckResul
 		}
 
 		// Only consider real block devices (skip tmpfs/overlay/loop devices)
-		if !strings.HasPrefix(filesystem, "/dev/") || strings.HasPrefix(filesystem, "/dev/loop") {
+		if !dc.checkDevice(filesystem, !strings.HasPrefix(filesystem, "/dev/") || strings.HasPrefix(filesystem, "/dev/loop")) {
 			return
 		}
 
 		// Skip obvious container-specific mounts
-		if strings.Contains(mountpoint, "/var/lib/containers/storage/overlay") ||
+		if !dc.checkMount(filesystem, mountpoint, strings.Contains(mountpoint, "/var/lib/containers/storage/overlay") ||
 			strings.Contains(mountpoint, "/var/lib/kubelet/pods") ||
 			strings.HasPrefix(mountpoint, "/run/") ||
-			strings.HasPrefix(mountpoint, "/sys/") {
+			strings.HasPrefix(mountpoint, "/sys/")) {
 			return
 		}
 
@@ -1370,9 +1312,9 @@ func (dc *DiskChecker) CheckInodeUsage(ctx context.Context) *v1alpha1.CheckResul
 			entry = fmt.Sprintf("%s [%s]", entry, source)
 		}
 
-		if percent >= 95 {
+		if percent >= criticalPercent {
 			criticalInodeUsage = append(criticalInodeUsage, entry)
-		} else if percent >= 85 {
+		} else if percent >= warningPercent {
 			highInodeUsage = append(highInodeUsage, entry)
 		}
 	}
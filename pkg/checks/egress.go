@@ -0,0 +1,146 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// validEchoURL restricts the echo URL to characters that are safe inside a single-quoted host shell argument
+var validEchoURL = regexp.MustCompile(`^https?://[A-Za-z0-9._~:/?#@!&()*+,;=%\[\]-]+$`)
+
+// parseEchoResponse extracts the caller IP from an echo endpoint response: either plain text
+// (ifconfig.me, icanhazip.com) or JSON with an "ip" or "origin" field (ipify, httpbin)
+func parseEchoResponse(body string) net.IP {
+	body = strings.TrimSpace(body)
+	var payload map[string]interface{}
+	if json.Unmarshal([]byte(body), &payload) == nil {
+		for _, key := range []string{"ip", "origin"} {
+			if value, ok := payload[key].(string); ok {
+				// httpbin reports "client, proxy" when the request went through proxies
+				return net.ParseIP(strings.TrimSpace(strings.Split(value, ",")[0]))
+			}
+		}
+		return nil
+	}
+	fields := strings.Fields(body)
+	if len(fields) == 0 {
+		return nil
+	}
+	return net.ParseIP(fields[0])
+}
+
+// sourceIPExpected reports whether ip matches one of the expected IP addresses or CIDRs
+func sourceIPExpected(ip net.IP, expected []string) bool {
+	for _, entry := range expected {
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			if network.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if expectedIP := net.ParseIP(entry); expectedIP != nil && expectedIP.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEgress validates north-south egress: it requests spec.egressEchoURL (from the host, or from a pod of
+// spec.egressNamespace on the node) and checks that the source IP seen by the endpoint is one of
+// spec.egressExpectedSourceIPs, i.e. that the traffic actually exits through the configured egress IP or gateway.
+func (nc *NetworkChecker) CheckEgress(ctx context.Context, spec v1alpha1.NetworkChecks) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	echoURL := spec.EgressEchoURL
+	if echoURL == "" || !validEchoURL.MatchString(echoURL) {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Invalid or missing egressEchoURL %q", echoURL)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["echo_url"] = echoURL
+	if len(spec.EgressExpectedSourceIPs) > 0 {
+		details["expected_source_ips"] = spec.EgressExpectedSourceIPs
+	}
+
+	command := fmt.Sprintf("curl -sS --connect-timeout 5 --max-time 15 '%s'", echoURL)
+	result.Command = command
+
+	// Probe from a pod network namespace when egress IPs are assigned to a namespace
+	if spec.EgressNamespace != "" {
+		details["namespace"] = spec.EgressNamespace
+		kc, err := NewKubernetesChecker(nc.nodeName)
+		if err != nil {
+			result.Status = "Warning"
+			result.Message = fmt.Sprintf("Unable to create Kubernetes client: %v", err)
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+		netns, podName, err := kc.PodNetworkNamespaceIn(ctx, spec.EgressNamespace)
+		if errors.Is(err, errNoPodInNamespace) {
+			result.Status = "Healthy"
+			result.Message = fmt.Sprintf("No running pod of namespace %s on the node, egress not probed", spec.EgressNamespace)
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+		if err != nil {
+			result.Status = "Warning"
+			result.Message = fmt.Sprintf("Unable to probe egress from namespace %s: %v", spec.EgressNamespace, err)
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+		details["probe_pod"] = fmt.Sprintf("%s/%s", spec.EgressNamespace, podName)
+		command = fmt.Sprintf("nsenter --net=%s %s", netns, command)
+		result.Command = fmt.Sprintf("nsenter --net=<netns of %s/%s> %s", spec.EgressNamespace, podName, result.Command)
+	}
+
+	output, err := runHostCommand(ctx, command)
+	if err != nil {
+		details["error"] = strings.TrimSpace(string(output))
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Egress endpoint %s unreachable: %s", echoURL, strings.TrimSpace(string(output)))
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	sourceIP := parseEchoResponse(string(output))
+	if sourceIP == nil {
+		response := strings.TrimSpace(string(output))
+		if len(response) > 200 {
+			response = response[:200]
+		}
+		details["response"] = response
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Egress endpoint %s reachable but its response does not contain an IP address", echoURL)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["source_ip"] = sourceIP.String()
+
+	switch {
+	case len(spec.EgressExpectedSourceIPs) == 0:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Egress endpoint reachable, traffic exits with source IP %s", sourceIP)
+	case sourceIPExpected(sourceIP, spec.EgressExpectedSourceIPs):
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Traffic exits with expected source IP %s", sourceIP)
+	default:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Egress source IP mismatch: expected %s, got %s", strings.Join(spec.EgressExpectedSourceIPs, " or "), sourceIP)
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
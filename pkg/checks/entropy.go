@@ -0,0 +1,64 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CheckEntropy checks the entropy available to the kernel random number generator. On older kernels a
+// starved pool blocks /dev/random readers and slows down TLS handshakes and key generation, which is common
+// on VMs without a hardware RNG. Since Linux 5.18 the pool is always reported full (256 bits).
+func (sc *SystemChecker) CheckEntropy(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	result.Command = "cat /proc/sys/kernel/random/entropy_avail"
+	data, err := readProcFile(ctx, "/proc/sys/kernel/random/entropy_avail")
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Failed to read /proc/sys/kernel/random/entropy_avail: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	entropy, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Invalid /proc/sys/kernel/random/entropy_avail value: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["entropy_avail"] = entropy
+	if poolData, err := readProcFile(ctx, "/proc/sys/kernel/random/poolsize"); err == nil {
+		if poolSize, err := strconv.Atoi(strings.TrimSpace(string(poolData))); err == nil {
+			details["pool_size"] = poolSize
+		}
+	}
+
+	const warningEntropy, criticalEntropy = 200, 64
+	details["warning_threshold"] = warningEntropy
+	details["critical_threshold"] = criticalEntropy
+
+	if entropy < criticalEntropy {
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Entropy pool exhausted: %d bits available", entropy)
+		details["recommendation"] = "Install rng-tools (rngd) or enable a virtio-rng device on the VM"
+	} else if entropy < warningEntropy {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Entropy pool critically low: %d bits available", entropy)
+		details["recommendation"] = "Install rng-tools (rngd) or enable a virtio-rng device on the VM"
+	} else {
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("%d bits of entropy available", entropy)
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
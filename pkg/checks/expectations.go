@@ -0,0 +1,282 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Names are interpolated into host shell commands, so they are validated again here
+// even though the CRD already restricts them
+var (
+	validModuleName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+	validUnitName   = regexp.MustCompile(`^[a-zA-Z0-9@._:-]+$`)
+)
+
+// SetExpectations applies the expected node state from spec.expectations
+func (sc *SystemChecker) SetExpectations(expectations *v1alpha1.ExpectedState) {
+	sc.expectations = expectations
+}
+
+// missingKernelModules returns the required modules that are not loaded. Loaded and built-in
+// modules are listed in /sys/module; names are compared with dashes normalized to underscores
+// as the kernel does.
+func missingKernelModules(ctx context.Context, required []string) ([]string, error) {
+	output, err := runHostCommand(ctx, "ls /sys/module")
+	if err != nil {
+		return nil, fmt.Errorf("unable to list /sys/module: %v", err)
+	}
+	loaded := make(map[string]bool)
+	for _, name := range strings.Fields(string(output)) {
+		loaded[name] = true
+	}
+
+	missing := []string{}
+	for _, module := range required {
+		if !validModuleName.MatchString(module) {
+			missing = append(missing, fmt.Sprintf("%s (invalid name)", module))
+			continue
+		}
+		if !loaded[strings.ReplaceAll(module, "-", "_")] {
+			missing = append(missing, module)
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// inactiveServices returns the required systemd units that are not active, with their state
+// (e.g. "crio (inactive)")
+func inactiveServices(ctx context.Context, required []string) ([]string, error) {
+	inactive := []string{}
+	units := []string{}
+	for _, unit := range required {
+		if !validUnitName.MatchString(unit) {
+			inactive = append(inactive, fmt.Sprintf("%s (invalid name)", unit))
+			continue
+		}
+		units = append(units, unit)
+	}
+	if len(units) == 0 {
+		return inactive, nil
+	}
+
+	// is-active prints one state per unit, in order, and exits non-zero if any unit is not active
+	output, err := runHostCommand(ctx, fmt.Sprintf("systemctl is-active %s || true", strings.Join(units, " ")))
+	if err != nil {
+		return nil, fmt.Errorf("unable to query systemd: %v", err)
+	}
+	states := strings.Fields(string(output))
+	for i, unit := range units {
+		state := "unknown"
+		if i < len(states) {
+			state = states[i]
+		}
+		if state != "active" {
+			inactive = append(inactive, fmt.Sprintf("%s (%s)", unit, state))
+		}
+	}
+	return inactive, nil
+}
+
+// validExpectedSysctl matches the sysctl names of spec.expectations.sysctls. Slashes stand for the dots
+// of the names of interfaces (e.g. net.ipv4.conf.eth0/100.rp_filter), as in sysctl.
+var validExpectedSysctl = regexp.MustCompile(`^[a-zA-Z0-9_-]+([./][a-zA-Z0-9_-]+)*$`)
+
+// sysctlPath returns the /proc/sys file of a sysctl name: dots separate the directories and slashes are
+// dots in a directory name
+func sysctlPath(name string) string {
+	return "/proc/sys/" + strings.Map(func(r rune) rune {
+		switch r {
+		case '.':
+			return '/'
+		case '/':
+			return '.'
+		}
+		return r
+	}, name)
+}
+
+// CheckSysctlDrift compares the kernel parameters of spec.expectations.sysctls with their values in
+// /proc/sys and reports every mismatching key. Parameters the kernel does not know (e.g. a module not
+// loaded) are mismatches too.
+func (sc *SystemChecker) CheckSysctlDrift(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = "cat /proc/sys/<expected sysctls>"
+
+	if sc.expectations == nil || len(sc.expectations.Sysctls) == 0 {
+		result.Status = "Healthy"
+		result.Message = "No sysctls declared in spec.expectations.sysctls"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	names := make([]string, 0, len(sc.expectations.Sysctls))
+	for name := range sc.expectations.Sysctls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var mismatches []map[string]interface{}
+	var mismatching []string
+	for _, name := range names {
+		expected := strings.Join(strings.Fields(sc.expectations.Sysctls[name]), " ")
+		actual := ""
+		if !validExpectedSysctl.MatchString(name) {
+			actual = "<invalid name>"
+		} else if data, err := readProcFile(ctx, sysctlPath(name)); err != nil {
+			actual = "<not available>"
+		} else {
+			actual = strings.Join(strings.Fields(string(data)), " ")
+		}
+		if actual == expected {
+			continue
+		}
+		mismatches = append(mismatches, map[string]interface{}{
+			"name":     name,
+			"expected": expected,
+			"actual":   actual,
+		})
+		mismatching = append(mismatching, fmt.Sprintf("%s=%s (expected %s)", name, actual, expected))
+	}
+	details["expected_count"] = len(names)
+	details["mismatches"] = mismatches
+
+	if len(mismatches) > 0 {
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("%d of %d sysctls differ from spec.expectations: %s", len(mismatches), len(names), strings.Join(mismatching, ", "))
+	} else {
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("All %d expected sysctls match", len(names))
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
+
+// parseKernelCmdline splits /proc/cmdline into parameters, keeping the double quoted values whole
+// (e.g. dyndbg="file drivers/* +p") as the kernel does
+func parseKernelCmdline(cmdline string) []string {
+	var params []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range strings.TrimSpace(cmdline) {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case !quoted && (r == ' ' || r == '\t' || r == '\n'):
+			if current.Len() > 0 {
+				params = append(params, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		params = append(params, current.String())
+	}
+	return params
+}
+
+// kernelParamName normalizes the name of a kernel parameter: the kernel accepts dashes and underscores
+// interchangeably in the names, not in the values
+func kernelParamName(param string) string {
+	name, _, _ := strings.Cut(param, "=")
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// matchKernelParam returns the parameter of the command line matching an expected "name" or
+// "name=value", or "" if none does. When a parameter is repeated the last one is used, as by most
+// kernel parameters.
+func matchKernelParam(params []string, expected string) string {
+	name := kernelParamName(expected)
+	_, value, hasValue := strings.Cut(expected, "=")
+	last := ""
+	for _, param := range params {
+		if kernelParamName(param) == name {
+			last = param
+		}
+	}
+	if last == "" || !hasValue {
+		return last
+	}
+	if _, actual, _ := strings.Cut(last, "="); actual != value {
+		return ""
+	}
+	return last
+}
+
+// CheckKernelCmdline validates the parameters the kernel was booted with (/proc/cmdline) against
+// spec.expectations.requiredKernelParameters and forbiddenKernelParameters. A missing required or a
+// present forbidden parameter is Critical: boot parameters (IOMMU, huge pages, isolated CPUs,
+// mitigations) only change with a reboot, usually after a MachineConfig or bootloader change.
+func (sc *SystemChecker) CheckKernelCmdline(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = "cat /proc/cmdline"
+
+	if sc.expectations == nil || (len(sc.expectations.RequiredKernelParameters) == 0 && len(sc.expectations.ForbiddenKernelParameters) == 0) {
+		result.Status = "Healthy"
+		result.Message = "No kernel parameters declared in spec.expectations"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	output, err := runHostCommand(ctx, "cat /proc/cmdline")
+	if err != nil {
+		result.Status = "Unknown"
+		result.Message = fmt.Sprintf("Unable to read /proc/cmdline: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	cmdline := strings.TrimSpace(string(output))
+	params := parseKernelCmdline(cmdline)
+	details["cmdline"] = cmdline
+
+	missing := []string{}
+	for _, expected := range sc.expectations.RequiredKernelParameters {
+		if matchKernelParam(params, expected) == "" {
+			missing = append(missing, expected)
+		}
+	}
+	present := []string{}
+	for _, forbidden := range sc.expectations.ForbiddenKernelParameters {
+		if param := matchKernelParam(params, forbidden); param != "" {
+			present = append(present, param)
+		}
+	}
+	details["required_count"] = len(sc.expectations.RequiredKernelParameters)
+	details["forbidden_count"] = len(sc.expectations.ForbiddenKernelParameters)
+	details["missing_required"] = missing
+	details["forbidden_present"] = present
+
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing required parameters: %s", strings.Join(missing, ", ")))
+	}
+	if len(present) > 0 {
+		problems = append(problems, fmt.Sprintf("booted with forbidden parameters: %s", strings.Join(present, ", ")))
+	}
+	if len(problems) > 0 {
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Kernel command line does not match spec.expectations: %s", strings.Join(problems, "; "))
+	} else {
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Kernel command line has the %d required parameters and none of the %d forbidden ones",
+			len(sc.expectations.RequiredKernelParameters), len(sc.expectations.ForbiddenKernelParameters))
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,339 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// nvidiaQueryFields are the fields of nvidia-smi --query-gpu read by the gpu check, in the order of its output
+const nvidiaQueryFields = "index,name,pci.bus_id,temperature.gpu,utilization.gpu,memory.used,memory.total," +
+	"ecc.errors.corrected.volatile.total,ecc.errors.uncorrected.volatile.total,retired_pages.pending," +
+	"clocks_throttle_reasons.hw_thermal_slowdown"
+
+// Host commands of the gpu check. With the NVIDIA GPU Operator the driver, and nvidia-smi, live in the driver
+// container, whose root is mounted at /run/nvidia/driver on the host. The AMD uncorrectable and correctable
+// memory errors are read from the RAS counters of amdgpu, printed as "<card> ue <n> ce <n>".
+const (
+	nvidiaSMICommand = "if command -v nvidia-smi >/dev/null 2>&1; then nvidia-smi --query-gpu=" + nvidiaQueryFields + " --format=csv,noheader,nounits; " +
+		"elif [ -x /run/nvidia/driver/usr/bin/nvidia-smi ]; then chroot /run/nvidia/driver nvidia-smi --query-gpu=" + nvidiaQueryFields + " --format=csv,noheader,nounits; fi"
+	rocmSMICommand   = "if command -v rocm-smi >/dev/null 2>&1; then rocm-smi --showtemp --showuse --showmemuse --json; fi"
+	amdRASCommand    = `for f in /sys/class/drm/card*/device/ras/umc_err_count; do [ -r "$f" ] && echo "$(echo "$f" | cut -d/ -f5) $(tr ':\n' '  ' < "$f")"; done; true`
+	gpuKernelCommand = "journalctl -k --no-pager -o cat --since '1 hour ago' | grep -E 'NVRM: Xid|amdgpu.*(GPU reset begin|ring .* timeout)' || true"
+)
+
+// Temperatures of the GPU cores, in °C. Data center GPUs slow down around 90 °C.
+const (
+	gpuTemperatureWarning  = 85
+	gpuTemperatureCritical = 95
+)
+
+// criticalXids are the NVIDIA Xid errors of a failing GPU: double bit ECC errors (48), row remapping
+// failures (63, 64), NVLink errors (74), GPU fallen off the bus (79), uncontained ECC errors (94, 95),
+// GSP and firmware errors (61, 62, 119, 120) and excessive SBE interrupts (92). They need a GPU reset
+// or a node drain.
+var criticalXids = map[int]bool{48: true, 61: true, 62: true, 63: true, 64: true, 74: true, 79: true, 92: true, 94: true, 95: true, 119: true, 120: true}
+
+// applicationXids are raised by faulty workloads (graphics engine exceptions, MMU faults, stopped
+// channels) rather than by the GPU, and only reported in the details
+var applicationXids = map[int]bool{13: true, 31: true, 43: true, 45: true}
+
+// xidPattern matches an NVIDIA Xid error in the kernel log: "NVRM: Xid (PCI:0000:3b:00): 79, pid=..."
+var xidPattern = regexp.MustCompile(`NVRM: Xid \(PCI:([0-9a-fA-F:.]+)\): (\d+)`)
+
+// GPUChecker handles the health of the NVIDIA and AMD GPUs
+type GPUChecker struct {
+	nodeName string
+}
+
+// NewGPUChecker creates a new GPU checker
+func NewGPUChecker(nodeName string) *GPUChecker {
+	return &GPUChecker{
+		nodeName: nodeName,
+	}
+}
+
+// gpuDevice is the state of a GPU; the values a tool does not report are negative
+type gpuDevice struct {
+	vendor          string
+	index           string
+	name            string
+	busID           string
+	temperature     float64
+	utilization     float64
+	memoryUsed      float64
+	eccCorrected    int64
+	eccUncorrected  int64
+	retiredPending  bool
+	thermalSlowdown bool
+}
+
+// details returns the values of the GPU reported by its tool
+func (g gpuDevice) details() map[string]interface{} {
+	entry := map[string]interface{}{"vendor": g.vendor, "index": g.index}
+	if g.name != "" {
+		entry["name"] = g.name
+	}
+	if g.busID != "" {
+		entry["bus_id"] = g.busID
+	}
+	for key, value := range map[string]float64{
+		"temperature_celsius": g.temperature,
+		"utilization_percent": g.utilization,
+		"memory_used_percent": g.memoryUsed,
+	} {
+		if value >= 0 {
+			entry[key] = value
+		}
+	}
+	if g.eccUncorrected >= 0 {
+		entry["ecc_uncorrected"] = g.eccUncorrected
+		entry["ecc_corrected"] = g.eccCorrected
+	}
+	if g.retiredPending {
+		entry["retired_pages_pending"] = true
+	}
+	if g.thermalSlowdown {
+		entry["thermal_slowdown"] = true
+	}
+	return entry
+}
+
+// gpuValue parses a value printed by nvidia-smi or rocm-smi, -1 for "[N/A]", "[Not Supported]" and
+// the other values that are not numbers
+func gpuValue(value string) float64 {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return -1
+	}
+	return number
+}
+
+// parseNvidiaSMI parses the CSV output of nvidiaSMICommand, one line per GPU
+func parseNvidiaSMI(output string) []gpuDevice {
+	var gpus []gpuDevice
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != len(strings.Split(nvidiaQueryFields, ",")) {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		gpu := gpuDevice{
+			vendor:          "NVIDIA",
+			index:           fields[0],
+			name:            fields[1],
+			busID:           fields[2],
+			temperature:     gpuValue(fields[3]),
+			utilization:     gpuValue(fields[4]),
+			memoryUsed:      -1,
+			eccCorrected:    int64(gpuValue(fields[7])),
+			eccUncorrected:  int64(gpuValue(fields[8])),
+			retiredPending:  strings.EqualFold(fields[9], "Yes"),
+			thermalSlowdown: strings.EqualFold(fields[10], "Active"),
+		}
+		if used, total := gpuValue(fields[5]), gpuValue(fields[6]); used >= 0 && total > 0 {
+			gpu.memoryUsed = used * 100 / total
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus
+}
+
+// parseRocmSMI parses the JSON output of rocmSMICommand, keyed by card ("card0"), with the AMD RAS
+// counters of amdRASCommand
+func parseRocmSMI(output, ras string) ([]gpuDevice, error) {
+	var cards map[string]map[string]string
+	if err := json.Unmarshal([]byte(output), &cards); err != nil {
+		return nil, err
+	}
+	rasErrors := make(map[string][2]int64)
+	for _, line := range strings.Split(ras, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 5 || fields[1] != "ue" || fields[3] != "ce" {
+			continue
+		}
+		ue, _ := strconv.ParseInt(fields[2], 10, 64)
+		ce, _ := strconv.ParseInt(fields[4], 10, 64)
+		rasErrors[fields[0]] = [2]int64{ue, ce}
+	}
+
+	var gpus []gpuDevice
+	for card, values := range cards {
+		if !strings.HasPrefix(card, "card") {
+			continue
+		}
+		gpu := gpuDevice{
+			vendor:         "AMD",
+			index:          strings.TrimPrefix(card, "card"),
+			temperature:    -1,
+			utilization:    gpuValue(values["GPU use (%)"]),
+			memoryUsed:     gpuValue(values["GPU memory use (%)"]),
+			eccCorrected:   -1,
+			eccUncorrected: -1,
+		}
+		// The junction (hotspot) temperature is the one the GPU throttles on, the edge one on older cards
+		for _, key := range []string{"Temperature (Sensor junction) (C)", "Temperature (Sensor edge) (C)"} {
+			if temperature := gpuValue(values[key]); temperature >= 0 {
+				gpu.temperature = temperature
+				break
+			}
+		}
+		if counts, ok := rasErrors[card]; ok {
+			gpu.eccUncorrected, gpu.eccCorrected = counts[0], counts[1]
+		}
+		gpus = append(gpus, gpu)
+	}
+	sort.Slice(gpus, func(i, j int) bool { return gpus[i].index < gpus[j].index })
+	return gpus, nil
+}
+
+// CheckGPU reports the health of the NVIDIA (nvidia-smi) and AMD (rocm-smi) GPUs of the node: the
+// temperature, utilization and memory usage of each GPU, its ECC errors and the GPU errors of the kernel
+// log in the last hour (NVIDIA Xid errors, amdgpu resets and ring timeouts). Uncorrectable ECC errors,
+// the Xid errors of a failing GPU, GPU resets and temperatures from gpuTemperatureCritical are Critical;
+// retired pages waiting for a GPU reset, thermal slowdown and temperatures from gpuTemperatureWarning
+// are Warning. Nodes without GPU tools are Healthy.
+func (gc *GPUChecker) CheckGPU(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = fmt.Sprintf("%s; %s; %s", nvidiaSMICommand, rocmSMICommand, gpuKernelCommand)
+
+	var gpus []gpuDevice
+	var critical, warning []string
+	output, err := runHostCommand(ctx, nvidiaSMICommand)
+	if err != nil {
+		// A failing nvidia-smi is the usual sign of a driver that lost its GPUs
+		message := strings.TrimSpace(string(output))
+		if message == "" {
+			message = err.Error()
+		}
+		details["nvidia_smi_error"] = message
+		critical = append(critical, fmt.Sprintf("nvidia-smi failed: %s", message))
+	} else {
+		gpus = append(gpus, parseNvidiaSMI(string(output))...)
+	}
+	if output, err := runHostCommand(ctx, rocmSMICommand); err == nil && strings.TrimSpace(string(output)) != "" {
+		ras, _ := runHostCommand(ctx, amdRASCommand)
+		amd, err := parseRocmSMI(string(output), string(ras))
+		if err != nil {
+			details["rocm_smi_error"] = err.Error()
+		}
+		gpus = append(gpus, amd...)
+	}
+
+	if output, err := runHostCommand(ctx, gpuKernelCommand); err == nil {
+		xids := make(map[string]int)
+		var applicationErrors, resets int
+		for _, line := range strings.Split(string(output), "\n") {
+			if match := xidPattern.FindStringSubmatch(line); match != nil {
+				xid, _ := strconv.Atoi(match[2])
+				if applicationXids[xid] {
+					applicationErrors++
+					continue
+				}
+				xids[fmt.Sprintf("%s xid %d", match[1], xid)]++
+				continue
+			}
+			if strings.Contains(line, "amdgpu") {
+				resets++
+			}
+		}
+		keys := make([]string, 0, len(xids))
+		for key := range xids {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			busID, code, _ := strings.Cut(key, " xid ")
+			xid, _ := strconv.Atoi(code)
+			message := fmt.Sprintf("Xid %d on %s (%d times)", xid, busID, xids[key])
+			if criticalXids[xid] {
+				critical = append(critical, message)
+			} else {
+				warning = append(warning, message)
+			}
+		}
+		details["xid_errors"] = xids
+		details["application_xid_errors"] = applicationErrors
+		if resets > 0 {
+			details["amdgpu_resets"] = resets
+			critical = append(critical, fmt.Sprintf("%d amdgpu resets or ring timeouts", resets))
+		}
+	} else {
+		details["kernel_log_error"] = err.Error()
+	}
+
+	gpuDetails := make([]map[string]interface{}, 0, len(gpus))
+	for _, gpu := range gpus {
+		gpuDetails = append(gpuDetails, gpu.details())
+	}
+	details["gpus"] = gpuDetails
+	details["gpu_count"] = len(gpus)
+	if len(gpus) == 0 && len(critical) == 0 && len(warning) == 0 {
+		result.Status = "Healthy"
+		result.Message = "No NVIDIA or AMD GPU found (nvidia-smi and rocm-smi not available)"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	maxTemperature, totalUtilization, utilizationCount := -1.0, 0.0, 0
+	for _, gpu := range gpus {
+		label := fmt.Sprintf("%s GPU %s", gpu.vendor, gpu.index)
+		switch {
+		case gpu.eccUncorrected > 0:
+			critical = append(critical, fmt.Sprintf("%s: %d uncorrectable ECC errors", label, gpu.eccUncorrected))
+		case gpu.retiredPending:
+			warning = append(warning, fmt.Sprintf("%s: retired memory pages pending a GPU reset", label))
+		}
+		switch {
+		case gpu.temperature >= gpuTemperatureCritical:
+			critical = append(critical, fmt.Sprintf("%s at %.0f°C", label, gpu.temperature))
+		case gpu.temperature >= gpuTemperatureWarning:
+			warning = append(warning, fmt.Sprintf("%s at %.0f°C", label, gpu.temperature))
+		case gpu.thermalSlowdown:
+			warning = append(warning, fmt.Sprintf("%s: thermal slowdown active", label))
+		}
+		if gpu.temperature > maxTemperature {
+			maxTemperature = gpu.temperature
+		}
+		if gpu.utilization >= 0 {
+			totalUtilization += gpu.utilization
+			utilizationCount++
+		}
+	}
+
+	summary := fmt.Sprintf("%d GPUs", len(gpus))
+	if maxTemperature >= 0 {
+		summary += fmt.Sprintf(", max %.0f°C", maxTemperature)
+	}
+	if utilizationCount > 0 {
+		average := totalUtilization / float64(utilizationCount)
+		details["average_utilization_percent"] = average
+		summary += fmt.Sprintf(", %.0f%% average utilization", average)
+	}
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("GPU errors: %s (%s)", strings.Join(append(critical, warning...), "; "), summary)
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("GPU warnings: %s (%s)", strings.Join(warning, "; "), summary)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("GPUs healthy: %s", summary)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -5,14 +5,30 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// typedToRawExtension converts the typed details of a check (e.g. v1alpha1.MemoryDetails) to
+// runtime.RawExtension, storing them in the same format as mapToRawExtension
+func typedToRawExtension(details interface{}) runtime.RawExtension {
+	data, err := json.Marshal(details)
+	if err != nil {
+		return runtime.RawExtension{}
+	}
+	var detailsMap map[string]interface{}
+	if err := json.Unmarshal(data, &detailsMap); err != nil {
+		return runtime.RawExtension{}
+	}
+	return mapToRawExtension(detailsMap)
+}
+
 // mapToRawExtension converts a map to runtime.RawExtension
 func mapToRawExtension(data map[string]interface{}) runtime.RawExtension {
 	if len(data) == 0 {
@@ -254,6 +270,22 @@ type CPUStats struct {
 	Steal   int64
 }
 
+// stealPercentBetween returns the share of the CPU time stolen between two /proc/stat measurements
+func stealPercentBetween(before, after *CPUStats) float64 {
+	total := (after.User - before.User) + (after.Nice - before.Nice) + (after.System - before.System) +
+		(after.Idle - before.Idle) + (after.IOWait - before.IOWait) + (after.IRQ - before.IRQ) +
+		(after.SoftIRQ - before.SoftIRQ) + (after.Steal - before.Steal)
+	if total <= 0 {
+		return 0
+	}
+	return float64(after.Steal-before.Steal) / float64(total) * 100.0
+}
+
+// total returns the CPU time of all the states, in clock ticks
+func (s *CPUStats) total() int64 {
+	return s.User + s.Nice + s.System + s.Idle + s.IOWait + s.IRQ + s.SoftIRQ + s.Steal
+}
+
 // readCPUStats reads CPU statistics from /proc/stat (aggregate line "cpu ")
 func readCPUStats(ctx context.Context) (*CPUStats, error) {
 	data, err := readProcFile(ctx, "/proc/stat")
@@ -265,54 +297,31 @@ func readCPUStats(ctx context.Context) (*CPUStats, error) {
 	for _, line := range lines {
 		if strings.HasPrefix(line, "cpu ") {
 			// This is the aggregate CPU line (not cpu0, cpu1, etc.)
-			fields := strings.Fields(line)
-			if len(fields) < 9 {
-				return nil, fmt.Errorf("invalid cpu line format")
-			}
-
-			stats := &CPUStats{}
-			// cpu line format: cpu user nice system idle iowait irq softirq steal guest guest_nice
-			// Index:           0   1    2    3      4     5       6   7        8     9      10
-			if val, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
-				stats.User = val
-			}
-			if val, err := strconv.ParseInt(fields[2], 10, 64); err == nil {
-				stats.Nice = val
-			}
-			if val, err := strconv.ParseInt(fields[3], 10, 64); err == nil {
-				stats.System = val
-			}
-			if val, err := strconv.ParseInt(fields[4], 10, 64); err == nil {
-				stats.Idle = val
-			}
-			if len(fields) > 5 {
-				if val, err := strconv.ParseInt(fields[5], 10, 64); err == nil {
-					stats.IOWait = val
-				}
-			}
-			if len(fields) > 6 {
-				if val, err := strconv.ParseInt(fields[6], 10, 64); err == nil {
-					stats.IRQ = val
-				}
-			}
-			if len(fields) > 7 {
-				if val, err := strconv.ParseInt(fields[7], 10, 64); err == nil {
-					stats.SoftIRQ = val
-				}
-			}
-			if len(fields) > 8 {
-				if val, err := strconv.ParseInt(fields[8], 10, 64); err == nil {
-					stats.Steal = val
-				}
-			}
-
-			return stats, nil
+			return parseCPUStatsLine(strings.Fields(line))
 		}
 	}
 
 	return nil, fmt.Errorf("cpu aggregate line not found in /proc/stat")
 }
 
+// parseCPUStatsLine parses the fields of the aggregate cpu line of /proc/stat
+func parseCPUStatsLine(fields []string) (*CPUStats, error) {
+	if len(fields) < 9 {
+		return nil, fmt.Errorf("invalid cpu line format")
+	}
+
+	stats := &CPUStats{}
+	// cpu line format: cpu user nice system idle iowait irq softirq steal guest guest_nice
+	// Index:           0   1    2    3      4     5       6   7        8     9      10
+	values := []*int64{&stats.User, &stats.Nice, &stats.System, &stats.Idle, &stats.IOWait, &stats.IRQ, &stats.SoftIRQ, &stats.Steal}
+	for i, value := range values {
+		if val, err := strconv.ParseInt(fields[i+1], 10, 64); err == nil {
+			*value = val
+		}
+	}
+	return stats, nil
+}
+
 // EventWindow tracks events in a sliding time window for debouncing
 type EventWindow struct {
 	mu     sync.Mutex
@@ -369,7 +378,8 @@ func (w *EventWindow) LastEvent() time.Time {
 	return w.events[len(w.events)-1]
 }
 
-// withTimeout adds a timeout to a context if not already present
+// withTimeout adds a timeout to a context if not already present.
+// A deadline set by the executor from spec.timeouts takes precedence over the built-in timeout.
 func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
 	if _, hasDeadline := ctx.Deadline(); hasDeadline {
 		return ctx, func() {}
@@ -401,3 +411,31 @@ func checkSystemdAvailable(ctx context.Context, command string) (bool, []byte, e
 	return true, output, nil
 }
 
+
+// filterAllows reports whether an object must be checked, given the built-in skip decision and the
+// user filter. Exclude patterns always skip, Include patterns override the built-in skip lists.
+// Each name is matched separately (e.g. "/dev/sda" and "sda") so patterns can use either form.
+func filterAllows(filter *v1alpha1.FilterPatterns, skippedByDefault bool, names ...string) bool {
+	if filter == nil {
+		return !skippedByDefault
+	}
+	if matchesAnyPattern(filter.Exclude, names) {
+		return false
+	}
+	if skippedByDefault {
+		return matchesAnyPattern(filter.Include, names)
+	}
+	return true
+}
+
+// matchesAnyPattern reports whether any name matches any of the glob patterns
+func matchesAnyPattern(patterns []string, names []string) bool {
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if matched, err := path.Match(pattern, name); err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -5,14 +5,25 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"sync"
 )
 
 const hostRootMountPath = "/host/root"
 
-// runHostCommand executes the provided shell command inside the host namespaces
-// using nsenter and the mounted host root filesystem. It returns the combined
-// stdout/stderr output so callers can include detailed error messages.
-func runHostCommand(ctx context.Context, command string) ([]byte, error) {
+// HostRunner runs the shell commands of the checks on the host. The executor uses NsenterRunner;
+// harnesses replace it with SetHostRunner to run the checks against canned command outputs (see
+// pkg/checks/hostfake) without a real node.
+type HostRunner interface {
+	Run(ctx context.Context, command string) ([]byte, error)
+}
+
+// NsenterRunner runs the commands inside the host namespaces using nsenter and the host root
+// filesystem mounted at /host/root
+type NsenterRunner struct{}
+
+// Run executes a shell command on the host. It returns the combined stdout/stderr output so
+// callers can include detailed error messages.
+func (NsenterRunner) Run(ctx context.Context, command string) ([]byte, error) {
 	if _, err := exec.LookPath("nsenter"); err != nil {
 		return nil, fmt.Errorf("nsenter not available: %w", err)
 	}
@@ -29,3 +40,28 @@ func runHostCommand(ctx context.Context, command string) ([]byte, error) {
 	return cmd.CombinedOutput()
 }
 
+var (
+	hostRunnerMu sync.RWMutex
+	hostRunner   HostRunner = NsenterRunner{}
+)
+
+// SetHostRunner replaces the runner of the host commands and returns the previous one, so callers
+// can restore it
+func SetHostRunner(runner HostRunner) HostRunner {
+	hostRunnerMu.Lock()
+	defer hostRunnerMu.Unlock()
+	previous := hostRunner
+	hostRunner = runner
+	resetToolVersions()
+	resetOrphanedMounts()
+	resetSmoothing()
+	return previous
+}
+
+// runHostCommand executes the provided shell command on the host with the current HostRunner
+func runHostCommand(ctx context.Context, command string) ([]byte, error) {
+	hostRunnerMu.RLock()
+	runner := hostRunner
+	hostRunnerMu.RUnlock()
+	return runner.Run(ctx, command)
+}
@@ -0,0 +1,180 @@
+// Package hostfake provides a checks.HostRunner answering with canned command outputs, so the
+// parsers of the checks can be exercised against the output of different tool versions (iostat,
+// df, smartctl, ...) without a real node.
+package hostfake
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrNoResponse is returned for the commands without a canned output. The checks then take their
+// fallback path, as on a node where the host command is not available.
+var ErrNoResponse = errors.New("no canned output")
+
+// Response is the canned result of a command. Commands are matched exactly, after trimming spaces.
+type Response struct {
+	Command string
+	Output  string
+	// Err, when set, makes the command fail with Output as its combined output
+	Err error
+}
+
+// Runner is a checks.HostRunner answering with canned responses. It records the commands it ran.
+type Runner struct {
+	mu        sync.Mutex
+	responses map[string]Response
+	calls     []string
+	unmatched []string
+}
+
+// New returns a Runner answering with responses
+func New(responses ...Response) *Runner {
+	runner := &Runner{responses: make(map[string]Response)}
+	for _, response := range responses {
+		runner.Add(response)
+	}
+	return runner
+}
+
+// Add adds a canned response, replacing the one of the same command
+func (r *Runner) Add(response Response) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses[strings.TrimSpace(response.Command)] = response
+}
+
+// Run returns the canned output of a command
+func (r *Runner) Run(ctx context.Context, command string) ([]byte, error) {
+	command = strings.TrimSpace(command)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, command)
+	response, ok := r.responses[command]
+	if !ok {
+		r.unmatched = append(r.unmatched, command)
+		return nil, fmt.Errorf("%w for %q", ErrNoResponse, command)
+	}
+	return []byte(response.Output), response.Err
+}
+
+// Calls returns the commands run, in order
+func (r *Runner) Calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+// Unmatched returns the commands run without a canned output, in order
+func (r *Runner) Unmatched() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.unmatched...)
+}
+
+// Fixture is a check run against canned outputs, with the status it must report
+type Fixture struct {
+	// Check is the name of the check, as in spec.checkOrder (e.g. "disk_performance")
+	Check string
+	// Expect is the status the check must report
+	Expect string
+	// Description says what the fixture covers (e.g. the tool version)
+	Description string
+	// Expectations is the spec.expectations of the NodeCheck, as JSON, for the checks comparing the
+	// node against it (e.g. sysctl_drift, kernel_cmdline)
+	Expectations string
+	// Sampling is the spec.sampling of the NodeCheck, as JSON, for the checks sampling spiky metrics
+	// (e.g. context_switches, disk_io_wait)
+	Sampling  string
+	Responses []Response
+}
+
+// Runner returns a Runner answering with the responses of the fixture
+func (f *Fixture) Runner() *Runner {
+	return New(f.Responses...)
+}
+
+// LoadFixture reads a fixture file. The file starts with "# key: value" headers (check, expect,
+// description, expectations, sampling), followed by the commands: a "$ <command>" line followed by its output, up to the
+// next "$ " line. An output made of a single "! <message>" line makes the command fail.
+//
+//	# check: disk_space
+//	# expect: Warning
+//	$ df -hPT
+//	Filesystem     Type  Size  Used Avail Use% Mounted on
+//	/dev/sda4      xfs   120G  104G   16G  87% /
+func LoadFixture(path string) (*Fixture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	fixture := &Fixture{}
+	var current *Response
+	var output []string
+	flush := func() {
+		if current == nil {
+			return
+		}
+		// Blank lines separating the commands are not part of the output
+		text := strings.TrimRight(strings.Join(output, "\n"), "\n")
+		if message, ok := strings.CutPrefix(text, "! "); ok && !strings.Contains(text, "\n") {
+			current.Err = errors.New(message)
+			text = message
+		}
+		current.Output = text
+		fixture.Responses = append(fixture.Responses, *current)
+	}
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if command, ok := strings.CutPrefix(text, "$ "); ok {
+			flush()
+			current = &Response{Command: strings.TrimSpace(command)}
+			output = nil
+			continue
+		}
+		if current != nil {
+			output = append(output, text)
+			continue
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		header, ok := strings.CutPrefix(text, "#")
+		key, value, found := strings.Cut(header, ":")
+		if !ok || !found {
+			return nil, fmt.Errorf("%s:%d: expected a \"# key: value\" header or a \"$ command\" line", path, line)
+		}
+		switch strings.TrimSpace(key) {
+		case "check":
+			fixture.Check = strings.TrimSpace(value)
+		case "expect":
+			fixture.Expect = strings.TrimSpace(value)
+		case "description":
+			fixture.Description = strings.TrimSpace(value)
+		case "expectations":
+			fixture.Expectations = strings.TrimSpace(value)
+		case "sampling":
+			fixture.Sampling = strings.TrimSpace(value)
+		default:
+			return nil, fmt.Errorf("%s:%d: unknown header %q", path, line, strings.TrimSpace(key))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	flush()
+	if fixture.Check == "" {
+		return nil, fmt.Errorf("%s: missing the check header", path)
+	}
+	return fixture, nil
+}
@@ -0,0 +1,169 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// hugePagesNUMANode is the huge page pool of the default page size on a NUMA node
+type hugePagesNUMANode struct {
+	Node  int
+	Total int
+	Free  int
+}
+
+// parseNodeList parses a sysfs node list such as "0-1,3"
+func parseNodeList(value string) ([]int, error) {
+	var nodes []int
+	for _, part := range strings.Split(strings.TrimSpace(value), ",") {
+		if part == "" {
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid node list %q", value)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil || end < start {
+				return nil, fmt.Errorf("invalid node list %q", value)
+			}
+		}
+		for node := start; node <= end; node++ {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// readHugePagesNUMA reads the huge page pool of the given page size on each online NUMA node
+func readHugePagesNUMA(ctx context.Context, pageSizeKB int) ([]hugePagesNUMANode, error) {
+	online, err := readProcFile(ctx, "/sys/devices/system/node/online")
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := parseNodeList(string(online))
+	if err != nil {
+		return nil, err
+	}
+	pools := make([]hugePagesNUMANode, 0, len(nodes))
+	for _, node := range nodes {
+		dir := fmt.Sprintf("/sys/devices/system/node/node%d/hugepages/hugepages-%dkB", node, pageSizeKB)
+		pool := hugePagesNUMANode{Node: node}
+		for _, value := range []struct {
+			file  string
+			count *int
+		}{{"nr_hugepages", &pool.Total}, {"free_hugepages", &pool.Free}} {
+			data, err := readProcFile(ctx, dir+"/"+value.file)
+			if err != nil {
+				return nil, err
+			}
+			if *value.count, err = strconv.Atoi(strings.TrimSpace(string(data))); err != nil {
+				return nil, fmt.Errorf("invalid %s/%s: %w", dir, value.file, err)
+			}
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// CheckHugePages checks the huge page pool of the default page size (HugePages_* in /proc/meminfo) and its
+// availability on each NUMA node. DPDK, SAP HANA and NUMA-pinned VMs allocate huge pages from a given NUMA
+// node, so an exhausted pool, a NUMA node without free pages or without pages at all is a Warning.
+func (sc *SystemChecker) CheckHugePages(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	result.Command = "grep HugePages /proc/meminfo; cat /sys/devices/system/node/node*/hugepages/hugepages-*/{nr,free}_hugepages"
+	data, err := readProcFile(ctx, "/proc/meminfo")
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Failed to read /proc/meminfo: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	values := make(map[string]int)
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		key := strings.TrimSuffix(fields[0], ":")
+		if strings.HasPrefix(key, "HugePages_") || key == "Hugepagesize" {
+			if value, err := strconv.Atoi(fields[1]); err == nil {
+				values[key] = value
+			}
+		}
+	}
+	if _, ok := values["HugePages_Total"]; !ok {
+		result.Message = "Huge pages are not supported by the kernel"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	total := values["HugePages_Total"]
+	free := values["HugePages_Free"]
+	reserved := values["HugePages_Rsvd"]
+	pageSizeKB := values["Hugepagesize"]
+	available := free - reserved
+	details["total"] = total
+	details["free"] = free
+	details["reserved"] = reserved
+	details["surplus"] = values["HugePages_Surp"]
+	details["available"] = available
+	details["page_size_kb"] = pageSizeKB
+	details["allocated_mb"] = total * pageSizeKB / 1024
+	details["page_size_bytes"] = pageSizeKB * 1024
+	details["allocated_bytes"] = total * pageSizeKB * 1024
+
+	if total == 0 {
+		result.Status = "Healthy"
+		result.Message = "No huge pages allocated"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	var issues []string
+	if available <= 0 {
+		issues = append(issues, fmt.Sprintf("huge page pool exhausted: all %d pages are in use or reserved", total))
+	}
+	pools, err := readHugePagesNUMA(ctx, pageSizeKB)
+	if err != nil {
+		details["numa_error"] = err.Error()
+	} else {
+		numaNodes := make([]map[string]interface{}, 0, len(pools))
+		for _, pool := range pools {
+			numaNodes = append(numaNodes, map[string]interface{}{"node": pool.Node, "total": pool.Total, "free": pool.Free})
+		}
+		details["numa_nodes"] = numaNodes
+		if len(pools) > 1 {
+			for _, pool := range pools {
+				if pool.Total == 0 {
+					issues = append(issues, fmt.Sprintf("no huge pages allocated on NUMA node %d", pool.Node))
+				} else if pool.Free == 0 && available > 0 {
+					issues = append(issues, fmt.Sprintf("no free huge pages on NUMA node %d", pool.Node))
+				}
+			}
+		}
+	}
+
+	if len(issues) > 0 {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Huge pages: %s", strings.Join(issues, "; "))
+	} else {
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Huge pages: %d of %d available (%d reserved, %d kB pages)", available, total, reserved, pageSizeKB)
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,186 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ingressCanaryNamespace and ingressCanaryRoute identify the canary route created by the OpenShift ingress operator
+	ingressCanaryNamespace = "openshift-ingress-canary"
+	ingressCanaryRoute     = "canary"
+	// ingressRouterNamespace and ingressRouterSelector select the pods of the default router
+	ingressRouterNamespace = "openshift-ingress"
+	ingressRouterSelector  = "ingresscontroller.operator.openshift.io/deployment-ingresscontroller=default"
+)
+
+// validIngressHost restricts the canary host to DNS names, which are safe inside a host shell command
+var validIngressHost = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9.-]*[A-Za-z0-9])?$`)
+
+// ingressCanaryHost returns the host of the OpenShift ingress canary route
+func (kc *KubernetesChecker) ingressCanaryHost(ctx context.Context) (string, error) {
+	gvr := schema.GroupVersionResource{
+		Group:    "route.openshift.io",
+		Version:  "v1",
+		Resource: "routes",
+	}
+	route, err := kc.dynamicClient.Resource(gvr).Namespace(ingressCanaryNamespace).Get(ctx, ingressCanaryRoute, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	host, found, err := unstructured.NestedString(route.Object, "spec", "host")
+	if err != nil || !found || host == "" {
+		return "", fmt.Errorf("route %s/%s has no host", ingressCanaryNamespace, ingressCanaryRoute)
+	}
+	return host, nil
+}
+
+// routerPodIPs returns the IPs of the running default router pods, keyed by pod name
+func (kc *KubernetesChecker) routerPodIPs(ctx context.Context) (map[string]string, error) {
+	pods, err := kc.client.CoreV1().Pods(ingressRouterNamespace).List(ctx, metav1.ListOptions{LabelSelector: ingressRouterSelector})
+	if err != nil {
+		return nil, err
+	}
+	ips := make(map[string]string)
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" {
+			ips[pod.Name] = pod.Status.PodIP
+		}
+	}
+	return ips, nil
+}
+
+// tlsHandshake requests https://host/ (optionally pinned to address) and returns the HTTP status code, the
+// command and whether the TLS handshake completed (any code other than "000": the router answered)
+func tlsHandshake(ctx context.Context, host, address string) (string, string, bool) {
+	resolve := ""
+	if address != "" {
+		hostPort := address
+		if strings.Contains(address, ":") {
+			hostPort = "[" + address + "]"
+		}
+		resolve = fmt.Sprintf(" --resolve %s:443:%s", host, hostPort)
+	}
+	// -k: the handshake is what matters here, the router certificate may not be trusted by the host
+	command := fmt.Sprintf("curl -k -sS -o /dev/null -w '%%{http_code}' --connect-timeout 5 --max-time 10%s https://%s/", resolve, host)
+	// curl exits non-zero on connection errors, the -w output is still the last thing printed
+	output, _ := runHostCommand(ctx, command)
+	code := strings.TrimSpace(string(output))
+	if len(code) > 3 {
+		code = code[len(code)-3:]
+	}
+	if code == "" {
+		code = "000"
+	}
+	return code, command, code != "000"
+}
+
+// CheckIngress verifies that the node can reach the cluster ingress: it completes a TLS handshake for the canary
+// route through the ingress VIP (or the route DNS name) and directly with every default router pod, catching
+// asymmetric routing or per-node firewall rules that only break ingress traffic from some nodes.
+func (nc *NetworkChecker) CheckIngress(ctx context.Context, spec v1alpha1.NetworkChecks) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	kc, err := NewKubernetesChecker(nc.nodeName)
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Unable to create Kubernetes client: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	host := spec.IngressCanaryHost
+	if host == "" {
+		host, err = kc.ingressCanaryHost(ctx)
+		if err != nil {
+			result.Status = "Warning"
+			result.Message = fmt.Sprintf("Unable to discover the ingress canary route (set ingressCanaryHost): %v", err)
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+	}
+	if !validIngressHost.MatchString(host) {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Invalid ingress canary host %q", host)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["canary_host"] = host
+
+	vip := spec.IngressVIP
+	if vip != "" && net.ParseIP(vip) == nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Invalid ingressVIP %q", vip)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	if vip != "" {
+		details["ingress_vip"] = vip
+	}
+
+	var problems []string
+	code, command, ok := tlsHandshake(ctx, host, vip)
+	result.Command = command
+	details["vip_http_code"] = code
+	vipFailed := !ok
+	if vipFailed {
+		target := host
+		if vip != "" {
+			target = vip
+		}
+		problems = append(problems, fmt.Sprintf("TLS handshake for %s via %s failed", host, target))
+	}
+
+	routerIPs, err := kc.routerPodIPs(ctx)
+	if err != nil {
+		details["router_pods_error"] = err.Error()
+	}
+	podNames := make([]string, 0, len(routerIPs))
+	for podName := range routerIPs {
+		podNames = append(podNames, podName)
+	}
+	sort.Strings(podNames)
+	routers := make(map[string]interface{})
+	failedRouters := 0
+	for _, podName := range podNames {
+		ip := routerIPs[podName]
+		code, _, ok := tlsHandshake(ctx, host, ip)
+		routers[podName] = map[string]interface{}{"ip": ip, "http_code": code}
+		if !ok {
+			failedRouters++
+			problems = append(problems, fmt.Sprintf("router %s (%s) unreachable", podName, ip))
+		}
+	}
+	if len(routers) > 0 {
+		details["router_pods"] = routers
+	}
+
+	switch {
+	case len(problems) == 0:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("TLS handshake for %s succeeded via the ingress and %d router pod(s)", host, len(routerIPs))
+	case vipFailed || (len(routerIPs) > 0 && failedRouters == len(routerIPs)):
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Ingress unreachable from the node: %s", strings.Join(problems, "; "))
+	default:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Ingress partially unreachable from the node: %s", strings.Join(problems, "; "))
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,187 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// inotifyUsage is the inotify usage of a user or a process
+type inotifyUsage struct {
+	instances int
+	watches   int
+}
+
+// inotifyProcRoot returns the proc filesystem of the host, or the one of the container when the host root is not mounted
+func inotifyProcRoot() string {
+	if entries, err := os.ReadDir("/host/root/proc/1"); err == nil && len(entries) > 0 {
+		return "/host/root/proc"
+	}
+	return "/proc"
+}
+
+// CheckInotify compares the inotify instances and watches of each user with fs.inotify.max_user_instances and
+// fs.inotify.max_user_watches. Both limits are per user (real UID), so the most loaded user sets the status;
+// reaching them makes inotify_init/inotify_add_watch fail, which breaks the kubelet and many operators.
+func (sc *SystemChecker) CheckInotify(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = "cat /proc/sys/fs/inotify/max_user_instances /proc/sys/fs/inotify/max_user_watches; readlink /proc/*/fd/*; grep inotify /proc/*/fdinfo/*"
+
+	limits := make(map[string]int)
+	for _, name := range []string{"max_user_instances", "max_user_watches"} {
+		data, err := readProcFile(ctx, "/proc/sys/fs/inotify/"+name)
+		if err != nil {
+			result.Message = fmt.Sprintf("Failed to read fs.inotify.%s: %v", name, err)
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+		value, err := strconv.Atoi(strings.TrimSpace(string(data)))
+		if err != nil || value <= 0 {
+			result.Message = fmt.Sprintf("Invalid fs.inotify.%s %q", name, strings.TrimSpace(string(data)))
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+		limits[name] = value
+	}
+	maxInstances, maxWatches := limits["max_user_instances"], limits["max_user_watches"]
+	details["max_user_instances"] = maxInstances
+	details["max_user_watches"] = maxWatches
+
+	procRoot := inotifyProcRoot()
+	entries, err := os.ReadDir(procRoot)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to list processes: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	users := make(map[string]*inotifyUsage)
+	processes := make(map[string]*inotifyUsage)
+	commands := make(map[string]string)
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			break
+		}
+		pid := entry.Name()
+		if _, err := strconv.Atoi(pid); err != nil {
+			continue
+		}
+		// Processes exiting while they are read are skipped
+		fds, err := os.ReadDir(path.Join(procRoot, pid, "fd"))
+		if err != nil {
+			continue
+		}
+		usage := &inotifyUsage{}
+		for _, fd := range fds {
+			target, err := os.Readlink(path.Join(procRoot, pid, "fd", fd.Name()))
+			if err != nil || target != "anon_inode:inotify" {
+				continue
+			}
+			usage.instances++
+			if info, err := os.ReadFile(path.Join(procRoot, pid, "fdinfo", fd.Name())); err == nil {
+				usage.watches += strings.Count(string(info), "inotify wd:")
+			}
+		}
+		if usage.instances == 0 {
+			continue
+		}
+		status, err := os.ReadFile(path.Join(procRoot, pid, "status"))
+		if err != nil {
+			continue
+		}
+		uid := ""
+		for _, line := range strings.Split(string(status), "\n") {
+			if value, ok := strings.CutPrefix(line, "Uid:"); ok {
+				if fields := strings.Fields(value); len(fields) > 0 {
+					uid = fields[0]
+				}
+			} else if value, ok := strings.CutPrefix(line, "Name:"); ok {
+				commands[pid] = strings.TrimSpace(value)
+			}
+		}
+		if users[uid] == nil {
+			users[uid] = &inotifyUsage{}
+		}
+		users[uid].instances += usage.instances
+		users[uid].watches += usage.watches
+		processes[pid] = usage
+	}
+
+	warningPercent, criticalPercent := usageThresholds(sc.thresholds, "inotify", 75, 90)
+	details["warning_threshold"] = warningPercent
+	details["critical_threshold"] = criticalPercent
+
+	var userDetails []map[string]interface{}
+	worstUsage := 0.0
+	worstMessage := ""
+	for uid, usage := range users {
+		instancesPercent := float64(usage.instances) * 100 / float64(maxInstances)
+		watchesPercent := float64(usage.watches) * 100 / float64(maxWatches)
+		userDetails = append(userDetails, map[string]interface{}{
+			"uid":               uid,
+			"instances":         usage.instances,
+			"watches":           usage.watches,
+			"instances_percent": instancesPercent,
+			"watches_percent":   watchesPercent,
+		})
+		if instancesPercent > worstUsage {
+			worstUsage = instancesPercent
+			worstMessage = fmt.Sprintf("UID %s uses %d/%d inotify instances (%.1f%%)", uid, usage.instances, maxInstances, instancesPercent)
+		}
+		if watchesPercent > worstUsage {
+			worstUsage = watchesPercent
+			worstMessage = fmt.Sprintf("UID %s uses %d/%d inotify watches (%.1f%%)", uid, usage.watches, maxWatches, watchesPercent)
+		}
+	}
+	sort.Slice(userDetails, func(i, j int) bool {
+		return userDetails[i]["watches"].(int) > userDetails[j]["watches"].(int)
+	})
+	details["users"] = userDetails
+
+	// The processes holding the most watches point at the workload to fix or to move
+	pids := make([]string, 0, len(processes))
+	for pid := range processes {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool { return processes[pids[i]].watches > processes[pids[j]].watches })
+	if len(pids) > 5 {
+		pids = pids[:5]
+	}
+	var topProcesses []map[string]interface{}
+	for _, pid := range pids {
+		topProcesses = append(topProcesses, map[string]interface{}{
+			"pid":       pid,
+			"command":   commands[pid],
+			"instances": processes[pid].instances,
+			"watches":   processes[pid].watches,
+		})
+	}
+	details["top_processes"] = topProcesses
+
+	switch {
+	case len(users) == 0:
+		result.Status = "Healthy"
+		result.Message = "No inotify instance in use"
+	case worstUsage >= float64(criticalPercent):
+		result.Status = "Critical"
+		result.Message = worstMessage + ", raise fs.inotify limits or reduce the watchers"
+	case worstUsage >= float64(warningPercent):
+		result.Status = "Warning"
+		result.Message = worstMessage
+	default:
+		result.Status = "Healthy"
+		result.Message = worstMessage
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,143 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// kdumpMinFreeRatio is the share of the node memory that must be free on a local dump target. makedumpfile
+// filters and compresses the vmcore, which usually ends up well below a tenth of the memory.
+const kdumpMinFreeRatio = 10
+
+// validKdumpPath matches the dump paths of /etc/kdump.conf passed to df
+var validKdumpPath = regexp.MustCompile(`^/[A-Za-z0-9._/-]*$`)
+
+// kdumpConfig is the dump target of /etc/kdump.conf
+type kdumpConfig struct {
+	// path is the directory of the vmcores (default /var/crash)
+	path string
+	// target is the target directive (nfs, ssh, xfs, ext4, raw, ...) with its value, empty for the root filesystem
+	target string
+}
+
+// parseKdumpConfig reads the path and target directives of /etc/kdump.conf
+func parseKdumpConfig(data string) kdumpConfig {
+	config := kdumpConfig{path: "/var/crash"}
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "path":
+			config.path = fields[1]
+		case "nfs", "ssh", "raw", "ext2", "ext3", "ext4", "xfs", "btrfs", "minix", "virtiofs":
+			config.target = fields[0] + " " + fields[1]
+		}
+	}
+	return config
+}
+
+// CheckKdump checks that the node can capture a vmcore after a kernel panic: memory is reserved for the
+// crash kernel (crashkernel= boot parameter), kdump.service is active and has loaded the crash kernel,
+// and a dump target on the root filesystem has at least a tenth of the node memory free. Remote and
+// dedicated dump targets are reported without checking their space.
+func (sc *SystemChecker) CheckKdump(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = "cat /sys/kernel/kexec_crash_size /sys/kernel/kexec_crash_loaded /etc/kdump.conf; systemctl is-active kdump; df -Pk <path>"
+
+	var problems []string
+	data, err := readProcFile(ctx, "/sys/kernel/kexec_crash_size")
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to read the crash kernel reservation: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	crashSize, _ := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	details["crashkernel_reserved_bytes"] = crashSize
+	if cmdline, err := readProcFile(ctx, "/proc/cmdline"); err == nil {
+		for _, arg := range strings.Fields(string(cmdline)) {
+			if strings.HasPrefix(arg, "crashkernel=") {
+				details["crashkernel_parameter"] = arg
+			}
+		}
+	}
+	if crashSize == 0 {
+		problems = append(problems, "no memory reserved for the crash kernel (crashkernel= boot parameter)")
+	}
+
+	output, err := runHostCommand(ctx, "systemctl is-active kdump || true")
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to query systemd: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	state := strings.TrimSpace(string(output))
+	details["service_state"] = state
+	if state != "active" {
+		problems = append(problems, fmt.Sprintf("kdump.service is %s", state))
+	}
+	if data, err := readProcFile(ctx, "/sys/kernel/kexec_crash_loaded"); err == nil {
+		loaded := strings.TrimSpace(string(data)) == "1"
+		details["crash_kernel_loaded"] = loaded
+		if !loaded && state == "active" {
+			problems = append(problems, "the crash kernel is not loaded")
+		}
+	}
+
+	config := kdumpConfig{path: "/var/crash"}
+	if data, err := runHostCommand(ctx, "cat /etc/kdump.conf"); err == nil {
+		config = parseKdumpConfig(string(data))
+	}
+	details["dump_path"] = config.path
+	if config.target != "" {
+		details["dump_target"] = config.target
+		details["dump_target_note"] = "Dump target not on the root filesystem, its free space is not checked"
+	} else if !validKdumpPath.MatchString(config.path) {
+		problems = append(problems, fmt.Sprintf("invalid dump path %q", config.path))
+	} else if total, _, _, _, _, _, err := readMemInfo(ctx); err == nil {
+		// The dump directory may not exist until the first vmcore: use its closest existing parent
+		for dir := config.path; ; dir = path.Dir(dir) {
+			dfOutput, err := runHostCommand(ctx, fmt.Sprintf("df -Pk %s 2>/dev/null", dir))
+			lines := strings.Split(strings.TrimSpace(string(dfOutput)), "\n")
+			if err == nil && len(lines) >= 2 {
+				if fields := strings.Fields(lines[len(lines)-1]); len(fields) >= 6 {
+					availableKB, _ := strconv.ParseInt(fields[3], 10, 64)
+					required := total / kdumpMinFreeRatio
+					details["dump_filesystem"] = fields[5]
+					details["dump_free_bytes"] = availableKB * 1024
+					details["dump_required_bytes"] = required
+					if availableKB*1024 < required {
+						problems = append(problems, fmt.Sprintf("only %d MiB free on %s for the vmcore, %d MiB required",
+							availableKB/1024, fields[5], required/(1024*1024)))
+					}
+				}
+				break
+			}
+			if dir == "/" {
+				break
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Node not ready to capture crash dumps: %s", strings.Join(problems, "; "))
+	} else {
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("kdump ready: %d MiB reserved for the crash kernel, dumps to %s", crashSize/(1024*1024), config.path)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
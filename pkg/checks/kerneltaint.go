@@ -0,0 +1,113 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// kernelTaint is a bit of /proc/sys/kernel/tainted
+type kernelTaint struct {
+	bit         uint
+	flag        string
+	description string
+}
+
+// kernelTaints are the taint bits documented in the kernel admin guide (tainted-kernels)
+var kernelTaints = []kernelTaint{
+	{0, "P", "proprietary module loaded"},
+	{1, "F", "module force loaded"},
+	{2, "S", "kernel running on an out of specification system"},
+	{3, "R", "module force unloaded"},
+	{4, "M", "machine check exception occurred"},
+	{5, "B", "bad page referenced or unexpected page flags"},
+	{6, "U", "taint requested by userspace"},
+	{7, "D", "kernel died recently (OOPS or BUG)"},
+	{8, "A", "ACPI table overridden by user"},
+	{9, "W", "kernel issued a warning"},
+	{10, "C", "staging driver loaded"},
+	{11, "I", "workaround for a platform firmware bug applied"},
+	{12, "O", "externally-built (out-of-tree) module loaded"},
+	{13, "E", "unsigned module loaded"},
+	{14, "L", "soft lockup occurred"},
+	{15, "K", "kernel live patched"},
+	{16, "X", "auxiliary taint (distribution defined)"},
+	{17, "T", "kernel built with the struct randomization plugin"},
+	{18, "N", "in-kernel test run"},
+}
+
+// CheckKernelTaint decodes the kernel taint flags. Machine check exceptions and bad pages point to
+// failing hardware and are Critical; oopses, warnings, soft lockups and forced module loads/unloads
+// are Warning; the other taints (proprietary, out-of-tree, unsigned modules, live patches) are
+// expected on many nodes and only reported.
+func (sc *SystemChecker) CheckKernelTaint(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	result.Command = "cat /proc/sys/kernel/tainted"
+	data, err := readProcFile(ctx, "/proc/sys/kernel/tainted")
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Failed to read /proc/sys/kernel/tainted: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Invalid /proc/sys/kernel/tainted value: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["taint_value"] = value
+
+	var flags, descriptions, critical, warning []string
+	known := uint64(0)
+	for _, taint := range kernelTaints {
+		known |= 1 << taint.bit
+		if value&(1<<taint.bit) == 0 {
+			continue
+		}
+		flags = append(flags, taint.flag)
+		descriptions = append(descriptions, fmt.Sprintf("%s: %s", taint.flag, taint.description))
+		switch taint.flag {
+		case "M", "B":
+			critical = append(critical, taint.description)
+		case "D", "W", "L", "F", "R":
+			warning = append(warning, taint.description)
+		}
+	}
+	if unknown := value &^ known; unknown != 0 {
+		details["unknown_taint_bits"] = fmt.Sprintf("0x%x", unknown)
+	}
+	details["taint_flags"] = strings.Join(flags, "")
+	if len(descriptions) > 0 {
+		details["taints"] = descriptions
+	}
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Kernel tainted by hardware errors: %s", strings.Join(critical, ", "))
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Kernel tainted: %s", strings.Join(warning, ", "))
+	case value == 0:
+		result.Status = "Healthy"
+		result.Message = "Kernel is not tainted"
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Kernel tainted (%d: %s), no error taints", value, strings.Join(flags, ""))
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
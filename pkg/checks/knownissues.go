@@ -0,0 +1,190 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/rulepacks"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetRules applies the rules of the rule packs: the log patterns of the system_logs check and the
+// signatures of the known_issues check
+func (sc *SystemChecker) SetRules(rules *rulepacks.Rules) {
+	sc.rules = rules
+}
+
+// matchLogPatterns matches log lines with the log patterns of the rule packs. It returns a match per
+// pattern, with the first matching line and the number of matching lines, and whether a Critical
+// pattern matched.
+func (sc *SystemChecker) matchLogPatterns(lines []string) ([]map[string]interface{}, bool) {
+	if sc.rules == nil {
+		return nil, false
+	}
+	var matches []map[string]interface{}
+	critical := false
+	for _, pattern := range sc.rules.LogPatterns {
+		count := 0
+		first := ""
+		for _, line := range lines {
+			if pattern.Regexp.MatchString(line) {
+				if count == 0 {
+					first = line
+				}
+				count++
+			}
+		}
+		if count == 0 {
+			continue
+		}
+		matches = append(matches, map[string]interface{}{
+			"rule":     pattern.Name,
+			"pack":     pattern.Pack,
+			"severity": pattern.Severity,
+			"message":  pattern.Message,
+			"line":     first,
+			"count":    count,
+		})
+		if pattern.Severity == "Critical" {
+			critical = true
+		}
+	}
+	return matches, critical
+}
+
+// osPrettyName returns PRETTY_NAME of /etc/os-release (e.g. "Red Hat Enterprise Linux CoreOS 415.92...")
+func osPrettyName(osRelease string) string {
+	for _, line := range strings.Split(osRelease, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "PRETTY_NAME="); ok {
+			return strings.Trim(value, `"'`)
+		}
+	}
+	return ""
+}
+
+// CheckKnownIssues evaluates the known-issue signatures of the rule packs against the kernel version, the
+// OS image and the system journal of the last hour (warning priority and above). Each matching signature
+// is reported with its advisory; the status is the highest severity of the matches.
+func (sc *SystemChecker) CheckKnownIssues(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = "uname -r; cat /etc/os-release; journalctl --no-pager -p warning --since '1 hour ago' --no-hostname"
+
+	rules := sc.rules
+	if rules == nil {
+		rules = &rulepacks.Rules{}
+	}
+	details["rule_packs"] = strings.Join(rules.Packs, ", ")
+	if len(rules.Errors) > 0 {
+		details["rule_pack_errors"] = rules.Errors
+	}
+	details["signatures"] = len(rules.KnownIssues)
+	if len(rules.KnownIssues) == 0 {
+		result.Status = "Healthy"
+		result.Message = "No known-issue signatures loaded"
+		if len(rules.Errors) > 0 {
+			result.Status = "Warning"
+			result.Message = fmt.Sprintf("No known-issue signatures loaded, %d invalid rule packs", len(rules.Errors))
+		}
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	kernelOutput, err := runHostCommand(ctx, "uname -r")
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to read the kernel version: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	kernelVersion := strings.TrimSpace(string(kernelOutput))
+	osImage := ""
+	if osRelease, err := runHostCommand(ctx, "cat /etc/os-release"); err == nil {
+		osImage = osPrettyName(string(osRelease))
+	}
+	details["kernel_version"] = kernelVersion
+	details["os_image"] = osImage
+
+	// The journal is only read when a signature matching the node needs it
+	var candidates []rulepacks.CompiledKnownIssue
+	needsJournal := false
+	for _, issue := range rules.KnownIssues {
+		if issue.Matches(kernelVersion, osImage) {
+			candidates = append(candidates, issue)
+			needsJournal = needsJournal || issue.LogRe != nil
+		}
+	}
+	var journal []string
+	if needsJournal {
+		available, output, err := checkSystemdAvailable(ctx, "journalctl --no-pager -p warning --since '1 hour ago' --no-hostname")
+		if available && err == nil {
+			journal = strings.Split(string(output), "\n")
+		} else {
+			details["journal_note"] = "System journal not available, signatures with a log pattern are not evaluated"
+		}
+	}
+
+	var matches []map[string]interface{}
+	var critical, warning int
+	for _, issue := range candidates {
+		match := map[string]interface{}{
+			"id":       issue.ID,
+			"title":    issue.Title,
+			"pack":     issue.Pack,
+			"severity": issue.Severity,
+		}
+		if issue.URL != "" {
+			match["url"] = issue.URL
+		}
+		if issue.Remediation != "" {
+			match["remediation"] = issue.Remediation
+		}
+		if issue.LogRe != nil {
+			line := ""
+			for _, l := range journal {
+				if issue.LogRe.MatchString(l) {
+					line = strings.TrimSpace(l)
+					break
+				}
+			}
+			if line == "" {
+				continue
+			}
+			match["log_line"] = line
+		}
+		matches = append(matches, match)
+		if issue.Severity == "Critical" {
+			critical++
+		} else {
+			warning++
+		}
+	}
+	details["matches"] = matches
+
+	switch {
+	case critical > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("%d known issues match this node (%d Critical): %s", len(matches), critical, knownIssueTitles(matches))
+	case warning > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("%d known issues match this node: %s", len(matches), knownIssueTitles(matches))
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("No known issue matches this node (%d signatures evaluated)", len(rules.KnownIssues))
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
+
+// knownIssueTitles lists the ids and titles of the matching known issues
+func knownIssueTitles(matches []map[string]interface{}) string {
+	titles := make([]string, 0, len(matches))
+	for _, match := range matches {
+		titles = append(titles, fmt.Sprintf("%s (%s)", match["id"], match["title"]))
+	}
+	return strings.Join(titles, ", ")
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/albertofilice/node-check-operator/api/v1alpha1"
 	discoveryv1 "k8s.io/api/discovery/v1"
@@ -25,10 +26,22 @@ type KubernetesChecker struct {
 	dynamicClient dynamic.Interface
 	metricsClient *metricsclient.Clientset
 	isOpenShift   *bool // Cached OpenShift detection result
+	isOpenShiftMu sync.Mutex // Guards isOpenShift, checks may run concurrently
+	namespaces    *v1alpha1.FilterPatterns
+}
+
+// SetFilters applies the namespace filter from spec.filters
+func (kc *KubernetesChecker) SetFilters(filters *v1alpha1.CheckFilters) {
+	if filters == nil {
+		return
+	}
+	kc.namespaces = filters.Namespaces
 }
 
 // isOpenShiftCluster detects if we're running on OpenShift
 func (kc *KubernetesChecker) isOpenShiftCluster(ctx context.Context) bool {
+	kc.isOpenShiftMu.Lock()
+	defer kc.isOpenShiftMu.Unlock()
 	if kc.isOpenShift != nil {
 		return *kc.isOpenShift
 	}
@@ -212,6 +225,11 @@ func (kc *KubernetesChecker) CheckPods(ctx context.Context) *v1alpha1.CheckResul
 	crashLoopPods := []string{}
 
 	for _, pod := range pods.Items {
+		// Skip pods in namespaces excluded by spec.filters
+		if !filterAllows(kc.namespaces, false, pod.Namespace) {
+			continue
+		}
+
 		podInfo := map[string]interface{}{
 			"name":         pod.Name,
 			"namespace":    pod.Namespace,
@@ -303,6 +321,13 @@ func (kc *KubernetesChecker) CheckServices(ctx context.Context) *v1alpha1.CheckR
 			"external_ips": service.Spec.ExternalIPs,
 		}
 
+		// Skip services in namespaces excluded by spec.filters
+		if !filterAllows(kc.namespaces, false, service.Namespace) {
+			serviceInfo["skip_reason"] = "Namespace excluded by filters"
+			serviceDetails = append(serviceDetails, serviceInfo)
+			continue
+		}
+
 		// Skip ExternalName services - they don't have endpoints by design
 		if service.Spec.Type == "ExternalName" {
 			serviceInfo["skip_reason"] = "ExternalName service"
@@ -408,13 +433,18 @@ func (kc *KubernetesChecker) CheckServices(ctx context.Context) *v1alpha1.CheckR
 				"cert-manager-operator",
 				"open-cluster-management",
 			}
+			operatorNamespace := false
 			for _, ns := range operatorNamespaces {
 				if service.Namespace == ns {
-					shouldWarn = false
-					serviceInfo["skip_reason"] = "Operator namespace service"
+					operatorNamespace = true
 					break
 				}
 			}
+			// spec.filters.namespaces.include re-enables the check for operator namespaces
+			if !filterAllows(kc.namespaces, operatorNamespace, service.Namespace) {
+				shouldWarn = false
+				serviceInfo["skip_reason"] = "Operator namespace service"
+			}
 			
 			if shouldWarn {
 				servicesWithoutEndpoints = append(servicesWithoutEndpoints, fmt.Sprintf("%s/%s", service.Namespace, service.Name))
@@ -643,19 +673,28 @@ func (kc *KubernetesChecker) CheckNodeResources(ctx context.Context) *v1alpha1.C
 
 	// Build details
 	details["node_name"] = node.Name
+	// The quantities are also set as numbers in base units (cpu_millicores, memory_bytes)
 	details["capacity"] = map[string]interface{}{
 		"cpu":    nodeCapacityCPU.String(),
 		"memory": nodeCapacityMemory.String(),
+		"cpu_millicores": capacityCPUMilli,
+		"memory_bytes":   capacityMemoryBytes,
 	}
 	details["allocatable"] = map[string]interface{}{
 		"cpu":    nodeAllocatableCPU.String(),
 		"memory": nodeAllocatableMemory.String(),
+		"cpu_millicores": nodeAllocatableCPU.MilliValue(),
+		"memory_bytes":   nodeAllocatableMemory.Value(),
 	}
 	details["allocated"] = map[string]interface{}{
 		"cpu_requests":     fmt.Sprintf("%dm", totalCPURequestsMilli),
 		"cpu_limits":       fmt.Sprintf("%dm", totalCPULimitsMilli),
 		"memory_requests":  fmt.Sprintf("%d", totalMemoryRequestsBytes),
 		"memory_limits":    fmt.Sprintf("%d", totalMemoryLimitsBytes),
+		"cpu_requests_millicores": totalCPURequestsMilli,
+		"cpu_limits_millicores":   totalCPULimitsMilli,
+		"memory_requests_bytes":   totalMemoryRequestsBytes,
+		"memory_limits_bytes":     totalMemoryLimitsBytes,
 	}
 	details["percentages"] = map[string]interface{}{
 		"cpu_request_percent":    cpuRequestPercent,
@@ -705,7 +744,7 @@ func (kc *KubernetesChecker) CheckNodeResources(ctx context.Context) *v1alpha1.C
 // CheckNodeResourceUsage checks actual real-time CPU and memory consumption using Metrics API
 // This provides actual usage metrics, unlike CheckNodeResources which shows allocations
 func (kc *KubernetesChecker) CheckNodeResourceUsage(ctx context.Context) *v1alpha1.CheckResult {
-	details := make(map[string]interface{})
+	details := v1alpha1.NodeResourceUsageDetails{}
 	result := &v1alpha1.CheckResult{
 		Timestamp: metav1.Now(),
 		Status:    "Unknown",
@@ -713,7 +752,7 @@ func (kc *KubernetesChecker) CheckNodeResourceUsage(ctx context.Context) *v1alph
 
 	// Detect if we're on OpenShift (which has metrics-server built-in)
 	isOpenShift := kc.isOpenShiftCluster(ctx)
-	details["is_openshift"] = isOpenShift
+	details.IsOpenShift = isOpenShift
 	
 	if isOpenShift {
 		result.Command = fmt.Sprintf("oc adm top node %s", kc.nodeName)
@@ -727,13 +766,13 @@ func (kc *KubernetesChecker) CheckNodeResourceUsage(ctx context.Context) *v1alph
 		result.Status = "Warning"
 		if isOpenShift {
 			result.Message = fmt.Sprintf("Metrics API not available: %v (OpenShift should have metrics-server by default, check if it's running)", err)
-			details["note"] = "OpenShift includes metrics-server by default. If this check fails, verify that the metrics-server pods are running in the openshift-monitoring namespace."
+			details.Note = "OpenShift includes metrics-server by default. If this check fails, verify that the metrics-server pods are running in the openshift-monitoring namespace."
 		} else {
 			result.Message = fmt.Sprintf("Metrics API not available: %v (metrics-server may not be installed or accessible)", err)
-			details["note"] = "Node resource usage requires metrics-server to be installed. Install with: kubectl apply -f https://github.com/kubernetes-sigs/metrics-server/releases/latest/download/components.yaml"
+			details.Note = "Node resource usage requires metrics-server to be installed. Install with: kubectl apply -f https://github.com/kubernetes-sigs/metrics-server/releases/latest/download/components.yaml"
 		}
-		details["error"] = err.Error()
-		result.Details = mapToRawExtension(details)
+		details.Error = err.Error()
+		result.Details = typedToRawExtension(details)
 		return result
 	}
 
@@ -745,8 +784,8 @@ func (kc *KubernetesChecker) CheckNodeResourceUsage(ctx context.Context) *v1alph
 	if !cpuOk || !memoryOk {
 		result.Status = "Warning"
 		result.Message = "Failed to parse metrics response (unexpected format)"
-		details["error"] = "Metrics API returned unexpected format"
-		result.Details = mapToRawExtension(details)
+		details.Error = "Metrics API returned unexpected format"
+		result.Details = typedToRawExtension(details)
 		return result
 	}
 
@@ -758,8 +797,8 @@ func (kc *KubernetesChecker) CheckNodeResourceUsage(ctx context.Context) *v1alph
 	if err != nil {
 		result.Status = "Warning"
 		result.Message = fmt.Sprintf("Failed to get node information: %v", err)
-		details["error"] = err.Error()
-		result.Details = mapToRawExtension(details)
+		details.Error = err.Error()
+		result.Details = typedToRawExtension(details)
 		return result
 	}
 
@@ -783,20 +822,23 @@ func (kc *KubernetesChecker) CheckNodeResourceUsage(ctx context.Context) *v1alph
 	}
 
 	// Build details
-	details["node_name"] = kc.nodeName
-	details["cpu_usage"] = map[string]interface{}{
-		"cores":    fmt.Sprintf("%dm", cpuUsageMilli),
-		"percent":  cpuUsagePercent,
-		"capacity": nodeCapacityCPU.String(),
-	}
-	details["memory_usage"] = map[string]interface{}{
-		"bytes":    memoryUsageBytes,
-		"human":    memoryUsage.String(),
-		"percent":  memoryUsagePercent,
-		"capacity": nodeCapacityMemory.String(),
-	}
-	details["check_method"] = "Metrics API (metrics-server)"
-	details["note"] = "These values represent ACTUAL real-time consumption, not allocations. Compare with NodeResources check to see allocation vs usage."
+	details.NodeName = kc.nodeName
+	details.CPUUsage = &v1alpha1.CPUUsage{
+		Cores:    fmt.Sprintf("%dm", cpuUsageMilli),
+		Percent:  cpuUsagePercent,
+		Capacity: nodeCapacityCPU.String(),
+		Millicores:         cpuUsageMilli,
+		CapacityMillicores: nodeCapacityCPU.MilliValue(),
+	}
+	details.MemoryUsage = &v1alpha1.MemoryUsage{
+		Bytes:    memoryUsageBytes,
+		Human:    memoryUsage.String(),
+		Percent:  memoryUsagePercent,
+		Capacity: nodeCapacityMemory.String(),
+		CapacityBytes: nodeCapacityMemory.Value(),
+	}
+	details.CheckMethod = "Metrics API (metrics-server)"
+	details.Note = "These values represent ACTUAL real-time consumption, not allocations. Compare with NodeResources check to see allocation vs usage."
 
 	// Determine status based on actual usage
 	issues := []string{}
@@ -818,7 +860,7 @@ func (kc *KubernetesChecker) CheckNodeResourceUsage(ctx context.Context) *v1alph
 		result.Message = fmt.Sprintf("Resource usage is normal (CPU: %.1f%%, Memory: %.1f%%)", cpuUsagePercent, memoryUsagePercent)
 	}
 
-	result.Details = mapToRawExtension(details)
+	result.Details = typedToRawExtension(details)
 	return result
 }
 
@@ -0,0 +1,200 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the process_limits check. The fourth field of /proc/loadavg counts the tasks
+// (processes and threads) of the host, each holding a PID. The second command prints, for the kubelet
+// and the container runtime, "<name> <pid> <open fds> <threads> <nofile soft limit> <nproc soft limit>".
+const (
+	pidLimitsCommand     = "cat /proc/sys/kernel/pid_max /proc/sys/kernel/threads-max /proc/loadavg"
+	processLimitsCommand = `for name in kubelet crio containerd; do pid=$(pgrep -xo "$name") || continue; ` +
+		`echo "$name $pid $(ls /proc/$pid/fd | wc -l) $(ls /proc/$pid/task | wc -l) ` +
+		`$(awk '/^Max open files/ {print $4}' /proc/$pid/limits) $(awk '/^Max processes/ {print $3}' /proc/$pid/limits)"; done; true`
+)
+
+// processLimits is a line of processLimitsCommand
+type processLimits struct {
+	name    string
+	pid     int
+	openFDs int
+	threads int
+	// nofile and nproc are the soft limits, -1 when unlimited
+	nofile int64
+	nproc  int64
+}
+
+// pidLimits are the task count of the host and the kernel limits on it
+type pidLimits struct {
+	pidMax     int64
+	threadsMax int64
+	tasks      int64
+}
+
+// readPIDLimits runs pidLimitsCommand and parses its output
+func readPIDLimits(ctx context.Context) (pidLimits, error) {
+	output, err := runHostCommand(ctx, pidLimitsCommand)
+	if err != nil {
+		return pidLimits{}, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	if len(lines) < 3 {
+		return pidLimits{}, fmt.Errorf("unexpected output of kernel.pid_max, kernel.threads-max and /proc/loadavg")
+	}
+	pidMax, err1 := strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64)
+	threadsMax, err2 := strconv.ParseInt(strings.TrimSpace(lines[1]), 10, 64)
+	var tasks int64
+	var err3 error = fmt.Errorf("no task count")
+	if fields := strings.Fields(lines[2]); len(fields) >= 4 {
+		if _, total, ok := strings.Cut(fields[3], "/"); ok {
+			tasks, err3 = strconv.ParseInt(total, 10, 64)
+		}
+	}
+	if err1 != nil || err2 != nil || err3 != nil || pidMax <= 0 || threadsMax <= 0 {
+		return pidLimits{}, fmt.Errorf("unable to parse %q", strings.Join(lines, " "))
+	}
+	return pidLimits{pidMax: pidMax, threadsMax: threadsMax, tasks: tasks}, nil
+}
+
+// limit returns the lower of kernel.pid_max and kernel.threads-max, with its name
+func (l pidLimits) limit() (int64, string) {
+	if l.threadsMax < l.pidMax {
+		return l.threadsMax, "kernel.threads-max"
+	}
+	return l.pidMax, "kernel.pid_max"
+}
+
+// usage returns the percentage of the PID limit used by the tasks
+func (l pidLimits) usage() float64 {
+	limit, _ := l.limit()
+	return 100 * float64(l.tasks) / float64(limit)
+}
+
+// parseLimit parses a limit of /proc/<pid>/limits, "unlimited" being -1
+func parseLimit(value string) (int64, error) {
+	if value == "unlimited" {
+		return -1, nil
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// parseProcessLimits parses the output of processLimitsCommand, skipping the processes that exited
+// while they were read
+func parseProcessLimits(output string) []processLimits {
+	var processes []processLimits
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 6 {
+			continue
+		}
+		pid, err1 := strconv.Atoi(fields[1])
+		openFDs, err2 := strconv.Atoi(fields[2])
+		threads, err3 := strconv.Atoi(fields[3])
+		nofile, err4 := parseLimit(fields[4])
+		nproc, err5 := parseLimit(fields[5])
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil || err5 != nil {
+			continue
+		}
+		processes = append(processes, processLimits{name: fields[0], pid: pid, openFDs: openFDs, threads: threads, nofile: nofile, nproc: nproc})
+	}
+	return processes
+}
+
+// CheckProcessLimits warns before the node runs out of PIDs or the kubelet and the container runtime
+// run out of file descriptors, both of which stop new pods from starting (fork fails with EAGAIN, and
+// the runtime cannot open the sockets and logs of the containers). The PID usage is the number of tasks
+// against kernel.pid_max and kernel.threads-max, whichever is lower; the file descriptor usage is the
+// open descriptors of kubelet, crio and containerd against their nofile soft limit. Both use the
+// process_limits thresholds (75/90 by default) and the most loaded of them sets the status.
+func (sc *SystemChecker) CheckProcessLimits(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = fmt.Sprintf("%s; %s", pidLimitsCommand, processLimitsCommand)
+
+	limits, err := readPIDLimits(ctx)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to read the PID limits: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	tasks := limits.tasks
+	pidLimit, pidLimitName := limits.limit()
+	pidUsage := limits.usage()
+	details["pid_max"] = limits.pidMax
+	details["threads_max"] = limits.threadsMax
+	details["tasks"] = tasks
+	details["pid_limit"] = pidLimitName
+	details["pid_usage_percent"] = pidUsage
+
+	warningPercent, criticalPercent := usageThresholds(sc.thresholds, "process_limits", 75, 90)
+	details["warning_threshold"] = warningPercent
+	details["critical_threshold"] = criticalPercent
+
+	var problems []string
+	worst := pidUsage
+	processCount := 0
+	if pidUsage > float64(warningPercent) {
+		problems = append(problems, fmt.Sprintf("%d tasks use %.1f%% of %s (%d)", tasks, pidUsage, pidLimitName, pidLimit))
+	}
+
+	// The file descriptors are best effort: the PID usage is still reported without them
+	output, err := runHostCommand(ctx, processLimitsCommand)
+	if err != nil {
+		details["process_limits_error"] = err.Error()
+	} else {
+		processes := parseProcessLimits(string(output))
+		sort.Slice(processes, func(i, j int) bool { return processes[i].name < processes[j].name })
+		processDetails := make([]map[string]interface{}, 0, len(processes))
+		for _, process := range processes {
+			entry := map[string]interface{}{
+				"name":     process.name,
+				"pid":      process.pid,
+				"open_fds": process.openFDs,
+				"threads":  process.threads,
+				"nofile":   process.nofile,
+				"nproc":    process.nproc,
+			}
+			if process.nofile > 0 {
+				fdUsage := 100 * float64(process.openFDs) / float64(process.nofile)
+				entry["fd_usage_percent"] = fdUsage
+				if fdUsage > worst {
+					worst = fdUsage
+				}
+				if fdUsage > float64(warningPercent) {
+					problems = append(problems, fmt.Sprintf("%s has %d open files, %.1f%% of its nofile limit (%d)", process.name, process.openFDs, fdUsage, process.nofile))
+				}
+			}
+			processDetails = append(processDetails, entry)
+		}
+		details["processes"] = processDetails
+		processCount = len(processes)
+	}
+
+	switch {
+	case worst > float64(criticalPercent):
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Close to the process limits, new pods may fail to start: %s", strings.Join(problems, "; "))
+	case worst > float64(warningPercent):
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("High process limits usage: %s", strings.Join(problems, "; "))
+	case processCount == 0:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("%d tasks, %.1f%% of %s; no kubelet or container runtime process found to check the file descriptors", tasks, pidUsage, pidLimitName)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("%d tasks, %.1f%% of %s; kubelet and container runtime file descriptors within their limits", tasks, pidUsage, pidLimitName)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
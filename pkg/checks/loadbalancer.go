@@ -0,0 +1,188 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// kubeProxyHealthzPort is the port of the kube-proxy (or ovnkube-node) healthz endpoint probed by
+	// cloud load balancers for services with externalTrafficPolicy Cluster
+	kubeProxyHealthzPort = 10256
+	// lbHealthCheckMaxServices bounds the number of LoadBalancer services probed per run
+	lbHealthCheckMaxServices = 20
+	// lbProbeTimeout bounds every single probe
+	lbProbeTimeout = 3 * time.Second
+)
+
+// nodeInternalIP returns the InternalIP of the node, which is the address cloud load balancers target
+func nodeInternalIP(node *corev1.Node) string {
+	for _, address := range node.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			return address.Address
+		}
+	}
+	return ""
+}
+
+// probeHealthz requests http://address:port/healthz and returns the HTTP status code.
+// The executor runs in the host network, so this is the path the load balancer probe takes.
+func probeHealthz(ctx context.Context, address string, port int32) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, lbProbeTimeout)
+	defer cancel()
+	url := fmt.Sprintf("http://%s/healthz", net.JoinHostPort(address, strconv.Itoa(int(port))))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+// probeTCP opens a TCP connection to address:port, as load balancers using TCP health checks do
+func probeTCP(ctx context.Context, address string, port int32) error {
+	dialer := net.Dialer{Timeout: lbProbeTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address, strconv.Itoa(int(port))))
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// CheckLBHealthCheck validates the ports cloud load balancers probe on the node: the kube-proxy healthz
+// endpoint, the healthCheckNodePort of LoadBalancer services with externalTrafficPolicy Local and the
+// node ports of the other LoadBalancer services. A failing probe silently pulls the node out of rotation.
+func (kc *KubernetesChecker) CheckLBHealthCheck(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = fmt.Sprintf("curl http://<node-ip>:%d/healthz; curl http://<node-ip>:<healthCheckNodePort>/healthz; nc -z <node-ip> <nodePort>", kubeProxyHealthzPort)
+
+	node, err := kc.client.CoreV1().Nodes().Get(ctx, kc.nodeName, metav1.GetOptions{})
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Failed to get node %s: %v", kc.nodeName, err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	nodeIP := nodeInternalIP(node)
+	if nodeIP == "" {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Node %s has no InternalIP address", kc.nodeName)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["node_ip"] = nodeIP
+
+	services, err := kc.client.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Failed to list services: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	lbServices := []corev1.Service{}
+	for _, service := range services.Items {
+		if service.Spec.Type == corev1.ServiceTypeLoadBalancer && filterAllows(kc.namespaces, false, service.Namespace) {
+			lbServices = append(lbServices, service)
+		}
+	}
+	details["load_balancer_services"] = len(lbServices)
+	if len(lbServices) > lbHealthCheckMaxServices {
+		details["probed_services"] = lbHealthCheckMaxServices
+		lbServices = lbServices[:lbHealthCheckMaxServices]
+	}
+
+	critical := []string{}
+	warnings := []string{}
+
+	// kube-proxy healthz: 503 means the proxy rules are stale and the LB stops sending traffic
+	code, healthzErr := probeHealthz(ctx, nodeIP, kubeProxyHealthzPort)
+	switch {
+	case healthzErr != nil:
+		details["kube_proxy_healthz"] = healthzErr.Error()
+		if len(lbServices) > 0 {
+			warnings = append(warnings, fmt.Sprintf("kube-proxy healthz port %d not responding", kubeProxyHealthzPort))
+		}
+	case code != http.StatusOK:
+		details["kube_proxy_healthz"] = code
+		critical = append(critical, fmt.Sprintf("kube-proxy healthz returned HTTP %d", code))
+	default:
+		details["kube_proxy_healthz"] = code
+	}
+
+	probes := []map[string]interface{}{}
+	for _, service := range lbServices {
+		name := service.Namespace + "/" + service.Name
+		if service.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal && service.Spec.HealthCheckNodePort > 0 {
+			// 200 means the node has local endpoints, 503 that it has none: both are valid answers
+			port := service.Spec.HealthCheckNodePort
+			code, err := probeHealthz(ctx, nodeIP, port)
+			probe := map[string]interface{}{"service": name, "health_check_node_port": port}
+			switch {
+			case err != nil:
+				probe["error"] = err.Error()
+				critical = append(critical, fmt.Sprintf("%s healthCheckNodePort %d not responding", name, port))
+			case code == http.StatusOK || code == http.StatusServiceUnavailable:
+				probe["http_code"] = code
+				probe["local_endpoints"] = code == http.StatusOK
+			default:
+				probe["http_code"] = code
+				warnings = append(warnings, fmt.Sprintf("%s healthCheckNodePort %d returned HTTP %d", name, port, code))
+			}
+			probes = append(probes, probe)
+			continue
+		}
+
+		for _, servicePort := range service.Spec.Ports {
+			if servicePort.NodePort == 0 || servicePort.Protocol == corev1.ProtocolUDP || servicePort.Protocol == corev1.ProtocolSCTP {
+				continue
+			}
+			probe := map[string]interface{}{"service": name, "node_port": servicePort.NodePort}
+			if err := probeTCP(ctx, nodeIP, servicePort.NodePort); err != nil {
+				probe["error"] = err.Error()
+				critical = append(critical, fmt.Sprintf("%s nodePort %d not accepting connections", name, servicePort.NodePort))
+			}
+			probes = append(probes, probe)
+		}
+	}
+	if len(probes) > 0 {
+		details["probes"] = probes
+	}
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Load balancer health checks failing, the node may be out of rotation: %s", strings.Join(append(critical, warnings...), "; "))
+	case len(warnings) > 0:
+		result.Status = "Warning"
+		result.Message = strings.Join(warnings, "; ")
+	case len(lbServices) == 0:
+		result.Status = "Healthy"
+		result.Message = "No LoadBalancer services, kube-proxy healthz checked only"
+		if healthzErr != nil {
+			result.Message = "No LoadBalancer services and no kube-proxy healthz endpoint on the node"
+		}
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Load balancer health-check ports of %d service(s) respond on %s", len(lbServices), nodeIP)
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
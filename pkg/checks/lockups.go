@@ -0,0 +1,145 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host command of the kernel_lockups check: the lockup, hung task and RCU stall reports of the kernel
+// in the last hour
+const (
+	kernelLockupsCommand = "journalctl --no-pager -k -o short-iso --no-hostname --since '1 hour ago' 2>/dev/null | grep -E 'soft lockup|hard LOCKUP|blocked for more than|rcu_[a-z_]+ (self-)?detected stall' || true"
+	// kernelStallsCritical is the number of soft lockups or RCU stalls in the last hour that marks a node
+	// stuck rather than a transient stall
+	kernelStallsCritical = 3
+	// hungTasksCritical is the number of hung task reports in the last hour that marks stuck I/O
+	hungTasksCritical = 10
+)
+
+var (
+	// softLockupPattern matches "watchdog: BUG: soft lockup - CPU#3 stuck for 23s! [kworker/3:1:1234]"
+	softLockupPattern = regexp.MustCompile(`soft lockup - CPU#(\d+) stuck for (\d+)s! \[(.+):(\d+)\]`)
+	// hardLockupPattern matches "Watchdog detected hard LOCKUP on cpu 5", with or without the NMI prefix
+	hardLockupPattern = regexp.MustCompile(`hard LOCKUP on cpu (\d+)`)
+	// hungTaskPattern matches "INFO: task jbd2/sda1-8:412 blocked for more than 120 seconds."
+	hungTaskPattern = regexp.MustCompile(`task (.+):(\d+) blocked for more than (\d+) seconds`)
+	// rcuStallPattern matches "rcu: INFO: rcu_sched self-detected stall on CPU" and
+	// "rcu: INFO: rcu_preempt detected stalls on CPUs/tasks:"
+	rcuStallPattern = regexp.MustCompile(`(rcu_[a-z_]+) (self-)?detected stall`)
+)
+
+// CheckKernelLockups reports the soft and hard lockups, hung tasks and RCU stalls the kernel logged in the
+// last hour, a window sliding with each run. They are the kernel watchdogs firing on a CPU stuck in kernel
+// mode, a task blocked in D state (usually on storage or NFS) and an RCU grace period that cannot
+// complete; the node may still answer, but its workloads hang. Unlike kernel_panics, the reports are
+// counted by kind in the window: a hard lockup, kernelStallsCritical soft lockups or RCU stalls or
+// hungTasksCritical hung tasks are Critical, any other report is a Warning.
+func (sc *SystemChecker) CheckKernelLockups(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = kernelLockupsCommand
+
+	output, err := runHostCommand(ctx, kernelLockupsCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to read the kernel log: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	var events []map[string]interface{}
+	counts := map[string]int{"soft_lockup": 0, "hard_lockup": 0, "hung_task": 0, "rcu_stall": 0}
+	cpus := make(map[int]bool)
+	tasks := make(map[string]bool)
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		event := make(map[string]interface{})
+		if match := softLockupPattern.FindStringSubmatch(line); match != nil {
+			cpu, _ := strconv.Atoi(match[1])
+			seconds, _ := strconv.Atoi(match[2])
+			cpus[cpu] = true
+			event["kind"], event["cpu"], event["stuck_seconds"], event["task"] = "soft_lockup", cpu, seconds, match[3]
+		} else if match := hardLockupPattern.FindStringSubmatch(line); match != nil {
+			cpu, _ := strconv.Atoi(match[1])
+			cpus[cpu] = true
+			event["kind"], event["cpu"] = "hard_lockup", cpu
+		} else if match := hungTaskPattern.FindStringSubmatch(line); match != nil {
+			seconds, _ := strconv.Atoi(match[3])
+			tasks[match[1]] = true
+			event["kind"], event["task"], event["pid"], event["blocked_seconds"] = "hung_task", match[1], match[2], seconds
+		} else if match := rcuStallPattern.FindStringSubmatch(line); match != nil {
+			event["kind"], event["rcu_flavor"] = "rcu_stall", match[1]
+		} else {
+			continue
+		}
+		if timestamp, _, ok := strings.Cut(line, " "); ok {
+			event["time"] = timestamp
+		}
+		counts[event["kind"].(string)]++
+		events = append(events, event)
+	}
+
+	details["soft_lockups"] = counts["soft_lockup"]
+	details["hard_lockups"] = counts["hard_lockup"]
+	details["hung_tasks"] = counts["hung_task"]
+	details["rcu_stalls"] = counts["rcu_stall"]
+	details["window_minutes"] = 60
+	affectedCPUs := make([]int, 0, len(cpus))
+	for cpu := range cpus {
+		affectedCPUs = append(affectedCPUs, cpu)
+	}
+	sort.Ints(affectedCPUs)
+	details["cpus"] = affectedCPUs
+	hungTasks := make([]string, 0, len(tasks))
+	for task := range tasks {
+		hungTasks = append(hungTasks, task)
+	}
+	sort.Strings(hungTasks)
+	details["hung_task_names"] = hungTasks
+	// The most recent reports are enough for the investigation
+	if len(events) > 20 {
+		details["events"] = events[len(events)-20:]
+	} else {
+		details["events"] = events
+	}
+
+	var summary []string
+	for _, kind := range []struct{ key, label string }{
+		{"hard_lockup", "hard lockups"}, {"soft_lockup", "soft lockups"}, {"rcu_stall", "RCU stalls"}, {"hung_task", "hung tasks"},
+	} {
+		if counts[kind.key] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[kind.key], kind.label))
+		}
+	}
+	switch {
+	case counts["hard_lockup"] > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Kernel watchdog detected a CPU stuck with interrupts disabled in the last hour (%s)", strings.Join(summary, ", "))
+	case counts["soft_lockup"]+counts["rcu_stall"] >= kernelStallsCritical:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Repeated kernel stalls in the last hour (%s)", strings.Join(summary, ", "))
+	case counts["hung_task"] >= hungTasksCritical:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("%d hung task reports in the last hour (%d tasks), I/O likely stuck", counts["hung_task"], len(hungTasks))
+	case len(events) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Kernel stalls in the last hour: %s", strings.Join(summary, ", "))
+	default:
+		result.Status = "Healthy"
+		result.Message = "No soft lockup, hard lockup, hung task or RCU stall in the last hour"
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,184 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the orphaned_mounts check: the mount points of the pod volumes, the age of the pod
+// directories (preceded by the current time) and the pod sandboxes known to the container runtime
+const (
+	kubeletPodsDir         = "/var/lib/kubelet/pods/"
+	kubeletMountsCommand   = `awk '$5 ~ "^/var/lib/kubelet/pods/" {print $5}' /proc/self/mountinfo`
+	kubeletPodDirsCommand  = "date +%s; stat -c '%Y %n' /var/lib/kubelet/pods/* 2>/dev/null; true"
+	podSandboxesCommand    = "crictl pods -o json"
+	orphanedMountsGrace    = 10 * time.Minute
+	orphanedMountsCritical = 200
+	// orphanedMountsGrowthCritical is the growth since the previous check that marks an ongoing leak
+	orphanedMountsGrowthCritical = 20
+)
+
+// orphanedMountsSeen remembers the orphaned mounts of the previous check, to tell a leak that keeps
+// growing from mounts left behind once
+var orphanedMountsSeen = struct {
+	sync.Mutex
+	value int
+	seen  bool
+}{}
+
+// resetOrphanedMounts forgets the orphaned mounts of the previous check, which belong to the host of the
+// previous runner
+func resetOrphanedMounts() {
+	orphanedMountsSeen.Lock()
+	orphanedMountsSeen.value, orphanedMountsSeen.seen = 0, false
+	orphanedMountsSeen.Unlock()
+}
+
+// podSandboxList is the part of "crictl pods -o json" output holding the pod UIDs
+type podSandboxList struct {
+	Items []struct {
+		Metadata struct {
+			UID string `json:"uid"`
+		} `json:"metadata"`
+	} `json:"items"`
+}
+
+// podMountUID returns the pod UID of a mount point under /var/lib/kubelet/pods
+func podMountUID(mountPoint string) string {
+	uid, _, _ := strings.Cut(strings.TrimPrefix(mountPoint, kubeletPodsDir), "/")
+	return uid
+}
+
+// CheckOrphanedMounts counts the pod volume mounts left behind under /var/lib/kubelet/pods after the
+// deletion of their pod, a symptom of kubelet and CSI driver unmount bugs: the mounts pin the volumes
+// (multi-attach errors elsewhere) and bloat the mount table the kubelet and systemd walk. A mount is
+// orphaned when the container runtime knows no sandbox of its pod and the pod directory is older than
+// orphanedMountsGrace, so pods being set up are not counted. Leftover mounts are a Warning; many of
+// them, or a growth of orphanedMountsGrowthCritical since the previous check, are Critical.
+func (sc *SystemChecker) CheckOrphanedMounts(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = fmt.Sprintf("%s; %s; %s", kubeletMountsCommand, kubeletPodDirsCommand, podSandboxesCommand)
+
+	output, err := runHostCommand(ctx, kubeletMountsCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to read the mount table: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	mounts := make(map[string][]string)
+	for _, line := range strings.Split(string(output), "\n") {
+		mountPoint := strings.TrimSpace(line)
+		if uid := podMountUID(mountPoint); uid != "" {
+			mounts[uid] = append(mounts[uid], mountPoint)
+		}
+	}
+	details["pod_volume_mounts"] = len(mounts)
+	if len(mounts) == 0 {
+		result.Status = "Healthy"
+		result.Message = "No pod volume mounted under /var/lib/kubelet/pods"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	output, err = runHostCommand(ctx, podSandboxesCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to list the pod sandboxes, crictl not available: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	var sandboxes podSandboxList
+	if err := json.Unmarshal(output, &sandboxes); err != nil {
+		result.Message = fmt.Sprintf("Unable to parse the pod sandboxes: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	known := make(map[string]bool, len(sandboxes.Items))
+	for _, sandbox := range sandboxes.Items {
+		known[sandbox.Metadata.UID] = true
+	}
+	details["pod_sandboxes"] = len(known)
+
+	// Without the age of the pod directories every unknown pod counts, as if past the grace period
+	ages := make(map[string]time.Duration)
+	if output, err := runHostCommand(ctx, kubeletPodDirsCommand); err == nil {
+		lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+		if now, err := strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64); err == nil {
+			for _, line := range lines[1:] {
+				modified, dir, ok := strings.Cut(strings.TrimSpace(line), " ")
+				seconds, err := strconv.ParseInt(modified, 10, 64)
+				if !ok || err != nil {
+					continue
+				}
+				ages[podMountUID(dir)] = time.Duration(now-seconds) * time.Second
+			}
+		}
+	}
+
+	var uids []string
+	orphaned, recent := 0, 0
+	for uid, podMounts := range mounts {
+		if known[uid] {
+			continue
+		}
+		if age, ok := ages[uid]; ok && age < orphanedMountsGrace {
+			recent += len(podMounts)
+			continue
+		}
+		uids = append(uids, uid)
+		orphaned += len(podMounts)
+	}
+	sort.Strings(uids)
+	orphanedPods := make([]map[string]interface{}, 0, len(uids))
+	for _, uid := range uids {
+		entry := map[string]interface{}{"uid": uid, "mounts": len(mounts[uid])}
+		if age, ok := ages[uid]; ok {
+			entry["age"] = age.String()
+		}
+		orphanedPods = append(orphanedPods, entry)
+	}
+	details["orphaned_pods"] = orphanedPods
+	details["orphaned_mounts"] = orphaned
+	details["recent_unknown_mounts"] = recent
+
+	// Growth since the previous check; on the first check there is nothing to compare with
+	orphanedMountsSeen.Lock()
+	growth, compared := orphaned-orphanedMountsSeen.value, orphanedMountsSeen.seen
+	orphanedMountsSeen.value, orphanedMountsSeen.seen = orphaned, true
+	orphanedMountsSeen.Unlock()
+	if compared {
+		details["growth_since_last_check"] = growth
+	}
+
+	switch {
+	case orphaned == 0:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("No orphaned pod volume mount (%d pods with volumes mounted)", len(mounts))
+	case orphaned >= orphanedMountsCritical:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("%d orphaned volume mounts of %d deleted pods under /var/lib/kubelet/pods", orphaned, len(uids))
+	case compared && growth >= orphanedMountsGrowthCritical:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Orphaned volume mounts growing: %d more since the previous check, %d of %d deleted pods", growth, orphaned, len(uids))
+	case compared && growth > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("%d orphaned volume mounts of %d deleted pods, %d more since the previous check", orphaned, len(uids), growth)
+	default:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("%d orphaned volume mounts of %d deleted pods left under /var/lib/kubelet/pods", orphaned, len(uids))
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -13,7 +13,8 @@ import (
 
 // NetworkChecker handles network monitoring
 type NetworkChecker struct {
-	nodeName string
+	nodeName   string
+	interfaces *v1alpha1.FilterPatterns
 }
 
 // NewNetworkChecker creates a new network checker
@@ -23,6 +24,14 @@ func NewNetworkChecker(nodeName string) *NetworkChecker {
 	}
 }
 
+// SetFilters applies the interface filter from spec.filters
+func (nc *NetworkChecker) SetFilters(filters *v1alpha1.CheckFilters) {
+	if filters == nil {
+		return
+	}
+	nc.interfaces = filters.Interfaces
+}
+
 // CheckInterfaces performs network interface monitoring
 func (nc *NetworkChecker) CheckInterfaces(ctx context.Context) *v1alpha1.CheckResult {
 	details := make(map[string]interface{})
@@ -122,8 +131,8 @@ func (nc *NetworkChecker) CheckInterfaces(ctx context.Context) *v1alpha1.CheckRe
 			if len(parts) >= 2 {
 				currentInterface = strings.TrimSpace(parts[1])
 				
-				// Skip ignored interfaces (veth, container interfaces, etc.)
-				if isIgnoredInterface(currentInterface) {
+				// Skip ignored interfaces (veth, container interfaces, etc.) unless spec.filters includes them
+				if !filterAllows(nc.interfaces, isIgnoredInterface(currentInterface), strings.SplitN(currentInterface, "@", 2)[0]) {
 					currentInterface = ""
 					continue
 				}
@@ -418,6 +427,9 @@ func (nc *NetworkChecker) CheckStatistics(ctx context.Context) *v1alpha1.CheckRe
 	interfaceStats := make(map[string]map[string]int64)
 	
 	for _, iface := range interfaces {
+		if !filterAllows(nc.interfaces, false, iface) {
+			continue
+		}
 		statsOutput, err := runHostCommand(ctx, fmt.Sprintf("ethtool -S %s", iface))
 		if err != nil {
 			cmd := exec.CommandContext(ctx, "ethtool", "-S", iface)
@@ -561,6 +573,9 @@ func (nc *NetworkChecker) CheckErrors(ctx context.Context) *v1alpha1.CheckResult
 	// Check for high error rates
 	highErrorInterfaces := []string{}
 	for iface, errors := range interfaceErrors {
+		if !filterAllows(nc.interfaces, false, strings.SplitN(iface, "@", 2)[0]) {
+			continue
+		}
 		totalErrors := int64(0)
 		for _, count := range errors {
 			totalErrors += count
@@ -0,0 +1,176 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// numaNodeStats are the allocation counters (/sys/devices/system/node/nodeN/numastat, in pages) and the
+// memory usage (nodeN/meminfo) of a NUMA node
+type numaNodeStats struct {
+	node        int
+	localNode   int64
+	otherNode   int64
+	totalKB     int64
+	freeKB      int64
+	usedPercent float64
+}
+
+// readNUMANode reads the allocation counters and the memory usage of a NUMA node
+func readNUMANode(ctx context.Context, node int) (numaNodeStats, error) {
+	stats := numaNodeStats{node: node}
+	dir := fmt.Sprintf("/sys/devices/system/node/node%d", node)
+	data, err := readProcFile(ctx, dir+"/numastat")
+	if err != nil {
+		return stats, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "local_node":
+			stats.localNode = value
+		case "other_node":
+			stats.otherNode = value
+		}
+	}
+
+	// Lines look like "Node 0 MemTotal:       65842852 kB"
+	data, err = readProcFile(ctx, dir+"/meminfo")
+	if err != nil {
+		return stats, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[2] {
+		case "MemTotal:":
+			stats.totalKB = value
+		case "MemFree:":
+			stats.freeKB = value
+		}
+	}
+	if stats.totalKB > 0 {
+		stats.usedPercent = float64(stats.totalKB-stats.freeKB) / float64(stats.totalKB) * 100
+	}
+	return stats, nil
+}
+
+// CheckNUMA checks the NUMA balance of the node. It reports the share of the memory allocations served
+// by a remote NUMA node (other_node in numastat, since boot): Warning from 10%, Critical from 25%, as
+// remote memory accesses explain latency that shows nowhere else on large hosts. A NUMA node almost full
+// (85%) while another is half empty is a Warning as well: its allocations spill over to the remote node.
+func (sc *SystemChecker) CheckNUMA(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	result.Command = "cat /sys/devices/system/node/node*/numastat /sys/devices/system/node/node*/meminfo"
+	online, err := readProcFile(ctx, "/sys/devices/system/node/online")
+	if err != nil {
+		result.Message = fmt.Sprintf("NUMA topology not available: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	nodes, err := parseNodeList(string(online))
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Failed to read the NUMA nodes: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["numa_nodes"] = len(nodes)
+	if len(nodes) < 2 {
+		result.Status = "Healthy"
+		result.Message = "Single NUMA node, no cross-node allocations"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	var local, remote int64
+	var fullest, emptiest *numaNodeStats
+	perNode := make([]map[string]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		stats, err := readNUMANode(ctx, node)
+		if err != nil {
+			result.Status = "Warning"
+			result.Message = fmt.Sprintf("Failed to read the statistics of NUMA node %d: %v", node, err)
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+		local += stats.localNode
+		remote += stats.otherNode
+		perNode = append(perNode, map[string]interface{}{
+			"node":             stats.node,
+			"local_node":       stats.localNode,
+			"other_node":       stats.otherNode,
+			"mem_total_kb":     stats.totalKB,
+			"mem_free_kb":      stats.freeKB,
+			"mem_total_bytes":  stats.totalKB * 1024,
+			"mem_free_bytes":   stats.freeKB * 1024,
+			"mem_used_percent": stats.usedPercent,
+		})
+		if fullest == nil || stats.usedPercent > fullest.usedPercent {
+			s := stats
+			fullest = &s
+		}
+		if emptiest == nil || stats.usedPercent < emptiest.usedPercent {
+			s := stats
+			emptiest = &s
+		}
+	}
+	details["nodes"] = perNode
+
+	remotePercent := 0.0
+	if local+remote > 0 {
+		remotePercent = float64(remote) / float64(local+remote) * 100
+	}
+	details["remote_allocation_percent"] = remotePercent
+	details["memory_imbalance_percent"] = fullest.usedPercent - emptiest.usedPercent
+
+	var issues []string
+	status := "Healthy"
+	if remotePercent >= 25 {
+		status = "Critical"
+		issues = append(issues, fmt.Sprintf("%.1f%% of the memory allocations are on a remote NUMA node", remotePercent))
+	} else if remotePercent >= 10 {
+		status = "Warning"
+		issues = append(issues, fmt.Sprintf("%.1f%% of the memory allocations are on a remote NUMA node", remotePercent))
+	}
+	if fullest.usedPercent >= 85 && emptiest.usedPercent <= 50 {
+		if status == "Healthy" {
+			status = "Warning"
+		}
+		issues = append(issues, fmt.Sprintf("NUMA node %d memory is %.1f%% used while node %d is %.1f%% used",
+			fullest.node, fullest.usedPercent, emptiest.node, emptiest.usedPercent))
+	}
+
+	result.Status = status
+	if len(issues) > 0 {
+		result.Message = fmt.Sprintf("NUMA imbalance: %s", strings.Join(issues, "; "))
+	} else {
+		result.Message = fmt.Sprintf("NUMA balanced across %d nodes (%.1f%% remote allocations)", len(nodes), remotePercent)
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
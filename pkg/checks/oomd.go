@@ -0,0 +1,157 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the userspace_oom check: the state of the userspace OOM daemons, one line per unit in
+// order, and the kills they logged in the last hour
+const (
+	oomDaemonsCommand = "systemctl is-active systemd-oomd.service earlyoom.service 2>/dev/null; true"
+	oomKillsCommand   = "journalctl --no-pager -o short-iso --no-hostname --since '1 hour ago' -t systemd-oomd -t earlyoom 2>/dev/null | grep -E 'Killed |sending SIG' || true"
+	// userspaceOOMKillsCritical is the number of kills in the last hour that marks sustained memory pressure
+	userspaceOOMKillsCritical = 3
+)
+
+// oomDaemons are the userspace OOM daemons, in the order of oomDaemonsCommand
+var oomDaemons = []string{"systemd-oomd", "earlyoom"}
+
+// oomdKillPattern matches the kill of a cgroup by systemd-oomd, with the pressure (systemd 247+) or the
+// swap usage (systemd 250+) that triggered it
+var oomdKillPattern = regexp.MustCompile(`Killed (\S+) due to (memory pressure|memory used|swap used)`)
+
+// earlyoomKillPattern matches the signal sent by earlyoom to a process; earlyoom 1.6+ also logs the uid
+var earlyoomKillPattern = regexp.MustCompile(`sending (SIGTERM|SIGKILL) to process (\d+)(?: uid \d+)? "([^"]*)"`)
+
+// essentialOOMUnits are the cgroups whose kill by a userspace OOM daemon takes the node down
+var essentialOOMUnits = []string{"kubelet.service", "crio.service", "containerd.service"}
+
+// CheckUserspaceOOM reports the kills of the userspace OOM daemons, systemd-oomd and earlyoom. They kill
+// whole cgroups or processes on memory pressure before the kernel OOM killer triggers, so the kills never
+// reach dmesg and the oom_killer check misses them; the kubelet does not see them as OOMKilled either. The
+// kills of the last hour are read from the journal: any kill is a Warning, userspaceOOMKillsCritical of
+// them or the kill of the kubelet or the container runtime are Critical. Running both daemons is a
+// Warning, as they race for the same victims.
+func (sc *SystemChecker) CheckUserspaceOOM(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = fmt.Sprintf("%s; %s", oomDaemonsCommand, oomKillsCommand)
+
+	output, err := runHostCommand(ctx, oomDaemonsCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to query the userspace OOM daemons: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	states := strings.Split(strings.TrimSpace(string(output)), "\n")
+	var active []string
+	daemons := make(map[string]string, len(oomDaemons))
+	for i, daemon := range oomDaemons {
+		state := "unknown"
+		if i < len(states) && strings.TrimSpace(states[i]) != "" {
+			state = strings.TrimSpace(states[i])
+		}
+		daemons[daemon] = state
+		if state == "active" {
+			active = append(active, daemon)
+		}
+	}
+	details["daemons"] = daemons
+
+	// The journal is read even without an active daemon: a daemon stopped within the hour may have killed
+	journal, err := runHostCommand(ctx, oomKillsCommand)
+	if err != nil {
+		if len(active) == 0 {
+			result.Status = "Healthy"
+			result.Message = "No userspace OOM daemon running, kernel OOM kills are reported by the oom_killer check"
+		} else {
+			result.Message = fmt.Sprintf("System journal not available, kills of %s unknown: %v", strings.Join(active, " and "), err)
+		}
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	var kills []map[string]interface{}
+	var essential []string
+	podKills := 0
+	signaled := make(map[string]bool)
+	for _, line := range strings.Split(string(journal), "\n") {
+		line = strings.TrimSpace(line)
+		if match := oomdKillPattern.FindStringSubmatch(line); match != nil {
+			reason := "memory pressure"
+			if match[2] != "memory pressure" {
+				reason = "swap usage"
+			}
+			kill := map[string]interface{}{"daemon": "systemd-oomd", "cgroup": match[1], "reason": reason}
+			if timestamp, _, ok := strings.Cut(line, " "); ok {
+				kill["time"] = timestamp
+			}
+			kills = append(kills, kill)
+			if strings.HasPrefix(match[1], "/kubepods.slice") {
+				podKills++
+			}
+			for _, unit := range essentialOOMUnits {
+				if strings.HasSuffix(match[1], "/"+unit) {
+					essential = append(essential, unit)
+				}
+			}
+			continue
+		}
+		// earlyoom sends SIGKILL to a process ignoring its SIGTERM, the same kill
+		if match := earlyoomKillPattern.FindStringSubmatch(line); match != nil && !signaled[match[2]] {
+			signaled[match[2]] = true
+			kill := map[string]interface{}{"daemon": "earlyoom", "pid": match[2], "process": match[3], "signal": match[1]}
+			if timestamp, _, ok := strings.Cut(line, " "); ok {
+				kill["time"] = timestamp
+			}
+			kills = append(kills, kill)
+			for _, unit := range essentialOOMUnits {
+				if match[3] == strings.TrimSuffix(unit, ".service") {
+					essential = append(essential, unit)
+				}
+			}
+		}
+	}
+	sort.Strings(essential)
+	details["kills_last_hour"] = len(kills)
+	details["pod_kills"] = podKills
+	// The most recent kills are enough for the investigation
+	if len(kills) > 20 {
+		details["kills"] = kills[len(kills)-20:]
+	} else {
+		details["kills"] = kills
+	}
+
+	switch {
+	case len(essential) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Userspace OOM daemon killed %s in the last hour (%d kills)", strings.Join(essential, ", "), len(kills))
+	case len(kills) >= userspaceOOMKillsCritical:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("%d kills by userspace OOM daemons in the last hour (%d of pods), sustained memory pressure", len(kills), podKills)
+	case len(kills) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("%d kills by userspace OOM daemons in the last hour (%d of pods)", len(kills), podKills)
+	case len(active) > 1:
+		result.Status = "Warning"
+		result.Message = "Both systemd-oomd and earlyoom are running and compete for the same victims"
+	case len(active) == 1:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("No kill by %s in the last hour", active[0])
+	default:
+		result.Status = "Healthy"
+		result.Message = "No userspace OOM daemon running, kernel OOM kills are reported by the oom_killer check"
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
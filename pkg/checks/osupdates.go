@@ -0,0 +1,188 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the os_updates check. The package managers only read their cached metadata: a
+// check refreshing the repositories of every node at each interval would load the mirrors, and the
+// cache is kept fresh by dnf-makecache.timer on package-based hosts.
+const (
+	rpmOstreeStatusCommand  = "rpm-ostree status --json"
+	securityUpdatesCommand  = "if command -v dnf >/dev/null 2>&1; then dnf -q --cacheonly updateinfo list --security; else yum -q -C updateinfo list security; fi"
+	needsRestartingCommand  = "needs-restarting -r >/dev/null 2>&1; echo $?"
+	needsRestartingRequired = "1"
+	needsRestartingNotFound = "127"
+)
+
+// advisoryPattern matches the ID of an errata advisory (RHSA-2024:1234, ALSA-2024:1234, FEDORA-2024-1a2b3c)
+var advisoryPattern = regexp.MustCompile(`^[A-Z]+-[0-9]{4}[:-][0-9A-Za-z]+$`)
+
+// rpmOstreeStatus is the part of rpm-ostree status --json read by the check
+type rpmOstreeStatus struct {
+	Deployments []struct {
+		Booted   bool   `json:"booted"`
+		Staged   bool   `json:"staged"`
+		Version  string `json:"version"`
+		Checksum string `json:"checksum"`
+	} `json:"deployments"`
+}
+
+// securityAdvisory is a line of dnf/yum updateinfo list --security
+type securityAdvisory struct {
+	ID       string
+	Severity string
+	Package  string
+}
+
+// parseSecurityUpdates parses the output of updateinfo list --security: "<advisory> <severity>/Sec.
+// <package>", one line per package. Headers and notices (e.g. the expiration of the metadata) are skipped.
+func parseSecurityUpdates(output string) []securityAdvisory {
+	var advisories []securityAdvisory
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || !advisoryPattern.MatchString(fields[0]) {
+			continue
+		}
+		severity, _, _ := strings.Cut(fields[1], "/")
+		advisories = append(advisories, securityAdvisory{ID: fields[0], Severity: severity, Package: fields[2]})
+	}
+	return advisories
+}
+
+// CheckOSUpdates reports the nodes behind on patches. On image-based hosts (RHCOS, Fedora CoreOS) a
+// pending rpm-ostree deployment, staged by the Machine Config Operator or rpm-ostree upgrade, waits for a
+// reboot. On package-based hosts (RHEL, CentOS) the pending security advisories are read from the dnf or
+// yum cache, and needs-restarting -r tells whether an updated kernel or core library needs a reboot.
+// Critical advisories are Critical; other advisories, a pending deployment or a required reboot are Warning.
+func (sc *SystemChecker) CheckOSUpdates(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = rpmOstreeStatusCommand
+
+	// Image-based host: updates are whole deployments, the package managers are not used
+	if output, err := runHostCommand(ctx, rpmOstreeStatusCommand); err == nil {
+		var status rpmOstreeStatus
+		if err := json.Unmarshal(output, &status); err != nil {
+			result.Message = fmt.Sprintf("Unable to parse rpm-ostree status: %v", err)
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+		details["update_method"] = "rpm-ostree"
+		details["deployments"] = len(status.Deployments)
+		booted := ""
+		for _, deployment := range status.Deployments {
+			if deployment.Booted {
+				booted = deployment.Version
+				details["booted_version"] = deployment.Version
+				details["booted_checksum"] = deployment.Checksum
+			}
+		}
+		// rpm-ostree lists the deployment of the next boot first
+		if len(status.Deployments) > 0 && !status.Deployments[0].Booted {
+			pending := status.Deployments[0]
+			details["pending_version"] = pending.Version
+			details["pending_checksum"] = pending.Checksum
+			details["pending_staged"] = pending.Staged
+			result.Status = "Warning"
+			result.Message = fmt.Sprintf("Pending rpm-ostree deployment %s (booted %s), reboot the node to apply it", pending.Version, booted)
+		} else {
+			result.Status = "Healthy"
+			result.Message = fmt.Sprintf("No pending rpm-ostree deployment, booted %s", booted)
+		}
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	// Package-based host
+	result.Command = fmt.Sprintf("%s; %s", securityUpdatesCommand, needsRestartingCommand)
+	details["update_method"] = "dnf"
+	var problems []string
+	critical := false
+
+	output, err := runHostCommand(ctx, securityUpdatesCommand)
+	if err != nil {
+		details["security_updates_error"] = fmt.Sprintf("unable to list the security updates (no cached metadata?): %v", err)
+	} else {
+		advisories := parseSecurityUpdates(string(output))
+		ids := make(map[string]string)
+		severities := make(map[string]int)
+		packages := make(map[string]bool)
+		for _, advisory := range advisories {
+			if _, seen := ids[advisory.ID]; !seen {
+				severities[advisory.Severity]++
+			}
+			ids[advisory.ID] = advisory.Severity
+			packages[advisory.Package] = true
+		}
+		criticalAdvisories := []string{}
+		for id, severity := range ids {
+			if severity == "Critical" {
+				criticalAdvisories = append(criticalAdvisories, id)
+			}
+		}
+		sort.Strings(criticalAdvisories)
+		details["security_advisories"] = len(ids)
+		details["security_packages"] = len(packages)
+		details["advisories_by_severity"] = severities
+		details["critical_advisories"] = criticalAdvisories
+		if len(ids) > 0 {
+			problem := fmt.Sprintf("%d security advisories pending for %d packages", len(ids), len(packages))
+			if len(criticalAdvisories) > 0 {
+				critical = true
+				problem += fmt.Sprintf(", critical: %s", strings.Join(criticalAdvisories, ", "))
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	rebootKnown := false
+	if restartOutput, restartErr := runHostCommand(ctx, needsRestartingCommand); restartErr == nil {
+		switch strings.TrimSpace(string(restartOutput)) {
+		case needsRestartingRequired:
+			rebootKnown = true
+			details["reboot_required"] = true
+			problems = append(problems, "updated core packages (kernel, glibc, systemd) need a reboot")
+		case needsRestartingNotFound:
+			details["reboot_required_note"] = "needs-restarting not installed (dnf-utils/yum-utils)"
+		default:
+			rebootKnown = true
+			details["reboot_required"] = false
+		}
+	}
+
+	if err != nil && !rebootKnown {
+		result.Message = "Unable to query rpm-ostree, dnf/yum or needs-restarting on the host"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	switch {
+	case critical:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Node is behind on patches: %s", strings.Join(problems, "; "))
+	case len(problems) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Node is behind on patches: %s", strings.Join(problems, "; "))
+	default:
+		result.Status = "Healthy"
+		result.Message = "No pending security updates or reboot"
+		if err != nil {
+			result.Message = "No reboot required; the security updates could not be listed"
+		} else if !rebootKnown {
+			result.Message = "No pending security updates; whether a reboot is required is unknown"
+		}
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,313 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// clusterDomain is the DNS domain of the cluster services probed from the pod network
+	clusterDomain = "cluster.local"
+	// podNetworkMaxCandidates is the number of pods tried when looking for a pod network namespace
+	podNetworkMaxCandidates = 3
+)
+
+// errNoPodInNamespace is returned by PodNetworkNamespaceIn when no pod of the namespace runs on the node
+var errNoPodInNamespace = errors.New("no running pod of the namespace on the node")
+
+// dnsServices are the cluster DNS services, in lookup order (OpenShift, then upstream Kubernetes)
+var dnsServices = []struct {
+	namespace string
+	name      string
+}{
+	{"openshift-dns", "dns-default"},
+	{"kube-system", "kube-dns"},
+}
+
+var (
+	// Pod names, namespaces and sandbox IDs are interpolated into host shell commands
+	validObjectName = regexp.MustCompile(`^[a-z0-9.-]+$`)
+	validSandboxID  = regexp.MustCompile(`^[a-f0-9]+$`)
+	validNetnsPath  = regexp.MustCompile(`^/[a-zA-Z0-9/._-]+$`)
+)
+
+// podSandboxInspect is the part of "crictl inspectp" output holding the sandbox namespaces
+type podSandboxInspect struct {
+	Info struct {
+		RuntimeSpec struct {
+			Linux struct {
+				Namespaces []struct {
+					Type string `json:"type"`
+					Path string `json:"path"`
+				} `json:"namespaces"`
+			} `json:"linux"`
+		} `json:"runtimeSpec"`
+	} `json:"info"`
+}
+
+// podNetworkNamespace returns the network namespace path of a running pod, looked up through crictl
+func podNetworkNamespace(ctx context.Context, pod *corev1.Pod) (string, error) {
+	if !validObjectName.MatchString(pod.Namespace) || !validObjectName.MatchString(pod.Name) {
+		return "", fmt.Errorf("unexpected pod name %s/%s", pod.Namespace, pod.Name)
+	}
+	output, err := runHostCommand(ctx, fmt.Sprintf("crictl pods --state ready --namespace %s --name %s -q", pod.Namespace, pod.Name))
+	if err != nil {
+		return "", fmt.Errorf("crictl not available: %v", err)
+	}
+	ids := strings.Fields(string(output))
+	if len(ids) == 0 || !validSandboxID.MatchString(ids[0]) {
+		return "", fmt.Errorf("pod sandbox not found")
+	}
+
+	output, err = runHostCommand(ctx, fmt.Sprintf("crictl inspectp %s", ids[0]))
+	if err != nil {
+		return "", fmt.Errorf("unable to inspect pod sandbox: %v", err)
+	}
+	var inspect podSandboxInspect
+	if err := json.Unmarshal(output, &inspect); err != nil {
+		return "", fmt.Errorf("unable to parse pod sandbox: %v", err)
+	}
+	for _, namespace := range inspect.Info.RuntimeSpec.Linux.Namespaces {
+		if namespace.Type == "network" && validNetnsPath.MatchString(namespace.Path) {
+			return namespace.Path, nil
+		}
+	}
+	return "", fmt.Errorf("pod sandbox has no network namespace path")
+}
+
+// podNetworkCandidates returns the running pods on the node that use the pod network, oldest first
+func (kc *KubernetesChecker) podNetworkCandidates(ctx context.Context) ([]corev1.Pod, error) {
+	pods, err := kc.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("spec.nodeName=%s,status.phase=Running", kc.nodeName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	candidates := []corev1.Pod{}
+	for _, pod := range pods.Items {
+		if pod.Spec.HostNetwork || pod.DeletionTimestamp != nil || !filterAllows(kc.namespaces, false, pod.Namespace) {
+			continue
+		}
+		candidates = append(candidates, pod)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].CreationTimestamp.Before(&candidates[j].CreationTimestamp)
+	})
+	return candidates, nil
+}
+
+// PodNetworkNamespaceIn returns the network namespace path and the name of a running pod of the given
+// namespace on the node (e.g. to probe egress IPs assigned to that namespace)
+func (kc *KubernetesChecker) PodNetworkNamespaceIn(ctx context.Context, namespace string) (string, string, error) {
+	candidates, err := kc.podNetworkCandidates(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to list pods: %v", err)
+	}
+	tried := 0
+	for i := range candidates {
+		if candidates[i].Namespace != namespace {
+			continue
+		}
+		if tried >= podNetworkMaxCandidates {
+			break
+		}
+		tried++
+		if path, err := podNetworkNamespace(ctx, &candidates[i]); err == nil {
+			return path, candidates[i].Name, nil
+		}
+	}
+	if tried == 0 {
+		return "", "", errNoPodInNamespace
+	}
+	return "", "", fmt.Errorf("unable to find the network namespace of a pod in %s", namespace)
+}
+
+// clusterDNSAddress returns the ClusterIP of the cluster DNS service
+func (kc *KubernetesChecker) clusterDNSAddress(ctx context.Context) (string, error) {
+	for _, dns := range dnsServices {
+		service, err := kc.client.CoreV1().Services(dns.namespace).Get(ctx, dns.name, metav1.GetOptions{})
+		if err == nil && net.ParseIP(service.Spec.ClusterIP) != nil {
+			return service.Spec.ClusterIP, nil
+		}
+	}
+	return "", fmt.Errorf("cluster DNS service not found")
+}
+
+// serviceReachable reports whether an HTTPS service answers, from the given network namespace ("" for the host)
+func serviceReachable(ctx context.Context, netns, address string) (bool, string) {
+	command := fmt.Sprintf("curl -sk -o /dev/null -w %%{http_code} --connect-timeout 5 --max-time 10 https://%s/healthz", address)
+	if netns != "" {
+		command = fmt.Sprintf("nsenter --net=%s %s", netns, command)
+	}
+	output, _ := runHostCommand(ctx, command)
+	code := strings.TrimSpace(string(output))
+	// Any HTTP status (even 401/403) proves the TCP and TLS path works; 000 means no connection
+	return code != "" && code != "000" && len(code) == 3, code
+}
+
+// resolveFromNamespace resolves a name against a DNS server from the given network namespace
+func resolveFromNamespace(ctx context.Context, netns, server, name string) ([]string, error) {
+	output, err := runHostCommand(ctx, fmt.Sprintf("nsenter --net=%s dig +short +time=3 +tries=2 @%s %s", netns, server, name))
+	if err != nil && (strings.Contains(string(output), "failed to execute") || strings.Contains(string(output), "not found")) {
+		// dig is not installed on every host, fall back to nslookup
+		output, err = runHostCommand(ctx, fmt.Sprintf("nsenter --net=%s nslookup -timeout=3 %s %s", netns, name, server))
+		if err != nil {
+			return nil, fmt.Errorf("%s", strings.TrimSpace(string(output)))
+		}
+		addresses := []string{}
+		answers := false
+		for _, line := range strings.Split(string(output), "\n") {
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "Name:") {
+				answers = true
+			}
+			if answers && strings.HasPrefix(line, "Address:") {
+				addresses = append(addresses, strings.TrimSpace(strings.TrimPrefix(line, "Address:")))
+			}
+		}
+		return addresses, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	addresses := []string{}
+	for _, line := range strings.Fields(string(output)) {
+		if net.ParseIP(line) != nil {
+			addresses = append(addresses, line)
+		}
+	}
+	return addresses, nil
+}
+
+// CheckPodNetwork probes the cluster DNS and the kubernetes API service from inside the network namespace
+// of a pod running on the node. CNI problems (e.g. broken OVS flows or missing service NAT rules) often only
+// affect the pod path, which host network checks cannot see.
+func (kc *KubernetesChecker) CheckPodNetwork(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	result.Command = "crictl inspectp <pod> && nsenter --net=<pod netns> dig @<cluster DNS> kubernetes.default.svc." + clusterDomain +
+		" && nsenter --net=<pod netns> curl -k https://<kubernetes service>/healthz"
+
+	service, err := kc.client.CoreV1().Services("default").Get(ctx, "kubernetes", metav1.GetOptions{})
+	if err != nil || net.ParseIP(service.Spec.ClusterIP) == nil || len(service.Spec.Ports) == 0 {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Unable to read the kubernetes service: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	serviceAddress := net.JoinHostPort(service.Spec.ClusterIP, fmt.Sprintf("%d", service.Spec.Ports[0].Port))
+	details["kubernetes_service"] = serviceAddress
+
+	candidates, err := kc.podNetworkCandidates(ctx)
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Failed to list pods: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	if len(candidates) == 0 {
+		result.Status = "Healthy"
+		result.Message = "No running pod on the pod network to probe from"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	// Use the first pod whose network namespace can be found
+	netns := ""
+	var lookupErrors []string
+	for i := range candidates {
+		if i >= podNetworkMaxCandidates {
+			break
+		}
+		path, err := podNetworkNamespace(ctx, &candidates[i])
+		if err == nil {
+			netns = path
+			details["probe_pod"] = fmt.Sprintf("%s/%s", candidates[i].Namespace, candidates[i].Name)
+			details["probe_netns"] = path
+			break
+		}
+		lookupErrors = append(lookupErrors, fmt.Sprintf("%s/%s: %v", candidates[i].Namespace, candidates[i].Name, err))
+	}
+	if netns == "" {
+		details["lookup_errors"] = lookupErrors
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Unable to find a pod network namespace: %s", lookupErrors[0])
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	problems := []string{}
+
+	// Service path: kubernetes API service ClusterIP from the pod network, compared with the host network
+	podReachable, podCode := serviceReachable(ctx, netns, serviceAddress)
+	hostReachable, hostCode := serviceReachable(ctx, "", serviceAddress)
+	details["service_reachable_from_pod"] = podReachable
+	details["service_reachable_from_host"] = hostReachable
+	details["service_http_code_from_pod"] = podCode
+	details["service_http_code_from_host"] = hostCode
+	if !podReachable {
+		if hostReachable {
+			problems = append(problems, fmt.Sprintf("kubernetes service %s unreachable from the pod network but reachable from the host", serviceAddress))
+		} else {
+			problems = append(problems, fmt.Sprintf("kubernetes service %s unreachable from the pod network and the host", serviceAddress))
+		}
+	}
+
+	// DNS path: resolve the kubernetes service through the cluster DNS from the pod network
+	name := "kubernetes.default.svc." + clusterDomain
+	dnsServer, err := kc.clusterDNSAddress(ctx)
+	if err != nil {
+		details["dns_note"] = err.Error()
+	} else {
+		details["dns_server"] = dnsServer
+		addresses, err := resolveFromNamespace(ctx, netns, dnsServer, name)
+		details["dns_answers"] = addresses
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("DNS lookup of %s via %s failed from the pod network: %v", name, dnsServer, err))
+		case len(addresses) == 0:
+			problems = append(problems, fmt.Sprintf("DNS lookup of %s via %s returned no address", name, dnsServer))
+		case !containsString(addresses, service.Spec.ClusterIP):
+			problems = append(problems, fmt.Sprintf("DNS lookup of %s returned %s, expected %s", name, strings.Join(addresses, ", "), service.Spec.ClusterIP))
+		}
+	}
+
+	if len(problems) > 0 {
+		details["problems"] = problems
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Pod network path broken: %s", strings.Join(problems, "; "))
+	} else if dnsServer == "" {
+		result.Status = "Warning"
+		result.Message = "Kubernetes service reachable from the pod network, DNS not probed (cluster DNS service not found)"
+	} else {
+		result.Status = "Healthy"
+		result.Message = "Cluster DNS and kubernetes service reachable from the pod network"
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// pressureLine is a line of a /proc/pressure file: the share of time (in percent) in which some or all
+// the non-idle tasks were stalled on the resource, averaged over 10 seconds, 1 minute and 5 minutes
+type pressureLine struct {
+	avg10  float64
+	avg60  float64
+	avg300 float64
+}
+
+// parsePressure parses a /proc/pressure file into its "some" and "full" lines
+func parsePressure(data string) (map[string]pressureLine, error) {
+	lines := make(map[string]pressureLine)
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		var parsed pressureLine
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value %q", key, value)
+			}
+			switch key {
+			case "avg10":
+				parsed.avg10 = number
+			case "avg60":
+				parsed.avg60 = number
+			case "avg300":
+				parsed.avg300 = number
+			}
+		}
+		lines[fields[0]] = parsed
+	}
+	if _, ok := lines["some"]; !ok {
+		return nil, fmt.Errorf("no \"some\" line")
+	}
+	return lines, nil
+}
+
+// CheckPressureStall checks the pressure stall information (PSI) of CPU, memory and I/O. Unlike the load
+// average, PSI measures the time tasks actually wait for a resource. A pressure is sustained when both its
+// 1 minute and 5 minutes averages are above a threshold: "some" pressure is compared with the thresholds of
+// the pressure_stall check (40/80% by default), "full" pressure (all tasks stalled, memory and I/O only)
+// with 10/25%.
+func (sc *SystemChecker) CheckPressureStall(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = "cat /proc/pressure/cpu /proc/pressure/memory /proc/pressure/io"
+
+	someWarning, someCritical := usageThresholds(sc.thresholds, "pressure_stall", 40, 80)
+	const fullWarning, fullCritical = 10, 25
+	details["some_warning_threshold"] = someWarning
+	details["some_critical_threshold"] = someCritical
+	details["full_warning_threshold"] = fullWarning
+	details["full_critical_threshold"] = fullCritical
+
+	var critical, warning []string
+	for _, resource := range []string{"cpu", "memory", "io"} {
+		data, err := readProcFile(ctx, "/proc/pressure/"+resource)
+		if err != nil {
+			result.Status = "Unknown"
+			result.Message = fmt.Sprintf("Pressure stall information not available (kernel without PSI or booted with psi=0): %v", err)
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+		lines, err := parsePressure(string(data))
+		if err != nil {
+			result.Status = "Warning"
+			result.Message = fmt.Sprintf("Invalid /proc/pressure/%s: %v", resource, err)
+			result.Details = mapToRawExtension(details)
+			return result
+		}
+
+		for _, kind := range []string{"some", "full"} {
+			line, ok := lines[kind]
+			if !ok {
+				continue
+			}
+			details[fmt.Sprintf("%s_%s", resource, kind)] = map[string]float64{
+				"avg10":  line.avg10,
+				"avg60":  line.avg60,
+				"avg300": line.avg300,
+			}
+			// The system-wide CPU "full" line is always zero, a CPU is never stalled by itself
+			if resource == "cpu" && kind == "full" {
+				continue
+			}
+
+			sustained := line.avg60
+			if line.avg300 < sustained {
+				sustained = line.avg300
+			}
+			warn, crit := float64(someWarning), float64(someCritical)
+			if kind == "full" {
+				warn, crit = fullWarning, fullCritical
+			}
+			pressure := fmt.Sprintf("%s %s %.1f%%", resource, kind, sustained)
+			if sustained >= crit {
+				critical = append(critical, pressure)
+			} else if sustained >= warn {
+				warning = append(warning, pressure)
+			}
+		}
+	}
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Sustained resource pressure: %s", strings.Join(append(critical, warning...), ", "))
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Sustained resource pressure: %s", strings.Join(warning, ", "))
+	default:
+		result.Status = "Healthy"
+		result.Message = "No sustained CPU, memory or I/O pressure"
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,370 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The CPU, memory and process statistics of the resources, processes, context_switches, swap_activity
+// and zombie_processes checks are read from the procfs of the host rather than parsed from the output of
+// vmstat, top and ps, which changes with the procps version and the locale. The commands remain the
+// fallback when the procfs cannot be read.
+
+// errProcDisabled is returned by the native collection when SetProcRoot disabled it
+var errProcDisabled = errors.New("native /proc collection disabled")
+
+var (
+	procRootMu sync.RWMutex
+	procRoot   = defaultProcRoot()
+)
+
+// defaultProcRoot returns the procfs of the host under the host root, or the /proc of the container,
+// which shows the processes of the host in a hostPID pod
+func defaultProcRoot() string {
+	hostProc := path.Join(hostRootMountPath, "proc")
+	if _, err := os.Stat(path.Join(hostProc, "stat")); err == nil {
+		return hostProc
+	}
+	return "/proc"
+}
+
+// SetProcRoot replaces the procfs read by the native collection and returns the previous one, so callers
+// can restore it. An empty root disables the native collection and the checks run their commands, as
+// harnesses replaying canned command outputs need.
+func SetProcRoot(root string) string {
+	procRootMu.Lock()
+	defer procRootMu.Unlock()
+	previous := procRoot
+	procRoot = root
+	return previous
+}
+
+// readProc reads a file of the procfs, by its path under /proc (e.g. "stat" or "1/stat")
+func readProc(name string) ([]byte, error) {
+	procRootMu.RLock()
+	root := procRoot
+	procRootMu.RUnlock()
+	if root == "" {
+		return nil, errProcDisabled
+	}
+	return os.ReadFile(path.Join(root, name))
+}
+
+// procSnapshot holds the cumulative counters of /proc/stat and /proc/vmstat and the memory of
+// /proc/meminfo at a point in time
+type procSnapshot struct {
+	taken        time.Time
+	cpu          CPUStats
+	cpus         int
+	ctxt         int64
+	procsRunning int64
+	procsBlocked int64
+	pswpin       int64
+	pswpout      int64
+	// meminfo is /proc/meminfo in KiB, keyed by field name (e.g. "MemFree")
+	meminfo map[string]int64
+}
+
+// parseProcStat parses /proc/stat into a snapshot: the aggregate cpu line, the number of CPUs (the cpuN
+// lines), the context switches and the running and blocked processes
+func parseProcStat(data string, snapshot *procSnapshot) error {
+	found := false
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		switch {
+		case fields[0] == "cpu":
+			stats, err := parseCPUStatsLine(fields)
+			if err != nil {
+				return err
+			}
+			snapshot.cpu = *stats
+			found = true
+		case strings.HasPrefix(fields[0], "cpu"):
+			snapshot.cpus++
+		case fields[0] == "ctxt":
+			snapshot.ctxt, _ = strconv.ParseInt(fields[1], 10, 64)
+		case fields[0] == "procs_running":
+			snapshot.procsRunning, _ = strconv.ParseInt(fields[1], 10, 64)
+		case fields[0] == "procs_blocked":
+			snapshot.procsBlocked, _ = strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	if !found {
+		return fmt.Errorf("cpu aggregate line not found in /proc/stat")
+	}
+	if snapshot.cpus == 0 {
+		snapshot.cpus = 1
+	}
+	return nil
+}
+
+// parseMeminfo parses /proc/meminfo, in KiB (HugePages_* are page counts)
+func parseMeminfo(data string) map[string]int64 {
+	meminfo := make(map[string]int64)
+	for _, line := range strings.Split(data, "\n") {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+		if number, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			meminfo[name] = number
+		}
+	}
+	return meminfo
+}
+
+// readProcSnapshot reads /proc/stat, /proc/vmstat and /proc/meminfo
+func readProcSnapshot() (procSnapshot, error) {
+	snapshot := procSnapshot{taken: time.Now()}
+	data, err := readProc("stat")
+	if err != nil {
+		return snapshot, err
+	}
+	if err := parseProcStat(string(data), &snapshot); err != nil {
+		return snapshot, err
+	}
+	data, err = readProc("vmstat")
+	if err != nil {
+		return snapshot, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "pswpin":
+			snapshot.pswpin, _ = strconv.ParseInt(fields[1], 10, 64)
+		case "pswpout":
+			snapshot.pswpout, _ = strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	data, err = readProc("meminfo")
+	if err != nil {
+		return snapshot, err
+	}
+	snapshot.meminfo = parseMeminfo(string(data))
+	return snapshot, nil
+}
+
+// waitInterval waits for the next measurement, or returns the error of the context
+func waitInterval(ctx context.Context, interval time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(interval):
+		return nil
+	}
+}
+
+// sampleProcSnapshots reads measurements+1 snapshots, interval apart
+func sampleProcSnapshots(ctx context.Context, measurements int, interval time.Duration) ([]procSnapshot, error) {
+	snapshot, err := readProcSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	snapshots := []procSnapshot{snapshot}
+	for i := 0; i < measurements; i++ {
+		if err := waitInterval(ctx, interval); err != nil {
+			return nil, err
+		}
+		if snapshot, err = readProcSnapshot(); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, nil
+}
+
+// vmstatSample returns the values between two snapshots with the keys and units of parseVmstatSamples:
+// swap in and out in KiB per second, the CPU time in whole percents grouped as vmstat does (us is user
+// and nice, sy is system, irq and softirq)
+func vmstatSample(before, after procSnapshot) map[string]int64 {
+	seconds := after.taken.Sub(before.taken).Seconds()
+	if seconds <= 0 {
+		seconds = 1
+	}
+	pageKiB := int64(os.Getpagesize() / 1024)
+	perSecond := func(delta int64) int64 {
+		return int64(math.Round(float64(delta) / seconds))
+	}
+	percent := func(delta, total int64) int64 {
+		if total <= 0 {
+			return 0
+		}
+		return int64(math.Round(float64(delta) * 100 / float64(total)))
+	}
+	cpu, previous := after.cpu, before.cpu
+	total := cpu.total() - previous.total()
+	return map[string]int64{
+		"runnable":         after.procsRunning,
+		"blocked":          after.procsBlocked,
+		"swap_used_kb":     after.meminfo["SwapTotal"] - after.meminfo["SwapFree"],
+		"free_kb":          after.meminfo["MemFree"],
+		"swap_in":          perSecond((after.pswpin - before.pswpin) * pageKiB),
+		"swap_out":         perSecond((after.pswpout - before.pswpout) * pageKiB),
+		"context_switches": perSecond(after.ctxt - before.ctxt),
+		"cpu_user":         percent(cpu.User+cpu.Nice-previous.User-previous.Nice, total),
+		"cpu_system":       percent(cpu.System+cpu.IRQ+cpu.SoftIRQ-previous.System-previous.IRQ-previous.SoftIRQ, total),
+		"cpu_idle":         percent(cpu.Idle-previous.Idle, total),
+		"cpu_iowait":       percent(cpu.IOWait-previous.IOWait, total),
+		"cpu_steal":        percent(cpu.Steal-previous.Steal, total),
+	}
+}
+
+// sampleVmstat returns one sample per interval computed from the procfs, as parseVmstatSamples returns
+// them from the output of "vmstat <interval> <measurements+1>"
+func sampleVmstat(ctx context.Context, measurements int, interval time.Duration) ([]map[string]int64, error) {
+	snapshots, err := sampleProcSnapshots(ctx, measurements, interval)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]map[string]int64, 0, measurements)
+	for i := 1; i < len(snapshots); i++ {
+		samples = append(samples, vmstatSample(snapshots[i-1], snapshots[i]))
+	}
+	return samples, nil
+}
+
+// procProcess is a process of /proc/<pid>/stat
+type procProcess struct {
+	pid      int
+	name     string
+	state    string
+	threads  int
+	cpuTicks int64
+	rssPages int64
+}
+
+// parseProcessStat parses /proc/<pid>/stat. The command name is between parentheses and may hold spaces
+// and parentheses, so the fields are counted from the last ")".
+func parseProcessStat(data string) (procProcess, error) {
+	start, end := strings.Index(data, "("), strings.LastIndex(data, ")")
+	if start < 0 || end < start {
+		return procProcess{}, fmt.Errorf("invalid stat line %q", data)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(data[:start]))
+	if err != nil {
+		return procProcess{}, fmt.Errorf("invalid pid in stat line %q", data)
+	}
+	// Fields from the state (field 3 of proc(5)): utime and stime are fields 14 and 15, num_threads
+	// field 20 and rss field 24
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 22 {
+		return procProcess{}, fmt.Errorf("truncated stat line of pid %d", pid)
+	}
+	utime, _ := strconv.ParseInt(fields[11], 10, 64)
+	stime, _ := strconv.ParseInt(fields[12], 10, 64)
+	threads, _ := strconv.Atoi(fields[17])
+	rss, _ := strconv.ParseInt(fields[21], 10, 64)
+	return procProcess{
+		pid:      pid,
+		name:     data[start+1 : end],
+		state:    fields[0],
+		threads:  threads,
+		cpuTicks: utime + stime,
+		rssPages: rss,
+	}, nil
+}
+
+// readProcesses reads /proc/<pid>/stat of every process. The processes exiting meanwhile are skipped.
+func readProcesses() (map[int]procProcess, error) {
+	procRootMu.RLock()
+	root := procRoot
+	procRootMu.RUnlock()
+	if root == "" {
+		return nil, errProcDisabled
+	}
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	processes := make(map[int]procProcess)
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		data, err := readProc(entry.Name() + "/stat")
+		if err != nil {
+			continue
+		}
+		if process, err := parseProcessStat(strings.TrimSpace(string(data))); err == nil {
+			processes[process.pid] = process
+		}
+	}
+	if len(processes) == 0 {
+		return nil, fmt.Errorf("no process found in %s", root)
+	}
+	return processes, nil
+}
+
+// sampleProcessTable reads the processes twice, interval apart, and returns the process table with the
+// CPU each process used in between, in percent of a CPU as top reports it (100% is a full CPU). The CPU
+// time elapsed per CPU is taken from /proc/stat, so the clock tick rate is not needed.
+func sampleProcessTable(ctx context.Context, interval time.Duration) ([]processEntry, error) {
+	var before procSnapshot
+	data, err := readProc("stat")
+	if err == nil {
+		err = parseProcStat(string(data), &before)
+	}
+	if err != nil {
+		return nil, err
+	}
+	first, err := readProcesses()
+	if err != nil {
+		return nil, err
+	}
+	if err := waitInterval(ctx, interval); err != nil {
+		return nil, err
+	}
+	var after procSnapshot
+	if data, err = readProc("stat"); err == nil {
+		err = parseProcStat(string(data), &after)
+	}
+	if err != nil {
+		return nil, err
+	}
+	second, err := readProcesses()
+	if err != nil {
+		return nil, err
+	}
+
+	ticksPerCPU := float64(after.cpu.total()-before.cpu.total()) / float64(after.cpus)
+	pageKiB := int64(os.Getpagesize() / 1024)
+	processes := make([]processEntry, 0, len(second))
+	for pid, process := range second {
+		// A process started in between used all its CPU time in the interval
+		ticks := process.cpuTicks
+		if previous, ok := first[pid]; ok {
+			ticks -= previous.cpuTicks
+		}
+		cpu := 0.0
+		if ticksPerCPU > 0 {
+			cpu = math.Round(float64(ticks)/ticksPerCPU*1000) / 10
+		}
+		processes = append(processes, processEntry{
+			pid:     pid,
+			threads: process.threads,
+			state:   process.state,
+			cpu:     cpu,
+			rssKiB:  process.rssPages * pageKiB,
+			name:    process.name,
+		})
+	}
+	return processes, nil
+}
@@ -0,0 +1,181 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the resolv_conf check: the resolver configuration, and a query of the root NS
+// records sent to one nameserver, with dig or, where it is not installed, nslookup
+const (
+	resolvConfCommand  = "cat /etc/resolv.conf"
+	resolvProbeCommand = "if command -v dig >/dev/null 2>&1; then dig +time=2 +tries=1 @%[1]s . NS; else nslookup -timeout=2 -type=NS . %[1]s; fi 2>&1; true"
+)
+
+// Limits of the resolver configuration. glibc only queries the first resolvMaxNameservers nameservers.
+// Past resolvMaxSearchDomains domains or resolvMaxSearchChars characters the search list is truncated
+// by glibc before 2.26 and by the kubelets before 1.28, which also append it to the cluster domains of
+// the ClusterFirst pods, and each name that is not fully qualified costs a query per domain.
+const (
+	resolvMaxNameservers   = 3
+	resolvMaxSearchDomains = 6
+	resolvMaxSearchChars   = 256
+	// resolvNdotsWarning is the ndots from which every external name is first tried against the search
+	// domains: the default of the pods, unusual on a host
+	resolvNdotsWarning = 5
+)
+
+// resolvConf is the part of /etc/resolv.conf the resolver uses
+type resolvConf struct {
+	nameservers []string
+	search      []string
+	options     map[string]string
+}
+
+// parseResolvConf parses /etc/resolv.conf as glibc does: the last "search" or "domain" line wins and
+// the options of every "options" line add up
+func parseResolvConf(content string) resolvConf {
+	conf := resolvConf{options: make(map[string]string)}
+	for _, line := range strings.Split(content, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") || strings.HasPrefix(fields[0], ";") {
+			continue
+		}
+		switch fields[0] {
+		case "nameserver":
+			if len(fields) > 1 {
+				conf.nameservers = append(conf.nameservers, fields[1])
+			}
+		case "search":
+			conf.search = fields[1:]
+		case "domain":
+			conf.search = fields[1:2]
+		case "options":
+			for _, option := range fields[1:] {
+				name, value, _ := strings.Cut(option, ":")
+				conf.options[name] = value
+			}
+		}
+	}
+	return conf
+}
+
+// option returns a numeric option of the resolver, or its default
+func (c resolvConf) option(name string, defaultValue int) int {
+	if value, err := strconv.Atoi(c.options[name]); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// nameserverReachable reports whether a nameserver answered the probe, whatever the answer (a
+// SERVFAIL or REFUSED reply still proves the nameserver is reachable)
+func nameserverReachable(output string, err error) bool {
+	lower := strings.ToLower(output)
+	for _, failure := range []string{"no servers could be reached", "communications error", "connection refused", "couldn't get address", "network unreachable"} {
+		if strings.Contains(lower, failure) {
+			return false
+		}
+	}
+	return err == nil || strings.TrimSpace(output) != ""
+}
+
+// CheckResolvConf validates the resolver configuration of the host, used by the kubelet, the container
+// runtime pulling images and the pods with dnsPolicy Default, unlike CheckDNSResolution which resolves
+// a few names. No nameserver, or none reachable, is Critical. Nameservers ignored by glibc or
+// unreachable, a high ndots and an oversized search list are Warnings.
+func (nc *NetworkChecker) CheckResolvConf(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = fmt.Sprintf("%s; %s", resolvConfCommand, fmt.Sprintf(resolvProbeCommand, "<nameserver>"))
+
+	output, err := runHostCommand(ctx, resolvConfCommand)
+	if err != nil {
+		if strings.Contains(string(output), "No such file") {
+			result.Status = "Critical"
+			result.Message = "/etc/resolv.conf is missing, the host resolver has no nameserver"
+		} else {
+			result.Message = fmt.Sprintf("Unable to read /etc/resolv.conf: %v", err)
+		}
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	conf := parseResolvConf(string(output))
+	ndots := conf.option("ndots", 1)
+	details["nameservers"] = conf.nameservers
+	details["search"] = conf.search
+	details["ndots"] = ndots
+	details["timeout"] = conf.option("timeout", 5)
+	details["attempts"] = conf.option("attempts", 2)
+	if len(conf.nameservers) == 0 {
+		result.Status = "Critical"
+		result.Message = "No nameserver in /etc/resolv.conf"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	var critical, warning []string
+	used := conf.nameservers
+	if len(used) > resolvMaxNameservers {
+		used = used[:resolvMaxNameservers]
+		warning = append(warning, fmt.Sprintf("%d nameservers, only the first %d are used (%s ignored)",
+			len(conf.nameservers), resolvMaxNameservers, strings.Join(conf.nameservers[resolvMaxNameservers:], ", ")))
+	}
+
+	var unreachable []string
+	probes := make(map[string]bool, len(used))
+	for _, nameserver := range used {
+		address, _, _ := strings.Cut(nameserver, "%")
+		if net.ParseIP(address) == nil {
+			warning = append(warning, fmt.Sprintf("nameserver %q is not an IP address and is ignored", nameserver))
+			continue
+		}
+		output, err := runHostCommand(ctx, fmt.Sprintf(resolvProbeCommand, nameserver))
+		probes[nameserver] = nameserverReachable(string(output), err)
+		if !probes[nameserver] {
+			unreachable = append(unreachable, nameserver)
+		}
+	}
+	details["nameservers_reachable"] = probes
+	switch {
+	case len(probes) == 0:
+		critical = append(critical, "no valid nameserver")
+	case len(unreachable) == len(probes):
+		critical = append(critical, fmt.Sprintf("no nameserver reachable (%s)", strings.Join(unreachable, ", ")))
+	case len(unreachable) > 0:
+		warning = append(warning, fmt.Sprintf("nameserver %s unreachable, each lookup waits for it", strings.Join(unreachable, ", ")))
+	}
+
+	// Every name with fewer than ndots dots is tried against each search domain before being resolved as is
+	details["queries_per_short_name"] = (len(conf.search) + 1) * len(used)
+	if ndots >= resolvNdotsWarning && len(conf.search) > 0 {
+		warning = append(warning, fmt.Sprintf("ndots:%d, names with fewer dots are tried against the %d search domains first", ndots, len(conf.search)))
+	}
+	if chars := len(strings.Join(conf.search, " ")); len(conf.search) > resolvMaxSearchDomains || chars > resolvMaxSearchChars {
+		warning = append(warning, fmt.Sprintf("%d search domains (%d characters), above the %d domains or %d characters older resolvers and kubelets keep",
+			len(conf.search), chars, resolvMaxSearchDomains, resolvMaxSearchChars))
+	}
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Host resolver unusable: %s", strings.Join(append(critical, warning...), "; "))
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Host resolver configuration issues: %s", strings.Join(warning, "; "))
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("%d nameservers reachable, %d search domains, ndots:%d", len(used), len(conf.search), ndots)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
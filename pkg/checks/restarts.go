@@ -0,0 +1,158 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Restart rates of the service_restarts check, in automatic restarts within the last hour
+const (
+	// essentialRestartsWarning and essentialRestartsCritical apply to the services the node cannot run without
+	essentialRestartsWarning  = 1
+	essentialRestartsCritical = 3
+	// flappingRestarts applies to the other services
+	flappingRestarts = 5
+)
+
+// essentialServices are the services whose restarts degrade the node, besides expectations.requiredServices
+var essentialServices = []string{"crio.service", "kubelet.service", "NetworkManager.service"}
+
+// restartJobPattern matches the journal message of systemd scheduling the automatic restart of a unit
+var restartJobPattern = regexp.MustCompile(`^(\S+\.service): Scheduled restart job, restart counter is at (\d+)`)
+
+// CheckServiceRestarts flags the services that keep restarting: systemd restarts a crashing service
+// (Restart=) without marking it failed, so a flapping crio, kubelet or NetworkManager goes unnoticed by the
+// services check. The restarts of the last hour are counted from the journal; NRestarts (restarts since the
+// unit was last started by hand) is reported for context.
+func (sc *SystemChecker) CheckServiceRestarts(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	showCommand := "systemctl show '*.service' -p Id -p NRestarts -p ActiveState --no-pager"
+	journalCommand := "journalctl --no-pager -o cat --since '1 hour ago' _PID=1 | grep 'Scheduled restart job' || true"
+	result.Command = showCommand + "; " + journalCommand
+
+	available, output, err := checkSystemdAvailable(ctx, showCommand)
+	if !available && err == nil {
+		result.Status = "Healthy"
+		result.Message = "Systemd not available in this environment"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to query systemd: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	// systemctl show prints a block of properties per unit, separated by blank lines
+	totals := make(map[string]int)
+	states := make(map[string]string)
+	for _, block := range strings.Split(string(output), "\n\n") {
+		properties := make(map[string]string)
+		for _, line := range strings.Split(block, "\n") {
+			if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+				properties[key] = value
+			}
+		}
+		unit := properties["Id"]
+		if unit == "" {
+			continue
+		}
+		states[unit] = properties["ActiveState"]
+		if restarts, err := strconv.Atoi(properties["NRestarts"]); err == nil && restarts > 0 {
+			totals[unit] = restarts
+		}
+	}
+
+	essential := make(map[string]bool)
+	for _, unit := range essentialServices {
+		essential[unit] = true
+	}
+	if sc.expectations != nil {
+		for _, unit := range sc.expectations.RequiredServices {
+			if !strings.Contains(unit, ".") {
+				unit += ".service"
+			}
+			essential[unit] = true
+		}
+	}
+
+	journal, err := runHostCommand(ctx, journalCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("System journal not available, restart rate unknown: %v", err)
+		details["restart_totals"] = totals
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	lastHour := make(map[string]int)
+	for _, line := range strings.Split(string(journal), "\n") {
+		if match := restartJobPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			lastHour[match[1]]++
+		}
+	}
+
+	units := make([]string, 0, len(lastHour)+len(totals))
+	for unit := range totals {
+		units = append(units, unit)
+	}
+	for unit := range lastHour {
+		if _, ok := totals[unit]; !ok {
+			units = append(units, unit)
+		}
+	}
+	sort.Slice(units, func(i, j int) bool {
+		if lastHour[units[i]] != lastHour[units[j]] {
+			return lastHour[units[i]] > lastHour[units[j]]
+		}
+		return units[i] < units[j]
+	})
+
+	var restarting []map[string]interface{}
+	var critical, warning []string
+	for _, unit := range units {
+		restarting = append(restarting, map[string]interface{}{
+			"unit":           unit,
+			"restarts_1h":    lastHour[unit],
+			"restarts_total": totals[unit],
+			"active_state":   states[unit],
+			"essential":      essential[unit],
+		})
+		count := lastHour[unit]
+		label := fmt.Sprintf("%s (%d restarts in the last hour)", unit, count)
+		switch {
+		case essential[unit] && count >= essentialRestartsCritical:
+			critical = append(critical, label)
+		case essential[unit] && count >= essentialRestartsWarning, count >= flappingRestarts:
+			warning = append(warning, label)
+		}
+	}
+	details["restarting_services"] = restarting
+	details["essential_services"] = len(essential)
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Essential services flapping: %s", strings.Join(critical, ", "))
+		if len(warning) > 0 {
+			result.Message += fmt.Sprintf("; also restarting: %s", strings.Join(warning, ", "))
+		}
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Services restarting: %s", strings.Join(warning, ", "))
+	default:
+		result.Status = "Healthy"
+		result.Message = "No service restarting repeatedly in the last hour"
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
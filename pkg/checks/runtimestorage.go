@@ -0,0 +1,242 @@
+package checks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the disk_runtime_storage check: the image filesystem of the container runtime, its
+// images and the images its containers use. The usage of the filesystem holding the image store is
+// read with df once its mount point is known.
+const (
+	imageFSInfoCommand     = "crictl imagefsinfo"
+	runtimeImagesCommand   = "crictl images -o json"
+	runtimeContainersCmd   = "crictl ps -a -o json"
+	runtimeStorageDFFormat = "df -PB1 '%s'"
+	// imageGCLowMargin is the gap between the high and low thresholds of the kubelet image garbage
+	// collection (imageGCHighThresholdPercent 85, imageGCLowThresholdPercent 80 by default)
+	imageGCLowMargin = 5
+)
+
+// imageFSUsage is a filesystem usage entry of "crictl imagefsinfo"
+type imageFSUsage struct {
+	FsID struct {
+		Mountpoint string `json:"mountpoint"`
+	} `json:"fsId"`
+	UsedBytes struct {
+		Value string `json:"value"`
+	} `json:"usedBytes"`
+	InodesUsed struct {
+		Value string `json:"value"`
+	} `json:"inodesUsed"`
+}
+
+// imageFSInfo is the output of "crictl imagefsinfo". crictl 1.29 lists the image and container
+// filesystems; older versions print a single usage entry as the status.
+type imageFSInfo struct {
+	Status struct {
+		imageFSUsage
+		ImageFilesystems     []imageFSUsage `json:"imageFilesystems"`
+		ContainerFilesystems []imageFSUsage `json:"containerFilesystems"`
+	} `json:"status"`
+}
+
+// runtimeImageList is the part of "crictl images -o json" output used by the check
+type runtimeImageList struct {
+	Images []struct {
+		ID          string   `json:"id"`
+		RepoTags    []string `json:"repoTags"`
+		RepoDigests []string `json:"repoDigests"`
+		Size        string   `json:"size"`
+	} `json:"images"`
+}
+
+// runtimeContainerList is the part of "crictl ps -a -o json" output holding the images of the containers.
+// The image reference is the image ID with containerd and the digest reference with CRI-O.
+type runtimeContainerList struct {
+	Containers []struct {
+		Image struct {
+			Image string `json:"image"`
+		} `json:"image"`
+		ImageRef string `json:"imageRef"`
+	} `json:"containers"`
+}
+
+// CheckRuntimeStorage reports the image storage of the container runtime (CRI-O or containerd), which the
+// disk_space check only sees as one more mount: the image count, the layer storage used according to
+// crictl imagefsinfo and the usage of the filesystem holding it. The kubelet starts the image garbage
+// collection at 85% of that filesystem and evicts pods when it fills up; when the unused images cannot
+// bring it back below the low threshold, every collection fails and starts over (a garbage collection
+// storm) while the pods keep pulling. The usage is Warning from 80% and Critical from 85% by default
+// (thresholds.disk_runtime_storage); the details report the space the unused images would free.
+func (dc *DiskChecker) CheckRuntimeStorage(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	warningPercent, criticalPercent := usageThresholds(dc.thresholds, "disk_runtime_storage", 80, 85)
+	result.Command = fmt.Sprintf("%s; %s; %s", imageFSInfoCommand, runtimeImagesCommand, runtimeContainersCmd)
+
+	output, err := runHostCommand(ctx, imageFSInfoCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to query the image filesystem, crictl not available: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	var info imageFSInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		result.Message = fmt.Sprintf("Unable to parse crictl imagefsinfo: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	imageFS := info.Status.imageFSUsage
+	if len(info.Status.ImageFilesystems) > 0 {
+		imageFS = info.Status.ImageFilesystems[0]
+	}
+	mountpoint := imageFS.FsID.Mountpoint
+	if mountpoint == "" {
+		result.Message = "crictl imagefsinfo reported no image filesystem"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["image_store"] = mountpoint
+	layerBytes, _ := strconv.ParseInt(imageFS.UsedBytes.Value, 10, 64)
+	details["layer_storage_bytes"] = layerBytes
+	if inodes, err := strconv.ParseInt(imageFS.InodesUsed.Value, 10, 64); err == nil {
+		details["layer_storage_inodes"] = inodes
+	}
+	for _, containerFS := range info.Status.ContainerFilesystems {
+		if used, err := strconv.ParseInt(containerFS.UsedBytes.Value, 10, 64); err == nil {
+			details["container_storage_bytes"] = used
+			details["container_storage"] = containerFS.FsID.Mountpoint
+		}
+	}
+
+	// The images and their use by the containers; without them the usage is still reported
+	reclaimable, unused := int64(0), 0
+	images, err := runHostCommand(ctx, runtimeImagesCommand)
+	var imageList runtimeImageList
+	if err == nil {
+		err = json.Unmarshal(images, &imageList)
+	}
+	if err != nil {
+		details["images_error"] = err.Error()
+	} else {
+		used := make(map[string]bool)
+		if containers, err := runHostCommand(ctx, runtimeContainersCmd); err == nil {
+			var containerList runtimeContainerList
+			if err := json.Unmarshal(containers, &containerList); err == nil {
+				for _, container := range containerList.Containers {
+					used[container.ImageRef] = true
+					used[container.Image.Image] = true
+				}
+			}
+		}
+		dangling := 0
+		var totalBytes int64
+		for _, image := range imageList.Images {
+			size, _ := strconv.ParseInt(image.Size, 10, 64)
+			totalBytes += size
+			if len(image.RepoTags) == 0 {
+				dangling++
+			}
+			inUse := used[image.ID] || used["sha256:"+image.ID]
+			for _, digest := range image.RepoDigests {
+				inUse = inUse || used[digest]
+			}
+			if len(used) > 0 && !inUse {
+				unused++
+				reclaimable += size
+			}
+		}
+		details["images"] = len(imageList.Images)
+		details["dangling_images"] = dangling
+		// Images share layers, so their sizes add up to more than the layer storage
+		details["images_bytes"] = totalBytes
+		if len(used) > 0 {
+			details["unused_images"] = unused
+			details["reclaimable_bytes"] = reclaimable
+		}
+	}
+
+	dfCommand := fmt.Sprintf(runtimeStorageDFFormat, mountpoint)
+	result.Command += "; " + dfCommand
+	output, err = runHostCommand(ctx, dfCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to read the usage of the image filesystem %s: %v", mountpoint, err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	filesystems, err := parseDF(string(output))
+	if err != nil {
+		notSupported(ctx, result, err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	if len(filesystems) == 0 {
+		result.Message = fmt.Sprintf("df reported no filesystem for %s", mountpoint)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	fs := filesystems[0]
+	size, _ := strconv.ParseInt(fs["size"], 10, 64)
+	usedBytes, _ := strconv.ParseInt(fs["used"], 10, 64)
+	available, _ := strconv.ParseInt(fs["available"], 10, 64)
+	if size <= 0 {
+		result.Message = fmt.Sprintf("Unable to read the size of the image filesystem %s", mountpoint)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	// As the kubelet does, the usage is the share of the capacity not available
+	percent := float64(size-available) * 100 / float64(size)
+	details["filesystem"] = map[string]interface{}{
+		"mounted_on":      fs["mounted_on"],
+		"size_bytes":      size,
+		"used_bytes":      usedBytes,
+		"available_bytes": available,
+		"used_percent":    math.Round(percent*10) / 10,
+	}
+	details["warning_threshold"] = warningPercent
+	details["critical_threshold"] = criticalPercent
+
+	// Whether removing the unused images brings the filesystem below the low threshold of the image GC
+	lowPercent := float64(criticalPercent - imageGCLowMargin)
+	gcStorm := false
+	if _, known := details["reclaimable_bytes"]; known && percent >= float64(warningPercent) {
+		afterGC := float64(size-available-reclaimable) * 100 / float64(size)
+		details["used_after_gc_percent"] = math.Round(afterGC*10) / 10
+		gcStorm = afterGC >= lowPercent
+	}
+	details["gc_cannot_free_enough"] = gcStorm
+
+	summary := fmt.Sprintf("image filesystem %s %.1f%% used", fs["mounted_on"], percent)
+	if count, ok := details["images"].(int); ok {
+		summary = fmt.Sprintf("%s, %d images", summary, count)
+	}
+	switch {
+	case percent >= float64(criticalPercent) && gcStorm:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Image garbage collection cannot free enough space: %s, the %d unused images free %s", summary, unused, v1alpha1.FormatBytes(float64(reclaimable)))
+	case percent >= float64(criticalPercent):
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Container runtime storage critical: %s", summary)
+	case gcStorm:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Container runtime storage high, image garbage collection would not free enough: %s", summary)
+	case percent >= float64(warningPercent):
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Container runtime storage high: %s", summary)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Container runtime storage normal: %s", summary)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
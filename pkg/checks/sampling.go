@@ -0,0 +1,149 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// Defaults and bounds of spec.sampling. The interval is bounded so a run of the sampled checks stays
+// within a minute or two whatever the configuration.
+const (
+	defaultSamples        = 5
+	defaultSampleInterval = time.Second
+	maxSampleInterval     = 10 * time.Second
+	defaultPercentile     = 95
+)
+
+// sampling are the settings of spec.sampling with the defaults applied
+type sampling struct {
+	samples    int
+	interval   time.Duration
+	percentile int
+	statistic  string
+}
+
+// samplingSettings returns the settings of spec.sampling, or false when it is unset and the checks take
+// a single snapshot
+func samplingSettings(config *v1alpha1.SamplingConfig) (sampling, bool) {
+	if config == nil {
+		return sampling{}, false
+	}
+	settings := sampling{
+		samples:    config.Samples,
+		interval:   defaultSampleInterval,
+		percentile: config.Percentile,
+		statistic:  config.Statistic,
+	}
+	if settings.samples < 2 {
+		settings.samples = defaultSamples
+	}
+	if config.Interval != nil && config.Interval.Duration > 0 {
+		// vmstat and iostat take whole seconds
+		settings.interval = config.Interval.Duration.Round(time.Second)
+	}
+	if settings.interval < time.Second {
+		settings.interval = time.Second
+	}
+	if settings.interval > maxSampleInterval {
+		settings.interval = maxSampleInterval
+	}
+	if settings.percentile <= 0 || settings.percentile >= 100 {
+		settings.percentile = defaultPercentile
+	}
+	if settings.statistic == "" {
+		settings.statistic = "Average"
+	}
+	return settings, true
+}
+
+// seconds returns the interval in the whole seconds of vmstat and iostat
+func (s sampling) seconds() int {
+	return int(s.interval / time.Second)
+}
+
+// duration returns how long the sampling lasts, to extend the built-in timeout of the checks
+func (s sampling) duration() time.Duration {
+	return time.Duration(s.samples) * s.interval
+}
+
+// sampleStats summarizes the samples of a metric
+type sampleStats struct {
+	count      int
+	min        float64
+	avg        float64
+	max        float64
+	percentile float64
+}
+
+// summarizeSamples returns the min, average, max and a percentile (nearest rank) of samples
+func summarizeSamples(samples []float64, percentile int) sampleStats {
+	if len(samples) == 0 {
+		return sampleStats{}
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	sum := 0.0
+	for _, sample := range sorted {
+		sum += sample
+	}
+	rank := int(math.Ceil(float64(percentile) / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sampleStats{
+		count:      len(sorted),
+		min:        sorted[0],
+		avg:        sum / float64(len(sorted)),
+		max:        sorted[len(sorted)-1],
+		percentile: sorted[rank-1],
+	}
+}
+
+// value returns the statistic the thresholds are evaluated on
+func (st sampleStats) value(statistic string) float64 {
+	switch statistic {
+	case "Percentile":
+		return st.percentile
+	case "Max":
+		return st.max
+	}
+	return st.avg
+}
+
+// details returns the statistics for the check details, the percentile keyed by its rank (e.g. "p95")
+func (st sampleStats) details(s sampling) map[string]interface{} {
+	return map[string]interface{}{
+		"samples":                        st.count,
+		"interval_seconds":               s.seconds(),
+		"min":                            math.Round(st.min*100) / 100,
+		"avg":                            math.Round(st.avg*100) / 100,
+		"max":                            math.Round(st.max*100) / 100,
+		fmt.Sprintf("p%d", s.percentile): math.Round(st.percentile*100) / 100,
+		"evaluated_on":                   s.statistic,
+	}
+}
+
+// label describes the statistic evaluated in the messages (e.g. "avg of 5 samples")
+func (s sampling) label() string {
+	switch s.statistic {
+	case "Percentile":
+		return fmt.Sprintf("p%d of %d samples", s.percentile, s.samples)
+	case "Max":
+		return fmt.Sprintf("max of %d samples", s.samples)
+	}
+	return fmt.Sprintf("avg of %d samples", s.samples)
+}
+
+// SetSampling applies the multi-sample settings of spec.sampling to cpu_steal_time and context_switches
+func (sc *SystemChecker) SetSampling(config *v1alpha1.SamplingConfig) {
+	sc.sampling = config
+}
+
+// SetSampling applies the multi-sample settings of spec.sampling to disk_io_wait
+func (dc *DiskChecker) SetSampling(config *v1alpha1.SamplingConfig) {
+	dc.sampling = config
+}
@@ -0,0 +1,116 @@
+package checks
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// SmoothedChecks are the checks whose value can be smoothed through spec.smoothing
+var SmoothedChecks = map[string]bool{
+	"cpu_steal_time":        true,
+	"uninterruptible_tasks": true,
+	"context_switches":      true,
+	"disk_io_wait":          true,
+}
+
+// Defaults and bounds of spec.smoothing
+const (
+	defaultSmoothingSamples = 5
+	maxSmoothingSamples     = 50
+)
+
+// seriesStore keeps the last values of the smoothed checks between runs, keyed by NodeCheck, check and
+// series (e.g. the device of disk_io_wait). Like the event windows, it lives in the executor process and
+// starts over when the executor restarts.
+type seriesStore struct {
+	mu     sync.Mutex
+	series map[string][]float64
+}
+
+// smoothingSeries is the store of the executor
+var smoothingSeries = &seriesStore{series: make(map[string][]float64)}
+
+// resetSmoothing forgets the values of the previous checks, which belong to the host of the previous runner
+func resetSmoothing() {
+	smoothingSeries.mu.Lock()
+	smoothingSeries.series = make(map[string][]float64)
+	smoothingSeries.mu.Unlock()
+}
+
+// add appends a value to a series, keeping its last limit values, and returns them oldest first
+func (s *seriesStore) add(key string, value float64, limit int) []float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	values := append(s.series[key], value)
+	if len(values) > limit {
+		values = values[len(values)-limit:]
+	}
+	s.series[key] = values
+	return append([]float64(nil), values...)
+}
+
+// smoother applies the spec.smoothing of a check
+type smoother struct {
+	key    string
+	config v1alpha1.SmoothingConfig
+}
+
+// smoothing returns the smoother of a check, or false when spec.smoothing leaves the check unsmoothed.
+// scope tells apart the NodeChecks running on the node.
+func smoothing(configs map[string]v1alpha1.SmoothingConfig, scope, check string) (smoother, bool) {
+	config, ok := configs[check]
+	if !ok || !SmoothedChecks[check] {
+		return smoother{}, false
+	}
+	if config.Samples < 2 {
+		config.Samples = defaultSmoothingSamples
+	}
+	if config.Samples > maxSmoothingSamples {
+		config.Samples = maxSmoothingSamples
+	}
+	if config.WeightPercent <= 0 || config.WeightPercent > 100 {
+		config.WeightPercent = int(math.Round(200 / float64(config.Samples+1)))
+	}
+	return smoother{key: scope + "/" + check, config: config}, true
+}
+
+// smooth records the value of a run in a series of the check (empty for its only series) and returns the
+// exponentially weighted moving average of the last values with its details. The average starts at the
+// oldest value kept, so the first runs are averaged over fewer values.
+func (s smoother) smooth(series string, value float64) (float64, map[string]interface{}) {
+	values := smoothingSeries.add(s.key+"/"+series, value, s.config.Samples)
+	weight := float64(s.config.WeightPercent) / 100
+	average := values[0]
+	for _, next := range values[1:] {
+		average = weight*next + (1-weight)*average
+	}
+	average = math.Round(average*100) / 100
+	return average, map[string]interface{}{
+		"ewma":           average,
+		"current":        math.Round(value*100) / 100,
+		"runs":           len(values),
+		"window":         s.config.Samples,
+		"weight_percent": s.config.WeightPercent,
+	}
+}
+
+// smoothingLabel describes the smoothed value in the messages (e.g. "EWMA of 3 runs")
+func smoothingLabel(details map[string]interface{}) string {
+	return fmt.Sprintf("EWMA of %v runs", details["runs"])
+}
+
+// SetSmoothing applies spec.smoothing to cpu_steal_time, uninterruptible_tasks and context_switches.
+// scope identifies the NodeCheck (namespace/name), whose values are averaged separately.
+func (sc *SystemChecker) SetSmoothing(scope string, configs map[string]v1alpha1.SmoothingConfig) {
+	sc.smoothingScope = scope
+	sc.smoothing = configs
+}
+
+// SetSmoothing applies spec.smoothing to disk_io_wait. scope identifies the NodeCheck (namespace/name).
+func (dc *DiskChecker) SetSmoothing(scope string, configs map[string]v1alpha1.SmoothingConfig) {
+	dc.smoothingScope = scope
+	dc.smoothing = configs
+}
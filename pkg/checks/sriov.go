@@ -0,0 +1,186 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the sriov check: the IOMMU groups and units the kernel created, and one line per
+// SR-IOV capable NIC (physical function): interface, maximum VFs, configured VFs, VFs present, VFs bound
+// to a driver, PCI address and driver of the PF
+const (
+	iommuGroupsCommand = "ls /sys/kernel/iommu_groups 2>/dev/null | wc -l"
+	iommuUnitsCommand  = "ls /sys/class/iommu 2>/dev/null; true"
+	sriovPFsCommand    = `for f in /sys/class/net/*/device/sriov_totalvfs; do [ -r "$f" ] || continue; d=${f%/sriov_totalvfs}; n=${d%/device}; ` +
+		`echo "${n##*/} $(cat "$f") $(cat "$d/sriov_numvfs") $(ls -d "$d"/virtfn* 2>/dev/null | wc -l) $(ls -d "$d"/virtfn*/driver 2>/dev/null | wc -l) ` +
+		`$(basename "$(readlink -f "$d")") $(basename "$(readlink "$d/driver")")"; done; true`
+)
+
+// sriovPF is an SR-IOV physical function of the sriovPFsCommand output
+type sriovPF struct {
+	iface      string
+	totalVFs   int
+	numVFs     int
+	present    int
+	bound      int
+	pciAddress string
+	driver     string
+}
+
+// parseSRIOVPFs parses the sriovPFsCommand output, skipping incomplete lines (a NIC removed meanwhile)
+func parseSRIOVPFs(output string) []sriovPF {
+	var pfs []sriovPF
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 6 {
+			continue
+		}
+		pf := sriovPF{iface: fields[0], pciAddress: fields[5]}
+		var err error
+		counts := []*int{&pf.totalVFs, &pf.numVFs, &pf.present, &pf.bound}
+		for i, count := range counts {
+			if *count, err = strconv.Atoi(fields[i+1]); err != nil {
+				break
+			}
+		}
+		if err != nil {
+			continue
+		}
+		if len(fields) > 6 {
+			pf.driver = fields[6]
+		}
+		pfs = append(pfs, pf)
+	}
+	return pfs
+}
+
+// CheckSRIOV validates the IOMMU and the SR-IOV virtual functions of telco/NFV nodes. Without an IOMMU
+// (no IOMMU group, intel_iommu=on or amd_iommu missing from the kernel command line) the VFs cannot be
+// assigned to pods with vfio-pci, which is Critical. The VFs of each physical function are compared with
+// spec.expectations.sriovVirtualFunctions: sriov_numvfs is not persistent, so a NIC that lost its VFs
+// after a reboot (the SR-IOV config daemon or the udev rule did not run) or an expected NIC that is
+// missing is Critical, more VFs than expected or VFs configured but not created are a Warning. Without
+// expectations the VFs of every NIC are only reported.
+func (sc *SystemChecker) CheckSRIOV(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = fmt.Sprintf("%s; %s; cat /proc/cmdline; %s", iommuGroupsCommand, iommuUnitsCommand, sriovPFsCommand)
+
+	output, err := runHostCommand(ctx, iommuGroupsCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to list the IOMMU groups: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	groups, _ := strconv.Atoi(strings.TrimSpace(string(output)))
+	details["iommu_groups"] = groups
+	if units, err := runHostCommand(ctx, iommuUnitsCommand); err == nil {
+		details["iommu_units"] = strings.Fields(string(units))
+	}
+	var iommuParams []string
+	if cmdline, err := runHostCommand(ctx, "cat /proc/cmdline"); err == nil {
+		for _, param := range parseKernelCmdline(string(cmdline)) {
+			switch kernelParamName(param) {
+			case "intel_iommu", "amd_iommu", "iommu", "iommu.passthrough":
+				iommuParams = append(iommuParams, param)
+			}
+		}
+	}
+	details["iommu_kernel_parameters"] = iommuParams
+	passthrough := false
+	for _, param := range iommuParams {
+		passthrough = passthrough || param == "iommu=pt" || param == "iommu.passthrough=1"
+	}
+	details["iommu_passthrough"] = passthrough
+
+	output, err = runHostCommand(ctx, sriovPFsCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to read the SR-IOV functions of the NICs: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	pfs := parseSRIOVPFs(string(output))
+	byInterface := make(map[string]sriovPF, len(pfs))
+	var functions []map[string]interface{}
+	configuredVFs := 0
+	for _, pf := range pfs {
+		byInterface[pf.iface] = pf
+		configuredVFs += pf.numVFs
+		functions = append(functions, map[string]interface{}{
+			"interface":   pf.iface,
+			"pci_address": pf.pciAddress,
+			"driver":      pf.driver,
+			"total_vfs":   pf.totalVFs,
+			"num_vfs":     pf.numVFs,
+			"present_vfs": pf.present,
+			"bound_vfs":   pf.bound,
+		})
+	}
+	details["physical_functions"] = functions
+	details["configured_vfs"] = configuredVFs
+
+	var critical, warning []string
+	if groups == 0 {
+		hint := "add intel_iommu=on or amd_iommu=on to the kernel command line"
+		if len(iommuParams) > 0 {
+			hint = fmt.Sprintf("kernel booted with %s", strings.Join(iommuParams, " "))
+		}
+		critical = append(critical, fmt.Sprintf("IOMMU disabled, VFs cannot be assigned with vfio-pci (%s)", hint))
+	}
+
+	var expected map[string]int
+	if sc.expectations != nil {
+		expected = sc.expectations.SRIOVVirtualFunctions
+	}
+	details["expected_vfs"] = expected
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := expected[name]
+		pf, ok := byInterface[name]
+		switch {
+		case !ok:
+			critical = append(critical, fmt.Sprintf("%s not found or not SR-IOV capable (expected %d VFs)", name, want))
+		case pf.numVFs == 0 && want > 0:
+			critical = append(critical, fmt.Sprintf("%s has no VFs, expected %d (lost after reboot?)", name, want))
+		case pf.numVFs < want:
+			critical = append(critical, fmt.Sprintf("%s has %d VFs, expected %d", name, pf.numVFs, want))
+		case pf.numVFs > want:
+			warning = append(warning, fmt.Sprintf("%s has %d VFs, expected %d", name, pf.numVFs, want))
+		}
+	}
+	for _, pf := range pfs {
+		if pf.present < pf.numVFs {
+			warning = append(warning, fmt.Sprintf("%s has %d of its %d VFs created", pf.iface, pf.present, pf.numVFs))
+		}
+	}
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("SR-IOV not available: %s", strings.Join(append(critical, warning...), "; "))
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("SR-IOV VFs differ: %s", strings.Join(warning, "; "))
+	case len(expected) > 0:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("IOMMU enabled (%d groups), the %d expected NICs have their VFs (%d VFs configured)", groups, len(expected), configuredVFs)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("IOMMU enabled (%d groups), %d SR-IOV capable NICs with %d VFs configured", groups, len(pfs), configuredVFs)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
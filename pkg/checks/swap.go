@@ -0,0 +1,175 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the swap_policy check: the active swap devices, the swap units systemd knows of (from
+// /etc/fstab or a zram generator, active or not) and the command line of the running kubelet, whose flags
+// override its configuration file (kubeletConfigCommand)
+const (
+	swapsCommand          = "cat /proc/swaps"
+	swapUnitsCommand      = "systemctl list-units --type=swap --all --no-legend --plain 2>/dev/null; true"
+	kubeletCmdlineCommand = "tr '\\0' ' ' < /proc/$(pgrep -xo kubelet)/cmdline 2>/dev/null; true"
+)
+
+var (
+	// kubeletFailSwapOnPattern matches failSwapOn in the YAML or JSON kubelet configuration
+	kubeletFailSwapOnPattern = regexp.MustCompile(`(?m)^\s*"?failSwapOn"?\s*:\s*"?(true|false)"?`)
+	// kubeletSwapBehaviorPattern matches memorySwap.swapBehavior in the YAML or JSON kubelet configuration
+	kubeletSwapBehaviorPattern = regexp.MustCompile(`(?m)^\s*"?swapBehavior"?\s*:\s*"?([A-Za-z]+)"?`)
+	// failSwapOnFlagPattern matches --fail-swap-on on the kubelet command line, a bare flag meaning true
+	failSwapOnFlagPattern = regexp.MustCompile(`(?:^|\s)--fail-swap-on(?:=(\S+))?`)
+)
+
+// swapDevice is an active swap area of /proc/swaps
+type swapDevice struct {
+	name     string
+	kind     string
+	sizeKB   int64
+	usedKB   int64
+	priority int
+}
+
+// parseProcSwaps parses /proc/swaps, whose first line is the header
+func parseProcSwaps(output string) []swapDevice {
+	var devices []swapDevice
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 || fields[0] == "Filename" {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		used, _ := strconv.ParseInt(fields[3], 10, 64)
+		priority, _ := strconv.Atoi(fields[4])
+		devices = append(devices, swapDevice{name: fields[0], kind: fields[1], sizeKB: size, usedKB: used, priority: priority})
+	}
+	return devices
+}
+
+// CheckSwapPolicy asserts the swap configuration of the node rather than its swap activity (swap_activity).
+// The kubelet refuses to start on a node with swap on unless failSwapOn is false, so swap turned on by an
+// fstab entry or a zram generator takes the node down at the next kubelet restart. The policy is
+// spec.expectations.swap, Disabled by default: Disabled wants no active swap, NoSwap and LimitedSwap want a
+// kubelet that tolerates swap (failSwapOn: false) with the matching memorySwap.swapBehavior, LimitedSwap
+// also an active swap device. Swap on with a kubelet that would refuse it is Critical, any other
+// deviation, including a swap unit that is off but comes back at the next boot, is a Warning.
+func (sc *SystemChecker) CheckSwapPolicy(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = strings.Join([]string{swapsCommand, swapUnitsCommand, kubeletConfigCommand, kubeletCmdlineCommand}, "; ")
+
+	policy := "Disabled"
+	if sc.expectations != nil && sc.expectations.Swap != "" {
+		policy = sc.expectations.Swap
+		details["policy_declared"] = true
+	}
+	details["policy"] = policy
+
+	output, err := runHostCommand(ctx, swapsCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to read /proc/swaps: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	devices := parseProcSwaps(string(output))
+	var totalKB, usedKB int64
+	var names []string
+	deviceDetails := []map[string]interface{}{}
+	for _, device := range devices {
+		totalKB += device.sizeKB
+		usedKB += device.usedKB
+		names = append(names, device.name)
+		deviceDetails = append(deviceDetails, map[string]interface{}{
+			"name":     device.name,
+			"type":     device.kind,
+			"size_kb":  device.sizeKB,
+			"used_kb":  device.usedKB,
+			"priority": device.priority,
+		})
+	}
+	details["swap_devices"] = deviceDetails
+	details["swap_total_kb"] = totalKB
+	details["swap_used_kb"] = usedKB
+
+	// Swap units systemd would start at boot but that are not active now (e.g. after a swapoff -a)
+	inactiveUnits := []string{}
+	if output, err := runHostCommand(ctx, swapUnitsCommand); err == nil {
+		for _, line := range strings.Split(string(output), "\n") {
+			// UNIT LOAD ACTIVE SUB DESCRIPTION
+			fields := strings.Fields(line)
+			if len(fields) < 4 || fields[1] != "loaded" || fields[2] == "active" {
+				continue
+			}
+			inactiveUnits = append(inactiveUnits, fields[0])
+		}
+	}
+	details["inactive_swap_units"] = inactiveUnits
+
+	output, err = runHostCommand(ctx, kubeletConfigCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Unable to read the kubelet configuration: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	failSwapOn, failSwapOnSource := true, "default"
+	if value := lastSubmatch(kubeletFailSwapOnPattern, string(output)); value != "" {
+		failSwapOn, failSwapOnSource = value == "true", "config"
+	}
+	swapBehavior := lastSubmatch(kubeletSwapBehaviorPattern, string(output))
+	if swapBehavior == "" {
+		swapBehavior = "NoSwap"
+		details["kubelet_swap_behavior_default"] = true
+	}
+	if cmdline, err := runHostCommand(ctx, kubeletCmdlineCommand); err == nil {
+		if match := failSwapOnFlagPattern.FindStringSubmatch(string(cmdline)); match != nil {
+			failSwapOn, failSwapOnSource = match[1] == "" || match[1] == "true", "flag"
+		}
+	}
+	details["kubelet_fail_swap_on"] = failSwapOn
+	details["kubelet_fail_swap_on_source"] = failSwapOnSource
+	details["kubelet_swap_behavior"] = swapBehavior
+
+	swapOn := fmt.Sprintf("%s, %d MiB", strings.Join(names, ", "), totalKB/1024)
+	switch {
+	case len(devices) > 0 && failSwapOn:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Swap is on (%s) and the kubelet has failSwapOn enabled (%s): it refuses to start at its next restart", swapOn, failSwapOnSource)
+	case policy == "Disabled" && len(devices) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Swap is on (%s) while the swap policy is Disabled; the kubelet tolerates it with failSwapOn: false", swapOn)
+	case policy != "Disabled" && failSwapOn:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("The swap policy is %s but the kubelet has failSwapOn enabled (%s), it will refuse to start once swap is on", policy, failSwapOnSource)
+	case policy != "Disabled" && swapBehavior != policy:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("The swap policy is %s but the kubelet swapBehavior is %s", policy, swapBehavior)
+	case policy == "LimitedSwap" && len(devices) == 0:
+		result.Status = "Warning"
+		result.Message = "The swap policy is LimitedSwap but no swap is on, pods cannot use swap"
+	case policy == "Disabled" && len(inactiveUnits) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Swap is off but %s still configured, swap comes back at the next boot", strings.Join(inactiveUnits, ", "))
+	case len(devices) > 0:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Swap is on (%s) as the %s policy allows, the kubelet has failSwapOn: false", swapOn, policy)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Swap is off (swap policy %s)", policy)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
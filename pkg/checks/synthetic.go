@@ -0,0 +1,274 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultSyntheticPodImage is the image used by the pod scheduling check
+	DefaultSyntheticPodImage = "registry.k8s.io/pause:3.9"
+
+	// syntheticLabel marks objects created by the synthetic checks, so leftovers can be cleaned up
+	syntheticLabel = "nodecheck.openshift.io/synthetic"
+	// syntheticNodeLabel records the node a synthetic object was created for
+	syntheticNodeLabel = "nodecheck.openshift.io/node"
+
+	syntheticPodTimeout        = 2 * time.Minute
+	syntheticPollInterval      = 1 * time.Second
+	syntheticStartupWarning    = 30 * time.Second
+	syntheticTeardownWarning   = 30 * time.Second
+	syntheticCleanupTimeout    = 30 * time.Second
+	defaultSyntheticNamespace  = "node-check-operator-system"
+	syntheticPodNamePrefix     = "nodecheck-synthetic-"
+	syntheticPodContainerName  = "pause"
+	syntheticPodSchedulingType = "pod-scheduling"
+)
+
+// syntheticNamespace returns the namespace synthetic objects are created in (the executor's namespace)
+func syntheticNamespace() string {
+	if namespace := os.Getenv("WATCH_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	return defaultSyntheticNamespace
+}
+
+// syntheticLabels returns the labels of a synthetic object of the given type for the node
+func (kc *KubernetesChecker) syntheticLabels(checkType string) map[string]string {
+	return map[string]string{
+		syntheticLabel:     checkType,
+		syntheticNodeLabel: kc.nodeName,
+	}
+}
+
+// syntheticPod builds a minimal pause pod that can only be scheduled on the checked node.
+// It goes through the scheduler (node affinity instead of spec.nodeName) and tolerates all taints.
+func (kc *KubernetesChecker) syntheticPod(namespace, image, checkType string) *corev1.Pod {
+	gracePeriod := int64(0)
+	automount := false
+	runAsNonRoot := true
+	allowPrivilegeEscalation := false
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: syntheticPodNamePrefix,
+			Namespace:    namespace,
+			Labels:       kc.syntheticLabels(checkType),
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: &gracePeriod,
+			AutomountServiceAccountToken:  &automount,
+			Affinity: &corev1.Affinity{
+				NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+							MatchFields: []corev1.NodeSelectorRequirement{{
+								Key:      "metadata.name",
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{kc.nodeName},
+							}},
+						}},
+					},
+				},
+			},
+			Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   &runAsNonRoot,
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []corev1.Container{{
+				Name:  syntheticPodContainerName,
+				Image: image,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("1m"),
+						corev1.ResourceMemory: resource.MustParse("8Mi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("16Mi"),
+					},
+				},
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: &allowPrivilegeEscalation,
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+			}},
+		},
+	}
+}
+
+// cleanupSyntheticPods deletes synthetic pods of the given type left over on the node (e.g. after an executor restart)
+func (kc *KubernetesChecker) cleanupSyntheticPods(ctx context.Context, namespace, checkType string) int {
+	selector := fmt.Sprintf("%s=%s,%s=%s", syntheticLabel, checkType, syntheticNodeLabel, kc.nodeName)
+	pods, err := kc.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return 0
+	}
+	gracePeriod := int64(0)
+	for _, pod := range pods.Items {
+		_ = kc.client.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+	}
+	return len(pods.Items)
+}
+
+// podWaitingReason returns why a pod is not running yet (e.g. "ImagePullBackOff", "Unschedulable: ...")
+func podWaitingReason(pod *corev1.Pod) string {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodScheduled && condition.Status != corev1.ConditionTrue && condition.Reason != "" {
+			return strings.TrimSpace(fmt.Sprintf("%s: %s", condition.Reason, condition.Message))
+		}
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason != "" {
+			return strings.TrimSpace(fmt.Sprintf("%s: %s", status.State.Waiting.Reason, status.State.Waiting.Message))
+		}
+	}
+	return string(pod.Status.Phase)
+}
+
+// CheckPodScheduling creates a pause pod pinned to the node and measures how long it takes to be
+// scheduled, to be running and to be torn down. This exercises the scheduler, kubelet, container
+// runtime, image pulls and CNI together, which the static checks cannot catch.
+func (kc *KubernetesChecker) CheckPodScheduling(ctx context.Context, image string) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	if image == "" {
+		image = DefaultSyntheticPodImage
+	}
+	namespace := syntheticNamespace()
+	details["image"] = image
+	details["namespace"] = namespace
+	result.Command = fmt.Sprintf("%s create pod %s* -n %s --image=%s (pinned to node %s), then delete it",
+		kc.getKubectlCommand(ctx), syntheticPodNamePrefix, namespace, image, kc.nodeName)
+
+	ctx, cancel := withTimeout(ctx, syntheticPodTimeout)
+	defer cancel()
+
+	if leftovers := kc.cleanupSyntheticPods(ctx, namespace, syntheticPodSchedulingType); leftovers > 0 {
+		details["leftover_pods_deleted"] = leftovers
+	}
+
+	start := time.Now()
+	pod, err := kc.client.CoreV1().Pods(namespace).Create(ctx, kc.syntheticPod(namespace, image, syntheticPodSchedulingType), metav1.CreateOptions{})
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Failed to create synthetic pod: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["pod_name"] = pod.Name
+
+	// Make sure the pod is removed even if the check fails or times out
+	deleted := false
+	defer func() {
+		if deleted {
+			return
+		}
+		cleanupCtx, cleanupCancel := context.WithTimeout(context.Background(), syntheticCleanupTimeout)
+		defer cleanupCancel()
+		gracePeriod := int64(0)
+		_ = kc.client.CoreV1().Pods(namespace).Delete(cleanupCtx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+	}()
+
+	// Wait for the pod to be scheduled and running
+	var scheduledAfter, runningAfter time.Duration
+	waitingReason := ""
+	for {
+		current, err := kc.client.CoreV1().Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err == nil {
+			if scheduledAfter == 0 && current.Spec.NodeName != "" {
+				scheduledAfter = time.Since(start)
+			}
+			if current.Status.Phase == corev1.PodRunning {
+				runningAfter = time.Since(start)
+				break
+			}
+			if current.Status.Phase == corev1.PodFailed || current.Status.Phase == corev1.PodSucceeded {
+				waitingReason = fmt.Sprintf("pod terminated with phase %s: %s", current.Status.Phase, current.Status.Message)
+				break
+			}
+			waitingReason = podWaitingReason(current)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(syntheticPollInterval):
+		}
+	}
+
+	if scheduledAfter > 0 {
+		details["time_to_scheduled_ms"] = scheduledAfter.Milliseconds()
+	}
+	if runningAfter == 0 {
+		details["waiting_reason"] = waitingReason
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Synthetic pod did not reach Running within %s: %s", time.Since(start).Round(time.Second), waitingReason)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["time_to_running_ms"] = runningAfter.Milliseconds()
+
+	// Tear the pod down and wait until it is gone
+	teardownStart := time.Now()
+	gracePeriod := int64(0)
+	if err := kc.client.CoreV1().Pods(namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod}); err != nil && !apierrors.IsNotFound(err) {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Synthetic pod started in %s but could not be deleted: %v", runningAfter.Round(time.Millisecond), err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	var teardownAfter time.Duration
+	for {
+		_, err := kc.client.CoreV1().Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			teardownAfter = time.Since(teardownStart)
+			deleted = true
+			break
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(syntheticPollInterval):
+		}
+	}
+
+	if !deleted {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Synthetic pod started in %s but was not torn down within the check timeout", runningAfter.Round(time.Millisecond))
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["time_to_teardown_ms"] = teardownAfter.Milliseconds()
+
+	switch {
+	case runningAfter > syntheticStartupWarning:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Synthetic pod took %s to start (threshold %s)", runningAfter.Round(time.Millisecond), syntheticStartupWarning)
+	case teardownAfter > syntheticTeardownWarning:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Synthetic pod took %s to tear down (threshold %s)", teardownAfter.Round(time.Millisecond), syntheticTeardownWarning)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Synthetic pod running in %s, torn down in %s", runningAfter.Round(time.Millisecond), teardownAfter.Round(time.Millisecond))
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,321 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	syntheticPVCProvisioningType = "pvc-provisioning"
+	syntheticPVCNamePrefix       = "nodecheck-synthetic-"
+	syntheticPVCSize             = "1Gi"
+	syntheticVolumeName          = "data"
+	syntheticVolumeMountPath     = "/data"
+	defaultStorageClassLabel     = "default"
+
+	// syntheticVolumeTimeout bounds provisioning, attach, mount and teardown for a single storage class
+	syntheticVolumeTimeout   = 5 * time.Minute
+	syntheticVolumeWarning   = 2 * time.Minute
+	syntheticTeardownTimeout = 1 * time.Minute
+)
+
+// volumeProbe is the outcome of the provisioning test for a single storage class
+type volumeProbe struct {
+	status  string
+	message string
+	details map[string]interface{}
+}
+
+// cleanupSyntheticPVCs deletes synthetic PVCs of the given type left over on the node (e.g. after an executor restart)
+func (kc *KubernetesChecker) cleanupSyntheticPVCs(ctx context.Context, namespace, checkType string) int {
+	selector := fmt.Sprintf("%s=%s,%s=%s", syntheticLabel, checkType, syntheticNodeLabel, kc.nodeName)
+	pvcs, err := kc.client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return 0
+	}
+	for _, pvc := range pvcs.Items {
+		_ = kc.client.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, pvc.Name, metav1.DeleteOptions{})
+	}
+	return len(pvcs.Items)
+}
+
+// syntheticPVC builds a 1Gi ReadWriteOnce claim for the storage class ("" uses the default storage class)
+func (kc *KubernetesChecker) syntheticPVC(namespace, storageClass string) *corev1.PersistentVolumeClaim {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: syntheticPVCNamePrefix,
+			Namespace:    namespace,
+			Labels:       kc.syntheticLabels(syntheticPVCProvisioningType),
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse(syntheticPVCSize),
+				},
+			},
+		},
+	}
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+	return pvc
+}
+
+// pvcPendingReason returns why a claim is not bound yet, from its phase and the latest events
+func (kc *KubernetesChecker) pvcPendingReason(ctx context.Context, pvc *corev1.PersistentVolumeClaim) string {
+	reason := fmt.Sprintf("claim is %s", pvc.Status.Phase)
+	events, err := kc.client.CoreV1().Events(pvc.Namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=PersistentVolumeClaim,involvedObject.name=%s", pvc.Name),
+	})
+	if err != nil || len(events.Items) == 0 {
+		return reason
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[i].LastTimestamp.Before(&events.Items[j].LastTimestamp)
+	})
+	latest := events.Items[len(events.Items)-1]
+	return strings.TrimSpace(fmt.Sprintf("%s: %s", latest.Reason, latest.Message))
+}
+
+// CheckPVCProvisioning provisions a 1Gi PVC for each storage class, mounts it in a pause pod pinned to
+// the node and measures how long the claim takes to be bound and the pod to be running with the volume
+// mounted. This validates the storage path end-to-end (provisioner, CSI node plugin, attach and mount).
+// An empty storageClasses list tests the cluster's default storage class.
+func (kc *KubernetesChecker) CheckPVCProvisioning(ctx context.Context, image string, storageClasses []string) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	if image == "" {
+		image = DefaultSyntheticPodImage
+	}
+	if len(storageClasses) == 0 {
+		storageClasses = []string{""}
+	}
+	namespace := syntheticNamespace()
+	details["image"] = image
+	details["namespace"] = namespace
+	details["size"] = syntheticPVCSize
+	result.Command = fmt.Sprintf("%s create pvc %s* -n %s (%s per storage class) and mount it in a pod pinned to node %s, then delete both",
+		kc.getKubectlCommand(ctx), syntheticPVCNamePrefix, namespace, syntheticPVCSize, kc.nodeName)
+
+	// Pods go first: a claim is only removed once no pod uses it
+	leftovers := kc.cleanupSyntheticPods(ctx, namespace, syntheticPVCProvisioningType) +
+		kc.cleanupSyntheticPVCs(ctx, namespace, syntheticPVCProvisioningType)
+	if leftovers > 0 {
+		details["leftover_objects_deleted"] = leftovers
+	}
+
+	classDetails := make(map[string]interface{})
+	critical, warning, healthy := []string{}, []string{}, []string{}
+	for _, storageClass := range storageClasses {
+		name := storageClass
+		if name == "" {
+			name = defaultStorageClassLabel
+		}
+		probe := kc.probeVolume(ctx, namespace, image, storageClass)
+		probe.details["status"] = probe.status
+		probe.details["message"] = probe.message
+		classDetails[name] = probe.details
+
+		summary := fmt.Sprintf("%s: %s", name, probe.message)
+		switch probe.status {
+		case "Critical":
+			critical = append(critical, summary)
+		case "Warning":
+			warning = append(warning, summary)
+		default:
+			healthy = append(healthy, summary)
+		}
+	}
+	details["storage_classes"] = classDetails
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("PVC provisioning failed for %d of %d storage classes: %s", len(critical), len(storageClasses), strings.Join(critical, "; "))
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("PVC provisioning degraded for %d of %d storage classes: %s", len(warning), len(storageClasses), strings.Join(warning, "; "))
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("PVC provisioned and mounted for %d storage classes (%s)", len(storageClasses), strings.Join(healthy, "; "))
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
+
+// probeVolume provisions, mounts and deletes a synthetic PVC of a single storage class
+func (kc *KubernetesChecker) probeVolume(ctx context.Context, namespace, image, storageClass string) volumeProbe {
+	probe := volumeProbe{details: make(map[string]interface{})}
+
+	ctx, cancel := withTimeout(ctx, syntheticVolumeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	pvc, err := kc.client.CoreV1().PersistentVolumeClaims(namespace).Create(ctx, kc.syntheticPVC(namespace, storageClass), metav1.CreateOptions{})
+	if err != nil {
+		probe.status = "Warning"
+		probe.message = fmt.Sprintf("failed to create PVC: %v", err)
+		return probe
+	}
+	probe.details["pvc_name"] = pvc.Name
+
+	pod := kc.syntheticPod(namespace, image, syntheticPVCProvisioningType)
+	pod.Spec.Volumes = []corev1.Volume{{
+		Name: syntheticVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc.Name},
+		},
+	}}
+	pod.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: syntheticVolumeName, MountPath: syntheticVolumeMountPath}}
+	pod, err = kc.client.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		kc.deleteSyntheticVolume(namespace, "", pvc.Name)
+		probe.status = "Warning"
+		probe.message = fmt.Sprintf("failed to create pod: %v", err)
+		return probe
+	}
+	probe.details["pod_name"] = pod.Name
+
+	// Make sure the pod and the claim are removed even if the check fails or times out
+	deleted := false
+	defer func() {
+		if !deleted {
+			kc.deleteSyntheticVolume(namespace, pod.Name, pvc.Name)
+		}
+	}()
+
+	// Wait for the claim to be bound (provisioning) and the pod to be running (attach and mount).
+	// With WaitForFirstConsumer binding the claim is only provisioned once the pod is scheduled.
+	var boundAfter, runningAfter time.Duration
+	waitingReason := ""
+	for {
+		if boundAfter == 0 {
+			current, err := kc.client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvc.Name, metav1.GetOptions{})
+			if err == nil {
+				if current.Status.Phase == corev1.ClaimBound {
+					boundAfter = time.Since(start)
+					probe.details["volume_name"] = current.Spec.VolumeName
+					if current.Spec.StorageClassName != nil {
+						probe.details["storage_class"] = *current.Spec.StorageClassName
+					}
+				} else {
+					waitingReason = kc.pvcPendingReason(ctx, current)
+				}
+			}
+		}
+		if boundAfter > 0 {
+			current, err := kc.client.CoreV1().Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+			if err == nil {
+				if current.Status.Phase == corev1.PodRunning {
+					runningAfter = time.Since(start)
+					break
+				}
+				if current.Status.Phase == corev1.PodFailed || current.Status.Phase == corev1.PodSucceeded {
+					waitingReason = fmt.Sprintf("pod terminated with phase %s: %s", current.Status.Phase, current.Status.Message)
+					break
+				}
+				waitingReason = podWaitingReason(current)
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(syntheticPollInterval):
+		}
+	}
+
+	if boundAfter == 0 {
+		probe.details["waiting_reason"] = waitingReason
+		probe.status = "Critical"
+		probe.message = fmt.Sprintf("PVC not bound within %s: %s", time.Since(start).Round(time.Second), waitingReason)
+		return probe
+	}
+	probe.details["time_to_bound_ms"] = boundAfter.Milliseconds()
+	if runningAfter == 0 {
+		probe.details["waiting_reason"] = waitingReason
+		probe.status = "Critical"
+		probe.message = fmt.Sprintf("PVC bound in %s but not mounted within %s: %s", boundAfter.Round(time.Millisecond), time.Since(start).Round(time.Second), waitingReason)
+		return probe
+	}
+	probe.details["time_to_running_ms"] = runningAfter.Milliseconds()
+	probe.details["attach_mount_ms"] = (runningAfter - boundAfter).Milliseconds()
+
+	// Tear down the pod, then the claim, and wait until both are gone
+	teardownStart := time.Now()
+	if err := kc.waitSyntheticVolumeDeleted(ctx, namespace, pod.Name, pvc.Name); err != nil {
+		probe.status = "Warning"
+		probe.message = fmt.Sprintf("mounted in %s but teardown failed: %v", runningAfter.Round(time.Millisecond), err)
+		return probe
+	}
+	deleted = true
+	teardownAfter := time.Since(teardownStart)
+	probe.details["time_to_teardown_ms"] = teardownAfter.Milliseconds()
+
+	switch {
+	case runningAfter > syntheticVolumeWarning:
+		probe.status = "Warning"
+		probe.message = fmt.Sprintf("took %s to provision and mount (threshold %s)", runningAfter.Round(time.Millisecond), syntheticVolumeWarning)
+	case teardownAfter > syntheticVolumeWarning:
+		probe.status = "Warning"
+		probe.message = fmt.Sprintf("took %s to tear down (threshold %s)", teardownAfter.Round(time.Millisecond), syntheticVolumeWarning)
+	default:
+		probe.status = "Healthy"
+		probe.message = fmt.Sprintf("bound in %s, mounted in %s, torn down in %s",
+			boundAfter.Round(time.Millisecond), runningAfter.Round(time.Millisecond), teardownAfter.Round(time.Millisecond))
+	}
+	return probe
+}
+
+// waitSyntheticVolumeDeleted deletes the synthetic pod and then its claim, waiting until both are gone
+func (kc *KubernetesChecker) waitSyntheticVolumeDeleted(ctx context.Context, namespace, podName, pvcName string) error {
+	gracePeriod := int64(0)
+	if err := kc.client.CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete pod: %v", err)
+	}
+	if err := kc.client.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, pvcName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete PVC: %v", err)
+	}
+	for {
+		_, podErr := kc.client.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+		_, pvcErr := kc.client.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+		if apierrors.IsNotFound(podErr) && apierrors.IsNotFound(pvcErr) {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("pod and PVC not deleted within the check timeout")
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(syntheticPollInterval):
+		}
+	}
+}
+
+// deleteSyntheticVolume removes the synthetic pod (if any) and claim without waiting, on a fresh context
+// so that cleanup also happens when the check context has expired
+func (kc *KubernetesChecker) deleteSyntheticVolume(namespace, podName, pvcName string) {
+	ctx, cancel := context.WithTimeout(context.Background(), syntheticTeardownTimeout)
+	defer cancel()
+	gracePeriod := int64(0)
+	if podName != "" {
+		_ = kc.client.CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod})
+	}
+	_ = kc.client.CoreV1().PersistentVolumeClaims(namespace).Delete(ctx, pvcName, metav1.DeleteOptions{})
+}
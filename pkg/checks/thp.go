@@ -0,0 +1,73 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// selectedSysfsMode returns the selected value of a sysfs setting listing the alternatives, with the
+// selected one in brackets (e.g. "always madvise [never]")
+func selectedSysfsMode(value string) string {
+	for _, field := range strings.Fields(value) {
+		if strings.HasPrefix(field, "[") && strings.HasSuffix(field, "]") {
+			return strings.Trim(field, "[]")
+		}
+	}
+	return strings.TrimSpace(value)
+}
+
+// CheckTHP checks the Transparent Huge Pages configuration. Without expectations it only reports the
+// configured modes; with spec.expectations.transparentHugePages (and transparentHugePagesDefrag) a node
+// with different modes is Critical, since databases typically require THP to be disabled ("never").
+func (sc *SystemChecker) CheckTHP(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	result.Command = "cat /sys/kernel/mm/transparent_hugepage/enabled /sys/kernel/mm/transparent_hugepage/defrag"
+	enabledData, err := readProcFile(ctx, "/sys/kernel/mm/transparent_hugepage/enabled")
+	if err != nil {
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Failed to read the Transparent Huge Pages configuration: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	enabled := selectedSysfsMode(string(enabledData))
+	details["enabled"] = enabled
+	defrag := ""
+	if defragData, err := readProcFile(ctx, "/sys/kernel/mm/transparent_hugepage/defrag"); err == nil {
+		defrag = selectedSysfsMode(string(defragData))
+		details["defrag"] = defrag
+	}
+
+	var mismatches []string
+	if sc.expectations != nil && sc.expectations.TransparentHugePages != "" {
+		details["expected_enabled"] = sc.expectations.TransparentHugePages
+		if enabled != sc.expectations.TransparentHugePages {
+			mismatches = append(mismatches, fmt.Sprintf("enabled: expected %s, got %s", sc.expectations.TransparentHugePages, enabled))
+		}
+	}
+	if sc.expectations != nil && sc.expectations.TransparentHugePagesDefrag != "" {
+		details["expected_defrag"] = sc.expectations.TransparentHugePagesDefrag
+		if defrag != sc.expectations.TransparentHugePagesDefrag {
+			mismatches = append(mismatches, fmt.Sprintf("defrag: expected %s, got %s", sc.expectations.TransparentHugePagesDefrag, defrag))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Transparent Huge Pages mismatch: %s", strings.Join(mismatches, "; "))
+	} else {
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Transparent Huge Pages: enabled=%s, defrag=%s", enabled, defrag)
+	}
+
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,83 @@
+package checks
+
+import (
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// ThresholdChecks are the checks whose usage thresholds can be tuned through spec.thresholds
+var ThresholdChecks = map[string]bool{
+	"memory":               true,
+	"file_descriptors":     true,
+	"disk_space":           true,
+	"disk_inode_usage":     true,
+	"pressure_stall":       true,
+	"conntrack":            true,
+	"inotify":              true,
+	"process_limits":       true,
+	"disk_runtime_storage": true,
+	"disk_wear_level":      true,
+}
+
+// VerificationThresholds are the thresholds of the post-maintenance verification profile, 10 points
+// below the built-in ones of each check (half of them for pressure_stall), so a node returning to service
+// has some headroom rather than just being below the alerting thresholds
+var VerificationThresholds = map[string]v1alpha1.CheckThresholds{
+	"memory":               {Warning: 70, Critical: 80},
+	"file_descriptors":     {Warning: 70, Critical: 80},
+	"disk_space":           {Warning: 75, Critical: 85},
+	"disk_inode_usage":     {Warning: 75, Critical: 85},
+	"pressure_stall":       {Warning: 20, Critical: 40},
+	"conntrack":            {Warning: 65, Critical: 80},
+	"inotify":              {Warning: 65, Critical: 80},
+	"process_limits":       {Warning: 65, Critical: 80},
+	"disk_runtime_storage": {Warning: 70, Critical: 75},
+	"disk_wear_level":      {Warning: 70, Critical: 80},
+}
+
+// TightenThresholds returns the thresholds of the verification profile: for each check the lower of the
+// configured and the VerificationThresholds values
+func TightenThresholds(configured map[string]v1alpha1.CheckThresholds) map[string]v1alpha1.CheckThresholds {
+	tightened := make(map[string]v1alpha1.CheckThresholds, len(VerificationThresholds))
+	for name, strict := range VerificationThresholds {
+		current := configured[name]
+		if current.Warning > 0 && current.Warning < strict.Warning {
+			strict.Warning = current.Warning
+		}
+		if current.Critical > 0 && current.Critical < strict.Critical {
+			strict.Critical = current.Critical
+		}
+		tightened[name] = strict
+	}
+	return tightened
+}
+
+// usageThresholds returns the warning and critical usage percentages of a check, falling back
+// to the built-in ones for the values not set in spec.thresholds
+func usageThresholds(thresholds map[string]v1alpha1.CheckThresholds, name string, warning, critical int) (int, int) {
+	configured, ok := thresholds[name]
+	if !ok {
+		return warning, critical
+	}
+	if configured.Warning > 0 {
+		warning = configured.Warning
+	}
+	if configured.Critical > 0 {
+		critical = configured.Critical
+	}
+	return warning, critical
+}
+
+// SetThresholds applies the usage thresholds from spec.thresholds
+func (sc *SystemChecker) SetThresholds(thresholds map[string]v1alpha1.CheckThresholds) {
+	sc.thresholds = thresholds
+}
+
+// SetTimeDrift applies the clock offset thresholds from spec.timeDrift
+func (sc *SystemChecker) SetTimeDrift(thresholds *v1alpha1.TimeDriftThresholds) {
+	sc.timeDrift = thresholds
+}
+
+// SetThresholds applies the usage thresholds from spec.thresholds
+func (dc *DiskChecker) SetThresholds(thresholds map[string]v1alpha1.CheckThresholds) {
+	dc.thresholds = thresholds
+}
@@ -0,0 +1,188 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// timeDriftWarningMs and timeDriftCriticalMs are the built-in clock offsets of the time_drift check:
+	// TLS certificates and service account tokens are validated against the clock, and etcd and the
+	// kubelet leases already misbehave with offsets of a second
+	timeDriftWarningMs  = 100
+	timeDriftCriticalMs = 1000
+)
+
+var (
+	// chronyOffsetPattern matches the offset of the system clock in chronyc tracking
+	chronyOffsetPattern = regexp.MustCompile(`System time\s*:\s*([0-9.]+) seconds (fast|slow)`)
+	// timesyncOffsetPattern matches the offset in timedatectl timesync-status (e.g. "Offset: -1.234ms")
+	timesyncOffsetPattern = regexp.MustCompile(`Offset:\s*([+-]?[0-9.]+(?:ns|us|µs|ms|s))`)
+)
+
+// daemonClockOffset reads the offset of the system clock from the time synchronization daemon, in
+// milliseconds (positive when the clock is ahead). It returns the daemon and the command that gave it.
+func daemonClockOffset(ctx context.Context) (float64, string, string, bool) {
+	command := "chronyc tracking 2>/dev/null"
+	if output, err := runHostCommand(ctx, command); err == nil {
+		if match := chronyOffsetPattern.FindStringSubmatch(string(output)); match != nil {
+			if seconds, err := strconv.ParseFloat(match[1], 64); err == nil {
+				if match[2] == "slow" {
+					seconds = -seconds
+				}
+				return seconds * 1000, "chronyd", command, true
+			}
+		}
+	}
+
+	// ntpq prints the offset to the system peer (marked with "*") in milliseconds, in the 9th column
+	command = "ntpq -pn 2>/dev/null"
+	if output, err := runHostCommand(ctx, command); err == nil {
+		for _, line := range strings.Split(string(output), "\n") {
+			if !strings.HasPrefix(line, "*") {
+				continue
+			}
+			if fields := strings.Fields(line[1:]); len(fields) >= 9 {
+				if offset, err := strconv.ParseFloat(fields[8], 64); err == nil {
+					// ntpq reports the offset of the peer relative to the clock
+					return -offset, "ntpd", command, true
+				}
+			}
+		}
+	}
+
+	command = "timedatectl timesync-status 2>/dev/null"
+	if output, err := runHostCommand(ctx, command); err == nil {
+		if match := timesyncOffsetPattern.FindStringSubmatch(string(output)); match != nil {
+			if offset, err := time.ParseDuration(match[1]); err == nil {
+				// timesyncd reports the offset of the server relative to the clock, as ntpq
+				return -float64(offset) / float64(time.Millisecond), "systemd-timesyncd", command, true
+			}
+		}
+	}
+	return 0, "", "", false
+}
+
+// apiServerClockOffset compares the clock with the Date header of the API server, in milliseconds
+// (positive when the clock is ahead). The header has a resolution of one second, so the offset comes
+// with an uncertainty of half a second plus half the round trip, also returned.
+func apiServerClockOffset(ctx context.Context) (float64, float64, error) {
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return 0, 0, err
+	}
+	httpClient, err := rest.HTTPClientFor(config)
+	if err != nil {
+		return 0, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(config.Host, "/")+"/version", nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	sent := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, 0, err
+	}
+	resp.Body.Close()
+	received := time.Now()
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return 0, 0, fmt.Errorf("API server response without a valid Date header: %v", err)
+	}
+	roundTrip := received.Sub(sent)
+	// The header truncates the time of the server to the second: the middle of that second is the best guess
+	serverTime = serverTime.Add(500 * time.Millisecond)
+	local := sent.Add(roundTrip / 2)
+	offset := float64(local.Sub(serverTime)) / float64(time.Millisecond)
+	uncertainty := 500 + float64(roundTrip)/float64(time.Millisecond)/2
+	return offset, uncertainty, nil
+}
+
+// CheckTimeDrift measures how far the clock of the node is from the reference time, while ntp_sync only
+// checks that a synchronization daemon runs: a small drift already breaks the validation of certificates
+// and tokens. The offset is read from chronyd, ntpd or systemd-timesyncd; the Date header of the API
+// server gives a coarse second opinion, which also catches a daemon synchronized to a wrong source.
+func (sc *SystemChecker) CheckTimeDrift(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	warningMs, criticalMs := timeDriftWarningMs, timeDriftCriticalMs
+	if sc.timeDrift != nil {
+		if sc.timeDrift.WarningMilliseconds > 0 {
+			warningMs = sc.timeDrift.WarningMilliseconds
+		}
+		if sc.timeDrift.CriticalMilliseconds > 0 {
+			criticalMs = sc.timeDrift.CriticalMilliseconds
+		}
+	}
+	details["warning_threshold_ms"] = warningMs
+	details["critical_threshold_ms"] = criticalMs
+
+	ctx, cancel := withTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// drift is the offset used for the status, from the daemon or the API server
+	var drift float64
+	var source string
+	var commands []string
+
+	daemonOffset, daemon, command, daemonOK := daemonClockOffset(ctx)
+	if daemonOK {
+		details["ntp_daemon"] = daemon
+		details["offset_ms"] = daemonOffset
+		drift, source = math.Abs(daemonOffset), daemon
+		commands = append(commands, command)
+	}
+
+	apiOffset, uncertainty, apiErr := apiServerClockOffset(ctx)
+	commands = append(commands, "GET /version (Date header of the API server)")
+	if apiErr == nil {
+		details["api_server_offset_ms"] = apiOffset
+		details["api_server_uncertainty_ms"] = uncertainty
+		// Only the part of the offset beyond the uncertainty is certain
+		if certain := math.Abs(apiOffset) - uncertainty; certain > drift {
+			drift, source = certain, "API server"
+		}
+	} else {
+		details["api_server_error"] = apiErr.Error()
+	}
+	result.Command = strings.Join(commands, "; ")
+
+	if !daemonOK && apiErr != nil {
+		result.Message = fmt.Sprintf("Unable to measure the clock offset: no offset from chronyd, ntpd or systemd-timesyncd, and the API server could not be reached: %v", apiErr)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["drift_ms"] = drift
+	details["drift_source"] = source
+
+	switch {
+	case drift >= float64(criticalMs):
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Clock is off by %.0fms according to %s (critical threshold %dms)", drift, source, criticalMs)
+	case drift >= float64(warningMs):
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Clock is off by %.0fms according to %s (warning threshold %dms)", drift, source, warningMs)
+	case daemonOK:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Clock offset is %.3fms (%s)", daemonOffset, daemon)
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Clock matches the API server within %.0fms (no offset from a synchronization daemon)", uncertainty)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,395 @@
+package checks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// StatusNotSupported is the status of a check that could not parse the output of a host tool: the
+// tool prints a format unknown to the parsers, so the check cannot tell anything about the node.
+// It replaces the Healthy result that missing columns used to produce.
+const StatusNotSupported = "NotSupported"
+
+// FormatError reports an output of a host tool that the parsers do not recognize
+type FormatError struct {
+	// Tool is the command whose output was parsed (iostat, vmstat, df)
+	Tool string
+	// Version is the version printed by the tool, empty until notSupported detects it
+	Version string
+	Reason  string
+}
+
+func (e *FormatError) Error() string {
+	version := e.Version
+	if version == "" {
+		version = "unknown version"
+	}
+	return fmt.Sprintf("%s output not recognized (%s): %s", e.Tool, version, e.Reason)
+}
+
+// toolVersionCommands are the commands printing the version of the tools whose output is parsed
+var toolVersionCommands = map[string]string{
+	"iostat": "iostat -V",
+	"vmstat": "vmstat -V",
+	"df":     "df --version",
+}
+
+// toolVersionPattern matches the version number in the first line printed by toolVersionCommands
+var toolVersionPattern = regexp.MustCompile(`[0-9]+(\.[0-9]+)+`)
+
+// toolVersions caches the detected versions: the host tools only change with an OS update, which
+// restarts the node. The cache is cleared when the HostRunner is replaced.
+var toolVersions = struct {
+	sync.Mutex
+	versions map[string]string
+}{versions: make(map[string]string)}
+
+// toolVersion returns the package and version of a host tool (e.g. "sysstat 12.5.4", "procps-ng
+// 3.3.17", "GNU coreutils 8.32"), or "" when it cannot be determined
+func toolVersion(ctx context.Context, tool string) string {
+	toolVersions.Lock()
+	version, ok := toolVersions.versions[tool]
+	toolVersions.Unlock()
+	if ok {
+		return version
+	}
+
+	command, ok := toolVersionCommands[tool]
+	if !ok {
+		return ""
+	}
+	output, err := runHostCommand(ctx, command)
+	if err != nil {
+		return ""
+	}
+	version = parseToolVersion(tool, string(output))
+	if version != "" {
+		toolVersions.Lock()
+		toolVersions.versions[tool] = version
+		toolVersions.Unlock()
+	}
+	return version
+}
+
+// parseToolVersion extracts the package and version from the version banner of a tool:
+// "sysstat version 12.5.4", "vmstat from procps-ng 3.3.17", "df (GNU coreutils) 8.32",
+// "BusyBox v1.36.1 (...)"
+func parseToolVersion(tool, output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		loc := toolVersionPattern.FindStringIndex(line)
+		if loc == nil {
+			continue
+		}
+		var name []string
+		for _, word := range strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(line[:loc[0]])) {
+			switch word {
+			case tool, "version", "from", "v":
+				continue
+			}
+			name = append(name, word)
+		}
+		if len(name) == 0 {
+			name = []string{tool}
+		}
+		return strings.Join(name, " ") + " " + line[loc[0]:loc[1]]
+	}
+	return ""
+}
+
+// resetToolVersions forgets the detected versions, which belong to the host of the previous runner
+func resetToolVersions() {
+	toolVersions.Lock()
+	toolVersions.versions = make(map[string]string)
+	toolVersions.Unlock()
+}
+
+// notSupported marks the result of a check whose tool output could not be parsed, naming the tool
+// version so the format can be added to the parsers (and to the fixtures of cmd/checkreplay)
+func notSupported(ctx context.Context, result *v1alpha1.CheckResult, err error) {
+	var formatErr *FormatError
+	if errors.As(err, &formatErr) && formatErr.Version == "" {
+		formatErr.Version = toolVersion(ctx, formatErr.Tool)
+	}
+	result.Status = StatusNotSupported
+	result.Message = fmt.Sprintf("%v; the check cannot evaluate this node until this format is supported", err)
+}
+
+// columnLayout maps the columns of a tabular tool output, found by their name in the header line, to
+// the keys of the parsed values. Columns are added, removed and renamed across tool versions (sysstat
+// 12 added the discard and flush columns to iostat -x, procps-ng 4 the gu column to vmstat), so they
+// are never addressed by position.
+type columnLayout struct {
+	tool    string
+	columns int
+	// index is the position of the column of each key
+	index map[string]int
+	// trailing is true when the last column takes the rest of the line (e.g. the mount point of df)
+	trailing bool
+}
+
+// newColumnLayout maps the header fields to keys with aliases (column name → key); unknown columns
+// are ignored. It returns a FormatError when one of the required keys has no column.
+func newColumnLayout(tool string, header []string, aliases map[string]string, required ...string) (*columnLayout, error) {
+	layout := &columnLayout{tool: tool, columns: len(header), index: make(map[string]int)}
+	for i, column := range header {
+		if key, ok := aliases[column]; ok {
+			layout.index[key] = i
+		}
+	}
+	var missing []string
+	for _, key := range required {
+		if _, ok := layout.index[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &FormatError{Tool: tool, Reason: fmt.Sprintf("no column for %s in the header %q", strings.Join(missing, ", "), strings.Join(header, " "))}
+	}
+	return layout, nil
+}
+
+// has reports whether the output has a column for the key
+func (l *columnLayout) has(key string) bool {
+	_, ok := l.index[key]
+	return ok
+}
+
+// row returns the values of a data line by key. A line whose fields do not match the header is a
+// FormatError: its values would be read from the wrong columns.
+func (l *columnLayout) row(fields []string) (map[string]string, error) {
+	if len(fields) != l.columns && !(l.trailing && len(fields) > l.columns) {
+		return nil, &FormatError{Tool: l.tool, Reason: fmt.Sprintf("a line has %d fields, the header %d: %q", len(fields), l.columns, strings.Join(fields, " "))}
+	}
+	values := make(map[string]string, len(l.index))
+	for key, i := range l.index {
+		values[key] = fields[i]
+		if l.trailing && i == l.columns-1 {
+			values[key] = strings.Join(fields[i:], " ")
+		}
+	}
+	return values, nil
+}
+
+// parseNumber parses a number printed by a tool, which uses a decimal comma in some locales
+func parseNumber(value string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+// iostatColumns maps the columns of iostat -x to the keys of the device statistics. Older sysstat
+// versions name the queue size avgqu-sz.
+var iostatColumns = map[string]string{
+	"r/s":      "reads_per_sec",
+	"rkB/s":    "read_kb_per_sec",
+	"rrqm/s":   "read_requests_merged",
+	"r_await":  "read_await_ms",
+	"rareq-sz": "read_avg_queue_size",
+	"w/s":      "writes_per_sec",
+	"wkB/s":    "write_kb_per_sec",
+	"wrqm/s":   "write_requests_merged",
+	"w_await":  "write_await_ms",
+	"wareq-sz": "write_avg_queue_size",
+	"aqu-sz":   "avg_queue_size",
+	"avgqu-sz": "avg_queue_size",
+	"%util":    "utilization_percent",
+}
+
+// parseIostat parses the device statistics of the last report of iostat -x, keyed by device and
+// by the keys of iostatColumns. The devices are returned in the order of the output.
+func parseIostat(output string, required ...string) ([]string, map[string]map[string]float64, error) {
+	lines := strings.Split(output, "\n")
+	headers := iostatHeaders(lines)
+	if len(headers) == 0 {
+		return nil, nil, &FormatError{Tool: "iostat", Reason: "no Device header line"}
+	}
+	return parseIostatReport(lines, headers[len(headers)-1], required...)
+}
+
+// parseIostatReports parses the device statistics of each report of iostat -x, as parseIostat. The first
+// report, the averages since boot, is left out when iostat printed others.
+func parseIostatReports(output string, required ...string) ([]map[string]map[string]float64, error) {
+	lines := strings.Split(output, "\n")
+	headers := iostatHeaders(lines)
+	if len(headers) == 0 {
+		return nil, &FormatError{Tool: "iostat", Reason: "no Device header line"}
+	}
+	if len(headers) > 1 {
+		headers = headers[1:]
+	}
+	reports := make([]map[string]map[string]float64, 0, len(headers))
+	for _, headerIndex := range headers {
+		_, stats, err := parseIostatReport(lines, headerIndex, required...)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, stats)
+	}
+	return reports, nil
+}
+
+// iostatHeaders returns the indexes of the Device header lines of the reports of iostat -x
+func iostatHeaders(lines []string) []int {
+	var headers []int
+	for i, line := range lines {
+		// sysstat 11 prints "Device:", 12 "Device"
+		if fields := strings.Fields(line); len(fields) > 0 && strings.TrimSuffix(fields[0], ":") == "Device" {
+			headers = append(headers, i)
+		}
+	}
+	return headers
+}
+
+// parseIostatReport parses the device statistics of the report of iostat -x starting at a header line
+func parseIostatReport(lines []string, headerIndex int, required ...string) ([]string, map[string]map[string]float64, error) {
+	layout, err := newColumnLayout("iostat", strings.Fields(lines[headerIndex]), iostatColumns, required...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var devices []string
+	stats := make(map[string]map[string]float64)
+	for _, line := range lines[headerIndex+1:] {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			break // End of the device report
+		}
+		values, err := layout.row(fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		deviceStats := make(map[string]float64)
+		for key, value := range values {
+			if number, err := parseNumber(value); err == nil {
+				deviceStats[key] = number
+			}
+		}
+		devices = append(devices, fields[0])
+		stats[fields[0]] = deviceStats
+	}
+	return devices, stats, nil
+}
+
+// vmstatColumns maps the columns of vmstat to the keys of its values
+var vmstatColumns = map[string]string{
+	"r":    "runnable",
+	"b":    "blocked",
+	"swpd": "swap_used_kb",
+	"free": "free_kb",
+	"si":   "swap_in",
+	"so":   "swap_out",
+	"cs":   "context_switches",
+	"us":   "cpu_user",
+	"sy":   "cpu_system",
+	"id":   "cpu_idle",
+}
+
+// parseVmstat parses the last sample of vmstat, keyed by the keys of vmstatColumns
+func parseVmstat(output string, required ...string) (map[string]int64, error) {
+	samples, err := parseVmstatSamples(output, required...)
+	if err != nil {
+		return nil, err
+	}
+	return samples[len(samples)-1], nil
+}
+
+// parseVmstatSamples parses the samples of vmstat printed after its last header, keyed by the keys of
+// vmstatColumns. The first sample, the averages since boot, is left out when vmstat printed others.
+func parseVmstatSamples(output string, required ...string) ([]map[string]int64, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	headerIndex := -1
+	for i, line := range lines {
+		if fields := strings.Fields(line); len(fields) > 2 && fields[0] == "r" && fields[1] == "b" {
+			headerIndex = i
+		}
+	}
+	if headerIndex == -1 {
+		return nil, &FormatError{Tool: "vmstat", Reason: "no \"r b\" header line"}
+	}
+	if headerIndex == len(lines)-1 {
+		return nil, &FormatError{Tool: "vmstat", Reason: "no sample after the header"}
+	}
+	layout, err := newColumnLayout("vmstat", strings.Fields(lines[headerIndex]), vmstatColumns, required...)
+	if err != nil {
+		return nil, err
+	}
+	rows := lines[headerIndex+1:]
+	if len(rows) > 1 {
+		rows = rows[1:]
+	}
+	samples := make([]map[string]int64, 0, len(rows))
+	for _, line := range rows {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		row, err := layout.row(strings.Fields(line))
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[string]int64, len(row))
+		for key, value := range row {
+			number, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, &FormatError{Tool: "vmstat", Reason: fmt.Sprintf("%s is not a number: %q", key, value)}
+			}
+			values[key] = number
+		}
+		samples = append(samples, values)
+	}
+	return samples, nil
+}
+
+// dfColumns maps the columns of df -P to the keys of the filesystem usage. The size columns are
+// named after the block size without -h (1-blocks with -B1), and POSIX mode names the use column Capacity.
+var dfColumns = map[string]string{
+	"Filesystem":  "filesystem",
+	"Type":        "fs_type",
+	"Size":        "size",
+	"1K-blocks":   "size",
+	"1024-blocks": "size",
+	"1-blocks":    "size",
+	"Used":        "used",
+	"Avail":       "available",
+	"Available":   "available",
+	"Use%":        "use_percent",
+	"Capacity":    "use_percent",
+	"Mounted on":  "mounted_on",
+}
+
+// parseDF parses the filesystems of df -P (with or without -T), one map per filesystem keyed by the
+// keys of dfColumns. The mount point is the rest of the line, it may contain spaces.
+func parseDF(output string) ([]map[string]string, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	header := strings.Fields(lines[0])
+	// "Mounted on" is the only header of two words
+	if n := len(header); n >= 2 && header[n-2] == "Mounted" && header[n-1] == "on" {
+		header = append(header[:n-2], "Mounted on")
+	}
+	layout, err := newColumnLayout("df", header, dfColumns, "filesystem", "size", "used", "available", "use_percent", "mounted_on")
+	if err != nil {
+		return nil, err
+	}
+	if layout.index["mounted_on"] != len(header)-1 {
+		return nil, &FormatError{Tool: "df", Reason: "the mount point is not the last column"}
+	}
+	layout.trailing = true
+
+	var filesystems []map[string]string
+	for _, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		values, err := layout.row(fields)
+		if err != nil {
+			return nil, err
+		}
+		filesystems = append(filesystems, values)
+	}
+	return filesystems, nil
+}
@@ -0,0 +1,129 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// cpuVulnerabilitiesDir lists one file per CPU vulnerability known to the kernel, with its state
+// ("Not affected", "Mitigation: ...", "Vulnerable...")
+const cpuVulnerabilitiesDir = "/sys/devices/system/cpu/vulnerabilities"
+
+var mitigationOffParameters = map[string]string{
+	"mitigations=off":               "",
+	"nopti":                         "meltdown",
+	"pti=off":                       "meltdown",
+	"nospectre_v1":                  "spectre_v1",
+	"nospectre_v2":                  "spectre_v2",
+	"spectre_v2=off":                "spectre_v2",
+	"spectre_bhi=off":               "spectre_v2",
+	"nospec_store_bypass_disable":   "spec_store_bypass",
+	"spec_store_bypass_disable=off": "spec_store_bypass",
+	"l1tf=off":                      "l1tf",
+	"mds=off":                       "mds",
+	"tsx_async_abort=off":           "tsx_async_abort",
+	"kvm.nx_huge_pages=off":         "itlb_multihit",
+	"srbds=off":                     "srbds",
+	"mmio_stale_data=off":           "mmio_stale_data",
+	"retbleed=off":                  "retbleed",
+	"spec_rstack_overflow=off":      "spec_rstack_overflow",
+	"gather_data_sampling=off":      "gather_data_sampling",
+	"reg_file_data_sampling=off":    "reg_file_data_sampling",
+}
+
+// CheckCPUVulnerabilities reports the CPU vulnerabilities (Spectre, Meltdown, MDS, Retbleed, ...)
+// the kernel leaves unmitigated, and the mitigations disabled on the kernel command line. Disabling
+// them is sometimes a deliberate trade for latency (e.g. mitigations=off in a performance profile),
+// so it is a Warning; a vulnerability left open without such a parameter, usually an outdated kernel
+// or microcode, is Critical.
+func (sc *SystemChecker) CheckCPUVulnerabilities(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+
+	output, err := runHostCommandWithCommand(ctx, fmt.Sprintf("grep -H . %s/*", cpuVulnerabilitiesDir), result)
+	result.Command += "; cat /proc/cmdline"
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to read %s (kernel without vulnerability reporting?): %v", cpuVulnerabilitiesDir, err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	vulnerabilities := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		file, state, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		vulnerabilities[path.Base(file)] = strings.TrimSpace(state)
+	}
+	if len(vulnerabilities) == 0 {
+		result.Message = fmt.Sprintf("No vulnerability reported in %s", cpuVulnerabilitiesDir)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	details["vulnerabilities"] = vulnerabilities
+
+	// Mitigations disabled on the kernel command line, and the vulnerabilities they explain
+	var disabled []string
+	disabledFor := make(map[string]bool)
+	if cmdline, err := runHostCommand(ctx, "cat /proc/cmdline"); err == nil {
+		for _, arg := range strings.Fields(string(cmdline)) {
+			if affected, ok := mitigationOffParameters[arg]; ok {
+				disabled = append(disabled, arg)
+				disabledFor[affected] = true
+			}
+		}
+	}
+	details["mitigations_disabled_by_cmdline"] = disabled
+
+	names := make([]string, 0, len(vulnerabilities))
+	for name := range vulnerabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var unmitigated, optedOut []string
+	mitigated := 0
+	for _, name := range names {
+		state := vulnerabilities[name]
+		switch {
+		case strings.HasPrefix(state, "Vulnerable") && (disabledFor[""] || disabledFor[name]):
+			optedOut = append(optedOut, name)
+		case strings.HasPrefix(state, "Vulnerable"):
+			unmitigated = append(unmitigated, fmt.Sprintf("%s (%s)", name, state))
+		case strings.Contains(state, "Mitigation"):
+			// itlb_multihit reports "KVM: Mitigation: ..."
+			mitigated++
+		}
+	}
+	details["unmitigated"] = unmitigated
+	details["disabled_by_cmdline"] = optedOut
+	details["mitigated"] = mitigated
+
+	switch {
+	case len(unmitigated) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("CPU vulnerable without mitigation: %s", strings.Join(unmitigated, ", "))
+		if len(disabled) > 0 {
+			result.Message += fmt.Sprintf("; mitigations disabled by %s", strings.Join(disabled, " "))
+		}
+	case len(disabled) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Mitigations disabled on the kernel command line (%s)", strings.Join(disabled, " "))
+		if len(optedOut) > 0 {
+			result.Message += fmt.Sprintf(", leaving the CPU vulnerable to %s", strings.Join(optedOut, ", "))
+		}
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("%d CPU vulnerabilities known to the kernel, %d mitigated, the others not affecting this CPU", len(vulnerabilities), mitigated)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,281 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Host commands of the disk_wear_level check: the disks with their rotational flag, so only SSDs and
+// NVMe drives are read, and the SMART data of each of them (the same output as disk_smart)
+const (
+	solidStateDisksCommand = "lsblk -d -n -o NAME,ROTA"
+	smartDataFormat        = "smartctl -a /dev/%s"
+)
+
+// Projected endurance, in days, below which a drive is reported
+const (
+	wearEnduranceWarningDays  = 180
+	wearEnduranceCriticalDays = 30
+)
+
+// ataWearAttributes are the ATA attributes whose normalized value is the remaining life of an SSD in
+// percent (100 when new), by vendor: Samsung, Intel, SandForce/Kingston, Crucial/Micron and SK hynix.
+// The first one found is used.
+var ataWearAttributes = []string{
+	"Wear_Leveling_Count",
+	"Media_Wearout_Indicator",
+	"SSD_Life_Left",
+	"Percent_Lifetime_Remain",
+	"Remaining_Lifetime_Perc",
+}
+
+// nvmeHealthPattern matches a "Name: value" line of the NVMe SMART/Health Information log
+var nvmeHealthPattern = regexp.MustCompile(`^([A-Za-z][A-Za-z ]+?):\s+(.+)$`)
+
+// driveWear is the wear of an SSD or NVMe drive from the output of smartctl -a
+type driveWear struct {
+	model string
+	// percentUsed is the share of the rated endurance consumed, -1 when the drive reports none. NVMe
+	// drives report it directly and may exceed 100; ATA drives report the remaining life.
+	percentUsed int
+	source      string
+	// availableSpare and spareThreshold are the NVMe spare blocks in percent, -1 when not reported
+	availableSpare int
+	spareThreshold int
+	powerOnHours   int64
+	// dataWrittenTB is the data written to an NVMe drive, in TB
+	dataWrittenTB float64
+}
+
+// leadingInt parses the leading digits of a SMART value, dropping the thousands separators of NVMe
+// logs ("12,345") and the minutes of some ATA raw values ("12345h+04m+33.120s")
+func leadingInt(value string) (int64, bool) {
+	value = strings.ReplaceAll(strings.TrimSpace(value), ",", "")
+	end := 0
+	for end < len(value) && value[end] >= '0' && value[end] <= '9' {
+		end++
+	}
+	number, err := strconv.ParseInt(value[:end], 10, 64)
+	return number, err == nil
+}
+
+// parseDriveWear reads the wear indicators of the output of smartctl -a for an NVMe or ATA drive
+func parseDriveWear(output string) driveWear {
+	wear := driveWear{percentUsed: -1, availableSpare: -1, spareThreshold: -1}
+	ataRemaining := map[string]int{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if match := nvmeHealthPattern.FindStringSubmatch(line); match != nil {
+			name, value := match[1], match[2]
+			switch name {
+			case "Model Number", "Device Model":
+				wear.model = value
+			case "Percentage Used":
+				if used, ok := leadingInt(value); ok {
+					wear.percentUsed, wear.source = int(used), "Percentage Used"
+				}
+			case "Available Spare":
+				if spare, ok := leadingInt(value); ok {
+					wear.availableSpare = int(spare)
+				}
+			case "Available Spare Threshold":
+				if threshold, ok := leadingInt(value); ok {
+					wear.spareThreshold = int(threshold)
+				}
+			case "Power On Hours":
+				wear.powerOnHours, _ = leadingInt(value)
+			case "Data Units Written":
+				// A data unit is 1000 blocks of 512 bytes
+				if units, ok := leadingInt(value); ok {
+					wear.dataWrittenTB = float64(units) * 512000 / 1e12
+				}
+			}
+			continue
+		}
+		// ID# ATTRIBUTE_NAME FLAG VALUE WORST THRESH TYPE UPDATED WHEN_FAILED RAW_VALUE
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		if fields[1] == "Power_On_Hours" {
+			wear.powerOnHours, _ = leadingInt(fields[9])
+			continue
+		}
+		if value, err := strconv.Atoi(fields[3]); err == nil {
+			ataRemaining[fields[1]] = value
+		}
+	}
+	if wear.percentUsed < 0 {
+		for _, attribute := range ataWearAttributes {
+			if remaining, ok := ataRemaining[attribute]; ok {
+				wear.percentUsed, wear.source = 100-remaining, attribute
+				break
+			}
+		}
+	}
+	return wear
+}
+
+// projectedEnduranceDays extrapolates the days left until the drive reaches its rated endurance at
+// the wear rate of its power-on time so far, -1 when there is not enough wear or history to tell
+func (w driveWear) projectedEnduranceDays() int {
+	if w.percentUsed < 1 || w.powerOnHours < 24*30 {
+		return -1
+	}
+	if w.percentUsed >= 100 {
+		return 0
+	}
+	hoursPerPercent := float64(w.powerOnHours) / float64(w.percentUsed)
+	return int(hoursPerPercent * float64(100-w.percentUsed) / 24)
+}
+
+// CheckWearLevel reports the wear of the SSDs and NVMe drives (rotational disks are skipped), which
+// disk_smart only sees once the drive fails its self-assessment. The consumed endurance is the
+// Percentage Used of the NVMe health log, or 100 minus the normalized value of the vendor wear
+// attribute of ATA SSDs (Wear_Leveling_Count, Media_Wearout_Indicator, SSD_Life_Left, ...). Warning from
+// 80% and Critical from 90% used (tunable with thresholds.disk_wear_level). The wear rate over the
+// power-on hours projects the remaining endurance: Warning under 180 days, Critical under 30. An NVMe
+// drive whose available spare fell below its threshold is Critical.
+func (dc *DiskChecker) CheckWearLevel(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+	}
+	result.Command = solidStateDisksCommand
+	warningPercent, criticalPercent := usageThresholds(dc.thresholds, "disk_wear_level", 80, 90)
+	details["warning_threshold"] = warningPercent
+	details["critical_threshold"] = criticalPercent
+
+	output, err := runHostCommand(ctx, solidStateDisksCommand)
+	if err != nil {
+		result.Message = fmt.Sprintf("Failed to list disk devices: %v", err)
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+	var devices []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || fields[1] != "0" {
+			continue
+		}
+		if dc.checkDevice(fields[0], !strings.HasPrefix(fields[0], "sd") && !strings.HasPrefix(fields[0], "nvme")) {
+			devices = append(devices, fields[0])
+		}
+	}
+	sort.Strings(devices)
+	if len(devices) == 0 {
+		result.Status = "Healthy"
+		result.Message = "No SSD or NVMe drive to check"
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	var critical, warning, unreadable, noIndicator []string
+	drives := map[string]interface{}{}
+	checked := 0
+	mostUsed := -1
+	for _, device := range devices {
+		command := fmt.Sprintf(smartDataFormat, device)
+		result.Command = solidStateDisksCommand + "; " + command
+		smartOutput, err := runHostCommand(ctx, command)
+		if err != nil {
+			unreadable = append(unreadable, device)
+			drives[device] = map[string]interface{}{"status": "not_accessible", "error": err.Error()}
+			continue
+		}
+		wear := parseDriveWear(string(smartOutput))
+		drive := map[string]interface{}{"model": wear.model}
+		if wear.powerOnHours > 0 {
+			drive["power_on_hours"] = wear.powerOnHours
+		}
+		if wear.dataWrittenTB > 0 {
+			drive["data_written_tb"] = float64(int(wear.dataWrittenTB*100)) / 100
+		}
+		if wear.availableSpare >= 0 {
+			drive["available_spare_percent"] = wear.availableSpare
+			drive["available_spare_threshold_percent"] = wear.spareThreshold
+		}
+		status := "Healthy"
+		if wear.availableSpare >= 0 && wear.spareThreshold > 0 && wear.availableSpare < wear.spareThreshold {
+			status = "Critical"
+			critical = append(critical, fmt.Sprintf("%s: available spare %d%% below its threshold %d%%", device, wear.availableSpare, wear.spareThreshold))
+		}
+		if wear.percentUsed < 0 {
+			noIndicator = append(noIndicator, device)
+			drive["status"] = status
+			drives[device] = drive
+			continue
+		}
+		checked++
+		if wear.percentUsed > mostUsed {
+			mostUsed = wear.percentUsed
+		}
+		drive["percent_used"] = wear.percentUsed
+		drive["wear_source"] = wear.source
+		switch {
+		case wear.percentUsed >= criticalPercent:
+			status = "Critical"
+			critical = append(critical, fmt.Sprintf("%s: %d%% of its endurance used", device, wear.percentUsed))
+		case wear.percentUsed >= warningPercent:
+			if status == "Healthy" {
+				status = "Warning"
+			}
+			warning = append(warning, fmt.Sprintf("%s: %d%% of its endurance used", device, wear.percentUsed))
+		}
+		if days := wear.projectedEnduranceDays(); days >= 0 {
+			drive["projected_endurance_days"] = days
+			switch {
+			case days < wearEnduranceCriticalDays && wear.percentUsed < criticalPercent:
+				status = "Critical"
+				critical = append(critical, fmt.Sprintf("%s: endurance exhausted in about %d days at its wear rate", device, days))
+			case days < wearEnduranceWarningDays && wear.percentUsed < warningPercent:
+				if status == "Healthy" {
+					status = "Warning"
+				}
+				warning = append(warning, fmt.Sprintf("%s: endurance exhausted in about %d days at its wear rate", device, days))
+			}
+		}
+		drive["status"] = status
+		drives[device] = drive
+	}
+	details["drives"] = drives
+	if len(unreadable) > 0 {
+		details["unreadable_drives"] = unreadable
+	}
+	if len(noIndicator) > 0 {
+		details["drives_without_wear_indicator"] = noIndicator
+	}
+	if mostUsed >= 0 {
+		details["max_percent_used"] = mostUsed
+	}
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("SSD wear critical: %s", strings.Join(append(critical, warning...), ", "))
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("SSD wear warning: %s", strings.Join(warning, ", "))
+	case checked == 0 && len(unreadable) > 0:
+		result.Message = fmt.Sprintf("Unable to read the SMART data of %s", strings.Join(unreadable, ", "))
+	case checked == 0:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("No wear indicator reported by %s", strings.Join(noIndicator, ", "))
+	default:
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("%d SSD/NVMe drives checked, the most worn has used %d%% of its endurance", checked, mostUsed)
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
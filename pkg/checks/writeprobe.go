@@ -0,0 +1,130 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// writeProbeCommand writes and fsyncs a 4 KiB probe file in each of the paths the kubelet, the container
+// runtime and the configuration management need writable, then removes it. It prints a line per path:
+// "<path>|ok|<mount>|" or "<path>|failed|<mount>|<error>", <mount> being the target, type and options
+// of the filesystem holding the path.
+const writeProbeCommand = `for dir in /var /var/log /etc; do mnt=$(findmnt -no TARGET,FSTYPE,OPTIONS -T "$dir" 2>/dev/null | head -1); ` +
+	`f="$dir/.node-check-write-probe"; if err=$(dd if=/dev/zero of="$f" bs=4096 count=1 conv=fsync status=none 2>&1); ` +
+	`then rm -f "$f"; echo "$dir|ok|$mnt|"; else rm -f "$f" 2>/dev/null; echo "$dir|failed|$mnt|$err"; fi; done`
+
+// writeProbe is a line of writeProbeCommand
+type writeProbe struct {
+	path    string
+	ok      bool
+	mount   string
+	options []string
+	err     string
+}
+
+// parseWriteProbes parses the output of writeProbeCommand
+func parseWriteProbes(output string) []writeProbe {
+	var probes []writeProbe
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), "|", 4)
+		if len(fields) != 4 || (fields[1] != "ok" && fields[1] != "failed") {
+			continue
+		}
+		probe := writeProbe{path: fields[0], ok: fields[1] == "ok", err: strings.TrimSpace(fields[3])}
+		if mount := strings.Fields(fields[2]); len(mount) == 3 {
+			probe.mount = mount[0]
+			probe.options = strings.Split(mount[2], ",")
+		}
+		probes = append(probes, probe)
+	}
+	return probes
+}
+
+// readOnly reports whether a failed probe hit a read-only filesystem, from the error or the mount options
+func (p writeProbe) readOnly() bool {
+	if strings.Contains(strings.ToLower(p.err), "read-only file system") {
+		return true
+	}
+	for _, option := range p.options {
+		if option == "ro" {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckWriteProbe actively writes to /var, /var/log and /etc rather than looking for remount messages in
+// the kernel log (see CheckMountPoints), which are lost with the ring buffer and missed when the
+// filesystem turns read-only without one: an fsync'd write fails as soon as the root filesystem, or the
+// RHCOS /var and /etc overlays, went read-only. A read-only filesystem or an I/O error is Critical (the
+// kubelet can no longer write pod data, logs and certificates), any other write failure (e.g. no space
+// left) is a Warning.
+func (dc *DiskChecker) CheckWriteProbe(ctx context.Context) *v1alpha1.CheckResult {
+	details := make(map[string]interface{})
+	result := &v1alpha1.CheckResult{
+		Timestamp: metav1.Now(),
+		Status:    "Unknown",
+		Command:   writeProbeCommand,
+	}
+
+	output, err := runHostCommand(ctx, writeProbeCommand)
+	probes := parseWriteProbes(string(output))
+	if len(probes) == 0 {
+		if err != nil {
+			result.Message = fmt.Sprintf("Unable to run the write probe: %v", err)
+		} else {
+			result.Message = "The write probe reported no path"
+		}
+		result.Details = mapToRawExtension(details)
+		return result
+	}
+
+	var critical, warning []string
+	probeDetails := make([]map[string]interface{}, 0, len(probes))
+	for _, probe := range probes {
+		entry := map[string]interface{}{
+			"path":     probe.path,
+			"writable": probe.ok,
+		}
+		if probe.mount != "" {
+			entry["mount"] = probe.mount
+			entry["options"] = strings.Join(probe.options, ",")
+		}
+		if !probe.ok {
+			entry["error"] = probe.err
+			lowerErr := strings.ToLower(probe.err)
+			switch {
+			case probe.readOnly():
+				critical = append(critical, fmt.Sprintf("%s is read-only (%s)", probe.path, probe.err))
+			case strings.Contains(lowerErr, "input/output error"):
+				critical = append(critical, fmt.Sprintf("%s: I/O error writing (%s)", probe.path, probe.err))
+			default:
+				warning = append(warning, fmt.Sprintf("%s is not writable (%s)", probe.path, probe.err))
+			}
+		}
+		probeDetails = append(probeDetails, entry)
+	}
+	details["probes"] = probeDetails
+
+	switch {
+	case len(critical) > 0:
+		result.Status = "Critical"
+		result.Message = fmt.Sprintf("Filesystem not writable: %s", strings.Join(append(critical, warning...), "; "))
+	case len(warning) > 0:
+		result.Status = "Warning"
+		result.Message = fmt.Sprintf("Write probe failed: %s", strings.Join(warning, "; "))
+	default:
+		paths := make([]string, 0, len(probes))
+		for _, probe := range probes {
+			paths = append(paths, probe.path)
+		}
+		result.Status = "Healthy"
+		result.Message = fmt.Sprintf("Write probe succeeded on %s", strings.Join(paths, ", "))
+	}
+	result.Details = mapToRawExtension(details)
+	return result
+}
@@ -0,0 +1,169 @@
+// Package cloudevents sends the status transitions of the checks as CloudEvents 1.0, for Knative eventing
+// and event-driven automation. The HTTP binding posts each event in binary content mode (ce-* headers, the
+// data as body) to a sink such as a Knative broker. No Kafka client is built in: the Kafka binding produces
+// the events in structured content mode through the HTTP API of a Kafka bridge (Strimzi Kafka Bridge).
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/pkg/kafkabridge"
+)
+
+// Event types: the check turned Critical or Warning, or is Healthy again
+const (
+	TypeCritical  = "io.nodecheck.check.critical"
+	TypeWarning   = "io.nodecheck.check.warning"
+	TypeRecovered = "io.nodecheck.check.recovered"
+)
+
+// Bindings of spec.cloudEvents.binding
+const (
+	BindingHTTP  = "HTTP"
+	BindingKafka = "Kafka"
+)
+
+// specVersion is the version of the CloudEvents specification of the events
+const specVersion = "1.0"
+
+// requestTimeout bounds the delivery of the events of a run
+const requestTimeout = 10 * time.Second
+
+// Transition is the data of an event: a check of a node changed status
+type Transition struct {
+	Node      string `json:"node"`
+	Namespace string `json:"namespace"`
+	NodeCheck string `json:"nodeCheck"`
+	Check     string `json:"check"`
+	Previous  string `json:"previous"`
+	Current   string `json:"current"`
+	Message   string `json:"message"`
+}
+
+// Event is a CloudEvent in the JSON format. NodeName is the "nodename" extension attribute, so
+// Knative triggers can filter the events of a node.
+type Event struct {
+	SpecVersion     string     `json:"specversion"`
+	ID              string     `json:"id"`
+	Source          string     `json:"source"`
+	Type            string     `json:"type"`
+	Subject         string     `json:"subject"`
+	Time            time.Time  `json:"time"`
+	DataContentType string     `json:"datacontenttype"`
+	NodeName        string     `json:"nodename"`
+	Data            Transition `json:"data"`
+}
+
+// Type returns the event type of a transition to a status, "" for the statuses without one
+func Type(current string) string {
+	switch current {
+	case "Critical":
+		return TypeCritical
+	case "Warning":
+		return TypeWarning
+	case "Healthy":
+		return TypeRecovered
+	}
+	return ""
+}
+
+// New returns the event of a transition seen at a time. The ID is derived from the NodeCheck UID, the
+// check and the time, so a retried delivery keeps its ID and receivers can drop the duplicates.
+func New(uid string, transition Transition, at time.Time) Event {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s/%s/%s", uid, transition.Check, at.UTC().Format(time.RFC3339Nano))))
+	return Event{
+		SpecVersion:     specVersion,
+		ID:              hex.EncodeToString(hash[:16]),
+		Source:          fmt.Sprintf("/apis/nodecheck.openshift.io/v1alpha1/namespaces/%s/nodechecks/%s", transition.Namespace, transition.NodeCheck),
+		Type:            Type(transition.Current),
+		Subject:         transition.Check,
+		Time:            at.UTC(),
+		DataContentType: "application/json",
+		NodeName:        transition.Node,
+		Data:            transition,
+	}
+}
+
+// Send delivers events to a sink with a binding. For the Kafka binding, sink is the URL of the Kafka
+// bridge and topic the Kafka topic.
+func Send(ctx context.Context, binding, sink, topic string, events []Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	switch binding {
+	case "", BindingHTTP:
+		for _, event := range events {
+			if err := sendHTTP(ctx, sink, event); err != nil {
+				return err
+			}
+		}
+		return nil
+	case BindingKafka:
+		if topic == "" {
+			return fmt.Errorf("topic is required for the Kafka binding")
+		}
+		return sendKafka(ctx, sink, topic, events)
+	}
+	return fmt.Errorf("unknown binding %q", binding)
+}
+
+// sendHTTP posts an event in the binary content mode of the HTTP protocol binding
+func sendHTTP(ctx context.Context, sink string, event Event) error {
+	body, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sink, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", event.DataContentType)
+	req.Header.Set("ce-specversion", event.SpecVersion)
+	req.Header.Set("ce-id", event.ID)
+	req.Header.Set("ce-source", event.Source)
+	req.Header.Set("ce-type", event.Type)
+	req.Header.Set("ce-subject", event.Subject)
+	req.Header.Set("ce-time", event.Time.Format(time.RFC3339Nano))
+	req.Header.Set("ce-nodename", event.NodeName)
+	return do(req)
+}
+
+// sendKafka produces the events in the structured content mode of the Kafka protocol binding: the
+// record value is the event in the JSON format and its content-type header application/cloudevents+json.
+// The records are keyed by node, so the events of a node keep their order within a partition.
+func sendKafka(ctx context.Context, bridge, topic string, events []Event) error {
+	records := make([]kafkabridge.Record, 0, len(events))
+	for _, event := range events {
+		records = append(records, kafkabridge.Record{
+			Key:     event.NodeName,
+			Value:   event,
+			Headers: map[string]string{"content-type": "application/cloudevents+json; charset=UTF-8"},
+		})
+	}
+	return kafkabridge.Produce(ctx, bridge, topic, "", false, records)
+}
+
+// do sends a request and fails on the responses other than 2xx
+func do(req *http.Request) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("POST %s: %s: %s", req.URL, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//+kubebuilder:rbac:groups="",resources=secrets,verbs=get
+
+// ChatOpsSecretName is the Secret of the operator namespace enabling the slash commands. Its
+// signingSecret key is the signing secret of the Slack app, used to verify the requests.
+const ChatOpsSecretName = "node-check-operator-chatops"
+
+const (
+	// slackSignatureMaxAge rejects replayed requests, as recommended by Slack
+	slackSignatureMaxAge = 5 * time.Minute
+	// maxSlashCommandBody bounds the size of a slash command request
+	maxSlashCommandBody = 64 * 1024
+	// defaultTopNodes and maxTopNodes bound the nodes listed by "top"
+	defaultTopNodes = 5
+	maxTopNodes     = 20
+)
+
+// topStatuses are the statuses "top" ranks the nodes by
+var topStatuses = map[string]string{"critical": "Critical", "warning": "Warning"}
+
+// chatOpsHelp lists the supported queries
+const chatOpsHelp = "Usage:\n" +
+	"• `status`: NodeChecks per overall status\n" +
+	"• `status <node>`: status of a node and its failing checks\n" +
+	"• `top critical [n]` or `top warning [n]`: nodes with the most Critical or Warning checks"
+
+// HandleSlackCommand answers Slack slash commands (e.g. "/nodecheck status worker-3") with the same
+// summaries as the dashboard. The requests are verified with the Slack signing secret, and the
+// replies are only visible to the user who sent the command.
+func (api *DashboardAPI) HandleSlackCommand(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	signingSecret, err := api.chatOpsSigningSecret(ctx)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, msgChatOpsNotConfigured, map[string]string{"secret": ChatOpsSecretName})
+			return
+		}
+		respondError(c, http.StatusServiceUnavailable, msgChatOpsNotConfigured, map[string]string{"secret": ChatOpsSecretName})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSlashCommandBody))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, msgChatOpsBadRequest, nil)
+		return
+	}
+	if !validSlackSignature(signingSecret, c.GetHeader("X-Slack-Request-Timestamp"), c.GetHeader("X-Slack-Signature"), body, time.Now()) {
+		respondError(c, http.StatusUnauthorized, msgChatOpsUnauthorized, nil)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		respondError(c, http.StatusBadRequest, msgChatOpsBadRequest, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"response_type": "ephemeral",
+		"text":          api.answerChatOpsQuery(ctx, form.Get("text")),
+	})
+}
+
+// chatOpsSigningSecret reads the signing secret of ChatOpsSecretName
+func (api *DashboardAPI) chatOpsSigningSecret(ctx context.Context) ([]byte, error) {
+	secret, err := api.clientset.CoreV1().Secrets(api.namespace).Get(ctx, ChatOpsSecretName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	signingSecret := []byte(strings.TrimSpace(string(secret.Data["signingSecret"])))
+	if len(signingSecret) == 0 {
+		return nil, fmt.Errorf("secret %s has no signingSecret key", ChatOpsSecretName)
+	}
+	return signingSecret, nil
+}
+
+// validSlackSignature verifies the v0 signature of a Slack request: the HMAC-SHA256 of
+// "v0:<timestamp>:<body>" with the signing secret
+func validSlackSignature(signingSecret []byte, timestamp, signature string, body []byte, now time.Time) bool {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return false
+	}
+	mac := hmac.New(sha256.New, signingSecret)
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// answerChatOpsQuery answers the text of a slash command
+func (api *DashboardAPI) answerChatOpsQuery(ctx context.Context, text string) string {
+	fields := strings.Fields(strings.ToLower(text))
+	if len(fields) == 0 || fields[0] == "help" {
+		return chatOpsHelp
+	}
+
+	var nodeChecks v1alpha1.NodeCheckList
+	if err := api.k8sClient.List(ctx, &nodeChecks); err != nil {
+		return fmt.Sprintf("Unable to list NodeChecks: %v", err)
+	}
+	// Generic NodeChecks ("*", "all") have no results of their own
+	items := make([]v1alpha1.NodeCheck, 0, len(nodeChecks.Items))
+	for _, nc := range nodeChecks.Items {
+		if nc.Spec.NodeName != "*" && nc.Spec.NodeName != "all" {
+			items = append(items, nc)
+		}
+	}
+
+	switch {
+	case fields[0] == "status" && len(fields) == 1:
+		return chatOpsClusterStatus(items)
+	case fields[0] == "status":
+		return chatOpsNodeStatus(items, fields[1])
+	case fields[0] == "top" && len(fields) > 1 && topStatuses[fields[1]] != "":
+		limit := defaultTopNodes
+		if len(fields) > 2 {
+			if n, err := strconv.Atoi(fields[2]); err == nil && n > 0 {
+				limit = n
+			}
+		}
+		if limit > maxTopNodes {
+			limit = maxTopNodes
+		}
+		return chatOpsTopNodes(items, fields[1], limit)
+	}
+	return fmt.Sprintf("Unknown query `%s`.\n%s", text, chatOpsHelp)
+}
+
+// chatOpsClusterStatus counts the NodeChecks per overall status
+func chatOpsClusterStatus(items []v1alpha1.NodeCheck) string {
+	counts := map[string]int{}
+	for _, nc := range items {
+		status := nc.Status.OverallStatus
+		if status == "" {
+			status = "Unknown"
+		}
+		counts[status]++
+	}
+	return fmt.Sprintf("%d NodeChecks: %d Healthy, %d Warning, %d Critical, %d Unknown",
+		len(items), counts["Healthy"], counts["Warning"], counts["Critical"], counts["Unknown"])
+}
+
+// chatOpsNodeStatus describes the NodeChecks of a node with their failing checks
+func chatOpsNodeStatus(items []v1alpha1.NodeCheck, node string) string {
+	var lines []string
+	for _, nc := range items {
+		if !strings.EqualFold(nc.Status.NodeName, node) && !strings.EqualFold(nc.Spec.NodeName, node) {
+			continue
+		}
+		summary := summarizeNodeCheck(nc)
+		lines = append(lines, fmt.Sprintf("*%s* (%s/%s): *%s* - %s", summary.NodeName, summary.Namespace, summary.Name,
+			summary.OverallStatus, summary.Message))
+		lines = append(lines, fmt.Sprintf("%d checks: %d Healthy, %d Warning, %d Critical, last run %s",
+			summary.CheckCount, summary.HealthyCount, summary.WarningCount, summary.CriticalCount,
+			summary.LastCheck.UTC().Format(time.RFC3339)))
+		for _, check := range flattenCheckResults(nc.Status.CheckResults) {
+			if check.Status == "Critical" || check.Status == "Warning" {
+				lines = append(lines, fmt.Sprintf("• %s `%s`: %s", check.Status, check.Name, check.Message))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return fmt.Sprintf("No NodeCheck found for node `%s`", node)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// chatOpsTopNodes lists the nodes with the most Critical (or Warning) checks
+func chatOpsTopNodes(items []v1alpha1.NodeCheck, status string, limit int) string {
+	summaries := make([]NodeCheckSummary, 0, len(items))
+	for _, nc := range items {
+		summary := summarizeNodeCheck(nc)
+		if statusCount(summary, status) > 0 {
+			summaries = append(summaries, summary)
+		}
+	}
+	if len(summaries) == 0 {
+		return fmt.Sprintf("No node has %s checks", topStatuses[status])
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if ci, cj := statusCount(summaries[i], status), statusCount(summaries[j], status); ci != cj {
+			return ci > cj
+		}
+		return summaries[i].NodeName < summaries[j].NodeName
+	})
+	if len(summaries) > limit {
+		summaries = summaries[:limit]
+	}
+	lines := []string{fmt.Sprintf("Nodes with the most %s checks:", topStatuses[status])}
+	for i, summary := range summaries {
+		lines = append(lines, fmt.Sprintf("%d. *%s*: %d %s (%s)", i+1, summary.NodeName, statusCount(summary, status), topStatuses[status], summary.OverallStatus))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// statusCount returns the number of checks of a NodeCheck with a status ("critical" or "warning")
+func statusCount(summary NodeCheckSummary, status string) int {
+	if status == "critical" {
+		return summary.CriticalCount
+	}
+	return summary.WarningCount
+}
@@ -0,0 +1,327 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/checks"
+	"github.com/gin-gonic/gin"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+//+kubebuilder:rbac:groups="",resources=users;groups;serviceaccounts,verbs=impersonate
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=userextras/*;uids,verbs=impersonate
+
+const (
+	// templateLabel is set by the operator on the NodeChecks generated by a NodeCheckTemplate
+	templateLabel       = "nodecheck.openshift.io/template"
+	checkUpdateTimeout  = 10 * time.Second
+	maxThresholdPercent = 100
+)
+
+// CheckConfigUpdate is the body of PATCH /api/v2/nodechecks/:namespace/:name/checks/:check.
+// Fields left out are not changed.
+type CheckConfigUpdate struct {
+	Enabled    *bool                     `json:"enabled,omitempty"`
+	Thresholds *v1alpha1.CheckThresholds `json:"thresholds,omitempty"`
+}
+
+// checkToggles returns the spec field enabling each check, keyed by the check name used in the results
+func checkToggles(spec *v1alpha1.NodeCheckSpec) map[string]*bool {
+	sc := &spec.SystemChecks
+	hw := &sc.Hardware
+	disks := &sc.Disks
+	network := &sc.Network
+	kc := &spec.KubernetesChecks
+	return map[string]*bool{
+		"uptime":                 &sc.Uptime,
+		"processes":              &sc.Processes,
+		"resources":              &sc.Resources,
+		"memory":                 &sc.Memory,
+		"uninterruptible_tasks":  &sc.UninterruptibleTasks,
+		"services":               &sc.Services,
+		"system_logs":            &sc.SystemLogs,
+		"file_descriptors":       &sc.FileDescriptors,
+		"zombie_processes":       &sc.ZombieProcesses,
+		"ntp_sync":               &sc.NTPSync,
+		"kernel_panics":          &sc.KernelPanics,
+		"oom_killer":             &sc.OOMKiller,
+		"cpu_frequency":          &sc.CPUFrequency,
+		"interrupts_balance":     &sc.InterruptsBalance,
+		"cpu_steal_time":         &sc.CPUStealTime,
+		"memory_fragmentation":   &sc.MemoryFragmentation,
+		"swap_activity":          &sc.SwapActivity,
+		"context_switches":       &sc.ContextSwitches,
+		"selinux_status":         &sc.SELinuxStatus,
+		"ssh_access":             &sc.SSHAccess,
+		"kernel_modules":         &sc.KernelModules,
+		"kernel_taint":           &sc.KernelTaint,
+		"pressure_stall":         &sc.PressureStall,
+		"entropy":                &sc.Entropy,
+		"transparent_hugepages":  &sc.TransparentHugePages,
+		"huge_pages":             &sc.HugePages,
+		"numa":                   &sc.NUMA,
+		"conntrack":              &sc.Conntrack,
+		"known_issues":           &sc.KnownIssues,
+		"kdump":                  &sc.Kdump,
+		"sysctl_drift":           &sc.SysctlDrift,
+		"inotify":                &sc.Inotify,
+		"service_restarts":       &sc.ServiceRestarts,
+		"core_dumps":             &sc.CoreDumps,
+		"time_drift":             &sc.TimeDrift,
+		"audit":                  &sc.Audit,
+		"cpu_vulnerabilities":    &sc.CPUVulnerabilities,
+		"kernel_cmdline":         &sc.KernelCmdline,
+		"os_updates":             &sc.OSUpdates,
+		"cgroup_driver":          &sc.CgroupDriver,
+		"process_limits":         &sc.ProcessLimits,
+		"orphaned_mounts":        &sc.OrphanedMounts,
+		"userspace_oom":          &sc.UserspaceOOM,
+		"gpu":                    &sc.GPU,
+		"sriov":                  &sc.SRIOV,
+		"kernel_lockups":         &sc.KernelLockups,
+		"swap_policy":            &sc.SwapPolicy,
+		"certificate_expiry":     &sc.CertificateExpiry,
+		"hardware_temperature":   &hw.Temperature,
+		"hardware_ipmi":          &hw.IPMI,
+		"hardware_bmc":           &hw.BMC,
+		"hardware_fan_status":    &hw.FanStatus,
+		"hardware_power_supply":  &hw.PowerSupply,
+		"hardware_memory_errors": &hw.MemoryErrors,
+		"hardware_pcie_errors":   &hw.PCIeErrors,
+		"hardware_cpu_microcode": &hw.CPUMicrocode,
+		"disk_space":             &disks.Space,
+		"disk_smart":             &disks.SMART,
+		"disk_performance":       &disks.Performance,
+		"disk_raid":              &disks.RAID,
+		"disk_pvs":               &disks.PVs,
+		"disk_lvm":               &disks.LVM,
+		"disk_io_wait":           &disks.IOWait,
+		"disk_queue_depth":       &disks.QueueDepth,
+		"disk_filesystem_errors": &disks.FilesystemErrors,
+		"disk_inode_usage":       &disks.InodeUsage,
+		"disk_mount_points":      &disks.MountPoints,
+		"disk_write_probe":       &disks.WriteProbe,
+		"disk_runtime_storage":   &disks.RuntimeStorage,
+		"disk_wear_level":        &disks.WearLevel,
+		"network_interfaces":     &network.Interfaces,
+		"network_routing":        &network.Routing,
+		"network_connectivity":   &network.Connectivity,
+		"network_statistics":     &network.Statistics,
+		"network_errors":         &network.Errors,
+		"network_latency":        &network.Latency,
+		"network_dns_resolution": &network.DNSResolution,
+		"network_bonding_status": &network.BondingStatus,
+		"network_firewall_rules": &network.FirewallRules,
+		"network_egress":         &network.Egress,
+		"network_ingress":        &network.Ingress,
+		"network_resolv_conf":    &network.ResolvConf,
+		"node_status":            &kc.NodeStatus,
+		"pods":                   &kc.Pods,
+		"cluster_operators":      &kc.ClusterOperators,
+		"node_resources":         &kc.NodeResources,
+		"node_resource_usage":    &kc.NodeResourceUsage,
+		"container_runtime":      &kc.ContainerRuntime,
+		"kubelet_health":         &kc.KubeletHealth,
+		"cni_plugin":             &kc.CNIPlugin,
+		"node_conditions":        &kc.NodeConditions,
+		"pod_scheduling":         &kc.PodScheduling,
+		"pvc_provisioning":       &kc.PVCProvisioning,
+		"pod_network":            &kc.PodNetwork,
+		"lb_health_check":        &kc.LBHealthCheck,
+	}
+}
+
+// validateThresholds reports the message key of an invalid threshold update, or "" if it is valid
+func validateThresholds(check string, thresholds *v1alpha1.CheckThresholds) string {
+	if !checks.ThresholdChecks[check] {
+		return msgThresholdsNotSupported
+	}
+	if thresholds.Warning < 0 || thresholds.Warning > maxThresholdPercent || thresholds.Critical < 0 || thresholds.Critical > maxThresholdPercent {
+		return msgInvalidThresholds
+	}
+	if thresholds.Warning > 0 && thresholds.Critical > 0 && thresholds.Warning >= thresholds.Critical {
+		return msgInvalidThresholds
+	}
+	return ""
+}
+
+// requestUser authenticates the bearer token of the request with a TokenReview. It returns nil if
+// the request carries no valid token.
+func (api *DashboardAPI) requestUser(ctx context.Context, c *gin.Context) (*authenticationv1.UserInfo, error) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || strings.TrimSpace(token) == "" {
+		return nil, nil
+	}
+
+	review, err := api.clientset.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: strings.TrimSpace(token)},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if !review.Status.Authenticated {
+		return nil, nil
+	}
+	return &review.Status.User, nil
+}
+
+// impersonatingClient returns a client acting as the user of the bearer token of the request, so
+// the changes are authorized by the RBAC of the user and audited under their name rather than the
+// operator's. It returns nil if the request carries no valid token.
+func (api *DashboardAPI) impersonatingClient(ctx context.Context, c *gin.Context) (client.Client, error) {
+	user, err := api.requestUser(ctx, c)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	return api.clientAs(user)
+}
+
+// clientAs returns a client impersonating an authenticated user
+func (api *DashboardAPI) clientAs(user *authenticationv1.UserInfo) (client.Client, error) {
+	config := rest.CopyConfig(api.restConfig)
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: user.Username,
+		UID:      user.UID,
+		Groups:   user.Groups,
+		Extra:    make(map[string][]string, len(user.Extra)),
+	}
+	for key, values := range user.Extra {
+		config.Impersonate.Extra[key] = values
+	}
+	return client.New(config, client.Options{Scheme: api.k8sClient.Scheme()})
+}
+
+// UpdateCheckConfig enables or disables a check of a NodeCheck and updates its thresholds. The
+// NodeCheck is updated as the requesting user, so tuning a check from the dashboard requires the
+// same permissions as editing the NodeCheck with kubectl.
+func (api *DashboardAPI) UpdateCheckConfig(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkUpdateTimeout)
+	defer cancel()
+
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+	check := c.Param("check")
+	params := map[string]string{"namespace": namespace, "name": name, "check": check}
+
+	var update CheckConfigUpdate
+	if err := c.ShouldBindJSON(&update); err != nil || (update.Enabled == nil && update.Thresholds == nil) {
+		respondError(c, http.StatusBadRequest, msgInvalidCheckUpdate, params)
+		return
+	}
+	if update.Thresholds != nil {
+		if key := validateThresholds(check, update.Thresholds); key != "" {
+			respondError(c, http.StatusBadRequest, key, params)
+			return
+		}
+	}
+
+	if api.restConfig == nil {
+		respondError(c, http.StatusServiceUnavailable, msgUserAuthenticationFailed, params)
+		return
+	}
+	userClient, err := api.impersonatingClient(ctx, c)
+	if err != nil {
+		fmt.Printf("Unable to authenticate the user updating check %s of NodeCheck %s/%s: %v\n", check, namespace, name, err)
+		respondError(c, http.StatusServiceUnavailable, msgUserAuthenticationFailed, params)
+		return
+	}
+	if userClient == nil {
+		respondError(c, http.StatusUnauthorized, msgUserNotAuthenticated, params)
+		return
+	}
+
+	var nodeCheck v1alpha1.NodeCheck
+	if err := userClient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, &nodeCheck); err != nil {
+		api.respondUpdateError(c, err, params)
+		return
+	}
+
+	// Generated NodeChecks are overwritten by their owner, the change must be made there
+	if template := nodeCheck.Labels[templateLabel]; template != "" {
+		params["owner"] = template
+		respondError(c, http.StatusConflict, msgNodeCheckGeneratedByTemplate, params)
+		return
+	}
+	if parent := api.parentNodeCheck(ctx, &nodeCheck); parent != "" {
+		params["owner"] = parent
+		respondError(c, http.StatusConflict, msgNodeCheckManagedByParent, params)
+		return
+	}
+
+	toggle, ok := checkToggles(&nodeCheck.Spec)[check]
+	if !ok {
+		respondError(c, http.StatusNotFound, msgUnknownCheck, params)
+		return
+	}
+	if update.Enabled != nil {
+		*toggle = *update.Enabled
+	}
+	if update.Thresholds != nil {
+		if nodeCheck.Spec.Thresholds == nil {
+			nodeCheck.Spec.Thresholds = make(map[string]v1alpha1.CheckThresholds)
+		}
+		if update.Thresholds.Warning == 0 && update.Thresholds.Critical == 0 {
+			delete(nodeCheck.Spec.Thresholds, check)
+		} else {
+			nodeCheck.Spec.Thresholds[check] = *update.Thresholds
+		}
+	}
+
+	if err := userClient.Update(ctx, &nodeCheck); err != nil {
+		api.respondUpdateError(c, err, params)
+		return
+	}
+
+	fmt.Printf("Check %s of NodeCheck %s/%s updated by the dashboard user\n", check, namespace, name)
+	thresholds, hasThresholds := nodeCheck.Spec.Thresholds[check]
+	response := gin.H{
+		"name":      nodeCheck.Name,
+		"namespace": nodeCheck.Namespace,
+		"check":     check,
+		"enabled":   *toggle,
+	}
+	if hasThresholds {
+		response["thresholds"] = thresholds
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// parentNodeCheck returns the name of the "*" (or "all") NodeCheck a child NodeCheck was created
+// from, or ""
+func (api *DashboardAPI) parentNodeCheck(ctx context.Context, nodeCheck *v1alpha1.NodeCheck) string {
+	nodeName := nodeCheck.Spec.NodeName
+	if nodeName == "" || nodeName == "*" || nodeName == "all" || !strings.HasSuffix(nodeCheck.Name, "-"+nodeName) {
+		return ""
+	}
+	parentName := strings.TrimSuffix(nodeCheck.Name, "-"+nodeName)
+	var parent v1alpha1.NodeCheck
+	if err := api.k8sClient.Get(ctx, client.ObjectKey{Name: parentName, Namespace: nodeCheck.Namespace}, &parent); err != nil || (parent.Spec.NodeName != "*" && parent.Spec.NodeName != "all") {
+		return ""
+	}
+	return parentName
+}
+
+// respondUpdateError maps the errors of the API server to the status returned to the user
+func (api *DashboardAPI) respondUpdateError(c *gin.Context, err error, params map[string]string) {
+	switch {
+	case errors.IsNotFound(err):
+		respondError(c, http.StatusNotFound, msgNodeCheckNotFound, params)
+	case errors.IsForbidden(err):
+		respondError(c, http.StatusForbidden, msgNodeCheckUpdateForbidden, params)
+	case errors.IsConflict(err):
+		respondError(c, http.StatusConflict, msgNodeCheckUpdateConflict, params)
+	default:
+		params["error"] = err.Error()
+		respondError(c, http.StatusInternalServerError, msgNodeCheckUpdateFailed, params)
+	}
+}
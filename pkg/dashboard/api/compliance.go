@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/nodehealth"
+	"github.com/gin-gonic/gin"
+)
+
+// complianceChecks are the checks comparing the configuration of a node with its declared or
+// recorded state (spec.expectations, spec.baseline), keyed by the path of their result in
+// status.checkResults. They make the compliance score, kept apart from the runtime health: a node
+// can be healthy and drifted, or compliant and failing.
+var complianceChecks = map[string]string{
+	"systemResults.sysctlDrift":          "sysctl_drift",
+	"systemResults.kernelCmdline":        "kernel_cmdline",
+	"systemResults.kernelModules":        "kernel_modules",
+	"systemResults.selinuxStatus":        "selinux_status",
+	"systemResults.transparentHugePages": "transparent_hugepages",
+	"systemResults.baselineDrift":        "baseline_drift",
+}
+
+// NodeCompliance is the configuration compliance of a node
+type NodeCompliance struct {
+	Node string `json:"node"`
+	// Score is the percentage of the evaluated compliance checks that are Healthy
+	Score float64 `json:"score"`
+	// Compliant and Drifted list the compliance checks that are Healthy and Warning/Critical
+	Compliant []string `json:"compliant"`
+	Drifted   []string `json:"drifted"`
+}
+
+// ComplianceReport is the configuration compliance of the fleet returned by /api/v2/compliance
+type ComplianceReport struct {
+	// Score is the percentage of the compliance checks of all the nodes that are Healthy, nil when
+	// no node ran one
+	Score          *float64         `json:"score"`
+	CompliantNodes int              `json:"compliantNodes"`
+	DriftedNodes   int              `json:"driftedNodes"`
+	Checks         []string         `json:"checks"`
+	Nodes          []NodeCompliance `json:"nodes"`
+	LastUpdate     time.Time        `json:"lastUpdate"`
+}
+
+// buildCompliance scores the compliance checks of the NodeChecks. When several NodeChecks run a check
+// on the same node the worst result wins, as in the heatmap. Results that are Unknown, NotSupported or
+// suppressed by a maintenance window tell nothing about the configuration and are left out; nodes
+// without any evaluated compliance check are not listed.
+func buildCompliance(nodeChecks []v1alpha1.NodeCheck) ComplianceReport {
+	codes := map[string]map[string]int{}
+	for _, nc := range nodeChecks {
+		nodeName := nc.Status.NodeName
+		if nodeName == "" {
+			nodeName = nc.Spec.NodeName
+		}
+		if nodeName == "" || nodeName == "*" || nodeName == "all" {
+			continue
+		}
+		for path, status := range nodehealth.CollectCheckStatuses(nc.Status.CheckResults) {
+			check, ok := complianceChecks[path]
+			if !ok {
+				continue
+			}
+			code := heatmapCode(status)
+			if code != heatmapHealthy && code != heatmapWarning && code != heatmapCritical {
+				continue
+			}
+			if codes[nodeName] == nil {
+				codes[nodeName] = map[string]int{}
+			}
+			if code > codes[nodeName][check] {
+				codes[nodeName][check] = code
+			}
+		}
+	}
+
+	report := ComplianceReport{
+		Checks:     make([]string, 0, len(complianceChecks)),
+		Nodes:      make([]NodeCompliance, 0, len(codes)),
+		LastUpdate: time.Now(),
+	}
+	for _, check := range complianceChecks {
+		report.Checks = append(report.Checks, check)
+	}
+	sort.Strings(report.Checks)
+
+	compliant, evaluated := 0, 0
+	for nodeName, checks := range codes {
+		node := NodeCompliance{Node: nodeName, Compliant: []string{}, Drifted: []string{}}
+		for check, code := range checks {
+			if code == heatmapHealthy {
+				node.Compliant = append(node.Compliant, check)
+			} else {
+				node.Drifted = append(node.Drifted, check)
+			}
+		}
+		sort.Strings(node.Compliant)
+		sort.Strings(node.Drifted)
+		node.Score = 100 * float64(len(node.Compliant)) / float64(len(checks))
+		if len(node.Drifted) == 0 {
+			report.CompliantNodes++
+		} else {
+			report.DriftedNodes++
+		}
+		compliant += len(node.Compliant)
+		evaluated += len(checks)
+		report.Nodes = append(report.Nodes, node)
+	}
+	// Least compliant nodes first
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		if report.Nodes[i].Score != report.Nodes[j].Score {
+			return report.Nodes[i].Score < report.Nodes[j].Score
+		}
+		return report.Nodes[i].Node < report.Nodes[j].Node
+	})
+	if evaluated > 0 {
+		score := 100 * float64(compliant) / float64(evaluated)
+		report.Score = &score
+	}
+	return report
+}
+
+// GetCompliance returns the configuration compliance score of every node and of the fleet. The
+// optional namespace parameter restricts it to the NodeChecks of a namespace.
+func (api *DashboardAPI) GetCompliance(c *gin.Context) {
+	ctx := context.Background()
+
+	var nodeChecks v1alpha1.NodeCheckList
+	if err := api.k8sClient.List(ctx, &nodeChecks); err != nil {
+		respondError(c, http.StatusInternalServerError, msgListNodeChecksFailed, map[string]string{"error": err.Error()})
+		return
+	}
+
+	items := nodeChecks.Items
+	if namespace := c.Query("namespace"); namespace != "" {
+		items = make([]v1alpha1.NodeCheck, 0, len(nodeChecks.Items))
+		for _, nc := range nodeChecks.Items {
+			if nc.Namespace == namespace {
+				items = append(items, nc)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, buildCompliance(items))
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gzipWriters reuses the gzip writers between responses, their buffers are large
+var gzipWriters = sync.Pool{
+	New: func() interface{} {
+		writer, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return writer
+	},
+}
+
+// gzipResponseWriter compresses the body of a response. The gzip writer is only started with
+// the first write, so bodyless responses (304 Not Modified, 204) are sent untouched.
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	writer *gzip.Writer
+}
+
+func (w *gzipResponseWriter) start() {
+	if w.writer != nil {
+		return
+	}
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Encoding", "gzip")
+	w.writer = gzipWriters.Get().(*gzip.Writer)
+	w.writer.Reset(w.ResponseWriter)
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if w.Status() == http.StatusNotModified || w.Status() == http.StatusNoContent {
+		return w.ResponseWriter.Write(data)
+	}
+	w.start()
+	return w.writer.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// close flushes the compressed body and returns the gzip writer to the pool
+func (w *gzipResponseWriter) close() {
+	if w.writer == nil {
+		return
+	}
+	w.writer.Close()
+	w.writer.Reset(nil)
+	gzipWriters.Put(w.writer)
+	w.writer = nil
+}
+
+// GzipResponses compresses the responses of clients sending Accept-Encoding: gzip. NodeCheck
+// details are mostly repeated JSON keys, so they shrink to a fraction of their size.
+func GzipResponses() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodOptions || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer writer.close()
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header accepts gzip (and does not refuse it with q=0)
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		encoding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if encoding != "gzip" && encoding != "*" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(field), "q="); ok && strings.Trim(value, "0.") == "" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
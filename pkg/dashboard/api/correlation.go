@@ -0,0 +1,167 @@
+package api
+
+// correlationRule groups the checks that fail together when a node has one underlying problem. Checks
+// lists the paths of their results in status.checkResults from the probable root cause to the symptoms,
+// e.g. a failing disk before the I/O wait and the tasks blocked on it.
+type correlationRule struct {
+	Name   string
+	Title  string
+	Hint   string
+	Checks []string
+}
+
+// correlationRules are tried in order and a failing check joins the incident of the first rule listing
+// it, so the more specific rules come first
+var correlationRules = []correlationRule{
+	{
+		Name:  "storage-stall",
+		Title: "Storage stall",
+		Hint:  "Slow or failing storage blocks tasks in uninterruptible sleep (D state), which raises the I/O wait and the load; look at the disk health and latency first",
+		Checks: []string{
+			"systemResults.disks.smart",
+			"systemResults.disks.raid",
+			"systemResults.hardware.pcieErrors",
+			"systemResults.disks.filesystemErrors",
+			"systemResults.disks.writeProbe",
+			"systemResults.disks.performance",
+			"systemResults.disks.queueDepth",
+			"systemResults.disks.ioWait",
+			"systemResults.uninterruptibleTasks",
+			"systemResults.kernelLockups",
+			"systemResults.pressureStall",
+			"systemResults.uptime",
+		},
+	},
+	{
+		Name:  "memory-pressure",
+		Title: "Memory pressure",
+		Hint:  "The node is short of memory: it swaps and reclaims, then the kernel or the kubelet kill processes; look at the memory usage and the largest pods first",
+		Checks: []string{
+			"systemResults.hardware.memoryErrors",
+			"systemResults.memory",
+			"systemResults.swapActivity",
+			"systemResults.memoryFragmentation",
+			"systemResults.oomKiller",
+			"systemResults.userspaceOOM",
+			"kubernetesResults.nodeResourceUsage",
+			"kubernetesResults.nodeConditions",
+		},
+	},
+	{
+		Name:  "cpu-contention",
+		Title: "CPU contention",
+		Hint:  "The CPUs run slower or are taken away from the node (thermal throttling, steal time, interrupt storms), so the run queue and the load grow; look at the temperature and the hypervisor first",
+		Checks: []string{
+			"systemResults.hardware.temperature",
+			"systemResults.cpuFrequency",
+			"systemResults.cpuStealTime",
+			"systemResults.interruptsBalance",
+			"systemResults.contextSwitches",
+			"systemResults.resources",
+		},
+	},
+	{
+		Name:  "network-degradation",
+		Title: "Network degradation",
+		Hint:  "A link or bond problem shows up as interface errors, then as latency, lost connectivity and failing name resolution up to the pod network; look at the interfaces first",
+		Checks: []string{
+			"systemResults.network.interfaces",
+			"systemResults.network.bondingStatus",
+			"systemResults.network.errors",
+			"systemResults.network.statistics",
+			"systemResults.conntrack",
+			"systemResults.network.latency",
+			"systemResults.network.connectivity",
+			"systemResults.network.egress",
+			"systemResults.network.ingress",
+			"systemResults.network.dnsResolution",
+			"kubernetesResults.cniPlugin",
+			"kubernetesResults.podNetwork",
+			"kubernetesResults.lbHealthCheck",
+		},
+	},
+	{
+		Name:  "runtime-degradation",
+		Title: "Container runtime degradation",
+		Hint:  "A full image filesystem or a failing container runtime makes the kubelet unhealthy, then the node NotReady and its pods fail; look at the runtime storage first",
+		Checks: []string{
+			"systemResults.disks.runtimeStorage",
+			"systemResults.disks.space",
+			"systemResults.disks.inodeUsage",
+			"kubernetesResults.containerRuntime",
+			"kubernetesResults.kubeletHealth",
+			"kubernetesResults.nodeStatus",
+			"kubernetesResults.podScheduling",
+			"kubernetesResults.pods",
+		},
+	},
+}
+
+// minIncidentChecks is the number of failing checks of a rule that make an incident: a single failing
+// check is reported as it is
+const minIncidentChecks = 2
+
+// Incident groups the failing checks of a NodeCheck that are likely the same problem, so it is looked at
+// once instead of as several alerts
+type Incident struct {
+	Rule  string `json:"rule"`
+	Title string `json:"title"`
+	// Status is the worst status of the checks
+	Status string `json:"status"`
+	// ProbableRootCause is the check closest to the cause in the rule, the first of Checks
+	ProbableRootCause string `json:"probableRootCause"`
+	Hint              string `json:"hint"`
+	// Checks are the failing checks from the probable root cause to the symptoms
+	Checks []IncidentCheck `json:"checks"`
+}
+
+// IncidentCheck is a failing check of an incident
+type IncidentCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// correlateChecks groups the Warning and Critical checks into incidents by the correlation rules.
+// Results suppressed by a maintenance window, Unknown or NotSupported are not failures and are left
+// out. Checks no rule groups stay single findings in the check list only.
+func correlateChecks(checks []CheckResultV2) []Incident {
+	failing := map[string]CheckResultV2{}
+	for _, check := range checks {
+		if check.Status == "Warning" || check.Status == "Critical" {
+			failing[check.Name] = check
+		}
+	}
+
+	incidents := []Incident{}
+	grouped := map[string]bool{}
+	for _, rule := range correlationRules {
+		var members []IncidentCheck
+		for _, name := range rule.Checks {
+			check, ok := failing[name]
+			if !ok || grouped[name] {
+				continue
+			}
+			members = append(members, IncidentCheck{Name: check.Name, Status: check.Status, Message: check.Message})
+		}
+		if len(members) < minIncidentChecks {
+			continue
+		}
+		incident := Incident{
+			Rule:              rule.Name,
+			Title:             rule.Title,
+			Status:            "Warning",
+			ProbableRootCause: members[0].Name,
+			Hint:              rule.Hint,
+			Checks:            members,
+		}
+		for _, member := range members {
+			grouped[member.Name] = true
+			if member.Status == "Critical" {
+				incident.Status = "Critical"
+			}
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents
+}
@@ -0,0 +1,272 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/nodehealth"
+	"github.com/gin-gonic/gin"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// mirrorPodAnnotation marks the API copy of a static pod, which the kubelet runs from its manifests
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// DrainPod is a pod of the drain report, with the reason it is listed
+type DrainPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+// DrainPDBConflict is a PodDisruptionBudget that would block the eviction of the pods of the node
+type DrainPDBConflict struct {
+	Namespace          string   `json:"namespace"`
+	Name               string   `json:"name"`
+	DisruptionsAllowed int32    `json:"disruptionsAllowed"`
+	Pods               []string `json:"pods"`
+}
+
+// DrainDaemonSet is a DaemonSet whose only scheduled pod runs on the node
+type DrainDaemonSet struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+}
+
+// DrainReport is the pre-flight report of /api/v2/nodes/:nodeName/drain-report. Safe is true when
+// there are no blockers: unmanaged pods, pods with local storage and PDB conflicts make kubectl drain
+// fail or lose data. Unique DaemonSets and static pods do not block the drain and are warnings.
+type DrainReport struct {
+	Node          string `json:"node"`
+	Unschedulable bool   `json:"unschedulable"`
+	// Health is the health of the node from its NodeChecks, nil when no NodeCheck targets it
+	Health           *nodehealth.NodeHealthStatus `json:"health"`
+	Safe             bool                         `json:"safe"`
+	Blockers         []string                     `json:"blockers"`
+	Warnings         []string                     `json:"warnings"`
+	EvictablePods    int                          `json:"evictablePods"`
+	UnmanagedPods    []DrainPod                   `json:"unmanagedPods"`
+	LocalStoragePods []DrainPod                   `json:"localStoragePods"`
+	PDBConflicts     []DrainPDBConflict           `json:"pdbConflicts"`
+	DaemonSetPods    []DrainPod                   `json:"daemonSetPods"`
+	UniqueDaemonSets []DrainDaemonSet             `json:"uniqueDaemonSets"`
+	StaticPods       []DrainPod                   `json:"staticPods"`
+	GeneratedAt      time.Time                    `json:"generatedAt"`
+}
+
+// GetDrainReport returns what would get in the way of draining and removing a node, typically one
+// flagged unhealthy by its NodeChecks: the report is read-only, nothing is cordoned or evicted.
+func (api *DashboardAPI) GetDrainReport(c *gin.Context) {
+	ctx := c.Request.Context()
+	nodeName := c.Param("nodeName")
+	params := map[string]string{"node": nodeName}
+
+	node, err := api.clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			respondError(c, http.StatusNotFound, msgNodeNotFound, params)
+			return
+		}
+		params["error"] = err.Error()
+		respondError(c, http.StatusInternalServerError, msgDrainReportFailed, params)
+		return
+	}
+
+	pods, err := api.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
+	})
+	if err != nil {
+		params["error"] = err.Error()
+		respondError(c, http.StatusInternalServerError, msgListPodsFailed, params)
+		return
+	}
+
+	report := DrainReport{
+		Node:             node.Name,
+		Unschedulable:    node.Spec.Unschedulable,
+		Blockers:         []string{},
+		Warnings:         []string{},
+		UnmanagedPods:    []DrainPod{},
+		LocalStoragePods: []DrainPod{},
+		PDBConflicts:     []DrainPDBConflict{},
+		DaemonSetPods:    []DrainPod{},
+		UniqueDaemonSets: []DrainDaemonSet{},
+		StaticPods:       []DrainPod{},
+		GeneratedAt:      time.Now(),
+	}
+	if err := api.addDrainPods(ctx, &report, pods.Items); err != nil {
+		params["error"] = err.Error()
+		respondError(c, http.StatusInternalServerError, msgDrainReportFailed, params)
+		return
+	}
+
+	var nodeChecks v1alpha1.NodeCheckList
+	if err := api.k8sClient.List(ctx, &nodeChecks); err != nil {
+		respondError(c, http.StatusInternalServerError, msgListNodeChecksFailed, map[string]string{"error": err.Error()})
+		return
+	}
+	for _, health := range nodehealth.BuildNodeHealths(nodeChecks.Items) {
+		if health.Name == nodeName {
+			status := health.Status
+			report.Health = &status
+		}
+	}
+
+	if n := len(report.UnmanagedPods); n > 0 {
+		report.Blockers = append(report.Blockers, fmt.Sprintf("%d pods have no controller and would be deleted for good (drain --force)", n))
+	}
+	if n := len(report.LocalStoragePods); n > 0 {
+		report.Blockers = append(report.Blockers, fmt.Sprintf("%d pods use local storage whose data is lost or stays on the node", n))
+	}
+	if n := len(report.PDBConflicts); n > 0 {
+		report.Blockers = append(report.Blockers, fmt.Sprintf("%d PodDisruptionBudgets do not allow the eviction of the pods of the node", n))
+	}
+	if n := len(report.UniqueDaemonSets); n > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d DaemonSets run only on this node and stop running when it is removed", n))
+	}
+	if n := len(report.StaticPods); n > 0 {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("%d static pods are not evicted and stop with the node", n))
+	}
+	if !report.Unschedulable {
+		report.Warnings = append(report.Warnings, "The node is not cordoned yet")
+	}
+	report.Safe = len(report.Blockers) == 0
+
+	c.JSON(http.StatusOK, report)
+}
+
+// addDrainPods sorts the pods of the node into the sections of the report. Completed pods are left
+// out, kubectl drain deletes them without further checks.
+func (api *DashboardAPI) addDrainPods(ctx context.Context, report *DrainReport, pods []corev1.Pod) error {
+	evictable := map[string][]corev1.Pod{}
+	daemonSets := map[string]DrainDaemonSet{}
+
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+			report.StaticPods = append(report.StaticPods, DrainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: "static pod"})
+			continue
+		}
+		owner := metav1.GetControllerOf(&pod)
+		if owner != nil && owner.Kind == "DaemonSet" {
+			report.DaemonSetPods = append(report.DaemonSetPods, DrainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: "DaemonSet " + owner.Name})
+			daemonSets[pod.Namespace+"/"+owner.Name] = DrainDaemonSet{Namespace: pod.Namespace, Name: owner.Name}
+			continue
+		}
+
+		if owner == nil {
+			report.UnmanagedPods = append(report.UnmanagedPods, DrainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: "no controller"})
+		}
+		reason, err := api.localStorage(ctx, pod)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			report.LocalStoragePods = append(report.LocalStoragePods, DrainPod{Namespace: pod.Namespace, Name: pod.Name, Reason: reason})
+		}
+		evictable[pod.Namespace] = append(evictable[pod.Namespace], pod)
+		report.EvictablePods++
+	}
+
+	for _, daemonSet := range daemonSets {
+		ds, err := api.clientset.AppsV1().DaemonSets(daemonSet.Namespace).Get(ctx, daemonSet.Name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if ds.Status.DesiredNumberScheduled <= 1 {
+			report.UniqueDaemonSets = append(report.UniqueDaemonSets, daemonSet)
+		}
+	}
+	sort.Slice(report.UniqueDaemonSets, func(i, j int) bool {
+		a, b := report.UniqueDaemonSets[i], report.UniqueDaemonSets[j]
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+
+	for namespace, nsPods := range evictable {
+		pdbs, err := api.clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, pdb := range pdbs.Items {
+			if conflict, ok := pdbConflict(pdb, nsPods); ok {
+				report.PDBConflicts = append(report.PDBConflicts, conflict)
+			}
+		}
+	}
+	sort.Slice(report.PDBConflicts, func(i, j int) bool {
+		a, b := report.PDBConflicts[i], report.PDBConflicts[j]
+		return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+	})
+	return nil
+}
+
+// pdbConflict reports a PodDisruptionBudget that selects more pods of the node than the disruptions it
+// currently allows: the drain would wait on it until the pods elsewhere become ready
+func pdbConflict(pdb policyv1.PodDisruptionBudget, pods []corev1.Pod) (DrainPDBConflict, bool) {
+	conflict := DrainPDBConflict{
+		Namespace:          pdb.Namespace,
+		Name:               pdb.Name,
+		DisruptionsAllowed: pdb.Status.DisruptionsAllowed,
+		Pods:               []string{},
+	}
+	// A nil selector selects no pod, an empty one every pod of the namespace
+	selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+	if err != nil {
+		return conflict, false
+	}
+	for _, pod := range pods {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			conflict.Pods = append(conflict.Pods, pod.Name)
+		}
+	}
+	sort.Strings(conflict.Pods)
+	return conflict, len(conflict.Pods) > 0 && int(pdb.Status.DisruptionsAllowed) < len(conflict.Pods)
+}
+
+// localStorage returns why the data of a pod is tied to the node, or "" when it is not: emptyDir
+// volumes are deleted with the pod, hostPath volumes and local PersistentVolumes stay on the node
+func (api *DashboardAPI) localStorage(ctx context.Context, pod corev1.Pod) (string, error) {
+	for _, volume := range pod.Spec.Volumes {
+		switch {
+		case volume.EmptyDir != nil:
+			return fmt.Sprintf("emptyDir volume %s", volume.Name), nil
+		case volume.HostPath != nil:
+			return fmt.Sprintf("hostPath volume %s (%s)", volume.Name, volume.HostPath.Path), nil
+		case volume.PersistentVolumeClaim != nil:
+			claim, err := api.clientset.CoreV1().PersistentVolumeClaims(pod.Namespace).Get(ctx, volume.PersistentVolumeClaim.ClaimName, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return "", err
+			}
+			if claim.Spec.VolumeName == "" {
+				continue
+			}
+			pv, err := api.clientset.CoreV1().PersistentVolumes().Get(ctx, claim.Spec.VolumeName, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return "", err
+			}
+			if pv.Spec.Local != nil || pv.Spec.HostPath != nil {
+				return fmt.Sprintf("local PersistentVolume %s (claim %s)", pv.Name, claim.Name), nil
+			}
+		}
+	}
+	return "", nil
+}
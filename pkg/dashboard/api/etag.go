@@ -0,0 +1,60 @@
+package api
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+)
+
+// etagEpoch changes the ETags on every start of the dashboard, so clients do not keep a response
+// of a previous version of the operator (and /stats refreshes the dashboard metrics after a restart)
+var etagEpoch = fmt.Sprint(time.Now().UnixNano())
+
+// nodeCheckListETag returns a weak ETag of a list of NodeChecks. It is derived from their
+// resourceVersions, so it changes whenever a NodeCheck is created, updated or deleted, and can be
+// computed before building the response. variant distinguishes responses built from the same
+// NodeChecks (e.g. the endpoint).
+func nodeCheckListETag(variant string, nodeChecks []v1alpha1.NodeCheck) string {
+	versions := make([]string, len(nodeChecks))
+	for i, nc := range nodeChecks {
+		versions[i] = nc.Namespace + "/" + nc.Name + "@" + nc.ResourceVersion
+	}
+	sort.Strings(versions)
+
+	hash := sha256.New()
+	hash.Write([]byte(etagEpoch))
+	hash.Write([]byte(variant))
+	for _, version := range versions {
+		hash.Write([]byte{0})
+		hash.Write([]byte(version))
+	}
+	return fmt.Sprintf(`W/"%x"`, hash.Sum(nil)[:16])
+}
+
+// notModified sets the ETag of the response and answers 304 Not Modified when it matches the
+// If-None-Match header of the request, so polling clients only download responses that changed
+func notModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	// Clients and proxies must revalidate every time, the NodeChecks change with each run
+	c.Header("Cache-Control", "no-cache")
+
+	match := c.GetHeader("If-None-Match")
+	if match == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(match, ",") {
+		candidate = strings.TrimSpace(candidate)
+		// If-None-Match uses the weak comparison: W/"x" matches "x"
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,208 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/faults"
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// faultInjectionEnabled follows the FaultInjection feature gate of the operator settings
+var faultInjectionEnabled atomic.Bool
+
+// SetFaultInjectionEnabled enables or disables the fault injection endpoints
+func SetFaultInjectionEnabled(enabled bool) {
+	faultInjectionEnabled.Store(enabled)
+}
+
+// FaultRequest is the body of POST /api/v2/nodechecks/:namespace/:name/faults
+type FaultRequest struct {
+	Check   string `json:"check"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// DurationMinutes defaults to faults.DefaultDuration and is capped at faults.MaxDuration
+	DurationMinutes int `json:"durationMinutes,omitempty"`
+}
+
+// requireFaultInjection answers 404 when the feature gate is disabled, so the endpoints do not exist
+// outside of the clusters used to test the alerting
+func requireFaultInjection(c *gin.Context) {
+	if !faultInjectionEnabled.Load() {
+		respondError(c, http.StatusNotFound, msgFaultInjectionDisabled, nil)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// ListFaults returns the active faults of a NodeCheck
+func (api *DashboardAPI) ListFaults(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkUpdateTimeout)
+	defer cancel()
+
+	params := map[string]string{"namespace": c.Param("namespace"), "name": c.Param("name")}
+	var nodeCheck v1alpha1.NodeCheck
+	if err := api.k8sClient.Get(ctx, client.ObjectKey{Name: params["name"], Namespace: params["namespace"]}, &nodeCheck); err != nil {
+		api.respondUpdateError(c, err, params)
+		return
+	}
+	parsed, err := faults.Parse(nodeCheck.Annotations)
+	if err != nil {
+		params["error"] = err.Error()
+		respondError(c, http.StatusInternalServerError, msgInvalidFaults, params)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"name":      nodeCheck.Name,
+		"namespace": nodeCheck.Namespace,
+		"faults":    faults.Active(parsed, time.Now()),
+	})
+}
+
+// InjectFault makes a check of a NodeCheck report a synthetic Warning or Critical result until the
+// fault expires. It replaces the fault already injected on the same check. The NodeCheck is updated
+// as the requesting user, like UpdateCheckConfig.
+func (api *DashboardAPI) InjectFault(c *gin.Context) {
+	params := map[string]string{
+		"namespace":  c.Param("namespace"),
+		"name":       c.Param("name"),
+		"maxMinutes": strconv.Itoa(int(faults.MaxDuration.Minutes())),
+	}
+
+	var request FaultRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.Check == "" || !faults.ValidStatus(request.Status) ||
+		request.DurationMinutes < 0 || time.Duration(request.DurationMinutes)*time.Minute > faults.MaxDuration {
+		respondError(c, http.StatusBadRequest, msgInvalidFault, params)
+		return
+	}
+	if _, ok := checkToggles(&v1alpha1.NodeCheckSpec{})[request.Check]; !ok {
+		params["check"] = request.Check
+		respondError(c, http.StatusNotFound, msgUnknownCheck, params)
+		return
+	}
+	duration := faults.DefaultDuration
+	if request.DurationMinutes > 0 {
+		duration = time.Duration(request.DurationMinutes) * time.Minute
+	}
+
+	api.updateFaults(c, params, func(user string, active []faults.Fault) []faults.Fault {
+		updated := []faults.Fault{}
+		for _, fault := range active {
+			if fault.Check != request.Check {
+				updated = append(updated, fault)
+			}
+		}
+		return append(updated, faults.Fault{
+			Check:      request.Check,
+			Status:     request.Status,
+			Message:    request.Message,
+			InjectedBy: user,
+			Expires:    metav1.NewTime(time.Now().Add(duration).Truncate(time.Second)),
+		})
+	})
+}
+
+// ClearFaults removes the fault of the check in the "check" query parameter, or all the faults of
+// the NodeCheck without it
+func (api *DashboardAPI) ClearFaults(c *gin.Context) {
+	params := map[string]string{"namespace": c.Param("namespace"), "name": c.Param("name")}
+	check := c.Query("check")
+	api.updateFaults(c, params, func(_ string, active []faults.Fault) []faults.Fault {
+		if check == "" {
+			return nil
+		}
+		updated := []faults.Fault{}
+		for _, fault := range active {
+			if fault.Check != check {
+				updated = append(updated, fault)
+			}
+		}
+		return updated
+	})
+}
+
+// updateFaults rewrites the faults annotation of a NodeCheck as the requesting user. change receives
+// the user name and the active faults, the expired ones are dropped. It responds with the new faults.
+func (api *DashboardAPI) updateFaults(c *gin.Context, params map[string]string, change func(user string, active []faults.Fault) []faults.Fault) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkUpdateTimeout)
+	defer cancel()
+
+	if api.restConfig == nil {
+		respondError(c, http.StatusServiceUnavailable, msgUserAuthenticationFailed, params)
+		return
+	}
+	// The user name is recorded in the faults, so the user is authenticated here rather than by
+	// impersonatingClient
+	user, err := api.requestUser(ctx, c)
+	if err != nil {
+		fmt.Printf("Unable to authenticate the user changing the faults of NodeCheck %s/%s: %v\n", params["namespace"], params["name"], err)
+		respondError(c, http.StatusServiceUnavailable, msgUserAuthenticationFailed, params)
+		return
+	}
+	if user == nil {
+		respondError(c, http.StatusUnauthorized, msgUserNotAuthenticated, params)
+		return
+	}
+	userClient, err := api.clientAs(user)
+	if err != nil {
+		fmt.Printf("Unable to impersonate the user changing the faults of NodeCheck %s/%s: %v\n", params["namespace"], params["name"], err)
+		respondError(c, http.StatusServiceUnavailable, msgUserAuthenticationFailed, params)
+		return
+	}
+
+	var nodeCheck v1alpha1.NodeCheck
+	if err := userClient.Get(ctx, client.ObjectKey{Name: params["name"], Namespace: params["namespace"]}, &nodeCheck); err != nil {
+		api.respondUpdateError(c, err, params)
+		return
+	}
+	// The executors only apply the faults of the NodeCheck of their node: the "*", "all" and selector
+	// NodeChecks are copied into a child per node
+	if nodeCheck.Spec.NodeName == "" || nodeCheck.Spec.NodeName == "*" || nodeCheck.Spec.NodeName == "all" {
+		respondError(c, http.StatusConflict, msgFaultNeedsNode, params)
+		return
+	}
+
+	// An annotation edited by hand may be invalid, it is replaced rather than blocking the cleanup
+	parsed, err := faults.Parse(nodeCheck.Annotations)
+	if err != nil {
+		fmt.Printf("Replacing the invalid faults of NodeCheck %s/%s: %v\n", params["namespace"], params["name"], err)
+	}
+	updated := change(user.Username, faults.Active(parsed, time.Now()))
+	value, err := faults.Encode(updated)
+	if err != nil {
+		params["error"] = err.Error()
+		respondError(c, http.StatusInternalServerError, msgNodeCheckUpdateFailed, params)
+		return
+	}
+	if value == "" {
+		delete(nodeCheck.Annotations, faults.Annotation)
+	} else {
+		if nodeCheck.Annotations == nil {
+			nodeCheck.Annotations = make(map[string]string)
+		}
+		nodeCheck.Annotations[faults.Annotation] = value
+	}
+
+	if err := userClient.Update(ctx, &nodeCheck); err != nil {
+		api.respondUpdateError(c, err, params)
+		return
+	}
+
+	fmt.Printf("Faults of NodeCheck %s/%s changed by %s: %d active\n", nodeCheck.Namespace, nodeCheck.Name, user.Username, len(updated))
+	if updated == nil {
+		updated = []faults.Fault{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"name":      nodeCheck.Name,
+		"namespace": nodeCheck.Namespace,
+		"faults":    updated,
+	})
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/history"
+	"github.com/gin-gonic/gin"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultFeedbackHours is the window of the false-positive report when the request sets no ?hours=
+const defaultFeedbackHours = 7 * 24
+
+// maxFeedbackComment bounds the comment of a feedback
+const maxFeedbackComment = 1024
+
+// FalsePositiveRequest is the body of POST /api/v2/nodechecks/:namespace/:name/false-positives
+type FalsePositiveRequest struct {
+	// Check is the check name used in the results (e.g. "disk_space") or the path of its result in the
+	// status (e.g. "systemResults.disks.space")
+	Check   string `json:"check"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// FalsePositiveReport is the feedback given within a window, aggregated by check
+type FalsePositiveReport struct {
+	Since    time.Time                 `json:"since"`
+	Checks   []history.FeedbackSummary `json:"checks"`
+	Feedback []history.Feedback        `json:"feedback"`
+}
+
+// MarkFalsePositive records the current result of a check of a NodeCheck as a false positive, with
+// the values it reported, in the feedback store of the history. Any user allowed to read the NodeCheck
+// can give feedback; the NodeCheck itself is not changed.
+func (api *DashboardAPI) MarkFalsePositive(c *gin.Context) {
+	params := map[string]string{"namespace": c.Param("namespace"), "name": c.Param("name")}
+	var request FalsePositiveRequest
+	if err := c.ShouldBindJSON(&request); err != nil || request.Check == "" || len(request.Comment) > maxFeedbackComment {
+		params["maxComment"] = strconv.Itoa(maxFeedbackComment)
+		respondError(c, http.StatusBadRequest, msgInvalidFalsePositive, params)
+		return
+	}
+	params["check"] = request.Check
+	check, ok := canonicalCheckName(request.Check)
+	if !ok {
+		respondError(c, http.StatusNotFound, msgUnknownCheck, params)
+		return
+	}
+	params["check"] = check
+
+	var nodeCheck v1alpha1.NodeCheck
+	if err := api.k8sClient.Get(c.Request.Context(), client.ObjectKey{Name: params["name"], Namespace: params["namespace"]}, &nodeCheck); err != nil {
+		respondError(c, http.StatusNotFound, msgNodeCheckNotFound, params)
+		return
+	}
+	result, ok := checkResultByName(nodeCheck.Status.CheckResults, check)
+	if !ok {
+		respondError(c, http.StatusNotFound, msgFalsePositiveNoResult, params)
+		return
+	}
+	if result.Status != "Warning" && result.Status != "Critical" {
+		params["status"] = result.Status
+		respondError(c, http.StatusConflict, msgFalsePositiveNotFinding, params)
+		return
+	}
+
+	value, _ := c.Get(userContextKey)
+	user, _ := value.(*authenticationv1.UserInfo)
+	feedback := history.Feedback{
+		Namespace: nodeCheck.Namespace,
+		NodeCheck: nodeCheck.Name,
+		Node:      nodeCheck.Status.NodeName,
+		Check:     check,
+		Status:    result.Status,
+		Message:   result.Message,
+		Comment:   strings.TrimSpace(request.Comment),
+		Timestamp: time.Now().UTC(),
+	}
+	if user != nil {
+		feedback.ReportedBy = user.Username
+	}
+	if timestamp, err := time.Parse(time.RFC3339, result.Timestamp); err == nil {
+		feedback.ResultTimestamp = timestamp
+	}
+	if result.Details != nil {
+		feedback.Values, _ = json.Marshal(result.Details)
+	}
+
+	if err := history.CurrentFeedback().RecordFeedback(c.Request.Context(), feedback); err != nil {
+		params["error"] = err.Error()
+		respondError(c, http.StatusInternalServerError, msgFeedbackFailed, params)
+		return
+	}
+	fmt.Printf("Check %s of NodeCheck %s/%s marked as false positive by %s (%s)\n",
+		check, nodeCheck.Namespace, nodeCheck.Name, feedback.ReportedBy, result.Status)
+	c.JSON(http.StatusCreated, feedback)
+}
+
+// GetFalsePositiveReport returns the false-positive feedback of the last ?hours= (a week by default),
+// aggregated by check with the checks marked most often first, for the tuning of the thresholds.
+// ?namespace= restricts it to the NodeChecks of a namespace.
+func (api *DashboardAPI) GetFalsePositiveReport(c *gin.Context) {
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", strconv.Itoa(defaultFeedbackHours)))
+	if err != nil || hours <= 0 {
+		hours = defaultFeedbackHours
+	}
+	since := time.Now().Add(-time.Duration(hours) * time.Hour)
+	feedback, err := history.CurrentFeedback().QueryFeedback(c.Request.Context(), since)
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, msgFeedbackFailed, map[string]string{"error": err.Error()})
+		return
+	}
+	if namespace := c.Query("namespace"); namespace != "" {
+		filtered := feedback[:0:0]
+		for _, entry := range feedback {
+			if entry.Namespace == namespace {
+				filtered = append(filtered, entry)
+			}
+		}
+		feedback = filtered
+	}
+	if feedback == nil {
+		feedback = []history.Feedback{}
+	}
+	c.JSON(http.StatusOK, FalsePositiveReport{
+		Since:    since,
+		Checks:   history.SummarizeFeedback(feedback),
+		Feedback: feedback,
+	})
+}
+
+// normalizedCheckName returns a check name ("disk_space"), or the path of its result in the status tree
+// ("systemResults.disks.space"), without the result group, the underscores and the case: both are diskspace
+func normalizedCheckName(name string) string {
+	if group, path, ok := strings.Cut(name, "."); ok && strings.HasSuffix(group, "Results") {
+		name = path
+		for group, prefix := range map[string]string{"disks.": "disk", "hardware.": "hardware", "network.": "network"} {
+			if rest, ok := strings.CutPrefix(name, group); ok {
+				name = prefix + rest
+			}
+		}
+	}
+	return strings.ToLower(strings.NewReplacer(".", "", "_", "").Replace(name))
+}
+
+// canonicalCheckName returns the check name used in the results for a check name or result path
+func canonicalCheckName(name string) (string, bool) {
+	wanted := normalizedCheckName(name)
+	for check := range checkToggles(&v1alpha1.NodeCheckSpec{}) {
+		if normalizedCheckName(check) == wanted {
+			return check, true
+		}
+	}
+	return "", false
+}
+
+// checkResultByName returns the result of a check of a NodeCheck
+func checkResultByName(results v1alpha1.CheckResults, check string) (CheckResultV2, bool) {
+	wanted := normalizedCheckName(check)
+	for _, result := range flattenCheckResults(results) {
+		if normalizedCheckName(result.Name) == wanted {
+			return result, true
+		}
+	}
+	return CheckResultV2{}, false
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldSelection is the field selection of a request: ?fields= keeps only the listed top-level
+// fields of the response (of each item for lists), ?exclude= removes the listed fields at any
+// depth (e.g. exclude=details drops the details of every check result)
+type fieldSelection struct {
+	fields  map[string]bool
+	exclude map[string]bool
+}
+
+// parseFieldSelection reads the field selection of a request
+func parseFieldSelection(c *gin.Context) fieldSelection {
+	return fieldSelection{
+		fields:  fieldSet(c.Query("fields")),
+		exclude: fieldSet(c.Query("exclude")),
+	}
+}
+
+// fieldSet parses a comma-separated list of field names
+func fieldSet(list string) map[string]bool {
+	set := map[string]bool{}
+	for _, field := range strings.Split(list, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			set[field] = true
+		}
+	}
+	return set
+}
+
+// empty reports whether the request selects the whole response
+func (s fieldSelection) empty() bool {
+	return len(s.fields) == 0 && len(s.exclude) == 0
+}
+
+// variant describes the selection for ETags, since the same NodeChecks give different responses
+func (s fieldSelection) variant() string {
+	if s.empty() {
+		return ""
+	}
+	return "?fields=" + sortedKeys(s.fields) + "&exclude=" + sortedKeys(s.exclude)
+}
+
+// sortedKeys joins the names of a field set in a stable order
+func sortedKeys(set map[string]bool) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// apply returns the selected part of a decoded JSON response
+func (s fieldSelection) apply(value interface{}) interface{} {
+	if items, ok := value.([]interface{}); ok {
+		for i, item := range items {
+			items[i] = s.apply(item)
+		}
+		return items
+	}
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return s.excludeFields(value)
+	}
+	if len(s.fields) > 0 {
+		for key := range object {
+			if !s.fields[key] {
+				delete(object, key)
+			}
+		}
+	}
+	return s.excludeFields(object)
+}
+
+// excludeFields removes the excluded fields from every object of a decoded JSON value
+func (s fieldSelection) excludeFields(value interface{}) interface{} {
+	if len(s.exclude) == 0 {
+		return value
+	}
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			if s.exclude[key] {
+				delete(typed, key)
+				continue
+			}
+			typed[key] = s.excludeFields(child)
+		}
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = s.excludeFields(child)
+		}
+	}
+	return value
+}
+
+// respondSelected writes a JSON response restricted to the field selection of the request,
+// so list views can skip the check details they do not render
+func respondSelected(c *gin.Context, code int, selection fieldSelection, obj interface{}) {
+	if selection.empty() {
+		c.JSON(code, obj)
+		return
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		c.JSON(code, obj)
+		return
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		c.JSON(code, obj)
+		return
+	}
+	c.JSON(code, selection.apply(decoded))
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=machine.openshift.io,resources=machines,verbs=list
+
+const (
+	// groupByRole groups the nodes by their node-role.kubernetes.io/<role> labels
+	groupByRole = "role"
+	// groupByMachineSet groups the nodes by the MachineSet of their OpenShift Machine
+	groupByMachineSet = "machineset"
+
+	// ungroupedName is the group of the nodes without a value for the grouping key
+	ungroupedName = "<none>"
+
+	nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+	machineAnnotation   = "machine.openshift.io/machine"
+	machineSetLabel     = "machine.openshift.io/cluster-api-machineset"
+	machineAPINamespace = "openshift-machine-api"
+)
+
+// groupByAliases are the short names accepted by groupBy for well-known node labels
+var groupByAliases = map[string]string{
+	"zone":   "topology.kubernetes.io/zone",
+	"region": "topology.kubernetes.io/region",
+	"pool":   "machineconfiguration.openshift.io/pool",
+}
+
+// NodeGroupSummary is the health roll-up of a group of nodes in /api/v1/stats?groupBy=
+type NodeGroupSummary struct {
+	Name          string   `json:"name"`
+	Nodes         int      `json:"nodes"`
+	HealthyNodes  int      `json:"healthyNodes"`
+	WarningNodes  int      `json:"warningNodes"`
+	CriticalNodes int      `json:"criticalNodes"`
+	UnknownNodes  int      `json:"unknownNodes"`
+	OverallStatus string   `json:"overallStatus"`
+	DegradedNodes []string `json:"degradedNodes,omitempty"`
+}
+
+// nodeGroupKeys returns the groups of each node for a groupBy value: "role", "machineset",
+// an alias of groupByAliases or any node label key. A node with several roles is in several groups.
+func (api *DashboardAPI) nodeGroupKeys(ctx context.Context, groupBy string) (map[string][]string, error) {
+	nodes, err := api.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	machineSets := map[string]string{}
+	if groupBy == groupByMachineSet {
+		machineSets = api.machineSetsByMachine(ctx)
+	}
+	labelKey := groupBy
+	if alias, ok := groupByAliases[groupBy]; ok {
+		labelKey = alias
+	}
+
+	groups := make(map[string][]string, len(nodes.Items))
+	for _, node := range nodes.Items {
+		keys := []string{}
+		switch groupBy {
+		case groupByRole:
+			for label := range node.Labels {
+				if role, ok := strings.CutPrefix(label, nodeRoleLabelPrefix); ok && role != "" {
+					keys = append(keys, role)
+				}
+			}
+			sort.Strings(keys)
+		case groupByMachineSet:
+			if machineSet := machineSets[node.Annotations[machineAnnotation]]; machineSet != "" {
+				keys = append(keys, machineSet)
+			}
+		default:
+			if value := node.Labels[labelKey]; value != "" {
+				keys = append(keys, value)
+			}
+		}
+		groups[node.Name] = keys
+	}
+	return groups, nil
+}
+
+// machineSetsByMachine maps the "namespace/name" of the OpenShift Machines to their MachineSet.
+// Clusters without the Machine API return an empty map, so all nodes end up ungrouped.
+func (api *DashboardAPI) machineSetsByMachine(ctx context.Context) map[string]string {
+	machines := &unstructured.UnstructuredList{}
+	machines.SetGroupVersionKind(schema.GroupVersionKind{Group: "machine.openshift.io", Version: "v1beta1", Kind: "MachineList"})
+	machineSets := map[string]string{}
+	if err := api.k8sClient.List(ctx, machines, client.InNamespace(machineAPINamespace)); err != nil {
+		return machineSets
+	}
+	for _, machine := range machines.Items {
+		if machineSet := machine.GetLabels()[machineSetLabel]; machineSet != "" {
+			machineSets[machine.GetNamespace()+"/"+machine.GetName()] = machineSet
+		}
+	}
+	return machineSets
+}
+
+// groupNodeChecks rolls up the overall status of the node NodeChecks per group of nodes
+func (api *DashboardAPI) groupNodeChecks(ctx context.Context, groupBy string, nodeChecks []v1alpha1.NodeCheck) ([]NodeGroupSummary, error) {
+	nodeGroups, err := api.nodeGroupKeys(ctx, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := map[string]*NodeGroupSummary{}
+	for _, nc := range nodeChecks {
+		nodeName := nc.Status.NodeName
+		if nodeName == "" {
+			nodeName = nc.Spec.NodeName
+		}
+		keys := nodeGroups[nodeName]
+		if len(keys) == 0 {
+			keys = []string{ungroupedName}
+		}
+		for _, key := range keys {
+			summary := summaries[key]
+			if summary == nil {
+				summary = &NodeGroupSummary{Name: key}
+				summaries[key] = summary
+			}
+			summary.Nodes++
+			switch nc.Status.OverallStatus {
+			case "Healthy":
+				summary.HealthyNodes++
+			case "Warning":
+				summary.WarningNodes++
+				summary.DegradedNodes = append(summary.DegradedNodes, nodeName)
+			case "Critical":
+				summary.CriticalNodes++
+				summary.DegradedNodes = append(summary.DegradedNodes, nodeName)
+			default:
+				summary.UnknownNodes++
+			}
+		}
+	}
+
+	groups := make([]NodeGroupSummary, 0, len(summaries))
+	for _, summary := range summaries {
+		summary.OverallStatus = calculateOverallStatus(summary.HealthyNodes, summary.WarningNodes, summary.CriticalNodes, summary.UnknownNodes)
+		sort.Strings(summary.DegradedNodes)
+		groups = append(groups, *summary)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Name < groups[j].Name })
+	return groups, nil
+}
@@ -2,18 +2,19 @@ package api
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/maintenance"
 	"github.com/albertofilice/node-check-operator/pkg/metrics"
+	"github.com/albertofilice/node-check-operator/pkg/selfstatus"
 	"github.com/gin-gonic/gin"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -22,14 +23,20 @@ type DashboardAPI struct {
 	k8sClient    client.Client
 	clientset     *kubernetes.Clientset
 	namespace    string
+	// restConfig is the operator's configuration, used to act as the dashboard user on updates
+	restConfig   *rest.Config
+	// summaries caches the summaries and node metrics of the NodeChecks by resourceVersion
+	summaries    *summaryCache
 }
 
 // NewDashboardAPI creates a new dashboard API
-func NewDashboardAPI(k8sClient client.Client, clientset *kubernetes.Clientset, namespace string) *DashboardAPI {
+func NewDashboardAPI(k8sClient client.Client, clientset *kubernetes.Clientset, restConfig *rest.Config, namespace string) *DashboardAPI {
 	return &DashboardAPI{
-		k8sClient: k8sClient,
-		clientset: clientset,
-		namespace: namespace,
+		k8sClient:  k8sClient,
+		clientset:  clientset,
+		namespace:  namespace,
+		restConfig: restConfig,
+		summaries:  newSummaryCache(),
 	}
 }
 
@@ -45,6 +52,11 @@ type NodeCheckSummary struct {
 	HealthyCount  int       `json:"healthyCount"`
 	WarningCount  int       `json:"warningCount"`
 	CriticalCount int       `json:"criticalCount"`
+	SuppressedCount int     `json:"suppressedCount"`
+	SuppressedBy  string    `json:"suppressedBy,omitempty"`
+	ResultLabels  map[string]string `json:"resultLabels,omitempty"`
+	ResultAnnotations map[string]string `json:"resultAnnotations,omitempty"`
+	Verification  *v1alpha1.VerificationStatus `json:"verification,omitempty"`
 }
 
 // CheckResultAPI represents a check result for API responses (with details as object instead of RawExtension)
@@ -54,6 +66,7 @@ type CheckResultAPI struct {
 	Timestamp string                 `json:"timestamp"`
 	Command   string                 `json:"command,omitempty"`
 	Details   map[string]interface{} `json:"details,omitempty"`
+	RunID     string                 `json:"runID,omitempty"`
 }
 
 // SystemCheckResultsAPI represents system check results for API responses
@@ -79,6 +92,34 @@ type SystemCheckResultsAPI struct {
 	SELinuxStatus       *CheckResultAPI           `json:"selinuxStatus,omitempty"`
 	SSHAccess           *CheckResultAPI           `json:"sshAccess,omitempty"`
 	KernelModules       *CheckResultAPI           `json:"kernelModules,omitempty"`
+	KernelTaint         *CheckResultAPI           `json:"kernelTaint,omitempty"`
+	PressureStall       *CheckResultAPI           `json:"pressureStall,omitempty"`
+	Entropy             *CheckResultAPI           `json:"entropy,omitempty"`
+	TransparentHugePages *CheckResultAPI          `json:"transparentHugePages,omitempty"`
+	HugePages           *CheckResultAPI           `json:"hugePages,omitempty"`
+	NUMA                *CheckResultAPI           `json:"numa,omitempty"`
+	Conntrack           *CheckResultAPI           `json:"conntrack,omitempty"`
+	KnownIssues         *CheckResultAPI           `json:"knownIssues,omitempty"`
+	Kdump               *CheckResultAPI           `json:"kdump,omitempty"`
+	SysctlDrift         *CheckResultAPI           `json:"sysctlDrift,omitempty"`
+	Inotify             *CheckResultAPI           `json:"inotify,omitempty"`
+	ServiceRestarts     *CheckResultAPI           `json:"serviceRestarts,omitempty"`
+	CoreDumps           *CheckResultAPI           `json:"coreDumps,omitempty"`
+	TimeDrift           *CheckResultAPI           `json:"timeDrift,omitempty"`
+	Audit               *CheckResultAPI           `json:"audit,omitempty"`
+	CPUVulnerabilities  *CheckResultAPI           `json:"cpuVulnerabilities,omitempty"`
+	KernelCmdline       *CheckResultAPI           `json:"kernelCmdline,omitempty"`
+	OSUpdates           *CheckResultAPI           `json:"osUpdates,omitempty"`
+	CgroupDriver        *CheckResultAPI           `json:"cgroupDriver,omitempty"`
+	ProcessLimits       *CheckResultAPI           `json:"processLimits,omitempty"`
+	OrphanedMounts      *CheckResultAPI           `json:"orphanedMounts,omitempty"`
+	UserspaceOOM        *CheckResultAPI           `json:"userspaceOOM,omitempty"`
+	GPU                 *CheckResultAPI           `json:"gpu,omitempty"`
+	SRIOV               *CheckResultAPI           `json:"sriov,omitempty"`
+	KernelLockups       *CheckResultAPI           `json:"kernelLockups,omitempty"`
+	SwapPolicy          *CheckResultAPI           `json:"swapPolicy,omitempty"`
+	CertificateExpiry   *CheckResultAPI           `json:"certificateExpiry,omitempty"`
+	BaselineDrift       *CheckResultAPI           `json:"baselineDrift,omitempty"`
 	Hardware            *HardwareCheckResultsAPI  `json:"hardware,omitempty"`
 	Disks               *DiskCheckResultsAPI      `json:"disks,omitempty"`
 	Network             *NetworkCheckResultsAPI   `json:"network,omitempty"`
@@ -109,6 +150,9 @@ type DiskCheckResultsAPI struct {
 	FilesystemErrors *CheckResultAPI `json:"filesystemErrors,omitempty"`
 	InodeUsage       *CheckResultAPI `json:"inodeUsage,omitempty"`
 	MountPoints      *CheckResultAPI `json:"mountPoints,omitempty"`
+	WriteProbe       *CheckResultAPI `json:"writeProbe,omitempty"`
+	RuntimeStorage   *CheckResultAPI `json:"runtimeStorage,omitempty"`
+	WearLevel        *CheckResultAPI `json:"wearLevel,omitempty"`
 }
 
 // NetworkCheckResultsAPI represents network check results for API responses
@@ -122,6 +166,9 @@ type NetworkCheckResultsAPI struct {
 	DNSResolution *CheckResultAPI `json:"dnsResolution,omitempty"`
 	BondingStatus *CheckResultAPI `json:"bondingStatus,omitempty"`
 	FirewallRules *CheckResultAPI `json:"firewallRules,omitempty"`
+	Egress        *CheckResultAPI `json:"egress,omitempty"`
+	Ingress       *CheckResultAPI `json:"ingress,omitempty"`
+	ResolvConf    *CheckResultAPI `json:"resolvConf,omitempty"`
 }
 
 // KubernetesCheckResultsAPI represents Kubernetes check results for API responses
@@ -135,6 +182,10 @@ type KubernetesCheckResultsAPI struct {
 	KubeletHealth      *CheckResultAPI `json:"kubeletHealth,omitempty"`
 	CNIPlugin          *CheckResultAPI `json:"cniPlugin,omitempty"`
 	NodeConditions     *CheckResultAPI `json:"nodeConditions,omitempty"`
+	PodScheduling      *CheckResultAPI `json:"podScheduling,omitempty"`
+	PVCProvisioning    *CheckResultAPI `json:"pvcProvisioning,omitempty"`
+	PodNetwork         *CheckResultAPI `json:"podNetwork,omitempty"`
+	LBHealthCheck      *CheckResultAPI `json:"lbHealthCheck,omitempty"`
 }
 
 // NodeCheckDetail represents detailed information about a NodeCheck
@@ -146,6 +197,7 @@ type NodeCheckDetail struct {
 
 // CheckSummary represents a summary of a check type across all nodes
 type CheckSummary struct {
+	Key            string `json:"key"` // "<category>:<check>", stable identifier to localize Name in clients
 	Name           string `json:"name"`
 	Category       string `json:"category"` // "system" or "kubernetes"
 	Enabled        bool   `json:"enabled"`
@@ -153,6 +205,7 @@ type CheckSummary struct {
 	WarningCount   int    `json:"warningCount"`
 	CriticalCount  int    `json:"criticalCount"`
 	UnknownCount   int    `json:"unknownCount"`
+	SuppressedCount int   `json:"suppressedCount"`
 	OverallStatus  string `json:"overallStatus"` // Worst status across all nodes
 }
 
@@ -165,6 +218,9 @@ type DashboardStats struct {
 	UnknownNodes    int            `json:"unknownNodes"`
 	LastUpdate      time.Time      `json:"lastUpdate"`
 	Checks          []CheckSummary `json:"checks,omitempty"`
+	// GroupBy and Groups are set when the stats are requested with ?groupBy=
+	GroupBy         string             `json:"groupBy,omitempty"`
+	Groups          []NodeGroupSummary `json:"groups,omitempty"`
 }
 
 // countStatus increments the appropriate counter based on check status
@@ -176,17 +232,29 @@ func countStatus(summary *NodeCheckSummary, status string) {
 		summary.WarningCount++
 	case "Critical":
 		summary.CriticalCount++
+	case maintenance.StatusSuppressed:
+		summary.SuppressedCount++
 	}
 }
 
-// GetDashboardStats returns overall dashboard statistics
+// GetDashboardStats returns overall dashboard statistics. The optional namespace parameter
+// restricts them to the NodeChecks of a namespace.
 func (api *DashboardAPI) GetDashboardStats(c *gin.Context) {
 	ctx := context.Background()
+	namespace := c.Query("namespace")
 	
-	// Get all NodeChecks
+	// Get all NodeChecks (of the namespace, if set)
 	var nodeChecks v1alpha1.NodeCheckList
-	if err := api.k8sClient.List(ctx, &nodeChecks); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := api.k8sClient.List(ctx, &nodeChecks, client.InNamespace(namespace)); err != nil {
+		respondError(c, http.StatusInternalServerError, msgListNodeChecksFailed, map[string]string{"error": err.Error()})
+		return
+	}
+
+	// Polling clients get a 304 while no NodeCheck changed. Groups also depend on the node labels,
+	// so grouped stats are always computed.
+	groupBy := strings.TrimSpace(c.Query("groupBy"))
+	selection := parseFieldSelection(c)
+	if groupBy == "" && notModified(c, nodeCheckListETag("stats"+selection.variant(), nodeChecks.Items)) {
 		return
 	}
 
@@ -413,6 +481,258 @@ func (api *DashboardAPI) GetDashboardStats(c *gin.Context) {
 				updateCheckSummary(checkMap[key], systemResults.KernelModules.Status)
 			}
 
+			// KernelTaint
+			if systemResults.KernelTaint != nil {
+				key := "system:kernel_taint"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Kernel Taint", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.KernelTaint.Status)
+			}
+
+			// PressureStall
+			if systemResults.PressureStall != nil {
+				key := "system:pressure_stall"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Pressure Stall (PSI)", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.PressureStall.Status)
+			}
+
+			// Entropy
+			if systemResults.Entropy != nil {
+				key := "system:entropy"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Entropy Pool", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.Entropy.Status)
+			}
+
+			// TransparentHugePages
+			if systemResults.TransparentHugePages != nil {
+				key := "system:transparent_hugepages"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Transparent Huge Pages", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.TransparentHugePages.Status)
+			}
+
+			// HugePages
+			if systemResults.HugePages != nil {
+				key := "system:huge_pages"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Huge Pages", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.HugePages.Status)
+			}
+
+			// NUMA
+			if systemResults.NUMA != nil {
+				key := "system:numa"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "NUMA Balance", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.NUMA.Status)
+			}
+
+			// Conntrack
+			if systemResults.Conntrack != nil {
+				key := "system:conntrack"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Conntrack Table", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.Conntrack.Status)
+			}
+
+			// KnownIssues
+			if systemResults.KnownIssues != nil {
+				key := "system:known_issues"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Known Issues", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.KnownIssues.Status)
+			}
+
+			// Kdump
+			if systemResults.Kdump != nil {
+				key := "system:kdump"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Kdump Readiness", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.Kdump.Status)
+			}
+
+			// SysctlDrift
+			if systemResults.SysctlDrift != nil {
+				key := "system:sysctl_drift"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Sysctl Drift", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.SysctlDrift.Status)
+			}
+
+			// Inotify
+			if systemResults.Inotify != nil {
+				key := "system:inotify"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Inotify Limits", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.Inotify.Status)
+			}
+
+			// ServiceRestarts
+			if systemResults.ServiceRestarts != nil {
+				key := "system:service_restarts"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Service Restarts", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.ServiceRestarts.Status)
+			}
+
+			// CoreDumps
+			if systemResults.CoreDumps != nil {
+				key := "system:core_dumps"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Core Dumps", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.CoreDumps.Status)
+			}
+
+			// TimeDrift
+			if systemResults.TimeDrift != nil {
+				key := "system:time_drift"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Time Drift", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.TimeDrift.Status)
+			}
+
+			// Audit
+			if systemResults.Audit != nil {
+				key := "system:audit"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Audit Subsystem", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.Audit.Status)
+			}
+
+			// CPUVulnerabilities
+			if systemResults.CPUVulnerabilities != nil {
+				key := "system:cpu_vulnerabilities"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "CPU Vulnerabilities", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.CPUVulnerabilities.Status)
+			}
+
+			// KernelCmdline
+			if systemResults.KernelCmdline != nil {
+				key := "system:kernel_cmdline"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Kernel Command Line", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.KernelCmdline.Status)
+			}
+
+			// OSUpdates
+			if systemResults.OSUpdates != nil {
+				key := "system:os_updates"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "OS Updates", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.OSUpdates.Status)
+			}
+
+			// CgroupDriver
+			if systemResults.CgroupDriver != nil {
+				key := "system:cgroup_driver"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Cgroup Driver", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.CgroupDriver.Status)
+			}
+
+			// ProcessLimits
+			if systemResults.ProcessLimits != nil {
+				key := "system:process_limits"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Process Limits", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.ProcessLimits.Status)
+			}
+
+			// OrphanedMounts
+			if systemResults.OrphanedMounts != nil {
+				key := "system:orphaned_mounts"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Orphaned Mounts", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.OrphanedMounts.Status)
+			}
+
+			// UserspaceOOM
+			if systemResults.UserspaceOOM != nil {
+				key := "system:userspace_oom"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Userspace OOM", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.UserspaceOOM.Status)
+			}
+
+			// GPU
+			if systemResults.GPU != nil {
+				key := "system:gpu"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "GPU", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.GPU.Status)
+			}
+
+			// SRIOV
+			if systemResults.SRIOV != nil {
+				key := "system:sriov"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "SR-IOV", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.SRIOV.Status)
+			}
+
+			// KernelLockups
+			if systemResults.KernelLockups != nil {
+				key := "system:kernel_lockups"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Kernel Lockups", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.KernelLockups.Status)
+			}
+
+			// SwapPolicy
+			if systemResults.SwapPolicy != nil {
+				key := "system:swap_policy"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Swap Policy", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.SwapPolicy.Status)
+			}
+
+			// CertificateExpiry
+			if systemResults.CertificateExpiry != nil {
+				key := "system:certificate_expiry"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Certificate Expiry", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.CertificateExpiry.Status)
+			}
+
+			// BaselineDrift
+			if systemResults.BaselineDrift != nil {
+				key := "system:baseline_drift"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Baseline Drift", Category: "system", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], systemResults.BaselineDrift.Status)
+			}
+
 			// Disks
 			if systemResults.Disks != nil {
 				if systemResults.Disks.Space != nil {
@@ -492,6 +812,27 @@ func (api *DashboardAPI) GetDashboardStats(c *gin.Context) {
 					}
 					updateCheckSummary(checkMap[key], systemResults.Disks.MountPoints.Status)
 				}
+				if systemResults.Disks.WriteProbe != nil {
+					key := "system:disk_write_probe"
+					if checkMap[key] == nil {
+						checkMap[key] = &CheckSummary{Name: "Write Probe", Category: "system", Enabled: true}
+					}
+					updateCheckSummary(checkMap[key], systemResults.Disks.WriteProbe.Status)
+				}
+				if systemResults.Disks.RuntimeStorage != nil {
+					key := "system:disk_runtime_storage"
+					if checkMap[key] == nil {
+						checkMap[key] = &CheckSummary{Name: "Runtime Storage", Category: "system", Enabled: true}
+					}
+					updateCheckSummary(checkMap[key], systemResults.Disks.RuntimeStorage.Status)
+				}
+				if systemResults.Disks.WearLevel != nil {
+					key := "system:disk_wear_level"
+					if checkMap[key] == nil {
+						checkMap[key] = &CheckSummary{Name: "Wear Level", Category: "system", Enabled: true}
+					}
+					updateCheckSummary(checkMap[key], systemResults.Disks.WearLevel.Status)
+				}
 			}
 
 			// Network
@@ -559,6 +900,27 @@ func (api *DashboardAPI) GetDashboardStats(c *gin.Context) {
 					}
 					updateCheckSummary(checkMap[key], systemResults.Network.FirewallRules.Status)
 				}
+				if systemResults.Network.Egress != nil {
+					key := "system:network_egress"
+					if checkMap[key] == nil {
+						checkMap[key] = &CheckSummary{Name: "Egress", Category: "system", Enabled: true}
+					}
+					updateCheckSummary(checkMap[key], systemResults.Network.Egress.Status)
+				}
+				if systemResults.Network.Ingress != nil {
+					key := "system:network_ingress"
+					if checkMap[key] == nil {
+						checkMap[key] = &CheckSummary{Name: "Ingress", Category: "system", Enabled: true}
+					}
+					updateCheckSummary(checkMap[key], systemResults.Network.Ingress.Status)
+				}
+				if systemResults.Network.ResolvConf != nil {
+					key := "system:network_resolv_conf"
+					if checkMap[key] == nil {
+						checkMap[key] = &CheckSummary{Name: "Resolv Conf", Category: "system", Enabled: true}
+					}
+					updateCheckSummary(checkMap[key], systemResults.Network.ResolvConf.Status)
+				}
 			}
 
 			// Hardware
@@ -698,12 +1060,49 @@ func (api *DashboardAPI) GetDashboardStats(c *gin.Context) {
 				updateCheckSummary(checkMap[key], k8sResults.NodeConditions.Status)
 			}
 
+			if k8sResults.PodScheduling != nil {
+				key := "kubernetes:pod_scheduling"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Pod Scheduling", Category: "kubernetes", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], k8sResults.PodScheduling.Status)
+			}
+
+			if k8sResults.PVCProvisioning != nil {
+				key := "kubernetes:pvc_provisioning"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "PVC Provisioning", Category: "kubernetes", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], k8sResults.PVCProvisioning.Status)
+			}
+
+			if k8sResults.PodNetwork != nil {
+				key := "kubernetes:pod_network"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "Pod Network", Category: "kubernetes", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], k8sResults.PodNetwork.Status)
+			}
+
+			if k8sResults.LBHealthCheck != nil {
+				key := "kubernetes:lb_health_check"
+				if checkMap[key] == nil {
+					checkMap[key] = &CheckSummary{Name: "LB Health Check", Category: "kubernetes", Enabled: true}
+				}
+				updateCheckSummary(checkMap[key], k8sResults.LBHealthCheck.Status)
+			}
+
 		}
 	}
 
 	// Convert map to slice and calculate overall status
-	for _, check := range checkMap {
+	for key, check := range checkMap {
+		check.Key = key
 		check.OverallStatus = calculateOverallStatus(check.HealthyCount, check.WarningCount, check.CriticalCount, check.UnknownCount)
+		// A check that is suppressed on every node it ran on is reported as Suppressed
+		if check.HealthyCount+check.WarningCount+check.CriticalCount+check.UnknownCount == 0 && check.SuppressedCount > 0 {
+			check.OverallStatus = maintenance.StatusSuppressed
+		}
 		stats.Checks = append(stats.Checks, *check)
 	}
 
@@ -728,38 +1127,16 @@ func (api *DashboardAPI) GetDashboardStats(c *gin.Context) {
 				"Warning":  check.WarningCount,
 				"Critical": check.CriticalCount,
 				"Unknown":  check.UnknownCount,
+				maintenance.StatusSuppressed: check.SuppressedCount,
 			},
 		})
 	}
 
-	// Helper function to deserialize RawExtension details to map
-	deserializeDetails := func(cr *v1alpha1.CheckResult) map[string]interface{} {
-		if cr == nil || len(cr.Details.Raw) == 0 {
-			return nil
-		}
-		var details map[string]interface{}
-		if err := json.Unmarshal(cr.Details.Raw, &details); err == nil {
-			// Parse JSON strings back to objects where needed
-			for k, v := range details {
-				if str, ok := v.(string); ok {
-					// Try to parse as JSON if it looks like JSON
-					if (strings.HasPrefix(str, "{") && strings.HasSuffix(str, "}")) ||
-					   (strings.HasPrefix(str, "[") && strings.HasSuffix(str, "]")) {
-						var parsed interface{}
-						if err := json.Unmarshal([]byte(str), &parsed); err == nil {
-							details[k] = parsed
-						}
-					}
-				}
-			}
-			return details
-		}
-		return nil
-	}
-
-	// Extract node-level metrics from NodeChecks
+	// Extract node-level metrics from NodeChecks. The details are only unmarshalled again for the
+	// NodeChecks that changed since the previous request.
 	nodeMetricsMap := make(map[string]*metrics.NodeMetricsSnapshot)
-	for _, nc := range filteredNodeChecks {
+	for i := range filteredNodeChecks {
+		nc := &filteredNodeChecks[i]
 		nodeName := nc.Spec.NodeName
 		if nodeName == "" || nodeName == "*" || nodeName == "all" {
 			continue
@@ -771,95 +1148,45 @@ func (api *DashboardAPI) GetDashboardStats(c *gin.Context) {
 			}
 		}
 		nodeMetrics := nodeMetricsMap[nodeName]
-
-		systemResults := nc.Status.CheckResults.SystemResults
-
-		// Extract temperature (average from all sensors)
-		if systemResults.Hardware != nil && systemResults.Hardware.Temperature != nil {
-			details := deserializeDetails(systemResults.Hardware.Temperature)
-			if details != nil {
-				if temps, ok := details["temperatures"].(map[string]interface{}); ok {
-					sumTemp := 0.0
-					count := 0
-					for _, tempVal := range temps {
-						if temp, ok := tempVal.(float64); ok && temp > 0 {
-							sumTemp += temp
-							count++
-						}
-					}
-					if count > 0 {
-						avgTemp := sumTemp / float64(count)
-						nodeMetrics.Temperature = &avgTemp
-					}
-				}
+		if len(nc.Spec.ResultLabels) > 0 {
+			if nodeMetrics.ResultLabels == nil {
+				nodeMetrics.ResultLabels = make(map[string]string)
 			}
-		}
-
-		// Extract CPU usage
-		if systemResults.Resources != nil {
-			details := deserializeDetails(systemResults.Resources)
-			if details != nil {
-				var cpuValue float64
-				if cpu, ok := details["cpu_usage"].(float64); ok {
-					cpuValue = cpu
-				} else if cpu, ok := details["cpuUsage"].(float64); ok {
-					cpuValue = cpu
-				} else if cpu, ok := details["cpu"].(float64); ok {
-					cpuValue = cpu
-				} else if cpuIdle, ok := details["cpu_idle_percent"].(float64); ok {
-					cpuValue = 100 - cpuIdle
-				} else if cpuUser, ok := details["cpu_user_percent"].(float64); ok {
-					cpuSys := 0.0
-					if cpuSysVal, ok := details["cpu_system_percent"].(float64); ok {
-						cpuSys = cpuSysVal
-					}
-					cpuValue = cpuUser + cpuSys
-				}
-				if cpuValue > 0 {
-					nodeMetrics.CPUUsage = &cpuValue
-				}
+			for key, value := range nc.Spec.ResultLabels {
+				nodeMetrics.ResultLabels[key] = value
 			}
 		}
 
-		// Extract memory usage
-		if systemResults.Memory != nil {
-			details := deserializeDetails(systemResults.Memory)
-			if details != nil {
-				var memValue float64
-				if mem, ok := details["memory_usage_percent"].(float64); ok {
-					memValue = mem
-				} else if mem, ok := details["memoryUsage"].(float64); ok {
-					memValue = mem
-				} else if mem, ok := details["used_percent"].(float64); ok {
-					memValue = mem
-				} else if usedKB, ok := details["memory_used_kb"].(float64); ok {
-					if totalKB, ok := details["memory_total_kb"].(float64); ok && totalKB > 0 {
-						memValue = (usedKB / totalKB) * 100
-					}
-				}
-				if memValue > 0 {
-					nodeMetrics.MemoryUsage = &memValue
-				}
-			}
+		extracted := api.summaries.nodeMetrics(nc)
+		if extracted.Temperature != nil {
+			nodeMetrics.Temperature = extracted.Temperature
+		}
+		if extracted.CPUUsage != nil {
+			nodeMetrics.CPUUsage = extracted.CPUUsage
+		}
+		if extracted.MemoryUsage != nil {
+			nodeMetrics.MemoryUsage = extracted.MemoryUsage
+		}
+		if extracted.LoadAverage1m != nil {
+			nodeMetrics.LoadAverage1m = extracted.LoadAverage1m
+		}
+		if extracted.LoadAverage5m != nil {
+			nodeMetrics.LoadAverage5m = extracted.LoadAverage5m
+		}
+		if extracted.LoadAverage15m != nil {
+			nodeMetrics.LoadAverage15m = extracted.LoadAverage15m
 		}
+	}
+	api.summaries.prune(nodeChecks.Items)
 
-		// Extract uptime and load averages
-		if systemResults.Uptime != nil {
-			details := deserializeDetails(systemResults.Uptime)
-			if details != nil {
-				if load1m, ok := details["load_1min"].(float64); ok {
-					nodeMetrics.LoadAverage1m = &load1m
-				}
-				if load5m, ok := details["load_5min"].(float64); ok {
-					nodeMetrics.LoadAverage5m = &load5m
-				}
-				if load15m, ok := details["load_15min"].(float64); ok {
-					nodeMetrics.LoadAverage15m = &load15m
-				}
-				// Parse uptime string to seconds (simplified - would need proper parsing)
-				// For now, we'll skip uptime parsing as it requires parsing the uptime string
-			}
+	compliance := buildCompliance(filteredNodeChecks)
+	metricsSnapshot.ComplianceScore = compliance.Score
+	for _, node := range compliance.Nodes {
+		if nodeMetricsMap[node.Node] == nil {
+			nodeMetricsMap[node.Node] = &metrics.NodeMetricsSnapshot{NodeName: node.Node}
 		}
+		score := node.Score
+		nodeMetricsMap[node.Node].ComplianceScore = &score
 	}
 
 	// Convert map to slice
@@ -869,7 +1196,18 @@ func (api *DashboardAPI) GetDashboardStats(c *gin.Context) {
 
 	metrics.UpdateDashboardMetrics(metricsSnapshot)
 
-	c.JSON(http.StatusOK, stats)
+	// Roll up the node health per group (e.g. ?groupBy=zone), so degrading pools stand out
+	if groupBy != "" {
+		groups, err := api.groupNodeChecks(ctx, groupBy, filteredNodeChecks)
+		if err != nil {
+			respondError(c, http.StatusInternalServerError, msgGroupNodesFailed, map[string]string{"groupBy": groupBy, "error": err.Error()})
+			return
+		}
+		stats.GroupBy = groupBy
+		stats.Groups = groups
+	}
+
+	respondSelected(c, http.StatusOK, selection, stats)
 }
 
 // updateCheckSummary updates a check summary with a status
@@ -881,6 +1219,8 @@ func updateCheckSummary(summary *CheckSummary, status string) {
 		summary.WarningCount++
 	case "Critical":
 		summary.CriticalCount++
+	case maintenance.StatusSuppressed:
+		summary.SuppressedCount++
 	default:
 		summary.UnknownCount++
 	}
@@ -906,550 +1246,461 @@ func (api *DashboardAPI) GetNodeChecks(c *gin.Context) {
 	
 	var nodeChecks v1alpha1.NodeCheckList
 	if err := api.k8sClient.List(ctx, &nodeChecks); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, msgListNodeChecksFailed, map[string]string{"error": err.Error()})
+		return
+	}
+	selection := parseFieldSelection(c)
+	if notModified(c, nodeCheckListETag("nodechecks"+selection.variant(), nodeChecks.Items)) {
 		return
 	}
 
 	summaries := make([]NodeCheckSummary, len(nodeChecks.Items))
-	for i, nc := range nodeChecks.Items {
-		summary := NodeCheckSummary{
-			Name:          nc.Name,
-			Namespace:     nc.Namespace,
-			NodeName:      nc.Spec.NodeName,
-			OverallStatus: nc.Status.OverallStatus,
-			LastCheck:     nc.Status.LastCheckTime.Time,
-			Message:       nc.Status.Message,
-		}
-
-		// Count all check results
-		// System checks
-		if nc.Status.CheckResults.SystemResults.Uptime != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.Uptime.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.Processes != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.Processes.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.Resources != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.Resources.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.Memory != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.Memory.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.UninterruptibleTasks != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.UninterruptibleTasks.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.Services != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.Services.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.SystemLogs != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.SystemLogs.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.FileDescriptors != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.FileDescriptors.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.ZombieProcesses != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.ZombieProcesses.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.NTPSync != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.NTPSync.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.KernelPanics != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.KernelPanics.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.OOMKiller != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.OOMKiller.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.CPUFrequency != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.CPUFrequency.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.InterruptsBalance != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.InterruptsBalance.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.CPUStealTime != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.CPUStealTime.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.MemoryFragmentation != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.MemoryFragmentation.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.SwapActivity != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.SwapActivity.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.ContextSwitches != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.ContextSwitches.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.SELinuxStatus != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.SELinuxStatus.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.SSHAccess != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.SSHAccess.Status)
-		}
-		if nc.Status.CheckResults.SystemResults.KernelModules != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.SystemResults.KernelModules.Status)
-		}
-		
-		// Hardware checks
-		if nc.Status.CheckResults.SystemResults.Hardware != nil {
-			if nc.Status.CheckResults.SystemResults.Hardware.Temperature != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.Temperature.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Hardware.IPMI != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.IPMI.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Hardware.BMC != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.BMC.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Hardware.FanStatus != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.FanStatus.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Hardware.PowerSupply != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.PowerSupply.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Hardware.MemoryErrors != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.MemoryErrors.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Hardware.PCIeErrors != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.PCIeErrors.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Hardware.CPUMicrocode != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.CPUMicrocode.Status)
-			}
-		}
-		
-		// Disk checks
-		if nc.Status.CheckResults.SystemResults.Disks != nil {
-			if nc.Status.CheckResults.SystemResults.Disks.Space != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.Space.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.SMART != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.SMART.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.Performance != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.Performance.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.RAID != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.RAID.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.PVs != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.PVs.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.LVM != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.LVM.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.IOWait != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.IOWait.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.QueueDepth != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.QueueDepth.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.FilesystemErrors != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.FilesystemErrors.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.InodeUsage != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.InodeUsage.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Disks.MountPoints != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.MountPoints.Status)
-			}
-		}
-		
-		// Network checks
-		if nc.Status.CheckResults.SystemResults.Network != nil {
-			if nc.Status.CheckResults.SystemResults.Network.Interfaces != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Interfaces.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Network.Routing != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Routing.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Network.Connectivity != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Connectivity.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Network.Statistics != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Statistics.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Network.Errors != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Errors.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Network.Latency != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Latency.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Network.DNSResolution != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.DNSResolution.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Network.BondingStatus != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.BondingStatus.Status)
-			}
-			if nc.Status.CheckResults.SystemResults.Network.FirewallRules != nil {
-				summary.CheckCount++
-				countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.FirewallRules.Status)
-			}
-		}
-		
-		// Kubernetes checks
-		if nc.Status.CheckResults.KubernetesResults.NodeStatus != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.KubernetesResults.NodeStatus.Status)
-		}
-		if nc.Status.CheckResults.KubernetesResults.Pods != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.KubernetesResults.Pods.Status)
-		}
-		if nc.Status.CheckResults.KubernetesResults.ClusterOperators != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.KubernetesResults.ClusterOperators.Status)
-		}
-		if nc.Status.CheckResults.KubernetesResults.NodeResources != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.KubernetesResults.NodeResources.Status)
-		}
-		if nc.Status.CheckResults.KubernetesResults.NodeResourceUsage != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.KubernetesResults.NodeResourceUsage.Status)
-		}
-		if nc.Status.CheckResults.KubernetesResults.ContainerRuntime != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.KubernetesResults.ContainerRuntime.Status)
-		}
-		if nc.Status.CheckResults.KubernetesResults.KubeletHealth != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.KubernetesResults.KubeletHealth.Status)
-		}
-		if nc.Status.CheckResults.KubernetesResults.CNIPlugin != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.KubernetesResults.CNIPlugin.Status)
-		}
-		if nc.Status.CheckResults.KubernetesResults.NodeConditions != nil {
-			summary.CheckCount++
-			countStatus(&summary, nc.Status.CheckResults.KubernetesResults.NodeConditions.Status)
-		}
-
-		summaries[i] = summary
+	for i := range nodeChecks.Items {
+		summaries[i] = api.summaries.summary(&nodeChecks.Items[i])
 	}
+	api.summaries.prune(nodeChecks.Items)
 
-	c.JSON(http.StatusOK, summaries)
+	respondSelected(c, http.StatusOK, selection, summaries)
 }
 
-// GetNodeCheckDetail returns detailed information about a specific NodeCheck
-func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
-	ctx := context.Background()
-	
-	name := c.Param("name")
-	// Usa node-check-operator-system come namespace di default invece di "default"
-	namespace := c.DefaultQuery("namespace", "node-check-operator-system")
-
-	var nodeCheck v1alpha1.NodeCheck
-	key := client.ObjectKey{Name: name, Namespace: namespace}
-	
-	if err := api.k8sClient.Get(ctx, key, &nodeCheck); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "NodeCheck not found"})
-		return
-	}
-
+// summarizeNodeCheck returns the summary of a NodeCheck with the count of its check results per status
+func summarizeNodeCheck(nc v1alpha1.NodeCheck) NodeCheckSummary {
 	summary := NodeCheckSummary{
-			Name:          nodeCheck.Name,
-			Namespace:     nodeCheck.Namespace,
-			NodeName:      nodeCheck.Spec.NodeName,
-			OverallStatus: nodeCheck.Status.OverallStatus,
-			LastCheck:     nodeCheck.Status.LastCheckTime.Time,
-			Message:       nodeCheck.Status.Message,
-	}
-
-	// Count all check results (same logic as GetNodeChecks)
+		Name:          nc.Name,
+		Namespace:     nc.Namespace,
+		NodeName:      nc.Spec.NodeName,
+		OverallStatus: nc.Status.OverallStatus,
+		LastCheck:     nc.Status.LastCheckTime.Time,
+		Message:       nc.Status.Message,
+		SuppressedBy:  nc.Status.SuppressedBy,
+		ResultLabels:  nc.Spec.ResultLabels,
+		ResultAnnotations: nc.Spec.ResultAnnotations,
+		Verification:  nc.Status.Verification,
+	}
+
+	// Count all check results
 	// System checks
-	if nodeCheck.Status.CheckResults.SystemResults.Uptime != nil {
+	if nc.Status.CheckResults.SystemResults.Uptime != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Uptime.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.Processes != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Processes.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.Resources != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Resources.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.Memory != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Memory.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.UninterruptibleTasks != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.UninterruptibleTasks.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.Services != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Services.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.SystemLogs != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.SystemLogs.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.FileDescriptors != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.FileDescriptors.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.ZombieProcesses != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.ZombieProcesses.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.NTPSync != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.NTPSync.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.KernelPanics != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.KernelPanics.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.OOMKiller != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.OOMKiller.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.CPUFrequency != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.CPUFrequency.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.InterruptsBalance != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.InterruptsBalance.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.CPUStealTime != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.CPUStealTime.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.MemoryFragmentation != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.MemoryFragmentation.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.SwapActivity != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.SwapActivity.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.ContextSwitches != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.ContextSwitches.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.SELinuxStatus != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.SELinuxStatus.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.SSHAccess != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.SSHAccess.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.KernelModules != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Uptime.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.KernelModules.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.Processes != nil {
+	if nc.Status.CheckResults.SystemResults.KernelTaint != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Processes.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.KernelTaint.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.Resources != nil {
+	if nc.Status.CheckResults.SystemResults.PressureStall != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Resources.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.PressureStall.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.Memory != nil {
+	if nc.Status.CheckResults.SystemResults.Entropy != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Memory.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Entropy.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.UninterruptibleTasks != nil {
+	if nc.Status.CheckResults.SystemResults.TransparentHugePages != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.UninterruptibleTasks.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.TransparentHugePages.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.Services != nil {
+	if nc.Status.CheckResults.SystemResults.HugePages != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Services.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.HugePages.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.SystemLogs != nil {
+	if nc.Status.CheckResults.SystemResults.NUMA != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.SystemLogs.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.NUMA.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.FileDescriptors != nil {
+	if nc.Status.CheckResults.SystemResults.Conntrack != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.FileDescriptors.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Conntrack.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.ZombieProcesses != nil {
+	if nc.Status.CheckResults.SystemResults.KnownIssues != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.ZombieProcesses.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.KnownIssues.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.NTPSync != nil {
+	if nc.Status.CheckResults.SystemResults.Kdump != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.NTPSync.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Kdump.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.KernelPanics != nil {
+	if nc.Status.CheckResults.SystemResults.SysctlDrift != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.KernelPanics.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.SysctlDrift.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.OOMKiller != nil {
+	if nc.Status.CheckResults.SystemResults.Inotify != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.OOMKiller.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Inotify.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.CPUFrequency != nil {
+	if nc.Status.CheckResults.SystemResults.ServiceRestarts != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.CPUFrequency.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.ServiceRestarts.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.InterruptsBalance != nil {
+	if nc.Status.CheckResults.SystemResults.CoreDumps != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.InterruptsBalance.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.CoreDumps.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.CPUStealTime != nil {
+	if nc.Status.CheckResults.SystemResults.TimeDrift != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.CPUStealTime.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.TimeDrift.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.MemoryFragmentation != nil {
+	if nc.Status.CheckResults.SystemResults.Audit != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.MemoryFragmentation.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.Audit.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.SwapActivity != nil {
+	if nc.Status.CheckResults.SystemResults.CPUVulnerabilities != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.SwapActivity.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.CPUVulnerabilities.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.ContextSwitches != nil {
+	if nc.Status.CheckResults.SystemResults.KernelCmdline != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.ContextSwitches.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.KernelCmdline.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.SELinuxStatus != nil {
+	if nc.Status.CheckResults.SystemResults.OSUpdates != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.SELinuxStatus.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.OSUpdates.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.SSHAccess != nil {
+	if nc.Status.CheckResults.SystemResults.CgroupDriver != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.SSHAccess.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.CgroupDriver.Status)
 	}
-	if nodeCheck.Status.CheckResults.SystemResults.KernelModules != nil {
+	if nc.Status.CheckResults.SystemResults.ProcessLimits != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.KernelModules.Status)
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.ProcessLimits.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.OrphanedMounts != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.OrphanedMounts.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.UserspaceOOM != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.UserspaceOOM.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.GPU != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.GPU.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.SRIOV != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.SRIOV.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.KernelLockups != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.KernelLockups.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.SwapPolicy != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.SwapPolicy.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.CertificateExpiry != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.CertificateExpiry.Status)
+	}
+	if nc.Status.CheckResults.SystemResults.BaselineDrift != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.SystemResults.BaselineDrift.Status)
 	}
 	
 	// Hardware checks
-	if nodeCheck.Status.CheckResults.SystemResults.Hardware != nil {
-		if nodeCheck.Status.CheckResults.SystemResults.Hardware.Temperature != nil {
+	if nc.Status.CheckResults.SystemResults.Hardware != nil {
+		if nc.Status.CheckResults.SystemResults.Hardware.Temperature != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Hardware.Temperature.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.Temperature.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Hardware.IPMI != nil {
+		if nc.Status.CheckResults.SystemResults.Hardware.IPMI != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Hardware.IPMI.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.IPMI.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Hardware.BMC != nil {
+		if nc.Status.CheckResults.SystemResults.Hardware.BMC != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Hardware.BMC.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.BMC.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Hardware.FanStatus != nil {
+		if nc.Status.CheckResults.SystemResults.Hardware.FanStatus != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Hardware.FanStatus.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.FanStatus.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Hardware.PowerSupply != nil {
+		if nc.Status.CheckResults.SystemResults.Hardware.PowerSupply != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Hardware.PowerSupply.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.PowerSupply.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Hardware.MemoryErrors != nil {
+		if nc.Status.CheckResults.SystemResults.Hardware.MemoryErrors != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Hardware.MemoryErrors.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.MemoryErrors.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Hardware.PCIeErrors != nil {
+		if nc.Status.CheckResults.SystemResults.Hardware.PCIeErrors != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Hardware.PCIeErrors.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.PCIeErrors.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Hardware.CPUMicrocode != nil {
+		if nc.Status.CheckResults.SystemResults.Hardware.CPUMicrocode != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Hardware.CPUMicrocode.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Hardware.CPUMicrocode.Status)
 		}
 	}
 	
 	// Disk checks
-	if nodeCheck.Status.CheckResults.SystemResults.Disks != nil {
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.Space != nil {
+	if nc.Status.CheckResults.SystemResults.Disks != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.Space != nil {
+			summary.CheckCount++
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.Space.Status)
+		}
+		if nc.Status.CheckResults.SystemResults.Disks.SMART != nil {
+			summary.CheckCount++
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.SMART.Status)
+		}
+		if nc.Status.CheckResults.SystemResults.Disks.Performance != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.Space.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.Performance.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.SMART != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.RAID != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.SMART.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.RAID.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.Performance != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.PVs != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.Performance.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.PVs.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.RAID != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.LVM != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.RAID.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.LVM.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.PVs != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.IOWait != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.PVs.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.IOWait.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.LVM != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.QueueDepth != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.LVM.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.QueueDepth.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.IOWait != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.FilesystemErrors != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.IOWait.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.FilesystemErrors.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.QueueDepth != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.InodeUsage != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.QueueDepth.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.InodeUsage.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.FilesystemErrors != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.MountPoints != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.FilesystemErrors.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.MountPoints.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.InodeUsage != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.WriteProbe != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.InodeUsage.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.WriteProbe.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Disks.MountPoints != nil {
+		if nc.Status.CheckResults.SystemResults.Disks.RuntimeStorage != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Disks.MountPoints.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.RuntimeStorage.Status)
+		}
+		if nc.Status.CheckResults.SystemResults.Disks.WearLevel != nil {
+			summary.CheckCount++
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Disks.WearLevel.Status)
 		}
 	}
 	
 	// Network checks
-	if nodeCheck.Status.CheckResults.SystemResults.Network != nil {
-		if nodeCheck.Status.CheckResults.SystemResults.Network.Interfaces != nil {
+	if nc.Status.CheckResults.SystemResults.Network != nil {
+		if nc.Status.CheckResults.SystemResults.Network.Interfaces != nil {
+			summary.CheckCount++
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Interfaces.Status)
+		}
+		if nc.Status.CheckResults.SystemResults.Network.Routing != nil {
+			summary.CheckCount++
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Routing.Status)
+		}
+		if nc.Status.CheckResults.SystemResults.Network.Connectivity != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Network.Interfaces.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Connectivity.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Network.Routing != nil {
+		if nc.Status.CheckResults.SystemResults.Network.Statistics != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Network.Routing.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Statistics.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Network.Connectivity != nil {
+		if nc.Status.CheckResults.SystemResults.Network.Errors != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Network.Connectivity.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Errors.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Network.Statistics != nil {
+		if nc.Status.CheckResults.SystemResults.Network.Latency != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Network.Statistics.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Latency.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Network.Errors != nil {
+		if nc.Status.CheckResults.SystemResults.Network.DNSResolution != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Network.Errors.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.DNSResolution.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Network.Latency != nil {
+		if nc.Status.CheckResults.SystemResults.Network.BondingStatus != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Network.Latency.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.BondingStatus.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Network.DNSResolution != nil {
+		if nc.Status.CheckResults.SystemResults.Network.FirewallRules != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Network.DNSResolution.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.FirewallRules.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Network.BondingStatus != nil {
+		if nc.Status.CheckResults.SystemResults.Network.Egress != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Network.BondingStatus.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Egress.Status)
 		}
-		if nodeCheck.Status.CheckResults.SystemResults.Network.FirewallRules != nil {
+		if nc.Status.CheckResults.SystemResults.Network.Ingress != nil {
 			summary.CheckCount++
-			countStatus(&summary, nodeCheck.Status.CheckResults.SystemResults.Network.FirewallRules.Status)
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.Ingress.Status)
+		}
+		if nc.Status.CheckResults.SystemResults.Network.ResolvConf != nil {
+			summary.CheckCount++
+			countStatus(&summary, nc.Status.CheckResults.SystemResults.Network.ResolvConf.Status)
 		}
 	}
 	
 	// Kubernetes checks
-	if nodeCheck.Status.CheckResults.KubernetesResults.NodeStatus != nil {
+	if nc.Status.CheckResults.KubernetesResults.NodeStatus != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.NodeStatus.Status)
+	}
+	if nc.Status.CheckResults.KubernetesResults.Pods != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.Pods.Status)
+	}
+	if nc.Status.CheckResults.KubernetesResults.ClusterOperators != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.ClusterOperators.Status)
+	}
+	if nc.Status.CheckResults.KubernetesResults.NodeResources != nil {
+		summary.CheckCount++
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.NodeResources.Status)
+	}
+	if nc.Status.CheckResults.KubernetesResults.NodeResourceUsage != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.KubernetesResults.NodeStatus.Status)
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.NodeResourceUsage.Status)
 	}
-	if nodeCheck.Status.CheckResults.KubernetesResults.Pods != nil {
+	if nc.Status.CheckResults.KubernetesResults.ContainerRuntime != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.KubernetesResults.Pods.Status)
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.ContainerRuntime.Status)
 	}
-	if nodeCheck.Status.CheckResults.KubernetesResults.ClusterOperators != nil {
+	if nc.Status.CheckResults.KubernetesResults.KubeletHealth != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.KubernetesResults.ClusterOperators.Status)
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.KubeletHealth.Status)
 	}
-	if nodeCheck.Status.CheckResults.KubernetesResults.NodeResources != nil {
+	if nc.Status.CheckResults.KubernetesResults.CNIPlugin != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.KubernetesResults.NodeResources.Status)
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.CNIPlugin.Status)
 	}
-	if nodeCheck.Status.CheckResults.KubernetesResults.NodeResourceUsage != nil {
+	if nc.Status.CheckResults.KubernetesResults.NodeConditions != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.KubernetesResults.NodeResourceUsage.Status)
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.NodeConditions.Status)
 	}
-	if nodeCheck.Status.CheckResults.KubernetesResults.ContainerRuntime != nil {
+	if nc.Status.CheckResults.KubernetesResults.PodScheduling != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.KubernetesResults.ContainerRuntime.Status)
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.PodScheduling.Status)
 	}
-	if nodeCheck.Status.CheckResults.KubernetesResults.KubeletHealth != nil {
+	if nc.Status.CheckResults.KubernetesResults.PVCProvisioning != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.KubernetesResults.KubeletHealth.Status)
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.PVCProvisioning.Status)
 	}
-	if nodeCheck.Status.CheckResults.KubernetesResults.CNIPlugin != nil {
+	if nc.Status.CheckResults.KubernetesResults.PodNetwork != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.KubernetesResults.CNIPlugin.Status)
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.PodNetwork.Status)
 	}
-	if nodeCheck.Status.CheckResults.KubernetesResults.NodeConditions != nil {
+	if nc.Status.CheckResults.KubernetesResults.LBHealthCheck != nil {
 		summary.CheckCount++
-		countStatus(&summary, nodeCheck.Status.CheckResults.KubernetesResults.NodeConditions.Status)
+		countStatus(&summary, nc.Status.CheckResults.KubernetesResults.LBHealthCheck.Status)
+	}
+
+	return summary
+}
+
+// GetNodeCheckDetail returns detailed information about a specific NodeCheck
+func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
+	ctx := context.Background()
+	
+	name := c.Param("name")
+	// Usa node-check-operator-system come namespace di default invece di "default"
+	namespace := c.DefaultQuery("namespace", "node-check-operator-system")
+
+	var nodeCheck v1alpha1.NodeCheck
+	key := client.ObjectKey{Name: name, Namespace: namespace}
+	
+	if err := api.k8sClient.Get(ctx, key, &nodeCheck); err != nil {
+		respondError(c, http.StatusNotFound, msgNodeCheckNotFound, map[string]string{"namespace": namespace, "name": name})
+		return
 	}
 
+	summary := summarizeNodeCheck(nodeCheck)
+	units := parseUnits(c)
+
 	// Convert CheckResult to CheckResultAPI (deserialize RawExtension details)
 	convertCheckResult := func(cr *v1alpha1.CheckResult) *CheckResultAPI {
 		if cr == nil {
@@ -1460,27 +1711,13 @@ func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
 			Message:   cr.Message,
 			Timestamp: cr.Timestamp.Format(time.RFC3339),
 			Command:   cr.Command,
+			RunID:     cr.RunID,
 		}
 		
-		// Deserialize RawExtension details to map
-		if len(cr.Details.Raw) > 0 {
-			var details map[string]interface{}
-			if err := json.Unmarshal(cr.Details.Raw, &details); err == nil {
-				// Parse JSON strings back to objects where needed
-				for k, v := range details {
-					if str, ok := v.(string); ok {
-						// Try to parse as JSON if it looks like JSON
-						if (strings.HasPrefix(str, "{") && strings.HasSuffix(str, "}")) ||
-						   (strings.HasPrefix(str, "[") && strings.HasSuffix(str, "]")) {
-							var parsed interface{}
-							if err := json.Unmarshal([]byte(str), &parsed); err == nil {
-								details[k] = parsed
-							}
-						}
-					}
-				}
-				result.Details = details
-			}
+		// Deserialize RawExtension details to map, parsing the nested values stored as JSON strings
+		if details, err := cr.DetailsMap(); err == nil {
+			units.humanize(details)
+			result.Details = details
 		}
 		return result
 	}
@@ -1505,6 +1742,34 @@ func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
 		nodeCheck.Status.CheckResults.SystemResults.SELinuxStatus != nil ||
 		nodeCheck.Status.CheckResults.SystemResults.SSHAccess != nil ||
 		nodeCheck.Status.CheckResults.SystemResults.KernelModules != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.KernelTaint != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.PressureStall != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.Entropy != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.TransparentHugePages != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.HugePages != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.NUMA != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.Conntrack != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.KnownIssues != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.Kdump != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.SysctlDrift != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.Inotify != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.ServiceRestarts != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.CoreDumps != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.TimeDrift != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.Audit != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.CPUVulnerabilities != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.KernelCmdline != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.OSUpdates != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.CgroupDriver != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.ProcessLimits != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.OrphanedMounts != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.UserspaceOOM != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.GPU != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.SRIOV != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.KernelLockups != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.SwapPolicy != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.CertificateExpiry != nil ||
+		nodeCheck.Status.CheckResults.SystemResults.BaselineDrift != nil ||
 		nodeCheck.Status.CheckResults.SystemResults.Hardware != nil ||
 		nodeCheck.Status.CheckResults.SystemResults.Disks != nil ||
 		nodeCheck.Status.CheckResults.SystemResults.Network != nil {
@@ -1530,6 +1795,34 @@ func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
 			SELinuxStatus:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.SELinuxStatus),
 			SSHAccess:           convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.SSHAccess),
 			KernelModules:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.KernelModules),
+			KernelTaint:         convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.KernelTaint),
+			PressureStall:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.PressureStall),
+			Entropy:             convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Entropy),
+			TransparentHugePages: convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.TransparentHugePages),
+			HugePages:           convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.HugePages),
+			NUMA:                convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.NUMA),
+			Conntrack:           convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Conntrack),
+			KnownIssues:         convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.KnownIssues),
+			Kdump:               convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Kdump),
+			SysctlDrift:         convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.SysctlDrift),
+			Inotify:             convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Inotify),
+			ServiceRestarts:     convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.ServiceRestarts),
+			CoreDumps:           convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.CoreDumps),
+			TimeDrift:           convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.TimeDrift),
+			Audit:               convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Audit),
+			CPUVulnerabilities:  convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.CPUVulnerabilities),
+			KernelCmdline:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.KernelCmdline),
+			OSUpdates:           convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.OSUpdates),
+			CgroupDriver:        convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.CgroupDriver),
+			ProcessLimits:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.ProcessLimits),
+			OrphanedMounts:      convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.OrphanedMounts),
+			UserspaceOOM:        convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.UserspaceOOM),
+			GPU:                 convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.GPU),
+			SRIOV:               convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.SRIOV),
+			KernelLockups:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.KernelLockups),
+			SwapPolicy:          convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.SwapPolicy),
+			CertificateExpiry:   convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.CertificateExpiry),
+			BaselineDrift:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.BaselineDrift),
 		}
 		
 		if nodeCheck.Status.CheckResults.SystemResults.Hardware != nil {
@@ -1558,6 +1851,9 @@ func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
 				FilesystemErrors: convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Disks.FilesystemErrors),
 				InodeUsage:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Disks.InodeUsage),
 				MountPoints:      convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Disks.MountPoints),
+				WriteProbe:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Disks.WriteProbe),
+				RuntimeStorage:   convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Disks.RuntimeStorage),
+				WearLevel:        convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Disks.WearLevel),
 			}
 		}
 		
@@ -1572,6 +1868,9 @@ func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
 				DNSResolution: convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Network.DNSResolution),
 				BondingStatus: convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Network.BondingStatus),
 				FirewallRules: convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Network.FirewallRules),
+				Egress:        convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Network.Egress),
+				Ingress:       convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Network.Ingress),
+				ResolvConf:    convertCheckResult(nodeCheck.Status.CheckResults.SystemResults.Network.ResolvConf),
 			}
 		}
 	}
@@ -1586,7 +1885,11 @@ func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
 		nodeCheck.Status.CheckResults.KubernetesResults.ContainerRuntime != nil ||
 		nodeCheck.Status.CheckResults.KubernetesResults.KubeletHealth != nil ||
 		nodeCheck.Status.CheckResults.KubernetesResults.CNIPlugin != nil ||
-		nodeCheck.Status.CheckResults.KubernetesResults.NodeConditions != nil {
+		nodeCheck.Status.CheckResults.KubernetesResults.NodeConditions != nil ||
+		nodeCheck.Status.CheckResults.KubernetesResults.PodScheduling != nil ||
+		nodeCheck.Status.CheckResults.KubernetesResults.PVCProvisioning != nil ||
+		nodeCheck.Status.CheckResults.KubernetesResults.PodNetwork != nil ||
+		nodeCheck.Status.CheckResults.KubernetesResults.LBHealthCheck != nil {
 		kubernetesResultsAPI = &KubernetesCheckResultsAPI{
 			NodeStatus:         convertCheckResult(nodeCheck.Status.CheckResults.KubernetesResults.NodeStatus),
 			Pods:               convertCheckResult(nodeCheck.Status.CheckResults.KubernetesResults.Pods),
@@ -1597,6 +1900,10 @@ func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
 			KubeletHealth:      convertCheckResult(nodeCheck.Status.CheckResults.KubernetesResults.KubeletHealth),
 			CNIPlugin:          convertCheckResult(nodeCheck.Status.CheckResults.KubernetesResults.CNIPlugin),
 			NodeConditions:     convertCheckResult(nodeCheck.Status.CheckResults.KubernetesResults.NodeConditions),
+			PodScheduling:      convertCheckResult(nodeCheck.Status.CheckResults.KubernetesResults.PodScheduling),
+			PVCProvisioning:    convertCheckResult(nodeCheck.Status.CheckResults.KubernetesResults.PVCProvisioning),
+			PodNetwork:         convertCheckResult(nodeCheck.Status.CheckResults.KubernetesResults.PodNetwork),
+			LBHealthCheck:      convertCheckResult(nodeCheck.Status.CheckResults.KubernetesResults.LBHealthCheck),
 		}
 	}
 
@@ -1606,81 +1913,12 @@ func (api *DashboardAPI) GetNodeCheckDetail(c *gin.Context) {
 		KubernetesResults: kubernetesResultsAPI,
 	}
 
-	// Debug: log per verificare che i dati siano presenti
-	if detail.SystemResults != nil {
-		fmt.Printf("DEBUG: SystemResults presente, Uptime: %v, Processes: %v, Memory: %v\n",
-			detail.SystemResults.Uptime != nil,
-			detail.SystemResults.Processes != nil,
-			detail.SystemResults.Memory != nil)
-		if detail.SystemResults.Disks != nil {
-			fmt.Printf("DEBUG: Disks presente, Space: %v, SMART: %v, LVM: %v\n",
-				detail.SystemResults.Disks.Space != nil,
-				detail.SystemResults.Disks.SMART != nil,
-				detail.SystemResults.Disks.LVM != nil)
-		}
-		if detail.SystemResults.Network != nil {
-			fmt.Printf("DEBUG: Network presente, Interfaces: %v\n",
-				detail.SystemResults.Network.Interfaces != nil)
-		}
-		if detail.SystemResults.Hardware != nil {
-			fmt.Printf("DEBUG: Hardware presente, Temperature: %v\n",
-				detail.SystemResults.Hardware.Temperature != nil)
-		}
-	}
-
-	// Debug: serializza in JSON per vedere cosa viene restituito
-	jsonData, err := json.Marshal(detail)
-	if err != nil {
-		fmt.Printf("DEBUG: Errore serializzazione JSON: %v\n", err)
-	} else {
-		// Mostra solo i primi 2000 caratteri per non intasare i log
-		jsonStr := string(jsonData)
-		if len(jsonStr) > 2000 {
-			fmt.Printf("DEBUG: JSON restituito (primi 2000 caratteri): %s...\n", jsonStr[:2000])
-		} else {
-			fmt.Printf("DEBUG: JSON restituito: %s\n", jsonStr)
-		}
-	}
-
-	c.JSON(http.StatusOK, detail)
+	respondSelected(c, http.StatusOK, parseFieldSelection(c), detail)
 }
 
-// GetNodeCheckHistory returns historical data for a NodeCheck
-func (api *DashboardAPI) GetNodeCheckHistory(c *gin.Context) {
-	hours := c.DefaultQuery("hours", "24")
-	
-	hoursInt, err := strconv.Atoi(hours)
-	if err != nil {
-		hoursInt = 24
-	}
-
-	// This would typically query a time-series database
-	// For now, we'll return mock data
-	history := []map[string]interface{}{
-		{
-			"timestamp": time.Now().Add(-time.Duration(hoursInt) * time.Hour),
-			"status":    "Healthy",
-			"uptime":    99.5,
-			"cpu":       45.2,
-			"memory":    67.8,
-		},
-		{
-			"timestamp": time.Now().Add(-time.Duration(hoursInt-1) * time.Hour),
-			"status":    "Healthy",
-			"uptime":    99.2,
-			"cpu":       52.1,
-			"memory":    71.3,
-		},
-		{
-			"timestamp": time.Now().Add(-time.Duration(hoursInt-2) * time.Hour),
-			"status":    "Warning",
-			"uptime":    98.8,
-			"cpu":       78.9,
-			"memory":    85.2,
-		},
-	}
-
-	c.JSON(http.StatusOK, history)
+// GetSelfStatus returns the operator's internal status (cache sync, reconciles, components) for support
+func (api *DashboardAPI) GetSelfStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, selfstatus.GetReport(c.Request.Context()))
 }
 
 // GetNodeInfo returns information about a specific node
@@ -1691,7 +1929,7 @@ func (api *DashboardAPI) GetNodeInfo(c *gin.Context) {
 	
 	node, err := api.clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Node not found"})
+		respondError(c, http.StatusNotFound, msgNodeNotFound, map[string]string{"node": nodeName})
 		return
 	}
 
@@ -1720,7 +1958,7 @@ func (api *DashboardAPI) GetPodsOnNode(c *gin.Context) {
 		FieldSelector: fmt.Sprintf("spec.nodeName=%s", nodeName),
 	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondError(c, http.StatusInternalServerError, msgListPodsFailed, map[string]string{"node": nodeName, "error": err.Error()})
 		return
 	}
 
@@ -1741,27 +1979,42 @@ func (api *DashboardAPI) GetPodsOnNode(c *gin.Context) {
 // SetupRoutes sets up the API routes
 func (api *DashboardAPI) SetupRoutes(r *gin.Engine) {
 	// Main API group with /api/v1 prefix
-	// This is the standard route structure for the dashboard API
-	apiGroup := r.Group("/api/v1")
+	// v1 is kept for compatibility and is read-only: new endpoints go to /api/v2, and only a few
+	// reports are also served here
+	apiGroup := r.Group("/api/v1", deprecatedAPI)
 	{
 		apiGroup.GET("/stats", api.GetDashboardStats)
+		apiGroup.GET("/heatmap", api.GetHeatmap)
+		apiGroup.GET("/compliance", api.GetCompliance)
+		// A run is only returned to a user allowed to read its NodeCheck, so it needs the token of /api/v2
+		apiGroup.GET("/runs/:id", api.authenticateUser, api.GetRun)
 		apiGroup.GET("/nodechecks", api.GetNodeChecks)
 		apiGroup.GET("/nodechecks/:name", api.GetNodeCheckDetail)
 		apiGroup.GET("/nodechecks/:name/history", api.GetNodeCheckHistory)
 		apiGroup.GET("/nodes/:nodeName", api.GetNodeInfo)
 		apiGroup.GET("/nodes/:nodeName/pods", api.GetPodsOnNode)
+		apiGroup.GET("/selfstatus", api.GetSelfStatus)
+		apiGroup.GET("/uiconfig", api.GetUIConfig)
 	}
 	
 	// Fallback routes without /api/v1/ prefix
 	// These handle cases where the proxy might strip the prefix
 	// (though with correct plugin configuration, this shouldn't be needed)
-	fallbackGroup := r.Group("")
+	fallbackGroup := r.Group("", deprecatedAPI)
 	{
 		fallbackGroup.GET("/stats", api.GetDashboardStats)
+		fallbackGroup.GET("/heatmap", api.GetHeatmap)
+		fallbackGroup.GET("/compliance", api.GetCompliance)
+		fallbackGroup.GET("/runs/:id", api.authenticateUser, api.GetRun)
 		fallbackGroup.GET("/nodechecks", api.GetNodeChecks)
 		fallbackGroup.GET("/nodechecks/:name", api.GetNodeCheckDetail)
 		fallbackGroup.GET("/nodechecks/:name/history", api.GetNodeCheckHistory)
 		fallbackGroup.GET("/nodes/:nodeName", api.GetNodeInfo)
 		fallbackGroup.GET("/nodes/:nodeName/pods", api.GetPodsOnNode)
+		fallbackGroup.GET("/selfstatus", api.GetSelfStatus)
+		fallbackGroup.GET("/uiconfig", api.GetUIConfig)
 	}
+
+	// Paginated, authenticated API
+	api.setupV2Routes(r)
 }
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/maintenance"
+	"github.com/albertofilice/node-check-operator/pkg/nodehealth"
+	"github.com/gin-gonic/gin"
+)
+
+// Heatmap status codes, ordered by severity so that the worst result of a node wins when several
+// NodeChecks run the same check on it
+const (
+	heatmapNotRun = iota
+	heatmapHealthy
+	heatmapSuppressed
+	heatmapUnknown
+	heatmapWarning
+	heatmapCritical
+)
+
+// heatmapLegend is the status of each heatmap code, indexed by code
+var heatmapLegend = []string{"NotRun", "Healthy", maintenance.StatusSuppressed, "Unknown", "Warning", "Critical"}
+
+// HeatmapData is the fleet heatmap returned by /api/v1/heatmap: Matrix[i][j] is the status code
+// of check Checks[j] on node Nodes[i], and Legend[code] the status of a code
+type HeatmapData struct {
+	Nodes      []string  `json:"nodes"`
+	Checks     []string  `json:"checks"`
+	Legend     []string  `json:"legend"`
+	Matrix     [][]int   `json:"matrix"`
+	LastUpdate time.Time `json:"lastUpdate"`
+}
+
+// heatmapCode returns the heatmap code of a check status
+func heatmapCode(status string) int {
+	switch status {
+	case "Healthy":
+		return heatmapHealthy
+	case maintenance.StatusSuppressed:
+		return heatmapSuppressed
+	case "Warning":
+		return heatmapWarning
+	case "Critical":
+		return heatmapCritical
+	default:
+		return heatmapUnknown
+	}
+}
+
+// buildHeatmap builds the nodes × checks matrix of the NodeChecks. Checks are named by the path of
+// their result in status.checkResults (e.g. "systemResults.disks.smart"); nodes and checks are sorted.
+func buildHeatmap(nodeChecks []v1alpha1.NodeCheck) HeatmapData {
+	codes := map[string]map[string]int{}
+	checkSet := map[string]bool{}
+	for _, nc := range nodeChecks {
+		nodeName := nc.Status.NodeName
+		if nodeName == "" {
+			nodeName = nc.Spec.NodeName
+		}
+		if nodeName == "" || nodeName == "*" || nodeName == "all" {
+			continue
+		}
+		if codes[nodeName] == nil {
+			codes[nodeName] = map[string]int{}
+		}
+		for check, status := range nodehealth.CollectCheckStatuses(nc.Status.CheckResults) {
+			checkSet[check] = true
+			if code := heatmapCode(status); code > codes[nodeName][check] {
+				codes[nodeName][check] = code
+			}
+		}
+	}
+
+	heatmap := HeatmapData{
+		Nodes:      make([]string, 0, len(codes)),
+		Checks:     make([]string, 0, len(checkSet)),
+		Legend:     heatmapLegend,
+		LastUpdate: time.Now(),
+	}
+	for node := range codes {
+		heatmap.Nodes = append(heatmap.Nodes, node)
+	}
+	for check := range checkSet {
+		heatmap.Checks = append(heatmap.Checks, check)
+	}
+	sort.Strings(heatmap.Nodes)
+	sort.Strings(heatmap.Checks)
+
+	heatmap.Matrix = make([][]int, len(heatmap.Nodes))
+	for i, node := range heatmap.Nodes {
+		row := make([]int, len(heatmap.Checks))
+		for j, check := range heatmap.Checks {
+			row[j] = codes[node][check]
+		}
+		heatmap.Matrix[i] = row
+	}
+	return heatmap
+}
+
+// GetHeatmap returns the status of every check on every node as a compact matrix, so the console
+// plugin can render a fleet heatmap without loading the details of each NodeCheck.
+// The optional namespace parameter restricts it to the NodeChecks of a namespace.
+func (api *DashboardAPI) GetHeatmap(c *gin.Context) {
+	ctx := context.Background()
+
+	var nodeChecks v1alpha1.NodeCheckList
+	if err := api.k8sClient.List(ctx, &nodeChecks); err != nil {
+		respondError(c, http.StatusInternalServerError, msgListNodeChecksFailed, map[string]string{"error": err.Error()})
+		return
+	}
+
+	items := nodeChecks.Items
+	if namespace := c.Query("namespace"); namespace != "" {
+		items = make([]v1alpha1.NodeCheck, 0, len(nodeChecks.Items))
+		for _, nc := range nodeChecks.Items {
+			if nc.Namespace == namespace {
+				items = append(items, nc)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, buildHeatmap(items))
+}
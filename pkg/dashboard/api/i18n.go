@@ -0,0 +1,235 @@
+package api
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultLanguage is used when the client accepts none of the languages of messageCatalogs
+const defaultLanguage = "en"
+
+// Message keys of API errors. They are returned as "code" with their "params", so clients can
+// render the message in their own language instead of relying on the "error" text.
+const (
+	msgListNodeChecksFailed = "listNodeChecksFailed"
+	msgNodeCheckNotFound    = "nodeCheckNotFound"
+	msgNodeNotFound         = "nodeNotFound"
+	msgRunNotFound          = "runNotFound"
+	msgListPodsFailed       = "listPodsFailed"
+	msgGroupNodesFailed     = "groupNodesFailed"
+
+	msgInvalidCheckUpdate           = "invalidCheckUpdate"
+	msgUnknownCheck                 = "unknownCheck"
+	msgThresholdsNotSupported       = "thresholdsNotSupported"
+	msgInvalidThresholds            = "invalidThresholds"
+	msgUserNotAuthenticated         = "userNotAuthenticated"
+	msgUserAuthenticationFailed     = "userAuthenticationFailed"
+	msgNodeCheckGeneratedByTemplate = "nodeCheckGeneratedByTemplate"
+	msgNodeCheckManagedByParent     = "nodeCheckManagedByParent"
+	msgNodeCheckUpdateForbidden     = "nodeCheckUpdateForbidden"
+	msgNodeCheckUpdateConflict      = "nodeCheckUpdateConflict"
+	msgNodeCheckUpdateFailed        = "nodeCheckUpdateFailed"
+
+	msgAuthenticationRequired     = "authenticationRequired"
+	msgAuthenticationFailed       = "authenticationFailed"
+	msgNodeChecksListForbidden    = "nodeChecksListForbidden"
+	msgAllNodeChecksListForbidden = "allNodeChecksListForbidden"
+	msgNodeCheckReadForbidden     = "nodeCheckReadForbidden"
+	msgInvalidLimit               = "invalidLimit"
+	msgInvalidContinueToken       = "invalidContinueToken"
+
+	msgChatOpsNotConfigured = "chatOpsNotConfigured"
+	msgChatOpsUnauthorized  = "chatOpsUnauthorized"
+	msgChatOpsBadRequest    = "chatOpsBadRequest"
+
+	msgFaultInjectionDisabled = "faultInjectionDisabled"
+	msgInvalidFault           = "invalidFault"
+	msgFaultNeedsNode         = "faultNeedsNode"
+	msgInvalidFaults          = "invalidFaults"
+
+	msgDrainReportFailed = "drainReportFailed"
+
+	msgInvalidVerification   = "invalidVerification"
+	msgVerificationNeedsNode = "verificationNeedsNode"
+	msgUncordonForbidden     = "uncordonForbidden"
+
+	msgTriggerPaused = "triggerPaused"
+
+	msgInvalidFalsePositive    = "invalidFalsePositive"
+	msgFalsePositiveNoResult   = "falsePositiveNoResult"
+	msgFalsePositiveNotFinding = "falsePositiveNotFinding"
+	msgFeedbackFailed          = "feedbackFailed"
+)
+
+// messageCatalogs holds the API messages per language; {param} placeholders are replaced by the params
+var messageCatalogs = map[string]map[string]string{
+	"en": {
+		msgListNodeChecksFailed: "Unable to list NodeChecks: {error}",
+		msgNodeCheckNotFound:    "NodeCheck {namespace}/{name} not found",
+		msgNodeNotFound:         "Node {node} not found",
+		msgRunNotFound:          "Run {id} not found, it may be older than the runs kept in status.runs",
+		msgListPodsFailed:       "Unable to list the pods of node {node}: {error}",
+		msgGroupNodesFailed:     "Unable to group the nodes by {groupBy}: {error}",
+
+		msgInvalidCheckUpdate:           "The request must set \"enabled\" and/or \"thresholds\"",
+		msgUnknownCheck:                 "Unknown check {check}",
+		msgThresholdsNotSupported:       "Check {check} has no configurable thresholds",
+		msgInvalidThresholds:            "Thresholds must be between 0 and 100, with warning lower than critical",
+		msgUserNotAuthenticated:         "A valid user token is required to change NodeCheck {namespace}/{name}",
+		msgUserAuthenticationFailed:     "Unable to authenticate the user, NodeCheck {namespace}/{name} was not changed",
+		msgNodeCheckGeneratedByTemplate: "NodeCheck {namespace}/{name} is generated by NodeCheckTemplate {owner}, change the template instead",
+		msgNodeCheckManagedByParent:     "NodeCheck {namespace}/{name} is managed by NodeCheck {owner}, change it instead",
+		msgNodeCheckUpdateForbidden:     "You are not allowed to change NodeCheck {namespace}/{name}",
+		msgNodeCheckUpdateConflict:      "NodeCheck {namespace}/{name} was changed meanwhile, reload and try again",
+		msgNodeCheckUpdateFailed:        "Unable to update NodeCheck {namespace}/{name}: {error}",
+
+		msgAuthenticationRequired:     "A valid user token is required",
+		msgAuthenticationFailed:       "Unable to authenticate the user",
+		msgNodeChecksListForbidden:    "You are not allowed to list NodeChecks in namespace {namespace}",
+		msgAllNodeChecksListForbidden: "You are not allowed to list NodeChecks in all namespaces",
+		msgNodeCheckReadForbidden:     "You are not allowed to read NodeCheck {namespace}/{name}",
+		msgInvalidLimit:               "The limit must be a number between 1 and {max}",
+		msgInvalidContinueToken:       "The continue token is not valid",
+
+		msgChatOpsNotConfigured: "ChatOps is not configured: create the Secret {secret} with the signingSecret key",
+		msgChatOpsUnauthorized:  "The request signature is missing, invalid or expired",
+		msgChatOpsBadRequest:    "The request is not a valid slash command",
+
+		msgFaultInjectionDisabled: "Fault injection is disabled, enable the faultInjection feature gate",
+		msgInvalidFault:           "The fault must set \"check\" and a \"status\" of Warning or Critical, with a duration of at most {maxMinutes} minutes",
+		msgFaultNeedsNode:         "NodeCheck {namespace}/{name} does not run on a single node, inject the fault in the NodeCheck of the node",
+		msgInvalidFaults:          "The faults of NodeCheck {namespace}/{name} are not valid: {error}",
+
+		msgDrainReportFailed: "Unable to build the drain report of node {node}: {error}",
+
+		msgInvalidVerification:   "The verification request must be a JSON object with an optional \"autoUncordon\" boolean",
+		msgVerificationNeedsNode: "NodeCheck {namespace}/{name} does not run on a single node, verify the NodeCheck of the node",
+		msgUncordonForbidden:     "You are not allowed to uncordon node {node}, request the verification without autoUncordon",
+
+		msgTriggerPaused: "NodeCheck {namespace}/{name} is paused, resume it before triggering a run",
+
+		msgInvalidFalsePositive:    "The feedback must set \"check\", with a \"comment\" of at most {maxComment} characters",
+		msgFalsePositiveNoResult:   "NodeCheck {namespace}/{name} has no result of check {check}",
+		msgFalsePositiveNotFinding: "Check {check} of NodeCheck {namespace}/{name} is {status}, only Warning and Critical results can be marked as false positives",
+		msgFeedbackFailed:          "Unable to store the feedback: {error}",
+	},
+	"it": {
+		msgListNodeChecksFailed: "Impossibile elencare i NodeCheck: {error}",
+		msgNodeCheckNotFound:    "NodeCheck {namespace}/{name} non trovato",
+		msgNodeNotFound:         "Nodo {node} non trovato",
+		msgRunNotFound:          "Esecuzione {id} non trovata, potrebbe essere più vecchia di quelle conservate in status.runs",
+		msgListPodsFailed:       "Impossibile elencare i pod del nodo {node}: {error}",
+		msgGroupNodesFailed:     "Impossibile raggruppare i nodi per {groupBy}: {error}",
+
+		msgInvalidCheckUpdate:           "La richiesta deve impostare \"enabled\" e/o \"thresholds\"",
+		msgUnknownCheck:                 "Check {check} sconosciuto",
+		msgThresholdsNotSupported:       "Il check {check} non ha soglie configurabili",
+		msgInvalidThresholds:            "Le soglie devono essere tra 0 e 100, con warning inferiore a critical",
+		msgUserNotAuthenticated:         "Serve un token utente valido per modificare il NodeCheck {namespace}/{name}",
+		msgUserAuthenticationFailed:     "Impossibile autenticare l'utente, il NodeCheck {namespace}/{name} non è stato modificato",
+		msgNodeCheckGeneratedByTemplate: "Il NodeCheck {namespace}/{name} è generato dal NodeCheckTemplate {owner}, modifica il template",
+		msgNodeCheckManagedByParent:     "Il NodeCheck {namespace}/{name} è gestito dal NodeCheck {owner}, modifica quello",
+		msgNodeCheckUpdateForbidden:     "Non hai i permessi per modificare il NodeCheck {namespace}/{name}",
+		msgNodeCheckUpdateConflict:      "Il NodeCheck {namespace}/{name} è stato modificato nel frattempo, ricarica e riprova",
+		msgNodeCheckUpdateFailed:        "Impossibile aggiornare il NodeCheck {namespace}/{name}: {error}",
+
+		msgAuthenticationRequired:     "Serve un token utente valido",
+		msgAuthenticationFailed:       "Impossibile autenticare l'utente",
+		msgNodeChecksListForbidden:    "Non hai i permessi per elencare i NodeCheck del namespace {namespace}",
+		msgAllNodeChecksListForbidden: "Non hai i permessi per elencare i NodeCheck di tutti i namespace",
+		msgNodeCheckReadForbidden:     "Non hai i permessi per leggere il NodeCheck {namespace}/{name}",
+		msgInvalidLimit:               "Il limite deve essere un numero tra 1 e {max}",
+		msgInvalidContinueToken:       "Il token di continuazione non è valido",
+
+		msgChatOpsNotConfigured: "ChatOps non è configurato: crea il Secret {secret} con la chiave signingSecret",
+		msgChatOpsUnauthorized:  "La firma della richiesta è assente, non valida o scaduta",
+		msgChatOpsBadRequest:    "La richiesta non è uno slash command valido",
+
+		msgFaultInjectionDisabled: "L'iniezione di guasti è disabilitata, abilita il feature gate faultInjection",
+		msgInvalidFault:           "Il guasto deve impostare \"check\" e uno \"status\" Warning o Critical, con una durata massima di {maxMinutes} minuti",
+		msgFaultNeedsNode:         "Il NodeCheck {namespace}/{name} non gira su un singolo nodo, inietta il guasto nel NodeCheck del nodo",
+		msgInvalidFaults:          "I guasti del NodeCheck {namespace}/{name} non sono validi: {error}",
+
+		msgDrainReportFailed: "Impossibile generare il report di drain del nodo {node}: {error}",
+
+		msgInvalidVerification:   "La richiesta di verifica deve essere un oggetto JSON con un booleano \"autoUncordon\" opzionale",
+		msgVerificationNeedsNode: "Il NodeCheck {namespace}/{name} non gira su un singolo nodo, verifica il NodeCheck del nodo",
+		msgUncordonForbidden:     "Non hai i permessi per rimettere in servizio il nodo {node}, richiedi la verifica senza autoUncordon",
+
+		msgTriggerPaused: "Il NodeCheck {namespace}/{name} è in pausa, riprendilo prima di avviare un'esecuzione",
+
+		msgInvalidFalsePositive:    "Il feedback deve impostare \"check\", con un \"comment\" di al massimo {maxComment} caratteri",
+		msgFalsePositiveNoResult:   "Il NodeCheck {namespace}/{name} non ha risultati del check {check}",
+		msgFalsePositiveNotFinding: "Il check {check} del NodeCheck {namespace}/{name} è {status}, solo i risultati Warning e Critical possono essere segnalati come falsi positivi",
+		msgFeedbackFailed:          "Impossibile salvare il feedback: {error}",
+	},
+}
+
+// negotiateLanguage returns the catalog language that best matches an Accept-Language header
+// (e.g. "it-IT,it;q=0.9,en;q=0.8"), honoring the q-values and falling back to the base language
+func negotiateLanguage(acceptLanguage string) string {
+	type candidate struct {
+		language string
+		quality  float64
+	}
+	candidates := []candidate{}
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		language := strings.ToLower(strings.TrimSpace(fields[0]))
+		if language == "" {
+			continue
+		}
+		quality := 1.0
+		for _, field := range fields[1:] {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(field), "q="); ok {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		candidates = append(candidates, candidate{language, quality})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+
+	for _, c := range candidates {
+		if c.quality <= 0 {
+			continue
+		}
+		base, _, _ := strings.Cut(c.language, "-")
+		if _, ok := messageCatalogs[base]; ok {
+			return base
+		}
+	}
+	return defaultLanguage
+}
+
+// localize renders a message of the catalog of language, falling back to the default language
+func localize(language, key string, params map[string]string) string {
+	template, ok := messageCatalogs[language][key]
+	if !ok {
+		template, ok = messageCatalogs[defaultLanguage][key]
+	}
+	if !ok {
+		return key
+	}
+	replacements := make([]string, 0, 2*len(params))
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+// respondError writes an API error localized according to the request's Accept-Language header.
+// "error" holds the localized text, "code" and "params" let clients localize it themselves.
+func respondError(c *gin.Context, status int, key string, params map[string]string) {
+	language := negotiateLanguage(c.GetHeader("Accept-Language"))
+	c.Header("Content-Language", language)
+	c.JSON(status, gin.H{
+		"error":  localize(language, key, params),
+		"code":   key,
+		"params": params,
+	})
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	authenticationv1 "k8s.io/api/authentication/v1"
+)
+
+// RunSnapshot is a run of the executor of /api/v2/runs/:id with its results. Checks only holds the results
+// produced by the run, never the results other runs stored meanwhile: the checks of the run that ran again
+// since are counted in Superseded. Complete is true when the run completed and none of its results was
+// superseded, i.e. Checks is the whole run. Incidents correlate the failing checks of the run only.
+type RunSnapshot struct {
+	ID             string          `json:"id"`
+	Namespace      string          `json:"namespace"`
+	NodeCheck      string          `json:"nodeCheck"`
+	Node           string          `json:"node"`
+	Categories     []string        `json:"categories,omitempty"`
+	StartTime      time.Time       `json:"startTime"`
+	CompletionTime *time.Time      `json:"completionTime,omitempty"`
+	InProgress     bool            `json:"inProgress"`
+	Complete       bool            `json:"complete"`
+	Superseded     int             `json:"superseded"`
+	Checks         []CheckResultV2 `json:"checks"`
+	Incidents      []Incident      `json:"incidents"`
+}
+
+// findRun returns the NodeCheck that recorded a run in status.runs, with the record of the run
+func findRun(nodeChecks []v1alpha1.NodeCheck, id string) (*v1alpha1.NodeCheck, *v1alpha1.RunRecord) {
+	for i := range nodeChecks {
+		for j := range nodeChecks[i].Status.Runs {
+			if nodeChecks[i].Status.Runs[j].ID == id {
+				return &nodeChecks[i], &nodeChecks[i].Status.Runs[j]
+			}
+		}
+	}
+	return nil, nil
+}
+
+// buildRunSnapshot returns the results of a run still in the status of its NodeCheck
+func buildRunSnapshot(nodeCheck *v1alpha1.NodeCheck, run *v1alpha1.RunRecord) RunSnapshot {
+	snapshot := RunSnapshot{
+		ID:         run.ID,
+		Namespace:  nodeCheck.Namespace,
+		NodeCheck:  nodeCheck.Name,
+		Node:       nodeCheck.Status.NodeName,
+		Categories: run.Categories,
+		StartTime:  run.StartTime.Time,
+		InProgress: run.CompletionTime == nil,
+		Checks:     []CheckResultV2{},
+	}
+	if run.CompletionTime != nil {
+		completion := run.CompletionTime.Time
+		snapshot.CompletionTime = &completion
+	}
+	for _, check := range flattenCheckResults(nodeCheck.Status.CheckResults) {
+		if check.RunID == run.ID {
+			snapshot.Checks = append(snapshot.Checks, check)
+		}
+	}
+	if superseded := len(run.Checks) - len(snapshot.Checks); superseded > 0 {
+		snapshot.Superseded = superseded
+	}
+	snapshot.Complete = !snapshot.InProgress && snapshot.Superseded == 0
+	snapshot.Incidents = correlateChecks(snapshot.Checks)
+	return snapshot
+}
+
+// GetRun returns the results of a run of the executor by the runID of its results, so the results of a
+// single run can be looked at together when troubleshooting. The run is looked up in status.runs of all
+// the NodeChecks, then the user must be allowed to get its NodeCheck; runs of NodeChecks the user cannot
+// read are not found.
+func (api *DashboardAPI) GetRun(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var nodeChecks v1alpha1.NodeCheckList
+	if err := api.k8sClient.List(ctx, &nodeChecks); err != nil {
+		respondError(c, http.StatusInternalServerError, msgListNodeChecksFailed, map[string]string{"error": err.Error()})
+		return
+	}
+	nodeCheck, run := findRun(nodeChecks.Items, id)
+	if nodeCheck == nil {
+		respondError(c, http.StatusNotFound, msgRunNotFound, map[string]string{"id": id})
+		return
+	}
+
+	value, _ := c.Get(userContextKey)
+	user, _ := value.(*authenticationv1.UserInfo)
+	if user == nil {
+		respondError(c, http.StatusUnauthorized, msgAuthenticationRequired, nil)
+		return
+	}
+	allowed, err := api.userCan(ctx, user, "get", nodeCheck.Namespace, nodeCheck.Name)
+	if err != nil {
+		fmt.Printf("Unable to authorize user %s to get NodeCheck %s/%s: %v\n", user.Username, nodeCheck.Namespace, nodeCheck.Name, err)
+		respondError(c, http.StatusServiceUnavailable, msgAuthenticationFailed, nil)
+		return
+	}
+	if !allowed {
+		// A user not allowed to read the NodeCheck does not learn that the run exists
+		respondError(c, http.StatusNotFound, msgRunNotFound, map[string]string{"id": id})
+		return
+	}
+
+	units := parseUnits(c)
+	snapshot := buildRunSnapshot(nodeCheck, run)
+	for _, check := range snapshot.Checks {
+		units.humanize(check.Details)
+	}
+	c.JSON(http.StatusOK, snapshot)
+}
@@ -0,0 +1,170 @@
+package api
+
+import (
+	"sync"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/metrics"
+)
+
+// summaryCache keeps the summary and the node metrics of each NodeCheck, keyed by its resourceVersion, so
+// the list endpoints do not walk the results and unmarshal the details of the unchanged NodeChecks on
+// every request. An entry is rebuilt when the resourceVersion of its NodeCheck changes.
+type summaryCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedNodeCheck
+}
+
+// cachedNodeCheck is what is derived from one resourceVersion of a NodeCheck
+type cachedNodeCheck struct {
+	resourceVersion string
+	summary         NodeCheckSummary
+	nodeMetrics     metrics.NodeMetricsSnapshot
+}
+
+// newSummaryCache returns an empty summaryCache
+func newSummaryCache() *summaryCache {
+	return &summaryCache{entries: make(map[string]*cachedNodeCheck)}
+}
+
+// get returns the cache entry of the current resourceVersion of a NodeCheck, building it if needed
+func (s *summaryCache) get(nc *v1alpha1.NodeCheck) *cachedNodeCheck {
+	key := nc.Namespace + "/" + nc.Name
+	s.mu.Lock()
+	entry := s.entries[key]
+	s.mu.Unlock()
+	if entry != nil && entry.resourceVersion == nc.ResourceVersion && nc.ResourceVersion != "" {
+		return entry
+	}
+
+	entry = &cachedNodeCheck{
+		resourceVersion: nc.ResourceVersion,
+		summary:         summarizeNodeCheck(*nc),
+		nodeMetrics:     extractNodeMetrics(nc),
+	}
+	s.mu.Lock()
+	s.entries[key] = entry
+	s.mu.Unlock()
+	return entry
+}
+
+// summary returns the summary of a NodeCheck
+func (s *summaryCache) summary(nc *v1alpha1.NodeCheck) NodeCheckSummary {
+	return s.get(nc).summary
+}
+
+// nodeMetrics returns the node metrics read from the details of the results of a NodeCheck
+func (s *summaryCache) nodeMetrics(nc *v1alpha1.NodeCheck) metrics.NodeMetricsSnapshot {
+	return s.get(nc).nodeMetrics
+}
+
+// prune drops the entries of the NodeChecks that are not in a complete list anymore
+func (s *summaryCache) prune(nodeChecks []v1alpha1.NodeCheck) {
+	current := make(map[string]bool, len(nodeChecks))
+	for _, nc := range nodeChecks {
+		current[nc.Namespace+"/"+nc.Name] = true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.entries {
+		if !current[key] {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// extractNodeMetrics reads the node-level metrics (temperature, CPU, memory, load) from the details of the
+// results of a NodeCheck. The metrics without a result are left nil.
+func extractNodeMetrics(nc *v1alpha1.NodeCheck) metrics.NodeMetricsSnapshot {
+	nodeMetrics := metrics.NodeMetricsSnapshot{NodeName: nc.Spec.NodeName}
+	systemResults := nc.Status.CheckResults.SystemResults
+
+	// details returns the deserialized details of a result, nil if it has none
+	details := func(cr *v1alpha1.CheckResult) map[string]interface{} {
+		if cr == nil {
+			return nil
+		}
+		details, err := cr.DetailsMap()
+		if err != nil {
+			return nil
+		}
+		return details
+	}
+
+	// Extract temperature (average from all sensors)
+	if systemResults.Hardware != nil {
+		if details := details(systemResults.Hardware.Temperature); details != nil {
+			if temps, ok := details["temperatures"].(map[string]interface{}); ok {
+				sumTemp := 0.0
+				count := 0
+				for _, tempVal := range temps {
+					if temp, ok := tempVal.(float64); ok && temp > 0 {
+						sumTemp += temp
+						count++
+					}
+				}
+				if count > 0 {
+					avgTemp := sumTemp / float64(count)
+					nodeMetrics.Temperature = &avgTemp
+				}
+			}
+		}
+	}
+
+	// Extract CPU usage
+	if details := details(systemResults.Resources); details != nil {
+		var cpuValue float64
+		if cpu, ok := details["cpu_usage"].(float64); ok {
+			cpuValue = cpu
+		} else if cpu, ok := details["cpuUsage"].(float64); ok {
+			cpuValue = cpu
+		} else if cpu, ok := details["cpu"].(float64); ok {
+			cpuValue = cpu
+		} else if cpuIdle, ok := details["cpu_idle_percent"].(float64); ok {
+			cpuValue = 100 - cpuIdle
+		} else if cpuUser, ok := details["cpu_user_percent"].(float64); ok {
+			cpuSys := 0.0
+			if cpuSysVal, ok := details["cpu_system_percent"].(float64); ok {
+				cpuSys = cpuSysVal
+			}
+			cpuValue = cpuUser + cpuSys
+		}
+		if cpuValue > 0 {
+			nodeMetrics.CPUUsage = &cpuValue
+		}
+	}
+
+	// Extract memory usage
+	if details := details(systemResults.Memory); details != nil {
+		var memValue float64
+		if mem, ok := details["memory_usage_percent"].(float64); ok {
+			memValue = mem
+		} else if mem, ok := details["memoryUsage"].(float64); ok {
+			memValue = mem
+		} else if mem, ok := details["used_percent"].(float64); ok {
+			memValue = mem
+		} else if usedKB, ok := details["memory_used_kb"].(float64); ok {
+			if totalKB, ok := details["memory_total_kb"].(float64); ok && totalKB > 0 {
+				memValue = (usedKB / totalKB) * 100
+			}
+		}
+		if memValue > 0 {
+			nodeMetrics.MemoryUsage = &memValue
+		}
+	}
+
+	// Extract load averages (the uptime string is not parsed)
+	if details := details(systemResults.Uptime); details != nil {
+		if load1m, ok := details["load_1min"].(float64); ok {
+			nodeMetrics.LoadAverage1m = &load1m
+		}
+		if load5m, ok := details["load_5min"].(float64); ok {
+			nodeMetrics.LoadAverage5m = &load5m
+		}
+		if load15m, ok := details["load_15min"].(float64); ok {
+			nodeMetrics.LoadAverage15m = &load15m
+		}
+	}
+
+	return nodeMetrics
+}
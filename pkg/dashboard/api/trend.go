@@ -0,0 +1,119 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/history"
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// defaultTrendHours is the window of the trend endpoints when the request sets no ?hours=
+const defaultTrendHours = 24
+
+// NodeCheckTrend is the status history of the checks of a NodeCheck within a window, with the
+// lifecycle events of the node (provisioning, reboots, kubelet upgrades, MachineConfig updates) as
+// markers so that status changes can be correlated with maintenance. Source is the history backend
+// the entries come from (Status when read from status.history).
+type NodeCheckTrend struct {
+	Name      string                    `json:"name"`
+	Namespace string                    `json:"namespace"`
+	NodeName  string                    `json:"nodeName"`
+	Since     time.Time                 `json:"since"`
+	Source    string                    `json:"source"`
+	Checks    []CheckTrend              `json:"checks"`
+	Markers   []v1alpha1.LifecycleEvent `json:"markers"`
+}
+
+// CheckTrend is the status history of a check within the window of a NodeCheckTrend
+type CheckTrend struct {
+	Name        string                       `json:"name"`
+	Transitions int                          `json:"transitions"`
+	Entries     []v1alpha1.CheckHistoryEntry `json:"entries"`
+}
+
+// trendSince returns the start of the window of ?hours= (24 hours when unset or invalid)
+func trendSince(c *gin.Context) time.Time {
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", strconv.Itoa(defaultTrendHours)))
+	if err != nil || hours <= 0 {
+		hours = defaultTrendHours
+	}
+	return time.Now().Add(-time.Duration(hours) * time.Hour)
+}
+
+// buildTrend returns the history entries (oldest first) and the lifecycle markers of a NodeCheck since a time
+func buildTrend(nodeCheck v1alpha1.NodeCheck, since time.Time, source string, entries []history.Entry) NodeCheckTrend {
+	trend := NodeCheckTrend{
+		Name:      nodeCheck.Name,
+		Namespace: nodeCheck.Namespace,
+		NodeName:  nodeCheck.Spec.NodeName,
+		Since:     since,
+		Source:    source,
+		Checks:    []CheckTrend{},
+		Markers:   []v1alpha1.LifecycleEvent{},
+	}
+	checks := make(map[string]int)
+	for _, entry := range entries {
+		if entry.Timestamp.Before(since) {
+			continue
+		}
+		index, ok := checks[entry.Check]
+		if !ok {
+			index = len(trend.Checks)
+			checks[entry.Check] = index
+			trend.Checks = append(trend.Checks, CheckTrend{Name: entry.Check, Entries: []v1alpha1.CheckHistoryEntry{}})
+		}
+		check := &trend.Checks[index]
+		if n := len(check.Entries); n > 0 && check.Entries[n-1].Status != entry.Status {
+			check.Transitions++
+		}
+		check.Entries = append(check.Entries, v1alpha1.CheckHistoryEntry{Timestamp: metav1.NewTime(entry.Timestamp), Status: entry.Status})
+	}
+	if nodeCheck.Status.Lifecycle != nil {
+		for _, event := range nodeCheck.Status.Lifecycle.Events {
+			if !event.Timestamp.Time.Before(since) {
+				trend.Markers = append(trend.Markers, event)
+			}
+		}
+	}
+	return trend
+}
+
+// GetNodeCheckHistory returns the check history of a NodeCheck over the last ?hours= (24 by default),
+// with the lifecycle events of the node as markers. The history comes from the store of spec.history
+// of the NodeCheckOperatorConfig when one is configured, from status.history otherwise.
+func (api *DashboardAPI) GetNodeCheckHistory(c *gin.Context) {
+	name := c.Param("name")
+	namespace := c.DefaultQuery("namespace", "node-check-operator-system")
+	api.respondTrend(c, namespace, name)
+}
+
+// GetNodeCheckHistoryV2 is GetNodeCheckHistory for /api/v2, with the namespace in the path
+func (api *DashboardAPI) GetNodeCheckHistoryV2(c *gin.Context) {
+	api.respondTrend(c, c.Param("namespace"), c.Param("name"))
+}
+
+// respondTrend responds with the trend of a NodeCheck
+func (api *DashboardAPI) respondTrend(c *gin.Context, namespace, name string) {
+	var nodeCheck v1alpha1.NodeCheck
+	if err := api.k8sClient.Get(c.Request.Context(), client.ObjectKey{Name: name, Namespace: namespace}, &nodeCheck); err != nil {
+		respondError(c, http.StatusNotFound, msgNodeCheckNotFound, map[string]string{"namespace": namespace, "name": name})
+		return
+	}
+	since := trendSince(c)
+	source, entries := history.BackendStatus, history.StatusEntries(nodeCheck.Status.History)
+	if store := history.Current(); store != nil {
+		stored, err := store.Query(c.Request.Context(), history.Key{Namespace: namespace, Name: name}, since)
+		if err != nil {
+			fmt.Printf("Unable to query the %s history store, using status.history: %v\n", store.Backend(), err)
+		} else {
+			source, entries = store.Backend(), stored
+		}
+	}
+	c.JSON(http.StatusOK, buildTrend(nodeCheck, since, source, entries))
+}
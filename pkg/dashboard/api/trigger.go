@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/trigger"
+	"github.com/gin-gonic/gin"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// TriggerRun is the trigger webhook of the externally scheduled NodeChecks (spec.scheduling External),
+// for CronJobs and KEDA ScaledJobs: it sets a new value of the trigger annotation as the calling user,
+// like RequestVerification, and the executors run every enabled check category once. On a "*" NodeCheck
+// the trigger is copied to the NodeCheck of every node. It also runs an Interval NodeCheck right away.
+func (api *DashboardAPI) TriggerRun(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkUpdateTimeout)
+	defer cancel()
+
+	params := map[string]string{"namespace": c.Param("namespace"), "name": c.Param("name")}
+	value, _ := c.Get(userContextKey)
+	user, _ := value.(*authenticationv1.UserInfo)
+	if user == nil || api.restConfig == nil {
+		respondError(c, http.StatusUnauthorized, msgUserNotAuthenticated, params)
+		return
+	}
+	userClient, err := api.clientAs(user)
+	if err != nil {
+		fmt.Printf("Unable to impersonate the user triggering NodeCheck %s/%s: %v\n", params["namespace"], params["name"], err)
+		respondError(c, http.StatusServiceUnavailable, msgUserAuthenticationFailed, params)
+		return
+	}
+
+	var nodeCheck v1alpha1.NodeCheck
+	if err := userClient.Get(ctx, client.ObjectKey{Name: params["name"], Namespace: params["namespace"]}, &nodeCheck); err != nil {
+		api.respondUpdateError(c, err, params)
+		return
+	}
+	if nodeCheck.Spec.Paused {
+		respondError(c, http.StatusConflict, msgTriggerPaused, params)
+		return
+	}
+
+	patch := client.MergeFrom(nodeCheck.DeepCopy())
+	if nodeCheck.Annotations == nil {
+		nodeCheck.Annotations = make(map[string]string)
+	}
+	nodeCheck.Annotations[trigger.Annotation] = trigger.NewValue(time.Now())
+	if err := userClient.Patch(ctx, &nodeCheck, patch); err != nil {
+		api.respondUpdateError(c, err, params)
+		return
+	}
+
+	scheduling := nodeCheck.Spec.Scheduling
+	if scheduling == "" {
+		scheduling = trigger.SchedulingInterval
+	}
+	fmt.Printf("Run of NodeCheck %s/%s triggered by %s\n", nodeCheck.Namespace, nodeCheck.Name, user.Username)
+	c.JSON(http.StatusAccepted, gin.H{
+		"name":       nodeCheck.Name,
+		"namespace":  nodeCheck.Namespace,
+		"trigger":    nodeCheck.Annotations[trigger.Annotation],
+		"scheduling": scheduling,
+	})
+}
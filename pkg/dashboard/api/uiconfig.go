@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/operatorconfig"
+)
+
+const (
+	defaultUITitle = "Node Check"
+	defaultUIView  = "overview"
+)
+
+// uiViews are the views of the console plugin that can be opened first
+var uiViews = map[string]bool{"overview": true, "checks": true, "nodes": true}
+
+// operatorConfig is the operator configuration whose ui section is served by /api/v1/uiconfig
+var operatorConfig atomic.Pointer[operatorconfig.Config]
+
+// SetOperatorConfig sets the operator configuration the console plugin configuration is read from
+func SetOperatorConfig(config *operatorconfig.Config) {
+	operatorConfig.Store(config)
+}
+
+// UIConfig is the console plugin configuration returned by /api/v1/uiconfig
+type UIConfig struct {
+	Title       string          `json:"title"`
+	LogoURL     string          `json:"logoURL,omitempty"`
+	DefaultView string          `json:"defaultView"`
+	Features    map[string]bool `json:"features"`
+}
+
+// defaultUIConfig is returned when the NodeCheckOperatorConfig does not exist or has no ui section
+func defaultUIConfig() UIConfig {
+	return UIConfig{
+		Title:       defaultUITitle,
+		DefaultView: defaultUIView,
+		Features:    map[string]bool{},
+	}
+}
+
+// buildUIConfig applies the ui section of the NodeCheckOperatorConfig over the defaults. The CRD
+// validates the values; the ones it could not check are ignored, so they never break the plugin.
+func buildUIConfig(ui *v1alpha1.UIConfig) UIConfig {
+	config := defaultUIConfig()
+	if ui == nil {
+		return config
+	}
+	if title := strings.TrimSpace(ui.Title); title != "" {
+		config.Title = title
+	}
+	config.LogoURL = ui.LogoURL
+	if uiViews[ui.DefaultView] {
+		config.DefaultView = ui.DefaultView
+	}
+	for name, enabled := range ui.FeatureFlags {
+		if name != "" {
+			config.Features[name] = enabled
+		}
+	}
+	return config
+}
+
+// GetUIConfig returns the console plugin configuration, from the ui section of the
+// NodeCheckOperatorConfig singleton as last read by the operator, so changes apply on the next page
+// load without restarting anything
+func (api *DashboardAPI) GetUIConfig(c *gin.Context) {
+	var ui *v1alpha1.UIConfig
+	if config := operatorConfig.Load(); config != nil {
+		ui = config.UI()
+	}
+	c.JSON(http.StatusOK, buildUIConfig(ui))
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+)
+
+// decimalSeparators are the decimal separators of the catalog languages that do not use "."
+var decimalSeparators = map[string]string{
+	"it": ",",
+}
+
+// unitsFormatter adds human readable values to the details of the check results when a request
+// sets ?units=human: every number stored in base units gets a "_human" sibling, formatted in the
+// language of the Accept-Language header (e.g. "memory_limits_bytes": 8804682956 gets
+// "memory_limits_human": "8.2 GiB", and "bytes" gets "human"). Clients no longer need to parse
+// or format sizes themselves; the numbers are left untouched. ?units=raw, the default, returns
+// the details as stored.
+type unitsFormatter struct {
+	language string
+}
+
+// parseUnits returns the formatter of a request, nil when it asks for the raw units
+func parseUnits(c *gin.Context) *unitsFormatter {
+	if !strings.EqualFold(strings.TrimSpace(c.Query("units")), "human") {
+		return nil
+	}
+	return &unitsFormatter{language: negotiateLanguage(c.GetHeader("Accept-Language"))}
+}
+
+// variant describes the units for ETags, since the human readable values depend on the language
+func (f *unitsFormatter) variant() string {
+	if f == nil {
+		return ""
+	}
+	return "?units=human&lang=" + f.language
+}
+
+// humanize adds the human readable values to details, at any depth
+func (f *unitsFormatter) humanize(details map[string]interface{}) {
+	if f == nil || details == nil {
+		return
+	}
+	added := make(map[string]interface{})
+	for key, value := range details {
+		switch typed := value.(type) {
+		case map[string]interface{}:
+			f.humanize(typed)
+		case []interface{}:
+			for _, item := range typed {
+				if object, ok := item.(map[string]interface{}); ok {
+					f.humanize(object)
+				}
+			}
+		default:
+			number, ok := value.(float64)
+			if !ok {
+				continue
+			}
+			if name, human, ok := f.format(key, number); ok {
+				if _, exists := details[name]; !exists {
+					added[name] = human
+				}
+			}
+		}
+	}
+	for key, value := range added {
+		details[key] = value
+	}
+}
+
+// format returns the key and the human readable value of a number stored under key, if the key
+// carries a unit
+func (f *unitsFormatter) format(key string, number float64) (string, string, bool) {
+	var base, human string
+	switch {
+	case key == "bytes":
+		return "human", f.localize(v1alpha1.FormatBytes(number)), true
+	case strings.HasSuffix(key, "_bytes"):
+		base, human = strings.TrimSuffix(key, "_bytes"), v1alpha1.FormatBytes(number)
+	case strings.HasSuffix(key, "_seconds"):
+		base, human = strings.TrimSuffix(key, "_seconds"), v1alpha1.FormatSeconds(number)
+	case strings.HasSuffix(key, "_ms"):
+		base, human = strings.TrimSuffix(key, "_ms"), v1alpha1.FormatSeconds(number/1000)
+	default:
+		return "", "", false
+	}
+	return base + "_human", f.localize(human), true
+}
+
+// localize replaces the decimal point of a formatted value with the separator of the language
+func (f *unitsFormatter) localize(value string) string {
+	if separator, ok := decimalSeparators[f.language]; ok {
+		return strings.Replace(value, ".", separator, 1)
+	}
+	return value
+}
@@ -0,0 +1,365 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+const (
+	// defaultPageLimit is the page size of /api/v2/nodechecks when the request sets no limit
+	defaultPageLimit = 100
+	// maxPageLimit bounds the page size of /api/v2/nodechecks
+	maxPageLimit = 500
+
+	// userContextKey holds the user authenticated by authenticateUser in the gin context
+	userContextKey = "user"
+)
+
+// NodeCheckPage is a page of /api/v2/nodechecks. Continue is set when more NodeChecks follow
+// and must be sent back as ?continue= to get the next page.
+type NodeCheckPage struct {
+	Items    []NodeCheckSummary `json:"items"`
+	Continue string             `json:"continue,omitempty"`
+	Total    int                `json:"total"`
+}
+
+// CheckResultV2 is a check result of /api/v2. Checks are a flat list named by the path of their
+// result in status.checkResults (e.g. "systemResults.disks.smart"), so clients do not need to
+// know the layout of the status to iterate over them. Schema names the typed schema of the
+// details (e.g. "MemoryDetails"), empty for free-form details. RunID identifies the run of the
+// executor that produced the result (GET /api/v2/runs/:id).
+type CheckResultV2 struct {
+	Name      string                 `json:"name"`
+	Category  string                 `json:"category"`
+	Status    string                 `json:"status"`
+	Message   string                 `json:"message,omitempty"`
+	Timestamp string                 `json:"timestamp,omitempty"`
+	Command   string                 `json:"command,omitempty"`
+	Schema    string                 `json:"schema,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+	RunID     string                 `json:"runID,omitempty"`
+}
+
+// detailsSchemas maps the checks with typed details to the name of their schema in api/v1alpha1
+var detailsSchemas = map[string]string{
+	"systemResults.uptime":                "LoadDetails",
+	"systemResults.memory":                "MemoryDetails",
+	"systemResults.disks.space":           "DiskSpaceDetails",
+	"kubernetesResults.nodeResourceUsage": "NodeResourceUsageDetails",
+}
+
+// NodeCheckDetailV2 is a NodeCheck of /api/v2 with its check results and the incidents correlating
+// the failing ones
+type NodeCheckDetailV2 struct {
+	NodeCheckSummary
+	Checks    []CheckResultV2 `json:"checks"`
+	Incidents []Incident      `json:"incidents"`
+}
+
+// setupV2Routes registers the /api/v2 surface. Every request must carry the bearer token of a user
+// (the console forwards it), and reads are authorized with SubjectAccessReviews against the RBAC
+// of the user on NodeChecks, so the API shows each user only what kubectl would show them.
+func (api *DashboardAPI) setupV2Routes(r *gin.Engine) {
+	v2 := r.Group("/api/v2", api.authenticateUser)
+	{
+		v2.GET("/stats", api.authorizeNodeChecks("list"), api.GetDashboardStats)
+		v2.GET("/heatmap", api.authorizeNodeChecks("list"), api.GetHeatmap)
+		v2.GET("/compliance", api.authorizeNodeChecks("list"), api.GetCompliance)
+		v2.GET("/runs/:id", api.GetRun)
+		v2.GET("/nodechecks", api.authorizeNodeChecks("list"), api.ListNodeChecksV2)
+		v2.GET("/nodechecks/:namespace/:name", api.authorizeNodeChecks("get"), api.GetNodeCheckV2)
+		v2.GET("/nodechecks/:namespace/:name/history", api.authorizeNodeChecks("get"), api.GetNodeCheckHistoryV2)
+		v2.PATCH("/nodechecks/:namespace/:name/checks/:check", api.UpdateCheckConfig)
+		v2.GET("/nodechecks/:namespace/:name/faults", requireFaultInjection, api.authorizeNodeChecks("get"), api.ListFaults)
+		v2.POST("/nodechecks/:namespace/:name/faults", requireFaultInjection, api.InjectFault)
+		v2.DELETE("/nodechecks/:namespace/:name/faults", requireFaultInjection, api.ClearFaults)
+		v2.POST("/nodechecks/:namespace/:name/verify", api.RequestVerification)
+		v2.POST("/nodechecks/:namespace/:name/trigger", api.TriggerRun)
+		v2.POST("/nodechecks/:namespace/:name/false-positives", api.authorizeNodeChecks("get"), api.MarkFalsePositive)
+		v2.GET("/false-positives", api.authorizeNodeChecks("list"), api.GetFalsePositiveReport)
+		v2.GET("/nodes/:nodeName/drain-report", api.authorizeAllNodeChecks("list"), api.GetDrainReport)
+		v2.GET("/selfstatus", api.GetSelfStatus)
+		v2.GET("/uiconfig", api.GetUIConfig)
+	}
+
+	// Slash commands are authenticated by their signature instead of a user token
+	r.POST("/api/v2/chatops/slack", api.HandleSlackCommand)
+}
+
+// deprecatedAPI marks the responses of the /api/v1 and unprefixed routes as deprecated
+// (draft-ietf-httpapi-deprecation-header) and links their successor
+func deprecatedAPI(c *gin.Context) {
+	c.Header("Deprecation", "true")
+	c.Header("Link", `</api/v2>; rel="successor-version"`)
+	c.Next()
+}
+
+// authenticateUser rejects the requests without a valid user token and stores the user for the
+// authorization of the handlers
+func (api *DashboardAPI) authenticateUser(c *gin.Context) {
+	if c.Request.Method == http.MethodOptions {
+		c.Next()
+		return
+	}
+	user, err := api.requestUser(c.Request.Context(), c)
+	if err != nil {
+		fmt.Printf("Unable to authenticate the user of %s: %v\n", c.Request.URL.Path, err)
+		respondError(c, http.StatusServiceUnavailable, msgAuthenticationFailed, nil)
+		c.Abort()
+		return
+	}
+	if user == nil {
+		respondError(c, http.StatusUnauthorized, msgAuthenticationRequired, nil)
+		c.Abort()
+		return
+	}
+	c.Set(userContextKey, user)
+	c.Next()
+}
+
+// authorizeNodeChecks checks with a SubjectAccessReview that the user may run verb on the
+// NodeChecks of the request: the namespace and name come from the path, or from ?namespace=
+// for lists (all namespaces when unset). The handlers behind it must scope their lists to that
+// namespace.
+func (api *DashboardAPI) authorizeNodeChecks(verb string) gin.HandlerFunc {
+	return api.authorizeNodeChecksIn(verb, false)
+}
+
+// authorizeAllNodeChecks checks that the user may run verb on the NodeChecks of all namespaces,
+// whatever ?namespace= says, for the handlers whose response cannot be scoped to a namespace
+func (api *DashboardAPI) authorizeAllNodeChecks(verb string) gin.HandlerFunc {
+	return api.authorizeNodeChecksIn(verb, true)
+}
+
+// authorizeNodeChecksIn runs the SubjectAccessReview of authorizeNodeChecks, against all the
+// namespaces when clusterWide is set
+func (api *DashboardAPI) authorizeNodeChecksIn(verb string, clusterWide bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, _ := c.Get(userContextKey)
+		user, _ := value.(*authenticationv1.UserInfo)
+		if user == nil {
+			respondError(c, http.StatusUnauthorized, msgAuthenticationRequired, nil)
+			c.Abort()
+			return
+		}
+
+		namespace := c.Param("namespace")
+		if namespace == "" && !clusterWide {
+			namespace = c.Query("namespace")
+		}
+		name := c.Param("name")
+
+		allowed, err := api.userCan(c.Request.Context(), user, verb, namespace, name)
+		if err != nil {
+			fmt.Printf("Unable to authorize user %s to %s NodeChecks: %v\n", user.Username, verb, err)
+			respondError(c, http.StatusServiceUnavailable, msgAuthenticationFailed, nil)
+			c.Abort()
+			return
+		}
+		if !allowed {
+			switch {
+			case name != "":
+				respondError(c, http.StatusForbidden, msgNodeCheckReadForbidden, map[string]string{"namespace": namespace, "name": name})
+			case namespace != "":
+				respondError(c, http.StatusForbidden, msgNodeChecksListForbidden, map[string]string{"namespace": namespace})
+			default:
+				respondError(c, http.StatusForbidden, msgAllNodeChecksListForbidden, nil)
+			}
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// userCan asks the API server whether a user may run verb on NodeChecks
+func (api *DashboardAPI) userCan(ctx context.Context, user *authenticationv1.UserInfo, verb, namespace, name string) (bool, error) {
+	return api.userCanAccess(ctx, user, &authorizationv1.ResourceAttributes{
+		Group:     v1alpha1.GroupVersion.Group,
+		Version:   v1alpha1.GroupVersion.Version,
+		Resource:  "nodechecks",
+		Namespace: namespace,
+		Name:      name,
+		Verb:      verb,
+	})
+}
+
+// userCanAccess checks with a SubjectAccessReview that the user may access a resource
+func (api *DashboardAPI) userCanAccess(ctx context.Context, user *authenticationv1.UserInfo, attributes *authorizationv1.ResourceAttributes) (bool, error) {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, values := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(values)
+	}
+	review, err := api.clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:               user.Username,
+			UID:                user.UID,
+			Groups:             user.Groups,
+			Extra:              extra,
+			ResourceAttributes: attributes,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return false, err
+	}
+	return review.Status.Allowed, nil
+}
+
+// ListNodeChecksV2 returns a page of NodeCheck summaries, sorted by namespace and name.
+// ?namespace= and ?status= filter them; ?limit= sets the page size and ?continue= the page.
+func (api *DashboardAPI) ListNodeChecksV2(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	limit := defaultPageLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageLimit {
+			respondError(c, http.StatusBadRequest, msgInvalidLimit, map[string]string{"max": strconv.Itoa(maxPageLimit)})
+			return
+		}
+		limit = parsed
+	}
+	after := ""
+	if token := c.Query("continue"); token != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(token)
+		if err != nil || !strings.Contains(string(decoded), "/") {
+			respondError(c, http.StatusBadRequest, msgInvalidContinueToken, nil)
+			return
+		}
+		after = string(decoded)
+	}
+
+	namespace := c.Query("namespace")
+	status := c.Query("status")
+
+	var nodeChecks v1alpha1.NodeCheckList
+	if err := api.k8sClient.List(ctx, &nodeChecks, client.InNamespace(namespace)); err != nil {
+		respondError(c, http.StatusInternalServerError, msgListNodeChecksFailed, map[string]string{"error": err.Error()})
+		return
+	}
+	variant := fmt.Sprintf("v2/nodechecks?namespace=%s&status=%s&limit=%d&continue=%s", namespace, status, limit, after)
+	if notModified(c, nodeCheckListETag(variant, nodeChecks.Items)) {
+		return
+	}
+
+	items := make([]v1alpha1.NodeCheck, 0, len(nodeChecks.Items))
+	for _, nc := range nodeChecks.Items {
+		if status == "" || strings.EqualFold(nc.Status.OverallStatus, status) {
+			items = append(items, nc)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return pageKey(items[i]) < pageKey(items[j]) })
+
+	// The token is the key of the last NodeCheck returned, so pages stay consistent when
+	// NodeChecks are created or deleted between two requests
+	start := sort.Search(len(items), func(i int) bool { return pageKey(items[i]) > after })
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	page := NodeCheckPage{
+		Items: make([]NodeCheckSummary, 0, end-start),
+		Total: len(items),
+	}
+	for _, nc := range items[start:end] {
+		page.Items = append(page.Items, summarizeNodeCheck(nc))
+	}
+	if end < len(items) {
+		page.Continue = base64.RawURLEncoding.EncodeToString([]byte(pageKey(items[end-1])))
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// pageKey orders the NodeChecks of /api/v2/nodechecks
+func pageKey(nc v1alpha1.NodeCheck) string {
+	return nc.Namespace + "/" + nc.Name
+}
+
+// GetNodeCheckV2 returns a NodeCheck with its check results as a flat list. It supports the
+// ?fields= and ?exclude= selection and the ?units= option of the v1 endpoints.
+func (api *DashboardAPI) GetNodeCheckV2(c *gin.Context) {
+	namespace := c.Param("namespace")
+	name := c.Param("name")
+
+	var nodeCheck v1alpha1.NodeCheck
+	if err := api.k8sClient.Get(c.Request.Context(), client.ObjectKey{Name: name, Namespace: namespace}, &nodeCheck); err != nil {
+		respondError(c, http.StatusNotFound, msgNodeCheckNotFound, map[string]string{"namespace": namespace, "name": name})
+		return
+	}
+	selection := parseFieldSelection(c)
+	units := parseUnits(c)
+	if notModified(c, nodeCheckListETag("v2/nodecheck"+selection.variant()+units.variant(), []v1alpha1.NodeCheck{nodeCheck})) {
+		return
+	}
+
+	detail := NodeCheckDetailV2{
+		NodeCheckSummary: summarizeNodeCheck(nodeCheck),
+		Checks:           flattenCheckResults(nodeCheck.Status.CheckResults),
+	}
+	detail.Incidents = correlateChecks(detail.Checks)
+	for _, check := range detail.Checks {
+		units.humanize(check.Details)
+	}
+	respondSelected(c, http.StatusOK, selection, detail)
+}
+
+// flattenCheckResults lists the check results of a NodeCheck, sorted by name
+func flattenCheckResults(results v1alpha1.CheckResults) []CheckResultV2 {
+	checks := []CheckResultV2{}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return checks
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return checks
+	}
+	walkCheckResultsV2("", tree, &checks)
+	sort.Slice(checks, func(i, j int) bool { return checks[i].Name < checks[j].Name })
+	return checks
+}
+
+// walkCheckResultsV2 collects the results of the status tree: objects with a status are results,
+// the others (disks, network, hardware) group them
+func walkCheckResultsV2(path string, node map[string]interface{}, checks *[]CheckResultV2) {
+	if status, ok := node["status"].(string); ok {
+		check := CheckResultV2{Name: path, Status: status}
+		check.Category, _, _ = strings.Cut(path, ".")
+		check.Message, _ = node["message"].(string)
+		check.Timestamp, _ = node["timestamp"].(string)
+		check.Command, _ = node["command"].(string)
+		check.RunID, _ = node["runID"].(string)
+		check.Schema = detailsSchemas[path]
+		if details, ok := node["details"].(map[string]interface{}); ok {
+			v1alpha1.ExpandDetails(details)
+			check.Details = details
+		}
+		*checks = append(*checks, check)
+		return
+	}
+	for key, child := range node {
+		if object, ok := child.(map[string]interface{}); ok {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			walkCheckResultsV2(childPath, object, checks)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/verification"
+	"github.com/gin-gonic/gin"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VerifyRequest is the body of POST /api/v2/nodechecks/:namespace/:name/verify
+type VerifyRequest struct {
+	// AutoUncordon uncordons the node when the verification passes; it requires the permission to
+	// patch the node
+	AutoUncordon bool `json:"autoUncordon,omitempty"`
+}
+
+// RequestVerification requests the post-maintenance verification of the node of a NodeCheck: the
+// operator runs all the checks once with tighter thresholds and records the pass/fail gate in
+// status.verification. The request annotation is set as the requesting user, like UpdateCheckConfig,
+// and replaces a verification still running.
+func (api *DashboardAPI) RequestVerification(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), checkUpdateTimeout)
+	defer cancel()
+
+	params := map[string]string{"namespace": c.Param("namespace"), "name": c.Param("name")}
+	var request VerifyRequest
+	// The body is optional
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			respondError(c, http.StatusBadRequest, msgInvalidVerification, params)
+			return
+		}
+	}
+
+	value, _ := c.Get(userContextKey)
+	user, _ := value.(*authenticationv1.UserInfo)
+	if user == nil || api.restConfig == nil {
+		respondError(c, http.StatusUnauthorized, msgUserNotAuthenticated, params)
+		return
+	}
+	userClient, err := api.clientAs(user)
+	if err != nil {
+		fmt.Printf("Unable to impersonate the user verifying NodeCheck %s/%s: %v\n", params["namespace"], params["name"], err)
+		respondError(c, http.StatusServiceUnavailable, msgUserAuthenticationFailed, params)
+		return
+	}
+
+	var nodeCheck v1alpha1.NodeCheck
+	if err := userClient.Get(ctx, client.ObjectKey{Name: params["name"], Namespace: params["namespace"]}, &nodeCheck); err != nil {
+		api.respondUpdateError(c, err, params)
+		return
+	}
+	nodeName := nodeCheck.Spec.NodeName
+	if nodeName == "" || nodeName == "*" || nodeName == "all" || nodeCheck.Labels[verification.Label] != "" {
+		respondError(c, http.StatusConflict, msgVerificationNeedsNode, params)
+		return
+	}
+
+	// The operator uncordons the node with its own permissions, so the user must be allowed to do it
+	if request.AutoUncordon {
+		params["node"] = nodeName
+		allowed, err := api.userCanAccess(ctx, user, &authorizationv1.ResourceAttributes{
+			Resource: "nodes",
+			Name:     nodeName,
+			Verb:     "patch",
+		})
+		if err != nil {
+			fmt.Printf("Unable to authorize user %s to uncordon node %s: %v\n", user.Username, nodeName, err)
+			respondError(c, http.StatusServiceUnavailable, msgAuthenticationFailed, nil)
+			return
+		}
+		if !allowed {
+			respondError(c, http.StatusForbidden, msgUncordonForbidden, params)
+			return
+		}
+	}
+
+	annotation, err := verification.Request{RequestedBy: user.Username, AutoUncordon: request.AutoUncordon}.Encode()
+	if err != nil {
+		params["error"] = err.Error()
+		respondError(c, http.StatusInternalServerError, msgNodeCheckUpdateFailed, params)
+		return
+	}
+	if nodeCheck.Annotations == nil {
+		nodeCheck.Annotations = make(map[string]string)
+	}
+	nodeCheck.Annotations[verification.Annotation] = annotation
+	if err := userClient.Update(ctx, &nodeCheck); err != nil {
+		api.respondUpdateError(c, err, params)
+		return
+	}
+
+	fmt.Printf("Verification of NodeCheck %s/%s (node %s) requested by %s, autoUncordon=%t\n",
+		nodeCheck.Namespace, nodeCheck.Name, nodeName, user.Username, request.AutoUncordon)
+	c.JSON(http.StatusAccepted, gin.H{
+		"name":         nodeCheck.Name,
+		"namespace":    nodeCheck.Namespace,
+		"node":         nodeName,
+		"nodeCheck":    verification.Name(nodeCheck.Name),
+		"autoUncordon": request.AutoUncordon,
+	})
+}
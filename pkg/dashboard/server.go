@@ -11,6 +11,7 @@ import (
 	"github.com/albertofilice/node-check-operator/pkg/dashboard/api"
 	"github.com/gin-gonic/gin"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -19,15 +20,17 @@ type DashboardServer struct {
 	server    *http.Server
 	k8sClient client.Client
 	clientset *kubernetes.Clientset
+	config    *rest.Config
 	namespace string
 	port      int
 }
 
 // NewDashboardServer creates a new dashboard server
-func NewDashboardServer(k8sClient client.Client, clientset *kubernetes.Clientset, namespace string, port int) *DashboardServer {
+func NewDashboardServer(k8sClient client.Client, clientset *kubernetes.Clientset, config *rest.Config, namespace string, port int) *DashboardServer {
 	return &DashboardServer{
 		k8sClient: k8sClient,
 		clientset: clientset,
+		config:    config,
 		namespace: namespace,
 		port:      port,
 	}
@@ -44,7 +47,7 @@ func (ds *DashboardServer) Start() error {
 	// Setup CORS
 	router.Use(func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
 
 		if c.Request.Method == "OPTIONS" {
@@ -55,8 +58,11 @@ func (ds *DashboardServer) Start() error {
 		c.Next()
 	})
 
+	// Compress the responses for clients accepting gzip
+	router.Use(api.GzipResponses())
+
 	// Setup API routes
-	dashboardAPI := api.NewDashboardAPI(ds.k8sClient, ds.clientset, ds.namespace)
+	dashboardAPI := api.NewDashboardAPI(ds.k8sClient, ds.clientset, ds.config, ds.namespace)
 	dashboardAPI.SetupRoutes(router)
 
 	// Setup web routes
@@ -149,8 +155,13 @@ func (ds *DashboardServer) dashboardPage(c *gin.Context) {
 		"message": "Node Check Dashboard API",
 		"endpoints": gin.H{
 			"stats":      "/api/v1/stats",
+			"heatmap":    "/api/v1/heatmap",
 			"nodechecks": "/api/v1/nodechecks",
+			"selfstatus": "/api/v1/selfstatus",
+			"uiconfig":   "/api/v1/uiconfig",
+			"checks":     "PATCH /api/v1/nodechecks/:name/checks/:check",
 			"health":     "/health",
+			"v2":         "/api/v2",
 		},
 	})
 }
@@ -0,0 +1,125 @@
+// Package faults injects synthetic check results, so the alerting, notification and remediation
+// pipelines can be verified end to end without breaking a real node. Faults are stored in an
+// annotation of the NodeCheck by the dashboard API and applied by the executor to the results of its
+// next run, before maintenance windows and the aggregation of the overall status, like real results.
+// Both sides only act when the FaultInjection feature gate is enabled.
+package faults
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+const (
+	// Annotation holds the injected faults of a NodeCheck, as a JSON list of Fault
+	Annotation = "nodecheck.openshift.io/inject-faults"
+	// EnvVar enables fault injection in the executor; the operator sets it on the executor
+	// DaemonSet from the FaultInjection feature gate
+	EnvVar = "ENABLE_FAULT_INJECTION"
+
+	// DefaultDuration is how long a fault lasts when the request sets no duration
+	DefaultDuration = 15 * time.Minute
+	// MaxDuration bounds the duration of a fault, so a forgotten fault cannot page forever
+	MaxDuration = 24 * time.Hour
+)
+
+// Fault is a synthetic result replacing the result of a check until it expires
+type Fault struct {
+	// Check is the check name as in the results (e.g. "disk_smart", "node_status")
+	Check string `json:"check"`
+	// Status is Warning or Critical
+	Status string `json:"status"`
+	// Message replaces the message of the check
+	Message string `json:"message,omitempty"`
+	// InjectedBy is the user who injected the fault
+	InjectedBy string `json:"injectedBy,omitempty"`
+	// Expires is when the check reports its real result again
+	Expires metav1.Time `json:"expires"`
+}
+
+// ValidStatus reports whether a status can be injected
+func ValidStatus(status string) bool {
+	return status == "Warning" || status == "Critical"
+}
+
+// Parse reads the faults of the annotations of a NodeCheck
+func Parse(annotations map[string]string) ([]Fault, error) {
+	value := strings.TrimSpace(annotations[Annotation])
+	if value == "" {
+		return nil, nil
+	}
+	var faults []Fault
+	if err := json.Unmarshal([]byte(value), &faults); err != nil {
+		return nil, fmt.Errorf("invalid %s annotation: %w", Annotation, err)
+	}
+	return faults, nil
+}
+
+// Encode returns the annotation value of the faults, "" when there is none
+func Encode(faults []Fault) (string, error) {
+	if len(faults) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(faults)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Active returns the faults that have not expired at the given time
+func Active(faults []Fault, now time.Time) []Fault {
+	active := []Fault{}
+	for _, fault := range faults {
+		if now.Before(fault.Expires.Time) {
+			active = append(active, fault)
+		}
+	}
+	return active
+}
+
+// Enabled reports whether the executor applies the injected faults
+func Enabled() bool {
+	switch strings.ToLower(os.Getenv(EnvVar)) {
+	case "true", "1", "yes", "enabled":
+		return true
+	default:
+		return false
+	}
+}
+
+// InjectResult replaces a check result with the first active fault of the check. The message names
+// the fault and the real status, so an injected result cannot be taken for a real one. Returns true if
+// a fault was applied.
+func InjectResult(checkName string, result *v1alpha1.CheckResult, active []Fault, now time.Time) bool {
+	for _, fault := range active {
+		if fault.Check != checkName || !ValidStatus(fault.Status) {
+			continue
+		}
+		message := fault.Message
+		if message == "" {
+			message = fmt.Sprintf("Synthetic %s result of %s", fault.Status, checkName)
+		}
+		original := result.Status
+		if original == "" {
+			original = "not run"
+		}
+		injectedBy := ""
+		if fault.InjectedBy != "" {
+			injectedBy = " by " + fault.InjectedBy
+		}
+		result.Message = fmt.Sprintf("[injected fault%s until %s, was %s] %s",
+			injectedBy, fault.Expires.UTC().Format(time.RFC3339), original, message)
+		result.Status = fault.Status
+		result.Timestamp = metav1.NewTime(now)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Feedback is a check result marked as a false positive from the dashboard. It keeps the values the
+// check reported (its details), so the thresholds can be tuned against them later.
+type Feedback struct {
+	Namespace string `json:"namespace"`
+	NodeCheck string `json:"nodeCheck"`
+	Node      string `json:"node"`
+	Check     string `json:"check"`
+	// Status, Message and Values are the result marked, as it was when the feedback was given
+	Status          string          `json:"status"`
+	Message         string          `json:"message,omitempty"`
+	Values          json.RawMessage `json:"values,omitempty"`
+	ResultTimestamp time.Time       `json:"resultTimestamp"`
+	Comment         string          `json:"comment,omitempty"`
+	ReportedBy      string          `json:"reportedBy"`
+	Timestamp       time.Time       `json:"timestamp"`
+}
+
+// FeedbackStore keeps the false-positive feedback. The Memory, File and SQLite stores implement it.
+type FeedbackStore interface {
+	// RecordFeedback stores a feedback
+	RecordFeedback(ctx context.Context, feedback Feedback) error
+	// QueryFeedback returns the feedback given since a time, oldest first
+	QueryFeedback(ctx context.Context, since time.Time) ([]Feedback, error)
+}
+
+// FeedbackSummary aggregates the feedback given on a check
+type FeedbackSummary struct {
+	Check string `json:"check"`
+	// FalsePositives is the number of results marked
+	FalsePositives int `json:"falsePositives"`
+	// Nodes is the number of distinct nodes the results were marked on
+	Nodes int `json:"nodes"`
+	// Statuses counts the results marked by status (Warning, Critical)
+	Statuses map[string]int `json:"statuses"`
+	Last     time.Time      `json:"last"`
+}
+
+// fallbackFeedback keeps the feedback in memory while the history store cannot (Status and Prometheus
+// backends)
+var fallbackFeedback = &memoryFeedback{retention: DefaultRetention}
+
+// CurrentFeedback returns the store of the false-positive feedback: the history store when it keeps
+// feedback, otherwise a store in the operator process, lost when the operator restarts
+func CurrentFeedback() FeedbackStore {
+	if store, ok := Current().(FeedbackStore); ok {
+		return store
+	}
+	return fallbackFeedback
+}
+
+// SummarizeFeedback aggregates feedback by check, the checks with the most false positives first
+func SummarizeFeedback(feedback []Feedback) []FeedbackSummary {
+	byCheck := make(map[string]*FeedbackSummary)
+	nodes := make(map[string]map[string]bool)
+	for _, entry := range feedback {
+		summary, ok := byCheck[entry.Check]
+		if !ok {
+			summary = &FeedbackSummary{Check: entry.Check, Statuses: make(map[string]int)}
+			byCheck[entry.Check] = summary
+			nodes[entry.Check] = make(map[string]bool)
+		}
+		summary.FalsePositives++
+		summary.Statuses[entry.Status]++
+		nodes[entry.Check][entry.Node] = true
+		if entry.Timestamp.After(summary.Last) {
+			summary.Last = entry.Timestamp
+		}
+	}
+	summaries := make([]FeedbackSummary, 0, len(byCheck))
+	for check, summary := range byCheck {
+		summary.Nodes = len(nodes[check])
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].FalsePositives != summaries[j].FalsePositives {
+			return summaries[i].FalsePositives > summaries[j].FalsePositives
+		}
+		return summaries[i].Check < summaries[j].Check
+	})
+	return summaries
+}
+
+// memoryFeedback keeps the feedback of the last retention in memory
+type memoryFeedback struct {
+	retention time.Duration
+
+	mu       sync.Mutex
+	feedback []Feedback
+}
+
+// RecordFeedback appends a feedback and drops the ones older than the retention
+func (m *memoryFeedback) RecordFeedback(ctx context.Context, feedback Feedback) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := append(m.feedback, feedback)
+	sort.SliceStable(stored, func(i, j int) bool { return stored[i].Timestamp.Before(stored[j].Timestamp) })
+	cutoff := time.Now().Add(-m.retention)
+	first := sort.Search(len(stored), func(i int) bool { return !stored[i].Timestamp.Before(cutoff) })
+	m.feedback = append([]Feedback(nil), stored[first:]...)
+	return nil
+}
+
+// QueryFeedback returns the feedback given since a time
+func (m *memoryFeedback) QueryFeedback(ctx context.Context, since time.Time) ([]Feedback, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var feedback []Feedback
+	for _, entry := range m.feedback {
+		if !entry.Timestamp.Before(since) {
+			feedback = append(feedback, entry)
+		}
+	}
+	return feedback, nil
+}
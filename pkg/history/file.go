@@ -0,0 +1,264 @@
+package history
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// fileCompaction is how often the file of a NodeCheck is rewritten without the entries older than the retention
+const fileCompaction = time.Hour
+
+// feedbackFile is the file of the false-positive feedback under the directory. NodeCheck files always
+// hold a "_", so they cannot clash with it.
+const feedbackFile = "feedback.jsonl"
+
+// FileStore keeps the history in a JSON Lines file per NodeCheck under a directory, typically on a
+// PersistentVolumeClaim mounted in the operator Deployment so that it survives restarts
+type FileStore struct {
+	dir       string
+	retention time.Duration
+
+	mu sync.Mutex
+	// last is the timestamp of the last stored entry of each check, read from the file on first use
+	last map[Key]map[string]time.Time
+	// compacted is when the file of each NodeCheck was last rewritten
+	compacted map[Key]time.Time
+	// feedbackCompacted is when the feedback file was last rewritten
+	feedbackCompacted time.Time
+}
+
+// NewFileStore returns a FileStore writing under dir, which is created if needed
+func NewFileStore(dir string, retention time.Duration) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("history directory %s: %w", dir, err)
+	}
+	return &FileStore{
+		dir:       dir,
+		retention: retention,
+		last:      make(map[Key]map[string]time.Time),
+		compacted: make(map[Key]time.Time),
+	}, nil
+}
+
+// Backend returns BackendFile
+func (s *FileStore) Backend() string {
+	return BackendFile
+}
+
+// path returns the file of a NodeCheck. Namespaces and names cannot contain "_".
+func (s *FileStore) path(key Key) string {
+	return filepath.Join(s.dir, key.Namespace+"_"+key.Name+".jsonl")
+}
+
+// Record appends the new entries of a NodeCheck to its file
+func (s *FileStore) Record(ctx context.Context, key Key, node string, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.last[key]
+	if !ok {
+		stored, err := s.read(key, time.Time{})
+		if err != nil {
+			return err
+		}
+		last = lastTimestamps(stored)
+		s.last[key] = last
+		s.compacted[key] = time.Time{}
+	}
+
+	file, err := os.OpenFile(s.path(key), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range entries {
+		if !entry.Timestamp.After(last[entry.Check]) {
+			continue
+		}
+		if err := encoder.Encode(entry); err != nil {
+			file.Close()
+			return err
+		}
+		last[entry.Check] = entry.Timestamp
+	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if time.Since(s.compacted[key]) >= fileCompaction {
+		if err := s.compact(key); err != nil {
+			return err
+		}
+		s.compacted[key] = time.Now()
+	}
+	return nil
+}
+
+// compact rewrites the file of a NodeCheck without the entries older than the retention
+func (s *FileStore) compact(key Key) error {
+	entries, err := s.read(key, time.Now().Add(-s.retention))
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(s.dir, ".compact-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	writer := bufio.NewWriter(temp)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			temp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), s.path(key))
+}
+
+// read returns the entries of the file of a NodeCheck since a time. Lines that cannot be parsed (e.g. a
+// line cut by a crash while it was written) are skipped.
+func (s *FileStore) read(key Key, since time.Time) ([]Entry, error) {
+	file, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if !entry.Timestamp.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Query returns the entries of a NodeCheck since a time
+func (s *FileStore) Query(ctx context.Context, key Key, since time.Time) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(key, since)
+}
+
+// Forget removes the file of a deleted NodeCheck
+func (s *FileStore) Forget(key Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.last, key)
+	delete(s.compacted, key)
+	os.Remove(s.path(key))
+}
+
+// RecordFeedback appends a feedback to the feedback file
+func (s *FileStore) RecordFeedback(ctx context.Context, feedback Feedback) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	line, err := json.Marshal(feedback)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(s.dir, feedbackFile)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if time.Since(s.feedbackCompacted) < fileCompaction {
+		return nil
+	}
+	kept, err := s.readFeedback(time.Now().Add(-s.retention))
+	if err != nil {
+		return err
+	}
+	temp, err := os.CreateTemp(s.dir, ".compact-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	writer := bufio.NewWriter(temp)
+	encoder := json.NewEncoder(writer)
+	for _, entry := range kept {
+		if err := encoder.Encode(entry); err != nil {
+			temp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return err
+	}
+	s.feedbackCompacted = time.Now()
+	return nil
+}
+
+// QueryFeedback returns the feedback of the feedback file given since a time
+func (s *FileStore) QueryFeedback(ctx context.Context, since time.Time) ([]Feedback, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readFeedback(since)
+}
+
+// readFeedback returns the feedback of the feedback file since a time, skipping the lines that cannot
+// be parsed like read
+func (s *FileStore) readFeedback(since time.Time) ([]Feedback, error) {
+	file, err := os.Open(filepath.Join(s.dir, feedbackFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var feedback []Feedback
+	scanner := bufio.NewScanner(file)
+	// The values of a result can be longer than the default line limit
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var entry Feedback
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if !entry.Timestamp.Before(since) {
+			feedback = append(feedback, entry)
+		}
+	}
+	return feedback, scanner.Err()
+}
@@ -0,0 +1,152 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// History backends of spec.history.backend of the NodeCheckOperatorConfig
+const (
+	BackendStatus     = "Status"
+	BackendMemory     = "Memory"
+	BackendFile       = "File"
+	BackendSQLite     = "SQLite"
+	BackendPrometheus = "Prometheus"
+)
+
+// Defaults of spec.history
+const (
+	DefaultRetention = 7 * 24 * time.Hour
+	DefaultPath      = "/var/lib/node-check-operator/history"
+)
+
+// Key identifies the NodeCheck a history belongs to
+type Key struct {
+	Namespace string
+	Name      string
+}
+
+// Entry is a past result of a check
+type Entry struct {
+	Check     string    `json:"check"`
+	Timestamp time.Time `json:"timestamp"`
+	Status    string    `json:"status"`
+}
+
+// Store keeps the check history of the NodeChecks beyond the spec.historySize entries of their status
+type Store interface {
+	// Backend returns the name of the backend (BackendMemory, BackendFile, ...)
+	Backend() string
+	// Record stores the entries of a NodeCheck of a node. Entries already stored (not newer than the last
+	// stored entry of their check) are ignored, so the whole status.history can be recorded on every update.
+	Record(ctx context.Context, key Key, node string, entries []Entry) error
+	// Query returns the entries of a NodeCheck since a time, oldest first
+	Query(ctx context.Context, key Key, since time.Time) ([]Entry, error)
+	// Forget drops the history of a deleted NodeCheck
+	Forget(key Key)
+}
+
+// StatusEntries returns the entries of status.history of a NodeCheck
+func StatusEntries(history []v1alpha1.CheckHistory) []Entry {
+	var entries []Entry
+	for _, check := range history {
+		for _, entry := range check.Entries {
+			entries = append(entries, Entry{Check: check.Name, Timestamp: entry.Timestamp.Time, Status: entry.Status})
+		}
+	}
+	return entries
+}
+
+var (
+	mu      sync.RWMutex
+	current Store
+	// configured is the spec.history the current store was created from
+	configured v1alpha1.HistoryConfig
+)
+
+// Current returns the history store of the operator, nil when the history is only kept in status.history
+func Current() Store {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// Configure creates the store of spec.history, unless the current store was created from the same spec.
+// A nil spec (or the Status backend) removes the store. It returns whether the store changed.
+func Configure(spec *v1alpha1.HistoryConfig) (bool, error) {
+	wanted := v1alpha1.HistoryConfig{Backend: BackendStatus}
+	if spec != nil {
+		spec.DeepCopyInto(&wanted)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if sameConfig(configured, wanted) {
+		return false, nil
+	}
+
+	retention := DefaultRetention
+	if wanted.Retention != nil && wanted.Retention.Duration > 0 {
+		retention = wanted.Retention.Duration
+	}
+	var store Store
+	var err error
+	switch wanted.Backend {
+	case BackendStatus, "":
+	case BackendMemory:
+		store = NewMemoryStore(retention)
+	case BackendFile:
+		path := wanted.Path
+		if path == "" {
+			path = DefaultPath
+		}
+		store, err = NewFileStore(path, retention)
+	case BackendSQLite:
+		path := wanted.Path
+		if path == "" {
+			path = DefaultPath
+		}
+		store, err = NewSQLiteStore(path, retention)
+	case BackendPrometheus:
+		store, err = NewPrometheusStore(wanted.PrometheusURL)
+	default:
+		err = fmt.Errorf("unknown history backend %q", wanted.Backend)
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, ok := current.(*PrometheusStore); ok {
+		historyStatusGauge.Reset()
+	}
+	if previous, ok := current.(*SQLiteStore); ok {
+		previous.Close()
+	}
+	current = store
+	configured = wanted
+	return true, nil
+}
+
+// sameConfig compares two spec.history
+func sameConfig(a, b v1alpha1.HistoryConfig) bool {
+	retention := func(c v1alpha1.HistoryConfig) time.Duration {
+		if c.Retention == nil {
+			return 0
+		}
+		return c.Retention.Duration
+	}
+	return a.Backend == b.Backend && a.Path == b.Path && a.PrometheusURL == b.PrometheusURL && retention(a) == retention(b)
+}
+
+// lastTimestamps returns the timestamp of the last entry of each check
+func lastTimestamps(entries []Entry) map[string]time.Time {
+	last := make(map[string]time.Time)
+	for _, entry := range entries {
+		if entry.Timestamp.After(last[entry.Check]) {
+			last[entry.Check] = entry.Timestamp
+		}
+	}
+	return last
+}
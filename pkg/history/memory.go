@@ -0,0 +1,71 @@
+package history
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps the history, and the false-positive feedback, in the operator process. It is lost
+// when the operator restarts.
+type MemoryStore struct {
+	*memoryFeedback
+	retention time.Duration
+
+	mu      sync.Mutex
+	entries map[Key][]Entry
+}
+
+// NewMemoryStore returns a MemoryStore keeping the entries of the last retention
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{
+		memoryFeedback: &memoryFeedback{retention: retention},
+		retention:      retention,
+		entries:        make(map[Key][]Entry),
+	}
+}
+
+// Backend returns BackendMemory
+func (s *MemoryStore) Backend() string {
+	return BackendMemory
+}
+
+// Record appends the new entries of a NodeCheck and drops the ones older than the retention
+func (s *MemoryStore) Record(ctx context.Context, key Key, node string, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored := s.entries[key]
+	last := lastTimestamps(stored)
+	for _, entry := range entries {
+		if entry.Timestamp.After(last[entry.Check]) {
+			stored = append(stored, entry)
+		}
+	}
+	sort.SliceStable(stored, func(i, j int) bool { return stored[i].Timestamp.Before(stored[j].Timestamp) })
+
+	cutoff := time.Now().Add(-s.retention)
+	first := sort.Search(len(stored), func(i int) bool { return !stored[i].Timestamp.Before(cutoff) })
+	s.entries[key] = append([]Entry(nil), stored[first:]...)
+	return nil
+}
+
+// Query returns the entries of a NodeCheck since a time
+func (s *MemoryStore) Query(ctx context.Context, key Key, since time.Time) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var entries []Entry
+	for _, entry := range s.entries[key] {
+		if !entry.Timestamp.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// Forget drops the entries of a deleted NodeCheck
+func (s *MemoryStore) Forget(key Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+}
@@ -0,0 +1,176 @@
+package history
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/albertofilice/node-check-operator/pkg/maintenance"
+)
+
+const (
+	// serviceAccountToken and serviceCA authenticate the operator to Prometheus (e.g. thanos-querier)
+	serviceAccountToken = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	serviceCA           = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+
+	// prometheusTimeout bounds a query
+	prometheusTimeout = 30 * time.Second
+	// maxQueryPoints bounds the samples of a series returned by a query (Prometheus rejects more than 11000)
+	maxQueryPoints = 1000
+)
+
+// prometheusStatuses are the statuses exported by historyStatusGauge, indexed by value
+var prometheusStatuses = []string{"Healthy", "Warning", "Critical", "Unknown", maintenance.StatusSuppressed}
+
+// historyStatusGauge exports the latest status of each check for the Prometheus backend
+var historyStatusGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "nodecheck_check_history_status",
+	Help: "Latest status of a check of a NodeCheck: 0 Healthy, 1 Warning, 2 Critical, 3 Unknown, 4 Suppressed",
+}, []string{"namespace", "nodecheck", "node", "check"})
+
+func init() {
+	metrics.Registry.MustRegister(historyStatusGauge)
+}
+
+// PrometheusStore exports the latest status of each check as the nodecheck_check_history_status metric,
+// and reads the history back from the Prometheus API. Prometheus keeps the history, with its own retention.
+type PrometheusStore struct {
+	url  string
+	http *http.Client
+}
+
+// NewPrometheusStore returns a PrometheusStore querying the Prometheus API at rawURL
+func NewPrometheusStore(rawURL string) (*PrometheusStore, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid prometheusURL %q", rawURL)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if ca, err := os.ReadFile(serviceCA); err == nil {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(ca)
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &PrometheusStore{
+		url:  strings.TrimSuffix(rawURL, "/"),
+		http: &http.Client{Transport: transport, Timeout: prometheusTimeout},
+	}, nil
+}
+
+// Backend returns BackendPrometheus
+func (s *PrometheusStore) Backend() string {
+	return BackendPrometheus
+}
+
+// Record exports the latest entry of each check of a NodeCheck
+func (s *PrometheusStore) Record(ctx context.Context, key Key, node string, entries []Entry) error {
+	latest := make(map[string]Entry)
+	for _, entry := range entries {
+		if entry.Timestamp.After(latest[entry.Check].Timestamp) {
+			latest[entry.Check] = entry
+		}
+	}
+	for check, entry := range latest {
+		value := 3
+		for i, status := range prometheusStatuses {
+			if status == entry.Status {
+				value = i
+			}
+		}
+		historyStatusGauge.WithLabelValues(key.Namespace, key.Name, node, check).Set(float64(value))
+	}
+	return nil
+}
+
+// Query reads the history of a NodeCheck with a range query. Only the first sample and the status
+// changes are returned, at the resolution of the query step.
+func (s *PrometheusStore) Query(ctx context.Context, key Key, since time.Time) ([]Entry, error) {
+	end := time.Now()
+	step := end.Sub(since) / maxQueryPoints
+	if step < time.Minute {
+		step = time.Minute
+	}
+	query := url.Values{}
+	query.Set("query", fmt.Sprintf(`max by (check) (nodecheck_check_history_status{namespace=%q,nodecheck=%q})`, key.Namespace, key.Name))
+	query.Set("start", strconv.FormatInt(since.Unix(), 10))
+	query.Set("end", strconv.FormatInt(end.Unix(), 10))
+	query.Set("step", strconv.Itoa(int(step.Seconds())))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+"/api/v1/query_range?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if token, err := os.ReadFile(serviceAccountToken); err == nil {
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("prometheus query: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	var response struct {
+		Status string `json:"status"`
+		Data   struct {
+			Result []struct {
+				Metric map[string]string    `json:"metric"`
+				Values [][2]json.RawMessage `json:"values"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("prometheus query: %v", err)
+	}
+
+	var entries []Entry
+	for _, series := range response.Data.Result {
+		previous := ""
+		for _, sample := range series.Values {
+			var timestamp float64
+			var value string
+			if json.Unmarshal(sample[0], &timestamp) != nil || json.Unmarshal(sample[1], &value) != nil {
+				continue
+			}
+			index, err := strconv.ParseFloat(value, 64)
+			if err != nil || index < 0 || int(index) >= len(prometheusStatuses) {
+				continue
+			}
+			status := prometheusStatuses[int(index)]
+			if status == previous {
+				continue
+			}
+			previous = status
+			entries = append(entries, Entry{
+				Check:     series.Metric["check"],
+				Timestamp: time.Unix(int64(timestamp), 0),
+				Status:    status,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Timestamp.Before(entries[j].Timestamp) })
+	return entries, nil
+}
+
+// Forget stops exporting the checks of a deleted NodeCheck. Prometheus keeps the past samples.
+func (s *PrometheusStore) Forget(key Key) {
+	historyStatusGauge.DeletePartialMatch(prometheus.Labels{"namespace": key.Namespace, "nodecheck": key.Name})
+}
@@ -0,0 +1,238 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	// Pure Go SQLite driver, the operator image is built without cgo
+	_ "modernc.org/sqlite"
+)
+
+// sqliteFile is the database of the SQLite backend under the directory
+const sqliteFile = "history.db"
+
+// sqlitePruning is how often the entries and the feedback older than the retention are deleted
+const sqlitePruning = time.Hour
+
+// sqliteSchema creates the tables of the SQLite backend. Timestamps are stored as Unix nanoseconds, so
+// they keep the precision of the results and compare as integers.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS entries (
+	namespace  TEXT NOT NULL,
+	name       TEXT NOT NULL,
+	node       TEXT NOT NULL,
+	check_name TEXT NOT NULL,
+	timestamp  INTEGER NOT NULL,
+	status     TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS entries_nodecheck ON entries (namespace, name, timestamp);
+CREATE TABLE IF NOT EXISTS feedback (
+	timestamp INTEGER NOT NULL,
+	feedback  TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS feedback_timestamp ON feedback (timestamp);
+`
+
+// SQLiteStore keeps the history, and the false-positive feedback, in a SQLite database under a
+// directory, typically on a PersistentVolumeClaim mounted in the operator Deployment so that it
+// survives restarts. Unlike the File backend, a query only reads the entries of its window.
+type SQLiteStore struct {
+	db        *sql.DB
+	retention time.Duration
+
+	mu sync.Mutex
+	// last is the timestamp of the last stored entry of each check, read from the database on first use
+	last map[Key]map[string]time.Time
+	// pruned is when the old entries and feedback were last deleted
+	pruned time.Time
+}
+
+// NewSQLiteStore opens, or creates, the database of a SQLiteStore under dir, which is created if needed
+func NewSQLiteStore(dir string, retention time.Duration) (*SQLiteStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("history directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, sqliteFile)
+	// WAL lets the trend endpoints read while the results are recorded; busy_timeout waits for the
+	// lock instead of failing when they meet
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("history database %s: %w", path, err)
+	}
+	// A single connection serializes the writes, which SQLite does anyway
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("history database %s: %w", path, err)
+	}
+	return &SQLiteStore{
+		db:        db,
+		retention: retention,
+		last:      make(map[Key]map[string]time.Time),
+	}, nil
+}
+
+// Backend returns BackendSQLite
+func (s *SQLiteStore) Backend() string {
+	return BackendSQLite
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Record inserts the new entries of a NodeCheck in one transaction
+func (s *SQLiteStore) Record(ctx context.Context, key Key, node string, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last, ok := s.last[key]
+	if !ok {
+		var err error
+		if last, err = s.lastTimestamps(ctx, key); err != nil {
+			return err
+		}
+		s.last[key] = last
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	recorded := make(map[string]time.Time)
+	for _, entry := range entries {
+		if !entry.Timestamp.After(last[entry.Check]) {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO entries (namespace, name, node, check_name, timestamp, status) VALUES (?, ?, ?, ?, ?, ?)",
+			key.Namespace, key.Name, node, entry.Check, entry.Timestamp.UnixNano(), entry.Status); err != nil {
+			return err
+		}
+		if entry.Timestamp.After(recorded[entry.Check]) {
+			recorded[entry.Check] = entry.Timestamp
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	for check, timestamp := range recorded {
+		if timestamp.After(last[check]) {
+			last[check] = timestamp
+		}
+	}
+	return s.prune(ctx)
+}
+
+// lastTimestamps returns the timestamp of the last stored entry of each check of a NodeCheck
+func (s *SQLiteStore) lastTimestamps(ctx context.Context, key Key) (map[string]time.Time, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT check_name, MAX(timestamp) FROM entries WHERE namespace = ? AND name = ? GROUP BY check_name",
+		key.Namespace, key.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	last := make(map[string]time.Time)
+	for rows.Next() {
+		var check string
+		var timestamp int64
+		if err := rows.Scan(&check, &timestamp); err != nil {
+			return nil, err
+		}
+		last[check] = time.Unix(0, timestamp)
+	}
+	return last, rows.Err()
+}
+
+// prune deletes the entries and the feedback older than the retention, at most every sqlitePruning
+func (s *SQLiteStore) prune(ctx context.Context) error {
+	if time.Since(s.pruned) < sqlitePruning {
+		return nil
+	}
+	cutoff := time.Now().Add(-s.retention).UnixNano()
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM entries WHERE timestamp < ?", cutoff); err != nil {
+		return err
+	}
+	if _, err := s.db.ExecContext(ctx, "DELETE FROM feedback WHERE timestamp < ?", cutoff); err != nil {
+		return err
+	}
+	s.pruned = time.Now()
+	return nil
+}
+
+// Query returns the entries of a NodeCheck since a time
+func (s *SQLiteStore) Query(ctx context.Context, key Key, since time.Time) ([]Entry, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT check_name, timestamp, status FROM entries WHERE namespace = ? AND name = ? AND timestamp >= ? ORDER BY timestamp",
+		key.Namespace, key.Name, since.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		var timestamp int64
+		if err := rows.Scan(&entry.Check, &timestamp, &entry.Status); err != nil {
+			return nil, err
+		}
+		entry.Timestamp = time.Unix(0, timestamp)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// Forget deletes the entries of a deleted NodeCheck
+func (s *SQLiteStore) Forget(key Key) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.last, key)
+	if _, err := s.db.Exec("DELETE FROM entries WHERE namespace = ? AND name = ?", key.Namespace, key.Name); err != nil {
+		fmt.Printf("Unable to delete the history of NodeCheck %s/%s: %v\n", key.Namespace, key.Name, err)
+	}
+}
+
+// RecordFeedback inserts a feedback
+func (s *SQLiteStore) RecordFeedback(ctx context.Context, feedback Feedback) error {
+	data, err := json.Marshal(feedback)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.db.ExecContext(ctx, "INSERT INTO feedback (timestamp, feedback) VALUES (?, ?)",
+		feedback.Timestamp.UnixNano(), string(data)); err != nil {
+		return err
+	}
+	return s.prune(ctx)
+}
+
+// QueryFeedback returns the feedback given since a time
+func (s *SQLiteStore) QueryFeedback(ctx context.Context, since time.Time) ([]Feedback, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT feedback FROM feedback WHERE timestamp >= ? ORDER BY timestamp", since.UnixNano())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var feedback []Feedback
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var entry Feedback
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			continue
+		}
+		feedback = append(feedback, entry)
+	}
+	return feedback, rows.Err()
+}
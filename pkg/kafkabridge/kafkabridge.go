@@ -0,0 +1,91 @@
+// Package kafkabridge produces Kafka records through the HTTP API of a Kafka bridge (Strimzi Kafka Bridge),
+// so the operator and the executors can write to Kafka without a Kafka client.
+package kafkabridge
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Embedded formats of the bridge: the record keys and values are JSON, or base64 encoded bytes
+const (
+	contentTypeJSON   = "application/vnd.kafka.json.v2+json"
+	contentTypeBinary = "application/vnd.kafka.binary.v2+json"
+)
+
+// Record is a record to produce. With the JSON format, Value is marshalled as JSON; with the binary
+// format it must be a []byte.
+type Record struct {
+	Key     string
+	Value   interface{}
+	Headers map[string]string
+}
+
+// header is a record header of the bridge API, with a base64 encoded value
+type header struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// record is a record of the bridge API
+type record struct {
+	Key     interface{} `json:"key,omitempty"`
+	Value   interface{} `json:"value"`
+	Headers []header    `json:"headers,omitempty"`
+}
+
+// Produce sends records to a topic through the bridge at bridgeURL. binary selects the binary embedded
+// format; authorization, when set, is the Authorization header.
+func Produce(ctx context.Context, bridgeURL, topic, authorization string, binary bool, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	payload := make([]record, 0, len(records))
+	for _, r := range records {
+		out := record{Value: r.Value}
+		if r.Key != "" {
+			out.Key = r.Key
+			if binary {
+				// []byte is marshalled as base64
+				out.Key = []byte(r.Key)
+			}
+		}
+		for key, value := range r.Headers {
+			out.Headers = append(out.Headers, header{Key: key, Value: base64.StdEncoding.EncodeToString([]byte(value))})
+		}
+		payload = append(payload, out)
+	}
+	body, err := json.Marshal(map[string]interface{}{"records": payload})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/topics/%s", strings.TrimSuffix(bridgeURL, "/"), topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+	if binary {
+		req.Header.Set("Content-Type", contentTypeBinary)
+	}
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("POST %s: %s: %s", url, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
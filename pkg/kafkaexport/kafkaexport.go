@@ -0,0 +1,116 @@
+// Package kafkaexport streams the check results of the NodeChecks to a Kafka topic, for the organizations
+// piping infrastructure telemetry into a central data lake. Each result is a record keyed by node,
+// serialized as JSON or Avro (binary encoding of Schema), produced through a Kafka bridge.
+package kafkaexport
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/kafkabridge"
+)
+
+// Serializations of spec.kafkaExport.serialization
+const (
+	SerializationJSON = "JSON"
+	SerializationAvro = "Avro"
+)
+
+// Schema is the Avro schema of the records. The details are a JSON document, as their keys differ
+// between checks.
+const Schema = `{"type":"record","name":"CheckResult","namespace":"io.nodecheck","fields":[` +
+	`{"name":"node","type":"string"},` +
+	`{"name":"namespace","type":"string"},` +
+	`{"name":"nodeCheck","type":"string"},` +
+	`{"name":"category","type":"string"},` +
+	`{"name":"check","type":"string"},` +
+	`{"name":"status","type":"string"},` +
+	`{"name":"message","type":"string"},` +
+	`{"name":"command","type":"string"},` +
+	`{"name":"timestamp","type":{"type":"long","logicalType":"timestamp-millis"}},` +
+	`{"name":"details","type":"string"}]}`
+
+// Record is a check result of a NodeCheck, as serialized with JSON
+type Record struct {
+	Node      string          `json:"node"`
+	Namespace string          `json:"namespace"`
+	NodeCheck string          `json:"nodeCheck"`
+	Category  string          `json:"category"`
+	Check     string          `json:"check"`
+	Status    string          `json:"status"`
+	Message   string          `json:"message"`
+	Command   string          `json:"command"`
+	Timestamp int64           `json:"timestamp"`
+	Details   json.RawMessage `json:"details,omitempty"`
+}
+
+// NewRecord returns the record of a check result. The timestamp is in milliseconds since the epoch.
+func NewRecord(nodeCheck *v1alpha1.NodeCheck, category, check string, result v1alpha1.CheckResult) Record {
+	record := Record{
+		Node:      nodeCheck.Status.NodeName,
+		Namespace: nodeCheck.Namespace,
+		NodeCheck: nodeCheck.Name,
+		Category:  category,
+		Check:     check,
+		Status:    result.Status,
+		Message:   result.Message,
+		Command:   result.Command,
+		Timestamp: result.Timestamp.UnixMilli(),
+	}
+	if len(result.Details.Raw) > 0 {
+		record.Details = json.RawMessage(result.Details.Raw)
+	}
+	return record
+}
+
+// Produce streams records to a topic through the Kafka bridge at bridgeURL. With Avro and a schemaID,
+// the values are framed in the wire format of the Confluent Schema Registry (magic byte 0 and the
+// 4-byte ID of Schema in the registry).
+func Produce(ctx context.Context, bridgeURL, topic, authorization, serialization string, schemaID int32, records []Record) error {
+	out := make([]kafkabridge.Record, 0, len(records))
+	switch serialization {
+	case "", SerializationJSON:
+		for _, record := range records {
+			out = append(out, kafkabridge.Record{Key: record.Node, Value: record})
+		}
+		return kafkabridge.Produce(ctx, bridgeURL, topic, authorization, false, out)
+	case SerializationAvro:
+		for _, record := range records {
+			var value []byte
+			if schemaID > 0 {
+				value = []byte{0, 0, 0, 0, 0}
+				binary.BigEndian.PutUint32(value[1:], uint32(schemaID))
+			}
+			out = append(out, kafkabridge.Record{Key: record.Node, Value: appendAvro(value, record)})
+		}
+		return kafkabridge.Produce(ctx, bridgeURL, topic, authorization, true, out)
+	}
+	return fmt.Errorf("unknown serialization %q", serialization)
+}
+
+// appendAvro appends the Avro binary encoding of a record, in the field order of Schema
+func appendAvro(buf []byte, record Record) []byte {
+	details := string(record.Details)
+	if details == "" {
+		details = "{}"
+	}
+	for _, field := range []string{record.Node, record.Namespace, record.NodeCheck, record.Category, record.Check,
+		record.Status, record.Message, record.Command} {
+		buf = appendAvroString(buf, field)
+	}
+	buf = appendAvroLong(buf, record.Timestamp)
+	return appendAvroString(buf, details)
+}
+
+// appendAvroLong appends a long: a zig-zag encoded variable-length integer
+func appendAvroLong(buf []byte, value int64) []byte {
+	return binary.AppendUvarint(buf, uint64((value<<1)^(value>>63)))
+}
+
+// appendAvroString appends a string: its length in bytes as a long, then its UTF-8 bytes
+func appendAvroString(buf []byte, value string) []byte {
+	return append(appendAvroLong(buf, int64(len(value))), value...)
+}
@@ -0,0 +1,124 @@
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed 5-field cron expression
+type cronSchedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+	// Standard cron semantics: if both day fields are restricted, either may match. As in Vixie cron, a
+	// field starting with "*" (including "*/N") is not restricted.
+	domRestricted bool
+	dowRestricted bool
+}
+
+// parseCronSchedule parses a standard 5-field cron expression.
+// Supported syntax per field: "*", single values, ranges ("1-5"), lists ("1,15") and steps ("*/15", "0-30/5").
+func parseCronSchedule(expr string) (*cronSchedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron schedule %q must have 5 fields, got %d", expr, len(fields))
+	}
+
+	minutes, err := parseCronField(fields[0], 0, 59)
+	if err != nil {
+		return nil, fmt.Errorf("invalid minute field: %v", err)
+	}
+	hours, err := parseCronField(fields[1], 0, 23)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hour field: %v", err)
+	}
+	daysOfMonth, err := parseCronField(fields[2], 1, 31)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day-of-month field: %v", err)
+	}
+	months, err := parseCronField(fields[3], 1, 12)
+	if err != nil {
+		return nil, fmt.Errorf("invalid month field: %v", err)
+	}
+	daysOfWeek, err := parseCronField(fields[4], 0, 7)
+	if err != nil {
+		return nil, fmt.Errorf("invalid day-of-week field: %v", err)
+	}
+	// 7 is an alias for Sunday
+	if daysOfWeek[7] {
+		daysOfWeek[0] = true
+	}
+
+	return &cronSchedule{
+		minutes:       minutes,
+		hours:         hours,
+		daysOfMonth:   daysOfMonth,
+		months:        months,
+		daysOfWeek:    daysOfWeek,
+		domRestricted: !strings.HasPrefix(fields[2], "*"),
+		dowRestricted: !strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+// parseCronField parses a single cron field into the set of matching values
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if idx := strings.Index(part, "/"); idx >= 0 {
+			s, err := strconv.Atoi(part[idx+1:])
+			if err != nil || s <= 0 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+			step = s
+			part = part[:idx]
+		}
+
+		start, end := min, max
+		if part != "*" {
+			if idx := strings.Index(part, "-"); idx >= 0 {
+				s, err := strconv.Atoi(part[:idx])
+				if err != nil {
+					return nil, fmt.Errorf("invalid range start in %q", part)
+				}
+				e, err := strconv.Atoi(part[idx+1:])
+				if err != nil {
+					return nil, fmt.Errorf("invalid range end in %q", part)
+				}
+				start, end = s, e
+			} else {
+				v, err := strconv.Atoi(part)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value %q", part)
+				}
+				start, end = v, v
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return nil, fmt.Errorf("value out of range [%d-%d] in %q", min, max, field)
+		}
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+// matches reports whether the given time (truncated to the minute) matches the schedule
+func (cs *cronSchedule) matches(t time.Time) bool {
+	if !cs.minutes[t.Minute()] || !cs.hours[t.Hour()] || !cs.months[int(t.Month())] {
+		return false
+	}
+
+	domMatch := cs.daysOfMonth[t.Day()]
+	dowMatch := cs.daysOfWeek[int(t.Weekday())]
+	if cs.domRestricted && cs.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
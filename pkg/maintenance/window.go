@@ -0,0 +1,122 @@
+package maintenance
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// StatusSuppressed is the status assigned to check results covered by an active maintenance window
+const StatusSuppressed = "Suppressed"
+
+// maxScheduleLookback bounds how far back a recurring window start is searched for
+const maxScheduleLookback = 7 * 24 * time.Hour
+
+// ActiveWindows returns the suppression windows that are active at the given time.
+// Windows with an invalid schedule are skipped and reported in the returned error.
+func ActiveWindows(windows []v1alpha1.SuppressionWindow, now time.Time) ([]v1alpha1.SuppressionWindow, error) {
+	active := []v1alpha1.SuppressionWindow{}
+	invalid := []string{}
+
+	for _, window := range windows {
+		isActive, err := IsActive(window, now)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s: %v", window.Name, err))
+			continue
+		}
+		if isActive {
+			active = append(active, window)
+		}
+	}
+
+	if len(invalid) > 0 {
+		return active, fmt.Errorf("invalid suppression windows: %s", strings.Join(invalid, "; "))
+	}
+	return active, nil
+}
+
+// IsActive reports whether a single suppression window is active at the given time
+func IsActive(window v1alpha1.SuppressionWindow, now time.Time) (bool, error) {
+	// Fixed start/end window
+	if window.Start != nil || window.End != nil {
+		if window.Start != nil && now.Before(window.Start.Time) {
+			return false, nil
+		}
+		if window.End != nil && !now.Before(window.End.Time) {
+			return false, nil
+		}
+		if window.Schedule == "" {
+			return true, nil
+		}
+	}
+
+	if window.Schedule == "" {
+		return false, fmt.Errorf("either start/end or schedule must be set")
+	}
+
+	// Recurring cron window
+	schedule, err := parseCronSchedule(window.Schedule)
+	if err != nil {
+		return false, err
+	}
+	duration := window.Duration.Duration
+	if duration <= 0 {
+		return false, fmt.Errorf("duration must be set for scheduled windows")
+	}
+	if duration > maxScheduleLookback {
+		duration = maxScheduleLookback
+	}
+
+	// Walk back minute by minute looking for a schedule match within the window duration
+	current := now.UTC().Truncate(time.Minute)
+	earliest := now.UTC().Add(-duration)
+	for !current.Before(earliest) {
+		if schedule.matches(current) {
+			return true, nil
+		}
+		current = current.Add(-time.Minute)
+	}
+	return false, nil
+}
+
+// AppliesTo reports whether the window suppresses the given check name
+func AppliesTo(window v1alpha1.SuppressionWindow, checkName string) bool {
+	if len(window.Checks) == 0 {
+		return true
+	}
+	for _, name := range window.Checks {
+		if name == checkName {
+			return true
+		}
+	}
+	return false
+}
+
+// SuppressResult marks a check result as suppressed if one of the active windows applies to it.
+// The original status is preserved in the message. Returns true if the result was suppressed.
+func SuppressResult(checkName string, result *v1alpha1.CheckResult, active []v1alpha1.SuppressionWindow) bool {
+	if result.Status == StatusSuppressed {
+		// Already suppressed (e.g. a result kept from a previous run)
+		return true
+	}
+	for _, window := range active {
+		if !AppliesTo(window, checkName) {
+			continue
+		}
+		result.Message = fmt.Sprintf("[suppressed by %s, was %s] %s", window.Name, result.Status, result.Message)
+		result.Status = StatusSuppressed
+		return true
+	}
+	return false
+}
+
+// WindowNames returns a comma-separated list of window names
+func WindowNames(windows []v1alpha1.SuppressionWindow) string {
+	names := make([]string, 0, len(windows))
+	for _, window := range windows {
+		names = append(names, window.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -63,6 +63,24 @@ var (
 		Name: "nodecheck_load_average_15m",
 		Help: "15-minute load average for a node",
 	}, []string{"node"})
+
+	// resultLabelInfoGauge exposes spec.resultLabels (one series per label, always 1) to join with other metrics
+	resultLabelInfoGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nodecheck_result_label_info",
+		Help: "Result labels (spec.resultLabels) of the NodeCheck of a node, e.g. team or environment",
+	}, []string{"node", "label", "value"})
+
+	// Configuration compliance (drift checks), kept apart from the runtime health
+	complianceScoreGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nodecheck_compliance_score_percent",
+		Help: "Percentage of the configuration compliance checks of a node that are Healthy",
+	}, []string{"node"})
+
+	// A vector without labels, so the series is absent rather than 0 when no node ran a compliance check
+	fleetComplianceScoreGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "nodecheck_fleet_compliance_score_percent",
+		Help: "Percentage of the configuration compliance checks of all the nodes that are Healthy",
+	}, []string{})
 )
 
 func init() {
@@ -79,6 +97,9 @@ func init() {
 		loadAverage1mGauge,
 		loadAverage5mGauge,
 		loadAverage15mGauge,
+		resultLabelInfoGauge,
+		complianceScoreGauge,
+		fleetComplianceScoreGauge,
 	)
 }
 
@@ -89,6 +110,8 @@ type DashboardSnapshot struct {
 	NodeStatus      map[string]int
 	Checks          []CheckStatusSnapshot
 	Nodes           []NodeMetricsSnapshot
+	// ComplianceScore is the configuration compliance of the fleet, nil when no node was evaluated
+	ComplianceScore *float64
 }
 
 // CheckStatusSnapshot contains the counters for a single check across all nodes.
@@ -108,6 +131,8 @@ type NodeMetricsSnapshot struct {
 	LoadAverage1m *float64
 	LoadAverage5m *float64
 	LoadAverage15m *float64
+	ResultLabels   map[string]string // spec.resultLabels of the node's NodeChecks
+	ComplianceScore *float64         // Percentage of the compliance checks that are Healthy
 }
 
 // UpdateDashboardMetrics publishes the provided snapshot to the Prometheus metrics exposed by the controller-runtime server.
@@ -142,8 +167,18 @@ func UpdateDashboardMetrics(snapshot DashboardSnapshot) {
 	loadAverage1mGauge.Reset()
 	loadAverage5mGauge.Reset()
 	loadAverage15mGauge.Reset()
+	resultLabelInfoGauge.Reset()
+	complianceScoreGauge.Reset()
+
+	fleetComplianceScoreGauge.Reset()
+	if snapshot.ComplianceScore != nil {
+		fleetComplianceScoreGauge.WithLabelValues().Set(*snapshot.ComplianceScore)
+	}
 
 	for _, node := range snapshot.Nodes {
+		for label, value := range node.ResultLabels {
+			resultLabelInfoGauge.WithLabelValues(node.NodeName, label, value).Set(1)
+		}
 		if node.Temperature != nil {
 			temperatureGauge.WithLabelValues(node.NodeName).Set(*node.Temperature)
 		}
@@ -165,6 +200,26 @@ func UpdateDashboardMetrics(snapshot DashboardSnapshot) {
 		if node.LoadAverage15m != nil {
 			loadAverage15mGauge.WithLabelValues(node.NodeName).Set(*node.LoadAverage15m)
 		}
+		if node.ComplianceScore != nil {
+			complianceScoreGauge.WithLabelValues(node.NodeName).Set(*node.ComplianceScore)
+		}
 	}
 }
 
+// DeleteNodeMetrics removes the series of a node from the node-level metrics, e.g. when its NodeCheck is deleted.
+// The series come back on the next dashboard refresh if the node is still reported by another NodeCheck.
+func DeleteNodeMetrics(nodeName string) {
+	for _, gauge := range []*prometheus.GaugeVec{
+		temperatureGauge,
+		cpuUsageGauge,
+		memoryUsageGauge,
+		uptimeGauge,
+		loadAverage1mGauge,
+		loadAverage5mGauge,
+		loadAverage15mGauge,
+		complianceScoreGauge,
+	} {
+		gauge.DeleteLabelValues(nodeName)
+	}
+	resultLabelInfoGauge.DeletePartialMatch(prometheus.Labels{"node": nodeName})
+}
@@ -0,0 +1,138 @@
+package nodehealth
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	authConfigMapNamespace = "kube-system"
+	authConfigMapName      = "extension-apiserver-authentication"
+)
+
+// requestHeaderAuth holds the front-proxy (requestheader) configuration published by
+// kube-apiserver. Requests proxied by the aggregation layer carry a client certificate
+// signed by the requestheader CA plus the user identity in request headers.
+type requestHeaderAuth struct {
+	clientCAs       *x509.CertPool
+	allowedNames    []string
+	usernameHeaders []string
+	groupHeaders    []string
+}
+
+// userInfo is the identity extracted from the front-proxy headers
+type userInfo struct {
+	name   string
+	groups []string
+}
+
+// loadRequestHeaderAuth reads the requestheader configuration from kube-system/extension-apiserver-authentication
+func loadRequestHeaderAuth(ctx context.Context, clientset kubernetes.Interface) (*requestHeaderAuth, error) {
+	cm, err := clientset.CoreV1().ConfigMaps(authConfigMapNamespace).Get(ctx, authConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s/%s: %v", authConfigMapNamespace, authConfigMapName, err)
+	}
+
+	caPEM := cm.Data["requestheader-client-ca-file"]
+	if caPEM == "" {
+		return nil, fmt.Errorf("requestheader-client-ca-file not found in %s/%s", authConfigMapNamespace, authConfigMapName)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+		return nil, fmt.Errorf("requestheader-client-ca-file contains no valid certificates")
+	}
+
+	auth := &requestHeaderAuth{
+		clientCAs:       pool,
+		allowedNames:    parseStringList(cm.Data["requestheader-allowed-names"]),
+		usernameHeaders: parseStringList(cm.Data["requestheader-username-headers"]),
+		groupHeaders:    parseStringList(cm.Data["requestheader-group-headers"]),
+	}
+	if len(auth.usernameHeaders) == 0 {
+		auth.usernameHeaders = []string{"X-Remote-User"}
+	}
+	if len(auth.groupHeaders) == 0 {
+		auth.groupHeaders = []string{"X-Remote-Group"}
+	}
+	return auth, nil
+}
+
+// parseStringList parses the JSON string arrays stored in the authentication ConfigMap
+func parseStringList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(value), &list); err != nil {
+		return nil
+	}
+	return list
+}
+
+// authenticate verifies the request was proxied by kube-apiserver and returns the end user.
+// The TLS layer has already verified the client certificate against the requestheader CA.
+func (a *requestHeaderAuth) authenticate(r *http.Request) (*userInfo, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 || len(r.TLS.VerifiedChains[0]) == 0 {
+		return nil, fmt.Errorf("request is not authenticated by the aggregation layer")
+	}
+
+	if len(a.allowedNames) > 0 {
+		commonName := r.TLS.VerifiedChains[0][0].Subject.CommonName
+		allowed := false
+		for _, name := range a.allowedNames {
+			if name == commonName {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("client certificate common name %q is not allowed", commonName)
+		}
+	}
+
+	user := &userInfo{}
+	for _, header := range a.usernameHeaders {
+		if value := strings.TrimSpace(r.Header.Get(header)); value != "" {
+			user.name = value
+			break
+		}
+	}
+	if user.name == "" {
+		return nil, fmt.Errorf("no user found in request headers")
+	}
+	for _, header := range a.groupHeaders {
+		user.groups = append(user.groups, r.Header.Values(header)...)
+	}
+	return user, nil
+}
+
+// authorize asks kube-apiserver whether the user may perform the verb on nodehealths
+func authorize(ctx context.Context, clientset kubernetes.Interface, user *userInfo, verb, name string) (bool, string, error) {
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.name,
+			Groups: user.groups,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Group:    GroupName,
+				Version:  Version,
+				Resource: Resource,
+				Name:     name,
+				Verb:     verb,
+			},
+		},
+	}
+
+	result, err := clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", err
+	}
+	return result.Status.Allowed, result.Status.Reason, nil
+}
@@ -0,0 +1,149 @@
+package nodehealth
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/maintenance"
+)
+
+// statusSeverity orders overall statuses from best to worst
+var statusSeverity = map[string]int{
+	"Healthy":  0,
+	"Unknown":  1,
+	"Warning":  2,
+	"Critical": 3,
+}
+
+// BuildNodeHealths derives one NodeHealth per node from the given NodeChecks.
+// Wildcard template NodeChecks ("*" / "all") and NodeChecks without a node are skipped.
+func BuildNodeHealths(nodeChecks []v1alpha1.NodeCheck) []NodeHealth {
+	byNode := make(map[string]*NodeHealth)
+
+	for _, nc := range nodeChecks {
+		nodeName := nc.Spec.NodeName
+		if nodeName == "" || nodeName == "*" || nodeName == "all" {
+			continue
+		}
+
+		health, ok := byNode[nodeName]
+		if !ok {
+			health = &NodeHealth{
+				TypeMeta: metav1.TypeMeta{APIVersion: GroupVersion(), Kind: Kind},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              nodeName,
+					CreationTimestamp: nc.CreationTimestamp,
+				},
+				Status: NodeHealthStatus{
+					NodeName:   nodeName,
+					NodeChecks: []string{},
+				},
+			}
+			byNode[nodeName] = health
+		}
+
+		mergeNodeCheck(health, nc)
+	}
+
+	healths := make([]NodeHealth, 0, len(byNode))
+	for _, health := range byNode {
+		if health.Status.OverallStatus == "" {
+			health.Status.OverallStatus = "Unknown"
+		}
+		sort.Strings(health.Status.CriticalCheckNames)
+		sort.Strings(health.Status.NodeChecks)
+		healths = append(healths, *health)
+	}
+	sort.Slice(healths, func(i, j int) bool {
+		return healths[i].Name < healths[j].Name
+	})
+	return healths
+}
+
+// mergeNodeCheck folds a single NodeCheck into the node's health summary
+func mergeNodeCheck(health *NodeHealth, nc v1alpha1.NodeCheck) {
+	status := &health.Status
+	status.NodeChecks = append(status.NodeChecks, fmt.Sprintf("%s/%s", nc.Namespace, nc.Name))
+
+	// The oldest NodeCheck determines the age of the node health object
+	if nc.CreationTimestamp.Before(&health.CreationTimestamp) {
+		health.CreationTimestamp = nc.CreationTimestamp
+	}
+	if status.LastCheckTime.Before(&nc.Status.LastCheckTime) {
+		status.LastCheckTime = nc.Status.LastCheckTime
+	}
+	if nc.Status.SuppressedBy != "" && status.SuppressedBy == "" {
+		status.SuppressedBy = nc.Status.SuppressedBy
+	}
+
+	overall := nc.Status.OverallStatus
+	if overall == "" {
+		overall = "Unknown"
+	}
+	if status.OverallStatus == "" || statusSeverity[overall] > statusSeverity[status.OverallStatus] {
+		status.OverallStatus = overall
+		status.Message = nc.Status.Message
+	}
+
+	for name, checkStatus := range CollectCheckStatuses(nc.Status.CheckResults) {
+		status.CheckCount++
+		switch checkStatus {
+		case "Healthy":
+			status.HealthyChecks++
+		case "Warning":
+			status.WarningChecks++
+		case "Critical":
+			status.CriticalChecks++
+			status.CriticalCheckNames = append(status.CriticalCheckNames, name)
+		case maintenance.StatusSuppressed:
+			status.SuppressedChecks++
+		default:
+			status.UnknownChecks++
+		}
+	}
+}
+
+// CollectCheckStatuses returns the status of every individual check result, keyed by
+// its JSON path (e.g. "systemResults.disks.smart"). Walking the JSON form keeps this
+// in sync with CheckResults without enumerating every check.
+func CollectCheckStatuses(results v1alpha1.CheckResults) map[string]string {
+	statuses := make(map[string]string)
+
+	raw, err := json.Marshal(results)
+	if err != nil {
+		return statuses
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(raw, &tree); err != nil {
+		return statuses
+	}
+
+	walkCheckResults("", tree, statuses)
+	return statuses
+}
+
+// walkCheckResults recursively collects objects that look like a CheckResult
+func walkCheckResults(path string, node map[string]interface{}, statuses map[string]string) {
+	if status, ok := node["status"].(string); ok {
+		if _, hasTimestamp := node["timestamp"]; hasTimestamp {
+			statuses[path] = status
+			return
+		}
+	}
+
+	for key, value := range node {
+		child, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		childPath := key
+		if path != "" {
+			childPath = path + "." + key
+		}
+		walkCheckResults(childPath, child, statuses)
+	}
+}
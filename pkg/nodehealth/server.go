@@ -0,0 +1,362 @@
+package nodehealth
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultPort is the port the aggregated nodehealth API listens on
+	DefaultPort = 31683
+	// CertDir is where the serving certificate for the aggregated API is mounted
+	CertDir = "/etc/nodehealth-tls"
+
+	// authRefreshInterval controls how often the requestheader CA is re-read (it can be rotated)
+	authRefreshInterval = 10 * time.Minute
+)
+
+var log = ctrl.Log.WithName("nodehealth-api")
+
+// Server serves the read-only nodehealth aggregated API (health.nodecheck.openshift.io/v1alpha1).
+// It is registered with kube-apiserver through an APIService, which enables
+// "kubectl get nodehealth" with server-side printing columns.
+type Server struct {
+	server    *http.Server
+	k8sClient client.Client
+	clientset kubernetes.Interface
+	port      int
+
+	mu   sync.RWMutex
+	auth *requestHeaderAuth
+}
+
+// NewServer creates a new nodehealth API server
+func NewServer(k8sClient client.Client, clientset kubernetes.Interface, port int) *Server {
+	return &Server{
+		k8sClient: k8sClient,
+		clientset: clientset,
+		port:      port,
+	}
+}
+
+// Start starts the nodehealth API server over HTTPS
+func (s *Server) Start(ctx context.Context) error {
+	certPath := CertDir + "/tls.crt"
+	keyPath := CertDir + "/tls.key"
+	certificate, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		return fmt.Errorf("unable to load serving certificate from %s: %v", CertDir, err)
+	}
+
+	auth, err := loadRequestHeaderAuth(ctx, s.clientset)
+	if err != nil {
+		return err
+	}
+	s.setAuth(auth)
+	go s.refreshAuth(ctx)
+
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gin.Recovery())
+	s.setupRoutes(router)
+
+	s.server = &http.Server{
+		Addr:         fmt.Sprintf(":%d", s.port),
+		Handler:      router,
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion:   tls.VersionTLS12,
+			Certificates: []tls.Certificate{certificate},
+			// Client certificates are optional at the TLS layer so /healthz stays reachable,
+			// but every /apis request must present one signed by the requestheader CA
+			GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				return &tls.Config{
+					MinVersion:   tls.VersionTLS12,
+					Certificates: []tls.Certificate{certificate},
+					ClientAuth:   tls.VerifyClientCertIfGiven,
+					ClientCAs:    s.getAuth().clientCAs,
+				}, nil
+			},
+		},
+	}
+
+	go func() {
+		if err := s.server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			log.Error(err, "nodehealth API server error")
+		}
+	}()
+	log.Info("nodehealth API server started", "port", s.port, "groupVersion", GroupVersion())
+
+	return nil
+}
+
+// Stop stops the nodehealth API server
+func (s *Server) Stop(ctx context.Context) error {
+	if s.server != nil {
+		return s.server.Shutdown(ctx)
+	}
+	return nil
+}
+
+func (s *Server) setAuth(auth *requestHeaderAuth) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.auth = auth
+}
+
+func (s *Server) getAuth() *requestHeaderAuth {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.auth
+}
+
+// refreshAuth periodically reloads the requestheader configuration
+func (s *Server) refreshAuth(ctx context.Context) {
+	ticker := time.NewTicker(authRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			auth, err := loadRequestHeaderAuth(ctx, s.clientset)
+			if err != nil {
+				log.Error(err, "unable to refresh requestheader authentication, keeping previous configuration")
+				continue
+			}
+			s.setAuth(auth)
+		}
+	}
+}
+
+// setupRoutes registers discovery and resource endpoints
+func (s *Server) setupRoutes(router *gin.Engine) {
+	router.GET("/healthz", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	apis := router.Group("/apis", s.authenticate)
+	apis.GET("", s.getAPIGroupList)
+	apis.GET("/"+GroupName, s.getAPIGroup)
+	apis.GET("/"+GroupVersion(), s.getAPIResourceList)
+	apis.GET("/"+GroupVersion()+"/"+Resource, s.listNodeHealths)
+	apis.GET("/"+GroupVersion()+"/"+Resource+"/:name", s.getNodeHealth)
+}
+
+// authenticate rejects requests that were not proxied by kube-apiserver
+func (s *Server) authenticate(c *gin.Context) {
+	user, err := s.getAuth().authenticate(c.Request)
+	if err != nil {
+		writeStatus(c, http.StatusUnauthorized, metav1.StatusReasonUnauthorized, err.Error())
+		return
+	}
+	c.Set("user", user)
+	c.Next()
+}
+
+// authorizeRequest performs a SubjectAccessReview for the current user
+func (s *Server) authorizeRequest(c *gin.Context, verb, name string) bool {
+	user := c.MustGet("user").(*userInfo)
+	allowed, reason, err := authorize(c.Request.Context(), s.clientset, user, verb, name)
+	if err != nil {
+		log.Error(err, "SubjectAccessReview failed", "user", user.name)
+		writeStatus(c, http.StatusInternalServerError, metav1.StatusReasonInternalError, "unable to authorize request")
+		return false
+	}
+	if !allowed {
+		message := fmt.Sprintf("%s is forbidden: User %q cannot %s resource %q in API group %q", Resource, user.name, verb, Resource, GroupName)
+		if reason != "" {
+			message += ": " + reason
+		}
+		writeStatus(c, http.StatusForbidden, metav1.StatusReasonForbidden, message)
+		return false
+	}
+	return true
+}
+
+// getAPIGroupList serves /apis discovery
+func (s *Server) getAPIGroupList(c *gin.Context) {
+	group := apiGroup()
+	c.JSON(http.StatusOK, metav1.APIGroupList{
+		TypeMeta: metav1.TypeMeta{Kind: "APIGroupList", APIVersion: "v1"},
+		Groups:   []metav1.APIGroup{group},
+	})
+}
+
+// getAPIGroup serves /apis/<group> discovery
+func (s *Server) getAPIGroup(c *gin.Context) {
+	c.JSON(http.StatusOK, apiGroup())
+}
+
+// getAPIResourceList serves /apis/<group>/<version> discovery
+func (s *Server) getAPIResourceList(c *gin.Context) {
+	c.JSON(http.StatusOK, metav1.APIResourceList{
+		TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+		GroupVersion: GroupVersion(),
+		APIResources: []metav1.APIResource{
+			{
+				Name:         Resource,
+				SingularName: "nodehealth",
+				Namespaced:   false,
+				Kind:         Kind,
+				Verbs:        metav1.Verbs{"get", "list"},
+				ShortNames:   []string{"nh"},
+				Categories:   []string{"nodecheck"},
+			},
+		},
+	})
+}
+
+// apiGroup returns the discovery information for the nodehealth group
+func apiGroup() metav1.APIGroup {
+	version := metav1.GroupVersionForDiscovery{GroupVersion: GroupVersion(), Version: Version}
+	return metav1.APIGroup{
+		TypeMeta:         metav1.TypeMeta{Kind: "APIGroup", APIVersion: "v1"},
+		Name:             GroupName,
+		Versions:         []metav1.GroupVersionForDiscovery{version},
+		PreferredVersion: version,
+	}
+}
+
+// listNodeHealths serves GET /apis/<group>/<version>/nodehealths
+func (s *Server) listNodeHealths(c *gin.Context) {
+	if !s.authorizeRequest(c, "list", "") {
+		return
+	}
+
+	healths, err := s.nodeHealths(c.Request.Context())
+	if err != nil {
+		writeStatus(c, http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+		return
+	}
+
+	if wantsTable(c.Request) {
+		c.JSON(http.StatusOK, buildTable(healths))
+		return
+	}
+	c.JSON(http.StatusOK, NodeHealthList{
+		TypeMeta: metav1.TypeMeta{APIVersion: GroupVersion(), Kind: Kind + "List"},
+		Items:    healths,
+	})
+}
+
+// getNodeHealth serves GET /apis/<group>/<version>/nodehealths/<name>
+func (s *Server) getNodeHealth(c *gin.Context) {
+	name := c.Param("name")
+	if !s.authorizeRequest(c, "get", name) {
+		return
+	}
+
+	healths, err := s.nodeHealths(c.Request.Context())
+	if err != nil {
+		writeStatus(c, http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+		return
+	}
+
+	for _, health := range healths {
+		if health.Name != name {
+			continue
+		}
+		if wantsTable(c.Request) {
+			c.JSON(http.StatusOK, buildTable([]NodeHealth{health}))
+			return
+		}
+		c.JSON(http.StatusOK, health)
+		return
+	}
+
+	writeStatus(c, http.StatusNotFound, metav1.StatusReasonNotFound,
+		fmt.Sprintf("%s.%s %q not found", Resource, GroupName, name))
+}
+
+// nodeHealths lists all NodeChecks and derives the per-node health objects
+func (s *Server) nodeHealths(ctx context.Context) ([]NodeHealth, error) {
+	var nodeChecks v1alpha1.NodeCheckList
+	if err := s.k8sClient.List(ctx, &nodeChecks); err != nil {
+		return nil, fmt.Errorf("unable to list NodeChecks: %v", err)
+	}
+	return BuildNodeHealths(nodeChecks.Items), nil
+}
+
+// wantsTable reports whether the client asked for a server-side printed table (as kubectl does)
+func wantsTable(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		if strings.Contains(accept, "as=Table") && strings.Contains(accept, "g=meta.k8s.io") {
+			return true
+		}
+	}
+	return false
+}
+
+// buildTable renders NodeHealth objects as a meta.k8s.io/v1 Table
+func buildTable(healths []NodeHealth) *metav1.Table {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{Kind: "Table", APIVersion: "meta.k8s.io/v1"},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Node", Type: "string", Format: "name", Description: "Name of the node"},
+			{Name: "Status", Type: "string", Description: "Worst overall status across the node's NodeChecks"},
+			{Name: "Critical Checks", Type: "integer", Description: "Number of checks in Critical status"},
+			{Name: "Warning Checks", Type: "integer", Priority: 1, Description: "Number of checks in Warning status"},
+			{Name: "Last Check", Type: "date", Priority: 1, Description: "Time since the most recent check"},
+			{Name: "Message", Type: "string", Priority: 1, Description: "Message of the NodeCheck that determined the status"},
+			{Name: "Age", Type: "date", Description: "Time since the oldest NodeCheck for the node was created"},
+		},
+		Rows: []metav1.TableRow{},
+	}
+
+	for i := range healths {
+		health := &healths[i]
+		lastCheck := "<unknown>"
+		if !health.Status.LastCheckTime.IsZero() {
+			lastCheck = duration.HumanDuration(time.Since(health.Status.LastCheckTime.Time))
+		}
+
+		metadata, _ := json.Marshal(metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{Kind: "PartialObjectMetadata", APIVersion: "meta.k8s.io/v1"},
+			ObjectMeta: health.ObjectMeta,
+		})
+
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{
+				health.Name,
+				health.Status.OverallStatus,
+				health.Status.CriticalChecks,
+				health.Status.WarningChecks,
+				lastCheck,
+				health.Status.Message,
+				duration.HumanDuration(time.Since(health.CreationTimestamp.Time)),
+			},
+			Object: runtime.RawExtension{Raw: metadata},
+		})
+	}
+	return table
+}
+
+// writeStatus writes a metav1.Status error response and aborts the request
+func writeStatus(c *gin.Context, code int, reason metav1.StatusReason, message string) {
+	c.AbortWithStatusJSON(code, metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusFailure,
+		Message:  message,
+		Reason:   reason,
+		Code:     int32(code),
+	})
+}
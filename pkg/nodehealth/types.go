@@ -0,0 +1,70 @@
+package nodehealth
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// GroupName is the API group served by the aggregated nodehealth API
+	GroupName = "health.nodecheck.openshift.io"
+	// Version is the API version served by the aggregated nodehealth API
+	Version = "v1alpha1"
+	// Resource is the plural resource name
+	Resource = "nodehealths"
+	// Kind is the kind of a single NodeHealth object
+	Kind = "NodeHealth"
+)
+
+// GroupVersion returns the "group/version" string used in apiVersion fields
+func GroupVersion() string {
+	return GroupName + "/" + Version
+}
+
+// NodeHealth is a read-only, cluster-scoped view of the health of a single node,
+// derived from all NodeChecks that target that node. The object name is the node name.
+type NodeHealth struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status NodeHealthStatus `json:"status"`
+}
+
+// NodeHealthStatus summarizes the check results for a node
+type NodeHealthStatus struct {
+	// NodeName is the node this health summary refers to
+	NodeName string `json:"nodeName"`
+
+	// OverallStatus is the worst overall status across the node's NodeChecks
+	OverallStatus string `json:"overallStatus"`
+
+	// Message is the message of the NodeCheck that determined the overall status
+	Message string `json:"message,omitempty"`
+
+	// LastCheckTime is the most recent check time across the node's NodeChecks
+	LastCheckTime metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// SuppressedBy is the comma-separated names of the maintenance windows active during the last check
+	SuppressedBy string `json:"suppressedBy,omitempty"`
+
+	// Check counters across all individual check results
+	CheckCount       int `json:"checkCount"`
+	HealthyChecks    int `json:"healthyChecks"`
+	WarningChecks    int `json:"warningChecks"`
+	CriticalChecks   int `json:"criticalChecks"`
+	UnknownChecks    int `json:"unknownChecks"`
+	SuppressedChecks int `json:"suppressedChecks"`
+
+	// CriticalCheckNames lists the checks currently in Critical status (e.g. "systemResults.disks.smart")
+	CriticalCheckNames []string `json:"criticalCheckNames,omitempty"`
+
+	// NodeChecks lists the NodeChecks (namespace/name) this summary was derived from
+	NodeChecks []string `json:"nodeChecks"`
+}
+
+// NodeHealthList is a list of NodeHealth objects
+type NodeHealthList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []NodeHealth `json:"items"`
+}
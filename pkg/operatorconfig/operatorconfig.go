@@ -0,0 +1,180 @@
+package operatorconfig
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// Built-in defaults, used when neither the NodeCheckOperatorConfig nor the environment set a value
+const (
+	DefaultNamespace          = "node-check-operator-system"
+	DefaultOperatorImage      = "quay.io/rh_ee_afilice/node-check-operator:v1.0.8"
+	DefaultConsolePluginImage = "quay.io/rh_ee_afilice/node-check-operator-console-plugin:v1.0.8"
+	DefaultDashboardPort      = 31682
+)
+
+// Settings is the effective configuration of the operator
+type Settings struct {
+	WatchNamespace     string
+	OperatorImage      string
+	ConsolePluginImage string
+	DashboardPort      int
+	OpenShiftFeatures  bool
+	NodeHealthAPI      bool
+	FaultInjection     bool
+}
+
+// FromEnvironment returns the settings of the environment variables of the operator Deployment.
+// openShiftFeatures and nodeHealthAPI are the values of the command line flags, overridden by
+// ENABLE_OPENSHIFT_FEATURES and ENABLE_NODEHEALTH_API when they are set. Fault injection is only
+// enabled by ENABLE_FAULT_INJECTION.
+func FromEnvironment(openShiftFeatures, nodeHealthAPI bool) Settings {
+	settings := Settings{
+		WatchNamespace:     os.Getenv("WATCH_NAMESPACE"),
+		OperatorImage:      os.Getenv("OPERATOR_IMAGE"),
+		ConsolePluginImage: os.Getenv("CONSOLE_PLUGIN_IMAGE"),
+		DashboardPort:      DefaultDashboardPort,
+		OpenShiftFeatures:  openShiftFeatures,
+		NodeHealthAPI:      nodeHealthAPI,
+	}
+	if settings.WatchNamespace == "" {
+		settings.WatchNamespace = DefaultNamespace
+	}
+	if settings.OperatorImage == "" {
+		settings.OperatorImage = DefaultOperatorImage
+	}
+	if settings.ConsolePluginImage == "" {
+		settings.ConsolePluginImage = DefaultConsolePluginImage
+	}
+
+	if val := strings.ToLower(os.Getenv("ENABLE_OPENSHIFT_FEATURES")); val != "" {
+		switch val {
+		case "false", "0", "no", "disabled":
+			settings.OpenShiftFeatures = false
+		default:
+			settings.OpenShiftFeatures = true
+		}
+	}
+	if val := strings.ToLower(os.Getenv("ENABLE_NODEHEALTH_API")); val != "" {
+		switch val {
+		case "true", "1", "yes", "enabled":
+			settings.NodeHealthAPI = true
+		default:
+			settings.NodeHealthAPI = false
+		}
+	}
+	switch strings.ToLower(os.Getenv("ENABLE_FAULT_INJECTION")) {
+	case "true", "1", "yes", "enabled":
+		settings.FaultInjection = true
+	}
+	return settings
+}
+
+// Resolve applies the fields set in a NodeCheckOperatorConfig spec over the defaults
+func Resolve(defaults Settings, spec *v1alpha1.NodeCheckOperatorConfigSpec) Settings {
+	settings := defaults
+	if spec == nil {
+		return settings
+	}
+	if spec.WatchNamespace != "" {
+		settings.WatchNamespace = spec.WatchNamespace
+	}
+	if spec.OperatorImage != "" {
+		settings.OperatorImage = spec.OperatorImage
+	}
+	if spec.ConsolePluginImage != "" {
+		settings.ConsolePluginImage = spec.ConsolePluginImage
+	}
+	if spec.DashboardPort > 0 {
+		settings.DashboardPort = spec.DashboardPort
+	}
+	if gates := spec.FeatureGates; gates != nil {
+		if gates.OpenShiftFeatures != nil {
+			settings.OpenShiftFeatures = *gates.OpenShiftFeatures
+		}
+		if gates.NodeHealthAPI != nil {
+			settings.NodeHealthAPI = *gates.NodeHealthAPI
+		}
+		if gates.FaultInjection != nil {
+			settings.FaultInjection = *gates.FaultInjection
+		}
+	}
+	return settings
+}
+
+// Config gives the controllers the current settings: the NodeCheckOperatorConfig singleton applied
+// over the settings of the environment
+type Config struct {
+	defaults Settings
+
+	mu      sync.RWMutex
+	applied Settings
+	// ui is the ui section of the singleton read last, nil when it sets none
+	ui *v1alpha1.UIConfig
+}
+
+// New returns a Config falling back to the given settings while no NodeCheckOperatorConfig exists
+func New(defaults Settings) *Config {
+	return &Config{defaults: defaults, applied: defaults}
+}
+
+// Defaults returns the settings of the environment
+func (c *Config) Defaults() Settings {
+	return c.defaults
+}
+
+// Load reads the NodeCheckOperatorConfig singleton and returns the resulting settings. The
+// controllers call it on every reconcile, so a change of the singleton applies on their next
+// reconcile. The defaults are returned when the singleton (or its CRD) does not exist.
+// Load also keeps the ui section of the singleton, returned by UI.
+func (c *Config) Load(ctx context.Context, reader client.Reader) (Settings, error) {
+	var config v1alpha1.NodeCheckOperatorConfig
+	if err := reader.Get(ctx, client.ObjectKey{Name: v1alpha1.NodeCheckOperatorConfigName}, &config); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			c.setUI(nil)
+			return c.defaults, nil
+		}
+		return c.defaults, err
+	}
+	c.setUI(config.Spec.UI)
+	return Resolve(c.defaults, &config.Spec), nil
+}
+
+// UI returns a copy of the console plugin configuration of the singleton read last, nil when it
+// sets none
+func (c *Config) UI() *v1alpha1.UIConfig {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ui.DeepCopy()
+}
+
+// setUI records the ui section of the singleton
+func (c *Config) setUI(ui *v1alpha1.UIConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ui = ui.DeepCopy()
+}
+
+// Applied returns the settings the runtime components (dashboard, aggregated API) run with
+func (c *Config) Applied() Settings {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.applied
+}
+
+// SetApplied records the settings the runtime components run with and returns the previous ones
+func (c *Config) SetApplied(settings Settings) Settings {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	previous := c.applied
+	c.applied = settings
+	return previous
+}
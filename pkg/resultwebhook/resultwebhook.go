@@ -0,0 +1,103 @@
+// Package resultwebhook posts the raw check results of the NodeChecks to HTTP endpoints (SIEM, CMDB, ...):
+// a JSON document per NodeCheck and check category, holding the full CheckResult of every check of the
+// category that ran since the previous delivery.
+package resultwebhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// Header carries the name of the webhook in every request
+const Header = "X-NodeCheck-Webhook"
+
+// requestTimeout bounds every delivery
+const requestTimeout = 15 * time.Second
+
+var httpClient = &http.Client{Timeout: requestTimeout}
+
+// Payload is the document posted for the new results of a check category of a NodeCheck
+type Payload struct {
+	// ID identifies the delivery: it is the same when the same results are posted again (e.g. after a
+	// restart of the operator), so receivers can drop duplicates
+	ID        string                          `json:"id"`
+	Node      string                          `json:"node"`
+	Namespace string                          `json:"namespace"`
+	NodeCheck string                          `json:"nodeCheck"`
+	Category  string                          `json:"category"`
+	Results   map[string]v1alpha1.CheckResult `json:"results"`
+}
+
+// NewPayload returns the payload of the results of a check category of a NodeCheck
+func NewPayload(nodeCheck *v1alpha1.NodeCheck, category string, results map[string]v1alpha1.CheckResult) Payload {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s/%s/%s", nodeCheck.Namespace, nodeCheck.Name, category)
+	for _, name := range names {
+		fmt.Fprintf(hash, "/%s@%s", name, results[name].Timestamp.UTC().Format(time.RFC3339Nano))
+	}
+	return Payload{
+		ID:        hex.EncodeToString(hash.Sum(nil)[:16]),
+		Node:      nodeCheck.Status.NodeName,
+		Namespace: nodeCheck.Namespace,
+		NodeCheck: nodeCheck.Name,
+		Category:  category,
+		Results:   results,
+	}
+}
+
+// Covers reports whether a webhook posts the results of a check category
+func Covers(webhook *v1alpha1.ResultWebhookConfig, category string) bool {
+	if len(webhook.Categories) == 0 {
+		return true
+	}
+	for _, covered := range webhook.Categories {
+		if covered == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Post posts a payload to the URL of a webhook. authorization, when set, is the Authorization header.
+func Post(ctx context.Context, webhook *v1alpha1.ResultWebhookConfig, authorization string, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(Header, webhook.Name)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("POST %s: %s: %s", webhook.URL, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
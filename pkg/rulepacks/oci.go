@@ -0,0 +1,313 @@
+package rulepacks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// MediaType is the media type of the rule pack layers of an OCI artifact. Layers of other types are
+	// rule packs too when their title (the file name pushed with oras) ends in .yaml, .yml or .json.
+	MediaType = "application/vnd.nodecheck.rulepack.v1+yaml"
+
+	// pullTimeout bounds the pull of an artifact
+	pullTimeout = 2 * time.Minute
+	// maxManifestSize and maxLayerSize bound the downloads; rule packs end up in a ConfigMap (1 MiB)
+	maxManifestSize = 256 * 1024
+	maxLayerSize    = 512 * 1024
+)
+
+// manifestMediaTypes are the manifests accepted from the registry
+var manifestMediaTypes = strings.Join([]string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}, ", ")
+
+// Artifact is a pulled OCI artifact
+type Artifact struct {
+	// Digest is the digest of the manifest
+	Digest string
+	// Files are the rule packs of the artifact, keyed by file name
+	Files map[string]string
+}
+
+// Credentials authenticate to a registry. Empty credentials pull anonymously.
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// reference is a parsed artifact reference: registry/repository[:tag][@digest]
+type reference struct {
+	registry   string
+	repository string
+	reference  string
+}
+
+// parseReference parses an artifact reference. References without a registry host are on Docker Hub,
+// and references without tag or digest use "latest".
+func parseReference(ref string) (reference, error) {
+	parsed := reference{}
+	name := ref
+	if at := strings.Index(name, "@"); at >= 0 {
+		parsed.reference = name[at+1:]
+		name = name[:at]
+	} else if colon := strings.LastIndex(name, ":"); colon > strings.LastIndex(name, "/") {
+		parsed.reference = name[colon+1:]
+		name = name[:colon]
+	}
+	if parsed.reference == "" {
+		parsed.reference = "latest"
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 2 && (strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		parsed.registry, parsed.repository = parts[0], parts[1]
+	} else {
+		parsed.registry, parsed.repository = "registry-1.docker.io", name
+		if !strings.Contains(name, "/") {
+			parsed.repository = "library/" + name
+		}
+	}
+	if parsed.repository == "" {
+		return parsed, fmt.Errorf("invalid artifact reference %q", ref)
+	}
+	return parsed, nil
+}
+
+// registryClient calls the distribution API of a registry, authenticating on demand
+type registryClient struct {
+	http        *http.Client
+	registry    string
+	credentials Credentials
+	// authorization is the Authorization header obtained after the first challenge
+	authorization string
+}
+
+// Pull downloads the rule packs of an OCI artifact (e.g. quay.io/example/node-rules:v1)
+func Pull(ctx context.Context, ref string, credentials Credentials) (*Artifact, error) {
+	parsed, err := parseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, pullTimeout)
+	defer cancel()
+	rc := &registryClient{http: &http.Client{}, registry: parsed.registry, credentials: credentials}
+
+	manifestPath := fmt.Sprintf("/v2/%s/manifests/%s", parsed.repository, parsed.reference)
+	data, header, err := rc.get(ctx, manifestPath, manifestMediaTypes, maxManifestSize)
+	if err != nil {
+		return nil, fmt.Errorf("manifest of %s: %w", ref, err)
+	}
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" {
+		sum := sha256.Sum256(data)
+		digest = "sha256:" + hex.EncodeToString(sum[:])
+	}
+	var manifest struct {
+		Layers []struct {
+			MediaType   string            `json:"mediaType"`
+			Digest      string            `json:"digest"`
+			Size        int64             `json:"size"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("manifest of %s: %v", ref, err)
+	}
+
+	artifact := &Artifact{Digest: digest, Files: make(map[string]string)}
+	for i, layer := range manifest.Layers {
+		title := path.Base(layer.Annotations["org.opencontainers.image.title"])
+		if layer.MediaType != MediaType && !IsPackFile(title) {
+			continue
+		}
+		if !IsPackFile(title) {
+			title = fmt.Sprintf("layer-%d.yaml", i)
+		}
+		if layer.Size > maxLayerSize {
+			return nil, fmt.Errorf("layer %s of %s is larger than %d bytes", title, ref, maxLayerSize)
+		}
+		blob, _, err := rc.get(ctx, fmt.Sprintf("/v2/%s/blobs/%s", parsed.repository, layer.Digest), "", maxLayerSize)
+		if err != nil {
+			return nil, fmt.Errorf("layer %s of %s: %w", title, ref, err)
+		}
+		sum := sha256.Sum256(blob)
+		if layer.Digest != "sha256:"+hex.EncodeToString(sum[:]) {
+			return nil, fmt.Errorf("layer %s of %s does not match its digest %s", title, ref, layer.Digest)
+		}
+		artifact.Files[title] = string(blob)
+	}
+	if len(artifact.Files) == 0 {
+		return nil, fmt.Errorf("artifact %s has no rule pack layer (%s or a .yaml/.json file)", ref, MediaType)
+	}
+	return artifact, nil
+}
+
+// get sends a GET request, answering the authentication challenge of the registry if any
+func (rc *registryClient) get(ctx context.Context, apiPath, accept string, limit int64) ([]byte, http.Header, error) {
+	resp, err := rc.do(ctx, apiPath, accept)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && rc.authorization == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if rc.authorization, err = rc.authenticate(ctx, challenge); err != nil {
+			return nil, nil, err
+		}
+		if resp, err = rc.do(ctx, apiPath, accept); err != nil {
+			return nil, nil, err
+		}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, nil, fmt.Errorf("response larger than %d bytes", limit)
+	}
+	return data, resp.Header, nil
+}
+
+func (rc *registryClient) do(ctx context.Context, apiPath, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+rc.registry+apiPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if rc.authorization != "" {
+		req.Header.Set("Authorization", rc.authorization)
+	}
+	return rc.http.Do(req)
+}
+
+// authenticate answers a WWW-Authenticate challenge: Basic with the credentials, or Bearer with a token
+// of the token service of the registry
+func (rc *registryClient) authenticate(ctx context.Context, challenge string) (string, error) {
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte(rc.credentials.Username+":"+rc.credentials.Password))
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if rc.credentials.Username == "" {
+			return "", fmt.Errorf("registry %s requires credentials", rc.registry)
+		}
+		return basic, nil
+	case "bearer":
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Scheme != "https" {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if rc.credentials.Username != "" {
+		req.Header.Set("Authorization", basic)
+	}
+	resp, err := rc.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request to %s: %s", realm.Host, resp.Status)
+	}
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxManifestSize)).Decode(&token); err != nil {
+		return "", fmt.Errorf("token request to %s: %v", realm.Host, err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+	if token.Token == "" {
+		return "", fmt.Errorf("token request to %s returned no token", realm.Host)
+	}
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header (e.g. Bearer realm="...",service="...",scope="...")
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, ", "), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+	return strings.ToLower(scheme), params
+}
+
+// DockerConfigCredentials returns the credentials of a registry in a .dockerconfigjson document
+func DockerConfigCredentials(dockerConfig []byte, ref string) (Credentials, error) {
+	parsed, err := parseReference(ref)
+	if err != nil {
+		return Credentials{}, err
+	}
+	var config struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}
+	if err := json.Unmarshal(dockerConfig, &config); err != nil {
+		return Credentials{}, fmt.Errorf("invalid .dockerconfigjson: %v", err)
+	}
+	for registry, auth := range config.Auths {
+		host := strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://")
+		host, _, _ = strings.Cut(host, "/")
+		if host == "index.docker.io" || host == "docker.io" {
+			host = "registry-1.docker.io"
+		}
+		if host != parsed.registry {
+			continue
+		}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return Credentials{}, fmt.Errorf("invalid auth of %s in .dockerconfigjson", registry)
+			}
+			username, password, _ := strings.Cut(string(decoded), ":")
+			return Credentials{Username: username, Password: password}, nil
+		}
+		return Credentials{Username: auth.Username, Password: auth.Password}, nil
+	}
+	return Credentials{}, nil
+}
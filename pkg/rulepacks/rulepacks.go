@@ -0,0 +1,250 @@
+// Package rulepacks loads additional detection rules at runtime: log patterns, threshold presets and
+// known-issue signatures keyed by OS and kernel version. Rule packs are YAML or JSON documents stored in
+// labelled ConfigMaps of the operator namespace, either created by hand or pulled by the operator from
+// OCI artifacts, so new known issues can be detected without an operator upgrade.
+package rulepacks
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+const (
+	// Label marks the ConfigMaps holding rule packs ("true"). Every key of their data ending in .yaml,
+	// .yml or .json is a rule pack.
+	Label = "nodecheck.openshift.io/rule-pack"
+	// SourceAnnotation is the OCI artifact a rule pack ConfigMap was pulled from
+	SourceAnnotation = "nodecheck.openshift.io/rule-pack-source"
+	// DigestAnnotation is the manifest digest of the pulled OCI artifact
+	DigestAnnotation = "nodecheck.openshift.io/rule-pack-digest"
+)
+
+// Pack is a rule pack document
+type Pack struct {
+	// Name identifies the pack in the results (default: the ConfigMap and key it was read from)
+	Name string `json:"name,omitempty"`
+	// Version is informational
+	Version string `json:"version,omitempty"`
+	// LogPatterns are matched by the system_logs check against the errors of the system journal
+	LogPatterns []LogPattern `json:"logPatterns,omitempty"`
+	// ThresholdPresets are the default thresholds of the checks with usage thresholds, applied under
+	// spec.thresholds of the NodeChecks
+	ThresholdPresets map[string]v1alpha1.CheckThresholds `json:"thresholdPresets,omitempty"`
+	// KnownIssues are the signatures evaluated by the known_issues check
+	KnownIssues []KnownIssue `json:"knownIssues,omitempty"`
+}
+
+// LogPattern is a regular expression reporting a known problem when it matches a log line
+type LogPattern struct {
+	Name     string `json:"name"`
+	Pattern  string `json:"pattern"`
+	Severity string `json:"severity,omitempty"`
+	Message  string `json:"message,omitempty"`
+}
+
+// KnownIssue is the signature of a known issue. It matches a node when all its set conditions match:
+// the kernel version, the OS image (PRETTY_NAME of /etc/os-release) and a line of the system journal
+// of the last hour.
+type KnownIssue struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Severity      string `json:"severity,omitempty"`
+	URL           string `json:"url,omitempty"`
+	Remediation   string `json:"remediation,omitempty"`
+	KernelVersion string `json:"kernelVersion,omitempty"`
+	OSImage       string `json:"osImage,omitempty"`
+	LogPattern    string `json:"logPattern,omitempty"`
+}
+
+// Rules are the compiled rules of all the loaded packs
+type Rules struct {
+	// Packs are the names of the loaded packs
+	Packs []string
+	// Errors are the packs that could not be loaded, with the reason
+	Errors []string
+
+	LogPatterns      []CompiledLogPattern
+	ThresholdPresets map[string]v1alpha1.CheckThresholds
+	KnownIssues      []CompiledKnownIssue
+}
+
+// CompiledLogPattern is a LogPattern of a pack with its regular expression compiled
+type CompiledLogPattern struct {
+	LogPattern
+	Pack   string
+	Regexp *regexp.Regexp
+}
+
+// CompiledKnownIssue is a KnownIssue of a pack with its regular expressions compiled
+type CompiledKnownIssue struct {
+	KnownIssue
+	Pack      string
+	KernelRe  *regexp.Regexp
+	OSImageRe *regexp.Regexp
+	LogRe     *regexp.Regexp
+}
+
+// Matches reports whether the kernel version and OS image conditions of the signature match
+func (k CompiledKnownIssue) Matches(kernelVersion, osImage string) bool {
+	if k.KernelRe != nil && !k.KernelRe.MatchString(kernelVersion) {
+		return false
+	}
+	if k.OSImageRe != nil && !k.OSImageRe.MatchString(osImage) {
+		return false
+	}
+	return true
+}
+
+// Empty reports whether no rule is loaded
+func (r *Rules) Empty() bool {
+	return r == nil || (len(r.LogPatterns) == 0 && len(r.ThresholdPresets) == 0 && len(r.KnownIssues) == 0)
+}
+
+// IsPackFile reports whether a ConfigMap key or artifact file holds a rule pack
+func IsPackFile(name string) bool {
+	return strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") || strings.HasSuffix(name, ".json")
+}
+
+// Parse decodes a YAML or JSON rule pack
+func Parse(data []byte) (*Pack, error) {
+	var pack Pack
+	if err := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096).Decode(&pack); err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// severity validates the severity of a rule, Warning by default
+func severity(value string) (string, error) {
+	switch value {
+	case "":
+		return "Warning", nil
+	case "Warning", "Critical":
+		return value, nil
+	}
+	return "", fmt.Errorf("invalid severity %q (Warning or Critical)", value)
+}
+
+// compile compiles an optional regular expression
+func compile(field, pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", field, err)
+	}
+	return re, nil
+}
+
+// Add validates a pack and adds its rules. An invalid pack is rejected as a whole and recorded in Errors.
+func (r *Rules) Add(name string, pack *Pack) {
+	if pack.Name != "" {
+		name = pack.Name
+	}
+	if err := r.add(name, pack); err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("%s: %v", name, err))
+		return
+	}
+	r.Packs = append(r.Packs, name)
+}
+
+func (r *Rules) add(name string, pack *Pack) error {
+	var logPatterns []CompiledLogPattern
+	for _, pattern := range pack.LogPatterns {
+		if pattern.Name == "" || pattern.Pattern == "" {
+			return fmt.Errorf("log patterns need a name and a pattern")
+		}
+		sev, err := severity(pattern.Severity)
+		if err != nil {
+			return fmt.Errorf("log pattern %s: %v", pattern.Name, err)
+		}
+		re, err := compile("pattern", pattern.Pattern)
+		if err != nil {
+			return fmt.Errorf("log pattern %s: %v", pattern.Name, err)
+		}
+		pattern.Severity = sev
+		logPatterns = append(logPatterns, CompiledLogPattern{LogPattern: pattern, Pack: name, Regexp: re})
+	}
+
+	for check, threshold := range pack.ThresholdPresets {
+		if threshold.Warning < 0 || threshold.Warning > 100 || threshold.Critical < 0 || threshold.Critical > 100 {
+			return fmt.Errorf("thresholds of %s must be between 0 and 100", check)
+		}
+		if threshold.Warning > 0 && threshold.Critical > 0 && threshold.Warning >= threshold.Critical {
+			return fmt.Errorf("warning threshold of %s must be lower than the critical one", check)
+		}
+	}
+
+	var knownIssues []CompiledKnownIssue
+	for _, issue := range pack.KnownIssues {
+		if issue.ID == "" || issue.Title == "" {
+			return fmt.Errorf("known issues need an id and a title")
+		}
+		if issue.KernelVersion == "" && issue.OSImage == "" && issue.LogPattern == "" {
+			return fmt.Errorf("known issue %s: set at least one of kernelVersion, osImage and logPattern", issue.ID)
+		}
+		sev, err := severity(issue.Severity)
+		if err != nil {
+			return fmt.Errorf("known issue %s: %v", issue.ID, err)
+		}
+		issue.Severity = sev
+		compiled := CompiledKnownIssue{KnownIssue: issue, Pack: name}
+		if compiled.KernelRe, err = compile("kernelVersion", issue.KernelVersion); err != nil {
+			return fmt.Errorf("known issue %s: %v", issue.ID, err)
+		}
+		if compiled.OSImageRe, err = compile("osImage", issue.OSImage); err != nil {
+			return fmt.Errorf("known issue %s: %v", issue.ID, err)
+		}
+		if compiled.LogRe, err = compile("logPattern", issue.LogPattern); err != nil {
+			return fmt.Errorf("known issue %s: %v", issue.ID, err)
+		}
+		knownIssues = append(knownIssues, compiled)
+	}
+
+	r.LogPatterns = append(r.LogPatterns, logPatterns...)
+	r.KnownIssues = append(r.KnownIssues, knownIssues...)
+	if len(pack.ThresholdPresets) > 0 && r.ThresholdPresets == nil {
+		r.ThresholdPresets = make(map[string]v1alpha1.CheckThresholds)
+	}
+	for check, threshold := range pack.ThresholdPresets {
+		r.ThresholdPresets[check] = threshold
+	}
+	return nil
+}
+
+// FromConfigMapData loads the rule packs of the data of ConfigMaps, keyed by ConfigMap name. Packs are
+// added in the order of the ConfigMap names and keys, so the threshold presets of later packs win.
+func FromConfigMapData(configMaps map[string]map[string]string) *Rules {
+	rules := &Rules{}
+	names := make([]string, 0, len(configMaps))
+	for name := range configMaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		keys := make([]string, 0, len(configMaps[name]))
+		for key := range configMaps[name] {
+			if IsPackFile(key) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			pack, err := Parse([]byte(configMaps[name][key]))
+			if err != nil {
+				rules.Errors = append(rules.Errors, fmt.Sprintf("%s/%s: %v", name, key, err))
+				continue
+			}
+			rules.Add(name+"/"+key, pack)
+		}
+	}
+	return rules
+}
@@ -0,0 +1,257 @@
+package selfstatus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Component statuses
+const (
+	StatusOK            = "OK"
+	StatusStarting      = "Starting"
+	StatusDegraded      = "Degraded"
+	StatusFailed        = "Failed"
+	StatusNotConfigured = "NotConfigured"
+)
+
+// failingReconcileThreshold is how long a controller may keep failing (without any
+// successful reconcile) before the "reconcilers" health check reports it as unhealthy
+const failingReconcileThreshold = 15 * time.Minute
+
+// cacheSyncTimeout bounds how long a probe waits for the informer cache
+const cacheSyncTimeout = 1 * time.Second
+
+// ControllerStatus reports the reconcile history of a single controller
+type ControllerStatus struct {
+	Name              string    `json:"name"`
+	LastReconcile     time.Time `json:"lastReconcile,omitempty"`
+	LastSuccess       time.Time `json:"lastSuccess,omitempty"`
+	LastSuccessAge    string    `json:"lastSuccessAge,omitempty"`
+	LastError         string    `json:"lastError,omitempty"`
+	LastErrorTime     time.Time `json:"lastErrorTime,omitempty"`
+	Reconciles        int64     `json:"reconciles"`
+	Errors            int64     `json:"errors"`
+	ConsecutiveErrors int64     `json:"consecutiveErrors"`
+}
+
+// ComponentStatus reports the status of an auxiliary component (dashboard server, history store, ...)
+type ComponentStatus struct {
+	Name           string    `json:"name"`
+	Status         string    `json:"status"`
+	Message        string    `json:"message,omitempty"`
+	LastTransition time.Time `json:"lastTransition"`
+}
+
+// Report is the full self-status returned by /api/v1/selfstatus
+type Report struct {
+	Status      string             `json:"status"`
+	Mode        string             `json:"mode"`
+	StartTime   time.Time          `json:"startTime"`
+	Uptime      string             `json:"uptime"`
+	CacheSynced bool               `json:"cacheSynced"`
+	Controllers []ControllerStatus `json:"controllers"`
+	Components  []ComponentStatus  `json:"components"`
+}
+
+// tracker holds the process-wide self-status
+type tracker struct {
+	mu          sync.RWMutex
+	mode        string
+	startTime   time.Time
+	cacheSynced func(ctx context.Context) bool
+	controllers map[string]*ControllerStatus
+	components  map[string]*ComponentStatus
+}
+
+var global = &tracker{
+	startTime:   time.Now(),
+	controllers: make(map[string]*ControllerStatus),
+	components:  make(map[string]*ComponentStatus),
+}
+
+// SetMode records the mode the process is running in (operator or executor)
+func SetMode(mode string) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.mode = mode
+}
+
+// SetCacheSyncFunc registers the function used to check informer cache sync
+// (typically mgr.GetCache().WaitForCacheSync)
+func SetCacheSyncFunc(fn func(ctx context.Context) bool) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	global.cacheSynced = fn
+}
+
+// SetComponent records the status of an auxiliary component
+func SetComponent(name, status, message string) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	component, ok := global.components[name]
+	if !ok {
+		component = &ComponentStatus{Name: name}
+		global.components[name] = component
+	}
+	if component.Status != status {
+		component.LastTransition = time.Now()
+	}
+	component.Status = status
+	component.Message = message
+}
+
+// recordReconcile records the outcome of a single reconcile
+func recordReconcile(name string, err error) {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+
+	controller, ok := global.controllers[name]
+	if !ok {
+		controller = &ControllerStatus{Name: name}
+		global.controllers[name] = controller
+	}
+	now := time.Now()
+	controller.LastReconcile = now
+	controller.Reconciles++
+	if err != nil {
+		controller.Errors++
+		controller.ConsecutiveErrors++
+		controller.LastError = err.Error()
+		controller.LastErrorTime = now
+		return
+	}
+	controller.ConsecutiveErrors = 0
+	controller.LastSuccess = now
+}
+
+// trackedReconciler wraps a reconciler to record the outcome of every reconcile
+type trackedReconciler struct {
+	name       string
+	reconciler reconcile.Reconciler
+}
+
+// Track wraps a reconciler so its reconciles are reported in the self-status
+func Track(name string, r reconcile.Reconciler) reconcile.Reconciler {
+	global.mu.Lock()
+	if _, ok := global.controllers[name]; !ok {
+		global.controllers[name] = &ControllerStatus{Name: name}
+	}
+	global.mu.Unlock()
+
+	return &trackedReconciler{name: name, reconciler: r}
+}
+
+// Reconcile delegates to the wrapped reconciler and records the result
+func (t *trackedReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	result, err := t.reconciler.Reconcile(ctx, req)
+	recordReconcile(t.name, err)
+	return result, err
+}
+
+// isCacheSynced reports whether the informer cache has synced
+func isCacheSynced(ctx context.Context) bool {
+	global.mu.RLock()
+	fn := global.cacheSynced
+	global.mu.RUnlock()
+	if fn == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cacheSyncTimeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// failingControllers returns the controllers that have only been failing for longer than the threshold
+func failingControllers(now time.Time) []string {
+	global.mu.RLock()
+	defer global.mu.RUnlock()
+
+	failing := []string{}
+	for name, controller := range global.controllers {
+		if controller.ConsecutiveErrors == 0 {
+			continue
+		}
+		since := controller.LastSuccess
+		if since.IsZero() {
+			since = global.startTime
+		}
+		if now.Sub(since) > failingReconcileThreshold {
+			failing = append(failing, name)
+		}
+	}
+	sort.Strings(failing)
+	return failing
+}
+
+// CacheSyncCheck is a readyz check that fails until the informer cache has synced
+func CacheSyncCheck(req *http.Request) error {
+	if !isCacheSynced(req.Context()) {
+		return fmt.Errorf("informer cache not synced")
+	}
+	return nil
+}
+
+// ReconcilersCheck is a healthz check that fails when a controller has been failing
+// without a single successful reconcile for longer than failingReconcileThreshold
+func ReconcilersCheck(req *http.Request) error {
+	if failing := failingControllers(time.Now()); len(failing) > 0 {
+		return fmt.Errorf("controllers failing for more than %s: %s", failingReconcileThreshold, strings.Join(failing, ", "))
+	}
+	return nil
+}
+
+// GetReport builds the current self-status report
+func GetReport(ctx context.Context) Report {
+	now := time.Now()
+	cacheSynced := isCacheSynced(ctx)
+	failing := failingControllers(now)
+
+	global.mu.RLock()
+	defer global.mu.RUnlock()
+
+	report := Report{
+		Status:      StatusOK,
+		Mode:        global.mode,
+		StartTime:   global.startTime,
+		Uptime:      now.Sub(global.startTime).Round(time.Second).String(),
+		CacheSynced: cacheSynced,
+		Controllers: []ControllerStatus{},
+		Components:  []ComponentStatus{},
+	}
+
+	for _, controller := range global.controllers {
+		status := *controller
+		if !status.LastSuccess.IsZero() {
+			status.LastSuccessAge = now.Sub(status.LastSuccess).Round(time.Second).String()
+		}
+		report.Controllers = append(report.Controllers, status)
+	}
+	sort.Slice(report.Controllers, func(i, j int) bool {
+		return report.Controllers[i].Name < report.Controllers[j].Name
+	})
+
+	for _, component := range global.components {
+		report.Components = append(report.Components, *component)
+		if component.Status == StatusFailed || component.Status == StatusDegraded {
+			report.Status = StatusDegraded
+		}
+	}
+	sort.Slice(report.Components, func(i, j int) bool {
+		return report.Components[i].Name < report.Components[j].Name
+	})
+
+	if !cacheSynced || len(failing) > 0 {
+		report.Status = StatusDegraded
+	}
+	return report
+}
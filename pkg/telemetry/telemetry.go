@@ -0,0 +1,176 @@
+// Package telemetry builds the anonymized fleet statistics the operator reports when telemetry is
+// enabled: the pass rates of each check type, the operator version and the size of the cluster as a
+// bucket. The report holds no node, NodeCheck, namespace or cluster names, no messages and no details,
+// only counts; the maintainers use it to find the checks producing the most false positives.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+// requestTimeout bounds every report
+const requestTimeout = 30 * time.Second
+
+var httpClient = &http.Client{Timeout: requestTimeout}
+
+// CheckStats are the results of a check type across the cluster
+type CheckStats struct {
+	// Runs is the number of results counted, from status.history or the latest results
+	Runs     int `json:"runs"`
+	Healthy  int `json:"healthy"`
+	Warning  int `json:"warning"`
+	Critical int `json:"critical"`
+	Unknown  int `json:"unknown"`
+	// Transitions is the number of status changes: a check flapping between Healthy and Warning is
+	// the usual sign of a threshold too tight for the fleet
+	Transitions int `json:"transitions"`
+	// PassRate is the share of the runs that were Healthy, in percent
+	PassRate float64 `json:"passRate"`
+	// FalsePositives is the number of results marked as false positives from the dashboard
+	FalsePositives int `json:"falsePositives"`
+}
+
+// Report is the document sent to the telemetry endpoint
+type Report struct {
+	// InstallationID tells the reports of the same installation apart without identifying it: a hash
+	// of the UID of the NodeCheckOperatorConfig
+	InstallationID  string                `json:"installationID"`
+	OperatorVersion string                `json:"operatorVersion"`
+	ClusterSize     string                `json:"clusterSize"`
+	NodeChecks      int                   `json:"nodeChecks"`
+	Checks          map[string]CheckStats `json:"checks"`
+	GeneratedAt     time.Time             `json:"generatedAt"`
+}
+
+// InstallationID returns the anonymous ID of an installation from the UID of its NodeCheckOperatorConfig
+func InstallationID(uid string) string {
+	hash := sha256.Sum256([]byte("node-check-operator/" + uid))
+	return hex.EncodeToString(hash[:16])
+}
+
+// OperatorVersion returns the version of the operator from the tag of its image
+// (quay.io/example/node-check-operator:v1.0.8 is v1.0.8), or "unknown" for images pinned by digest
+func OperatorVersion(image string) string {
+	if strings.Contains(image, "@") {
+		return "unknown"
+	}
+	if i := strings.LastIndex(image, ":"); i >= 0 && !strings.Contains(image[i:], "/") {
+		return image[i+1:]
+	}
+	return "latest"
+}
+
+// ClusterSizeBucket returns the size bucket of a cluster of nodes, so the report does not disclose its
+// exact size
+func ClusterSizeBucket(nodes int) string {
+	switch {
+	case nodes <= 3:
+		return "1-3"
+	case nodes <= 10:
+		return "4-10"
+	case nodes <= 50:
+		return "11-50"
+	case nodes <= 200:
+		return "51-200"
+	case nodes <= 1000:
+		return "201-1000"
+	}
+	return "1000+"
+}
+
+// NewReport returns an empty report of an installation
+func NewReport(installationID, operatorVersion string, nodes int) *Report {
+	return &Report{
+		InstallationID:  installationID,
+		OperatorVersion: operatorVersion,
+		ClusterSize:     ClusterSizeBucket(nodes),
+		Checks:          make(map[string]CheckStats),
+		GeneratedAt:     time.Now().UTC().Truncate(time.Hour),
+	}
+}
+
+// Add counts the results of the NodeCheck of a node by check type. Checks with a history count every
+// entry of it; the others count their latest result.
+func (r *Report) Add(history []v1alpha1.CheckHistory, results map[string]v1alpha1.CheckResult) {
+	r.NodeChecks++
+	counted := make(map[string]bool)
+	for _, check := range history {
+		if len(check.Entries) == 0 {
+			continue
+		}
+		counted[check.Name] = true
+		stats := r.Checks[check.Name]
+		for _, entry := range check.Entries {
+			stats.count(entry.Status)
+		}
+		stats.Transitions += check.Transitions
+		r.Checks[check.Name] = stats
+	}
+	for name, result := range results {
+		if counted[name] {
+			continue
+		}
+		stats := r.Checks[name]
+		stats.count(result.Status)
+		r.Checks[name] = stats
+	}
+}
+
+// AddFalsePositives counts the results of a check marked as false positives
+func (r *Report) AddFalsePositives(check string, count int) {
+	stats := r.Checks[check]
+	stats.FalsePositives += count
+	r.Checks[check] = stats
+}
+
+// count adds a result of a status
+func (s *CheckStats) count(status string) {
+	s.Runs++
+	switch status {
+	case "Healthy":
+		s.Healthy++
+	case "Warning":
+		s.Warning++
+	case "Critical":
+		s.Critical++
+	default:
+		s.Unknown++
+	}
+	s.PassRate = math.Round(float64(s.Healthy)*1000/float64(s.Runs)) / 10
+}
+
+// Send posts a report to the telemetry endpoint
+func Send(ctx context.Context, endpoint string, report *Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("POST %s: %s: %s", endpoint, resp.Status, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
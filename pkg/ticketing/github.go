@@ -0,0 +1,66 @@
+package ticketing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// gitHub opens the issues in a GitHub repository. The open issues of the operator carry its label and
+// their key in the body.
+type gitHub struct {
+	client     *client
+	repository string
+	labels     []string
+}
+
+type gitHubIssue struct {
+	Number      int       `json:"number"`
+	HTMLURL     string    `json:"html_url"`
+	Body        string    `json:"body"`
+	PullRequest *struct{} `json:"pull_request,omitempty"`
+}
+
+func (g *gitHub) OpenIssues(ctx context.Context) (map[string]Issue, error) {
+	issues := make(map[string]Issue)
+	for page := 1; ; page++ {
+		query := url.Values{"state": {"open"}, "labels": {Label}, "per_page": {"100"}, "page": {strconv.Itoa(page)}}
+		var found []gitHubIssue
+		if err := g.client.do(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/issues?%s", g.repository, query.Encode()), nil, &found); err != nil {
+			return nil, err
+		}
+		for _, issue := range found {
+			if key := keyOf(issue.Body); key != "" && issue.PullRequest == nil {
+				issues[key] = Issue{Key: key, ID: strconv.Itoa(issue.Number), URL: issue.HTMLURL}
+			}
+		}
+		if len(found) < 100 {
+			return issues, nil
+		}
+	}
+}
+
+func (g *gitHub) Create(ctx context.Context, issue Issue) (Issue, error) {
+	request := map[string]interface{}{
+		"title":  issue.Title,
+		"body":   issue.Body + "\n\n<!-- " + keyMarker(issue.Key) + " -->\n",
+		"labels": g.labels,
+	}
+	var created gitHubIssue
+	if err := g.client.do(ctx, http.MethodPost, fmt.Sprintf("/repos/%s/issues", g.repository), request, &created); err != nil {
+		return issue, err
+	}
+	issue.ID = strconv.Itoa(created.Number)
+	issue.URL = created.HTMLURL
+	return issue, nil
+}
+
+func (g *gitHub) Close(ctx context.Context, issue Issue, comment string) error {
+	path := fmt.Sprintf("/repos/%s/issues/%s", g.repository, issue.ID)
+	if err := g.client.do(ctx, http.MethodPost, path+"/comments", map[string]string{"body": comment}, nil); err != nil {
+		return err
+	}
+	return g.client.do(ctx, http.MethodPatch, path, map[string]string{"state": "closed", "state_reason": "completed"}, nil)
+}
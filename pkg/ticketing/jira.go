@@ -0,0 +1,111 @@
+package ticketing
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// jira opens the issues in a Jira project. The open issues of the operator carry its label and their key
+// as a second label.
+type jira struct {
+	client    *client
+	project   string
+	issueType string
+	labels    []string
+}
+
+// basicAuth encodes the credentials of a Basic Authorization header
+func basicAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+func (j *jira) browseURL(id string) string {
+	return j.client.baseURL + "/browse/" + id
+}
+
+func (j *jira) OpenIssues(ctx context.Context) (map[string]Issue, error) {
+	jql := fmt.Sprintf(`project = "%s" AND labels = "%s" AND statusCategory != Done`, j.project, Label)
+	issues := make(map[string]Issue)
+	for startAt := 0; ; {
+		query := url.Values{"jql": {jql}, "fields": {"labels"}, "maxResults": {"100"}, "startAt": {strconv.Itoa(startAt)}}
+		var found struct {
+			Total  int `json:"total"`
+			Issues []struct {
+				Key    string `json:"key"`
+				Fields struct {
+					Labels []string `json:"labels"`
+				} `json:"fields"`
+			} `json:"issues"`
+		}
+		if err := j.client.do(ctx, http.MethodGet, "/rest/api/2/search?"+query.Encode(), nil, &found); err != nil {
+			return nil, err
+		}
+		for _, issue := range found.Issues {
+			for _, label := range issue.Fields.Labels {
+				if strings.HasPrefix(label, "nodecheck_") {
+					issues[label] = Issue{Key: label, ID: issue.Key, URL: j.browseURL(issue.Key)}
+				}
+			}
+		}
+		startAt += len(found.Issues)
+		if len(found.Issues) == 0 || startAt >= found.Total {
+			return issues, nil
+		}
+	}
+}
+
+func (j *jira) Create(ctx context.Context, issue Issue) (Issue, error) {
+	request := map[string]interface{}{
+		"fields": map[string]interface{}{
+			"project":     map[string]string{"key": j.project},
+			"issuetype":   map[string]string{"name": j.issueType},
+			"summary":     issue.Title,
+			"description": issue.Body + "\n\n" + keyMarker(issue.Key),
+			"labels":      append(append([]string{}, j.labels...), issue.Key),
+		},
+	}
+	var created struct {
+		Key string `json:"key"`
+	}
+	if err := j.client.do(ctx, http.MethodPost, "/rest/api/2/issue", request, &created); err != nil {
+		return issue, err
+	}
+	issue.ID = created.Key
+	issue.URL = j.browseURL(created.Key)
+	return issue, nil
+}
+
+func (j *jira) Close(ctx context.Context, issue Issue, comment string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(issue.ID)
+	if err := j.client.do(ctx, http.MethodPost, path+"/comment", map[string]string{"body": comment}, nil); err != nil {
+		return err
+	}
+
+	// Workflows differ between projects: use the first transition to a Done status
+	var transitions struct {
+		Transitions []struct {
+			ID string `json:"id"`
+			To struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := j.client.do(ctx, http.MethodGet, path+"/transitions", nil, &transitions); err != nil {
+		return err
+	}
+	for _, transition := range transitions.Transitions {
+		if transition.To.StatusCategory.Key == "done" {
+			return j.client.do(ctx, http.MethodPost, path+"/transitions", map[string]interface{}{
+				"transition": map[string]string{"id": transition.ID},
+			}, nil)
+		}
+	}
+	return fmt.Errorf("issue %s has no transition to a Done status", issue.ID)
+}
@@ -0,0 +1,163 @@
+// Package ticketing reports persistent Critical findings to an issue tracker (GitHub or Jira): one issue
+// per node and check, found again through its key so it is never opened twice.
+package ticketing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Label marks the issues opened by the operator
+const Label = "node-check-operator"
+
+// requestTimeout bounds every call to the tracker
+const requestTimeout = 30 * time.Second
+
+// Issue is an issue of the tracker for a node and check
+type Issue struct {
+	// Key identifies the node and check of the issue (see Key)
+	Key string
+	// ID is the identifier of the issue in the tracker: the GitHub issue number or the Jira issue key
+	ID string
+	// URL is the web page of the issue
+	URL string
+	// Title and Body are set on the issues to open
+	Title string
+	Body  string
+}
+
+// Tracker opens, finds and closes the issues of the operator
+type Tracker interface {
+	// OpenIssues returns the open issues of the operator, keyed by Issue.Key
+	OpenIssues(ctx context.Context) (map[string]Issue, error)
+	// Create opens an issue and returns it with its ID and URL set
+	Create(ctx context.Context, issue Issue) (Issue, error)
+	// Close adds a comment to an issue and closes it
+	Close(ctx context.Context, issue Issue, comment string) error
+}
+
+// Config is the tracker configuration with the credentials read from the Secret
+type Config struct {
+	Provider   string
+	URL        string
+	Repository string
+	Project    string
+	IssueType  string
+	Username   string
+	Token      string
+	Labels     []string
+}
+
+// New returns the tracker of the configured provider
+func New(config Config) (Tracker, error) {
+	if config.Token == "" {
+		return nil, fmt.Errorf("no token in the credentials")
+	}
+	labels := append([]string{Label}, config.Labels...)
+	switch config.Provider {
+	case "GitHub":
+		if config.Repository == "" {
+			return nil, fmt.Errorf("repository is required for GitHub")
+		}
+		url := config.URL
+		if url == "" {
+			url = "https://api.github.com"
+		}
+		return &gitHub{client: newClient(url, "Bearer "+config.Token), repository: config.Repository, labels: labels}, nil
+	case "Jira":
+		if config.URL == "" || config.Project == "" {
+			return nil, fmt.Errorf("url and project are required for Jira")
+		}
+		issueType := config.IssueType
+		if issueType == "" {
+			issueType = "Bug"
+		}
+		authorization := "Bearer " + config.Token
+		if config.Username != "" {
+			authorization = "Basic " + basicAuth(config.Username, config.Token)
+		}
+		return &jira{client: newClient(config.URL, authorization), project: config.Project, issueType: issueType, labels: labels}, nil
+	}
+	return nil, fmt.Errorf("unknown provider %q", config.Provider)
+}
+
+// keyLabelInvalid matches the characters not allowed in a key label
+var keyLabelInvalid = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// Key returns the key of the issue of a check on a node
+func Key(node, check string) string {
+	return keyLabelInvalid.ReplaceAllString("nodecheck_"+node+"_"+check, "-")
+}
+
+// keyMarker is the line of the issue body holding its key, so the issue can be found again
+func keyMarker(key string) string {
+	return fmt.Sprintf("nodecheck-key: %s", key)
+}
+
+// markerPattern extracts the key from an issue body
+var markerPattern = regexp.MustCompile(`nodecheck-key: ([A-Za-z0-9_.-]+)`)
+
+// keyOf returns the key in an issue body, or "" if it has none
+func keyOf(body string) string {
+	if match := markerPattern.FindStringSubmatch(body); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// client calls the REST API of a tracker
+type client struct {
+	baseURL       string
+	authorization string
+	http          *http.Client
+}
+
+func newClient(baseURL, authorization string) *client {
+	return &client{
+		baseURL:       strings.TrimSuffix(baseURL, "/"),
+		authorization: authorization,
+		http:          &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// do sends a request with a JSON body (if any) and decodes the JSON response into out (if not nil)
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", c.authorization)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, strings.TrimSpace(string(message)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
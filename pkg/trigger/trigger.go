@@ -0,0 +1,77 @@
+// Package trigger runs the checks of a NodeCheck on demand, for the sites whose scheduling is
+// centralized (CronJobs, KEDA): a new value of the trigger annotation, set by the dashboard trigger
+// webhook or kubectl, runs every enabled check category of the NodeCheck once. The categories that
+// ran for a value are recorded in status.trigger, so a value is never run twice and the annotation
+// can be left in place. With spec.scheduling External, triggers are the only runs.
+package trigger
+
+import (
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+)
+
+const (
+	// Annotation triggers a run of the NodeCheck whenever its value changes. On a "*" NodeCheck it is
+	// copied to the child NodeCheck of every node.
+	Annotation = "nodecheck.openshift.io/trigger"
+
+	// Scheduling modes of spec.scheduling
+	SchedulingInterval = "Interval"
+	SchedulingExternal = "External"
+)
+
+// External reports whether the checks of a NodeCheck only run when triggered
+func External(spec *v1alpha1.NodeCheckSpec) bool {
+	return spec.Scheduling == SchedulingExternal
+}
+
+// NewValue returns the annotation value of a trigger requested at a time
+func NewValue(now time.Time) string {
+	return now.UTC().Format(time.RFC3339Nano)
+}
+
+// Pending returns the value of the trigger annotation when a check category has not run for it yet
+func Pending(nodeCheck *v1alpha1.NodeCheck, category string) (string, bool) {
+	value := strings.TrimSpace(nodeCheck.Annotations[Annotation])
+	if value == "" {
+		return "", false
+	}
+	status := nodeCheck.Status.Trigger
+	if status == nil || status.Value != value {
+		return value, true
+	}
+	for _, ran := range status.Categories {
+		if ran == category {
+			return "", false
+		}
+	}
+	return value, true
+}
+
+// Record marks the check categories as run for a trigger value. A new value replaces the categories
+// recorded for the previous one.
+func Record(status *v1alpha1.NodeCheckStatus, value string, categories []string, now metav1.Time) {
+	if value == "" || len(categories) == 0 {
+		return
+	}
+	if status.Trigger == nil || status.Trigger.Value != value {
+		status.Trigger = &v1alpha1.TriggerStatus{Value: value}
+	}
+	for _, category := range categories {
+		found := false
+		for _, ran := range status.Trigger.Categories {
+			if ran == category {
+				found = true
+				break
+			}
+		}
+		if !found {
+			status.Trigger.Categories = append(status.Trigger.Categories, category)
+		}
+	}
+	status.Trigger.LastRunTime = now
+}
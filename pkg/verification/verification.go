@@ -0,0 +1,129 @@
+// Package verification runs the post-maintenance verification of a node: a one-shot NodeCheck with all
+// the checks enabled and tighter thresholds, whose results make a pass/fail gate for the return of the
+// node to service. A verification is requested with an annotation of the NodeCheck of the node, by the
+// dashboard API or kubectl; the operator creates the one-shot NodeCheck, evaluates it once its first run
+// completes, records the outcome in status.verification and deletes it.
+package verification
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/albertofilice/node-check-operator/api/v1alpha1"
+	"github.com/albertofilice/node-check-operator/pkg/checks"
+	"github.com/albertofilice/node-check-operator/pkg/nodehealth"
+)
+
+const (
+	// Annotation requests a verification of a NodeCheck, as a JSON Request ("{}" for the defaults). The
+	// operator removes it once the verification has started.
+	Annotation = "nodecheck.openshift.io/verify"
+	// Label marks the one-shot NodeCheck of a verification, with the name of the verified NodeCheck
+	Label = "nodecheck.openshift.io/verification-of"
+
+	// Timeout bounds the first run of the one-shot NodeCheck; a verification not complete by then fails
+	Timeout = 20 * time.Minute
+
+	// Phases of a verification
+	PhaseRunning = "Running"
+	PhasePassed  = "Passed"
+	PhaseFailed  = "Failed"
+)
+
+// Request is a verification request
+type Request struct {
+	// RequestedBy is the user who requested the verification
+	RequestedBy string `json:"requestedBy,omitempty"`
+	// AutoUncordon uncordons the node when the verification passes
+	AutoUncordon bool `json:"autoUncordon,omitempty"`
+}
+
+// Requested reports whether the annotations of a NodeCheck request a verification
+func Requested(annotations map[string]string) bool {
+	_, ok := annotations[Annotation]
+	return ok
+}
+
+// ParseRequest reads the verification request of the annotations of a NodeCheck. An empty value
+// requests a verification with the defaults.
+func ParseRequest(annotations map[string]string) (Request, error) {
+	var request Request
+	value := strings.TrimSpace(annotations[Annotation])
+	if value == "" {
+		return request, nil
+	}
+	if err := json.Unmarshal([]byte(value), &request); err != nil {
+		return request, fmt.Errorf("invalid %s annotation: %w", Annotation, err)
+	}
+	return request, nil
+}
+
+// Encode returns the annotation value of a request
+func (r Request) Encode() (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Name returns the name of the one-shot NodeCheck verifying a NodeCheck
+func Name(nodeCheckName string) string {
+	return nodeCheckName + "-verify"
+}
+
+// Spec returns the spec of the one-shot NodeCheck verifying a NodeCheck: every check enabled, the
+// thresholds of checks.TightenThresholds, and none of the settings that keep state across runs or act on
+// the results (history, baseline, events, result labels). Maintenance windows are dropped as well, they
+// would suppress the results the gate is made of, and so is an external scheduling, which would never
+// run it.
+func Spec(spec *v1alpha1.NodeCheckSpec) v1alpha1.NodeCheckSpec {
+	verified := *spec.DeepCopy()
+	enableAll(reflect.ValueOf(&verified.SystemChecks).Elem())
+	enableAll(reflect.ValueOf(&verified.KubernetesChecks).Elem())
+	verified.Paused = false
+	verified.Scheduling = ""
+	verified.NodeSelector = nil
+	verified.CategoryIntervals = nil
+	verified.Thresholds = checks.TightenThresholds(spec.Thresholds)
+	verified.Suppressions = nil
+	verified.HistorySize = 0
+	verified.Baseline = nil
+	verified.EmitEvents = nil
+	verified.CloudEvents = nil
+	verified.ResultLabels = nil
+	verified.ResultAnnotations = nil
+	return verified
+}
+
+// enableAll sets every check toggle of a check group, including the nested ones (disks, network, hardware)
+func enableAll(group reflect.Value) {
+	for i := 0; i < group.NumField(); i++ {
+		field := group.Field(i)
+		switch field.Kind() {
+		case reflect.Bool:
+			field.SetBool(true)
+		case reflect.Struct:
+			enableAll(field)
+		}
+	}
+}
+
+// Evaluate returns the number of checks with a result and the checks failing the gate, in Warning or
+// Critical status. Unknown and NotSupported results do not fail it: with every check enabled, the
+// checks the node has no hardware or tooling for report them.
+func Evaluate(results v1alpha1.CheckResults) (int, []string) {
+	statuses := nodehealth.CollectCheckStatuses(results)
+	failed := []string{}
+	for path, status := range statuses {
+		if status == "Warning" || status == "Critical" {
+			failed = append(failed, path)
+		}
+	}
+	sort.Strings(failed)
+	return len(statuses), failed
+}
@@ -229,7 +229,7 @@ check_prerequisites() {
 install_crd() {
     log_info "Installing Custom Resource Definition..."
     
-    if $KUBECTL_CMD apply -f config/crd/bases/nodecheck.openshift.io_nodechecks.yaml; then
+    if $KUBECTL_CMD apply -f config/crd/bases/nodecheck.openshift.io_nodechecks.yaml -f config/crd/bases/nodecheck.openshift.io_nodechecktemplates.yaml -f config/crd/bases/nodecheck.openshift.io_nodecheckoperatorconfigs.yaml; then
         log_info "CRD installed successfully"
     else
         log_error "Error installing CRD"
@@ -540,6 +540,8 @@ force_cleanup() {
     log_info "Deleting RBAC and CRD definitions"
     $KUBECTL_CMD delete -f config/rbac/role_binding.yaml --ignore-not-found=true 2>/dev/null || true
     $KUBECTL_CMD delete -f config/rbac/role.yaml --ignore-not-found=true 2>/dev/null || true
+    $KUBECTL_CMD delete -f config/crd/bases/nodecheck.openshift.io_nodecheckoperatorconfigs.yaml --ignore-not-found=true 2>/dev/null || true
+    $KUBECTL_CMD delete -f config/crd/bases/nodecheck.openshift.io_nodechecktemplates.yaml --ignore-not-found=true 2>/dev/null || true
     $KUBECTL_CMD delete -f config/crd/bases/nodecheck.openshift.io_nodechecks.yaml --ignore-not-found=true 2>/dev/null || true
     
     # Wait a bit for resources to be fully deleted
@@ -572,7 +574,7 @@ verify_installation() {
     fi
     
     # Verify that the CRD is installed
-    if $KUBECTL_CMD get crd nodechecks.nodecheck.openshift.io &> /dev/null; then
+    if $KUBECTL_CMD get crd nodechecks.nodecheck.openshift.io nodechecktemplates.nodecheck.openshift.io nodecheckoperatorconfigs.nodecheck.openshift.io &> /dev/null; then
         log_info "CRD is available"
     else
         log_error "CRD is not available"
@@ -615,10 +617,17 @@ spec:
     pods: true
   nodeName: '*'
   systemChecks:
+    audit: true
+    cgroupDriver: true
+    conntrack: true
     contextSwitches: true
+    coreDumps: true
     cpuFrequency: true
     cpuStealTime: true
+    cpuVulnerabilities: true
+    entropy: true
     fileDescriptors: true
+    gpu: true
     hardware:
       bmc: true
       cpuMicrocode: true
@@ -628,9 +637,16 @@ spec:
       pcieErrors: true
       powerSupply: true
       temperature: true
+    hugePages: true
+    inotify: true
     interruptsBalance: true
+    kdump: true
+    kernelCmdline: true
+    kernelLockups: true
     kernelModules: true
     kernelPanics: true
+    kernelTaint: true
+    knownIssues: true
     memory: true
     memoryFragmentation: true
     network:
@@ -640,20 +656,34 @@ spec:
       errors: true
       interfaces: true
       latency: true
+      resolvConf: true
       routing: true
       statistics: true
       firewallRules: true
+    certificateExpiry: true
     ntpSync: true
+    numa: true
     oomKiller: true
+    orphanedMounts: true
+    osUpdates: true
+    pressureStall: true
+    processLimits: true
     processes: true
     resources: true
     selinuxStatus: true
+    serviceRestarts: true
     services: true
+    sriov: true
     sshAccess: true
     swapActivity: true
+    swapPolicy: true
+    sysctlDrift: true
     systemLogs: true
+    timeDrift: true
+    transparentHugePages: true
     uninterruptibleTasks: true
     uptime: true
+    userspaceOOM: true
     zombieProcesses: true
     disks:
       filesystemErrors: true
@@ -665,8 +695,11 @@ spec:
       pvs: true
       queueDepth: true
       raid: true
+      runtimeStorage: true
       smart: true
       space: true
+      wearLevel: true
+      writeProbe: true
 EOF
     
     log_info "Example NodeCheck created"
@@ -709,6 +742,8 @@ show_info() {
     echo "  $KUBECTL_CMD delete daemonset node-check-executor -n ${NAMESPACE} --ignore-not-found=true"
     echo "  $KUBECTL_CMD delete -f config/rbac/role_binding.yaml"
     echo "  $KUBECTL_CMD delete -f config/rbac/role.yaml"
+    echo "  $KUBECTL_CMD delete -f config/crd/bases/nodecheck.openshift.io_nodecheckoperatorconfigs.yaml"
+    echo "  $KUBECTL_CMD delete -f config/crd/bases/nodecheck.openshift.io_nodechecktemplates.yaml"
     echo "  $KUBECTL_CMD delete -f config/crd/bases/nodecheck.openshift.io_nodechecks.yaml"
     fi
     echo